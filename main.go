@@ -32,6 +32,7 @@ import (
 	admversion "github.com/submariner-io/admiral/pkg/version"
 	"github.com/submariner-io/admiral/pkg/watcher"
 	subv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/bfd"
 	"github.com/submariner-io/submariner/pkg/cableengine"
 	submarinerClientset "github.com/submariner-io/submariner/pkg/client/clientset/versioned"
 	"github.com/submariner-io/submariner/pkg/gateway"
@@ -63,9 +64,13 @@ func init() {
 }
 
 type leaderConfig struct {
-	LeaseDuration int64
-	RenewDeadline int64
-	RetryPeriod   int64
+	LeaseDuration       int64
+	RenewDeadline       int64
+	RetryPeriod         int64
+	BFDEnabled          bool  `split_words:"true"`
+	BFDPort             int   `split_words:"true"`
+	BFDInterval         int64 `split_words:"true"` // In milliseconds
+	BFDDetectMultiplier uint8 `split_words:"true"`
 }
 
 const leadershipConfigEnvPrefix = "leadership"
@@ -123,11 +128,20 @@ func main() {
 
 	logger.FatalOnError(subv1.AddToScheme(scheme.Scheme), "Error adding submariner types to the scheme")
 
+	additionalBrokers, err := gateway.AdditionalBrokerConfigs(submSpec.AdditionalBrokers)
+	logger.FatalOnError(err, "Error processing the additional brokers")
+
 	gw, err := gateway.New(&gateway.Config{
 		LeaderElectionConfig: gateway.LeaderElectionConfig{
 			LeaseDuration: time.Duration(gwLeadershipConfig.LeaseDuration) * time.Second,
 			RenewDeadline: time.Duration(gwLeadershipConfig.RenewDeadline) * time.Second,
 			RetryPeriod:   time.Duration(gwLeadershipConfig.RetryPeriod) * time.Second,
+			BFD: bfd.Config{
+				Enabled:          gwLeadershipConfig.BFDEnabled,
+				Port:             gwLeadershipConfig.BFDPort,
+				TxInterval:       time.Duration(gwLeadershipConfig.BFDInterval) * time.Millisecond,
+				DetectMultiplier: gwLeadershipConfig.BFDDetectMultiplier,
+			},
 		},
 		Spec: submSpec,
 		SyncerConfig: broker.SyncerConfig{
@@ -135,6 +149,7 @@ func main() {
 			LocalClient:     dynClient,
 			RestMapper:      restMapper,
 		},
+		AdditionalBrokers: additionalBrokers,
 		WatcherConfig: watcher.Config{
 			RestConfig: restConfig,
 		},
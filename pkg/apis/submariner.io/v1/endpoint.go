@@ -20,11 +20,14 @@ package v1
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/resource"
 	"k8s.io/apimachinery/pkg/api/equality"
+	k8snet "k8s.io/utils/net"
 )
 
 func (ep *EndpointSpec) GetBackendPort(configName string, defaultValue int32) (int32, error) {
@@ -40,6 +43,46 @@ func (ep *EndpointSpec) GetBackendPort(configName string, defaultValue int32) (i
 	return defaultValue, nil
 }
 
+// CableDriverUDPPortConfig returns the backend config name of the UDP port for the given cable driver.
+func CableDriverUDPPortConfig(driver string) string {
+	return driver + "-" + UDPPortConfig
+}
+
+// GetCableDriverPort returns the UDP port used by the given cable driver. A driver specific port takes precedence over
+// the shared UDPPortConfig so several cable drivers can run side by side on the same gateway.
+func (ep *EndpointSpec) GetCableDriverPort(driver string, defaultValue int32) (int32, error) {
+	if configName := CableDriverUDPPortConfig(driver); ep.BackendConfig[configName] != "" {
+		return ep.GetBackendPort(configName, defaultValue)
+	}
+
+	return ep.GetBackendPort(UDPPortConfig, defaultValue)
+}
+
+// ClusterScopedConfig returns the backend config name of the given config which only applies to the given remote cluster.
+func ClusterScopedConfig(configName, clusterID string) string {
+	return configName + "/" + clusterID
+}
+
+// BackendConfigFor returns the backend config which applies to the given remote cluster, ie without the configs scoped to
+// other clusters.
+func (ep *EndpointSpec) BackendConfigFor(clusterID string) map[string]string {
+	var config map[string]string
+
+	for name, value := range ep.BackendConfig {
+		if i := strings.LastIndex(name, "/"); i >= 0 && name[i+1:] != clusterID {
+			continue
+		}
+
+		if config == nil {
+			config = map[string]string{}
+		}
+
+		config[name] = value
+	}
+
+	return config
+}
+
 func (ep *EndpointSpec) GetBackendBool(configName string, defaultValue *bool) (*bool, error) {
 	if boolStr := ep.BackendConfig[configName]; boolStr != "" {
 		boolValue, err := strconv.ParseBool(boolStr)
@@ -91,6 +134,33 @@ func (ep *EndpointSpec) Equals(other *EndpointSpec) bool {
 		ep.Backend == other.Backend && ep.hasSameBackendConfig(other)
 }
 
+// TransitSubnetsExcluding returns the subnets of the transit routes that neither lead to nor traverse the given cluster,
+// ie the subnets whose traffic this Endpoint's gateway forwards to that cluster.
+func (ep *EndpointSpec) TransitSubnetsExcluding(clusterID string) []string {
+	var subnets []string
+
+	for i := range ep.TransitRoutes {
+		route := &ep.TransitRoutes[i]
+		if route.ClusterID == clusterID || slices.Contains(route.Path, clusterID) {
+			continue
+		}
+
+		for _, subnet := range route.Subnets {
+			if !slices.Contains(subnets, subnet) {
+				subnets = append(subnets, subnet)
+			}
+		}
+	}
+
+	return subnets
+}
+
+// IsActiveActive returns true if the Endpoint's cluster runs its gateways in active-active mode, in which case the
+// cluster may have several Endpoints which are all in use.
+func (ep *EndpointSpec) IsActiveActive() bool {
+	return ep.BackendConfig[GatewayHAModeConfig] == HAModeActiveActive
+}
+
 func (ep *EndpointSpec) hasSameBackendConfig(other *EndpointSpec) bool {
 	if ep.BackendConfig[UsingLoadBalancer] == "true" &&
 		other.BackendConfig[UsingLoadBalancer] == "true" {
@@ -102,3 +172,80 @@ func (ep *EndpointSpec) hasSameBackendConfig(other *EndpointSpec) bool {
 
 	return equality.Semantic.DeepEqual(ep.BackendConfig, other.BackendConfig)
 }
+
+// GetHealthCheckIP returns the health check IP for the given IP family or an empty string if there isn't one.
+func (ep *EndpointSpec) GetHealthCheckIP(family k8snet.IPFamily) string {
+	return ipForFamily(family, ep.HealthCheckIP, ep.HealthCheckIPs)
+}
+
+// SetHealthCheckIP sets the health check IP for the IP family of the given address.
+func (ep *EndpointSpec) SetHealthCheckIP(ip string) {
+	ep.HealthCheckIP, ep.HealthCheckIPs = setIPForFamily(ip, ep.HealthCheckIP, ep.HealthCheckIPs)
+}
+
+// GetPrivateIP returns the private IP for the given IP family or an empty string if there isn't one.
+func (ep *EndpointSpec) GetPrivateIP(family k8snet.IPFamily) string {
+	return ipForFamily(family, ep.PrivateIP, ep.PrivateIPs)
+}
+
+// SetPrivateIP sets the private IP for the IP family of the given address.
+func (ep *EndpointSpec) SetPrivateIP(ip string) {
+	ep.PrivateIP, ep.PrivateIPs = setIPForFamily(ip, ep.PrivateIP, ep.PrivateIPs)
+}
+
+// GetPublicIP returns the public IP for the given IP family or an empty string if there isn't one.
+func (ep *EndpointSpec) GetPublicIP(family k8snet.IPFamily) string {
+	return ipForFamily(family, ep.PublicIP, ep.PublicIPs)
+}
+
+// SetPublicIP sets the public IP for the IP family of the given address.
+func (ep *EndpointSpec) SetPublicIP(ip string) {
+	ep.PublicIP, ep.PublicIPs = setIPForFamily(ip, ep.PublicIP, ep.PublicIPs)
+}
+
+func ipForFamily(family k8snet.IPFamily, legacy string, ips []string) string {
+	for _, ip := range ips {
+		if k8snet.IPFamilyOfString(ip) == family {
+			return ip
+		}
+	}
+
+	if k8snet.IPFamilyOfString(legacy) == family {
+		return legacy
+	}
+
+	return ""
+}
+
+// setIPForFamily replaces the address of the same family in the list, or adds it, and returns the updated legacy field
+// and list. An endpoint from an older version may only have the legacy field set so it's carried over into the list.
+func setIPForFamily(ip, legacy string, ips []string) (string, []string) {
+	family := k8snet.IPFamilyOfString(ip)
+	if family == k8snet.IPFamilyUnknown {
+		return legacy, ips
+	}
+
+	if len(ips) == 0 && legacy != "" && k8snet.IPFamilyOfString(legacy) != family {
+		ips = []string{legacy}
+	}
+
+	newIPs := make([]string, 0, len(ips)+1)
+
+	for _, existing := range ips {
+		if k8snet.IPFamilyOfString(existing) != family {
+			newIPs = append(newIPs, existing)
+		}
+	}
+
+	newIPs = append(newIPs, ip)
+
+	slices.SortFunc(newIPs, func(a, b string) int {
+		return strings.Compare(string(k8snet.IPFamilyOfString(a)), string(k8snet.IPFamilyOfString(b)))
+	})
+
+	if legacy == "" || family == k8snet.IPv4 || k8snet.IPFamilyOfString(legacy) == family {
+		legacy = ip
+	}
+
+	return legacy, newIPs
+}
@@ -22,11 +22,16 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	k8snet "k8s.io/utils/net"
 )
 
 var _ = Describe("EndpointSpec", func() {
 	Context("GenerateName", testGenerateName)
 	Context("Equals", testEquals)
+	Context("IP family accessors", testIPFamilyAccessors)
+	Context("GetCableDriverPort", testGetCableDriverPort)
+	Context("TransitSubnetsExcluding", testTransitSubnetsExcluding)
+	Context("BackendConfigFor", testBackendConfigFor)
 })
 
 func testGenerateName() {
@@ -138,3 +143,122 @@ func testEquals() {
 		})
 	})
 }
+
+func testIPFamilyAccessors() {
+	var spec *v1.EndpointSpec
+
+	BeforeEach(func() {
+		spec = &v1.EndpointSpec{}
+	})
+
+	When("only an IPv4 address is set", func() {
+		It("should populate the legacy and list fields", func() {
+			spec.SetPrivateIP("10.1.1.1")
+
+			Expect(spec.PrivateIP).To(Equal("10.1.1.1"))
+			Expect(spec.PrivateIPs).To(Equal([]string{"10.1.1.1"}))
+			Expect(spec.GetPrivateIP(k8snet.IPv4)).To(Equal("10.1.1.1"))
+			Expect(spec.GetPrivateIP(k8snet.IPv6)).To(BeEmpty())
+		})
+	})
+
+	When("IPv6 and IPv4 addresses are set", func() {
+		It("should return the address for each family and keep the IPv4 address in the legacy field", func() {
+			spec.SetPublicIP("fd00::1")
+			Expect(spec.PublicIP).To(Equal("fd00::1"))
+
+			spec.SetPublicIP("1.2.3.4")
+
+			Expect(spec.PublicIP).To(Equal("1.2.3.4"))
+			Expect(spec.PublicIPs).To(Equal([]string{"1.2.3.4", "fd00::1"}))
+			Expect(spec.GetPublicIP(k8snet.IPv4)).To(Equal("1.2.3.4"))
+			Expect(spec.GetPublicIP(k8snet.IPv6)).To(Equal("fd00::1"))
+		})
+	})
+
+	When("an address of the same family is set again", func() {
+		It("should replace the existing address", func() {
+			spec.SetHealthCheckIP("10.1.1.1")
+			spec.SetHealthCheckIP("fd00::1")
+			spec.SetHealthCheckIP("10.2.2.2")
+
+			Expect(spec.HealthCheckIP).To(Equal("10.2.2.2"))
+			Expect(spec.HealthCheckIPs).To(Equal([]string{"10.2.2.2", "fd00::1"}))
+		})
+	})
+
+	When("only the legacy field is set", func() {
+		It("should return it for its family", func() {
+			spec.PrivateIP = "fd00::2"
+
+			Expect(spec.GetPrivateIP(k8snet.IPv6)).To(Equal("fd00::2"))
+			Expect(spec.GetPrivateIP(k8snet.IPv4)).To(BeEmpty())
+
+			spec.SetPrivateIP("10.1.1.1")
+			Expect(spec.PrivateIPs).To(Equal([]string{"10.1.1.1", "fd00::2"}))
+		})
+	})
+}
+
+func testGetCableDriverPort() {
+	var spec *v1.EndpointSpec
+
+	BeforeEach(func() {
+		spec = &v1.EndpointSpec{BackendConfig: map[string]string{v1.UDPPortConfig: "4500"}}
+	})
+
+	When("no driver specific port is configured", func() {
+		It("should return the shared UDP port", func() {
+			Expect(spec.GetCableDriverPort("vxlan", 1234)).To(Equal(int32(4500)))
+		})
+	})
+
+	When("a driver specific port is configured", func() {
+		It("should return it for that driver only", func() {
+			spec.BackendConfig[v1.CableDriverUDPPortConfig("vxlan")] = "4800"
+
+			Expect(spec.GetCableDriverPort("vxlan", 1234)).To(Equal(int32(4800)))
+			Expect(spec.GetCableDriverPort("libreswan", 1234)).To(Equal(int32(4500)))
+		})
+	})
+
+	When("no port is configured", func() {
+		It("should return the default", func() {
+			spec.BackendConfig = nil
+			Expect(spec.GetCableDriverPort("vxlan", 1234)).To(Equal(int32(1234)))
+		})
+	})
+}
+
+func testBackendConfigFor() {
+	spec := &v1.EndpointSpec{
+		BackendConfig: map[string]string{
+			v1.UDPPortConfig:                        "4500",
+			v1.ClusterScopedConfig("nonce", "east"): "1234",
+			v1.ClusterScopedConfig("nonce", "west"): "5678",
+		},
+	}
+
+	It("should return the configs which aren't scoped to other clusters", func() {
+		Expect(spec.BackendConfigFor("east")).To(Equal(map[string]string{
+			v1.UDPPortConfig:                        "4500",
+			v1.ClusterScopedConfig("nonce", "east"): "1234",
+		}))
+	})
+}
+
+func testTransitSubnetsExcluding() {
+	spec := &v1.EndpointSpec{
+		TransitRoutes: []v1.TransitRoute{
+			{ClusterID: "east", Subnets: []string{"10.0.0.0/16", "100.0.0.0/16"}, Path: []string{"hub"}},
+			{ClusterID: "west", Subnets: []string{"10.1.0.0/16"}, Path: []string{"hub"}},
+			{ClusterID: "north", Subnets: []string{"10.2.0.0/16"}, Path: []string{"hub", "west"}},
+		},
+	}
+
+	It("should return the subnets of the routes that don't lead to or traverse the cluster", func() {
+		Expect(spec.TransitSubnetsExcluding("west")).To(Equal([]string{"10.0.0.0/16", "100.0.0.0/16"}))
+		Expect(spec.TransitSubnetsExcluding("east")).To(Equal([]string{"10.1.0.0/16", "10.2.0.0/16"}))
+		Expect(spec.TransitSubnetsExcluding("south")).To(HaveLen(4))
+	})
+}
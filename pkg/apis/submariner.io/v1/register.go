@@ -55,6 +55,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&GlobalEgressIPList{},
 		&ClusterGlobalEgressIP{},
 		&ClusterGlobalEgressIPList{},
+		&MultiNamespaceGlobalEgressIP{},
+		&MultiNamespaceGlobalEgressIPList{},
 		&GlobalIngressIP{},
 		&GlobalIngressIPList{},
 		&GatewayRoute{},
@@ -63,6 +65,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&NonGatewayRouteList{},
 		&RouteAgent{},
 		&RouteAgentList{},
+		&ClusterTrafficPolicy{},
+		&ClusterTrafficPolicyList{},
+		&GatewayBenchmark{},
+		&GatewayBenchmarkList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 
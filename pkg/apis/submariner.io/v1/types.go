@@ -87,6 +87,29 @@ type EndpointSpec struct {
 	NATEnabled    bool              `json:"nat_enabled"`
 	Backend       string            `json:"backend"`
 	BackendConfig map[string]string `json:"backend_config,omitempty"`
+	// HealthCheckIPs, PrivateIPs and PublicIPs hold at most one address per IP family for dual-stack endpoints. The
+	// single-valued fields above are still populated with the IPv4 address (or the only address) for compatibility.
+	// +optional
+	HealthCheckIPs []string `json:"healthCheckIPs,omitempty"`
+	// +optional
+	PrivateIPs []string `json:"private_ips,omitempty"`
+	// +optional
+	PublicIPs []string `json:"public_ips,omitempty"`
+	// TransitRoutes advertises the subnets of other clusters that are reachable through this Endpoint's gateway.
+	// Remote clusters that aren't directly connected to those clusters route their subnets over the cable to this
+	// Endpoint.
+	// +optional
+	TransitRoutes []TransitRoute `json:"transitRoutes,omitempty"`
+}
+
+type TransitRoute struct {
+	// ClusterID is the ID of the cluster reachable through the advertising gateway.
+	ClusterID string `json:"clusterID"`
+	// Subnets are the subnets of the cluster.
+	Subnets []string `json:"subnets"`
+	// Path holds the IDs of the transit clusters the traffic traverses, starting with the advertising cluster. It is
+	// used to prevent routing loops.
+	Path []string `json:"path"`
 }
 
 const (
@@ -97,11 +120,40 @@ const (
 	PublicIP                = "public-ip"
 	UsingLoadBalancer       = "using-loadbalancer"
 	TCPMssValue             = "submariner.io/tcp-clamp-mss"
+	// GatewayHAModeConfig is the backend config which advertises the HA mode of the gateways of the Endpoint's cluster.
+	GatewayHAModeConfig = "gateway-ha-mode"
+	// BFDPortConfig is the backend config which advertises the UDP port on which the gateway accepts BFD sessions from
+	// the other gateway candidates in its cluster.
+	BFDPortConfig = "bfd-port"
+	// PMTUPortConfig is the backend config which advertises the UDP port on which the gateway answers path MTU probes
+	// from remote gateways.
+	PMTUPortConfig = "pmtu-port"
+	// BenchmarkPortConfig is the backend config which advertises the TCP and UDP port on which the gateway answers
+	// GatewayBenchmark traffic from remote gateways.
+	BenchmarkPortConfig = "benchmark-port"
+	// HealthCheckProbePortConfig is the backend config which advertises the UDP and TCP port on which the gateway answers
+	// health check probes from remote gateways.
+	HealthCheckProbePortConfig = "health-check-probe-port"
+	// CableDriversConfig is the backend config which advertises the comma-separated names of the cable drivers the
+	// gateway runs.
+	CableDriversConfig = "cable-drivers"
+	// CableDriverConfig is the backend config, scoped to a remote cluster via ClusterScopedConfig, which advertises the
+	// cable driver the gateway selected for that cluster.
+	CableDriverConfig = "cable-driver"
+)
+
+// Valid gateway HA modes.
+const (
+	// HAModeActivePassive is the default mode where a single elected gateway per cluster carries the traffic.
+	HAModeActivePassive = "active-passive"
+	// HAModeActiveActive is the mode where every gateway publishes its own Endpoint and carries traffic.
+	HAModeActiveActive = "active-active"
 )
 
 // Valid PublicIP resolvers.
 const (
 	IPv4         = "ipv4" // ipv4:1.2.3.4
+	IPv6         = "ipv6" // ipv6:2001:db8::1
 	LoadBalancer = "lb"   // lb:external-gw-lb
 	API          = "api"  // api:api.ipify.org
 	DNS          = "dns"  // dns:mygateway.dns.name.com
@@ -140,8 +192,32 @@ type GatewayStatus struct {
 	LocalEndpoint EndpointSpec `json:"localEndpoint"`
 	StatusFailure string       `json:"statusFailure"`
 	Connections   []Connection `json:"connections"`
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+type GatewayConditionType string
+
+const (
+	// GatewaySymmetricNAT indicates whether NAT discovery detected a symmetric NAT in front of the gateway, ie a NAT which
+	// maps the gateway's address to a different public port for each destination. Remote gateways can't reach the gateway
+	// on the mapped port learned from another peer in that case.
+	GatewaySymmetricNAT GatewayConditionType = "SymmetricNAT"
+
+	// GatewayGlobalIPPoolUsageHigh indicates whether the usage of any of the Globalnet global CIDRs has passed the configured
+	// threshold. Appending a CIDR to the cluster's global CIDRs provides more global IPs without restarting Globalnet.
+	GatewayGlobalIPPoolUsageHigh GatewayConditionType = "GlobalIPPoolUsageHigh"
+
+	// GatewayCIDROverlap indicates whether the Endpoints of any remote clusters aren't synced because their CIDRs overlap
+	// the local CIDRs. The message lists each conflicting cluster, the overlapping CIDRs and whether Globalnet would
+	// resolve the overlap.
+	GatewayCIDROverlap GatewayConditionType = "CIDROverlap"
+
+	// GatewayPeeringFiltered indicates whether any remote clusters aren't connected because the peering policy doesn't
+	// allow them. The message lists each filtered cluster and the reason.
+	GatewayPeeringFiltered GatewayConditionType = "PeeringFiltered"
+)
+
 // LatencySpec describes the round trip time information for a packet
 // between the gateway pods of two clusters.
 type LatencyRTTSpec struct {
@@ -175,6 +251,22 @@ type Connection struct {
 	UsingNAT      bool             `json:"usingNAT,omitempty"`
 	// +optional
 	LatencyRTT *LatencyRTTSpec `json:"latencyRTT,omitempty"`
+	// CableDriver is the name of the cable driver used for this connection.
+	// +optional
+	CableDriver string `json:"cableDriver,omitempty"`
+	// PathMTU is the path MTU to the remote endpoint measured by probing with the DF bit set.
+	// +optional
+	PathMTU int `json:"pathMTU,omitempty"`
+	// Traffic summarises the traffic carried by the cable, as reported by the cable driver.
+	// +optional
+	Traffic *ConnectionTraffic `json:"traffic,omitempty"`
+}
+
+type ConnectionTraffic struct {
+	RxBytes   int64 `json:"rxBytes"`
+	TxBytes   int64 `json:"txBytes"`
+	RxPackets int64 `json:"rxPackets"`
+	TxPackets int64 `json:"txPackets"`
 }
 
 type ConnectionStatus string
@@ -321,6 +413,54 @@ type ClusterGlobalEgressIPList struct {
 	Items []ClusterGlobalEgressIP `json:"items"`
 }
 
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope="Cluster",shortName="mngeip"
+// +kubebuilder:subresource:status
+// MultiNamespaceGlobalEgressIP defines a policy for allocating GlobalIPs for selected pods in all the namespaces selected
+// by its NamespaceSelector.
+type MultiNamespaceGlobalEgressIP struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of desired behavior.
+	Spec MultiNamespaceGlobalEgressIPSpec `json:"spec"`
+
+	// The most recently observed status. Read-only.
+	// +optional
+	Status GlobalEgressIPStatus `json:"status,omitempty"`
+}
+
+type MultiNamespaceGlobalEgressIPSpec struct {
+	// The requested number of contiguous GlobalIPs to allocate from the Globalnet CIDR assigned to the cluster.
+	// If not specified, defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=20
+	// +optional
+	NumberOfIPs *int `json:"numGlobalIPs,omitempty"`
+
+	// Selects the namespaces to which this MultiNamespaceGlobalEgressIP applies. Namespaces are matched dynamically as
+	// they're created or relabeled.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+
+	// Selects specific pods in the selected namespaces to which this MultiNamespaceGlobalEgressIP applies. If not
+	// specified, all pods in the selected namespaces are selected.
+	// A GlobalEgressIP in a selected namespace takes precedence over a MultiNamespaceGlobalEgressIP with the same kind
+	// of selection.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type MultiNamespaceGlobalEgressIPList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []MultiNamespaceGlobalEgressIP `json:"items"`
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:resource:shortName="giip"
@@ -350,6 +490,17 @@ type GlobalIngressIPSpec struct {
 	// The reference to a targeted Pod, if applicable.
 	// +Optional
 	PodRef *corev1.LocalObjectReference `json:"podRef,omitempty"`
+
+	// The specific GlobalIP to allocate, if any. It must be contained in one of the cluster's global CIDRs. If it's already
+	// allocated, allocation is retried until it becomes available.
+	// +optional
+	RequestedIP string `json:"requestedIP,omitempty"`
+
+	// The period after deletion during which the allocated GlobalIP is retained for a GlobalIngressIP with the same namespace
+	// and name, so a recreated Service gets the same GlobalIP. If not specified, the GlobalIP is released immediately.
+	// Retained GlobalIPs are persisted across restarts of the Globalnet controller.
+	// +optional
+	RetentionPeriod *metav1.Duration `json:"retentionPeriod,omitempty"`
 }
 
 type TargetType string
@@ -358,6 +509,9 @@ const (
 	ClusterIPService         TargetType = "ClusterIPService"
 	HeadlessServicePod       TargetType = "HeadlessServicePod"
 	HeadlessServiceEndpoints TargetType = "HeadlessServiceEndpoints"
+	// LoadBalancerService and NodePortService target a LoadBalancer or NodePort Service, reached like a ClusterIPService.
+	LoadBalancerService TargetType = "LoadBalancerService"
+	NodePortService     TargetType = "NodePortService"
 )
 
 type GlobalIngressIPStatus struct {
@@ -426,6 +580,179 @@ type RoutePolicySpec struct {
 	RemoteCIDRs []string `json:"remoteCIDRs"`
 }
 
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope="Cluster",shortName="ctp"
+// +kubebuilder:printcolumn:JSONPath=".spec.action",name="Action",type="string"
+// ClusterTrafficPolicy allows or denies traffic from remote clusters to the local cluster. Traffic is allowed by default.
+// Deny policies drop the traffic they match. Once a remote cluster is selected by an Allow policy, only the traffic
+// matched by Allow policies is accepted from it. Replies to connections initiated locally are always accepted.
+type ClusterTrafficPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterTrafficPolicySpec `json:"spec"`
+}
+
+type ClusterTrafficPolicySpec struct {
+	// Specifies whether matching traffic is allowed or denied.
+	// +kubebuilder:validation:Enum=Allow;Deny
+	Action TrafficPolicyAction `json:"action"`
+
+	// The IDs of the remote clusters this policy applies to. If empty, it applies to all remote clusters.
+	// +optional
+	RemoteClusters []string `json:"remoteClusters,omitempty"`
+
+	// The remote CIDRs this policy applies to. If empty, it applies to all subnets of the selected remote clusters.
+	// +optional
+	RemoteCIDRs []string `json:"remoteCIDRs,omitempty"`
+
+	// The local CIDRs this policy applies to. If empty, it applies to all local cluster CIDRs.
+	// +optional
+	LocalCIDRs []string `json:"localCIDRs,omitempty"`
+
+	// The destination ports this policy applies to. If empty, it applies to all protocols and ports.
+	// +optional
+	Ports []TrafficPolicyPort `json:"ports,omitempty"`
+}
+
+type TrafficPolicyAction string
+
+const (
+	TrafficPolicyAllow TrafficPolicyAction = "Allow"
+	TrafficPolicyDeny  TrafficPolicyAction = "Deny"
+)
+
+type TrafficPolicyPort struct {
+	// The IP protocol, TCP or UDP. Defaults to TCP.
+	// +kubebuilder:validation:Enum=TCP;UDP
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+
+	// The destination port. If not specified, all ports of the protocol are matched.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ClusterTrafficPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClusterTrafficPolicy `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName="gwb"
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".spec.remoteClusterID",name="Remote Cluster",type="string"
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name="Phase",type="string"
+// +kubebuilder:printcolumn:JSONPath=".status.results.throughput",name="Throughput",type="string"
+// GatewayBenchmark requests a one-off throughput and latency measurement from the active local gateway to the active
+// gateway of a remote cluster over the installed cable. It's created in the Submariner namespace.
+type GatewayBenchmark struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewayBenchmarkSpec `json:"spec"`
+
+	// +optional
+	Status GatewayBenchmarkStatus `json:"status,omitempty"`
+}
+
+type GatewayBenchmarkSpec struct {
+	// The ID of the remote cluster whose gateway is the target of the benchmark.
+	RemoteClusterID string `json:"remoteClusterID"`
+
+	// The transport protocol used, TCP or UDP. Defaults to TCP.
+	// +kubebuilder:validation:Enum=TCP;UDP
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+
+	// How long traffic is sent for. Defaults to 10s, at most 60s.
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s') && duration(self) <= duration('60s')"
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// For UDP, the rate in bits per second at which traffic is sent. Defaults to 100Mbps, at most 1Gbps.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000000000
+	// +optional
+	BitsPerSecond int64 `json:"bitsPerSecond,omitempty"`
+}
+
+type GatewayBenchmarkPhase string
+
+const (
+	GatewayBenchmarkRunning   GatewayBenchmarkPhase = "Running"
+	GatewayBenchmarkSucceeded GatewayBenchmarkPhase = "Succeeded"
+	GatewayBenchmarkFailed    GatewayBenchmarkPhase = "Failed"
+)
+
+type GatewayBenchmarkStatus struct {
+	// +optional
+	Phase GatewayBenchmarkPhase `json:"phase,omitempty"`
+
+	// A human-readable message, set if the benchmark failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The name of the local gateway that ran the benchmark.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// The remote health check IP the traffic was sent to.
+	// +optional
+	RemoteIP string `json:"remoteIP,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// +optional
+	Results *GatewayBenchmarkResults `json:"results,omitempty"`
+}
+
+type GatewayBenchmarkResults struct {
+	// The throughput measured at the remote gateway in bits per second.
+	BitsPerSecond int64 `json:"bitsPerSecond"`
+
+	// The throughput in a human-readable form, eg "942.1Mbps".
+	Throughput string `json:"throughput"`
+
+	// The number of bytes received by the remote gateway.
+	BytesReceived int64 `json:"bytesReceived"`
+
+	// For UDP, the interarrival jitter as defined by RFC 3550.
+	// +optional
+	Jitter string `json:"jitter,omitempty"`
+
+	// For UDP, the percentage of packets lost, eg "0.12".
+	// +optional
+	PacketLossPercent string `json:"packetLossPercent,omitempty"`
+
+	// The round trip time of the connection setup (TCP) or of the final report exchange (UDP).
+	// +optional
+	RTT string `json:"rtt,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type GatewayBenchmarkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []GatewayBenchmark `json:"items"`
+}
+
 var EndpointGVR = schema.GroupVersionResource{
 	Group:    SchemeGroupVersion.Group,
 	Version:  SchemeGroupVersion.Version,
@@ -437,3 +764,9 @@ var ClusterGVR = schema.GroupVersionResource{
 	Version:  SchemeGroupVersion.Version,
 	Resource: "clusters",
 }
+
+var GatewayBenchmarkGVR = schema.GroupVersionResource{
+	Group:    SchemeGroupVersion.Group,
+	Version:  SchemeGroupVersion.Version,
+	Resource: "gatewaybenchmarks",
+}
@@ -207,6 +207,102 @@ func (in *ClusterSpec) DeepCopy() *ClusterSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTrafficPolicy) DeepCopyInto(out *ClusterTrafficPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTrafficPolicy.
+func (in *ClusterTrafficPolicy) DeepCopy() *ClusterTrafficPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTrafficPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTrafficPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTrafficPolicyList) DeepCopyInto(out *ClusterTrafficPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTrafficPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTrafficPolicyList.
+func (in *ClusterTrafficPolicyList) DeepCopy() *ClusterTrafficPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTrafficPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTrafficPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTrafficPolicySpec) DeepCopyInto(out *ClusterTrafficPolicySpec) {
+	*out = *in
+	if in.RemoteClusters != nil {
+		in, out := &in.RemoteClusters, &out.RemoteClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemoteCIDRs != nil {
+		in, out := &in.RemoteCIDRs, &out.RemoteCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LocalCIDRs != nil {
+		in, out := &in.LocalCIDRs, &out.LocalCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]TrafficPolicyPort, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTrafficPolicySpec.
+func (in *ClusterTrafficPolicySpec) DeepCopy() *ClusterTrafficPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTrafficPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Connection) DeepCopyInto(out *Connection) {
 	*out = *in
@@ -216,6 +312,11 @@ func (in *Connection) DeepCopyInto(out *Connection) {
 		*out = new(LatencyRTTSpec)
 		**out = **in
 	}
+	if in.Traffic != nil {
+		in, out := &in.Traffic, &out.Traffic
+		*out = new(ConnectionTraffic)
+		**out = **in
+	}
 	return
 }
 
@@ -229,6 +330,22 @@ func (in *Connection) DeepCopy() *Connection {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionTraffic) DeepCopyInto(out *ConnectionTraffic) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionTraffic.
+func (in *ConnectionTraffic) DeepCopy() *ConnectionTraffic {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionTraffic)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 	*out = *in
@@ -304,6 +421,28 @@ func (in *EndpointSpec) DeepCopyInto(out *EndpointSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.HealthCheckIPs != nil {
+		in, out := &in.HealthCheckIPs, &out.HealthCheckIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrivateIPs != nil {
+		in, out := &in.PrivateIPs, &out.PrivateIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PublicIPs != nil {
+		in, out := &in.PublicIPs, &out.PublicIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TransitRoutes != nil {
+		in, out := &in.TransitRoutes, &out.TransitRoutes
+		*out = make([]TransitRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -344,6 +483,133 @@ func (in *Gateway) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBenchmark) DeepCopyInto(out *GatewayBenchmark) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBenchmark.
+func (in *GatewayBenchmark) DeepCopy() *GatewayBenchmark {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayBenchmark)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GatewayBenchmark) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBenchmarkList) DeepCopyInto(out *GatewayBenchmarkList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GatewayBenchmark, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBenchmarkList.
+func (in *GatewayBenchmarkList) DeepCopy() *GatewayBenchmarkList {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayBenchmarkList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GatewayBenchmarkList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBenchmarkResults) DeepCopyInto(out *GatewayBenchmarkResults) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBenchmarkResults.
+func (in *GatewayBenchmarkResults) DeepCopy() *GatewayBenchmarkResults {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayBenchmarkResults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBenchmarkSpec) DeepCopyInto(out *GatewayBenchmarkSpec) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBenchmarkSpec.
+func (in *GatewayBenchmarkSpec) DeepCopy() *GatewayBenchmarkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayBenchmarkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBenchmarkStatus) DeepCopyInto(out *GatewayBenchmarkStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = new(GatewayBenchmarkResults)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBenchmarkStatus.
+func (in *GatewayBenchmarkStatus) DeepCopy() *GatewayBenchmarkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayBenchmarkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayList) DeepCopyInto(out *GatewayList) {
 	*out = *in
@@ -448,6 +714,13 @@ func (in *GatewayStatus) DeepCopyInto(out *GatewayStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -650,6 +923,11 @@ func (in *GlobalIngressIPSpec) DeepCopyInto(out *GlobalIngressIPSpec) {
 		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
+	if in.RetentionPeriod != nil {
+		in, out := &in.RetentionPeriod, &out.RetentionPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -702,6 +980,94 @@ func (in *LatencyRTTSpec) DeepCopy() *LatencyRTTSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiNamespaceGlobalEgressIP) DeepCopyInto(out *MultiNamespaceGlobalEgressIP) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiNamespaceGlobalEgressIP.
+func (in *MultiNamespaceGlobalEgressIP) DeepCopy() *MultiNamespaceGlobalEgressIP {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiNamespaceGlobalEgressIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MultiNamespaceGlobalEgressIP) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiNamespaceGlobalEgressIPList) DeepCopyInto(out *MultiNamespaceGlobalEgressIPList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MultiNamespaceGlobalEgressIP, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiNamespaceGlobalEgressIPList.
+func (in *MultiNamespaceGlobalEgressIPList) DeepCopy() *MultiNamespaceGlobalEgressIPList {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiNamespaceGlobalEgressIPList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MultiNamespaceGlobalEgressIPList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiNamespaceGlobalEgressIPSpec) DeepCopyInto(out *MultiNamespaceGlobalEgressIPSpec) {
+	*out = *in
+	if in.NumberOfIPs != nil {
+		in, out := &in.NumberOfIPs, &out.NumberOfIPs
+		*out = new(int)
+		**out = **in
+	}
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiNamespaceGlobalEgressIPSpec.
+func (in *MultiNamespaceGlobalEgressIPSpec) DeepCopy() *MultiNamespaceGlobalEgressIPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiNamespaceGlobalEgressIPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NonGatewayRoute) DeepCopyInto(out *NonGatewayRoute) {
 	*out = *in
@@ -892,3 +1258,45 @@ func (in *RoutePolicySpec) DeepCopy() *RoutePolicySpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficPolicyPort) DeepCopyInto(out *TrafficPolicyPort) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficPolicyPort.
+func (in *TrafficPolicyPort) DeepCopy() *TrafficPolicyPort {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficPolicyPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransitRoute) DeepCopyInto(out *TransitRoute) {
+	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransitRoute.
+func (in *TransitRoute) DeepCopy() *TransitRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(TransitRoute)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,244 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	DefaultDuration      = 10 * time.Second
+	DefaultBitsPerSecond = 100 * 1000 * 1000
+
+	// MaxDuration and MaxBitsPerSecond bound the load a benchmark puts on the cable.
+	MaxDuration      = 60 * time.Second
+	MaxBitsPerSecond = 1000 * 1000 * 1000
+
+	dialTimeout    = 5 * time.Second
+	reportTimeout  = time.Second
+	reportAttempts = 3
+	pacingInterval = time.Millisecond
+)
+
+type Options struct {
+	Protocol corev1.Protocol
+	Duration time.Duration
+	// BitsPerSecond is the rate at which UDP traffic is sent.
+	BitsPerSecond int64
+}
+
+type Result struct {
+	BitsPerSecond int64
+	BytesReceived int64
+	// Jitter and PacketLossPercent are only measured for UDP.
+	Jitter            time.Duration
+	PacketLossPercent float64
+	RTT               time.Duration
+}
+
+// Measure sends traffic to the Server at the given address for the configured duration and returns what the Server
+// received. The duration and rate are capped at MaxDuration and MaxBitsPerSecond.
+func Measure(address string, options *Options) (*Result, error) {
+	o := *options
+
+	if o.Duration == 0 {
+		o.Duration = DefaultDuration
+	}
+
+	if o.BitsPerSecond == 0 {
+		o.BitsPerSecond = DefaultBitsPerSecond
+	}
+
+	o.Duration = min(o.Duration, MaxDuration)
+	o.BitsPerSecond = min(o.BitsPerSecond, MaxBitsPerSecond)
+
+	switch o.Protocol {
+	case corev1.ProtocolTCP, "":
+		return measureTCP(address, &o)
+	case corev1.ProtocolUDP:
+		return measureUDP(address, &o)
+	case corev1.ProtocolSCTP:
+	}
+
+	return nil, fmt.Errorf("unsupported benchmark protocol %q", o.Protocol)
+}
+
+func measureTCP(address string, o *Options) (*Result, error) {
+	start := time.Now()
+
+	conn, err := net.DialTimeout("tcp", address, dialTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error connecting to %s", address)
+	}
+
+	defer conn.Close()
+
+	rtt := time.Since(start)
+	buf := make([]byte, 128*1024)
+	deadline := time.Now().Add(o.Duration)
+
+	if err := conn.SetWriteDeadline(deadline); err != nil {
+		return nil, errors.Wrap(err, "error setting the write deadline")
+	}
+
+	for time.Now().Before(deadline) {
+		if _, err := conn.Write(buf); err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+
+			return nil, errors.Wrapf(err, "error sending to %s", address)
+		}
+	}
+
+	if err := conn.(*net.TCPConn).CloseWrite(); err != nil {
+		return nil, errors.Wrap(err, "error closing the connection for writing")
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(dialTimeout)); err != nil {
+		return nil, errors.Wrap(err, "error setting the read deadline")
+	}
+
+	reply := make([]byte, 16)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return nil, errors.Wrapf(err, "error reading the reply from %s", address)
+	}
+
+	received := int64(binary.BigEndian.Uint64(reply))
+
+	return &Result{
+		BitsPerSecond: bitsPerSecond(received, time.Duration(binary.BigEndian.Uint64(reply[8:]))),
+		BytesReceived: received,
+		RTT:           rtt,
+	}, nil
+}
+
+func measureUDP(address string, o *Options) (*Result, error) {
+	conn, err := net.DialTimeout("udp", address, dialTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error dialing %s", address)
+	}
+
+	defer conn.Close()
+
+	session := rand.Uint32() //nolint:gosec // Doesn't need to be cryptographically secure
+	packetsPerSecond := float64(o.BitsPerSecond) / (udpPacketSize * 8)
+
+	var sent uint32
+
+	start := time.Now()
+	ticker := time.NewTicker(pacingInterval)
+
+	defer ticker.Stop()
+
+	for elapsed := time.Duration(0); elapsed < o.Duration; elapsed = time.Since(start) {
+		// Send the packets due by now so the rate is kept regardless of the ticker's granularity.
+		for due := uint32(elapsed.Seconds() * packetsPerSecond); sent < due; sent++ {
+			h := &header{packetType: packetTypeData, session: session, seq: sent, sendTime: time.Now().UnixNano()}
+
+			// Send errors, eg ENOBUFS, are accounted for as loss.
+			_, _ = conn.Write(h.marshal(udpPacketSize))
+		}
+
+		<-ticker.C
+	}
+
+	r, rtt, err := requestReport(conn, session)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error requesting the report from %s", address)
+	}
+
+	result := &Result{
+		BitsPerSecond: bitsPerSecond(int64(r.bytesReceived), time.Duration(r.duration)),
+		BytesReceived: int64(r.bytesReceived),
+		Jitter:        time.Duration(r.jitter),
+		RTT:           rtt,
+	}
+
+	if sent > 0 && uint64(sent) > r.packetsReceived {
+		result.PacketLossPercent = float64(uint64(sent)-r.packetsReceived) * 100 / float64(sent)
+	}
+
+	return result, nil
+}
+
+func requestReport(conn net.Conn, session uint32) (*report, time.Duration, error) {
+	request := (&header{packetType: packetTypeReportRequest, session: session}).marshal(headerLength)
+	buf := make([]byte, reportLength)
+
+	var err error
+
+	for range reportAttempts {
+		start := time.Now()
+
+		if _, err = conn.Write(request); err != nil {
+			continue
+		}
+
+		if err = conn.SetReadDeadline(time.Now().Add(reportTimeout)); err != nil {
+			return nil, 0, errors.Wrap(err, "error setting the read deadline")
+		}
+
+		for {
+			var n int
+
+			n, err = conn.Read(buf)
+			if err != nil {
+				break
+			}
+
+			h, hErr := unmarshalHeader(buf[:n])
+			if hErr == nil && h.packetType == packetTypeReport && h.session == session && n >= reportLength {
+				return unmarshalReport(buf), time.Since(start), nil
+			}
+		}
+	}
+
+	return nil, 0, err
+}
+
+func bitsPerSecond(bytes int64, duration time.Duration) int64 {
+	if duration <= 0 {
+		return 0
+	}
+
+	return int64(float64(bytes*8) / duration.Seconds())
+}
+
+// FormatBitsPerSecond returns the given rate in a human-readable form, eg "942.1Mbps".
+func FormatBitsPerSecond(bps int64) string {
+	units := []string{"bps", "Kbps", "Mbps", "Gbps", "Tbps"}
+	v := float64(bps)
+	i := 0
+
+	for ; v >= 1000 && i < len(units)-1; i++ {
+		v /= 1000
+	}
+
+	return fmt.Sprintf("%.1f%s", v, units[i])
+}
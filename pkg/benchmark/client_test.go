@@ -0,0 +1,130 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark_test
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner/pkg/benchmark"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const measureDuration = 300 * time.Millisecond
+
+var _ = Describe("Measure", func() {
+	var address string
+
+	BeforeEach(func() {
+		address = startServer(nil)
+	})
+
+	When("the protocol is TCP", func() {
+		It("should return the received throughput", func() {
+			result, err := benchmark.Measure(address, &benchmark.Options{
+				Protocol: corev1.ProtocolTCP,
+				Duration: measureDuration,
+			})
+			Expect(err).To(Succeed())
+			Expect(result.BytesReceived).To(BeNumerically(">", 0))
+			Expect(result.BitsPerSecond).To(BeNumerically(">", 0))
+			Expect(result.RTT).To(BeNumerically(">", 0))
+		})
+	})
+
+	When("the protocol is UDP", func() {
+		It("should return the received throughput, jitter and loss", func() {
+			result, err := benchmark.Measure(address, &benchmark.Options{
+				Protocol:      corev1.ProtocolUDP,
+				Duration:      measureDuration,
+				BitsPerSecond: 10 * 1000 * 1000,
+			})
+			Expect(err).To(Succeed())
+			Expect(result.BytesReceived).To(BeNumerically(">", 0))
+			Expect(result.BitsPerSecond).To(BeNumerically("~", 10*1000*1000, 5*1000*1000))
+			Expect(result.PacketLossPercent).To(BeNumerically("<", 50))
+		})
+	})
+
+	When("there's no server", func() {
+		It("should return an error", func() {
+			_, err := benchmark.Measure(net.JoinHostPort("127.0.0.1", strconv.Itoa(freePort())), &benchmark.Options{
+				Protocol: corev1.ProtocolTCP,
+				Duration: measureDuration,
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the server doesn't allow the source IP", func() {
+		BeforeEach(func() {
+			address = startServer(func(_ net.IP) bool {
+				return false
+			})
+		})
+
+		It("should return an error", func() {
+			_, err := benchmark.Measure(address, &benchmark.Options{
+				Protocol: corev1.ProtocolTCP,
+				Duration: measureDuration,
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the protocol is unsupported", func() {
+		It("should return an error", func() {
+			_, err := benchmark.Measure(address, &benchmark.Options{Protocol: corev1.ProtocolSCTP})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("FormatBitsPerSecond", func() {
+	It("should return the rate in the largest unit", func() {
+		Expect(benchmark.FormatBitsPerSecond(512)).To(Equal("512.0bps"))
+		Expect(benchmark.FormatBitsPerSecond(942_100_000)).To(Equal("942.1Mbps"))
+		Expect(benchmark.FormatBitsPerSecond(10_000_000_000)).To(Equal("10.0Gbps"))
+	})
+})
+
+func startServer(isAllowed func(ip net.IP) bool) string {
+	server := benchmark.NewServer(freePort(), isAllowed)
+
+	stopCh := make(chan struct{})
+	DeferCleanup(func() {
+		close(stopCh)
+	})
+
+	Expect(server.Run(stopCh)).To(Succeed())
+
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(server.Port()))
+}
+
+func freePort() int {
+	listener, err := net.ListenTCP("tcp4", &net.TCPAddr{IP: net.ParseIP("127.0.0.1")})
+	Expect(err).To(Succeed())
+
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
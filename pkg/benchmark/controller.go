@@ -0,0 +1,349 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/resource"
+	"github.com/submariner-io/admiral/pkg/watcher"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cableengine"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
+)
+
+type ControllerConfig struct {
+	Engine        cableengine.Engine
+	DynamicClient dynamic.Interface
+	WatcherConfig *watcher.Config
+	Namespace     string
+	// GatewayName is the name of the local Gateway resource, recorded in the status of the benchmarks run by this gateway.
+	GatewayName string
+}
+
+type controller struct {
+	ControllerConfig
+	benchmarks dynamic.ResourceInterface
+	mutex      sync.Mutex
+	handled    map[types.UID]bool
+	// running holds the IDs of the remote clusters a benchmark is running against.
+	running map[string]bool
+}
+
+// StartController starts watching GatewayBenchmark resources and runs each new benchmark against the remote gateway.
+func StartController(config *ControllerConfig, stopCh <-chan struct{}) error {
+	logger.Info("Starting the benchmark controller")
+
+	c := &controller{
+		ControllerConfig: *config,
+		benchmarks:       config.DynamicClient.Resource(v1.GatewayBenchmarkGVR).Namespace(config.Namespace),
+		handled:          map[types.UID]bool{},
+		running:          map[string]bool{},
+	}
+
+	watcherConfig := *config.WatcherConfig
+	watcherConfig.ResourceConfigs = []watcher.ResourceConfig{
+		{
+			Name:         "Benchmark Controller",
+			ResourceType: &v1.GatewayBenchmark{},
+			Handler: watcher.EventHandlerFuncs{
+				OnCreateFunc: c.handleCreatedOrUpdated,
+				OnUpdateFunc: c.handleCreatedOrUpdated,
+				OnDeleteFunc: c.handleDeleted,
+			},
+			SourceNamespace: config.Namespace,
+		},
+	}
+
+	if watcherConfig.ResyncPeriod == 0 {
+		watcherConfig.ResyncPeriod = time.Second * 30
+	}
+
+	benchmarkWatcher, err := watcher.New(&watcherConfig)
+	if err != nil {
+		return errors.Wrap(err, "error creating the GatewayBenchmark watcher")
+	}
+
+	err = benchmarkWatcher.Start(stopCh)
+	if err != nil {
+		return errors.Wrap(err, "error starting the GatewayBenchmark watcher")
+	}
+
+	return nil
+}
+
+func (c *controller) handleCreatedOrUpdated(obj runtime.Object, _ int) bool {
+	benchmark := obj.(*v1.GatewayBenchmark)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.handled[benchmark.UID] {
+		return false
+	}
+
+	switch benchmark.Status.Phase {
+	case "":
+	case v1.GatewayBenchmarkRunning:
+		// The benchmark was started by this gateway before it restarted or lost leadership so it can't complete.
+		if benchmark.Status.Gateway == c.GatewayName {
+			c.handled[benchmark.UID] = true
+
+			go c.complete(benchmark, nil, errors.New("the benchmark was interrupted"))
+		}
+
+		return false
+	default:
+		return false
+	}
+
+	c.handled[benchmark.UID] = true
+
+	go c.run(benchmark)
+
+	return false
+}
+
+func (c *controller) handleDeleted(obj runtime.Object, _ int) bool {
+	benchmark := obj.(*v1.GatewayBenchmark)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.handled, benchmark.UID)
+
+	return false
+}
+
+func (c *controller) run(benchmark *v1.GatewayBenchmark) {
+	address, err := c.remoteAddressFor(benchmark.Spec.RemoteClusterID)
+	if err == nil {
+		err = validate(&benchmark.Spec)
+	}
+
+	benchmark.Status.Phase = v1.GatewayBenchmarkRunning
+	benchmark.Status.Gateway = c.GatewayName
+	benchmark.Status.RemoteIP, _, _ = net.SplitHostPort(address)
+	benchmark.Status.StartTime = ptr.To(metav1.Now())
+
+	// Updating with the observed resource version ensures only one active gateway claims the benchmark.
+	result, updateErr := c.benchmarks.UpdateStatus(context.TODO(), resource.MustToUnstructured(benchmark), metav1.UpdateOptions{})
+	if updateErr != nil {
+		if !apierrors.IsConflict(updateErr) && !apierrors.IsNotFound(updateErr) {
+			logger.Errorf(updateErr, "Error updating the status of GatewayBenchmark %q", benchmark.Name)
+		}
+
+		// On conflict, the ensuing update event is handled again unless another gateway claimed the benchmark.
+		c.unhandle(benchmark.UID)
+
+		return
+	}
+
+	benchmark.ResourceVersion = result.GetResourceVersion()
+
+	if err != nil {
+		c.complete(benchmark, nil, err)
+		return
+	}
+
+	if !c.startRunning(benchmark.Spec.RemoteClusterID) {
+		c.complete(benchmark, nil, fmt.Errorf("another benchmark is running against cluster %q", benchmark.Spec.RemoteClusterID))
+		return
+	}
+
+	defer c.stopRunning(benchmark.Spec.RemoteClusterID)
+
+	options := &Options{
+		Protocol:      benchmark.Spec.Protocol,
+		BitsPerSecond: benchmark.Spec.BitsPerSecond,
+	}
+
+	if benchmark.Spec.Duration != nil {
+		options.Duration = benchmark.Spec.Duration.Duration
+	}
+
+	logger.Infof("Running GatewayBenchmark %q against cluster %q at %s", benchmark.Name, benchmark.Spec.RemoteClusterID, address)
+
+	r, err := Measure(address, options)
+
+	c.complete(benchmark, r, err)
+}
+
+// validate rejects the benchmarks which would load the cable for longer, or at a higher rate, than allowed. The CRD
+// validation rejects them too but isn't enforced by older CRDs.
+func validate(spec *v1.GatewayBenchmarkSpec) error {
+	if spec.Duration != nil && (spec.Duration.Duration < 0 || spec.Duration.Duration > MaxDuration) {
+		return fmt.Errorf("the duration %v must be positive and at most %v", spec.Duration.Duration, MaxDuration)
+	}
+
+	if spec.BitsPerSecond < 0 || spec.BitsPerSecond > MaxBitsPerSecond {
+		return fmt.Errorf("the rate %s must be positive and at most %s", FormatBitsPerSecond(spec.BitsPerSecond),
+			FormatBitsPerSecond(MaxBitsPerSecond))
+	}
+
+	return nil
+}
+
+// startRunning returns false if a benchmark is already running against the given remote cluster, as concurrent
+// benchmarks would skew each other's results and saturate the cable.
+func (c *controller) startRunning(clusterID string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.running[clusterID] {
+		return false
+	}
+
+	c.running[clusterID] = true
+
+	return true
+}
+
+func (c *controller) stopRunning(clusterID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.running, clusterID)
+}
+
+func (c *controller) remoteAddressFor(clusterID string) (string, error) {
+	connections, err := c.Engine.ListCableConnections()
+	if err != nil {
+		return "", errors.Wrap(err, "error listing the cable connections")
+	}
+
+	for i := range connections {
+		endpoint := &connections[i].Endpoint
+		if endpoint.ClusterID != clusterID {
+			continue
+		}
+
+		if connections[i].Status != v1.Connected {
+			return "", fmt.Errorf("the cable to cluster %q is not connected", clusterID)
+		}
+
+		if endpoint.HealthCheckIP == "" {
+			return "", fmt.Errorf("the gateway of cluster %q has no health check IP", clusterID)
+		}
+
+		if endpoint.BackendConfig[v1.BenchmarkPortConfig] == "" {
+			return "", fmt.Errorf("benchmarking isn't enabled on the gateway of cluster %q", clusterID)
+		}
+
+		port, err := endpoint.GetBackendPort(v1.BenchmarkPortConfig, DefaultPort)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid benchmark port advertised by cluster %q", clusterID)
+		}
+
+		return net.JoinHostPort(endpoint.HealthCheckIP, strconv.Itoa(int(port))), nil
+	}
+
+	return "", fmt.Errorf("there's no cable to cluster %q", clusterID)
+}
+
+// IsRemoteEndpointIP returns whether the given IP is one of the IPs, or is in one of the subnets, of the remote Endpoints the
+// engine has cables to. It's used to only accept the benchmark and health check probe traffic of remote gateways.
+func IsRemoteEndpointIP(engine cableengine.Engine, ip net.IP) bool {
+	connections, err := engine.ListCableConnections()
+	if err != nil {
+		logger.Warningf("Error listing the cable connections: %v", err)
+		return false
+	}
+
+	for i := range connections {
+		endpoint := &connections[i].Endpoint
+
+		for _, endpointIP := range []string{endpoint.HealthCheckIP, endpoint.PrivateIP, endpoint.PublicIP, connections[i].UsingIP} {
+			if endpointIP != "" && ip.Equal(net.ParseIP(endpointIP)) {
+				return true
+			}
+		}
+
+		for _, subnet := range endpoint.Subnets {
+			if _, ipNet, err := net.ParseCIDR(subnet); err == nil && ipNet.Contains(ip) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (c *controller) complete(benchmark *v1.GatewayBenchmark, r *Result, err error) {
+	status := benchmark.Status
+	status.CompletionTime = ptr.To(metav1.Now())
+
+	if err != nil {
+		logger.Warningf("GatewayBenchmark %q failed: %v", benchmark.Name, err)
+
+		status.Phase = v1.GatewayBenchmarkFailed
+		status.Message = err.Error()
+	} else {
+		logger.Infof("GatewayBenchmark %q measured %s", benchmark.Name, FormatBitsPerSecond(r.BitsPerSecond))
+
+		status.Phase = v1.GatewayBenchmarkSucceeded
+		status.Results = &v1.GatewayBenchmarkResults{
+			BitsPerSecond:     r.BitsPerSecond,
+			Throughput:        FormatBitsPerSecond(r.BitsPerSecond),
+			BytesReceived:     r.BytesReceived,
+			Jitter:            r.Jitter.String(),
+			PacketLossPercent: fmt.Sprintf("%.2f", r.PacketLossPercent),
+			RTT:               r.RTT.String(),
+		}
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := c.benchmarks.Get(context.TODO(), benchmark.Name, metav1.GetOptions{})
+		if err != nil {
+			return err //nolint:wrapcheck // No need to wrap
+		}
+
+		existing := &v1.GatewayBenchmark{}
+		_ = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, existing)
+
+		existing.Status = status
+
+		_, err = c.benchmarks.UpdateStatus(context.TODO(), resource.MustToUnstructured(existing), metav1.UpdateOptions{})
+
+		return err //nolint:wrapcheck // No need to wrap
+	})
+
+	if err != nil && !apierrors.IsNotFound(err) {
+		logger.Errorf(err, "Error updating the status of GatewayBenchmark %q", benchmark.Name)
+	}
+}
+
+func (c *controller) unhandle(uid types.UID) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.handled, uid)
+}
@@ -0,0 +1,295 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/syncer/test"
+	"github.com/submariner-io/admiral/pkg/watcher"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/benchmark"
+	fakeengine "github.com/submariner-io/submariner/pkg/cableengine/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	fakeClient "k8s.io/client-go/dynamic/fake"
+	kubeScheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	namespace       = "submariner"
+	gatewayName     = "gateway-1"
+	remoteClusterID = "east"
+)
+
+var _ = Describe("Controller", func() {
+	var (
+		engine     *fakeengine.Engine
+		benchmarks dynamic.ResourceInterface
+		gwb        *v1.GatewayBenchmark
+		remotePort string
+	)
+
+	BeforeEach(func() {
+		_, remotePort, _ = splitAddress(startServer(nil))
+
+		engine = fakeengine.New()
+		engine.Connections = []v1.Connection{{
+			Status: v1.Connected,
+			Endpoint: v1.EndpointSpec{
+				ClusterID:     remoteClusterID,
+				CableName:     "submariner-cable-east-1-1-1-1",
+				HealthCheckIP: "127.0.0.1",
+				BackendConfig: map[string]string{v1.BenchmarkPortConfig: remotePort},
+			},
+		}}
+
+		gwb = &v1.GatewayBenchmark{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "east-tcp",
+				Namespace: namespace,
+			},
+			Spec: v1.GatewayBenchmarkSpec{
+				RemoteClusterID: remoteClusterID,
+				Duration:        &metav1.Duration{Duration: measureDuration},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		Expect(v1.AddToScheme(kubeScheme.Scheme)).To(Succeed())
+
+		scheme := runtime.NewScheme()
+		Expect(v1.AddToScheme(scheme)).To(Succeed())
+
+		client := fakeClient.NewSimpleDynamicClient(scheme)
+		benchmarks = client.Resource(v1.GatewayBenchmarkGVR).Namespace(namespace)
+
+		stopCh := make(chan struct{})
+		DeferCleanup(func() {
+			close(stopCh)
+		})
+
+		Expect(benchmark.StartController(&benchmark.ControllerConfig{
+			Engine:        engine,
+			DynamicClient: client,
+			WatcherConfig: &watcher.Config{
+				RestMapper: test.GetRESTMapperFor(&v1.GatewayBenchmark{}),
+				Client:     client,
+				Scheme:     scheme,
+			},
+			Namespace:   namespace,
+			GatewayName: gatewayName,
+		}, stopCh)).To(Succeed())
+	})
+
+	awaitPhase := func(phase v1.GatewayBenchmarkPhase) *v1.GatewayBenchmarkStatus {
+		status := &v1.GatewayBenchmarkStatus{}
+
+		Eventually(func() v1.GatewayBenchmarkPhase {
+			obj, err := benchmarks.Get(context.TODO(), gwb.Name, metav1.GetOptions{})
+			Expect(err).To(Succeed())
+
+			existing := &v1.GatewayBenchmark{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, existing)).To(Succeed())
+			*status = existing.Status
+
+			return status.Phase
+		}, 5*time.Second).Should(Equal(phase))
+
+		return status
+	}
+
+	When("a GatewayBenchmark is created", func() {
+		It("should run it and set the results", func() {
+			test.CreateResource(benchmarks, gwb)
+
+			status := awaitPhase(v1.GatewayBenchmarkSucceeded)
+			Expect(status.Gateway).To(Equal(gatewayName))
+			Expect(status.RemoteIP).To(Equal("127.0.0.1"))
+			Expect(status.StartTime).ToNot(BeNil())
+			Expect(status.CompletionTime).ToNot(BeNil())
+			Expect(status.Results).ToNot(BeNil())
+			Expect(status.Results.BytesReceived).To(BeNumerically(">", 0))
+			Expect(status.Results.Throughput).ToNot(BeEmpty())
+		})
+	})
+
+	When("there's no cable to the remote cluster", func() {
+		BeforeEach(func() {
+			gwb.Spec.RemoteClusterID = "west"
+		})
+
+		It("should fail it", func() {
+			test.CreateResource(benchmarks, gwb)
+
+			status := awaitPhase(v1.GatewayBenchmarkFailed)
+			Expect(status.Message).To(ContainSubstring("west"))
+			Expect(status.Results).To(BeNil())
+		})
+	})
+
+	When("the cable to the remote cluster isn't connected", func() {
+		BeforeEach(func() {
+			engine.Connections[0].Status = v1.ConnectionError
+		})
+
+		It("should fail it", func() {
+			test.CreateResource(benchmarks, gwb)
+			awaitPhase(v1.GatewayBenchmarkFailed)
+		})
+	})
+
+	When("the remote gateway doesn't advertise a benchmark port", func() {
+		BeforeEach(func() {
+			engine.Connections[0].Endpoint.BackendConfig = nil
+		})
+
+		It("should fail it", func() {
+			test.CreateResource(benchmarks, gwb)
+
+			status := awaitPhase(v1.GatewayBenchmarkFailed)
+			Expect(status.Message).To(ContainSubstring("isn't enabled"))
+		})
+	})
+
+	When("a GatewayBenchmark's duration exceeds the maximum", func() {
+		BeforeEach(func() {
+			gwb.Spec.Duration = &metav1.Duration{Duration: benchmark.MaxDuration + time.Second}
+		})
+
+		It("should fail it", func() {
+			test.CreateResource(benchmarks, gwb)
+
+			status := awaitPhase(v1.GatewayBenchmarkFailed)
+			Expect(status.Message).To(ContainSubstring("duration"))
+			Expect(status.Results).To(BeNil())
+		})
+	})
+
+	When("a GatewayBenchmark's rate exceeds the maximum", func() {
+		BeforeEach(func() {
+			gwb.Spec.BitsPerSecond = benchmark.MaxBitsPerSecond + 1
+		})
+
+		It("should fail it", func() {
+			test.CreateResource(benchmarks, gwb)
+
+			status := awaitPhase(v1.GatewayBenchmarkFailed)
+			Expect(status.Message).To(ContainSubstring("rate"))
+		})
+	})
+
+	When("a GatewayBenchmark is created while another runs against the same remote cluster", func() {
+		BeforeEach(func() {
+			gwb.Spec.Duration = &metav1.Duration{Duration: 2 * time.Second}
+		})
+
+		It("should fail it and complete the running one", func() {
+			test.CreateResource(benchmarks, gwb)
+			awaitPhase(v1.GatewayBenchmarkRunning)
+
+			running := gwb
+			gwb = running.DeepCopy()
+			gwb.Name = "east-udp"
+			// The fake client doesn't assign UIDs.
+			gwb.UID = "east-udp"
+			gwb.Spec.Protocol = "UDP"
+			test.CreateResource(benchmarks, gwb)
+
+			status := awaitPhase(v1.GatewayBenchmarkFailed)
+			Expect(status.Message).To(ContainSubstring("another benchmark is running"))
+
+			gwb = running
+			awaitPhase(v1.GatewayBenchmarkSucceeded)
+		})
+	})
+
+	When("a GatewayBenchmark was left running by this gateway", func() {
+		BeforeEach(func() {
+			gwb.Status.Phase = v1.GatewayBenchmarkRunning
+			gwb.Status.Gateway = gatewayName
+		})
+
+		It("should fail it", func() {
+			test.CreateResource(benchmarks, gwb)
+
+			status := awaitPhase(v1.GatewayBenchmarkFailed)
+			Expect(status.Message).To(ContainSubstring("interrupted"))
+		})
+	})
+
+	When("a GatewayBenchmark has already completed", func() {
+		BeforeEach(func() {
+			gwb.Status.Phase = v1.GatewayBenchmarkSucceeded
+		})
+
+		It("should not run it again", func() {
+			test.CreateResource(benchmarks, gwb)
+
+			Consistently(func() *metav1.Time {
+				obj, err := benchmarks.Get(context.TODO(), gwb.Name, metav1.GetOptions{})
+				Expect(err).To(Succeed())
+
+				existing := &v1.GatewayBenchmark{}
+				Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, existing)).To(Succeed())
+
+				return existing.Status.StartTime
+			}, 500*time.Millisecond).Should(BeNil())
+		})
+	})
+})
+
+var _ = Describe("IsRemoteEndpointIP", func() {
+	engine := fakeengine.New()
+	engine.Connections = []v1.Connection{{
+		Status: v1.Connected,
+		Endpoint: v1.EndpointSpec{
+			ClusterID:     remoteClusterID,
+			HealthCheckIP: "10.1.0.1",
+			PrivateIP:     "192.168.1.10",
+			Subnets:       []string{"242.1.0.0/16"},
+		},
+	}}
+
+	It("should return true for the IPs and subnets of remote Endpoints", func() {
+		Expect(benchmark.IsRemoteEndpointIP(engine, net.ParseIP("10.1.0.1"))).To(BeTrue())
+		Expect(benchmark.IsRemoteEndpointIP(engine, net.ParseIP("192.168.1.10"))).To(BeTrue())
+		Expect(benchmark.IsRemoteEndpointIP(engine, net.ParseIP("242.1.2.3"))).To(BeTrue())
+	})
+
+	It("should return false for other IPs", func() {
+		Expect(benchmark.IsRemoteEndpointIP(engine, net.ParseIP("10.2.0.1"))).To(BeFalse())
+	})
+})
+
+func splitAddress(address string) (string, string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err == nil {
+		_, err = strconv.Atoi(port)
+	}
+
+	return host, port, err
+}
@@ -0,0 +1,104 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	packetTypeData          = 1
+	packetTypeReportRequest = 2
+	packetTypeReport        = 3
+
+	headerLength = 24
+	reportLength = headerLength + 32
+
+	// udpPacketSize is small enough to not be fragmented over any of the cable types.
+	udpPacketSize = 1200
+)
+
+var magic = []byte("SMBM")
+
+// header is the header of the UDP packets. Data packets carry a sequence number and their send time, which are used by
+// the receiver to compute the loss and jitter, and are padded to udpPacketSize.
+type header struct {
+	packetType uint8
+	session    uint32
+	seq        uint32
+	sendTime   int64
+}
+
+func (h *header) marshal(length int) []byte {
+	b := make([]byte, max(length, headerLength))
+	copy(b, magic)
+	b[4] = h.packetType
+	binary.BigEndian.PutUint32(b[8:], h.session)
+	binary.BigEndian.PutUint32(b[12:], h.seq)
+	binary.BigEndian.PutUint64(b[16:], uint64(h.sendTime))
+
+	return b
+}
+
+func unmarshalHeader(b []byte) (*header, error) {
+	if len(b) < headerLength {
+		return nil, errors.Errorf("packet too short: %d bytes", len(b))
+	}
+
+	if !bytes.Equal(b[:len(magic)], magic) {
+		return nil, errors.New("invalid magic")
+	}
+
+	return &header{
+		packetType: b[4],
+		session:    binary.BigEndian.Uint32(b[8:]),
+		seq:        binary.BigEndian.Uint32(b[12:]),
+		sendTime:   int64(binary.BigEndian.Uint64(b[16:])),
+	}, nil
+}
+
+// report is sent by the receiver of a UDP session in reply to a report request.
+type report struct {
+	packetsReceived uint64
+	bytesReceived   uint64
+	jitter          uint64 // In nanoseconds
+	duration        uint64 // In nanoseconds, from the first to the last data packet received
+}
+
+func (r *report) marshal(session uint32) []byte {
+	b := (&header{packetType: packetTypeReport, session: session}).marshal(reportLength)
+	binary.BigEndian.PutUint64(b[headerLength:], r.packetsReceived)
+	binary.BigEndian.PutUint64(b[headerLength+8:], r.bytesReceived)
+	binary.BigEndian.PutUint64(b[headerLength+16:], r.jitter)
+	binary.BigEndian.PutUint64(b[headerLength+24:], r.duration)
+
+	return b
+}
+
+func unmarshalReport(b []byte) *report {
+	return &report{
+		packetsReceived: binary.BigEndian.Uint64(b[headerLength:]),
+		bytesReceived:   binary.BigEndian.Uint64(b[headerLength+8:]),
+		jitter:          binary.BigEndian.Uint64(b[headerLength+16:]),
+		duration:        binary.BigEndian.Uint64(b[headerLength+24:]),
+	}
+}
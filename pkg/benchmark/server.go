@@ -0,0 +1,262 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/submariner/pkg/port"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	DefaultPort = port.Benchmark
+
+	sessionIdleTimeout = time.Minute
+)
+
+var logger = log.Logger{Logger: logf.Log.WithName("Benchmark")}
+
+// Server receives the benchmark traffic sent by remote gateways on a TCP and a UDP port and reports what it received.
+type Server struct {
+	port      int
+	isAllowed func(ip net.IP) bool
+	mutex     sync.Mutex
+	sessions  map[sessionKey]*udpSession
+}
+
+type sessionKey struct {
+	addr    string
+	session uint32
+}
+
+type udpSession struct {
+	packetsReceived uint64
+	bytesReceived   uint64
+	first           time.Time
+	last            time.Time
+	prevTransit     int64
+	jitter          float64
+}
+
+// NewServer creates a Server which only accepts the traffic from the source IPs for which isAllowed returns true. A nil
+// isAllowed accepts the traffic from any source.
+func NewServer(port int, isAllowed func(ip net.IP) bool) *Server {
+	if port == 0 {
+		port = DefaultPort
+	}
+
+	if isAllowed == nil {
+		isAllowed = func(_ net.IP) bool {
+			return true
+		}
+	}
+
+	return &Server{
+		port:      port,
+		isAllowed: isAllowed,
+		sessions:  map[sessionKey]*udpSession{},
+	}
+}
+
+func (s *Server) Port() int {
+	return s.port
+}
+
+// Run starts receiving benchmark traffic until the stop channel is closed.
+func (s *Server) Run(stopCh <-chan struct{}) error {
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{Port: s.port})
+	if err != nil {
+		return errors.Wrapf(err, "error listening on UDP port %d", s.port)
+	}
+
+	tcpListener, err := net.ListenTCP("tcp", &net.TCPAddr{Port: s.port})
+	if err != nil {
+		udpConn.Close()
+		return errors.Wrapf(err, "error listening on TCP port %d", s.port)
+	}
+
+	logger.Infof("Benchmark server started on port %d", s.port)
+
+	go s.serveUDP(udpConn)
+	go s.serveTCP(tcpListener)
+
+	go func() {
+		<-stopCh
+		udpConn.Close()
+		tcpListener.Close()
+	}()
+
+	return nil
+}
+
+func (s *Server) serveTCP(listener *net.TCPListener) {
+	for {
+		conn, err := listener.AcceptTCP()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			logger.Warningf("Error accepting a benchmark connection: %v", err)
+
+			continue
+		}
+
+		if addr, ok := conn.RemoteAddr().(*net.TCPAddr); !ok || !s.isAllowed(addr.IP) {
+			logger.Warningf("Rejecting a benchmark connection from unknown source %s", conn.RemoteAddr())
+			conn.Close()
+
+			continue
+		}
+
+		go s.receiveTCP(conn)
+	}
+}
+
+// receiveTCP reads until the sender closes its side of the connection and then replies with the number of bytes received
+// and the time taken.
+func (s *Server) receiveTCP(conn *net.TCPConn) {
+	defer conn.Close()
+
+	buf := make([]byte, 128*1024)
+
+	n, err := conn.Read(buf)
+	if err != nil {
+		return
+	}
+
+	start := time.Now()
+
+	received, err := io.CopyBuffer(io.Discard, conn, buf)
+	if err != nil {
+		logger.Warningf("Error receiving benchmark traffic from %s: %v", conn.RemoteAddr(), err)
+		return
+	}
+
+	reply := make([]byte, 16)
+	binary.BigEndian.PutUint64(reply, uint64(received+int64(n)))
+	binary.BigEndian.PutUint64(reply[8:], uint64(time.Since(start)))
+
+	if _, err := conn.Write(reply); err != nil {
+		logger.Warningf("Error replying to benchmark traffic from %s: %v", conn.RemoteAddr(), err)
+	}
+}
+
+func (s *Server) serveUDP(conn *net.UDPConn) {
+	buf := make([]byte, 64*1024)
+
+	for {
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			logger.Warningf("Error reading benchmark traffic: %v", err)
+
+			continue
+		}
+
+		if !s.isAllowed(addr.IP) {
+			logger.V(log.TRACE).Infof("Ignoring benchmark packet from unknown source %s", addr)
+			continue
+		}
+
+		h, err := unmarshalHeader(buf[:n])
+		if err != nil {
+			logger.V(log.TRACE).Infof("Ignoring invalid benchmark packet from %s: %v", addr, err)
+			continue
+		}
+
+		key := sessionKey{addr: addr.String(), session: h.session}
+
+		switch h.packetType {
+		case packetTypeData:
+			s.receiveUDP(key, h, n)
+		case packetTypeReportRequest:
+			if _, err := conn.WriteToUDP(s.reportFor(key).marshal(h.session), addr); err != nil {
+				logger.Warningf("Error sending the benchmark report to %s: %v", addr, err)
+			}
+		}
+	}
+}
+
+func (s *Server) receiveUDP(key sessionKey, h *header, size int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	session, ok := s.sessions[key]
+	if !ok {
+		s.evictIdleSessions(now)
+
+		session = &udpSession{first: now}
+		s.sessions[key] = session
+	}
+
+	// Interarrival jitter as specified in RFC 3550 section 6.4.1. The clock offset between the sender and receiver
+	// cancels out.
+	transit := now.UnixNano() - h.sendTime
+	if session.packetsReceived > 0 {
+		d := float64(transit - session.prevTransit)
+		if d < 0 {
+			d = -d
+		}
+
+		session.jitter += (d - session.jitter) / 16
+	}
+
+	session.prevTransit = transit
+	session.packetsReceived++
+	session.bytesReceived += uint64(size)
+	session.last = now
+}
+
+func (s *Server) reportFor(key sessionKey) *report {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	session, ok := s.sessions[key]
+	if !ok {
+		return &report{}
+	}
+
+	return &report{
+		packetsReceived: session.packetsReceived,
+		bytesReceived:   session.bytesReceived,
+		jitter:          uint64(session.jitter),
+		duration:        uint64(session.last.Sub(session.first)),
+	}
+}
+
+func (s *Server) evictIdleSessions(now time.Time) {
+	for key, session := range s.sessions {
+		if now.Sub(session.last) > sessionIdleTimeout {
+			delete(s.sessions, key)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bfd
+
+import (
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	DefaultPort             = 3784
+	DefaultTxInterval       = 300 * time.Millisecond
+	DefaultDetectMultiplier = 3
+)
+
+type Config struct {
+	Enabled bool
+	// Port is the UDP port on which control packets are received and to which they're sent.
+	Port int
+	// TxInterval is the interval at which control packets are sent. It's also the minimum receive interval
+	// advertised to peers.
+	TxInterval time.Duration
+	// DetectMultiplier is the number of TxIntervals without a control packet after which a peer is declared down.
+	DetectMultiplier uint8
+}
+
+// DownHandler is invoked when the session with a peer added via AddPeer goes down after having been up. The detection
+// time is the time elapsed since the last control packet was received from the peer.
+type DownHandler func(detectionTime time.Duration)
+
+// Server runs BFD-style asynchronous mode sessions over UDP. Sessions are either initiated locally via AddPeer or
+// created passively on receipt of a control packet from an unknown peer, in which case they're removed once the peer
+// stops sending.
+type Server struct {
+	mutex    sync.Mutex
+	config   Config
+	conn     *net.UDPConn
+	sessions map[string]*session
+}
+
+type session struct {
+	peer                *net.UDPAddr
+	onDown              DownHandler
+	passive             bool
+	state               State
+	diagnostic          Diagnostic
+	localDiscriminator  uint32
+	remoteDiscriminator uint32
+	remoteMinTx         time.Duration
+	remoteDetectMult    uint8
+	lastRx              time.Time
+}
+
+var logger = log.Logger{Logger: logf.Log.WithName("BFD")}
+
+func NewServer(config Config) *Server {
+	if config.Port == 0 {
+		config.Port = DefaultPort
+	}
+
+	if config.TxInterval == 0 {
+		config.TxInterval = DefaultTxInterval
+	}
+
+	if config.DetectMultiplier == 0 {
+		config.DetectMultiplier = DefaultDetectMultiplier
+	}
+
+	return &Server{
+		config:   config,
+		sessions: map[string]*session{},
+	}
+}
+
+func (s *Server) Port() int {
+	return s.config.Port
+}
+
+func (s *Server) Run(stopCh <-chan struct{}) error {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{Port: s.config.Port})
+	if err != nil {
+		return errors.Wrapf(err, "error listening on UDP port %d", s.config.Port)
+	}
+
+	s.mutex.Lock()
+	s.conn = conn
+	s.mutex.Unlock()
+
+	logger.Infof("BFD server started on port %d with TX interval %v and detect multiplier %d", s.config.Port,
+		s.config.TxInterval, s.config.DetectMultiplier)
+
+	go s.receiveLoop(conn)
+
+	go func() {
+		ticker := time.NewTicker(s.config.TxInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.tick()
+			case <-stopCh:
+				s.shutdown()
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// AddPeer starts a session with the given peer, invoking onDown if the session subsequently goes down.
+func (s *Server) AddPeer(peer *net.UDPAddr, onDown DownHandler) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if existing, ok := s.sessions[peer.String()]; ok {
+		existing.onDown = onDown
+		existing.passive = false
+
+		return
+	}
+
+	logger.Infof("Adding BFD peer %s", peer)
+
+	s.sessions[peer.String()] = s.newSession(peer, onDown, false)
+}
+
+func (s *Server) RemovePeer(peer *net.UDPAddr) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.sessions[peer.String()]; ok {
+		logger.Infof("Removing BFD peer %s", peer)
+		delete(s.sessions, peer.String())
+	}
+}
+
+// PeerState returns the state of the session with the given peer and whether the session exists.
+func (s *Server) PeerState(peer *net.UDPAddr) (State, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if sess, ok := s.sessions[peer.String()]; ok {
+		return sess.state, true
+	}
+
+	return StateDown, false
+}
+
+func (s *Server) newSession(peer *net.UDPAddr, onDown DownHandler, passive bool) *session {
+	discriminator := rand.Uint32() //nolint:gosec // Use of math/rand is fine as the discriminator is not security-sensitive.
+	for discriminator == 0 || s.findSession(discriminator) != nil {
+		discriminator = rand.Uint32() //nolint:gosec // As above
+	}
+
+	return &session{
+		peer:               peer,
+		onDown:             onDown,
+		passive:            passive,
+		state:              StateDown,
+		localDiscriminator: discriminator,
+	}
+}
+
+func (s *Server) findSession(localDiscriminator uint32) *session {
+	for _, sess := range s.sessions {
+		if sess.localDiscriminator == localDiscriminator {
+			return sess
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) receiveLoop(conn *net.UDPConn) {
+	buf := make([]byte, 512)
+
+	for {
+		length, addr, err := conn.ReadFromUDP(buf)
+		if errors.Is(err, net.ErrClosed) {
+			logger.Info("Stopping BFD listener")
+			return
+		}
+
+		if err != nil {
+			logger.Errorf(err, "Error receiving from UDP")
+			continue
+		}
+
+		packet, err := UnmarshalControlPacket(buf[:length])
+		if err != nil {
+			logger.V(log.DEBUG).Infof("Discarding invalid control packet from %s: %v", addr, err)
+			continue
+		}
+
+		if notify := s.handlePacket(packet, addr); notify != nil {
+			notify()
+		}
+	}
+}
+
+func (s *Server) handlePacket(packet *ControlPacket, from *net.UDPAddr) func() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var sess *session
+
+	if packet.YourDiscriminator != 0 {
+		sess = s.findSession(packet.YourDiscriminator)
+	} else {
+		sess = s.sessions[from.String()]
+	}
+
+	if sess == nil {
+		if packet.YourDiscriminator != 0 || packet.State == StateAdminDown {
+			return nil
+		}
+
+		logger.V(log.DEBUG).Infof("Creating passive BFD session for peer %s", from)
+
+		sess = s.newSession(from, nil, true)
+		s.sessions[from.String()] = sess
+	}
+
+	sess.remoteDiscriminator = packet.MyDiscriminator
+	sess.remoteMinTx = packet.DesiredMinTxInterval
+	sess.remoteDetectMult = packet.DetectMultiplier
+	sess.lastRx = time.Now()
+
+	prevState := sess.state
+
+	var notify func()
+
+	if packet.State == StateAdminDown {
+		if sess.state != StateDown {
+			notify = s.sessionDown(sess, DiagNeighborSignaledDown, sess.lastRx)
+		}
+	} else {
+		switch sess.state {
+		case StateDown:
+			if packet.State == StateDown {
+				sess.state = StateInit
+			} else if packet.State == StateInit {
+				sess.state = StateUp
+			}
+		case StateInit:
+			if packet.State == StateInit || packet.State == StateUp {
+				sess.state = StateUp
+			}
+		case StateUp:
+			if packet.State == StateDown {
+				notify = s.sessionDown(sess, DiagNeighborSignaledDown, sess.lastRx)
+			}
+		case StateAdminDown:
+		}
+	}
+
+	if sess.state != prevState {
+		logger.Infof("BFD session with peer %s transitioned from %s to %s", sess.peer, prevState, sess.state)
+
+		if sess.state == StateUp {
+			sess.diagnostic = DiagNone
+		}
+
+		s.send(sess, sess.state)
+	}
+
+	return notify
+}
+
+// sessionDown transitions the session to Down and returns the function to notify the DownHandler, if any. This must be
+// called with the mutex held.
+func (s *Server) sessionDown(sess *session, diagnostic Diagnostic, lastRx time.Time) func() {
+	wasUp := sess.state == StateUp
+
+	sess.state = StateDown
+	sess.diagnostic = diagnostic
+
+	if diagnostic == DiagControlDetectionTimeExpired {
+		sess.remoteDiscriminator = 0
+	}
+
+	if !wasUp || sess.onDown == nil {
+		return nil
+	}
+
+	onDown := sess.onDown
+	detectionTime := time.Since(lastRx)
+
+	logger.Warningf("BFD session with peer %s went down after %v (diagnostic %d)", sess.peer, detectionTime, diagnostic)
+
+	return func() {
+		onDown(detectionTime)
+	}
+}
+
+func (s *Server) tick() {
+	now := time.Now()
+
+	var notifications []func()
+
+	s.mutex.Lock()
+
+	for key, sess := range s.sessions {
+		if (sess.state == StateInit || sess.state == StateUp) && now.Sub(sess.lastRx) > sess.detectionTime(s.config.TxInterval) {
+			if notify := s.sessionDown(sess, DiagControlDetectionTimeExpired, sess.lastRx); notify != nil {
+				notifications = append(notifications, notify)
+			}
+
+			if sess.passive {
+				logger.V(log.DEBUG).Infof("Removing passive BFD session for peer %s", sess.peer)
+				delete(s.sessions, key)
+
+				continue
+			}
+		}
+
+		s.send(sess, sess.state)
+	}
+
+	s.mutex.Unlock()
+
+	for _, notify := range notifications {
+		notify()
+	}
+}
+
+func (s *Server) shutdown() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Let the peers know we're going away so they don't have to wait for the detection time to expire.
+	for _, sess := range s.sessions {
+		s.send(sess, StateAdminDown)
+	}
+
+	s.conn.Close()
+	s.sessions = map[string]*session{}
+}
+
+// send sends a control packet to the session's peer. This must be called with the mutex held.
+func (s *Server) send(sess *session, state State) {
+	diagnostic := sess.diagnostic
+	if state == StateAdminDown {
+		diagnostic = DiagAdministrativelyDown
+	}
+
+	packet := &ControlPacket{
+		Diagnostic:            diagnostic,
+		State:                 state,
+		DetectMultiplier:      s.config.DetectMultiplier,
+		MyDiscriminator:       sess.localDiscriminator,
+		YourDiscriminator:     sess.remoteDiscriminator,
+		DesiredMinTxInterval:  s.config.TxInterval,
+		RequiredMinRxInterval: s.config.TxInterval,
+	}
+
+	if _, err := s.conn.WriteToUDP(packet.Marshal(), sess.peer); err != nil {
+		logger.V(log.DEBUG).Infof("Error sending control packet to %s: %v", sess.peer, err)
+	}
+}
+
+// detectionTime returns the time after which the peer is declared down if no control packets are received, per
+// section 6.8.4 of RFC 5880.
+func (sess *session) detectionTime(requiredMinRx time.Duration) time.Duration {
+	return time.Duration(sess.remoteDetectMult) * max(requiredMinRx, sess.remoteMinTx)
+}
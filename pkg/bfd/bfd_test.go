@@ -0,0 +1,200 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bfd_test
+
+import (
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner/pkg/bfd"
+)
+
+const txInterval = 50 * time.Millisecond
+
+var _ = Describe("ControlPacket", func() {
+	packet := &bfd.ControlPacket{
+		Diagnostic:            bfd.DiagNeighborSignaledDown,
+		State:                 bfd.StateInit,
+		DetectMultiplier:      3,
+		MyDiscriminator:       1234,
+		YourDiscriminator:     5678,
+		DesiredMinTxInterval:  100 * time.Millisecond,
+		RequiredMinRxInterval: 200 * time.Millisecond,
+	}
+
+	It("should be correctly marshalled and unmarshalled", func() {
+		actual, err := bfd.UnmarshalControlPacket(packet.Marshal())
+		Expect(err).To(Succeed())
+		Expect(actual).To(Equal(packet))
+	})
+
+	When("the packet is too short", func() {
+		It("should fail to unmarshal", func() {
+			_, err := bfd.UnmarshalControlPacket(packet.Marshal()[:10])
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the discriminator is zero", func() {
+		It("should fail to unmarshal", func() {
+			p := *packet
+			p.MyDiscriminator = 0
+
+			_, err := bfd.UnmarshalControlPacket(p.Marshal())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Server", func() {
+	var (
+		server    *bfd.Server
+		stopCh    chan struct{}
+		downTimes chan time.Duration
+	)
+
+	BeforeEach(func() {
+		downTimes = make(chan time.Duration, 10)
+		server, stopCh = startServer()
+	})
+
+	onDown := func(detectionTime time.Duration) {
+		downTimes <- detectionTime
+	}
+
+	When("the peer is another server", func() {
+		var (
+			peerAddr   *net.UDPAddr
+			peerStopCh chan struct{}
+		)
+
+		BeforeEach(func() {
+			var peer *bfd.Server
+
+			peer, peerStopCh = startServer()
+			peerAddr = loopbackAddr(peer.Port())
+
+			server.AddPeer(peerAddr, onDown)
+		})
+
+		It("should bring the session up", func() {
+			awaitState(server, peerAddr, bfd.StateUp)
+			Consistently(downTimes, txInterval*5).ShouldNot(Receive())
+		})
+
+		Context("and the peer is stopped", func() {
+			It("should invoke the down handler", func() {
+				awaitState(server, peerAddr, bfd.StateUp)
+
+				close(peerStopCh)
+
+				Eventually(downTimes).Should(Receive())
+				awaitState(server, peerAddr, bfd.StateDown)
+			})
+		})
+
+		Context("and the peer is removed", func() {
+			It("should no longer track the peer", func() {
+				awaitState(server, peerAddr, bfd.StateUp)
+
+				server.RemovePeer(peerAddr)
+
+				_, found := server.PeerState(peerAddr)
+				Expect(found).To(BeFalse())
+			})
+		})
+	})
+
+	When("the peer stops responding", func() {
+		It("should invoke the down handler after the detection time", func() {
+			conn, err := net.ListenUDP("udp4", loopbackAddr(0))
+			Expect(err).To(Succeed())
+
+			defer conn.Close()
+
+			peerAddr := loopbackAddr(conn.LocalAddr().(*net.UDPAddr).Port)
+			server.AddPeer(peerAddr, onDown)
+
+			buf := make([]byte, 100)
+			n, err := conn.Read(buf)
+			Expect(err).To(Succeed())
+
+			received, err := bfd.UnmarshalControlPacket(buf[:n])
+			Expect(err).To(Succeed())
+			Expect(received.State).To(Equal(bfd.StateDown))
+
+			reply := &bfd.ControlPacket{
+				State:                 bfd.StateInit,
+				DetectMultiplier:      3,
+				MyDiscriminator:       1,
+				YourDiscriminator:     received.MyDiscriminator,
+				DesiredMinTxInterval:  txInterval,
+				RequiredMinRxInterval: txInterval,
+			}
+
+			_, err = conn.WriteToUDP(reply.Marshal(), loopbackAddr(server.Port()))
+			Expect(err).To(Succeed())
+
+			awaitState(server, peerAddr, bfd.StateUp)
+
+			var detectionTime time.Duration
+			Eventually(downTimes).Should(Receive(&detectionTime))
+			Expect(detectionTime).To(BeNumerically(">=", 3*txInterval))
+			awaitState(server, peerAddr, bfd.StateDown)
+		})
+	})
+
+	AfterEach(func() {
+		close(stopCh)
+	})
+})
+
+func startServer() (*bfd.Server, chan struct{}) {
+	server := bfd.NewServer(bfd.Config{
+		Port:       freePort(),
+		TxInterval: txInterval,
+	})
+
+	stopCh := make(chan struct{})
+	Expect(server.Run(stopCh)).To(Succeed())
+
+	return server, stopCh
+}
+
+func awaitState(server *bfd.Server, peer *net.UDPAddr, state bfd.State) {
+	Eventually(func() bfd.State {
+		s, _ := server.PeerState(peer)
+		return s
+	}).Should(Equal(state))
+}
+
+func loopbackAddr(port int) *net.UDPAddr {
+	return &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}
+}
+
+func freePort() int {
+	conn, err := net.ListenUDP("udp4", loopbackAddr(0))
+	Expect(err).To(Succeed())
+
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
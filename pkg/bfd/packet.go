@@ -0,0 +1,131 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bfd
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// State is the session state as defined in RFC 5880.
+type State uint8
+
+const (
+	StateAdminDown State = iota
+	StateDown
+	StateInit
+	StateUp
+)
+
+func (s State) String() string {
+	switch s {
+	case StateAdminDown:
+		return "AdminDown"
+	case StateDown:
+		return "Down"
+	case StateInit:
+		return "Init"
+	case StateUp:
+		return "Up"
+	}
+
+	return "Unknown"
+}
+
+// Diagnostic is the reason for the last change in the local session state as defined in RFC 5880.
+type Diagnostic uint8
+
+const (
+	DiagNone                        Diagnostic = 0
+	DiagControlDetectionTimeExpired Diagnostic = 1
+	DiagNeighborSignaledDown        Diagnostic = 3
+	DiagAdministrativelyDown        Diagnostic = 7
+)
+
+const (
+	version      = 1
+	packetLength = 24
+)
+
+// ControlPacket is a BFD control packet without authentication, as defined in section 4.1 of RFC 5880.
+type ControlPacket struct {
+	Diagnostic            Diagnostic
+	State                 State
+	DetectMultiplier      uint8
+	MyDiscriminator       uint32
+	YourDiscriminator     uint32
+	DesiredMinTxInterval  time.Duration
+	RequiredMinRxInterval time.Duration
+}
+
+func (p *ControlPacket) Marshal() []byte {
+	b := make([]byte, packetLength)
+
+	b[0] = version<<5 | byte(p.Diagnostic)&0x1f
+	b[1] = byte(p.State) << 6
+	b[2] = p.DetectMultiplier
+	b[3] = packetLength
+	binary.BigEndian.PutUint32(b[4:], p.MyDiscriminator)
+	binary.BigEndian.PutUint32(b[8:], p.YourDiscriminator)
+	binary.BigEndian.PutUint32(b[12:], uint32(p.DesiredMinTxInterval.Microseconds()))  //nolint:gosec // Intervals fit in 32 bits
+	binary.BigEndian.PutUint32(b[16:], uint32(p.RequiredMinRxInterval.Microseconds())) //nolint:gosec // Intervals fit in 32 bits
+
+	return b
+}
+
+// UnmarshalControlPacket parses and validates a control packet per the reception rules in section 6.8.6 of RFC 5880.
+func UnmarshalControlPacket(b []byte) (*ControlPacket, error) {
+	if len(b) < packetLength {
+		return nil, errors.Errorf("packet too short: %d bytes", len(b))
+	}
+
+	if v := b[0] >> 5; v != version {
+		return nil, errors.Errorf("unsupported version %d", v)
+	}
+
+	if length := int(b[3]); length < packetLength || length > len(b) {
+		return nil, errors.Errorf("invalid length %d", length)
+	}
+
+	p := &ControlPacket{
+		Diagnostic:            Diagnostic(b[0] & 0x1f),
+		State:                 State(b[1] >> 6),
+		DetectMultiplier:      b[2],
+		MyDiscriminator:       binary.BigEndian.Uint32(b[4:]),
+		YourDiscriminator:     binary.BigEndian.Uint32(b[8:]),
+		DesiredMinTxInterval:  time.Duration(binary.BigEndian.Uint32(b[12:])) * time.Microsecond,
+		RequiredMinRxInterval: time.Duration(binary.BigEndian.Uint32(b[16:])) * time.Microsecond,
+	}
+
+	if p.DetectMultiplier == 0 {
+		return nil, errors.New("detect multiplier is zero")
+	}
+
+	if p.MyDiscriminator == 0 {
+		return nil, errors.New("my discriminator is zero")
+	}
+
+	if p.YourDiscriminator == 0 && p.State != StateDown && p.State != StateAdminDown {
+		return nil, errors.Errorf("your discriminator is zero in state %s", p.State)
+	}
+
+	return p, nil
+}
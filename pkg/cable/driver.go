@@ -20,6 +20,8 @@ package cable
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/submariner-io/admiral/pkg/log"
@@ -27,6 +29,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/endpoint"
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
 	"github.com/submariner-io/submariner/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -69,6 +72,18 @@ var drivers = map[string]DriverCreateFunc{}
 // Default name of the cable driver.
 var defaultCableDriver string
 
+// Names of the cable drivers to use for specific remote clusters, keyed by cluster ID.
+var clusterCableDrivers = map[string]string{}
+
+// Default UDP ports of the cable drivers, keyed by driver name, used when they run beside the Backend driver.
+var driverDefaultPorts = map[string]int32{}
+
+// Kubernetes client and namespace for cable drivers which persist state in Kubernetes resources.
+var (
+	kubeClient    kubernetes.Interface
+	kubeNamespace string
+)
+
 var logger = log.Logger{Logger: logf.Log.WithName("CableDriver")}
 
 // Adds a supported driver, prints a fatal error in the case of double registration.
@@ -83,24 +98,176 @@ func AddDriver(name string, driverCreate DriverCreateFunc) {
 // Returns a new driver according the required Backend.
 func NewDriver(localEndpoint *endpoint.Local, localCluster *types.SubmarinerCluster) (Driver, error) {
 	// We'll panic if localEndpoint or localCluster are nil, this is intentional
-	spec := localEndpoint.Spec()
+	return NewDriverByName(localEndpoint.Spec().Backend, localEndpoint, localCluster)
+}
 
-	driverCreate, ok := drivers[spec.Backend]
+// Returns a new driver with the given name.
+func NewDriverByName(name string, localEndpoint *endpoint.Local, localCluster *types.SubmarinerCluster) (Driver, error) {
+	driverCreate, ok := drivers[name]
 	if !ok {
-		var driverList strings.Builder
+		return nil, fmt.Errorf("unsupported cable type %s; supported types: %s", name, supportedDrivers())
+	}
 
-		for driver := range drivers {
-			if driverList.Len() > 0 {
-				driverList.WriteString(", ")
-			}
+	return driverCreate(localEndpoint, localCluster)
+}
+
+func supportedDrivers() string {
+	names := make([]string, 0, len(drivers))
+	for driver := range drivers {
+		names = append(names, driver)
+	}
 
-			driverList.WriteString(driver)
+	slices.Sort(names)
+
+	return strings.Join(names, ", ")
+}
+
+// Sets the cable drivers to use for specific remote clusters, keyed by cluster ID. Remote clusters that aren't
+// present use the local endpoint's Backend.
+func SetClusterCableDrivers(clusterDrivers map[string]string) error {
+	newDrivers := make(map[string]string, len(clusterDrivers))
+
+	for clusterID, name := range clusterDrivers {
+		name = strings.ToLower(name)
+		if _, ok := drivers[name]; !ok {
+			return fmt.Errorf("unsupported cable type %s for cluster %q; supported types: %s", name, clusterID, supportedDrivers())
 		}
 
-		return nil, fmt.Errorf("unsupported cable type %s; supported types: %s", spec.Backend, driverList.String())
+		newDrivers[clusterID] = name
 	}
 
-	return driverCreate(localEndpoint, localCluster)
+	clusterCableDrivers = newDrivers
+
+	return nil
+}
+
+// Returns the name of the cable driver to use to connect the local endpoint to the given remote endpoint. Both sides
+// advertise the cable drivers they run and the one they selected for the other's cluster. If the selections differ,
+// the selection of the cluster with the lowest ID is used if both sides run it, otherwise the other cluster's, so both
+// sides pick the same driver. An error is returned if neither selection is run by both sides. A remote endpoint which
+// doesn't advertise its drivers is assumed to use its Backend for all clusters.
+func GetDriverNameFor(localEndpoint, remoteEndpoint *v1.EndpointSpec) (string, error) {
+	localName, ok := clusterCableDrivers[remoteEndpoint.ClusterID]
+	if !ok {
+		localName = localEndpoint.Backend
+	}
+
+	remoteName, remoteNames := remoteDriverNames(remoteEndpoint, localEndpoint.ClusterID)
+	if remoteName == "" || remoteName == localName {
+		return localName, nil
+	}
+
+	localNames := GetDriverNames(localEndpoint)
+
+	candidates := []string{localName, remoteName}
+	if remoteEndpoint.ClusterID < localEndpoint.ClusterID {
+		candidates = []string{remoteName, localName}
+	}
+
+	for _, name := range candidates {
+		if slices.Contains(localNames, name) && slices.Contains(remoteNames, name) {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("cluster %q selected the %q cable driver but the remote cluster %q selected %q and the clusters "+
+		"don't both run either driver (local: %s, remote: %s)", localEndpoint.ClusterID, localName, remoteEndpoint.ClusterID,
+		remoteName, strings.Join(localNames, ", "), strings.Join(remoteNames, ", "))
+}
+
+// Returns the cable driver the given remote endpoint selected for the given local cluster and the drivers it runs, as
+// advertised in its backend config.
+func remoteDriverNames(remoteEndpoint *v1.EndpointSpec, localClusterID string) (string, []string) {
+	name := remoteEndpoint.BackendConfig[v1.ClusterScopedConfig(v1.CableDriverConfig, localClusterID)]
+	if name == "" {
+		name = remoteEndpoint.Backend
+	}
+
+	names := []string{remoteEndpoint.Backend}
+	if advertised := remoteEndpoint.BackendConfig[v1.CableDriversConfig]; advertised != "" {
+		names = strings.Split(advertised, ",")
+	}
+
+	return name, names
+}
+
+// Publishes, in the local endpoint's backend config, the cable drivers it runs and those selected for specific remote
+// clusters so remote gateways can agree on the driver to use.
+func AddDriverSelection(localEndpoint *v1.EndpointSpec) {
+	if localEndpoint.BackendConfig == nil {
+		localEndpoint.BackendConfig = map[string]string{}
+	}
+
+	localEndpoint.BackendConfig[v1.CableDriversConfig] = strings.Join(GetDriverNames(localEndpoint), ",")
+
+	for clusterID, name := range clusterCableDrivers {
+		localEndpoint.BackendConfig[v1.ClusterScopedConfig(v1.CableDriverConfig, clusterID)] = name
+	}
+}
+
+// Sets the UDP port a driver uses by default when it isn't the local endpoint's Backend, so it doesn't collide with the
+// Backend driver on the shared UDP port.
+func SetDriverDefaultPort(name string, port int32) {
+	driverDefaultPorts[name] = port
+}
+
+// Publishes, in the local endpoint's backend config, the UDP ports of the drivers used for specific remote clusters
+// that don't have a port configured, so they don't collide with the Backend driver on the shared UDP port.
+func AddDriverPorts(localEndpoint *v1.EndpointSpec) {
+	for _, name := range GetDriverNames(localEndpoint)[1:] {
+		port, ok := driverDefaultPorts[name]
+		configName := v1.CableDriverUDPPortConfig(name)
+
+		if !ok || localEndpoint.BackendConfig[configName] != "" {
+			continue
+		}
+
+		if localEndpoint.BackendConfig == nil {
+			localEndpoint.BackendConfig = map[string]string{}
+		}
+
+		localEndpoint.BackendConfig[configName] = strconv.Itoa(int(port))
+	}
+}
+
+// Validates that the cable drivers used by the local endpoint don't share a UDP port, eg because the port configured for
+// one of them is the same as the shared UDP port used by the Backend driver.
+func ValidateDriverPorts(localEndpoint *v1.EndpointSpec) error {
+	drivers := map[int32]string{}
+
+	for _, name := range GetDriverNames(localEndpoint) {
+		port, err := localEndpoint.GetCableDriverPort(name, driverDefaultPorts[name])
+		if err != nil {
+			return err //nolint:wrapcheck // No need to wrap
+		}
+
+		if port == 0 {
+			continue
+		}
+
+		if other, found := drivers[port]; found {
+			return fmt.Errorf("cable drivers %q and %q are both configured to use UDP port %d", other, name, port)
+		}
+
+		drivers[port] = name
+	}
+
+	return nil
+}
+
+// Returns the names of all the cable drivers used by the local endpoint, starting with its Backend.
+func GetDriverNames(localEndpoint *v1.EndpointSpec) []string {
+	names := []string{localEndpoint.Backend}
+
+	for _, name := range clusterCableDrivers {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names[1:])
+
+	return names
 }
 
 // Sets the default cable driver name, if it is not specified by user.
@@ -112,3 +279,14 @@ func SetDefaultCableDriver(driver string) {
 func GetDefaultCableDriver() string {
 	return defaultCableDriver
 }
+
+// Sets the Kubernetes client and namespace available to cable drivers which persist state, eg keys, in Kubernetes resources.
+func SetKubeClient(client kubernetes.Interface, namespace string) {
+	kubeClient = client
+	kubeNamespace = namespace
+}
+
+// Returns the Kubernetes client and namespace set via SetKubeClient. The client is nil if it wasn't set.
+func GetKubeClient() (kubernetes.Interface, string) {
+	return kubeClient, kubeNamespace
+}
@@ -0,0 +1,190 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cable_test
+
+import (
+	"strconv"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/log/kzerolog"
+	subv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cable"
+	"github.com/submariner-io/submariner/pkg/cable/libreswan"
+	"github.com/submariner-io/submariner/pkg/cable/vxlan"
+	"github.com/submariner-io/submariner/pkg/cable/wireguard"
+	"github.com/submariner-io/submariner/pkg/cable/xfrm"
+	"github.com/submariner-io/submariner/pkg/port"
+)
+
+func init() {
+	kzerolog.AddFlags(nil)
+}
+
+var _ = BeforeSuite(func() {
+	kzerolog.InitK8sLogging()
+})
+
+func TestCable(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cable Driver Suite")
+}
+
+var _ = Describe("Driver ports", func() {
+	var localEndpoint *subv1.EndpointSpec
+
+	BeforeEach(func() {
+		localEndpoint = &subv1.EndpointSpec{
+			BackendConfig: map[string]string{subv1.UDPPortConfig: strconv.Itoa(port.ExternalTunnel)},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(cable.SetClusterCableDrivers(nil)).To(Succeed())
+	})
+
+	testDriverPorts := func(backend, other string, otherPort int) {
+		BeforeEach(func() {
+			localEndpoint.Backend = backend
+			Expect(cable.SetClusterCableDrivers(map[string]string{"east": other})).To(Succeed())
+		})
+
+		It("should publish the default port of the other driver", func() {
+			cable.AddDriverPorts(localEndpoint)
+			Expect(localEndpoint.BackendConfig).To(HaveKeyWithValue(subv1.CableDriverUDPPortConfig(other), strconv.Itoa(otherPort)))
+			Expect(cable.ValidateDriverPorts(localEndpoint)).To(Succeed())
+		})
+
+		Context("and the other driver is configured with the shared UDP port", func() {
+			BeforeEach(func() {
+				localEndpoint.BackendConfig[subv1.CableDriverUDPPortConfig(other)] = strconv.Itoa(port.ExternalTunnel)
+			})
+
+			It("should reject the configuration", func() {
+				cable.AddDriverPorts(localEndpoint)
+				Expect(cable.ValidateDriverPorts(localEndpoint)).ToNot(Succeed())
+			})
+		})
+	}
+
+	When("the Backend is libreswan and wireguard is used for a remote cluster", func() {
+		testDriverPorts("libreswan", "wireguard", wireguard.DefaultPort)
+	})
+
+	When("the Backend is vxlan and libreswan is used for a remote cluster", func() {
+		testDriverPorts(vxlan.CableDriverName, "libreswan", libreswan.DefaultPort)
+	})
+
+	When("the Backend is libreswan and xfrm is used for a remote cluster", func() {
+		testDriverPorts("libreswan", xfrm.CableDriverName, xfrm.DefaultPort)
+	})
+})
+
+var _ = Describe("GetDriverNameFor", func() {
+	var localEndpoint, remoteEndpoint *subv1.EndpointSpec
+
+	BeforeEach(func() {
+		localEndpoint = &subv1.EndpointSpec{ClusterID: "east", Backend: "libreswan"}
+		remoteEndpoint = &subv1.EndpointSpec{ClusterID: "west", Backend: "libreswan", BackendConfig: map[string]string{}}
+	})
+
+	AfterEach(func() {
+		Expect(cable.SetClusterCableDrivers(nil)).To(Succeed())
+	})
+
+	When("both sides select the same driver", func() {
+		It("should return it", func() {
+			Expect(cable.GetDriverNameFor(localEndpoint, remoteEndpoint)).To(Equal("libreswan"))
+		})
+	})
+
+	When("the remote endpoint doesn't advertise its drivers", func() {
+		BeforeEach(func() {
+			remoteEndpoint.Backend = ""
+			Expect(cable.SetClusterCableDrivers(map[string]string{"west": "wireguard"})).To(Succeed())
+		})
+
+		It("should return the locally selected driver", func() {
+			Expect(cable.GetDriverNameFor(localEndpoint, remoteEndpoint)).To(Equal("wireguard"))
+		})
+	})
+
+	When("the sides select different drivers which they both run", func() {
+		BeforeEach(func() {
+			Expect(cable.SetClusterCableDrivers(map[string]string{"west": "wireguard"})).To(Succeed())
+			remoteEndpoint.BackendConfig[subv1.CableDriversConfig] = "libreswan,wireguard"
+		})
+
+		It("should return the driver selected by the cluster with the lowest ID on both sides", func() {
+			Expect(cable.GetDriverNameFor(localEndpoint, remoteEndpoint)).To(Equal("wireguard"))
+
+			// The west cluster runs wireguard too, for another cluster, as it advertises.
+			Expect(cable.SetClusterCableDrivers(map[string]string{"north": "wireguard"})).To(Succeed())
+
+			localEndpoint, remoteEndpoint = remoteEndpoint, localEndpoint
+			remoteEndpoint.BackendConfig = map[string]string{
+				subv1.CableDriversConfig:                                   "libreswan,wireguard",
+				subv1.ClusterScopedConfig(subv1.CableDriverConfig, "west"): "wireguard",
+			}
+
+			Expect(cable.GetDriverNameFor(localEndpoint, remoteEndpoint)).To(Equal("wireguard"))
+		})
+	})
+
+	When("the cluster with the lowest ID selected a driver the other doesn't run", func() {
+		BeforeEach(func() {
+			Expect(cable.SetClusterCableDrivers(map[string]string{"west": "wireguard"})).To(Succeed())
+			remoteEndpoint.BackendConfig[subv1.CableDriversConfig] = "libreswan"
+		})
+
+		It("should return the driver selected by the other cluster", func() {
+			Expect(cable.GetDriverNameFor(localEndpoint, remoteEndpoint)).To(Equal("libreswan"))
+		})
+	})
+
+	When("neither selected driver is run by both sides", func() {
+		BeforeEach(func() {
+			Expect(cable.SetClusterCableDrivers(map[string]string{"west": "wireguard"})).To(Succeed())
+			remoteEndpoint.Backend = vxlan.CableDriverName
+		})
+
+		It("should return an error", func() {
+			_, err := cable.GetDriverNameFor(localEndpoint, remoteEndpoint)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("AddDriverSelection", func() {
+	AfterEach(func() {
+		Expect(cable.SetClusterCableDrivers(nil)).To(Succeed())
+	})
+
+	It("should publish the drivers run by the local endpoint and those selected per remote cluster", func() {
+		Expect(cable.SetClusterCableDrivers(map[string]string{"west": "wireguard"})).To(Succeed())
+
+		localEndpoint := &subv1.EndpointSpec{ClusterID: "east", Backend: "libreswan"}
+		cable.AddDriverSelection(localEndpoint)
+
+		Expect(localEndpoint.BackendConfig).To(HaveKeyWithValue(subv1.CableDriversConfig, "libreswan,wireguard"))
+		Expect(localEndpoint.BackendConfig).To(HaveKeyWithValue(subv1.ClusterScopedConfig(subv1.CableDriverConfig, "west"),
+			"wireguard"))
+	})
+})
@@ -0,0 +1,228 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geneve
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// Attribute types from the kernel's include/uapi/linux/lwtunnel.h which aren't defined by the netlink library.
+const (
+	lwtunnelIPID   = 1
+	lwtunnelIPDst  = 2
+	lwtunnelIPTTL  = 4
+	lwtunnelIPOpts = 8
+
+	lwtunnelIPOptsGeneve = 1
+
+	lwtunnelIPOptGeneveClass = 1
+	lwtunnelIPOptGeneveType  = 2
+	lwtunnelIPOptGeneveData  = 3
+)
+
+const (
+	// ClusterIDOptionClass is the GENEVE option class used for the source cluster ID TLV. It's in the range reserved
+	// for experimental use by RFC 8926.
+	ClusterIDOptionClass = 0xFFF0
+	// ClusterIDOptionType is the GENEVE option type of the source cluster ID TLV. The critical bit is not set so
+	// receivers that don't understand the option ignore it.
+	ClusterIDOptionType = 0x01
+
+	// GENEVE option data must be a multiple of 4 bytes and at most 124 bytes.
+	maxOptionDataLen = 124
+	optionHeaderLen  = 4
+)
+
+// Option is a GENEVE TLV option.
+type Option struct {
+	Class uint16
+	Type  uint8
+	Data  []byte
+}
+
+// NewClusterIDOption returns the option carrying the given source cluster ID. The ID is NUL padded to a multiple of
+// 4 bytes as required by the GENEVE option format.
+func NewClusterIDOption(clusterID string) (*Option, error) {
+	data := []byte(clusterID)
+
+	if len(data) > maxOptionDataLen {
+		return nil, errors.Errorf("the cluster ID %q exceeds the maximum GENEVE option length of %d", clusterID, maxOptionDataLen)
+	}
+
+	if pad := len(data) % 4; pad != 0 {
+		data = append(data, make([]byte, 4-pad)...)
+	}
+
+	return &Option{Class: ClusterIDOptionClass, Type: ClusterIDOptionType, Data: data}, nil
+}
+
+// ClusterID returns the cluster ID carried by the option or an empty string if it's not a cluster ID option.
+func (o *Option) ClusterID() string {
+	if o.Class != ClusterIDOptionClass || o.Type != ClusterIDOptionType {
+		return ""
+	}
+
+	return string(bytes.TrimRight(o.Data, "\x00"))
+}
+
+func (o *Option) len() int {
+	return optionHeaderLen + len(o.Data)
+}
+
+// Encap is a lightweight tunnel IP encapsulation (as configured via "ip route ... encap ip id <vni> dst <ip>
+// geneve_opts <class:type:data>") used to route traffic through a flow based GENEVE device. The netlink library
+// doesn't support the geneve options so this is implemented here.
+type Encap struct {
+	ID     uint32
+	Dst    net.IP
+	TTL    uint8
+	Option *Option
+}
+
+var _ netlink.Encap = &Encap{}
+
+func (e *Encap) Type() int {
+	return nl.LWTUNNEL_ENCAP_IP
+}
+
+func (e *Encap) Encode() ([]byte, error) {
+	dst := e.Dst.To4()
+	if dst == nil {
+		return nil, errors.Errorf("invalid IPv4 tunnel destination %v", e.Dst)
+	}
+
+	id := make([]byte, 8)
+	binary.BigEndian.PutUint64(id, uint64(e.ID))
+
+	encoded := nl.NewRtAttr(lwtunnelIPID, id).Serialize()
+	encoded = append(encoded, nl.NewRtAttr(lwtunnelIPDst, dst).Serialize()...)
+
+	if e.TTL != 0 {
+		encoded = append(encoded, nl.NewRtAttr(lwtunnelIPTTL, []byte{e.TTL}).Serialize()...)
+	}
+
+	if e.Option != nil {
+		class := make([]byte, 2)
+		binary.BigEndian.PutUint16(class, e.Option.Class)
+
+		opts := nl.NewRtAttr(lwtunnelIPOpts|unix.NLA_F_NESTED, nil)
+		geneve := opts.AddRtAttr(lwtunnelIPOptsGeneve|unix.NLA_F_NESTED, nil)
+		geneve.AddRtAttr(lwtunnelIPOptGeneveClass, class)
+		geneve.AddRtAttr(lwtunnelIPOptGeneveType, []byte{e.Option.Type})
+		geneve.AddRtAttr(lwtunnelIPOptGeneveData, e.Option.Data)
+
+		encoded = append(encoded, opts.Serialize()...)
+	}
+
+	return encoded, nil
+}
+
+func (e *Encap) Decode(buf []byte) error {
+	attrs, err := nl.ParseRouteAttr(buf)
+	if err != nil {
+		return errors.Wrap(err, "error parsing the encap attributes")
+	}
+
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case lwtunnelIPID:
+			if len(attr.Value) != 8 {
+				return errors.Errorf("invalid tunnel ID length %d", len(attr.Value))
+			}
+
+			e.ID = uint32(binary.BigEndian.Uint64(attr.Value)) //nolint:gosec // The VNI is only 24 bits
+		case lwtunnelIPDst:
+			e.Dst = net.IP(attr.Value)
+		case lwtunnelIPTTL:
+			e.TTL = attr.Value[0]
+		case lwtunnelIPOpts:
+			if e.Option, err = decodeOption(attr.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func decodeOption(buf []byte) (*Option, error) {
+	attrs, err := nl.ParseRouteAttr(buf)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing the encap options")
+	}
+
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK != lwtunnelIPOptsGeneve {
+			continue
+		}
+
+		optAttrs, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return nil, errors.Wrap(err, "error parsing the GENEVE option")
+		}
+
+		option := &Option{}
+
+		for _, optAttr := range optAttrs {
+			switch optAttr.Attr.Type {
+			case lwtunnelIPOptGeneveClass:
+				option.Class = binary.BigEndian.Uint16(optAttr.Value)
+			case lwtunnelIPOptGeneveType:
+				option.Type = optAttr.Value[0]
+			case lwtunnelIPOptGeneveData:
+				option.Data = optAttr.Value
+			}
+		}
+
+		return option, nil
+	}
+
+	return nil, nil
+}
+
+func (e *Encap) String() string {
+	s := fmt.Sprintf("ip id %d dst %s ttl %d", e.ID, e.Dst, e.TTL)
+	if e.Option != nil {
+		s += fmt.Sprintf(" geneve_opts %04x:%02x:%x", e.Option.Class, e.Option.Type, e.Option.Data)
+	}
+
+	return s
+}
+
+func (e *Encap) Equal(x netlink.Encap) bool {
+	o, ok := x.(*Encap)
+	if !ok {
+		return false
+	}
+
+	if e.ID != o.ID || !e.Dst.Equal(o.Dst) || e.TTL != o.TTL || (e.Option == nil) != (o.Option == nil) {
+		return false
+	}
+
+	return e.Option == nil || (e.Option.Class == o.Option.Class && e.Option.Type == o.Option.Type &&
+		bytes.Equal(e.Option.Data, o.Option.Data))
+}
@@ -0,0 +1,72 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geneve_test
+
+import (
+	"net"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner/pkg/cable/geneve"
+)
+
+var _ = Describe("Encap", func() {
+	Specify("Encode and Decode should round trip", func() {
+		option, err := geneve.NewClusterIDOption("east")
+		Expect(err).To(Succeed())
+
+		encap := &geneve.Encap{ID: 1000, Dst: net.ParseIP("172.93.2.1").To4(), TTL: 64, Option: option}
+
+		encoded, err := encap.Encode()
+		Expect(err).To(Succeed())
+
+		decoded := &geneve.Encap{}
+		Expect(decoded.Decode(encoded)).To(Succeed())
+		Expect(decoded.Equal(encap)).To(BeTrue(), "Expected %s to equal %s", decoded, encap)
+		Expect(decoded.Option.ClusterID()).To(Equal("east"))
+	})
+
+	Specify("Encode should fail for an IPv6 destination", func() {
+		_, err := (&geneve.Encap{ID: 1, Dst: net.ParseIP("fd00::1")}).Encode()
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("NewClusterIDOption", func() {
+	It("should pad the data to a multiple of 4 bytes", func() {
+		option, err := geneve.NewClusterIDOption("cluster1")
+		Expect(err).To(Succeed())
+		Expect(option.Data).To(HaveLen(8))
+
+		option, err = geneve.NewClusterIDOption("west")
+		Expect(err).To(Succeed())
+		Expect(option.Data).To(HaveLen(4))
+
+		option, err = geneve.NewClusterIDOption("cluster-10")
+		Expect(err).To(Succeed())
+		Expect(option.Data).To(HaveLen(12))
+		Expect(option.ClusterID()).To(Equal("cluster-10"))
+	})
+
+	It("should fail if the cluster ID is too long", func() {
+		_, err := geneve.NewClusterIDOption(strings.Repeat("x", 125))
+		Expect(err).To(HaveOccurred())
+	})
+})
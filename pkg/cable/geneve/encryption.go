@@ -0,0 +1,110 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geneve
+
+import (
+	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cable"
+	"github.com/submariner-io/submariner/pkg/cable/xfrm"
+	submendpoint "github.com/submariner-io/submariner/pkg/endpoint"
+)
+
+const (
+	// EncryptionConfig is the backend config which advertises that the gateway encrypts its GENEVE traffic.
+	EncryptionConfig = "geneve-encryption"
+	// IKEPortConfig is the backend config of the UDP port on which the gateway negotiates the SAs of the encrypted GENEVE
+	// traffic and receives it, encapsulated in ESP.
+	IKEPortConfig = "geneve-ike-port"
+	// DefaultIKEPort is the UDP port used when IKEPortConfig isn't set.
+	DefaultIKEPort = 4570
+
+	// UDP encapsulation, SPI, sequence number, IV, pad length, next header and ICV plus up to 3 bytes of padding.
+	espOverhead = 8 + 4 + 4 + 8 + 2 + 16 + 3
+)
+
+// newEncryption returns the driver which negotiates, with IKEv2, the ESP transport mode SAs encrypting the GENEVE traffic
+// between the gateways. Its IKE messages and ESP packets are exchanged on a separate UDP port so the SAs don't select them.
+func (g *geneve) newEncryption(localEndpoint *submendpoint.Local) (cable.Driver, error) {
+	ipsecSpec := ipsecSpecification{}
+	if err := envconfig.Process(cable.IPSecEnvPrefix, &ipsecSpec); err != nil {
+		return nil, errors.Wrapf(err, "error processing environment config for %s", cable.IPSecEnvPrefix)
+	}
+
+	if ipsecSpec.PSK == "" {
+		return nil, errors.New("GENEVE encryption requires a pre-shared key")
+	}
+
+	ikePort, err := g.localEndpoint.GetBackendPort(IKEPortConfig, DefaultIKEPort)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the IKE UDP port configuration")
+	}
+
+	if int(ikePort) == g.port {
+		return nil, errors.Errorf("the IKE UDP port %d must differ from the GENEVE UDP port", ikePort)
+	}
+
+	return xfrm.NewTransportModeDriver(localEndpoint, &xfrm.TransportModeConfig{
+		DriverName: CableDriverName,
+		PSK:        []byte(ipsecSpec.PSK),
+		Port:       int(ikePort),
+		RemotePort: func(remote *v1.EndpointSpec) (int32, error) {
+			return remote.GetBackendPort(IKEPortConfig, DefaultIKEPort)
+		},
+		TrafficPort: g.port,
+		// The GENEVE packets are carried in ESP so the remote endpoint's NAT doesn't map their port.
+		RemoteTrafficPort: func(remote *v1.EndpointSpec) (int32, error) {
+			return remote.GetCableDriverPort(CableDriverName, DefaultPort)
+		},
+	}), nil
+}
+
+// checkEncryption verifies that the remote endpoint encrypts its GENEVE traffic too, as each side drops the plaintext traffic
+// of the other otherwise.
+func (g *geneve) checkEncryption(remote *v1.EndpointSpec) error {
+	if remote.BackendConfig[EncryptionConfig] != "true" {
+		return errors.New("the remote endpoint does not have GENEVE encryption enabled")
+	}
+
+	return nil
+}
+
+// encryptedStatus returns the given connections with the status of their encryption. The GENEVE traffic is dropped until
+// the SAs are negotiated, so connections are only connected once they are.
+func (g *geneve) encryptedStatus(connections []v1.Connection) ([]v1.Connection, error) {
+	encrypted, err := g.encryption.GetConnections()
+	if err != nil {
+		return nil, errors.Wrap(err, "error retrieving the encryption connections")
+	}
+
+	for i := range connections {
+		connections[i].Status = v1.Connecting
+		connections[i].StatusMessage = ""
+
+		for j := range encrypted {
+			if encrypted[j].Endpoint.CableName == connections[i].Endpoint.CableName {
+				connections[i].Status = encrypted[j].Status
+				connections[i].StatusMessage = encrypted[j].StatusMessage
+			}
+		}
+	}
+
+	return connections, nil
+}
@@ -0,0 +1,207 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geneve
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+
+	"github.com/pkg/errors"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// ClusterIDOptionConfig is the backend config which advertises that the gateway adds the cluster ID option to its GENEVE
+// traffic. The remote gateways then drop the GENEVE traffic from the gateway which doesn't carry its cluster ID.
+const ClusterIDOptionConfig = "geneve-cluster-id-option"
+
+// Attribute types from the kernel's include/uapi/linux/pkt_cls.h which aren't defined by the netlink library.
+const (
+	tcaFlowerKeyEncOptsGeneve = 1
+
+	tcaFlowerKeyEncOptGeneveClass = 1
+	tcaFlowerKeyEncOptGeneveType  = 2
+	tcaFlowerKeyEncOptGeneveData  = 3
+)
+
+// The priorities of the ingress filters validating the cluster ID option - the traffic from a remote gateway carrying its
+// cluster ID is accepted by the first, the rest of its traffic is dropped by the second.
+const (
+	optionFilterPriority = 1
+	dropFilterPriority   = 2
+)
+
+// OptionFilter is a flower filter (as configured via "tc filter add dev <dev> ingress flower enc_src_ip <ip> geneve_opts
+// <class:type:data>") matching the GENEVE traffic from a remote gateway which carries the given option. The netlink
+// library doesn't support the geneve options so this is implemented here.
+type OptionFilter struct {
+	netlink.FilterAttrs
+	EncSrcIP net.IP
+	Option   *Option
+	Actions  []netlink.Action
+}
+
+var _ netlinkAPI.EncodedFilter = &OptionFilter{}
+
+func (f *OptionFilter) Attrs() *netlink.FilterAttrs {
+	return &f.FilterAttrs
+}
+
+func (f *OptionFilter) Type() string {
+	return "flower"
+}
+
+func (f *OptionFilter) EncodeOptions(options *nl.RtAttr) error {
+	src := f.EncSrcIP.To4()
+	if src == nil {
+		return errors.Errorf("invalid IPv4 tunnel source %v", f.EncSrcIP)
+	}
+
+	options.AddRtAttr(nl.TCA_FLOWER_KEY_ENC_IPV4_SRC, src)
+	options.AddRtAttr(nl.TCA_FLOWER_KEY_ENC_IPV4_SRC_MASK, []byte(net.CIDRMask(32, 32)))
+
+	// The option must match exactly, so the mask has all its bits set.
+	encodeEncOpts(options, nl.TCA_FLOWER_KEY_ENC_OPTS, f.Option)
+	encodeEncOpts(options, nl.TCA_FLOWER_KEY_ENC_OPTS_MASK, &Option{
+		Class: 0xffff, Type: 0xff, Data: bytes.Repeat([]byte{0xff}, len(f.Option.Data)),
+	})
+
+	actions := options.AddRtAttr(nl.TCA_FLOWER_ACT, nil)
+
+	return errors.Wrap(netlink.EncodeActions(actions, f.Actions), "error encoding the filter actions")
+}
+
+func encodeEncOpts(options *nl.RtAttr, attrType int, option *Option) {
+	class := make([]byte, 2)
+	binary.BigEndian.PutUint16(class, option.Class)
+
+	opts := options.AddRtAttr(attrType|unix.NLA_F_NESTED, nil)
+	geneve := opts.AddRtAttr(tcaFlowerKeyEncOptsGeneve|unix.NLA_F_NESTED, nil)
+	geneve.AddRtAttr(tcaFlowerKeyEncOptGeneveClass, class)
+	geneve.AddRtAttr(tcaFlowerKeyEncOptGeneveType, []byte{option.Type})
+	geneve.AddRtAttr(tcaFlowerKeyEncOptGeneveData, option.Data)
+}
+
+// ingressFilters returns the filters which only accept the GENEVE traffic from the remote gateway at the given IP if it
+// carries the option with the remote cluster's ID. The handle distinguishes the filters of each remote gateway.
+func ingressFilters(linkIndex int, handle uint32, remoteIP net.IP, option *Option) []netlink.Filter {
+	attrs := func(priority uint16) netlink.FilterAttrs {
+		return netlink.FilterAttrs{
+			LinkIndex: linkIndex,
+			Parent:    netlink.HANDLE_MIN_INGRESS,
+			Priority:  priority,
+			Handle:    handle,
+			Protocol:  unix.ETH_P_ALL,
+		}
+	}
+
+	return []netlink.Filter{
+		&OptionFilter{
+			FilterAttrs: attrs(optionFilterPriority),
+			EncSrcIP:    remoteIP,
+			Option:      option,
+			Actions:     []netlink.Action{newGenericAction(netlink.TC_ACT_OK)},
+		},
+		&netlink.Flower{
+			FilterAttrs: attrs(dropFilterPriority),
+			EncSrcIP:    remoteIP,
+			Actions:     []netlink.Action{newGenericAction(netlink.TC_ACT_SHOT)},
+		},
+	}
+}
+
+func newGenericAction(action netlink.TcAct) netlink.Action {
+	return &netlink.GenericAction{ActionAttrs: netlink.ActionAttrs{Action: action}}
+}
+
+func (g *geneve) ensureIngressQdisc() error {
+	err := g.netLink.QdiscReplace(&netlink.Clsact{QdiscAttrs: netlink.QdiscAttrs{
+		LinkIndex: g.link.Attrs().Index,
+		Handle:    netlink.MakeHandle(0xffff, 0),
+		Parent:    netlink.HANDLE_CLSACT,
+	}})
+
+	return errors.Wrapf(err, "error adding the clsact qdisc to %q", GeneveIface)
+}
+
+// validateOption installs the ingress filters which drop the GENEVE traffic from the remote gateway, received from the
+// given IP, unless it carries the remote cluster's ID option. This is only done if the remote gateway advertises that it
+// adds the option. The filters' statistics ("tc -s filter show dev geneve-tunnel ingress") count the traffic accepted
+// from, and dropped for, each remote gateway.
+func (g *geneve) validateOption(state *cableState, remote *v1.EndpointSpec, remoteIP net.IP) error {
+	if remote.BackendConfig[ClusterIDOptionConfig] != "true" {
+		g.removeOptionValidation(state)
+		return nil
+	}
+
+	option, err := NewClusterIDOption(remote.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	if err := g.ensureIngressQdisc(); err != nil {
+		return err
+	}
+
+	if state.filterHandle == 0 {
+		state.filterHandle = g.newFilterHandle()
+	}
+
+	for _, filter := range ingressFilters(g.link.Attrs().Index, state.filterHandle, remoteIP, option) {
+		if err := g.netLink.FilterReplace(filter); err != nil {
+			return errors.Wrapf(err, "error adding the ingress filter validating the cluster ID option of %s", remoteIP)
+		}
+	}
+
+	return nil
+}
+
+func (g *geneve) removeOptionValidation(state *cableState) {
+	if state.filterHandle == 0 {
+		return
+	}
+
+	for _, filter := range ingressFilters(g.link.Attrs().Index, state.filterHandle, nil, nil) {
+		if err := g.netLink.FilterDel(filter); err != nil && !os.IsNotExist(err) {
+			logger.Errorf(err, "Error deleting the ingress filter with handle %d", state.filterHandle)
+		}
+	}
+
+	state.filterHandle = 0
+}
+
+// newFilterHandle returns the lowest filter handle not used by a remote gateway.
+func (g *geneve) newFilterHandle() uint32 {
+	used := map[uint32]bool{}
+	for _, c := range g.cables {
+		used[c.filterHandle] = true
+	}
+
+	handle := uint32(1)
+	for used[handle] {
+		handle++
+	}
+
+	return handle
+}
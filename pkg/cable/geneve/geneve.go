@@ -0,0 +1,633 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geneve
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cable"
+	"github.com/submariner-io/submariner/pkg/cni"
+	submendpoint "github.com/submariner-io/submariner/pkg/endpoint"
+	"github.com/submariner-io/submariner/pkg/natdiscovery"
+	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
+	"github.com/submariner-io/submariner/pkg/types"
+	"github.com/submariner-io/submariner/pkg/vxlan"
+	"github.com/vishvananda/netlink"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	GeneveIface = "geneve-tunnel"
+	// PortIfacePrefix prefixes the name of the GENEVE devices created for the remote gateways which receive the GENEVE
+	// traffic on another port than the local gateway, followed by the port.
+	PortIfacePrefix         = "geneve-p"
+	GeneveVTepNetworkPrefix = 242
+	CableDriverName         = "geneve"
+	TableID                 = 101
+	DefaultPort             = 6081
+	EnvPrefix               = "ce_geneve"
+
+	// Outer IP, UDP, GENEVE and inner Ethernet headers.
+	mtuOverhead = 20 + 8 + 8 + 14
+)
+
+// TunnelMAC is the MAC address of the GENEVE device on every gateway. The device is flow based so the remote gateway is
+// selected per route - using the same MAC everywhere means the inner Ethernet destination always matches the receiver.
+var TunnelMAC = net.HardwareAddr{0x02, 0x5a, 0xb6, 0xe0, 0x0e, 0x01}
+
+type specification struct {
+	VNI             uint32 `default:"1000"`
+	Encryption      bool
+	ClusterIDOption bool `split_words:"true"`
+}
+
+type ipsecSpecification struct {
+	PSK string
+}
+
+// cableState holds the data-plane state of the connection to a remote gateway.
+type cableState struct {
+	// port is the UDP port to which the GENEVE traffic to the remote gateway is sent.
+	port int
+	link netlink.Link
+	// filterHandle is the handle of the ingress filters validating the remote gateway's cluster ID option, if any.
+	filterHandle uint32
+}
+
+type geneve struct {
+	localEndpoint v1.EndpointSpec
+	localCluster  types.SubmarinerCluster
+	connections   []v1.Connection
+	cables        map[string]*cableState
+	mutex         sync.Mutex
+	spec          specification
+	netLink       netlinkAPI.Interface
+	link          netlink.Link
+	// portLinks holds the GENEVE devices by destination port.
+	portLinks  map[int]netlink.Link
+	vtepIP     net.IP
+	port       int
+	option     *Option
+	encryption cable.Driver
+}
+
+var logger = log.Logger{Logger: logf.Log.WithName("geneve")}
+
+func init() {
+	cable.AddDriver(CableDriverName, NewDriver)
+	cable.SetDriverDefaultPort(CableDriverName, DefaultPort)
+}
+
+// NewDriver creates a new GENEVE cable driver.
+func NewDriver(localEndpoint *submendpoint.Local, localCluster *types.SubmarinerCluster) (cable.Driver, error) {
+	// We'll panic if localEndpoint or localCluster are nil, this is intentional
+	g := &geneve{
+		localEndpoint: *localEndpoint.Spec(),
+		localCluster:  *localCluster,
+		cables:        map[string]*cableState{},
+		portLinks:     map[int]netlink.Link{},
+		netLink:       netlinkAPI.New(),
+	}
+
+	if err := envconfig.Process(EnvPrefix, &g.spec); err != nil {
+		return nil, errors.Wrapf(err, "error processing environment config for %s", EnvPrefix)
+	}
+
+	port, err := g.localEndpoint.GetCableDriverPort(CableDriverName, DefaultPort)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the UDP port configuration")
+	}
+
+	g.port = int(port)
+
+	if g.spec.ClusterIDOption {
+		if g.option, err = NewClusterIDOption(g.localEndpoint.ClusterID); err != nil {
+			return nil, err
+		}
+	}
+
+	if g.spec.Encryption {
+		if g.encryption, err = g.newEncryption(localEndpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := g.publishConfig(localEndpoint); err != nil {
+		return nil, err
+	}
+
+	if g.localEndpoint.NATEnabled {
+		logger.Warning("The GENEVE cable driver requires the GENEVE UDP port to be reachable on the gateways' public IPs")
+	}
+
+	if err := g.createGeneveInterface(); err != nil {
+		return nil, errors.Wrap(err, "failed to setup the GENEVE link")
+	}
+
+	return g, nil
+}
+
+// publishConfig advertises, in the local endpoint, whether the local gateway encrypts its GENEVE traffic and adds the
+// cluster ID option to it, so the remote gateways can check that they match.
+func (g *geneve) publishConfig(local *submendpoint.Local) error {
+	configs := map[string]bool{
+		EncryptionConfig:      g.spec.Encryption,
+		ClusterIDOptionConfig: g.spec.ClusterIDOption,
+	}
+
+	err := local.Update(context.TODO(), func(existing *v1.EndpointSpec) {
+		for name, enabled := range configs {
+			if !enabled {
+				delete(existing.BackendConfig, name)
+				continue
+			}
+
+			if existing.BackendConfig == nil {
+				existing.BackendConfig = map[string]string{}
+			}
+
+			existing.BackendConfig[name] = "true"
+		}
+	})
+	if err != nil {
+		return errors.Wrap(err, "error updating local endpoint")
+	}
+
+	g.localEndpoint = *local.Spec()
+
+	return nil
+}
+
+func (g *geneve) mtuOverhead() int {
+	overhead := mtuOverhead
+
+	if g.option != nil {
+		overhead += g.option.len()
+	}
+
+	if g.spec.Encryption {
+		overhead += espOverhead
+	}
+
+	return overhead
+}
+
+func (g *geneve) createGeneveInterface() error {
+	ipAddr := g.localEndpoint.PrivateIP
+
+	var err error
+
+	g.vtepIP, err = vxlan.GetVtepIPAddressFrom(ipAddr, GeneveVTepNetworkPrefix)
+	if err != nil {
+		return errors.Wrapf(err, "failed to derive the GENEVE vtepIP for %s", ipAddr)
+	}
+
+	defaultHostIface, err := netlinkAPI.GetDefaultGatewayInterface()
+	if err != nil {
+		return errors.Wrapf(err, "Unable to find the default interface on host: %s", g.localEndpoint.Hostname)
+	}
+
+	if g.link, err = g.addLink(GeneveIface, g.port, defaultHostIface.MTU-g.mtuOverhead()); err != nil {
+		return err
+	}
+
+	g.portLinks[g.port] = g.link
+
+	err = g.netLink.RuleAddIfNotPresent(netlinkAPI.NewTableRule(TableID))
+	if err != nil && !os.IsExist(err) {
+		return errors.Wrap(err, "failed to add ip rule")
+	}
+
+	err = g.netLink.AddrAddIfNotPresent(g.link, &netlink.Addr{IPNet: &net.IPNet{IP: g.vtepIP, Mask: net.CIDRMask(8, 32)}})
+	if err != nil {
+		return errors.Wrap(err, "failed to configure the GENEVE interface IP address")
+	}
+
+	return nil
+}
+
+// addLink creates a flow based GENEVE device sending to, and receiving on, the given UDP port.
+func (g *geneve) addLink(name string, port, mtu int) (netlink.Link, error) {
+	// The device is flow based (external) as the tunnel destination, VNI and options are set per route.
+	link := &netlink.Geneve{
+		LinkAttrs: netlink.LinkAttrs{
+			Name:         name,
+			MTU:          mtu,
+			Flags:        net.FlagUp,
+			HardwareAddr: TunnelMAC,
+		},
+		Dport:     uint16(port), //nolint:gosec // The port was validated when parsed
+		FlowBased: true,
+	}
+
+	err := g.netLink.LinkAdd(link)
+	if errors.Is(err, syscall.EEXIST) {
+		// Re-create the device as its configuration may have changed.
+		existing, err := g.netLink.LinkByName(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error retrieving link by name %q", name)
+		}
+
+		if err = g.netLink.LinkDel(existing); err != nil {
+			return nil, errors.Wrapf(err, "error deleting existing GENEVE device %q", name)
+		}
+
+		err = g.netLink.LinkAdd(link)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating GENEVE device %q", name)
+	}
+
+	created, err := g.netLink.LinkByName(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving link by name %q", name)
+	}
+
+	err = g.netLink.EnsureLooseModeIsConfigured(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while validating loose mode")
+	}
+
+	err = g.netLink.EnableForwarding(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error enabling forwarding on the %q iface", name)
+	}
+
+	return created, nil
+}
+
+// linkFor returns the GENEVE device sending to the given UDP port. A flow based device only sends to its own port, the
+// lightweight tunnel encapsulation of the routes can't set it, so a device is created for each port the remote gateways
+// receive on, besides the local one.
+func (g *geneve) linkFor(port int) (netlink.Link, error) {
+	if link, ok := g.portLinks[port]; ok {
+		return link, nil
+	}
+
+	link, err := g.addLink(fmt.Sprintf("%s%d", PortIfacePrefix, port), port, g.link.Attrs().MTU)
+	if err != nil {
+		return nil, err
+	}
+
+	g.portLinks[port] = link
+
+	return link, nil
+}
+
+// releaseLink deletes the GENEVE device sending to the given UDP port once no remote gateway uses it.
+func (g *geneve) releaseLink(port int) {
+	if port == g.port {
+		return
+	}
+
+	for _, c := range g.cables {
+		if c.port == port {
+			return
+		}
+	}
+
+	if link, ok := g.portLinks[port]; ok {
+		if err := g.netLink.LinkDel(link); err != nil {
+			logger.Errorf(err, "Error deleting GENEVE device %q", link.Attrs().Name)
+		}
+
+		delete(g.portLinks, port)
+	}
+}
+
+// remotePortFor returns the UDP port to which the GENEVE traffic to the remote gateway is sent, ie its advertised port as
+// mapped by its NAT. The port isn't mapped when the traffic is encrypted, as it's then carried in ESP.
+func (g *geneve) remotePortFor(endpointInfo *natdiscovery.NATEndpointInfo) (int, error) {
+	port, err := endpointInfo.Endpoint.Spec.GetCableDriverPort(CableDriverName, DefaultPort)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get the remote UDP port configuration")
+	}
+
+	if g.encryption == nil {
+		port = endpointInfo.RemotePort(port)
+	}
+
+	return int(port), nil
+}
+
+func (g *geneve) ConnectToEndpoint(endpointInfo *natdiscovery.NATEndpointInfo) (string, error) {
+	// We'll panic if endpointInfo is nil, this is intentional
+	remoteEndpoint := endpointInfo.Endpoint
+	if g.localEndpoint.ClusterID == remoteEndpoint.Spec.ClusterID {
+		logger.V(log.DEBUG).Infof("Will not connect to self")
+		return "", nil
+	}
+
+	remoteIP := net.ParseIP(endpointInfo.UseIP)
+	if remoteIP == nil || remoteIP.To4() == nil {
+		return "", fmt.Errorf("failed to parse remote IPv4 address %s", endpointInfo.UseIP)
+	}
+
+	remoteVtepIP, err := vxlan.GetVtepIPAddressFrom(remoteEndpoint.Spec.PrivateIP, GeneveVTepNetworkPrefix)
+	if err != nil {
+		return endpointInfo.UseIP, errors.Wrapf(err, "failed to derive the GENEVE vtepIP for %s", remoteEndpoint.Spec.PrivateIP)
+	}
+
+	remotePort, err := g.remotePortFor(endpointInfo)
+	if err != nil {
+		return endpointInfo.UseIP, err
+	}
+
+	logger.V(log.DEBUG).Infof("Connecting cluster %s endpoint %s:%d", remoteEndpoint.Spec.ClusterID, remoteIP, remotePort)
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.encryption != nil {
+		if err := g.checkEncryption(&remoteEndpoint.Spec); err != nil {
+			return endpointInfo.UseIP, err
+		}
+
+		if _, err := g.encryption.ConnectToEndpoint(endpointInfo); err != nil {
+			return endpointInfo.UseIP, errors.Wrapf(err, "error encrypting the tunnel to %s", remoteIP)
+		}
+	}
+
+	state := &cableState{port: remotePort}
+	if state.link, err = g.linkFor(remotePort); err != nil {
+		return endpointInfo.UseIP, err
+	}
+
+	prev, reconnecting := g.cables[remoteEndpoint.Spec.CableName]
+	if reconnecting {
+		state.filterHandle = prev.filterHandle
+	}
+
+	g.cables[remoteEndpoint.Spec.CableName] = state
+
+	if reconnecting {
+		g.releaseLink(prev.port)
+	}
+
+	err = g.netLink.NeighAppend(neighborFor(state.link, remoteVtepIP))
+	if err != nil {
+		return endpointInfo.UseIP, errors.Wrapf(err, "failed to add the neighbor entry for %s", remoteVtepIP)
+	}
+
+	var srcIP net.IP
+
+	cniIface, err := cni.Discover(g.localCluster.Spec.ClusterCIDR)
+	if err == nil {
+		srcIP = net.ParseIP(cniIface.IPAddress)
+	} else {
+		logger.Errorf(nil, "Failed to get the CNI interface IP for cluster CIDR %q, host-networking use-cases may not work",
+			g.localCluster.Spec.ClusterCIDR)
+	}
+
+	encap := &Encap{ID: g.spec.VNI, Dst: remoteIP, Option: g.option}
+
+	for _, dst := range parseSubnets(remoteEndpoint.Spec.Subnets) {
+		err = g.netLink.RouteAddOrReplace(&netlink.Route{
+			LinkIndex: state.link.Attrs().Index,
+			Dst:       dst,
+			Gw:        remoteVtepIP,
+			Src:       srcIP,
+			Encap:     encap,
+			Type:      netlink.NDA_DST,
+			Flags:     int(netlink.FLAG_ONLINK),
+			Table:     TableID,
+		})
+		if err != nil {
+			return endpointInfo.UseIP, errors.Wrapf(err, "failed to add the route for %s", dst)
+		}
+	}
+
+	if err := g.validateOption(state, &remoteEndpoint.Spec, remoteIP); err != nil {
+		return endpointInfo.UseIP, err
+	}
+
+	g.connections = append(g.connections, v1.Connection{
+		Endpoint: remoteEndpoint.Spec, Status: v1.Connected,
+		UsingIP: endpointInfo.UseIP, UsingNAT: endpointInfo.UseNAT,
+	})
+
+	// The encryption reports the status of the encrypted connections.
+	if g.encryption == nil {
+		cable.RecordConnection(CableDriverName, &g.localEndpoint, &remoteEndpoint.Spec, string(v1.Connected), true)
+	}
+
+	logger.V(log.DEBUG).Infof("Done adding endpoint for cluster %s", remoteEndpoint.Spec.ClusterID)
+
+	return endpointInfo.UseIP, nil
+}
+
+func neighborFor(link netlink.Link, vtepIP net.IP) *netlink.Neigh {
+	return &netlink.Neigh{
+		LinkIndex:    link.Attrs().Index,
+		Family:       netlink.FAMILY_V4,
+		State:        netlink.NUD_PERMANENT,
+		IP:           vtepIP,
+		HardwareAddr: TunnelMAC,
+	}
+}
+
+func (g *geneve) DisconnectFromEndpoint(remoteEndpoint *types.SubmarinerEndpoint) error {
+	// We'll panic if remoteEndpoint is nil, this is intentional
+	logger.V(log.DEBUG).Infof("Removing endpoint %#v", remoteEndpoint)
+
+	if g.localEndpoint.ClusterID == remoteEndpoint.Spec.ClusterID {
+		logger.V(log.DEBUG).Infof("Will not disconnect self")
+		return nil
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	var connection *v1.Connection
+
+	for i := range g.connections {
+		if g.connections[i].Endpoint.CableName == remoteEndpoint.Spec.CableName {
+			connection = &g.connections[i]
+		}
+	}
+
+	state := g.cables[remoteEndpoint.Spec.CableName]
+	if connection == nil || state == nil {
+		logger.Errorf(nil, "Cannot disconnect remote endpoint %q - no prior connection entry found", remoteEndpoint.Spec.CableName)
+		return nil
+	}
+
+	g.removeOptionValidation(state)
+
+	for _, dst := range parseSubnets(remoteEndpoint.Spec.Subnets) {
+		err := g.netLink.RouteDel(&netlink.Route{LinkIndex: state.link.Attrs().Index, Dst: dst, Table: TableID})
+		if err != nil && !os.IsNotExist(err) && !errors.Is(err, syscall.ESRCH) {
+			return errors.Wrapf(err, "failed to remove the route for %s", dst)
+		}
+	}
+
+	remoteVtepIP, err := vxlan.GetVtepIPAddressFrom(connection.Endpoint.PrivateIP, GeneveVTepNetworkPrefix)
+	if err == nil {
+		err = g.netLink.NeighDel(neighborFor(state.link, remoteVtepIP))
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to delete the neighbor entry for %s", remoteVtepIP)
+		}
+	}
+
+	if g.encryption != nil {
+		if err := g.encryption.DisconnectFromEndpoint(remoteEndpoint); err != nil {
+			return errors.Wrapf(err, "error removing the encryption of the tunnel to %s", connection.UsingIP)
+		}
+	}
+
+	delete(g.cables, remoteEndpoint.Spec.CableName)
+	g.releaseLink(state.port)
+
+	g.connections = removeConnectionForEndpoint(g.connections, remoteEndpoint)
+
+	if g.encryption == nil {
+		cable.RecordDisconnected(CableDriverName, &g.localEndpoint, &remoteEndpoint.Spec)
+	}
+
+	logger.V(log.DEBUG).Infof("Done removing endpoint for cluster %s", remoteEndpoint.Spec.ClusterID)
+
+	return nil
+}
+
+func removeConnectionForEndpoint(connections []v1.Connection, endpoint *types.SubmarinerEndpoint) []v1.Connection {
+	for j := range connections {
+		if connections[j].Endpoint.CableName == endpoint.Spec.CableName {
+			copy(connections[j:], connections[j+1:])
+			return connections[:len(connections)-1]
+		}
+	}
+
+	return connections
+}
+
+func (g *geneve) GetConnections() ([]v1.Connection, error) {
+	g.mutex.Lock()
+	connections := append([]v1.Connection{}, g.connections...)
+	g.mutex.Unlock()
+
+	if g.encryption != nil {
+		return g.encryptedStatus(connections)
+	}
+
+	return connections, nil
+}
+
+func (g *geneve) GetActiveConnections() ([]v1.Connection, error) {
+	connections, err := g.GetConnections()
+
+	active := connections[:0]
+
+	for i := range connections {
+		if connections[i].Status == v1.Connected {
+			active = append(active, connections[i])
+		}
+	}
+
+	return active, err
+}
+
+func (g *geneve) Init() error {
+	if g.encryption != nil {
+		return errors.Wrap(g.encryption.Init(), "error initializing the GENEVE encryption")
+	}
+
+	return nil
+}
+
+func (g *geneve) GetName() string {
+	return CableDriverName
+}
+
+// Parse CIDR string and skip errors. IPv6 subnets are skipped as the GENEVE cable driver only routes IPv4.
+func parseSubnets(subnets []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(subnets))
+
+	for _, sn := range subnets {
+		_, cidr, err := net.ParseCIDR(sn)
+		if err != nil {
+			// this should not happen. Log and continue
+			logger.Errorf(err, "Failed to parse subnet %s", sn)
+			continue
+		}
+
+		if cidr.IP.To4() == nil {
+			logger.Warningf("Skipping IPv6 subnet %s - IPv6 is not supported by the %s cable driver", sn, CableDriverName)
+			continue
+		}
+
+		nets = append(nets, cidr)
+	}
+
+	return nets
+}
+
+func (g *geneve) Cleanup() error {
+	logger.Infof("Uninstalling the GENEVE cable driver")
+
+	for _, name := range g.linkNames() {
+		err := netlinkAPI.DeleteIfaceAndAssociatedRoutes(name, TableID)
+		if err != nil {
+			logger.Errorf(nil, "Unable to delete interface %s and associated routes from table %d", name, TableID)
+		}
+	}
+
+	if g.encryption != nil {
+		if err := g.encryption.Cleanup(); err != nil {
+			logger.Errorf(err, "Unable to remove the GENEVE XFRM policies and states")
+		}
+	}
+
+	err := g.netLink.RuleDelIfPresent(netlinkAPI.NewTableRule(TableID))
+	if err != nil {
+		return errors.Wrapf(err, "unable to delete IP rule pointing to %d table", TableID)
+	}
+
+	return nil
+}
+
+// linkNames returns the names of the GENEVE devices, including the devices created for other ports by previous instances
+// of the driver.
+func (g *geneve) linkNames() []string {
+	names := []string{GeneveIface}
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		logger.Errorf(err, "Unable to list the network interfaces")
+		return names
+	}
+
+	for i := range ifaces {
+		if strings.HasPrefix(ifaces[i].Name, PortIfacePrefix) {
+			names = append(names, ifaces[i].Name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,545 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package geneve_test
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/log/kzerolog"
+	subv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cable"
+	"github.com/submariner-io/submariner/pkg/cable/geneve"
+	"github.com/submariner-io/submariner/pkg/cni"
+	"github.com/submariner-io/submariner/pkg/endpoint"
+	"github.com/submariner-io/submariner/pkg/ikev2"
+	"github.com/submariner-io/submariner/pkg/natdiscovery"
+	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
+	fakeNetlink "github.com/submariner-io/submariner/pkg/netlink/fake"
+	"github.com/submariner-io/submariner/pkg/types"
+	"github.com/vishvananda/netlink"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func init() {
+	kzerolog.AddFlags(nil)
+}
+
+var _ = BeforeSuite(func() {
+	flags := flag.NewFlagSet("kzerolog", flag.ExitOnError)
+	kzerolog.AddFlags(flags)
+	_ = flags.Parse([]string{"-v=4"})
+
+	kzerolog.InitK8sLogging()
+})
+
+func TestGeneve(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Geneve Cable Driver Suite")
+}
+
+const (
+	cniIPAddress = "192.168.5.1"
+	loopback     = "127.0.0.1"
+)
+
+var _ = Describe("Geneve", func() {
+	t := newTestDriver()
+
+	var natInfo *natdiscovery.NATEndpointInfo
+
+	BeforeEach(func() {
+		natInfo = &natdiscovery.NATEndpointInfo{
+			Endpoint: subv1.Endpoint{
+				Spec: subv1.EndpointSpec{
+					ClusterID: "east",
+					CableName: "submariner-cable-east-192-68-2-1",
+					PrivateIP: "192.68.2.1",
+					Subnets:   []string{"20.0.0.0/16", "21.0.0.0/16"},
+				},
+			},
+			UseIP:  "172.93.2.1",
+			UseNAT: true,
+		}
+	})
+
+	JustBeforeEach(func() {
+		link := t.netLink.AwaitLink(geneve.GeneveIface)
+		geneveLink, ok := link.(*netlink.Geneve)
+		Expect(ok).To(BeTrue(), "Unexpected Link type: %T", link)
+
+		Expect(geneveLink.Dport).To(Equal(uint16(geneve.DefaultPort)))
+		Expect(geneveLink.FlowBased).To(BeTrue())
+		Expect(geneveLink.HardwareAddr).To(Equal(geneve.TunnelMAC))
+
+		t.netLink.AwaitRule(geneve.TableID, "", "")
+	})
+
+	Context("with the default configuration", func() {
+		Specify("ConnectToEndpoint should create a Connection and add expected data-plane components", func() {
+			ip, err := t.driver.ConnectToEndpoint(natInfo)
+			Expect(err).To(Succeed())
+			Expect(ip).To(Equal(natInfo.UseIP))
+
+			t.assertConnection(natInfo)
+
+			remoteVtep := fmt.Sprintf("%d.68.2.1", geneve.GeneveVTepNetworkPrefix)
+			t.netLink.AwaitNeighbors(0, remoteVtep)
+
+			routes := t.routes()
+			Expect(routes).To(HaveLen(len(natInfo.Endpoint.Spec.Subnets)))
+
+			for i := range routes {
+				Expect(routes[i].Gw.String()).To(Equal(remoteVtep))
+				Expect(routes[i].Src.String()).To(Equal(cniIPAddress))
+				Expect(routes[i].Table).To(Equal(geneve.TableID))
+				Expect(routes[i].Encap).To(Equal(&geneve.Encap{ID: 1000, Dst: net.ParseIP(natInfo.UseIP)}))
+			}
+
+			t.netLink.AwaitNoXfrmStates()
+		})
+
+		Specify("DisconnectFromEndpoint should remove the Connection and its data-plane components", func() {
+			_, err := t.driver.ConnectToEndpoint(natInfo)
+			Expect(err).To(Succeed())
+
+			Expect(t.driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: natInfo.Endpoint.Spec})).To(Succeed())
+			t.assertNoConnection()
+			t.netLink.AwaitNoNeighbors(0, fmt.Sprintf("%d.68.2.1", geneve.GeneveVTepNetworkPrefix))
+			Expect(t.routes()).To(BeEmpty())
+		})
+
+		Specify("Cleanup should remove the GENEVE link device", func() {
+			Expect(t.driver.Cleanup()).To(Succeed())
+			t.netLink.AwaitNoLink(geneve.GeneveIface)
+			t.netLink.AwaitNoRule(geneve.TableID, "", "")
+		})
+	})
+
+	When("the cluster ID option is enabled", func() {
+		BeforeEach(func() {
+			setEnv("CE_GENEVE_CLUSTER_ID_OPTION", "true")
+		})
+
+		It("should publish it in the local endpoint", func() {
+			Expect(t.local.Spec().BackendConfig).To(HaveKeyWithValue(geneve.ClusterIDOptionConfig, "true"))
+		})
+
+		It("should add the option to the route encapsulation", func() {
+			_, err := t.driver.ConnectToEndpoint(natInfo)
+			Expect(err).To(Succeed())
+
+			routes := t.routes()
+			Expect(routes).ToNot(BeEmpty())
+
+			encap, ok := routes[0].Encap.(*geneve.Encap)
+			Expect(ok).To(BeTrue())
+			Expect(encap.Option).ToNot(BeNil())
+			Expect(encap.Option.ClusterID()).To(Equal(t.localEndpoint.ClusterID))
+		})
+	})
+
+	When("the remote gateway advertises another GENEVE port", func() {
+		BeforeEach(func() {
+			natInfo.Endpoint.Spec.BackendConfig = map[string]string{subv1.CableDriverUDPPortConfig(geneve.CableDriverName): "7000"}
+			t.netLink.SetLinkIndex(geneve.PortIfacePrefix+"7000", 7)
+		})
+
+		It("should route the traffic through a GENEVE device sending to that port", func() {
+			_, err := t.driver.ConnectToEndpoint(natInfo)
+			Expect(err).To(Succeed())
+
+			link := t.netLink.AwaitLink(geneve.PortIfacePrefix + "7000")
+			Expect(link.(*netlink.Geneve).Dport).To(Equal(uint16(7000)))
+			Expect(link.(*netlink.Geneve).FlowBased).To(BeTrue())
+
+			t.netLink.AwaitDstRoutes(7, geneve.TableID, natInfo.Endpoint.Spec.Subnets...)
+			t.netLink.AwaitNeighbors(7, fmt.Sprintf("%d.68.2.1", geneve.GeneveVTepNetworkPrefix))
+			Expect(t.routes()).To(BeEmpty())
+
+			Expect(t.driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: natInfo.Endpoint.Spec})).To(Succeed())
+			t.netLink.AwaitNoLink(geneve.PortIfacePrefix + "7000")
+			t.netLink.AwaitLink(geneve.GeneveIface)
+		})
+	})
+
+	When("the remote gateway's NAT maps its ports", func() {
+		BeforeEach(func() {
+			natInfo.Endpoint.Spec.BackendConfig = map[string]string{subv1.NATTDiscoveryPortConfig: "4490"}
+			natInfo.UsePort = 4491
+			t.netLink.SetLinkIndex(geneve.PortIfacePrefix+"6082", 8)
+		})
+
+		It("should send the traffic to the mapped GENEVE port", func() {
+			_, err := t.driver.ConnectToEndpoint(natInfo)
+			Expect(err).To(Succeed())
+
+			link := t.netLink.AwaitLink(geneve.PortIfacePrefix + "6082")
+			Expect(link.(*netlink.Geneve).Dport).To(Equal(uint16(6082)))
+
+			t.netLink.AwaitDstRoutes(8, geneve.TableID, natInfo.Endpoint.Spec.Subnets...)
+		})
+	})
+
+	When("the remote gateway advertises that it adds the cluster ID option", func() {
+		BeforeEach(func() {
+			natInfo.Endpoint.Spec.BackendConfig = map[string]string{geneve.ClusterIDOptionConfig: "true"}
+		})
+
+		It("should only accept its traffic which carries its cluster ID", func() {
+			_, err := t.driver.ConnectToEndpoint(natInfo)
+			Expect(err).To(Succeed())
+
+			t.netLink.AwaitQdisc(0, "clsact")
+
+			filters := t.netLink.AwaitFilters(0, 2)
+			Expect(filters).To(ContainElement(SatisfyAll(
+				BeAssignableToTypeOf(&geneve.OptionFilter{}),
+				HaveField("Parent", uint32(netlink.HANDLE_MIN_INGRESS)),
+				HaveField("EncSrcIP", Equal(net.ParseIP(natInfo.UseIP))),
+				HaveField("Option.ClusterID()", natInfo.Endpoint.Spec.ClusterID),
+				HaveField("Actions", HaveExactElements(HaveField("Action", netlink.TC_ACT_OK))))))
+			Expect(filters).To(ContainElement(SatisfyAll(
+				BeAssignableToTypeOf(&netlink.Flower{}),
+				HaveField("EncSrcIP", Equal(net.ParseIP(natInfo.UseIP))),
+				HaveField("Actions", HaveExactElements(HaveField("Action", netlink.TC_ACT_SHOT))))))
+
+			Expect(t.driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: natInfo.Endpoint.Spec})).To(Succeed())
+			t.netLink.AwaitFilters(0, 0)
+		})
+	})
+
+	When("the remote gateway doesn't advertise that it adds the cluster ID option", func() {
+		It("should not validate its traffic", func() {
+			_, err := t.driver.ConnectToEndpoint(natInfo)
+			Expect(err).To(Succeed())
+
+			t.netLink.AwaitFilters(0, 0)
+		})
+	})
+
+	When("encryption is enabled", func() {
+		var remote *testDriver
+
+		BeforeEach(func() {
+			setEnv("CE_GENEVE_ENCRYPTION", "true")
+			setEnv("CE_IPSEC_PSK", "secret")
+
+			t.localEndpoint.BackendConfig = map[string]string{geneve.IKEPortConfig: strconv.Itoa(freeUDPPort())}
+			remote = nil
+		})
+
+		JustBeforeEach(func() {
+			remote = t.newRemoteDriver(&natInfo.Endpoint.Spec)
+
+			natInfo.Endpoint.Spec = *remote.local.Spec()
+			natInfo.UseIP = loopback
+			natInfo.UseNAT = false
+		})
+
+		It("should publish it in the local endpoint", func() {
+			Expect(t.local.Spec().BackendConfig).To(HaveKeyWithValue(geneve.EncryptionConfig, "true"))
+		})
+
+		It("should negotiate the SAs with the remote gateway and install transport mode XFRM states and policies", func() {
+			_, err := t.driver.ConnectToEndpoint(natInfo)
+			Expect(err).To(Succeed())
+
+			remote.connectTo(t)
+
+			t.awaitConnectionStatus(subv1.Connected)
+			remote.awaitConnectionStatus(subv1.Connected)
+
+			out := t.netLink.AwaitXfrmState(t.localEndpoint.PrivateIP, loopback)
+			in := t.netLink.AwaitXfrmState(loopback, t.localEndpoint.PrivateIP)
+
+			for _, state := range []*netlink.XfrmState{out, in} {
+				Expect(state.Mode).To(Equal(netlink.XFRM_MODE_TRANSPORT))
+				Expect(state.Aead.Key).To(HaveLen(ikev2.KeyLength))
+				Expect(state.Encap.Type).To(Equal(netlink.XFRM_ENCAP_ESPINUDP))
+			}
+
+			Expect(out.Spi).ToNot(Equal(in.Spi))
+
+			// The policies select the GENEVE packets between the gateways whatever the inner addresses, so the traffic
+			// forwarded to and from transit clusters is encrypted as well.
+			remoteNet := &net.IPNet{IP: net.ParseIP(loopback).To4(), Mask: net.CIDRMask(32, 32)}
+
+			policies := t.netLink.AwaitXfrmPolicies(2)
+			Expect(policies).To(ConsistOf(
+				SatisfyAll(HaveField("Dir", netlink.XFRM_DIR_OUT), HaveField("Dst", Equal(remoteNet))),
+				SatisfyAll(HaveField("Dir", netlink.XFRM_DIR_IN), HaveField("Src", Equal(remoteNet)))))
+
+			for i := range policies {
+				Expect(policies[i].DstPort).To(Equal(geneve.DefaultPort))
+				Expect(policies[i].Proto).To(Equal(netlink.Proto(syscall.IPPROTO_UDP)))
+				Expect(policies[i].Tmpls[0].Mode).To(Equal(netlink.XFRM_MODE_TRANSPORT))
+			}
+
+			Expect(t.routes()).To(HaveLen(len(natInfo.Endpoint.Spec.Subnets)))
+
+			Expect(t.driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: natInfo.Endpoint.Spec})).To(Succeed())
+			t.netLink.AwaitNoXfrmStates()
+			t.netLink.AwaitXfrmPolicies(0)
+		})
+
+		Context("and transit routing is enabled", func() {
+			BeforeEach(func() {
+				t.localEndpoint.TransitRoutes = []subv1.TransitRoute{
+					{ClusterID: "north", Subnets: []string{"40.0.0.0/16"}, Path: []string{"local"}},
+				}
+			})
+
+			It("should carry the transit traffic in the encrypted GENEVE flow", func() {
+				// The subnets of the clusters reachable through the remote gateway are added to its Endpoint.
+				natInfo.Endpoint.Spec.Subnets = append(natInfo.Endpoint.Spec.Subnets, "30.0.0.0/16")
+
+				_, err := t.driver.ConnectToEndpoint(natInfo)
+				Expect(err).To(Succeed())
+
+				remote.connectTo(t)
+				t.awaitConnectionStatus(subv1.Connected)
+
+				Expect(t.routes()).To(ContainElement(SatisfyAll(
+					HaveField("Dst.String()", "30.0.0.0/16"),
+					HaveField("Encap", Equal(&geneve.Encap{ID: 1000, Dst: net.ParseIP(loopback)})))))
+
+				// The traffic selectors are the gateways' addresses rather than the subnets, so the transit subnets don't
+				// need to be negotiated.
+				policies := t.netLink.AwaitXfrmPolicies(2)
+				for i := range policies {
+					Expect(policies[i].Src.IP.String()).To(BeElementOf(t.localEndpoint.PrivateIP, loopback))
+					Expect(policies[i].Dst.IP.String()).To(BeElementOf(t.localEndpoint.PrivateIP, loopback))
+				}
+			})
+		})
+
+		Context("and only the local gateway connects", func() {
+			It("should remain connecting and drop the GENEVE traffic until the SAs are negotiated", func() {
+				_, err := t.driver.ConnectToEndpoint(natInfo)
+				Expect(err).To(Succeed())
+
+				Consistently(t.connectionStatus, 500*time.Millisecond).Should(Equal(subv1.Connecting))
+
+				active, err := t.driver.GetActiveConnections()
+				Expect(err).To(Succeed())
+				Expect(active).To(BeEmpty())
+
+				t.netLink.AwaitXfrmPolicies(2)
+				t.netLink.AwaitNoXfrmStates()
+			})
+		})
+
+		Context("and the remote gateway doesn't encrypt its GENEVE traffic", func() {
+			It("should fail to connect", func() {
+				delete(natInfo.Endpoint.Spec.BackendConfig, geneve.EncryptionConfig)
+
+				_, err := t.driver.ConnectToEndpoint(natInfo)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("and the IKE port is the GENEVE port", func() {
+			It("should fail to create the driver", func() {
+				t.localEndpoint.BackendConfig[geneve.IKEPortConfig] = strconv.Itoa(geneve.DefaultPort)
+
+				_, err := geneve.NewDriver(endpoint.NewLocal(&t.localEndpoint, dynamicfake.NewSimpleDynamicClient(scheme.Scheme), ""),
+					t.localCluster)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Specify("Cleanup should remove the XFRM states and policies", func() {
+			_, err := t.driver.ConnectToEndpoint(natInfo)
+			Expect(err).To(Succeed())
+
+			remote.connectTo(t)
+			t.awaitConnectionStatus(subv1.Connected)
+
+			Expect(t.driver.Cleanup()).To(Succeed())
+			t.netLink.AwaitNoXfrmStates()
+			t.netLink.AwaitXfrmPolicies(0)
+		})
+	})
+})
+
+func setEnv(name, value string) {
+	os.Setenv(name, value)
+
+	DeferCleanup(func() {
+		os.Unsetenv(name)
+	})
+}
+
+type testDriver struct {
+	localEndpoint subv1.EndpointSpec
+	local         *endpoint.Local
+	localCluster  *types.SubmarinerCluster
+	netLink       *fakeNetlink.NetLink
+	driver        cable.Driver
+}
+
+func newTestDriver() *testDriver {
+	t := &testDriver{}
+
+	BeforeEach(func() {
+		t.localCluster = &types.SubmarinerCluster{
+			Spec: subv1.ClusterSpec{
+				ClusterID:   "local",
+				ServiceCIDR: []string{"10.0.0.0/16"},
+				ClusterCIDR: []string{"11.0.0.0/16"},
+			},
+		}
+
+		t.localEndpoint = subv1.EndpointSpec{
+			ClusterID: t.localCluster.Spec.ClusterID,
+			CableName: "submariner-cable-local-192-68-1-1",
+			PrivateIP: "192.68.1.1",
+			Subnets:   append(t.localCluster.Spec.ServiceCIDR, t.localCluster.Spec.ClusterCIDR...),
+		}
+
+		t.netLink = fakeNetlink.New()
+		netlinkAPI.NewFunc = func() netlinkAPI.Interface {
+			return t.netLink
+		}
+
+		cni.DiscoverFunc = func(_ []string) (*cni.Interface, error) {
+			return &cni.Interface{
+				Name:      "veth0",
+				IPAddress: cniIPAddress,
+			}, nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		t.local = endpoint.NewLocal(&t.localEndpoint, dynamicfake.NewSimpleDynamicClient(scheme.Scheme), "")
+
+		d, err := geneve.NewDriver(t.local, t.localCluster)
+		Expect(err).To(Succeed())
+
+		Expect(d.Init()).To(Succeed())
+		Expect(d.GetName()).To(Equal(geneve.CableDriverName))
+
+		t.driver = d
+	})
+
+	return t
+}
+
+func (t *testDriver) routes() []netlink.Route {
+	link, err := t.netLink.LinkByName(geneve.GeneveIface)
+	Expect(err).To(Succeed())
+
+	routes, err := t.netLink.RouteList(link, 0)
+	Expect(err).To(Succeed())
+
+	return routes
+}
+
+func (t *testDriver) assertConnection(natInfo *natdiscovery.NATEndpointInfo) {
+	conn := subv1.Connection{
+		Status:   subv1.Connected,
+		Endpoint: natInfo.Endpoint.Spec,
+		UsingIP:  natInfo.UseIP,
+		UsingNAT: natInfo.UseNAT,
+	}
+
+	conns, err := t.driver.GetActiveConnections()
+	Expect(err).To(Succeed())
+	Expect(conns).To(HaveExactElements(conn))
+
+	conns, err = t.driver.GetConnections()
+	Expect(err).To(Succeed())
+	Expect(conns).To(HaveExactElements(conn))
+}
+
+func (t *testDriver) assertNoConnection() {
+	conns, err := t.driver.GetConnections()
+	Expect(err).To(Succeed())
+	Expect(conns).To(BeEmpty())
+}
+
+// newRemoteDriver creates a GENEVE driver, with its own netlink, for the remote endpoint with the given spec.
+func (t *testDriver) newRemoteDriver(spec *subv1.EndpointSpec) *testDriver {
+	remote := &testDriver{
+		localEndpoint: *spec.DeepCopy(),
+		localCluster:  &types.SubmarinerCluster{Spec: subv1.ClusterSpec{ClusterID: spec.ClusterID}},
+		netLink:       fakeNetlink.New(),
+	}
+
+	remote.localEndpoint.BackendConfig = map[string]string{geneve.IKEPortConfig: strconv.Itoa(freeUDPPort())}
+
+	netlinkAPI.NewFunc = func() netlinkAPI.Interface {
+		return remote.netLink
+	}
+
+	defer func() {
+		netlinkAPI.NewFunc = func() netlinkAPI.Interface {
+			return t.netLink
+		}
+	}()
+
+	remote.local = endpoint.NewLocal(&remote.localEndpoint, dynamicfake.NewSimpleDynamicClient(scheme.Scheme), "")
+
+	var err error
+
+	remote.driver, err = geneve.NewDriver(remote.local, remote.localCluster)
+	Expect(err).To(Succeed())
+	Expect(remote.driver.Init()).To(Succeed())
+
+	return remote
+}
+
+func (t *testDriver) connectTo(other *testDriver) {
+	_, err := t.driver.ConnectToEndpoint(&natdiscovery.NATEndpointInfo{
+		Endpoint: subv1.Endpoint{Spec: *other.local.Spec()},
+		UseIP:    loopback,
+	})
+	Expect(err).To(Succeed())
+}
+
+func (t *testDriver) connectionStatus() subv1.ConnectionStatus {
+	connections, err := t.driver.GetConnections()
+	Expect(err).To(Succeed())
+	Expect(connections).To(HaveLen(1))
+
+	return connections[0].Status
+}
+
+func (t *testDriver) awaitConnectionStatus(status subv1.ConnectionStatus) {
+	Eventually(t.connectionStatus, 5).Should(Equal(status))
+}
+
+func freeUDPPort() int {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{})
+	Expect(err).To(Succeed())
+
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
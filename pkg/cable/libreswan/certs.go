@@ -0,0 +1,304 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package libreswan
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/command"
+	"github.com/submariner-io/admiral/pkg/log"
+	subv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cable"
+	submendpoint "github.com/submariner-io/submariner/pkg/endpoint"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CertIDConfig is the BackendConfig entry with the IKE identity, derived from the gateway's certificate, which remote
+	// gateways use to authenticate it.
+	CertIDConfig = "ipsec-cert-id"
+
+	caCertData     = "ca.crt"
+	certNickname   = "submariner"
+	caCertNickname = "submariner-ca"
+	nssDB          = "sql:/var/lib/ipsec/nss"
+)
+
+var (
+	// certDir is where the certificate files are written to be imported into the NSS database.
+	certDir = "/etc/ipsec.d/submariner"
+
+	secretsFile = "/etc/ipsec.d/submariner.secrets"
+)
+
+// certificates holds the X.509 material used to authenticate the IKE peers.
+type certificates struct {
+	certPEM []byte
+	keyPEM  []byte
+	caPEM   []byte
+	// id is the local IKE identity, derived from the certificate's SAN or subject.
+	id string
+	// caID is the CA's subject, used to verify that remote certificates are issued by the configured CA.
+	caID string
+	// authArg is the whack authentication policy matching the certificate's key type.
+	authArg string
+}
+
+// loadCertificates loads the gateway certificate, its private key and the CA certificate from the given Secret, which
+// is expected to contain the tls.crt, tls.key and ca.crt entries.
+func loadCertificates(secretName string) (*certificates, error) {
+	kubeClient, namespace := cable.GetKubeClient()
+	if kubeClient == nil {
+		return nil, errors.New("no Kubernetes client is available to read the certificate Secret")
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving certificate Secret %q", secretName)
+	}
+
+	certs := &certificates{
+		certPEM: secret.Data[corev1.TLSCertKey],
+		keyPEM:  secret.Data[corev1.TLSPrivateKeyKey],
+		caPEM:   secret.Data[caCertData],
+	}
+
+	if len(certs.keyPEM) == 0 {
+		return nil, fmt.Errorf("certificate Secret %q is missing %q", secretName, corev1.TLSPrivateKeyKey)
+	}
+
+	cert, err := parseCertificate(certs.certPEM)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing %q from Secret %q", corev1.TLSCertKey, secretName)
+	}
+
+	caCert, err := parseCertificate(certs.caPEM)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing %q from Secret %q", caCertData, secretName)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err != nil {
+		return nil, errors.Wrapf(err, "the certificate in Secret %q isn't issued by its CA", secretName)
+	}
+
+	certs.authArg, err = authArgFor(cert)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error using %q from Secret %q", corev1.TLSCertKey, secretName)
+	}
+
+	certs.id = certID(cert)
+	certs.caID = distinguishedName(caCert)
+
+	logger.Infof("Using X.509 authentication (%s) with identity %q and CA %q", certs.authArg, certs.id, certs.caID)
+
+	return certs, nil
+}
+
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+
+	return x509.ParseCertificate(block.Bytes) //nolint:wrapcheck // Let the caller wrap it
+}
+
+// authArgFor returns the whack authentication policy for the certificate's public key: Libreswan signs with the
+// gateway's private key, so the policy must match its type.
+func authArgFor(cert *x509.Certificate) (string, error) {
+	switch cert.PublicKeyAlgorithm {
+	case x509.ECDSA:
+		return "--ecdsa", nil
+	case x509.RSA:
+		return "--rsasig", nil
+	default:
+		return "", fmt.Errorf("unsupported public key algorithm %v, only ECDSA and RSA keys are supported", cert.PublicKeyAlgorithm)
+	}
+}
+
+// certID returns the IKE identity for the certificate: its first DNS SAN, else its first IP SAN, else its subject.
+func certID(cert *x509.Certificate) string {
+	if len(cert.DNSNames) > 0 {
+		return "@" + cert.DNSNames[0]
+	}
+
+	if len(cert.IPAddresses) > 0 {
+		return cert.IPAddresses[0].String()
+	}
+
+	return distinguishedName(cert)
+}
+
+var attributeTypeNames = map[string]string{
+	"2.5.4.3":  "CN",
+	"2.5.4.5":  "SERIALNUMBER",
+	"2.5.4.6":  "C",
+	"2.5.4.7":  "L",
+	"2.5.4.8":  "ST",
+	"2.5.4.9":  "STREET",
+	"2.5.4.10": "O",
+	"2.5.4.11": "OU",
+}
+
+// distinguishedName formats the certificate's subject the way Libreswan expects an ID, ie in the encoded order, eg
+// "C=US, O=Example, CN=gateway".
+func distinguishedName(cert *x509.Certificate) string {
+	var rdns []string
+
+	for _, rdn := range cert.Subject.ToRDNSequence() {
+		for _, atv := range rdn {
+			rdns = append(rdns, fmt.Sprintf("%s=%v", attributeTypeName(atv.Type), atv.Value))
+		}
+	}
+
+	return strings.Join(rdns, ", ")
+}
+
+func attributeTypeName(oid asn1.ObjectIdentifier) string {
+	if name, ok := attributeTypeNames[oid.String()]; ok {
+		return name
+	}
+
+	return oid.String()
+}
+
+// publish adds the local IKE identity to the local endpoint's BackendConfig.
+func (c *certificates) publish(localEndpoint *submendpoint.Local) error {
+	err := localEndpoint.Update(context.TODO(), func(existing *subv1.EndpointSpec) {
+		if existing.BackendConfig == nil {
+			existing.BackendConfig = map[string]string{}
+		}
+
+		existing.BackendConfig[CertIDConfig] = c.id
+	})
+
+	return errors.Wrap(err, "error updating the local endpoint")
+}
+
+// importIntoNSS imports the gateway certificate with its private key and the CA certificate into Libreswan's NSS
+// database, replacing the entries imported previously, eg before a restart, which may hold a since rotated
+// certificate. The files containing the private key are removed once imported.
+func (c *certificates) importIntoNSS() error {
+	if err := os.MkdirAll(certDir, 0o700); err != nil {
+		return errors.Wrapf(err, "error creating directory %q", certDir)
+	}
+
+	certFile := filepath.Join(certDir, corev1.TLSCertKey)
+	keyFile := filepath.Join(certDir, corev1.TLSPrivateKeyKey)
+	caFile := filepath.Join(certDir, caCertData)
+	p12File := filepath.Join(certDir, "gateway.p12")
+
+	defer func() {
+		for _, file := range []string{keyFile, p12File} {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				logger.Errorf(err, "Error removing %q", file)
+			}
+		}
+	}()
+
+	for file, data := range map[string][]byte{certFile: c.certPEM, keyFile: c.keyPEM, caFile: c.caPEM} {
+		if err := os.WriteFile(file, data, 0o600); err != nil {
+			return errors.Wrapf(err, "error writing %q", file)
+		}
+	}
+
+	c.removeFromNSS()
+
+	commands := [][]string{
+		{"openssl", "pkcs12", "-export", "-in", certFile, "-inkey", keyFile, "-name", certNickname, "-out", p12File, "-passout", "pass:"},
+		{"pk12util", "-i", p12File, "-d", nssDB, "-W", ""},
+		{"certutil", "-A", "-d", nssDB, "-n", caCertNickname, "-t", "CT,,", "-a", "-i", caFile},
+	}
+
+	for _, args := range commands {
+		cmd := command.New(exec.Command(args[0], args[1:]...))
+
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "error running %v: %s", args, output)
+		}
+	}
+
+	return nil
+}
+
+// removeFromNSS removes the gateway certificate with its private key and the CA certificate from the NSS database. The
+// database may not contain them, eg on first start, so failures are only logged.
+func (c *certificates) removeFromNSS() {
+	commands := [][]string{
+		{"certutil", "-F", "-d", nssDB, "-n", certNickname},
+		{"certutil", "-D", "-d", nssDB, "-n", caCertNickname},
+	}
+
+	for _, args := range commands {
+		cmd := command.New(exec.Command(args[0], args[1:]...))
+
+		if output, err := cmd.CombinedOutput(); err != nil {
+			logger.V(log.DEBUG).Infof("Error running %v, the entry may not exist: %v: %s", args, err, output)
+		}
+	}
+}
+
+// authArgs returns the whack arguments for the authentication policy.
+func (i *libreswan) authArgs() []string {
+	if i.certs != nil {
+		return []string{i.certs.authArg}
+	}
+
+	return []string{"--psk"}
+}
+
+// localAuthArgs returns the whack arguments which identify the local side of a connection. With PSK authentication, the
+// given identifier is used.
+func (i *libreswan) localAuthArgs(identifier string) []string {
+	if i.certs != nil {
+		return []string{"--id", i.certs.id, "--cert", certNickname, "--sendcert", "always"}
+	}
+
+	return []string{"--id", identifier}
+}
+
+// remoteAuthArgs returns the whack arguments which authenticate the remote side of a connection. With X.509
+// authentication, the remote gateway must present a certificate for the identity it advertises, issued by the
+// configured CA.
+func (i *libreswan) remoteAuthArgs(remote *subv1.EndpointSpec, identifier string) ([]string, error) {
+	if i.certs == nil {
+		return []string{"--id", identifier}, nil
+	}
+
+	remoteID := remote.BackendConfig[CertIDConfig]
+	if remoteID == "" {
+		return nil, fmt.Errorf("remote endpoint %q does not advertise a certificate identity in %q", remote.CableName,
+			CertIDConfig)
+	}
+
+	return []string{"--id", remoteID, "--ca", i.certs.caID}, nil
+}
@@ -23,9 +23,11 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
@@ -37,10 +39,13 @@ import (
 	"github.com/submariner-io/admiral/pkg/log"
 	subv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/cable"
+	"github.com/submariner-io/submariner/pkg/cidr"
 	submendpoint "github.com/submariner-io/submariner/pkg/endpoint"
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
-	"github.com/submariner-io/submariner/pkg/netlink"
+	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
 	"github.com/submariner-io/submariner/pkg/types"
+	"github.com/vishvananda/netlink"
+	"k8s.io/apimachinery/pkg/util/sets"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -56,6 +61,9 @@ const (
 	ikeportArg       = "--ikeport"
 	dpdactionHoldArg = "--dpdaction=hold"
 	dpddelayArg      = "--dpddelay"
+	// DefaultPort is the NAT-T port used when the driver runs beside another Backend driver, which uses the shared
+	// UDP port.
+	DefaultPort = 4560
 )
 
 var logger = log.Logger{Logger: logf.Log.WithName("libreswan")}
@@ -63,14 +71,19 @@ var logger = log.Logger{Logger: logf.Log.WithName("libreswan")}
 func init() {
 	cable.AddDriver(cableDriverName, NewLibreswan)
 	cable.SetDefaultCableDriver(cableDriverName)
+	cable.SetDriverDefaultPort(cableDriverName, DefaultPort)
 }
 
 type libreswan struct {
 	localEndpoint subv1.EndpointSpec
+	local         *submendpoint.Local
+	// This tracks the transit subnets added to the local subnets of each cable, keyed by cable name
+	transitSubnets map[string][]string
 	// This tracks the requested connections
 	connections []subv1.Connection
 
 	secretKey string
+	certs     *certificates
 	logFile   string
 
 	ipSecNATTPort   string
@@ -79,6 +92,8 @@ type libreswan struct {
 	debug                 bool
 	forceUDPEncapsulation bool
 	plutoStarted          bool
+
+	netLink netlinkAPI.Interface
 }
 
 type specification struct {
@@ -86,6 +101,7 @@ type specification struct {
 	ForceEncaps bool
 	PSK         string
 	PSKSecret   string
+	CertSecret  string
 	LogFile     string
 	NATTPort    string `default:"4500"`
 }
@@ -107,7 +123,7 @@ func NewLibreswan(localEndpoint *submendpoint.Local, _ *types.SubmarinerCluster)
 
 	defaultNATTPort := int32(port) //nolint:gosec // We can safely ignore integer conversion error
 
-	nattPort, err := localEndpoint.Spec().GetBackendPort(subv1.UDPPortConfig, defaultNATTPort)
+	nattPort, err := localEndpoint.Spec().GetCableDriverPort(cableDriverName, defaultNATTPort)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error parsing %q from local endpoint", subv1.UDPPortConfig)
 	}
@@ -136,16 +152,33 @@ func NewLibreswan(localEndpoint *submendpoint.Local, _ *types.SubmarinerCluster)
 		encodedPsk = psk.String()
 	}
 
+	var certs *certificates
+
+	if ipSecSpec.CertSecret != "" {
+		certs, err = loadCertificates(ipSecSpec.CertSecret)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := certs.publish(localEndpoint); err != nil {
+			return nil, err
+		}
+	}
+
 	logger.Infof("Using NATT UDP port %d", nattPort)
 
 	return &libreswan{
 		secretKey:             encodedPsk,
+		certs:                 certs,
 		debug:                 ipSecSpec.Debug,
 		logFile:               ipSecSpec.LogFile,
 		ipSecNATTPort:         strconv.Itoa(int(nattPort)),
 		defaultNATTPort:       defaultNATTPort,
 		localEndpoint:         *localEndpoint.Spec(),
+		local:                 localEndpoint,
+		transitSubnets:        map[string][]string{},
 		connections:           []subv1.Connection{},
+		netLink:               netlinkAPI.New(),
 		forceUDPEncapsulation: ipSecSpec.ForceEncaps,
 		plutoStarted:          false,
 	}, nil
@@ -160,12 +193,17 @@ func (i *libreswan) GetName() string {
 func (i *libreswan) Init() error {
 	// Write the secrets file:
 	// %any %any : PSK "secret"
-	file, err := os.Create("/etc/ipsec.d/submariner.secrets")
+	// With X.509 authentication the file is left empty, so no PSK from a previous configuration remains.
+	file, err := os.Create(secretsFile)
 	if err != nil {
 		return errors.Wrap(err, "error creating the secrets file")
 	}
 	defer file.Close()
 
+	if i.certs != nil {
+		return i.certs.importIntoNSS()
+	}
+
 	fmt.Fprintf(file, "%%any %%any : PSK \"%s\"\n", i.secretKey)
 
 	return nil
@@ -245,16 +283,31 @@ func (i *libreswan) refreshConnectionStatus() error {
 		return err
 	}
 
-	localSubnets := extractSubnets(&i.localEndpoint)
+	// The per-SA statistics include the packet counts so they're preferred over the byte counts reported by whack.
+	states, err := i.netLink.XfrmStateList(netlink.FAMILY_ALL)
+	if err != nil {
+		logger.Warningf("Error listing the XFRM states - only the whack traffic statistics are available: %v", err)
+	}
+
+	policies, err := i.netLink.XfrmPolicyList(netlink.FAMILY_ALL)
+	if err != nil {
+		logger.Warningf("Error listing the XFRM policies - only the whack traffic statistics are available: %v", err)
+	}
 
 	for j := range i.connections {
 		isConnected := false
 
+		localSubnets := i.localSubnetsFor(i.connections[j].Endpoint.CableName)
+
 		remoteSubnets := extractSubnets(&i.connections[j].Endpoint)
 		rx, tx := 0, 0
 
 		for lsi := range localSubnets {
 			for rsi := range remoteSubnets {
+				if !cidr.SameFamily(localSubnets[lsi], remoteSubnets[rsi]) {
+					continue
+				}
+
 				connectionName := toConnectionName(i.connections[j].Endpoint.CableName, lsi, rsi)
 				subRx, okRx := activeConnectionsRx[connectionName]
 				subTx, okTx := activeConnectionsTx[connectionName]
@@ -272,8 +325,14 @@ func (i *libreswan) refreshConnectionStatus() error {
 		}
 
 		cable.RecordConnection(cableDriverName, &i.localEndpoint, &i.connections[j].Endpoint, string(i.connections[j].Status), false)
-		cable.RecordRxBytes(cableDriverName, &i.localEndpoint, &i.connections[j].Endpoint, rx)
-		cable.RecordTxBytes(cableDriverName, &i.localEndpoint, &i.connections[j].Endpoint, tx)
+
+		traffic := xfrmTrafficFor(states, xfrmReqIDsFor(policies, localSubnets, remoteSubnets),
+			net.ParseIP(i.connections[j].UsingIP))
+		if traffic == nil {
+			traffic = &subv1.ConnectionTraffic{RxBytes: int64(rx), TxBytes: int64(tx)}
+		}
+
+		cable.RecordTraffic(cableDriverName, &i.localEndpoint, &i.connections[j], traffic)
 
 		if !isConnected {
 			// Pluto should be connecting for us
@@ -287,6 +346,84 @@ func (i *libreswan) refreshConnectionStatus() error {
 	return nil
 }
 
+// xfrmReqIDsFor returns the reqids of the XFRM policies between the given local and remote subnets, ie of the SAs pluto
+// installed for the connections of a cable.
+func xfrmReqIDsFor(policies []netlink.XfrmPolicy, localSubnets, remoteSubnets []string) sets.Set[int] {
+	local, remote := canonicalSubnets(localSubnets), canonicalSubnets(remoteSubnets)
+	reqIDs := sets.New[int]()
+
+	for i := range policies {
+		if policies[i].Src == nil || policies[i].Dst == nil {
+			continue
+		}
+
+		src, dst := policies[i].Src.String(), policies[i].Dst.String()
+		if !(local.Has(src) && remote.Has(dst)) && !(remote.Has(src) && local.Has(dst)) {
+			continue
+		}
+
+		for _, tmpl := range policies[i].Tmpls {
+			if tmpl.Reqid != 0 {
+				reqIDs.Insert(tmpl.Reqid)
+			}
+		}
+	}
+
+	return reqIDs
+}
+
+// canonicalSubnets returns the given subnets in the form used by the XFRM policies.
+func canonicalSubnets(subnets []string) sets.Set[string] {
+	result := sets.New[string]()
+
+	for _, subnet := range subnets {
+		if _, ipNet, err := net.ParseCIDR(subnet); err == nil {
+			result.Insert(ipNet.String())
+		}
+	}
+
+	return result
+}
+
+// xfrmTrafficFor sums the statistics of the ESP SAs with the given reqids, ie those of a cable, to and from the given
+// remote IP. Keying the SAs by their reqid rather than the remote IP alone keeps the traffic of cables sharing a remote IP
+// apart. It returns nil if there are none.
+func xfrmTrafficFor(states []netlink.XfrmState, reqIDs sets.Set[int], remoteIP net.IP) *subv1.ConnectionTraffic {
+	if remoteIP == nil {
+		return nil
+	}
+
+	var traffic *subv1.ConnectionTraffic
+
+	for i := range states {
+		if states[i].Proto != netlink.XFRM_PROTO_ESP || !reqIDs.Has(states[i].Reqid) {
+			continue
+		}
+
+		inbound := states[i].Src.Equal(remoteIP)
+		if !inbound && !states[i].Dst.Equal(remoteIP) {
+			continue
+		}
+
+		if traffic == nil {
+			traffic = &subv1.ConnectionTraffic{}
+		}
+
+		//nolint:gosec // We can safely ignore integer conversion error
+		bytes, packets := int64(states[i].Statistics.Bytes), int64(states[i].Statistics.Packets)
+
+		if inbound {
+			traffic.RxBytes += bytes
+			traffic.RxPackets += packets
+		} else {
+			traffic.TxBytes += bytes
+			traffic.TxPackets += packets
+		}
+	}
+
+	return traffic
+}
+
 // GetActiveConnections returns an array of all the active connections.
 func (i *libreswan) GetActiveConnections() ([]subv1.Connection, error) {
 	return i.connections, nil
@@ -317,6 +454,11 @@ func extractSubnets(endpoint *subv1.EndpointSpec) []string {
 	return subnets
 }
 
+// localSubnetsFor returns the local subnets of the connections for the given cable, including its transit subnets.
+func (i *libreswan) localSubnetsFor(cableName string) []string {
+	return append(extractSubnets(&i.localEndpoint), i.transitSubnets[cableName]...)
+}
+
 func whack(args ...string) error {
 	var err error
 
@@ -365,15 +507,26 @@ func (i *libreswan) ConnectToEndpoint(endpointInfo *natdiscovery.NATEndpointInfo
 	// We'll panic if endpointInfo is nil, this is intentional
 	endpoint := &endpointInfo.Endpoint
 
-	rightNATTPort, err := endpoint.Spec.GetBackendPort(subv1.UDPPortConfig, i.defaultNATTPort)
+	rightNATTPort, err := endpoint.Spec.GetCableDriverPort(cableDriverName, i.defaultNATTPort)
 	if err != nil {
 		logger.Warningf("Error parsing %q from remote endpoint %q - using port %d instead: %v", subv1.UDPPortConfig,
 			endpoint.Spec.CableName, i.defaultNATTPort, err)
 	}
 
-	leftSubnets := extractSubnets(&i.localEndpoint)
+	// Connect to the port the remote endpoint's NAT maps its NAT-T port to, as observed by NAT discovery.
+	rightNATTPort = endpointInfo.RemotePort(rightNATTPort)
+
 	rightSubnets := extractSubnets(&endpoint.Spec)
 
+	// When transit routing is enabled, the traffic between the remote cluster and the clusters reachable through the
+	// local gateway is forwarded so their subnets are added to the local side of the connections.
+	transitSubnets := i.local.Spec().TransitSubnetsExcluding(endpoint.Spec.ClusterID)
+	i.transitSubnets[endpoint.Spec.CableName] = slices.DeleteFunc(transitSubnets, func(subnet string) bool {
+		return slices.Contains(rightSubnets, subnet)
+	})
+
+	leftSubnets := i.localSubnetsFor(endpoint.Spec.CableName)
+
 	// Ensure we’re listening
 	if err := whack("--listen"); err != nil {
 		return "", errors.Wrap(err, "error listening")
@@ -386,6 +539,11 @@ func (i *libreswan) ConnectToEndpoint(endpointInfo *natdiscovery.NATEndpointInfo
 	if len(leftSubnets) > 0 && len(rightSubnets) > 0 {
 		for lsi, leftSubnet := range leftSubnets {
 			for rsi, rightSubnet := range rightSubnets {
+				// IPsec tunnels can only carry traffic between subnets of the same IP family.
+				if !cidr.SameFamily(leftSubnet, rightSubnet) {
+					continue
+				}
+
 				connectionName := toConnectionName(endpoint.Spec.CableName, lsi, rsi)
 
 				switch connectionMode {
@@ -418,27 +576,32 @@ func (i *libreswan) bidirectionalConnectToEndpoint(connectionName string, endpoi
 	localEndpointIdentifier := i.localEndpoint.PrivateIP
 	remoteEndpointIdentifier := endpointInfo.Endpoint.Spec.PrivateIP
 
-	args := []string{}
+	remoteAuthArgs, err := i.remoteAuthArgs(&endpointInfo.Endpoint.Spec, remoteEndpointIdentifier)
+	if err != nil {
+		return err
+	}
+
+	args := i.authArgs()
 
-	args = append(args, "--psk", encryptArg)
+	args = append(args, encryptArg)
 	if endpointInfo.UseNAT || i.forceUDPEncapsulation {
 		args = append(args, forceencapsArg)
 	}
 
-	args = append(args, nameArg, connectionName,
+	args = append(args, nameArg, connectionName)
 
-		// Left-hand side
-		"--id", localEndpointIdentifier,
-		hostArg, i.localEndpoint.PrivateIP,
+	// Left-hand side
+	args = append(args, i.localAuthArgs(localEndpointIdentifier)...)
+	args = append(args, hostArg, i.localEndpoint.PrivateIP,
 		clientArg, leftSubnet,
 
 		ikeportArg, i.ipSecNATTPort,
 
-		"--to",
+		"--to")
 
-		// Right-hand side
-		"--id", remoteEndpointIdentifier,
-		hostArg, endpointInfo.UseIP,
+	// Right-hand side
+	args = append(args, remoteAuthArgs...)
+	args = append(args, hostArg, endpointInfo.UseIP,
 		clientArg, rightSubnet,
 
 		ikeportArg, strconv.Itoa(int(rightNATTPort)),
@@ -468,27 +631,32 @@ func (i *libreswan) serverConnectToEndpoint(connectionName string, endpointInfo
 	localEndpointIdentifier := toEndpointIdentifier(i.localEndpoint.PrivateIP, lsi, rsi)
 	remoteEndpointIdentifier := toEndpointIdentifier(endpointInfo.Endpoint.Spec.PrivateIP, rsi, lsi)
 
-	args := []string{}
+	remoteAuthArgs, err := i.remoteAuthArgs(&endpointInfo.Endpoint.Spec, remoteEndpointIdentifier)
+	if err != nil {
+		return err
+	}
+
+	args := i.authArgs()
 
-	args = append(args, "--psk", encryptArg)
+	args = append(args, encryptArg)
 	if endpointInfo.UseNAT || i.forceUDPEncapsulation {
 		args = append(args, forceencapsArg)
 	}
 
-	args = append(args, nameArg, connectionName,
+	args = append(args, nameArg, connectionName)
 
-		// Left-hand side.
-		"--id", localEndpointIdentifier,
-		hostArg, i.localEndpoint.PrivateIP,
+	// Left-hand side.
+	args = append(args, i.localAuthArgs(localEndpointIdentifier)...)
+	args = append(args, hostArg, i.localEndpoint.PrivateIP,
 		clientArg, leftSubnet,
 
 		ikeportArg, i.ipSecNATTPort,
 
-		"--to",
+		"--to")
 
-		// Right-hand side.
-		"--id", remoteEndpointIdentifier,
-		hostArg, "%any",
+	// Right-hand side.
+	args = append(args, remoteAuthArgs...)
+	args = append(args, hostArg, "%any",
 		clientArg, rightSubnet,
 		dpdactionHoldArg,
 		dpddelayArg, strconv.Itoa(dpdDelay))
@@ -500,7 +668,7 @@ func (i *libreswan) serverConnectToEndpoint(connectionName string, endpointInfo
 	}
 
 	// NOTE: in this case we don't route or initiate connection, we simply wait for the client
-	// to connect from %any IP, using the right PSK & ID.
+	// to connect from %any IP, using the right PSK or certificate & ID.
 	return nil
 }
 
@@ -511,25 +679,30 @@ func (i *libreswan) clientConnectToEndpoint(connectionName string, endpointInfo
 	localEndpointIdentifier := toEndpointIdentifier(i.localEndpoint.PrivateIP, lsi, rsi)
 	remoteEndpointIdentifier := toEndpointIdentifier(endpointInfo.Endpoint.Spec.PrivateIP, rsi, lsi)
 
-	args := []string{}
+	remoteAuthArgs, err := i.remoteAuthArgs(&endpointInfo.Endpoint.Spec, remoteEndpointIdentifier)
+	if err != nil {
+		return err
+	}
+
+	args := i.authArgs()
 
-	args = append(args, "--psk", encryptArg)
+	args = append(args, encryptArg)
 	if endpointInfo.UseNAT || i.forceUDPEncapsulation {
 		args = append(args, forceencapsArg)
 	}
 
-	args = append(args, nameArg, connectionName,
+	args = append(args, nameArg, connectionName)
 
-		// Left-hand side
-		"--id", localEndpointIdentifier,
-		hostArg, i.localEndpoint.PrivateIP,
+	// Left-hand side
+	args = append(args, i.localAuthArgs(localEndpointIdentifier)...)
+	args = append(args, hostArg, i.localEndpoint.PrivateIP,
 		clientArg, leftSubnet,
 
-		"--to",
+		"--to")
 
-		// Right-hand side
-		"--id", remoteEndpointIdentifier,
-		hostArg, endpointInfo.UseIP,
+	// Right-hand side
+	args = append(args, remoteAuthArgs...)
+	args = append(args, hostArg, endpointInfo.UseIP,
 		clientArg, rightSubnet,
 
 		ikeportArg, strconv.Itoa(int(rightNATTPort)),
@@ -552,7 +725,7 @@ func (i *libreswan) clientConnectToEndpoint(connectionName string, endpointInfo
 // DisconnectFromEndpoint disconnects from the connection to the given endpoint.
 func (i *libreswan) DisconnectFromEndpoint(endpoint *types.SubmarinerEndpoint) error {
 	// We'll panic if endpoint is nil, this is intentional
-	leftSubnets := extractSubnets(&i.localEndpoint)
+	leftSubnets := i.localSubnetsFor(endpoint.Spec.CableName)
 	rightSubnets := extractSubnets(&endpoint.Spec)
 
 	logger.Infof("Deleting connection to %v", endpoint)
@@ -560,6 +733,10 @@ func (i *libreswan) DisconnectFromEndpoint(endpoint *types.SubmarinerEndpoint) e
 	if len(leftSubnets) > 0 && len(rightSubnets) > 0 {
 		for lsi := range leftSubnets {
 			for rsi := range rightSubnets {
+				if !cidr.SameFamily(leftSubnets[lsi], rightSubnets[rsi]) {
+					continue
+				}
+
 				connectionName := toConnectionName(endpoint.Spec.CableName, lsi, rsi)
 				args := []string{"--delete", nameArg, connectionName}
 
@@ -576,6 +753,7 @@ func (i *libreswan) DisconnectFromEndpoint(endpoint *types.SubmarinerEndpoint) e
 	}
 
 	i.connections = removeConnectionForEndpoint(i.connections, endpoint)
+	delete(i.transitSubnets, endpoint.Spec.CableName)
 	cable.RecordDisconnected(cableDriverName, &i.localEndpoint, &endpoint.Spec)
 
 	return nil
@@ -673,5 +851,5 @@ func (i *libreswan) waitForControlSocket() error {
 func (i *libreswan) Cleanup() error {
 	logger.Info("Uninstalling the libreswan cable driver")
 
-	return netlink.DeleteXfrmRules() //nolint:wrapcheck  // No need to wrap this error
+	return netlinkAPI.DeleteXfrmRules() //nolint:wrapcheck  // No need to wrap this error
 }
@@ -19,18 +19,40 @@ limitations under the License.
 package libreswan
 
 import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"math/big"
+	"net"
 	"os"
+	"path/filepath"
 	"strconv"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	fakecommand "github.com/submariner-io/admiral/pkg/command/fake"
 	subv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cable"
 	"github.com/submariner-io/submariner/pkg/endpoint"
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
+	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
+	fakeNetlink "github.com/submariner-io/submariner/pkg/netlink/fake"
 	"github.com/submariner-io/submariner/pkg/types"
+	"github.com/vishvananda/netlink"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
@@ -41,6 +63,7 @@ var _ = Describe("Libreswan", func() {
 	Describe("DisconnectFromEndpoint", testDisconnectFromEndpoint)
 	Describe("GetConnections", testGetConnections)
 	Describe("Preferred server config", testPreferredServerConfig)
+	Describe("X.509 authentication", testCertificateAuthentication)
 })
 
 func testTrafficStatusRE() {
@@ -181,6 +204,50 @@ func testConnectToEndpoint() {
 		})
 	})
 
+	When("NAT discovery observed that the remote NAT remaps its ports", func() {
+		BeforeEach(func() {
+			natInfo.Endpoint.Spec.BackendConfig = map[string]string{
+				subv1.UDPPortConfig:           "4500",
+				subv1.NATTDiscoveryPortConfig: "4490",
+			}
+			natInfo.UsePort = 14490
+		})
+
+		It("should connect to the remapped NAT-T port", func() {
+			_, err := t.driver.ConnectToEndpoint(natInfo)
+			Expect(err).To(Succeed())
+
+			t.cmdExecutor.AwaitCommand(nil, "whack", natInfo.UseIP, "--ikeport", "14500")
+		})
+	})
+
+	When("the local endpoint advertises transit routes", func() {
+		BeforeEach(func() {
+			t.endpointSpec.TransitRoutes = []subv1.TransitRoute{
+				{ClusterID: "east", Subnets: []string{"20.0.0.0/16"}, Path: []string{"local"}},
+				{ClusterID: "west", Subnets: []string{"30.0.0.0/16"}, Path: []string{"local"}},
+				{ClusterID: "north", Subnets: []string{"40.0.0.0/16"}, Path: []string{"local", "east"}},
+			}
+		})
+
+		It("should add the subnets of the other transit clusters to the local side of the connections", func() {
+			_, err := t.driver.ConnectToEndpoint(natInfo)
+			Expect(err).To(Succeed())
+
+			t.cmdExecutor.AwaitCommand(nil, "whack", t.endpointSpec.PrivateIP, natInfo.UseIP,
+				t.endpointSpec.Subnets[0], natInfo.Endpoint.Spec.Subnets[0])
+			t.cmdExecutor.AwaitCommand(nil, "whack", toConnectionName(natInfo.Endpoint.Spec.CableName, 1, 0),
+				"30.0.0.0/16", natInfo.Endpoint.Spec.Subnets[0])
+			t.cmdExecutor.EnsureNoCommand("whack", "40.0.0.0/16")
+
+			t.cmdExecutor.Clear()
+
+			Expect(t.driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: natInfo.Endpoint.Spec})).To(Succeed())
+			t.cmdExecutor.AwaitCommand(nil, "whack", "--delete", toConnectionName(natInfo.Endpoint.Spec.CableName, 1, 0))
+			Expect(t.driver.transitSubnets).To(BeEmpty())
+		})
+	})
+
 	When("both sides prefer to be a server", func() {
 		BeforeEach(func() {
 			t.endpointSpec.BackendConfig = map[string]string{subv1.PreferredServerConfig: "true"}
@@ -242,8 +309,10 @@ func testDisconnectFromEndpoint() {
 func testGetConnections() {
 	t := newTestDriver()
 
-	It("should return the correct Connections", func() {
-		natInfo1 := &natdiscovery.NATEndpointInfo{
+	var natInfo1, natInfo2 *natdiscovery.NATEndpointInfo
+
+	JustBeforeEach(func() {
+		natInfo1 = &natdiscovery.NATEndpointInfo{
 			Endpoint: subv1.Endpoint{
 				Spec: subv1.EndpointSpec{
 					ClusterID: "remote1",
@@ -258,7 +327,7 @@ func testGetConnections() {
 		_, err := t.driver.ConnectToEndpoint(natInfo1)
 		Expect(err).To(Succeed())
 
-		natInfo2 := &natdiscovery.NATEndpointInfo{
+		natInfo2 = &natdiscovery.NATEndpointInfo{
 			Endpoint: subv1.Endpoint{
 				Spec: subv1.EndpointSpec{
 					ClusterID: "remote2",
@@ -277,7 +346,9 @@ func testGetConnections() {
 			fmt.Sprintf(" \"%s-0-0\", type=ESP, add_time=1590508783, inBytes=10, outBytes=20, id='192.68.2.1'",
 				natInfo1.Endpoint.Spec.CableName),
 			nil, "whack", "--trafficstatus")
+	})
 
+	It("should return the correct Connections", func() {
 		conn, err := t.driver.GetConnections()
 		Expect(err).To(Succeed())
 
@@ -286,13 +357,98 @@ func testGetConnections() {
 			Endpoint: natInfo1.Endpoint.Spec,
 			UsingIP:  natInfo1.UseIP,
 			UsingNAT: natInfo1.UseNAT,
+			Traffic:  &subv1.ConnectionTraffic{RxBytes: 10, TxBytes: 20},
 		}, subv1.Connection{
 			Status:   subv1.Connecting,
 			Endpoint: natInfo2.Endpoint.Spec,
 			UsingIP:  natInfo2.UseIP,
 			UsingNAT: natInfo2.UseNAT,
+			Traffic:  &subv1.ConnectionTraffic{},
 		}))
 	})
+
+	When("XFRM states exist for a connection", func() {
+		addXfrmPolicy := func(src, dst string, reqID int) {
+			_, srcNet, err := net.ParseCIDR(src)
+			Expect(err).To(Succeed())
+
+			_, dstNet, err := net.ParseCIDR(dst)
+			Expect(err).To(Succeed())
+
+			Expect(t.netLink.XfrmPolicyAdd(&netlink.XfrmPolicy{
+				Src:   srcNet,
+				Dst:   dstNet,
+				Dir:   netlink.XFRM_DIR_OUT,
+				Tmpls: []netlink.XfrmPolicyTmpl{{Proto: netlink.XFRM_PROTO_ESP, Reqid: reqID}},
+			})).To(Succeed())
+		}
+
+		addXfrmState := func(src, dst string, spi, reqID int, bytes, packets uint64) {
+			Expect(t.netLink.XfrmStateAdd(&netlink.XfrmState{
+				Src:        net.ParseIP(src),
+				Dst:        net.ParseIP(dst),
+				Proto:      netlink.XFRM_PROTO_ESP,
+				Spi:        spi,
+				Reqid:      reqID,
+				Statistics: netlink.XfrmStateStats{Bytes: bytes, Packets: packets},
+			})).To(Succeed())
+		}
+
+		JustBeforeEach(func() {
+			addXfrmPolicy(t.endpointSpec.Subnets[0], natInfo1.Endpoint.Spec.Subnets[0], 16389)
+			addXfrmPolicy(t.endpointSpec.Subnets[0], natInfo1.Endpoint.Spec.Subnets[1], 16393)
+
+			addXfrmState(natInfo1.UseIP, t.endpointSpec.PrivateIP, 1, 16389, 1000, 10)
+			addXfrmState(t.endpointSpec.PrivateIP, natInfo1.UseIP, 2, 16389, 3000, 20)
+			addXfrmState(t.endpointSpec.PrivateIP, natInfo1.UseIP, 3, 16393, 500, 5)
+		})
+
+		It("should return the per-SA traffic statistics", func() {
+			conn, err := t.driver.GetConnections()
+			Expect(err).To(Succeed())
+			Expect(conn).To(HaveLen(2))
+			Expect(conn[0].Traffic).To(Equal(&subv1.ConnectionTraffic{
+				RxBytes:   1000,
+				TxBytes:   3500,
+				RxPackets: 10,
+				TxPackets: 25,
+			}))
+			Expect(conn[1].Traffic).To(Equal(&subv1.ConnectionTraffic{}))
+		})
+
+		Context("and another connection uses the same remote IP", func() {
+			JustBeforeEach(func() {
+				Expect(t.driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: natInfo2.Endpoint.Spec})).To(Succeed())
+
+				natInfo2.UseIP = natInfo1.UseIP
+				_, err := t.driver.ConnectToEndpoint(natInfo2)
+				Expect(err).To(Succeed())
+
+				addXfrmPolicy(t.endpointSpec.Subnets[0], natInfo2.Endpoint.Spec.Subnets[0], 16397)
+
+				addXfrmState(natInfo2.UseIP, t.endpointSpec.PrivateIP, 4, 16397, 200, 2)
+				addXfrmState(t.endpointSpec.PrivateIP, natInfo2.UseIP, 5, 16397, 400, 4)
+			})
+
+			It("should return the traffic statistics of each connection's SAs", func() {
+				conn, err := t.driver.GetConnections()
+				Expect(err).To(Succeed())
+				Expect(conn).To(HaveLen(2))
+				Expect(conn[0].Traffic).To(Equal(&subv1.ConnectionTraffic{
+					RxBytes:   1000,
+					TxBytes:   3500,
+					RxPackets: 10,
+					TxPackets: 25,
+				}))
+				Expect(conn[1].Traffic).To(Equal(&subv1.ConnectionTraffic{
+					RxBytes:   200,
+					TxBytes:   400,
+					RxPackets: 2,
+					TxPackets: 4,
+				}))
+			})
+		})
+	})
 }
 
 func testPreferredServerConfig() {
@@ -337,10 +493,250 @@ func testPreferredServerConfig() {
 	})
 }
 
+func testCertificateAuthentication() {
+	const secretName = "gateway-cert"
+
+	t := newTestDriver()
+
+	var (
+		natInfo    *natdiscovery.NATEndpointInfo
+		kubeClient *k8sfake.Clientset
+		secretData map[string][]byte
+		caCert     *x509.Certificate
+		caKey      crypto.Signer
+	)
+
+	setGatewayCertificate := func(key crypto.Signer) {
+		_, certPEM := newTestCertificate(caCert, caKey, key, &x509.Certificate{
+			Subject:  pkix.Name{CommonName: "gateway"},
+			DNSNames: []string{"gateway.local.example"},
+		})
+
+		keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+		Expect(err).To(Succeed())
+
+		secretData[corev1.TLSCertKey] = certPEM
+		secretData[corev1.TLSPrivateKeyKey] = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})
+	}
+
+	updateSecret := func() {
+		_, err := kubeClient.CoreV1().Secrets("submariner").Update(context.Background(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName},
+			Data:       secretData,
+		}, metav1.UpdateOptions{})
+		Expect(err).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var caPEM []byte
+
+		caKey = newECDSAKey()
+		caCert, caPEM = newTestCertificate(nil, nil, caKey, &x509.Certificate{
+			Subject:               pkix.Name{Country: []string{"US"}, Organization: []string{"Submariner"}, CommonName: "Test CA"},
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		})
+
+		secretData = map[string][]byte{caCertData: caPEM}
+		setGatewayCertificate(newECDSAKey())
+
+		kubeClient = k8sfake.NewClientset()
+		cable.SetKubeClient(kubeClient, "submariner")
+
+		_, err := kubeClient.CoreV1().Secrets("submariner").Create(context.Background(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName},
+			Data:       secretData,
+		}, metav1.CreateOptions{})
+		Expect(err).To(Succeed())
+
+		os.Setenv("CE_IPSEC_CERTSECRET", secretName)
+
+		DeferCleanup(func() {
+			os.Unsetenv("CE_IPSEC_CERTSECRET")
+			cable.SetKubeClient(nil, "")
+		})
+
+		natInfo = &natdiscovery.NATEndpointInfo{
+			Endpoint: subv1.Endpoint{
+				Spec: subv1.EndpointSpec{
+					ClusterID:     "east",
+					CableName:     "submariner-cable-east-192-68-2-1",
+					PrivateIP:     "192.68.2.1",
+					Subnets:       []string{"20.0.0.0/16"},
+					BackendConfig: map[string]string{CertIDConfig: "@gateway.east.example"},
+				},
+			},
+			UseIP: "172.93.2.1",
+		}
+	})
+
+	It("should publish the certificate identity in the local endpoint", func() {
+		Expect(t.localEndpoint.Spec().BackendConfig).To(HaveKeyWithValue(CertIDConfig, "@gateway.local.example"))
+	})
+
+	It("should authenticate the remote peer with its certificate identity and the CA using ECDSA signatures", func() {
+		_, err := t.driver.ConnectToEndpoint(natInfo)
+		Expect(err).To(Succeed())
+
+		t.cmdExecutor.AwaitCommand(nil, "whack", "--ecdsa", "@gateway.local.example", "--cert", "@gateway.east.example",
+			"--ca", "C=US, O=Submariner, CN=Test CA")
+		t.cmdExecutor.EnsureNoCommand("whack", "--rsasig")
+		t.cmdExecutor.EnsureNoCommand("whack", "--psk")
+	})
+
+	When("the certificate has an RSA key", func() {
+		BeforeEach(func() {
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).To(Succeed())
+
+			setGatewayCertificate(key)
+			updateSecret()
+		})
+
+		It("should authenticate using RSA signatures", func() {
+			_, err := t.driver.ConnectToEndpoint(natInfo)
+			Expect(err).To(Succeed())
+
+			t.cmdExecutor.AwaitCommand(nil, "whack", "--rsasig", "@gateway.local.example", "--cert", "@gateway.east.example")
+			t.cmdExecutor.EnsureNoCommand("whack", "--ecdsa")
+		})
+	})
+
+	When("the certificate has an unsupported key type", func() {
+		It("should fail to load the certificates", func() {
+			_, key, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).To(Succeed())
+
+			setGatewayCertificate(key)
+			updateSecret()
+
+			_, err = loadCertificates(secretName)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("should import the certificates into the NSS database", func() {
+		certDir = GinkgoT().TempDir()
+
+		// The NSS database is empty so removing the previous entries fails.
+		t.cmdExecutor.SetupCommandOutputWithError("not found", errors.New("exit status 255"), nil, "certutil", "-F")
+		t.cmdExecutor.SetupCommandOutputWithError("not found", errors.New("exit status 255"), nil, "certutil", "-D")
+
+		Expect(t.driver.certs.importIntoNSS()).To(Succeed())
+		t.cmdExecutor.AwaitCommand(nil, "openssl", "pkcs12")
+		t.cmdExecutor.AwaitCommand(nil, "pk12util", nssDB)
+		t.cmdExecutor.AwaitCommand(nil, "certutil", "-A", caCertNickname)
+
+		Expect(filepath.Join(certDir, corev1.TLSCertKey)).To(BeAnExistingFile())
+		Expect(filepath.Join(certDir, corev1.TLSPrivateKeyKey)).ToNot(BeAnExistingFile())
+		Expect(filepath.Join(certDir, "gateway.p12")).ToNot(BeAnExistingFile())
+	})
+
+	When("the NSS database already contains the certificates", func() {
+		It("should replace them on re-import", func() {
+			certDir = GinkgoT().TempDir()
+
+			Expect(t.driver.certs.importIntoNSS()).To(Succeed())
+
+			// Simulate a restart with a rotated certificate.
+			t.cmdExecutor.Clear()
+
+			setGatewayCertificate(newECDSAKey())
+			updateSecret()
+
+			certs, err := loadCertificates(secretName)
+			Expect(err).To(Succeed())
+			Expect(certs.importIntoNSS()).To(Succeed())
+
+			t.cmdExecutor.AwaitCommand(nil, "certutil", "-F", nssDB, certNickname)
+			t.cmdExecutor.AwaitCommand(nil, "certutil", "-D", nssDB, caCertNickname)
+			t.cmdExecutor.AwaitCommand(nil, "pk12util", nssDB)
+			t.cmdExecutor.AwaitCommand(nil, "certutil", "-A", caCertNickname)
+
+			data, err := os.ReadFile(filepath.Join(certDir, corev1.TLSCertKey))
+			Expect(err).To(Succeed())
+			Expect(data).To(Equal(secretData[corev1.TLSCertKey]))
+		})
+	})
+
+	It("should not write a PSK to the secrets file", func() {
+		prevSecretsFile := secretsFile
+		DeferCleanup(func() {
+			secretsFile = prevSecretsFile
+		})
+
+		certDir = GinkgoT().TempDir()
+		secretsFile = filepath.Join(GinkgoT().TempDir(), "submariner.secrets")
+
+		Expect(t.driver.Init()).To(Succeed())
+
+		data, err := os.ReadFile(secretsFile)
+		Expect(err).To(Succeed())
+		Expect(string(data)).ToNot(ContainSubstring("PSK"))
+	})
+
+	When("the remote endpoint doesn't advertise a certificate identity", func() {
+		BeforeEach(func() {
+			natInfo.Endpoint.Spec.BackendConfig = nil
+		})
+
+		It("should fail to connect", func() {
+			_, err := t.driver.ConnectToEndpoint(natInfo)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("the certificate isn't issued by the CA", func() {
+		It("should fail to load the certificates", func() {
+			_, otherCA := newTestCertificate(nil, nil, newECDSAKey(), &x509.Certificate{
+				Subject:               pkix.Name{CommonName: "Other CA"},
+				IsCA:                  true,
+				BasicConstraintsValid: true,
+			})
+
+			secretData[caCertData] = otherCA
+			updateSecret()
+
+			_, err := loadCertificates(secretName)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+}
+
+func newECDSAKey() crypto.Signer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).To(Succeed())
+
+	return key
+}
+
+// newTestCertificate creates a certificate for the key from the template, signed by the given parent or self-signed if nil.
+func newTestCertificate(parent *x509.Certificate, parentKey, key crypto.Signer, template *x509.Certificate,
+) (*x509.Certificate, []byte) {
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	if parent == nil {
+		parent = template
+		parentKey = key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	Expect(err).To(Succeed())
+
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).To(Succeed())
+
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
 type testDriver struct {
 	endpointSpec  subv1.EndpointSpec
 	localEndpoint *endpoint.Local
 	cmdExecutor   *fakecommand.Executor
+	netLink       *fakeNetlink.NetLink
 	driver        *libreswan
 }
 
@@ -349,6 +745,16 @@ func newTestDriver() *testDriver {
 
 	BeforeEach(func() {
 		t.cmdExecutor = fakecommand.New()
+		t.netLink = fakeNetlink.New()
+
+		netlinkAPI.NewFunc = func() netlinkAPI.Interface {
+			return t.netLink
+		}
+
+		DeferCleanup(func() {
+			netlinkAPI.NewFunc = nil
+		})
+
 		t.endpointSpec = subv1.EndpointSpec{
 			ClusterID: "local",
 			CableName: "submariner-cable-local-192-68-1-1",
@@ -34,6 +34,7 @@ const (
 	remoteHostnameLabel    = "remote_hostname"
 	remoteEndpointIPLabel  = "remote_endpoint_ip"
 	connectionsStatusLabel = "status"
+	interfaceLabel         = "interface"
 )
 
 var (
@@ -68,6 +69,88 @@ var (
 			remoteEndpointIPLabel,
 		},
 	)
+	rxPacketsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submariner_gateway_rx_packets",
+			Help: "Count of packets received (by cable driver and cable)",
+		},
+		[]string{
+			cableDriverLabel,
+			localClusterLabel,
+			localHostnameLabel,
+			localEndpointIPLabel,
+			remoteClusterLabel,
+			remoteHostnameLabel,
+			remoteEndpointIPLabel,
+		},
+	)
+	txPacketsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submariner_gateway_tx_packets",
+			Help: "Count of packets transmitted (by cable driver and cable)",
+		},
+		[]string{
+			cableDriverLabel,
+			localClusterLabel,
+			localHostnameLabel,
+			localEndpointIPLabel,
+			remoteClusterLabel,
+			remoteHostnameLabel,
+			remoteEndpointIPLabel,
+		},
+	)
+	interfaceRxGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submariner_gateway_interface_rx_bytes",
+			Help: "Count of bytes received on the interface shared by the cables of a cable driver",
+		},
+		[]string{
+			cableDriverLabel,
+			localClusterLabel,
+			localHostnameLabel,
+			localEndpointIPLabel,
+			interfaceLabel,
+		},
+	)
+	interfaceTxGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submariner_gateway_interface_tx_bytes",
+			Help: "Count of bytes transmitted on the interface shared by the cables of a cable driver",
+		},
+		[]string{
+			cableDriverLabel,
+			localClusterLabel,
+			localHostnameLabel,
+			localEndpointIPLabel,
+			interfaceLabel,
+		},
+	)
+	interfaceRxPacketsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submariner_gateway_interface_rx_packets",
+			Help: "Count of packets received on the interface shared by the cables of a cable driver",
+		},
+		[]string{
+			cableDriverLabel,
+			localClusterLabel,
+			localHostnameLabel,
+			localEndpointIPLabel,
+			interfaceLabel,
+		},
+	)
+	interfaceTxPacketsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submariner_gateway_interface_tx_packets",
+			Help: "Count of packets transmitted on the interface shared by the cables of a cable driver",
+		},
+		[]string{
+			cableDriverLabel,
+			localClusterLabel,
+			localHostnameLabel,
+			localEndpointIPLabel,
+			interfaceLabel,
+		},
+	)
 	connectionsGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "submariner_connections",
@@ -127,8 +210,9 @@ var (
 )
 
 func init() {
-	prometheus.MustRegister(rxGauge, txGauge, connectionsGauge, shortConnectionsGauge, connectionEstablishedTimestampGauge,
-		connectionLatencySecondsGauge)
+	prometheus.MustRegister(rxGauge, txGauge, rxPacketsGauge, txPacketsGauge, interfaceRxGauge, interfaceTxGauge,
+		interfaceRxPacketsGauge, interfaceTxPacketsGauge, connectionsGauge, shortConnectionsGauge,
+		connectionEstablishedTimestampGauge, connectionLatencySecondsGauge)
 }
 
 func getLabels(cableDriverName string, localEndpoint, remoteEndpoint *submv1.EndpointSpec) prometheus.Labels {
@@ -157,6 +241,38 @@ func RecordTxBytes(cableDriverName string, localEndpoint, remoteEndpoint *submv1
 	txGauge.With(getLabels(cableDriverName, localEndpoint, remoteEndpoint)).Set(float64(bytes))
 }
 
+// RecordTraffic summarises the given traffic counters in the connection status and exports them.
+func RecordTraffic(cableDriverName string, localEndpoint *submv1.EndpointSpec, connection *submv1.Connection,
+	traffic *submv1.ConnectionTraffic,
+) {
+	connection.Traffic = traffic
+
+	labels := getLabels(cableDriverName, localEndpoint, &connection.Endpoint)
+	rxGauge.With(labels).Set(float64(traffic.RxBytes))
+	txGauge.With(labels).Set(float64(traffic.TxBytes))
+	rxPacketsGauge.With(labels).Set(float64(traffic.RxPackets))
+	txPacketsGauge.With(labels).Set(float64(traffic.TxPackets))
+}
+
+// RecordInterfaceTraffic exports the traffic counters of an interface shared by all the cables of a driver, for drivers
+// which can't attribute the traffic to each cable.
+func RecordInterfaceTraffic(cableDriverName string, localEndpoint *submv1.EndpointSpec, iface string,
+	traffic *submv1.ConnectionTraffic,
+) {
+	labels := prometheus.Labels{
+		cableDriverLabel:     cableDriverName,
+		localClusterLabel:    localEndpoint.ClusterID,
+		localHostnameLabel:   localEndpoint.Hostname,
+		localEndpointIPLabel: localEndpoint.PublicIP,
+		interfaceLabel:       iface,
+	}
+
+	interfaceRxGauge.With(labels).Set(float64(traffic.RxBytes))
+	interfaceTxGauge.With(labels).Set(float64(traffic.TxBytes))
+	interfaceRxPacketsGauge.With(labels).Set(float64(traffic.RxPackets))
+	interfaceTxPacketsGauge.With(labels).Set(float64(traffic.TxPackets))
+}
+
 func RecordConnectionLatency(cableDriverName string, localEndpoint, remoteEndpoint *submv1.EndpointSpec, latencySeconds float64) {
 	connectionLatencySecondsGauge.With(getLabels(cableDriverName, localEndpoint, remoteEndpoint)).Set(latencySeconds)
 }
@@ -184,6 +300,8 @@ func RecordDisconnected(cableDriverName string, localEndpoint, remoteEndpoint *s
 	connectionEstablishedTimestampGauge.Delete(labels)
 	rxGauge.Delete(labels)
 	txGauge.Delete(labels)
+	rxPacketsGauge.Delete(labels)
+	txPacketsGauge.Delete(labels)
 	connectionsGauge.Delete(labels)
 	shortConnectionsGauge.Delete(shortLabels)
 }
@@ -29,21 +29,26 @@ import (
 	"github.com/submariner-io/admiral/pkg/log"
 	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/cable"
+	"github.com/submariner-io/submariner/pkg/cidr"
 	"github.com/submariner-io/submariner/pkg/cni"
 	submendpoint "github.com/submariner-io/submariner/pkg/endpoint"
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
 	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
 	"github.com/submariner-io/submariner/pkg/types"
 	"github.com/submariner-io/submariner/pkg/vxlan"
+	"github.com/vishvananda/netlink"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 const (
 	VxlanIface             = "vxlan-tunnel"
 	VxlanVTepNetworkPrefix = 241
-	CableDriverName        = "vxlan"
-	TableID                = 100
-	DefaultPort            = 4500
+	// VxlanVTepIPv6NetworkPrefix is the /96 prefix of the IPv6 VTEP addresses, which embed the IPv4 private IP of the gateway.
+	VxlanVTepIPv6NetworkPrefix = "fd00:5ac::"
+	CableDriverName            = "vxlan"
+	TableID                    = 100
+	// DefaultPort differs from the IPsec NAT-T port so the driver can run beside an IPsec driver.
+	DefaultPort = 4530
 )
 
 type vxLan struct {
@@ -54,12 +59,14 @@ type vxLan struct {
 	vxlanIface    *vxlan.Interface
 	netLink       netlinkAPI.Interface
 	vtepIP        net.IP
+	vtepIPv6      net.IP
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("vxlan")}
 
 func init() {
 	cable.AddDriver(CableDriverName, NewDriver)
+	cable.SetDriverDefaultPort(CableDriverName, DefaultPort)
 }
 
 func NewDriver(localEndpoint *submendpoint.Local, localCluster *types.SubmarinerCluster) (cable.Driver, error) {
@@ -76,7 +83,7 @@ func NewDriver(localEndpoint *submendpoint.Local, localCluster *types.Submariner
 		logger.Warning("VxLan cable-driver is supported only with no NAT deployments")
 	}
 
-	port, err := v.localEndpoint.GetBackendPort(v1.UDPPortConfig, DefaultPort)
+	port, err := v.localEndpoint.GetCableDriverPort(CableDriverName, DefaultPort)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get the UDP port configuration")
 	}
@@ -135,6 +142,13 @@ func (v *vxLan) createVxlanInterface(port int) error {
 		return errors.Wrap(err, "failed to configure vxlan interface ipaddress on the Gateway Node")
 	}
 
+	if v.isDualStack() {
+		err = v.configureIPv6(ipAddr)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = v.netLink.EnableForwarding(VxlanIface)
 	if err != nil {
 		return errors.Wrapf(err, "error enabling forwarding on the %q iface", VxlanIface)
@@ -143,6 +157,33 @@ func (v *vxLan) createVxlanInterface(port int) error {
 	return nil
 }
 
+// configureIPv6 sets up the IPv6 VTEP and table rule. The VxLAN tunnel itself runs over IPv4 but it carries the IPv6
+// inter-cluster traffic as well.
+func (v *vxLan) configureIPv6(ipAddr string) error {
+	var err error
+
+	v.vtepIPv6, err = vxlan.GetVtepIPv6AddressFrom(ipAddr, net.ParseIP(VxlanVTepIPv6NetworkPrefix))
+	if err != nil {
+		return errors.Wrapf(err, "failed to derive the vxlan IPv6 vtepIP for %s", ipAddr)
+	}
+
+	err = v.vxlanIface.ConfigureIPAddress(v.vtepIPv6, net.CIDRMask(96, 128))
+	if err != nil {
+		return errors.Wrap(err, "failed to configure vxlan interface IPv6 address on the Gateway Node")
+	}
+
+	err = v.netLink.RuleAddIfNotPresent(netlinkAPI.NewTableRuleForFamily(TableID, netlink.FAMILY_V6))
+	if err != nil && !os.IsExist(err) {
+		return errors.Wrap(err, "failed to add IPv6 ip rule")
+	}
+
+	return nil
+}
+
+func (v *vxLan) isDualStack() bool {
+	return len(cidr.ExtractIPv6Subnets(v.localEndpoint.Subnets)) > 0
+}
+
 func (v *vxLan) ConnectToEndpoint(endpointInfo *natdiscovery.NATEndpointInfo) (string, error) {
 	// We'll panic if endpointInfo is nil, this is intentional
 	remoteEndpoint := endpointInfo.Endpoint
@@ -177,20 +218,30 @@ func (v *vxLan) ConnectToEndpoint(endpointInfo *natdiscovery.NATEndpointInfo) (s
 		return endpointInfo.UseIP, fmt.Errorf("failed to add remoteIP %q to the forwarding database: %w", remoteIP, err)
 	}
 
-	var ipAddress net.IP
+	var ipAddress, ipv6Address net.IP
 
 	cniIface, err := cni.Discover(v.localCluster.Spec.ClusterCIDR)
 	if err == nil {
 		ipAddress = net.ParseIP(cniIface.IPAddress)
+		ipv6Address = net.ParseIP(cniIface.IPv6Address)
 	} else {
 		logger.Errorf(nil, "Failed to get the CNI interface IP for cluster CIDR %q, host-networking use-cases may not work",
 			v.localCluster.Spec.ClusterCIDR[0])
 	}
 
-	err = v.vxlanIface.AddRoutes(remoteVtepIP, ipAddress, TableID, allowedIPs...)
+	allowedIPv4s, allowedIPv6s := splitByFamily(allowedIPs)
+
+	err = v.vxlanIface.AddRoutes(remoteVtepIP, ipAddress, TableID, allowedIPv4s...)
 	if err != nil {
 		return endpointInfo.UseIP, fmt.Errorf("failed to add route for the CIDR %q with remoteVtepIP %q and vxlanInterfaceIP %q: %w",
-			allowedIPs, remoteVtepIP, v.vtepIP, err)
+			allowedIPv4s, remoteVtepIP, v.vtepIP, err)
+	}
+
+	if len(allowedIPv6s) > 0 {
+		err = v.addIPv6Routes(privateIP, ipv6Address, allowedIPv6s)
+		if err != nil {
+			return endpointInfo.UseIP, err
+		}
 	}
 
 	v.connections = append(v.connections, v1.Connection{
@@ -254,6 +305,26 @@ func (v *vxLan) DisconnectFromEndpoint(remoteEndpoint *types.SubmarinerEndpoint)
 	return nil
 }
 
+func (v *vxLan) addIPv6Routes(remotePrivateIP string, srcIP net.IP, allowedIPs []net.IPNet) error {
+	if v.vtepIPv6 == nil {
+		logger.Warningf("Skipping the IPv6 subnets %q - the local cluster isn't dual-stack", allowedIPs)
+		return nil
+	}
+
+	remoteVtepIPv6, err := vxlan.GetVtepIPv6AddressFrom(remotePrivateIP, net.ParseIP(VxlanVTepIPv6NetworkPrefix))
+	if err != nil {
+		return fmt.Errorf("failed to derive the vxlan IPv6 vtepIP for %s: %w", remotePrivateIP, err)
+	}
+
+	err = v.vxlanIface.AddRoutes(remoteVtepIPv6, srcIP, TableID, allowedIPs...)
+	if err != nil {
+		return fmt.Errorf("failed to add route for the CIDR %q with remoteVtepIP %q and vxlanInterfaceIP %q: %w",
+			allowedIPs, remoteVtepIPv6, v.vtepIPv6, err)
+	}
+
+	return nil
+}
+
 func removeConnectionForEndpoint(connections []v1.Connection, endpoint *types.SubmarinerEndpoint) []v1.Connection {
 	for j := range connections {
 		if connections[j].Endpoint.CableName == endpoint.Spec.CableName {
@@ -266,9 +337,52 @@ func removeConnectionForEndpoint(connections []v1.Connection, endpoint *types.Su
 }
 
 func (v *vxLan) GetConnections() ([]v1.Connection, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.refreshTraffic()
+
 	return v.connections, nil
 }
 
+// refreshTraffic records the statistics of the VxLAN interface. The interface and its forwarding database are shared by
+// all the cables and the kernel doesn't keep per-FDB entry counters so the statistics are exported for the interface and
+// can only be attributed to a cable when it's the only one.
+func (v *vxLan) refreshTraffic() {
+	for i := range v.connections {
+		v.connections[i].Traffic = nil
+	}
+
+	if len(v.connections) == 0 {
+		return
+	}
+
+	link, err := v.netLink.LinkByName(VxlanIface)
+	if err != nil {
+		logger.Warningf("Error retrieving the %q interface: %v", VxlanIface, err)
+		return
+	}
+
+	stats := link.Attrs().Statistics
+	if stats == nil {
+		return
+	}
+
+	//nolint:gosec // We can safely ignore integer conversion error
+	traffic := &v1.ConnectionTraffic{
+		RxBytes:   int64(stats.RxBytes),
+		TxBytes:   int64(stats.TxBytes),
+		RxPackets: int64(stats.RxPackets),
+		TxPackets: int64(stats.TxPackets),
+	}
+
+	cable.RecordInterfaceTraffic(CableDriverName, &v.localEndpoint, VxlanIface, traffic)
+
+	if len(v.connections) == 1 {
+		cable.RecordTraffic(CableDriverName, &v.localEndpoint, &v.connections[0], traffic)
+	}
+}
+
 func (v *vxLan) GetActiveConnections() ([]v1.Connection, error) {
 	return v.connections, nil
 }
@@ -286,19 +400,31 @@ func parseSubnets(subnets []string) []net.IPNet {
 	nets := make([]net.IPNet, 0, len(subnets))
 
 	for _, sn := range subnets {
-		_, cidr, err := net.ParseCIDR(sn)
+		_, subnet, err := net.ParseCIDR(sn)
 		if err != nil {
 			// this should not happen. Log and continue
 			logger.Errorf(err, "Failed to parse subnet %s", sn)
 			continue
 		}
 
-		nets = append(nets, *cidr)
+		nets = append(nets, *subnet)
 	}
 
 	return nets
 }
 
+func splitByFamily(subnets []net.IPNet) (ipv4, ipv6 []net.IPNet) {
+	for i := range subnets {
+		if subnets[i].IP.To4() != nil {
+			ipv4 = append(ipv4, subnets[i])
+		} else {
+			ipv6 = append(ipv6, subnets[i])
+		}
+	}
+
+	return ipv4, ipv6
+}
+
 func (v *vxLan) Cleanup() error {
 	logger.Infof("Uninstalling the vxlan cable driver")
 
@@ -312,5 +438,12 @@ func (v *vxLan) Cleanup() error {
 		return errors.Wrapf(err, "unable to delete IP rule pointing to %d table", TableID)
 	}
 
+	if v.isDualStack() {
+		err = v.netLink.RuleDelIfPresent(netlinkAPI.NewTableRuleForFamily(TableID, netlink.FAMILY_V6))
+		if err != nil {
+			return errors.Wrapf(err, "unable to delete IPv6 IP rule pointing to %d table", TableID)
+		}
+	}
+
 	return nil
 }
@@ -25,6 +25,8 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/submariner-io/admiral/pkg/log/kzerolog"
 	subv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/cable"
@@ -129,11 +131,128 @@ var _ = Describe("Vxlan", func() {
 		Expect(routes).To(BeEmpty())
 	})
 
+	Specify("GetConnections should return the traffic statistics of the VxLAN interface", func() {
+		_, err := t.driver.ConnectToEndpoint(natInfo)
+		Expect(err).To(Succeed())
+
+		link, err := t.netLink.LinkByName(vxlan.VxlanIface)
+		Expect(err).To(Succeed())
+
+		link.Attrs().Statistics = &netlink.LinkStatistics{RxBytes: 1000, TxBytes: 2000, RxPackets: 10, TxPackets: 20}
+
+		conns, err := t.driver.GetConnections()
+		Expect(err).To(Succeed())
+		Expect(conns).To(HaveLen(1))
+		Expect(conns[0].Traffic).To(Equal(&subv1.ConnectionTraffic{RxBytes: 1000, TxBytes: 2000, RxPackets: 10, TxPackets: 20}))
+
+		natInfo2 := *natInfo
+		natInfo2.UseIP = "172.93.3.1"
+		natInfo2.Endpoint.Spec.ClusterID = "west"
+		natInfo2.Endpoint.Spec.CableName = "submariner-cable-west-192-68-3-1"
+		natInfo2.Endpoint.Spec.PrivateIP = "192.68.3.1"
+		natInfo2.Endpoint.Spec.Subnets = []string{"22.0.0.0/16"}
+
+		_, err = t.driver.ConnectToEndpoint(&natInfo2)
+		Expect(err).To(Succeed())
+
+		conns, err = t.driver.GetConnections()
+		Expect(err).To(Succeed())
+		Expect(conns).To(HaveLen(2))
+		Expect(conns[0].Traffic).To(BeNil())
+		Expect(conns[1].Traffic).To(BeNil())
+
+		Expect(testutil.GatherAndCount(prometheus.DefaultGatherer, "submariner_gateway_interface_rx_bytes")).To(Equal(1))
+	})
+
 	Specify("Cleanup should remove the VxLAN link device", func() {
 		Expect(t.driver.Cleanup()).To(Succeed())
 		t.netLink.AwaitNoLink(vxlan.VxlanIface)
 		t.netLink.AwaitNoRule(vxlan.TableID, "", "")
 	})
+
+	When("the remote endpoint has IPv6 subnets", func() {
+		const cniIPv6Address = "fd00:100::5"
+
+		remoteVtepIPv6 := vxlan.VxlanVTepIPv6NetworkPrefix + "c044:201"
+
+		BeforeEach(func() {
+			natInfo.Endpoint.Spec.Subnets = append(natInfo.Endpoint.Spec.Subnets, "fd00:20::/64")
+
+			cni.DiscoverFunc = func(_ []string) (*cni.Interface, error) {
+				return &cni.Interface{
+					Name:        "veth0",
+					IPAddress:   cniIPAddress,
+					IPv6Address: cniIPv6Address,
+				}, nil
+			}
+		})
+
+		Context("and the local cluster is dual-stack", func() {
+			BeforeEach(func() {
+				t.localEndpoint.Subnets = append(t.localEndpoint.Subnets, "fd00:10::/64")
+			})
+
+			It("should configure an IPv6 VTEP and route the IPv6 subnets via the remote IPv6 VTEP", func() {
+				link, err := t.netLink.LinkByName(vxlan.VxlanIface)
+				Expect(err).To(Succeed())
+
+				addrs, err := t.netLink.AddrList(link, netlink.FAMILY_ALL)
+				Expect(err).To(Succeed())
+
+				var actualAddrs []string
+				for i := range addrs {
+					actualAddrs = append(actualAddrs, addrs[i].IPNet.String())
+				}
+
+				Expect(actualAddrs).To(ConsistOf(fmt.Sprintf("%d.68.1.1/8", vxlan.VxlanVTepNetworkPrefix),
+					vxlan.VxlanVTepIPv6NetworkPrefix+"c044:101/96"))
+
+				rules, err := t.netLink.RuleList(netlink.FAMILY_V6)
+				Expect(err).To(Succeed())
+				Expect(rules).To(HaveLen(1))
+				Expect(rules[0].Table).To(Equal(vxlan.TableID))
+
+				_, err = t.driver.ConnectToEndpoint(natInfo)
+				Expect(err).To(Succeed())
+
+				routes, err := t.netLink.RouteList(link, netlink.FAMILY_V6)
+				Expect(err).To(Succeed())
+				Expect(routes).To(HaveLen(1))
+				Expect(routeFieldMap(routes[0].Src.String(), routes[0].Gw.String(), routes[0].Dst.String())).To(
+					Equal(routeFieldMap(cniIPv6Address, remoteVtepIPv6, "fd00:20::/64")))
+
+				Expect(t.driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: natInfo.Endpoint.Spec})).To(Succeed())
+
+				routes, err = t.netLink.RouteList(link, netlink.FAMILY_ALL)
+				Expect(err).To(Succeed())
+				Expect(routes).To(BeEmpty())
+
+				Expect(t.driver.Cleanup()).To(Succeed())
+
+				rules, err = t.netLink.RuleList(netlink.FAMILY_V6)
+				Expect(err).To(Succeed())
+				Expect(rules).To(BeEmpty())
+			})
+		})
+
+		Context("and the local cluster is IPv4-only", func() {
+			It("should only route the IPv4 subnets", func() {
+				_, err := t.driver.ConnectToEndpoint(natInfo)
+				Expect(err).To(Succeed())
+
+				link, err := t.netLink.LinkByName(vxlan.VxlanIface)
+				Expect(err).To(Succeed())
+
+				routes, err := t.netLink.RouteList(link, netlink.FAMILY_ALL)
+				Expect(err).To(Succeed())
+				Expect(routes).To(HaveLen(2))
+
+				rules, err := t.netLink.RuleList(netlink.FAMILY_V6)
+				Expect(err).To(Succeed())
+				Expect(rules).To(BeEmpty())
+			})
+		})
+	})
 })
 
 func routeFieldMap(src, gw, dst string) map[string]string {
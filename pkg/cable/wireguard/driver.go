@@ -20,7 +20,6 @@ package wireguard
 
 import (
 	"context"
-	"crypto/sha256"
 	"fmt"
 	"net"
 	"os"
@@ -50,12 +49,26 @@ const (
 	// PublicKey is name (key) of publicKey entry in back-end map.
 	PublicKey = "publicKey"
 
+	// PreviousPublicKey is name (key) of the entry in back-end map with the public key replaced by the last key rotation.
+	// It's only present during the rotation overlap window.
+	PreviousPublicKey = "previousPublicKey"
+
+	// PSKDerivation is name (key) of the entry in back-end map advertising how the gateway derives the pre-shared keys.
+	PSKDerivation = "pskDerivation"
+
+	// pskDerivationPair is the PSKDerivation value of gateways deriving a pre-shared key per cluster pair with HKDF.
+	pskDerivationPair = "pair-hkdf-sha256"
+
 	// KeepAliveInterval to use for wg peers.
 	KeepAliveInterval = 10 * time.Second
 
 	// handshakeTimeout is maximal time from handshake a connections is still considered connected.
 	handshakeTimeout = 2*time.Minute + 10*time.Second
 
+	// DefaultPort is the listen port used when the driver runs beside another Backend driver, which uses the shared
+	// UDP port.
+	DefaultPort = 4540
+
 	cableDriverName = "wireguard"
 	receiveBytes    = "ReceiveBytes"  // for peer connection status
 	transmitBytes   = "TransmitBytes" // for peer connection status
@@ -66,21 +79,41 @@ var logger = log.Logger{Logger: logf.Log.WithName("wireguard")}
 
 func init() {
 	cable.AddDriver(cableDriverName, NewDriver)
+	cable.SetDriverDefaultPort(cableDriverName, DefaultPort)
 }
 
 type specification struct {
 	PSK      string `default:"default psk"`
 	NATTPort int32  `default:"4500"`
+	// KeyRotationInterval is the interval at which the private key is rotated. Zero disables rotation.
+	KeyRotationInterval time.Duration `split_words:"true"`
+	// KeyRotationOverlap is the minimum time after a rotation during which remote peers accept both the old and new keys.
+	// The window is extended until the peers on both sides have completed a handshake with the new key.
+	KeyRotationOverlap time.Duration `split_words:"true" default:"2m"`
+	// PairPSKs holds optional secrets shared with individual remote clusters, by cluster ID, which are mixed into the
+	// pre-shared key of the pair so other clusters holding the PSK can't derive it. Both sides of a pair must configure
+	// the same secret.
+	PairPSKs map[string]string `split_words:"true"`
+}
+
+// wgClient is the subset of the wgctrl.Client API used by the driver.
+type wgClient interface {
+	Device(name string) (*wgtypes.Device, error)
+	ConfigureDevice(name string, cfg wgtypes.Config) error
+	Close() error
 }
 
 type wireguard struct {
 	localEndpoint v1.EndpointSpec
 	connections   map[string]*v1.Connection // clusterID -> remote ep connection
 	mutex         sync.Mutex
-	client        *wgctrl.Client
+	client        wgClient
 	link          netlink.Link
 	spec          *specification
-	psk           *wgtypes.Key
+	local         *endpoint.Local
+	keys          *keyStore
+	keyState      *keyState
+	retiredPeers  map[string]*retiredPeer // public key -> peer retired by a remote key rotation
 }
 
 // NewDriver creates a new WireGuard driver.
@@ -89,8 +122,10 @@ func NewDriver(localEndpoint *endpoint.Local, _ *types.SubmarinerCluster) (cable
 	var err error
 
 	w := wireguard{
-		connections: make(map[string]*v1.Connection),
-		spec:        new(specification),
+		connections:  make(map[string]*v1.Connection),
+		spec:         new(specification),
+		local:        localEndpoint,
+		retiredPeers: make(map[string]*retiredPeer),
 	}
 
 	if err = envconfig.Process(cable.IPSecEnvPrefix, w.spec); err != nil {
@@ -102,7 +137,8 @@ func NewDriver(localEndpoint *endpoint.Local, _ *types.SubmarinerCluster) (cable
 	}
 
 	// Create the controller.
-	if w.client, err = wgctrl.New(); err != nil {
+	client, err := wgctrl.New()
+	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, fmt.Errorf("wgctrl is not available on this system")
 		}
@@ -110,6 +146,8 @@ func NewDriver(localEndpoint *endpoint.Local, _ *types.SubmarinerCluster) (cable
 		return nil, errors.Wrap(err, "failed to open wgctl client")
 	}
 
+	w.client = client
+
 	defer func() {
 		if err != nil {
 			if e := w.client.Close(); e != nil {
@@ -120,20 +158,15 @@ func NewDriver(localEndpoint *endpoint.Local, _ *types.SubmarinerCluster) (cable
 		}
 	}()
 
-	// Generate local keys and set public key in BackendConfig.
-	var priv, pub, psk wgtypes.Key
-
-	if psk, err = genPsk(w.spec.PSK); err != nil {
-		return nil, errors.Wrap(err, "error generating pre-shared key")
-	}
-
-	w.psk = &psk
+	// Load or generate the local keys and set the public key in BackendConfig.
+	kubeClient, namespace := cable.GetKubeClient()
+	w.keys = newKeyStore(kubeClient, namespace, localEndpoint.Spec().Hostname)
 
-	if priv, err = wgtypes.GeneratePrivateKey(); err != nil {
-		return nil, errors.Wrap(err, "error generating private key")
+	if w.keyState, err = w.keys.loadOrCreate(context.TODO()); err != nil {
+		return nil, errors.Wrap(err, "error loading the WireGuard keys")
 	}
 
-	port, err := localEndpoint.Spec().GetBackendPort(v1.UDPPortConfig, w.spec.NATTPort)
+	port, err := localEndpoint.Spec().GetCableDriverPort(cableDriverName, w.spec.NATTPort)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error parsing %q from local endpoint", v1.UDPPortConfig)
 	}
@@ -141,7 +174,7 @@ func NewDriver(localEndpoint *endpoint.Local, _ *types.SubmarinerCluster) (cable
 	// Configure the device - still not up.
 	peerConfigs := make([]wgtypes.PeerConfig, 0)
 	cfg := wgtypes.Config{
-		PrivateKey:   &priv,
+		PrivateKey:   &w.keyState.privateKey,
 		ListenPort:   ptr.To(int(port)),
 		FirewallMark: nil,
 		ReplacePeers: true,
@@ -152,10 +185,12 @@ func NewDriver(localEndpoint *endpoint.Local, _ *types.SubmarinerCluster) (cable
 		return nil, errors.Wrap(err, "failed to configure WireGuard device")
 	}
 
-	pub = priv.PublicKey()
+	if w.keyState.previousPublicKey != nil && time.Since(w.keyState.rotatedAt) >= w.spec.KeyRotationOverlap {
+		w.keyState.previousPublicKey = nil
+	}
 
 	err = localEndpoint.Update(context.TODO(), func(existing *v1.EndpointSpec) {
-		existing.BackendConfig[PublicKey] = pub.String()
+		setKeysInBackendConfig(existing.BackendConfig, w.keyState)
 		existing.BackendConfig[cable.InterfaceNameConfig] = DefaultDeviceName
 	})
 	if err != nil {
@@ -164,7 +199,7 @@ func NewDriver(localEndpoint *endpoint.Local, _ *types.SubmarinerCluster) (cable
 
 	w.localEndpoint = *localEndpoint.Spec()
 
-	logger.V(log.DEBUG).Infof("Created WireGuard %s with publicKey %s", DefaultDeviceName, pub)
+	logger.V(log.DEBUG).Infof("Created WireGuard %s with publicKey %s", DefaultDeviceName, w.keyState.privateKey.PublicKey())
 
 	return &w, nil
 }
@@ -246,40 +281,70 @@ func (w *wireguard) ConnectToEndpoint(endpointInfo *natdiscovery.NATEndpointInfo
 	oldCon, found := w.connections[remoteEndpoint.Spec.ClusterID]
 	if found {
 		if oldKey, err := keyFromSpec(&oldCon.Endpoint); err == nil {
-			if oldKey.String() == remoteKey.String() {
+			if oldKey.String() == remoteKey.String() &&
+				oldCon.Endpoint.BackendConfig[PSKDerivation] == remoteEndpoint.Spec.BackendConfig[PSKDerivation] {
 				// Existing connection, update status and skip.
 				w.updatePeerStatus(oldCon, oldKey)
 				logger.V(log.DEBUG).Infof("Skipping connect for existing peer key %s", oldKey)
 
 				return ip, nil
 			}
-			// new peer will take over subnets so can ignore error
-			_ = w.removePeer(oldKey)
+
+			if isRotationOf(&remoteEndpoint.Spec, oldKey) {
+				// The remote gateway rotated its key - keep the old peer, and the remote subnets routed to it, until the
+				// new peer completed a handshake. The subnets are then moved to the new peer, and the old peer is removed
+				// once the overlap window expires.
+				logger.Infof("Peer cluster %s rotated its key from %s to %s", remoteEndpoint.Spec.ClusterID, oldKey, remoteKey)
+
+				w.retiredPeers[oldKey.String()] = &retiredPeer{
+					key:             *oldKey,
+					clusterID:       remoteEndpoint.Spec.ClusterID,
+					retiredAt:       time.Now(),
+					expiry:          time.Now().Add(w.spec.KeyRotationOverlap),
+					holdsAllowedIPs: !w.retiredPeerHoldsAllowedIPs(remoteEndpoint.Spec.ClusterID),
+				}
+
+				allowedIPs = nil
+			} else {
+				// new peer will take over subnets so can ignore error
+				_ = w.removePeer(oldKey)
+			}
 		}
 
 		delete(w.connections, remoteEndpoint.Spec.ClusterID)
 	}
 
+	if allowedIPs != nil {
+		// The new peer takes over the subnets from any peer retired by a previous rotation.
+		w.releaseAllowedIPs(remoteEndpoint.Spec.ClusterID)
+	}
+
 	// create connection, overwrite existing connection
 	connection := v1.NewConnection(&remoteEndpoint.Spec, ip, endpointInfo.UseNAT)
 	connection.SetStatus(v1.Connecting, "Connection has been created but not yet started")
 	logger.V(log.DEBUG).Infof("Adding connection for cluster %s, %v", remoteEndpoint.Spec.ClusterID, connection)
 	w.connections[remoteEndpoint.Spec.ClusterID] = connection
 
-	port, err := remoteEndpoint.Spec.GetBackendPort(v1.UDPPortConfig, w.spec.NATTPort)
+	port, err := remoteEndpoint.Spec.GetCableDriverPort(cableDriverName, w.spec.NATTPort)
 	if err != nil {
 		logger.Warningf("Error parsing %q from remote endpoint %q - using port %dº instead: %v", v1.UDPPortConfig,
 			remoteEndpoint.Spec.CableName, w.spec.NATTPort, err)
 	}
 
-	remotePort := int(port)
+	// Connect to the port the remote endpoint's NAT maps its WireGuard port to, as observed by NAT discovery.
+	remotePort := int(endpointInfo.RemotePort(port))
+
+	psk, err := w.peerPSK(&remoteEndpoint.Spec)
+	if err != nil {
+		return "", errors.Wrap(err, "error generating pre-shared key")
+	}
 
 	// configure peer
 	peerCfg := []wgtypes.PeerConfig{{
 		PublicKey:    *remoteKey,
 		Remove:       false,
 		UpdateOnly:   false,
-		PresharedKey: w.psk,
+		PresharedKey: &psk,
 		Endpoint: &net.UDPAddr{
 			IP:   remoteIP,
 			Port: remotePort,
@@ -352,6 +417,13 @@ func (w *wireguard) DisconnectFromEndpoint(remoteEndpoint *types.SubmarinerEndpo
 
 	delete(w.connections, remoteEndpoint.Spec.ClusterID)
 
+	for k, retired := range w.retiredPeers {
+		if retired.clusterID == remoteEndpoint.Spec.ClusterID {
+			_ = w.removePeer(&retired.key)
+			delete(w.retiredPeers, k)
+		}
+	}
+
 	logger.V(log.DEBUG).Infof("Done removing endpoint for cluster %s", remoteEndpoint.Spec.ClusterID)
 	cable.RecordDisconnected(cableDriverName, &w.localEndpoint, &remoteEndpoint.Spec)
 
@@ -493,12 +565,6 @@ func (w *wireguard) keyMismatch(cid string, key *wgtypes.Key) bool {
 	return false
 }
 
-func genPsk(psk string) (wgtypes.Key, error) {
-	// Convert spec PSK string to right length byte array, using sha256.Size == wgtypes.KeyLen.
-	pskBytes := sha256.Sum256([]byte(psk))
-	return wgtypes.NewKey(pskBytes[:]) //nolint:wrapcheck // Let the caller wrap it
-}
-
 func (w *wireguard) Cleanup() error {
 	logger.Info("Uninstalling the wireguard cable driver")
 
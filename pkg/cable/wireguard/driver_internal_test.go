@@ -0,0 +1,262 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wireguard
+
+import (
+	"net"
+	"slices"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/natdiscovery"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+var _ = Describe("Remote key rotation", func() {
+	const remoteSubnet = "10.1.0.0/16"
+
+	var (
+		client *fakeClient
+		w      *wireguard
+		oldKey wgtypes.Key
+		newKey wgtypes.Key
+	)
+
+	newPublicKey := func() wgtypes.Key {
+		privateKey, err := wgtypes.GeneratePrivateKey()
+		Expect(err).To(Succeed())
+
+		return privateKey.PublicKey()
+	}
+
+	connect := func(key wgtypes.Key, previousKey *wgtypes.Key) {
+		endpoint := v1.Endpoint{Spec: v1.EndpointSpec{
+			ClusterID:     "west",
+			CableName:     "submariner-cable-west-172-1-1-1",
+			Subnets:       []string{remoteSubnet},
+			BackendConfig: map[string]string{PublicKey: key.String(), PSKDerivation: pskDerivationPair},
+		}}
+
+		if previousKey != nil {
+			endpoint.Spec.BackendConfig[PreviousPublicKey] = previousKey.String()
+		}
+
+		_, err := w.ConnectToEndpoint(&natdiscovery.NATEndpointInfo{Endpoint: endpoint, UseIP: "172.1.1.1"})
+		Expect(err).To(Succeed())
+	}
+
+	allowedIPsOf := func(key wgtypes.Key) []string {
+		peer := client.peer(key)
+		Expect(peer).ToNot(BeNil(), "Peer %s not found", key)
+
+		subnets := []string{}
+		for i := range peer.AllowedIPs {
+			subnets = append(subnets, peer.AllowedIPs[i].String())
+		}
+
+		return subnets
+	}
+
+	getConnections := func() {
+		_, err := w.GetConnections()
+		Expect(err).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		client = &fakeClient{}
+		oldKey = newPublicKey()
+		newKey = newPublicKey()
+
+		w = &wireguard{
+			localEndpoint: v1.EndpointSpec{ClusterID: "east"},
+			connections:   map[string]*v1.Connection{},
+			client:        client,
+			spec:          &specification{PSK: "secret", NATTPort: 4500, KeyRotationOverlap: time.Hour},
+			keyState:      &keyState{rotatedAt: time.Now()},
+			retiredPeers:  map[string]*retiredPeer{},
+		}
+
+		connect(oldKey, nil)
+		Expect(allowedIPsOf(oldKey)).To(Equal([]string{remoteSubnet}))
+
+		connect(newKey, &oldKey)
+	})
+
+	When("the new peer hasn't completed a handshake", func() {
+		It("should keep routing the remote subnets to the old peer", func() {
+			Expect(allowedIPsOf(oldKey)).To(Equal([]string{remoteSubnet}))
+			Expect(allowedIPsOf(newKey)).To(BeEmpty())
+
+			getConnections()
+
+			Expect(allowedIPsOf(oldKey)).To(Equal([]string{remoteSubnet}))
+			Expect(allowedIPsOf(newKey)).To(BeEmpty())
+		})
+	})
+
+	When("the new peer completes a handshake", func() {
+		BeforeEach(func() {
+			client.peer(newKey).LastHandshakeTime = time.Now().Add(time.Second)
+		})
+
+		It("should move the remote subnets to the new peer and keep the old peer until the overlap window expires", func() {
+			getConnections()
+
+			Expect(allowedIPsOf(newKey)).To(Equal([]string{remoteSubnet}))
+			Expect(allowedIPsOf(oldKey)).To(BeEmpty())
+
+			w.retiredPeers[oldKey.String()].expiry = time.Now()
+
+			getConnections()
+
+			Expect(client.peer(oldKey)).To(BeNil())
+			Expect(allowedIPsOf(newKey)).To(Equal([]string{remoteSubnet}))
+		})
+	})
+
+	When("the remote gateway rotates its key again before the new peer completed a handshake", func() {
+		var nextKey wgtypes.Key
+
+		BeforeEach(func() {
+			nextKey = newPublicKey()
+			connect(nextKey, &newKey)
+		})
+
+		It("should keep routing the remote subnets to the first peer until the latest peer completes a handshake", func() {
+			Expect(allowedIPsOf(oldKey)).To(Equal([]string{remoteSubnet}))
+			Expect(allowedIPsOf(newKey)).To(BeEmpty())
+			Expect(allowedIPsOf(nextKey)).To(BeEmpty())
+
+			client.peer(nextKey).LastHandshakeTime = time.Now().Add(time.Second)
+			getConnections()
+
+			Expect(allowedIPsOf(nextKey)).To(Equal([]string{remoteSubnet}))
+			Expect(allowedIPsOf(oldKey)).To(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("ConnectToEndpoint", func() {
+	When("NAT discovery observed that the remote NAT remaps its ports", func() {
+		It("should configure the peer with the remapped port", func() {
+			client := &fakeClient{}
+			w := &wireguard{
+				localEndpoint: v1.EndpointSpec{ClusterID: "east"},
+				connections:   map[string]*v1.Connection{},
+				client:        client,
+				spec:          &specification{PSK: "secret", NATTPort: 4500},
+				retiredPeers:  map[string]*retiredPeer{},
+			}
+
+			privateKey, err := wgtypes.GeneratePrivateKey()
+			Expect(err).To(Succeed())
+
+			_, err = w.ConnectToEndpoint(&natdiscovery.NATEndpointInfo{
+				Endpoint: v1.Endpoint{Spec: v1.EndpointSpec{
+					ClusterID: "west",
+					BackendConfig: map[string]string{
+						PublicKey:                  privateKey.PublicKey().String(),
+						v1.NATTDiscoveryPortConfig: "4490",
+					},
+				}},
+				UseIP:   "172.1.1.1",
+				UseNAT:  true,
+				UsePort: 14490,
+			})
+			Expect(err).To(Succeed())
+
+			peer := client.peer(privateKey.PublicKey())
+			Expect(peer).ToNot(BeNil())
+			Expect(peer.Endpoint.Port).To(Equal(14500))
+		})
+	})
+})
+
+// fakeClient is an in-memory WireGuard device which, like the kernel, routes each allowed IP to a single peer.
+type fakeClient struct {
+	peers []wgtypes.Peer
+}
+
+func (c *fakeClient) Device(name string) (*wgtypes.Device, error) {
+	return &wgtypes.Device{Name: name, Peers: slices.Clone(c.peers)}, nil
+}
+
+func (c *fakeClient) ConfigureDevice(_ string, cfg wgtypes.Config) error {
+	for i := range cfg.Peers {
+		peerCfg := &cfg.Peers[i]
+
+		if peerCfg.Remove {
+			c.peers = slices.DeleteFunc(c.peers, func(p wgtypes.Peer) bool {
+				return p.PublicKey == peerCfg.PublicKey
+			})
+
+			continue
+		}
+
+		peer := c.peer(peerCfg.PublicKey)
+		if peer == nil {
+			if peerCfg.UpdateOnly {
+				continue
+			}
+
+			c.peers = append(c.peers, wgtypes.Peer{PublicKey: peerCfg.PublicKey})
+			peer = &c.peers[len(c.peers)-1]
+		}
+
+		if peerCfg.PresharedKey != nil {
+			peer.PresharedKey = *peerCfg.PresharedKey
+		}
+
+		if peerCfg.Endpoint != nil {
+			peer.Endpoint = peerCfg.Endpoint
+		}
+
+		if peerCfg.ReplaceAllowedIPs {
+			peer.AllowedIPs = nil
+		}
+
+		for _, allowedIP := range peerCfg.AllowedIPs {
+			for j := range c.peers {
+				c.peers[j].AllowedIPs = slices.DeleteFunc(c.peers[j].AllowedIPs, func(ipNet net.IPNet) bool {
+					return ipNet.String() == allowedIP.String()
+				})
+			}
+
+			peer.AllowedIPs = append(peer.AllowedIPs, allowedIP)
+		}
+	}
+
+	return nil
+}
+
+func (c *fakeClient) Close() error {
+	return nil
+}
+
+func (c *fakeClient) peer(key wgtypes.Key) *wgtypes.Peer {
+	for i := range c.peers {
+		if c.peers[i].PublicKey == key {
+			return &c.peers[i]
+		}
+	}
+
+	return nil
+}
@@ -40,9 +40,15 @@ func (w *wireguard) GetConnections() ([]v1.Connection, error) {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
+	w.maintainKeys(d.Peers)
+
 	for i := range d.Peers {
 		key := d.Peers[i].PublicKey
 
+		if _, retired := w.retiredPeers[key.String()]; retired {
+			continue
+		}
+
 		connection, err := w.connectionByKey(&key)
 		if err != nil {
 			logger.Warningf("Found unknown peer with key %s, removing", key)
@@ -113,7 +119,7 @@ func (w *wireguard) updateConnectionForPeer(p *wgtypes.Peer, connection *v1.Conn
 		// All is good.
 		connection.SetStatus(v1.Connected, "Rx=%d Bytes, Tx=%d Bytes", p.ReceiveBytes, p.TransmitBytes)
 		cable.RecordConnection(cableDriverName, &w.localEndpoint, &connection.Endpoint, string(connection.Status), false)
-		saveAndRecordPeerTraffic(&w.localEndpoint, &connection.Endpoint, now, p.TransmitBytes, p.ReceiveBytes)
+		saveAndRecordPeerTraffic(&w.localEndpoint, connection, now, p.TransmitBytes, p.ReceiveBytes)
 
 		return
 	}
@@ -168,8 +174,12 @@ func peerTrafficDelta(c *v1.Connection, key string, newVal int64) int64 {
 	return 0
 }
 
-// Save backendConfig[key] and export the metrics to prometheus.
-func saveAndRecordPeerTraffic(localEndpoint, remoteEndpoint *v1.EndpointSpec, lc, tx, rx int64) {
+// Save backendConfig[key] and export the metrics to prometheus. WireGuard doesn't count packets per peer so only the
+// bytes are summarised in the connection.
+func saveAndRecordPeerTraffic(localEndpoint *v1.EndpointSpec, connection *v1.Connection, lc, tx, rx int64) {
+	remoteEndpoint := &connection.Endpoint
+	connection.Traffic = &v1.ConnectionTraffic{RxBytes: rx, TxBytes: tx}
+
 	remoteEndpoint.BackendConfig[lastChecked] = strconv.FormatInt(lc, 10)
 	remoteEndpoint.BackendConfig[transmitBytes] = strconv.FormatInt(tx, 10)
 	remoteEndpoint.BackendConfig[receiveBytes] = strconv.FormatInt(rx, 10)
@@ -0,0 +1,424 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wireguard
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"golang.org/x/crypto/hkdf"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	keySecretPrefix = "submariner-wireguard-keys-"
+
+	privateKeyData        = "privateKey"
+	previousPublicKeyData = "previousPublicKey"
+	rotatedAtData         = "rotatedAt"
+
+	// pairPSKLabel separates the per-pair pre-shared key derivation from any other use of the PSK.
+	pairPSKLabel = "submariner wireguard pair psk v1"
+)
+
+// keyState is the persisted key material of the local gateway.
+type keyState struct {
+	privateKey wgtypes.Key
+	// previousPublicKey is the public key replaced by the last rotation, if still within the overlap window.
+	previousPublicKey *wgtypes.Key
+	rotatedAt         time.Time
+}
+
+// keyStore persists the local gateway's keys in a Secret so they survive restarts. If there's no Kubernetes client,
+// nothing is persisted and new keys are generated on each start.
+type keyStore struct {
+	secrets corev1client.SecretInterface
+	name    string
+}
+
+func newKeyStore(client kubernetes.Interface, namespace, hostname string) *keyStore {
+	k := &keyStore{name: keySecretPrefix + hostname}
+
+	if client != nil {
+		k.secrets = client.CoreV1().Secrets(namespace)
+	}
+
+	return k
+}
+
+// loadOrCreate returns the persisted keys, generating and persisting new keys if there aren't any.
+func (k *keyStore) loadOrCreate(ctx context.Context) (*keyState, error) {
+	state, err := k.load(ctx)
+	if err != nil || state != nil {
+		return state, err
+	}
+
+	privateKey, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "error generating private key")
+	}
+
+	state = &keyState{
+		privateKey: privateKey,
+		rotatedAt:  time.Now(),
+	}
+
+	return state, k.save(ctx, state)
+}
+
+func (k *keyStore) load(ctx context.Context) (*keyState, error) {
+	if k.secrets == nil {
+		return nil, nil
+	}
+
+	secret, err := k.secrets.Get(ctx, k.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving Secret %q", k.name)
+	}
+
+	privateKey, err := wgtypes.NewKey(secret.Data[privateKeyData])
+	if err != nil {
+		logger.Warningf("Secret %q contains an invalid private key - generating a new one: %v", k.name, err)
+		return nil, nil
+	}
+
+	state := &keyState{privateKey: privateKey}
+
+	if b, ok := secret.Data[previousPublicKeyData]; ok {
+		previous, err := wgtypes.NewKey(b)
+		if err == nil {
+			state.previousPublicKey = &previous
+		}
+	}
+
+	if err := state.rotatedAt.UnmarshalText(secret.Data[rotatedAtData]); err != nil {
+		state.rotatedAt = time.Now()
+	}
+
+	logger.Infof("Loaded the WireGuard private key from Secret %q", k.name)
+
+	return state, nil
+}
+
+func (k *keyStore) save(ctx context.Context, state *keyState) error {
+	if k.secrets == nil {
+		return nil
+	}
+
+	rotatedAt, err := state.rotatedAt.MarshalText()
+	if err != nil {
+		return errors.Wrap(err, "error marshalling the rotation time")
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: k.name,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			privateKeyData: state.privateKey[:],
+			rotatedAtData:  rotatedAt,
+		},
+	}
+
+	if state.previousPublicKey != nil {
+		secret.Data[previousPublicKeyData] = state.previousPublicKey[:]
+	}
+
+	_, err = k.secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = k.secrets.Create(ctx, secret, metav1.CreateOptions{})
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "error saving Secret %q", k.name)
+	}
+
+	logger.V(log.DEBUG).Infof("Saved the WireGuard keys in Secret %q", k.name)
+
+	return nil
+}
+
+// rotate replaces the private key, retaining the current public key as the previous one.
+func (s *keyState) rotate() error {
+	privateKey, err := wgtypes.GeneratePrivateKey()
+	if err != nil {
+		return errors.Wrap(err, "error generating private key")
+	}
+
+	previous := s.privateKey.PublicKey()
+	s.previousPublicKey = &previous
+	s.privateKey = privateKey
+	s.rotatedAt = time.Now()
+
+	return nil
+}
+
+// peerPSK returns the pre-shared key to use with the given remote endpoint. The per-pair key is only used once the
+// remote gateway advertises support for it, so gateways running a previous version keep connecting with the key derived
+// from the configured PSK alone until they're upgraded.
+func (w *wireguard) peerPSK(remote *v1.EndpointSpec) (wgtypes.Key, error) {
+	if remote.BackendConfig[PSKDerivation] == pskDerivationPair {
+		return pairPSK(w.spec.PSK, w.spec.PairPSKs[remote.ClusterID], w.localEndpoint.ClusterID, remote.ClusterID)
+	}
+
+	return legacyPSK(w.spec.PSK)
+}
+
+// legacyPSK derives the pre-shared key shared by all clusters from the configured PSK, as done by previous versions.
+func legacyPSK(psk string) (wgtypes.Key, error) {
+	// Convert spec PSK string to right length byte array, using sha256.Size == wgtypes.KeyLen.
+	pskBytes := sha256.Sum256([]byte(psk))
+	return wgtypes.NewKey(pskBytes[:]) //nolint:wrapcheck // Let the caller wrap it
+}
+
+// pairPSK derives the pre-shared key for a pair of clusters with HKDF-SHA256 from the configured PSK and the optional
+// secret configured for the pair, bound to the pair's cluster IDs. Without a pair secret, every cluster holding the PSK
+// can derive the key so the pairs are only separated by the secret. The result is the same regardless of which side
+// of the pair derives it.
+func pairPSK(psk, pairSecret, clusterID1, clusterID2 string) (wgtypes.Key, error) {
+	if clusterID1 > clusterID2 {
+		clusterID1, clusterID2 = clusterID2, clusterID1
+	}
+
+	secret := lengthPrefixed(nil, psk)
+	secret = lengthPrefixed(secret, pairSecret)
+
+	info := lengthPrefixed([]byte(pairPSKLabel), clusterID1)
+	info = lengthPrefixed(info, clusterID2)
+
+	key := make([]byte, wgtypes.KeyLen)
+
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, info), key); err != nil {
+		return wgtypes.Key{}, errors.Wrap(err, "error deriving the pair pre-shared key")
+	}
+
+	return wgtypes.NewKey(key) //nolint:wrapcheck // Let the caller wrap it
+}
+
+// lengthPrefixed appends the given string prefixed with its length so concatenated fields can't be ambiguous.
+func lengthPrefixed(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s))) //nolint:gosec // Lengths of configured strings fit
+	return append(b, s...)
+}
+
+// retiredPeer is a remote peer whose key was replaced by a rotation. It's kept configured until the overlap window
+// expires and the remote gateway completed a handshake with its new key, so both the old and new keys are accepted
+// while the remote gateway transitions. The remote subnets stay routed to it until that handshake.
+type retiredPeer struct {
+	key       wgtypes.Key
+	clusterID string
+	retiredAt time.Time
+	expiry    time.Time
+	// holdsAllowedIPs is whether the remote subnets are still routed to the retired peer.
+	holdsAllowedIPs bool
+}
+
+// maintainKeys rotates the local private key when the rotation interval has elapsed, ends the overlap window of a previous
+// rotation, moves the remote subnets from retired peers to the rotated peers and removes expired retired peers. An
+// overlap window only ends once the peers on both sides have completed a handshake with the new key, as observed in the
+// given device peers. This must be called with the mutex held.
+func (w *wireguard) maintainKeys(peers []wgtypes.Peer) {
+	now := time.Now()
+
+	for k, retired := range w.retiredPeers {
+		if !w.handshakeSince(peers, retired.clusterID, retired.retiredAt) {
+			continue
+		}
+
+		if retired.holdsAllowedIPs {
+			w.moveAllowedIPs(retired)
+		}
+
+		if now.After(retired.expiry) && !retired.holdsAllowedIPs {
+			_ = w.removePeer(&retired.key)
+			delete(w.retiredPeers, k)
+		}
+	}
+
+	switch {
+	case w.spec.KeyRotationInterval > 0 && now.Sub(w.keyState.rotatedAt) >= w.spec.KeyRotationInterval &&
+		w.keyState.previousPublicKey == nil:
+		w.rotateKey()
+	case w.keyState.previousPublicKey != nil && now.Sub(w.keyState.rotatedAt) >= w.spec.KeyRotationOverlap &&
+		w.allHandshakesSince(peers, w.keyState.rotatedAt):
+		logger.Infof("The key rotation overlap window has ended - no longer publishing the previous public key")
+
+		w.keyState.previousPublicKey = nil
+
+		if err := w.keys.save(context.TODO(), w.keyState); err != nil {
+			logger.Errorf(err, "Error saving the WireGuard keys")
+		}
+
+		w.publishKeys()
+	}
+}
+
+// moveAllowedIPs routes the remote subnets of the given retired peer's cluster to the cluster's current peer. This must
+// be called with the mutex held.
+func (w *wireguard) moveAllowedIPs(retired *retiredPeer) {
+	// If the connection is gone or its key is invalid, there's no peer to move the subnets to and the retired peer is
+	// simply removed.
+	if connection, ok := w.connections[retired.clusterID]; ok {
+		if key, err := keyFromSpec(&connection.Endpoint); err == nil {
+			err = w.client.ConfigureDevice(DefaultDeviceName, wgtypes.Config{
+				Peers: []wgtypes.PeerConfig{{
+					PublicKey:         *key,
+					UpdateOnly:        true,
+					ReplaceAllowedIPs: true,
+					AllowedIPs:        parseSubnets(connection.Endpoint.Subnets),
+				}},
+			})
+			if err != nil {
+				logger.Errorf(err, "Error moving the subnets of cluster %s to its rotated peer %s", retired.clusterID, key)
+				return
+			}
+
+			logger.Infof("Moved the subnets of cluster %s from retired peer %s to peer %s", retired.clusterID, retired.key, key)
+		}
+	}
+
+	retired.holdsAllowedIPs = false
+}
+
+// retiredPeerHoldsAllowedIPs returns whether the remote subnets of the given cluster are routed to one of its retired
+// peers. This must be called with the mutex held.
+func (w *wireguard) retiredPeerHoldsAllowedIPs(clusterID string) bool {
+	for _, retired := range w.retiredPeers {
+		if retired.clusterID == clusterID && retired.holdsAllowedIPs {
+			return true
+		}
+	}
+
+	return false
+}
+
+// releaseAllowedIPs records that the remote subnets of the given cluster are no longer routed to its retired peers. This
+// must be called with the mutex held.
+func (w *wireguard) releaseAllowedIPs(clusterID string) {
+	for _, retired := range w.retiredPeers {
+		if retired.clusterID == clusterID {
+			retired.holdsAllowedIPs = false
+		}
+	}
+}
+
+// handshakeSince returns whether the current peer of the given cluster completed a handshake after the given time. This
+// must be called with the mutex held.
+func (w *wireguard) handshakeSince(peers []wgtypes.Peer, clusterID string, since time.Time) bool {
+	connection, ok := w.connections[clusterID]
+	if !ok {
+		return true
+	}
+
+	key, err := keyFromSpec(&connection.Endpoint)
+	if err != nil {
+		return true
+	}
+
+	for i := range peers {
+		if peers[i].PublicKey == *key {
+			return peers[i].LastHandshakeTime.After(since)
+		}
+	}
+
+	return false
+}
+
+// allHandshakesSince returns whether the peers of all the connected clusters completed a handshake after the given
+// time. This must be called with the mutex held.
+func (w *wireguard) allHandshakesSince(peers []wgtypes.Peer, since time.Time) bool {
+	for clusterID := range w.connections {
+		if !w.handshakeSince(peers, clusterID, since) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (w *wireguard) rotateKey() {
+	newState := *w.keyState
+	if err := newState.rotate(); err != nil {
+		logger.Errorf(err, "Error rotating the WireGuard key")
+		return
+	}
+
+	// Persist the new key first so it's reused if we restart mid-rotation.
+	if err := w.keys.save(context.TODO(), &newState); err != nil {
+		logger.Errorf(err, "Error saving the rotated WireGuard keys")
+		return
+	}
+
+	err := w.client.ConfigureDevice(DefaultDeviceName, wgtypes.Config{PrivateKey: &newState.privateKey})
+	if err != nil {
+		logger.Errorf(err, "Error configuring the rotated WireGuard key")
+		return
+	}
+
+	w.keyState = &newState
+
+	logger.Infof("Rotated the WireGuard key - the new public key is %s", newState.privateKey.PublicKey())
+
+	w.publishKeys()
+}
+
+// publishKeys updates the public keys in the local endpoint's BackendConfig.
+func (w *wireguard) publishKeys() {
+	err := w.local.Update(context.TODO(), func(existing *v1.EndpointSpec) {
+		setKeysInBackendConfig(existing.BackendConfig, w.keyState)
+	})
+	if err != nil {
+		logger.Errorf(err, "Error updating the public keys in the local endpoint")
+		return
+	}
+
+	w.localEndpoint = *w.local.Spec()
+}
+
+func setKeysInBackendConfig(backendConfig map[string]string, state *keyState) {
+	backendConfig[PublicKey] = state.privateKey.PublicKey().String()
+	backendConfig[PSKDerivation] = pskDerivationPair
+
+	if state.previousPublicKey != nil {
+		backendConfig[PreviousPublicKey] = state.previousPublicKey.String()
+	} else {
+		delete(backendConfig, PreviousPublicKey)
+	}
+}
+
+// isRotationOf returns whether the given remote endpoint's key replaced the given key in a rotation.
+func isRotationOf(ep *v1.EndpointSpec, oldKey *wgtypes.Key) bool {
+	return ep.BackendConfig[PreviousPublicKey] == oldKey.String()
+}
@@ -0,0 +1,215 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wireguard
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	namespace = "submariner"
+	hostname  = "gateway-1"
+)
+
+var _ = Describe("keyStore", func() {
+	var (
+		kubeClient *k8sfake.Clientset
+		store      *keyStore
+	)
+
+	BeforeEach(func() {
+		kubeClient = k8sfake.NewClientset()
+		store = newKeyStore(kubeClient, namespace, hostname)
+	})
+
+	When("no keys have been persisted", func() {
+		It("should generate and persist a new private key", func() {
+			state, err := store.loadOrCreate(context.Background())
+			Expect(err).To(Succeed())
+			Expect(state.previousPublicKey).To(BeNil())
+
+			secret, err := kubeClient.CoreV1().Secrets(namespace).Get(context.Background(), keySecretPrefix+hostname,
+				metav1.GetOptions{})
+			Expect(err).To(Succeed())
+			Expect(secret.Data[privateKeyData]).To(Equal(state.privateKey[:]))
+		})
+	})
+
+	When("keys have been persisted", func() {
+		It("should reuse the persisted private key", func() {
+			state, err := store.loadOrCreate(context.Background())
+			Expect(err).To(Succeed())
+
+			reloaded, err := newKeyStore(kubeClient, namespace, hostname).loadOrCreate(context.Background())
+			Expect(err).To(Succeed())
+			Expect(reloaded.privateKey).To(Equal(state.privateKey))
+			Expect(reloaded.rotatedAt.Unix()).To(Equal(state.rotatedAt.Unix()))
+		})
+	})
+
+	When("the key is rotated", func() {
+		It("should persist the new private key and the previous public key", func() {
+			state, err := store.loadOrCreate(context.Background())
+			Expect(err).To(Succeed())
+
+			oldPublicKey := state.privateKey.PublicKey()
+
+			Expect(state.rotate()).To(Succeed())
+			Expect(state.privateKey.PublicKey()).ToNot(Equal(oldPublicKey))
+			Expect(store.save(context.Background(), state)).To(Succeed())
+
+			reloaded, err := store.loadOrCreate(context.Background())
+			Expect(err).To(Succeed())
+			Expect(reloaded.privateKey).To(Equal(state.privateKey))
+			Expect(reloaded.previousPublicKey).To(Equal(&oldPublicKey))
+
+			backendConfig := map[string]string{}
+			setKeysInBackendConfig(backendConfig, reloaded)
+			Expect(backendConfig).To(HaveKeyWithValue(PublicKey, state.privateKey.PublicKey().String()))
+			Expect(isRotationOf(&v1.EndpointSpec{BackendConfig: backendConfig}, &oldPublicKey)).To(BeTrue())
+		})
+	})
+
+	When("there's no Kubernetes client", func() {
+		It("should generate a new private key each time", func() {
+			store = newKeyStore(nil, namespace, hostname)
+
+			state1, err := store.loadOrCreate(context.Background())
+			Expect(err).To(Succeed())
+
+			state2, err := store.loadOrCreate(context.Background())
+			Expect(err).To(Succeed())
+			Expect(state2.privateKey).ToNot(Equal(state1.privateKey))
+		})
+	})
+})
+
+var _ = Describe("pairPSK", func() {
+	It("should derive the same key on both sides of a cluster pair", func() {
+		psk1, err := pairPSK("secret", "", "east", "west")
+		Expect(err).To(Succeed())
+
+		psk2, err := pairPSK("secret", "", "west", "east")
+		Expect(err).To(Succeed())
+		Expect(psk1).To(Equal(psk2))
+	})
+
+	It("should derive different keys for different cluster pairs", func() {
+		psk1, err := pairPSK("secret", "", "east", "west")
+		Expect(err).To(Succeed())
+
+		psk2, err := pairPSK("secret", "", "east", "north")
+		Expect(err).To(Succeed())
+		Expect(psk1).ToNot(Equal(psk2))
+	})
+
+	It("should derive different keys for different pair secrets", func() {
+		psk1, err := pairPSK("secret", "pair1", "east", "west")
+		Expect(err).To(Succeed())
+
+		psk2, err := pairPSK("secret", "pair2", "east", "west")
+		Expect(err).To(Succeed())
+		Expect(psk1).ToNot(Equal(psk2))
+
+		psk3, err := pairPSK("secret", "", "east", "west")
+		Expect(err).To(Succeed())
+		Expect(psk1).ToNot(Equal(psk3))
+	})
+
+	It("should not derive the same key from ambiguous concatenations", func() {
+		psk1, err := pairPSK("secret", "", "east", "west")
+		Expect(err).To(Succeed())
+
+		psk2, err := pairPSK("secret", "", "eastw", "est")
+		Expect(err).To(Succeed())
+		Expect(psk1).ToNot(Equal(psk2))
+
+		psk3, err := pairPSK("secre", "t", "east", "west")
+		Expect(err).To(Succeed())
+		Expect(psk1).ToNot(Equal(psk3))
+	})
+
+	It("should not derive the key derived from the PSK alone", func() {
+		psk, err := pairPSK("secret", "", "east", "west")
+		Expect(err).To(Succeed())
+		Expect(legacyPSK("secret")).ToNot(Equal(psk))
+	})
+})
+
+var _ = Describe("peerPSK", func() {
+	w := &wireguard{
+		spec:          &specification{PSK: "secret", PairPSKs: map[string]string{"west": "pair-secret"}},
+		localEndpoint: v1.EndpointSpec{ClusterID: "east"},
+	}
+
+	When("the remote endpoint advertises the per-pair derivation", func() {
+		It("should return the per-pair key", func() {
+			psk, err := w.peerPSK(&v1.EndpointSpec{
+				ClusterID:     "west",
+				BackendConfig: map[string]string{PSKDerivation: pskDerivationPair},
+			})
+			Expect(err).To(Succeed())
+			Expect(pairPSK("secret", "pair-secret", "west", "east")).To(Equal(psk))
+		})
+	})
+
+	When("the remote endpoint doesn't advertise the per-pair derivation", func() {
+		It("should return the key derived from the configured PSK alone", func() {
+			psk, err := w.peerPSK(&v1.EndpointSpec{ClusterID: "west", BackendConfig: map[string]string{}})
+			Expect(err).To(Succeed())
+			Expect(legacyPSK("secret")).To(Equal(psk))
+		})
+	})
+})
+
+var _ = Describe("allHandshakesSince", func() {
+	var (
+		w         *wireguard
+		peerKey   wgtypes.Key
+		rotatedAt time.Time
+	)
+
+	BeforeEach(func() {
+		privateKey, err := wgtypes.GeneratePrivateKey()
+		Expect(err).To(Succeed())
+
+		peerKey = privateKey.PublicKey()
+		rotatedAt = time.Now()
+
+		w = &wireguard{connections: map[string]*v1.Connection{
+			"west": {Endpoint: v1.EndpointSpec{BackendConfig: map[string]string{PublicKey: peerKey.String()}}},
+		}}
+	})
+
+	It("should return true only once the peers completed a handshake after the given time", func() {
+		Expect(w.allHandshakesSince(nil, rotatedAt)).To(BeFalse())
+		Expect(w.allHandshakesSince([]wgtypes.Peer{{PublicKey: peerKey, LastHandshakeTime: rotatedAt.Add(-time.Second)}},
+			rotatedAt)).To(BeFalse())
+		Expect(w.allHandshakesSince([]wgtypes.Peer{{PublicKey: peerKey, LastHandshakeTime: rotatedAt.Add(time.Second)}},
+			rotatedAt)).To(BeTrue())
+	})
+})
@@ -0,0 +1,616 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfrm
+
+import (
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cable"
+	"github.com/submariner-io/submariner/pkg/endpoint"
+	"github.com/submariner-io/submariner/pkg/ikev2"
+	"github.com/submariner-io/submariner/pkg/natdiscovery"
+	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
+	"github.com/submariner-io/submariner/pkg/types"
+	"github.com/vishvananda/netlink"
+	k8snet "k8s.io/utils/net"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	CableDriverName = "xfrm"
+	// DefaultPort is the UDP port used when the driver runs beside another Backend driver, which uses the shared UDP port,
+	// eg libreswan whose pluto binds it.
+	DefaultPort = 4550
+)
+
+var (
+	// RetransmitInterval is the interval at which IKE requests are retransmitted until a response is received.
+	RetransmitInterval = 2 * time.Second
+
+	// SALifetime is the age at which the initiator re-negotiates the SAs of a connection.
+	SALifetime = time.Hour
+
+	// LifetimeCheckInterval is the interval at which the lifetime of the SAs is checked.
+	LifetimeCheckInterval = 30 * time.Second
+)
+
+var logger = log.Logger{Logger: logf.Log.WithName("xfrm")}
+
+type specification struct {
+	PSK string
+}
+
+// TransportModeConfig configures a driver which, rather than tunnelling the traffic between the endpoint subnets, negotiates
+// ESP transport mode SAs protecting the UDP traffic another cable driver exchanges between the gateways, eg its encapsulated
+// traffic.
+type TransportModeConfig struct {
+	// DriverName is the name of the cable driver whose traffic is protected, under which the connections are reported.
+	DriverName string
+	// PSK is the pre-shared key with which the gateways authenticate each other.
+	PSK []byte
+	// Port is the UDP port on which IKE messages are exchanged and ESP packets are received.
+	Port int
+	// RemotePort returns the UDP port on which the remote endpoint exchanges IKE messages, as advertised.
+	RemotePort func(remote *v1.EndpointSpec) (int32, error)
+	// TrafficPort is the destination UDP port of the protected traffic received by the local gateway.
+	TrafficPort int
+	// RemoteTrafficPort returns the destination UDP port of the protected traffic sent to the remote endpoint.
+	RemoteTrafficPort func(remote *v1.EndpointSpec) (int32, error)
+}
+
+type connection struct {
+	v1.Connection
+	peer *ikev2.Peer
+	// remoteAddr is the address on which the remote gateway receives IKE and ESP packets.
+	remoteAddr *net.UDPAddr
+	localAddr  *net.UDPAddr
+	// remoteTrafficPort is the destination UDP port of the protected traffic in transport mode.
+	remoteTrafficPort int
+	// policies are the transport mode policies installed when connecting.
+	policies  []*netlink.XfrmPolicy
+	initiator *ikev2.Initiator
+	tunnel    *tunnel
+	// rekeying is set while the initiator re-negotiates the SAs of the established tunnel.
+	rekeying bool
+	stopCh   chan struct{}
+}
+
+type xfrmDriver struct {
+	name          string
+	localEndpoint v1.EndpointSpec
+	local         *endpoint.Local
+	psk           []byte
+	port          int
+	reqID         int
+	remotePort    func(remote *v1.EndpointSpec) (int32, error)
+	// transportMode is set when the driver protects the traffic of another cable driver.
+	transportMode *TransportModeConfig
+	netLink       netlinkAPI.Interface
+	responder     *ikev2.Responder
+	transport     *transport
+	mutex         sync.Mutex
+	connections   map[string]*connection
+}
+
+func init() {
+	cable.AddDriver(CableDriverName, NewDriver)
+	cable.SetDriverDefaultPort(CableDriverName, DefaultPort)
+}
+
+// NewDriver creates a new IPsec cable driver which negotiates the SAs itself, using IKEv2 with a pre-shared key, and
+// programs them via the kernel XFRM API.
+func NewDriver(localEndpoint *endpoint.Local, _ *types.SubmarinerCluster) (cable.Driver, error) {
+	// We'll panic if localEndpoint is nil, this is intentional
+	d := &xfrmDriver{
+		name:          CableDriverName,
+		localEndpoint: *localEndpoint.Spec(),
+		local:         localEndpoint,
+		reqID:         xfrmReqID,
+		netLink:       netlinkAPI.New(),
+		connections:   map[string]*connection{},
+	}
+
+	spec := specification{}
+	if err := envconfig.Process(cable.IPSecEnvPrefix, &spec); err != nil {
+		return nil, errors.Wrapf(err, "error processing environment config for %s", cable.IPSecEnvPrefix)
+	}
+
+	if spec.PSK == "" {
+		return nil, errors.New("the xfrm cable driver requires a pre-shared key")
+	}
+
+	d.psk = []byte(spec.PSK)
+
+	port, err := d.localEndpoint.GetCableDriverPort(CableDriverName, DefaultPort)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the UDP port configuration")
+	}
+
+	d.port = int(port)
+	d.remotePort = func(remote *v1.EndpointSpec) (int32, error) {
+		return remote.GetCableDriverPort(CableDriverName, DefaultPort)
+	}
+	d.responder = ikev2.NewResponder(d.psk, d.localEndpoint.CableName, d.peerFor)
+
+	return d, nil
+}
+
+// NewTransportModeDriver creates a driver which negotiates ESP transport mode SAs protecting the traffic of another cable
+// driver, as configured. The other driver delegates the connections to it and reports their status.
+func NewTransportModeDriver(localEndpoint *endpoint.Local, config *TransportModeConfig) cable.Driver {
+	// We'll panic if localEndpoint or config are nil, this is intentional
+	d := &xfrmDriver{
+		name:          config.DriverName,
+		localEndpoint: *localEndpoint.Spec(),
+		local:         localEndpoint,
+		psk:           config.PSK,
+		port:          config.Port,
+		reqID:         transportModeReqID,
+		remotePort:    config.RemotePort,
+		transportMode: config,
+		netLink:       netlinkAPI.New(),
+		connections:   map[string]*connection{},
+	}
+
+	d.responder = ikev2.NewResponder(d.psk, d.localEndpoint.CableName, d.peerFor)
+
+	return d
+}
+
+func (d *xfrmDriver) Init() error {
+	return bindTransport(d.port, d)
+}
+
+func (d *xfrmDriver) GetName() string {
+	return d.name
+}
+
+func (d *xfrmDriver) ConnectToEndpoint(endpointInfo *natdiscovery.NATEndpointInfo) (string, error) {
+	// We'll panic if endpointInfo is nil, this is intentional
+	remoteEndpoint := &endpointInfo.Endpoint
+	if d.localEndpoint.ClusterID == remoteEndpoint.Spec.ClusterID {
+		logger.V(log.DEBUG).Infof("Will not connect to self")
+		return "", nil
+	}
+
+	remoteIP := net.ParseIP(endpointInfo.UseIP)
+	if remoteIP == nil {
+		return "", errors.Errorf("failed to parse remote IP %q", endpointInfo.UseIP)
+	}
+
+	localIP := net.ParseIP(d.localEndpoint.GetPrivateIP(k8snet.IPFamilyOf(remoteIP)))
+	if localIP == nil {
+		return endpointInfo.UseIP, errors.Errorf("the local endpoint has no private IP of the same family as %s", remoteIP)
+	}
+
+	remotePort, err := d.remotePort(&remoteEndpoint.Spec)
+	if err != nil {
+		return endpointInfo.UseIP, errors.Wrap(err, "failed to get the remote UDP port configuration")
+	}
+
+	conn := &connection{
+		Connection: v1.Connection{
+			Endpoint: remoteEndpoint.Spec, Status: v1.Connecting,
+			UsingIP: endpointInfo.UseIP, UsingNAT: endpointInfo.UseNAT,
+		},
+		peer: &ikev2.Peer{
+			ID:       remoteEndpoint.Spec.CableName,
+			LocalTS:  parseSubnets(d.localSubnetsFor(&remoteEndpoint.Spec)),
+			RemoteTS: parseSubnets(remoteEndpoint.Spec.Subnets),
+		},
+		// The remote endpoint's NAT may map its advertised port.
+		remoteAddr: &net.UDPAddr{IP: remoteIP, Port: int(endpointInfo.RemotePort(remotePort))},
+		localAddr:  &net.UDPAddr{IP: localIP, Port: d.port},
+		stopCh:     make(chan struct{}),
+	}
+
+	if d.transportMode != nil {
+		if err := d.configureTransportMode(conn, &remoteEndpoint.Spec, localIP); err != nil {
+			return endpointInfo.UseIP, err
+		}
+	}
+
+	logger.V(log.DEBUG).Infof("Connecting cluster %s endpoint %s", remoteEndpoint.Spec.ClusterID, conn.remoteAddr)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if existing, ok := d.connections[conn.peer.ID]; ok {
+		d.removeConnection(existing)
+	}
+
+	if d.transportMode != nil {
+		// The policies are installed up front so the protected traffic is dropped, rather than sent in plaintext, until the
+		// SAs are negotiated.
+		conn.policies = d.newTunnel(conn, nil).policies()
+		if err := d.addPolicies(conn.policies); err != nil {
+			d.removePolicies(conn.policies)
+			return endpointInfo.UseIP, err
+		}
+	}
+
+	d.connections[conn.peer.ID] = conn
+
+	cable.RecordConnection(d.name, &d.localEndpoint, &conn.Endpoint, string(v1.Connecting), false)
+
+	// Only one side initiates the exchange, the other waits for the request.
+	if d.isInitiator(conn) {
+		go d.initiate(conn)
+	}
+
+	go d.monitorLifetime(conn)
+
+	return endpointInfo.UseIP, nil
+}
+
+// initiate sends the connection's pending IKE request, retransmitting it until the child SA is established, and does so
+// again whenever the SAs are re-negotiated.
+func (d *xfrmDriver) initiate(conn *connection) {
+	for {
+		d.mutex.Lock()
+
+		if d.transport.owner() != d {
+			d.mutex.Unlock()
+			return
+		}
+
+		if conn.tunnel == nil || conn.rekeying {
+			d.sendRequest(conn)
+		}
+
+		d.mutex.Unlock()
+
+		select {
+		case <-conn.stopCh:
+			return
+		case <-time.After(RetransmitInterval):
+		}
+	}
+}
+
+// sendRequest sends the pending IKE request of the connection, starting a new exchange if there isn't one. This must be
+// called with the mutex held.
+func (d *xfrmDriver) sendRequest(conn *connection) {
+	if conn.initiator == nil {
+		var err error
+
+		conn.initiator, err = ikev2.NewInitiator(d.psk, d.localEndpoint.CableName, conn.peer)
+		if err != nil {
+			logger.Errorf(err, "Error creating the IKE initiator for %q", conn.peer.ID)
+		}
+	}
+
+	if conn.initiator != nil {
+		d.transport.send(conn.initiator.Request(), conn.remoteAddr)
+	}
+}
+
+// localSubnetsFor returns the local subnets of the connection to the given remote endpoint. When transit routing is
+// enabled, the traffic between the remote cluster and the clusters reachable through the local gateway is forwarded so
+// their subnets are included.
+func (d *xfrmDriver) localSubnetsFor(remote *v1.EndpointSpec) []string {
+	subnets := slices.Clone(d.localEndpoint.Subnets)
+
+	for _, subnet := range d.local.Spec().TransitSubnetsExcluding(remote.ClusterID) {
+		if !slices.Contains(remote.Subnets, subnet) && !slices.Contains(subnets, subnet) {
+			subnets = append(subnets, subnet)
+		}
+	}
+
+	return subnets
+}
+
+// configureTransportMode sets up the connection to protect the traffic between the gateways. The traffic selectors are
+// their private IPs, which both know, as behind NAT they don't see the same addresses. The installed SAs and policies use
+// the addresses the gateways actually reach each other on.
+func (d *xfrmDriver) configureTransportMode(conn *connection, remote *v1.EndpointSpec, localIP net.IP) error {
+	port, err := d.transportMode.RemoteTrafficPort(remote)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the remote traffic port configuration")
+	}
+
+	remotePrivateIP := net.ParseIP(remote.GetPrivateIP(k8snet.IPFamilyOf(conn.remoteAddr.IP)))
+	if remotePrivateIP == nil {
+		return errors.Errorf("the remote endpoint has no private IP of the same family as %s", conn.remoteAddr.IP)
+	}
+
+	conn.remoteTrafficPort = int(port)
+	conn.peer.LocalTS = []*net.IPNet{hostNet(localIP)}
+	conn.peer.RemoteTS = []*net.IPNet{hostNet(remotePrivateIP)}
+
+	return nil
+}
+
+func (d *xfrmDriver) isInitiator(conn *connection) bool {
+	return d.localEndpoint.CableName < conn.peer.ID
+}
+
+// monitorLifetime periodically checks the lifetime of the connection's SAs. The initiator re-negotiates them once they
+// reach a soft limit. If the kernel removed them on reaching a hard limit, the connection is marked as failed until
+// they're re-negotiated.
+func (d *xfrmDriver) monitorLifetime(conn *connection) {
+	for {
+		select {
+		case <-conn.stopCh:
+			return
+		case <-time.After(LifetimeCheckInterval):
+		}
+
+		d.mutex.Lock()
+
+		if conn.tunnel != nil && !conn.rekeying {
+			expiring, expired := d.checkLifetime(conn.tunnel)
+
+			if expired && conn.Status != v1.ConnectionError {
+				logger.Warningf("The SAs of the tunnel to %q reached their hard lifetime", conn.peer.ID)
+
+				conn.Status = v1.ConnectionError
+				conn.StatusMessage = "The SAs reached their hard lifetime"
+
+				cable.RecordConnection(d.name, &d.localEndpoint, &conn.Endpoint, string(v1.ConnectionError), false)
+			}
+
+			if expiring && d.isInitiator(conn) {
+				logger.Infof("Re-negotiating the SAs of the tunnel to %q", conn.peer.ID)
+
+				conn.rekeying = true
+				conn.initiator = nil
+
+				d.sendRequest(conn)
+			}
+		}
+
+		d.mutex.Unlock()
+	}
+}
+
+// peerFor returns the Peer configuration for the remote gateway with the given ID, for the responder.
+func (d *xfrmDriver) peerFor(id string) *ikev2.Peer {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if conn, ok := d.connections[id]; ok {
+		return conn.peer
+	}
+
+	return nil
+}
+
+func (d *xfrmDriver) handleMessage(msg []byte, from *net.UDPAddr) {
+	spiI, _, isResponse, err := ikev2.SPIs(msg)
+	if err != nil {
+		logger.V(log.DEBUG).Infof("Ignoring invalid IKE message from %s: %v", from, err)
+		return
+	}
+
+	if isResponse {
+		d.handleResponse(msg, spiI, from)
+		return
+	}
+
+	response, child, peer, err := d.responder.HandleRequest(msg)
+	if response != nil {
+		d.transport.send(response, from)
+	}
+
+	if err != nil {
+		logger.Errorf(err, "Error handling the IKE request from %s", from)
+		return
+	}
+
+	if child == nil {
+		return
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	conn, ok := d.connections[peer.ID]
+	if !ok || conn.peer != peer {
+		return
+	}
+
+	// Use the address the request came from, which may have been translated by a NAT.
+	conn.remoteAddr = from
+	d.establish(conn, child)
+}
+
+func (d *xfrmDriver) handleResponse(msg []byte, spiI uint64, from *net.UDPAddr) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var conn *connection
+
+	for _, c := range d.connections {
+		if c.initiator != nil && c.initiator.SPI() == spiI {
+			conn = c
+			break
+		}
+	}
+
+	if conn == nil {
+		logger.V(log.DEBUG).Infof("Ignoring IKE response from %s for an unknown exchange", from)
+		return
+	}
+
+	child, err := conn.initiator.HandleResponse(msg)
+	if err != nil {
+		logger.Errorf(err, "Error handling the IKE response from %s", from)
+
+		// Start a new exchange on the next retransmission.
+		conn.initiator = nil
+
+		return
+	}
+
+	if child == nil {
+		d.transport.send(conn.initiator.Request(), conn.remoteAddr)
+		return
+	}
+
+	conn.initiator = nil
+	d.establish(conn, child)
+}
+
+// establish installs the negotiated child SA for the connection, replacing any previous one.
+func (d *xfrmDriver) establish(conn *connection, child *ikev2.ChildSA) {
+	if conn.tunnel != nil {
+		d.removeTunnel(conn.tunnel)
+	}
+
+	t := d.newTunnel(conn, child)
+
+	conn.rekeying = false
+
+	if err := d.installTunnel(t); err != nil {
+		logger.Errorf(err, "Error installing the tunnel to %q", conn.peer.ID)
+		d.removeTunnel(t)
+
+		conn.tunnel = nil
+		conn.Status = v1.ConnectionError
+		conn.StatusMessage = err.Error()
+
+		return
+	}
+
+	conn.tunnel = t
+	conn.Status = v1.Connected
+	conn.StatusMessage = ""
+
+	cable.RecordConnection(d.name, &d.localEndpoint, &conn.Endpoint, string(v1.Connected), true)
+
+	logger.Infof("Established the tunnel to %q at %s", conn.peer.ID, conn.remoteAddr)
+}
+
+func (d *xfrmDriver) newTunnel(conn *connection, child *ikev2.ChildSA) *tunnel {
+	t := &tunnel{
+		localAddr:   conn.localAddr,
+		remoteAddr:  conn.remoteAddr,
+		localTS:     conn.peer.LocalTS,
+		remoteTS:    conn.peer.RemoteTS,
+		reqID:       d.reqID,
+		child:       child,
+		established: time.Now(),
+	}
+
+	if d.transportMode != nil {
+		t.localTrafficPort = d.transportMode.TrafficPort
+		t.remoteTrafficPort = conn.remoteTrafficPort
+	}
+
+	return t
+}
+
+func (d *xfrmDriver) DisconnectFromEndpoint(remoteEndpoint *types.SubmarinerEndpoint) error {
+	// We'll panic if remoteEndpoint is nil, this is intentional
+	logger.V(log.DEBUG).Infof("Removing endpoint %#v", remoteEndpoint)
+
+	if d.localEndpoint.ClusterID == remoteEndpoint.Spec.ClusterID {
+		logger.V(log.DEBUG).Infof("Will not disconnect self")
+		return nil
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	conn, ok := d.connections[remoteEndpoint.Spec.CableName]
+	if !ok {
+		logger.Errorf(nil, "Cannot disconnect remote endpoint %q - no prior connection entry found", remoteEndpoint.Spec.CableName)
+		return nil
+	}
+
+	d.removeConnection(conn)
+	cable.RecordDisconnected(d.name, &d.localEndpoint, &remoteEndpoint.Spec)
+
+	logger.V(log.DEBUG).Infof("Done removing endpoint for cluster %s", remoteEndpoint.Spec.ClusterID)
+
+	return nil
+}
+
+func (d *xfrmDriver) removeConnection(conn *connection) {
+	close(conn.stopCh)
+
+	if conn.tunnel != nil {
+		d.removeTunnel(conn.tunnel)
+
+		if d.transportMode != nil {
+			d.removePolicies(conn.tunnel.policies())
+		}
+	}
+
+	d.removePolicies(conn.policies)
+	delete(d.connections, conn.peer.ID)
+}
+
+func (d *xfrmDriver) GetConnections() ([]v1.Connection, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	connections := make([]v1.Connection, 0, len(d.connections))
+	for _, conn := range d.connections {
+		connections = append(connections, *conn.Connection.DeepCopy())
+	}
+
+	return connections, nil
+}
+
+func (d *xfrmDriver) GetActiveConnections() ([]v1.Connection, error) {
+	connections, err := d.GetConnections()
+
+	active := connections[:0]
+
+	for i := range connections {
+		if connections[i].Status == v1.Connected {
+			active = append(active, connections[i])
+		}
+	}
+
+	return active, err
+}
+
+func (d *xfrmDriver) Cleanup() error {
+	logger.Infof("Uninstalling the %s XFRM states and policies", d.name)
+
+	return d.cleanupXfrm()
+}
+
+// Parse CIDR strings and skip errors.
+func parseSubnets(subnets []string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(subnets))
+
+	for _, sn := range subnets {
+		_, cidr, err := net.ParseCIDR(sn)
+		if err != nil {
+			// this should not happen. Log and continue
+			logger.Errorf(err, "Failed to parse subnet %s", sn)
+			continue
+		}
+
+		nets = append(nets, cidr)
+	}
+
+	return nets
+}
@@ -0,0 +1,361 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfrm_test
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	subv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cable"
+	"github.com/submariner-io/submariner/pkg/cable/xfrm"
+	"github.com/submariner-io/submariner/pkg/endpoint"
+	"github.com/submariner-io/submariner/pkg/ikev2"
+	"github.com/submariner-io/submariner/pkg/natdiscovery"
+	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
+	fakeNetlink "github.com/submariner-io/submariner/pkg/netlink/fake"
+	"github.com/submariner-io/submariner/pkg/types"
+	"github.com/vishvananda/netlink"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	psk      = "secret"
+	loopback = "127.0.0.1"
+)
+
+var _ = Describe("Driver", func() {
+	var (
+		east *testGateway
+		west *testGateway
+	)
+
+	BeforeEach(func() {
+		os.Setenv("CE_IPSEC_PSK", psk)
+
+		DeferCleanup(func() {
+			os.Unsetenv("CE_IPSEC_PSK")
+		})
+
+		east = newTestGateway("east", []string{"10.0.0.0/16", "11.0.0.0/16"})
+		west = newTestGateway("west", []string{"20.0.0.0/16", "fd00:20::/64"})
+	})
+
+	When("both gateways connect to each other", func() {
+		It("should establish the tunnel and program the XFRM states and policies", func() {
+			east.connectTo(west)
+			west.connectTo(east)
+
+			east.awaitConnectionStatus(subv1.Connected)
+			west.awaitConnectionStatus(subv1.Connected)
+
+			// Only the IPv4 subnet pairs are tunneled, with an outbound, inbound and forward policy each.
+			east.netLink.AwaitXfrmPolicies(6)
+			west.netLink.AwaitXfrmPolicies(6)
+
+			eastStates, _ := east.netLink.XfrmStateList(netlink.FAMILY_ALL)
+			Expect(eastStates).To(HaveLen(2))
+
+			westStates, _ := west.netLink.XfrmStateList(netlink.FAMILY_ALL)
+			Expect(westStates).To(HaveLen(2))
+
+			for i := range eastStates {
+				Expect(eastStates[i].Mode).To(Equal(netlink.XFRM_MODE_TUNNEL))
+				Expect(eastStates[i].Aead.Key).To(HaveLen(ikev2.KeyLength))
+				Expect(eastStates[i].Encap.Type).To(Equal(netlink.XFRM_ENCAP_ESPINUDP))
+
+				// Each of east's SAs has a counterpart on west with the same SPI, key and ports.
+				peer := findState(westStates, eastStates[i].Spi)
+				Expect(peer).ToNot(BeNil(), "No matching state for SPI %d", eastStates[i].Spi)
+				Expect(peer.Aead.Key).To(Equal(eastStates[i].Aead.Key))
+				Expect(peer.Encap.SrcPort).To(Equal(eastStates[i].Encap.SrcPort))
+				Expect(peer.Encap.DstPort).To(Equal(eastStates[i].Encap.DstPort))
+			}
+
+			active, err := east.driver.GetActiveConnections()
+			Expect(err).To(Succeed())
+			Expect(active).To(HaveLen(1))
+		})
+
+		Context("and one then disconnects", func() {
+			It("should remove its XFRM states and policies", func() {
+				east.connectTo(west)
+				west.connectTo(east)
+				east.awaitConnectionStatus(subv1.Connected)
+
+				Expect(east.driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: west.endpoint})).To(Succeed())
+
+				east.netLink.AwaitNoXfrmStates()
+				east.netLink.AwaitXfrmPolicies(0)
+
+				connections, err := east.driver.GetConnections()
+				Expect(err).To(Succeed())
+				Expect(connections).To(BeEmpty())
+			})
+		})
+	})
+
+	When("the local gateway forwards the traffic of transit clusters", func() {
+		BeforeEach(func() {
+			east = newTestGateway("east", []string{"10.0.0.0/16"}, subv1.TransitRoute{
+				ClusterID: "north",
+				Subnets:   []string{"30.0.0.0/16"},
+				Path:      []string{"local"},
+			})
+		})
+
+		It("should add the transit subnets to the local traffic selectors", func() {
+			east.connectTo(west)
+
+			// The remote gateway routes the transit subnets through the local gateway, as they're added to its Endpoint.
+			augmented := east.endpoint.DeepCopy()
+			augmented.Subnets = append(augmented.Subnets, "30.0.0.0/16")
+			west.connectToEndpoint(augmented)
+
+			east.awaitConnectionStatus(subv1.Connected)
+			west.awaitConnectionStatus(subv1.Connected)
+
+			policies := east.netLink.AwaitXfrmPolicies(6)
+			west.netLink.AwaitXfrmPolicies(6)
+
+			Expect(policies).To(ContainElement(SatisfyAll(
+				HaveField("Src.String()", "30.0.0.0/16"),
+				HaveField("Dst.String()", "20.0.0.0/16"),
+				HaveField("Dir", netlink.XFRM_DIR_OUT))))
+		})
+	})
+
+	When("the SAs reach their lifetime", func() {
+		BeforeEach(func() {
+			prevLifetime, prevInterval := xfrm.SALifetime, xfrm.LifetimeCheckInterval
+			xfrm.SALifetime = 500 * time.Millisecond
+			xfrm.LifetimeCheckInterval = 100 * time.Millisecond
+
+			DeferCleanup(func() {
+				xfrm.SALifetime, xfrm.LifetimeCheckInterval = prevLifetime, prevInterval
+			})
+		})
+
+		It("should re-negotiate them", func() {
+			east.connectTo(west)
+			west.connectTo(east)
+			east.awaitConnectionStatus(subv1.Connected)
+
+			spis := stateSPIs(east.netLink)
+
+			Eventually(func() []int {
+				return stateSPIs(east.netLink)
+			}, 5).Should(SatisfyAll(HaveLen(2), Not(ContainElements(spis))))
+
+			Eventually(func() []int {
+				return stateSPIs(west.netLink)
+			}, 5).Should(SatisfyAll(HaveLen(2), Not(ContainElements(spis))))
+
+			east.awaitConnectionStatus(subv1.Connected)
+			west.awaitConnectionStatus(subv1.Connected)
+		})
+	})
+
+	When("the kernel removes the SAs on reaching their hard lifetime", func() {
+		BeforeEach(func() {
+			prevInterval := xfrm.LifetimeCheckInterval
+			xfrm.LifetimeCheckInterval = 100 * time.Millisecond
+
+			DeferCleanup(func() {
+				xfrm.LifetimeCheckInterval = prevInterval
+			})
+		})
+
+		It("should re-negotiate them", func() {
+			east.connectTo(west)
+			west.connectTo(east)
+			east.awaitConnectionStatus(subv1.Connected)
+			west.awaitConnectionStatus(subv1.Connected)
+
+			spis := stateSPIs(east.netLink)
+
+			states, _ := east.netLink.XfrmStateList(netlink.FAMILY_ALL)
+			for i := range states {
+				Expect(east.netLink.XfrmStateDel(&states[i])).To(Succeed())
+			}
+
+			Eventually(func() []int {
+				return stateSPIs(east.netLink)
+			}, 5).Should(SatisfyAll(HaveLen(2), Not(ContainElements(spis))))
+
+			east.awaitConnectionStatus(subv1.Connected)
+		})
+	})
+
+	When("only the initiating gateway connects", func() {
+		It("should remain connecting", func() {
+			east.connectTo(west)
+
+			Consistently(func() subv1.ConnectionStatus {
+				return east.connectionStatus()
+			}, 500*time.Millisecond).Should(Equal(subv1.Connecting))
+
+			east.netLink.AwaitNoXfrmStates()
+		})
+	})
+
+	When("the pre-shared keys differ", func() {
+		BeforeEach(func() {
+			os.Setenv("CE_IPSEC_PSK", "other")
+			west = newTestGateway("west", []string{"20.0.0.0/16"})
+		})
+
+		It("should not establish the tunnel", func() {
+			east.connectTo(west)
+			west.connectTo(east)
+
+			Consistently(func() subv1.ConnectionStatus {
+				return west.connectionStatus()
+			}, 500*time.Millisecond).Should(Equal(subv1.Connecting))
+
+			east.netLink.AwaitNoXfrmStates()
+			west.netLink.AwaitNoXfrmStates()
+		})
+	})
+
+	Specify("Cleanup should remove the XFRM states and policies", func() {
+		east.connectTo(west)
+		west.connectTo(east)
+		east.netLink.AwaitXfrmPolicies(6)
+
+		Expect(east.driver.Cleanup()).To(Succeed())
+
+		east.netLink.AwaitNoXfrmStates()
+		east.netLink.AwaitXfrmPolicies(0)
+	})
+
+	When("no pre-shared key is configured", func() {
+		It("should fail to create the driver", func() {
+			os.Unsetenv("CE_IPSEC_PSK")
+
+			_, err := xfrm.NewDriver(endpoint.NewLocal(&east.endpoint, dynamicfake.NewSimpleDynamicClient(scheme.Scheme), ""),
+				&types.SubmarinerCluster{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+type testGateway struct {
+	endpoint subv1.EndpointSpec
+	netLink  *fakeNetlink.NetLink
+	driver   cable.Driver
+}
+
+func newTestGateway(clusterID string, subnets []string, transitRoutes ...subv1.TransitRoute) *testGateway {
+	t := &testGateway{
+		endpoint: subv1.EndpointSpec{
+			ClusterID:     clusterID,
+			CableName:     "submariner-cable-" + clusterID,
+			PrivateIP:     loopback,
+			Subnets:       subnets,
+			Backend:       xfrm.CableDriverName,
+			BackendConfig: map[string]string{subv1.UDPPortConfig: strconv.Itoa(freeUDPPort())},
+			TransitRoutes: transitRoutes,
+		},
+		netLink: fakeNetlink.New(),
+	}
+
+	netlinkAPI.NewFunc = func() netlinkAPI.Interface {
+		return t.netLink
+	}
+
+	DeferCleanup(func() {
+		netlinkAPI.NewFunc = nil
+	})
+
+	var err error
+
+	t.driver, err = xfrm.NewDriver(endpoint.NewLocal(&t.endpoint, dynamicfake.NewSimpleDynamicClient(scheme.Scheme), ""),
+		&types.SubmarinerCluster{})
+	Expect(err).To(Succeed())
+
+	Expect(t.driver.Init()).To(Succeed())
+	Expect(t.driver.GetName()).To(Equal(xfrm.CableDriverName))
+
+	return t
+}
+
+func (t *testGateway) connectTo(other *testGateway) {
+	t.connectToEndpoint(&other.endpoint)
+}
+
+func (t *testGateway) connectToEndpoint(spec *subv1.EndpointSpec) {
+	ip, err := t.driver.ConnectToEndpoint(&natdiscovery.NATEndpointInfo{
+		Endpoint: subv1.Endpoint{Spec: *spec},
+		UseIP:    loopback,
+	})
+	Expect(err).To(Succeed())
+	Expect(ip).To(Equal(loopback))
+
+	DeferCleanup(func() {
+		_ = t.driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: *spec})
+	})
+}
+
+func (t *testGateway) connectionStatus() subv1.ConnectionStatus {
+	connections, err := t.driver.GetConnections()
+	Expect(err).To(Succeed())
+	Expect(connections).To(HaveLen(1))
+
+	return connections[0].Status
+}
+
+func (t *testGateway) awaitConnectionStatus(status subv1.ConnectionStatus) {
+	Eventually(t.connectionStatus, 5).Should(Equal(status))
+}
+
+func stateSPIs(netLink *fakeNetlink.NetLink) []int {
+	states, _ := netLink.XfrmStateList(netlink.FAMILY_ALL)
+
+	spis := make([]int, len(states))
+	for i := range states {
+		spis[i] = states[i].Spi
+	}
+
+	return spis
+}
+
+func findState(states []netlink.XfrmState, spi int) *netlink.XfrmState {
+	for i := range states {
+		if states[i].Spi == spi {
+			return &states[i]
+		}
+	}
+
+	return nil
+}
+
+func freeUDPPort() int {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{})
+	Expect(err).To(Succeed())
+
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
@@ -0,0 +1,140 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfrm
+
+import (
+	"bytes"
+	"net"
+	"sync"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const maxMessageSize = 65535
+
+// The non-ESP marker prefixed to IKE messages sent over the NAT-T port, so the kernel can tell them from ESP packets (RFC 3948).
+var nonESPMarker = []byte{0, 0, 0, 0}
+
+// transport is the UDP socket on which IKE messages are exchanged and ESP packets are received. Drivers are re-created when
+// the cable engine restarts, so the socket is shared by all the driver instances using a given port and only the most
+// recent instance receives the messages.
+type transport struct {
+	mutex  sync.Mutex
+	conn   *net.UDPConn
+	driver *xfrmDriver
+}
+
+var (
+	transportsMutex sync.Mutex
+	transports      = map[int]*transport{}
+)
+
+// bindTransport binds the driver to the transport for the given port, creating it if needed, so the driver receives its messages.
+func bindTransport(port int, driver *xfrmDriver) error {
+	transportsMutex.Lock()
+	defer transportsMutex.Unlock()
+
+	t, ok := transports[port]
+	if !ok {
+		conn, err := net.ListenUDP("udp", &net.UDPAddr{Port: port})
+		if err != nil {
+			return errors.Wrapf(err, "error listening on UDP port %d", port)
+		}
+
+		if err := enableESPInUDP(conn); err != nil {
+			logger.Warningf("Unable to enable ESP in UDP decapsulation on port %d, NAT traversal won't work: %v", port, err)
+		}
+
+		t = &transport{conn: conn}
+		transports[port] = t
+
+		go t.run()
+	}
+
+	driver.transport = t
+
+	t.mutex.Lock()
+	t.driver = driver
+	t.mutex.Unlock()
+
+	return nil
+}
+
+// enableESPInUDP asks the kernel to decapsulate the ESP packets received on the socket. IKE messages, which start with the
+// non-ESP marker, are still delivered to the socket.
+func enableESPInUDP(conn *net.UDPConn) error {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return errors.Wrap(err, "error accessing the raw connection")
+	}
+
+	var sockErr error
+
+	err = rawConn.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptInt(int(fd), syscall.IPPROTO_UDP, unix.UDP_ENCAP, unix.UDP_ENCAP_ESPINUDP)
+	})
+	if err != nil {
+		return errors.Wrap(err, "error controlling the raw connection")
+	}
+
+	return errors.Wrap(sockErr, "error setting UDP_ENCAP")
+}
+
+func (t *transport) owner() *xfrmDriver {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.driver
+}
+
+func (t *transport) run() {
+	buf := make([]byte, maxMessageSize)
+
+	for {
+		n, from, err := t.conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			logger.Errorf(err, "Error reading from the IKE socket")
+
+			continue
+		}
+
+		if n <= len(nonESPMarker) || !bytes.Equal(buf[:len(nonESPMarker)], nonESPMarker) {
+			continue
+		}
+
+		msg := bytes.Clone(buf[len(nonESPMarker):n])
+
+		if driver := t.owner(); driver != nil {
+			driver.handleMessage(msg, from)
+		}
+	}
+}
+
+func (t *transport) send(msg []byte, to *net.UDPAddr) {
+	_, err := t.conn.WriteToUDP(append(bytes.Clone(nonESPMarker), msg...), to)
+	if err != nil {
+		logger.Errorf(err, "Error sending IKE message to %s", to)
+	}
+}
@@ -0,0 +1,271 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfrm
+
+import (
+	"math"
+	"net"
+	"os"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/submariner/pkg/ikev2"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// The reqids mark the XFRM states and policies owned by the driver, and by the transport mode drivers protecting the
+	// traffic of other cable drivers.
+	xfrmReqID          = 0x5ab6e0f
+	transportModeReqID = 0x5ab6e0e
+
+	espAlgorithm = "rfc4106(gcm(aes))"
+
+	// Extended sequence numbers aren't negotiated so the kernel removes the SAs before their sequence numbers wrap. The
+	// initiator re-negotiates the SAs well before, once the soft limit is reached.
+	saPacketSoftLimit = 1 << 31
+	saPacketHardLimit = math.MaxUint32 - 1<<16
+)
+
+// tunnel holds the addresses and child SA of an established connection, from which its XFRM states and policies are derived.
+type tunnel struct {
+	localAddr  *net.UDPAddr
+	remoteAddr *net.UDPAddr
+	localTS    []*net.IPNet
+	remoteTS   []*net.IPNet
+	// localTrafficPort and remoteTrafficPort are the destination UDP ports of the traffic protected in transport mode, in
+	// which case they're set.
+	localTrafficPort  int
+	remoteTrafficPort int
+	reqID             int
+	child             *ikev2.ChildSA
+	// established is when the child SA was installed, from which its lifetime is measured.
+	established time.Time
+}
+
+func (t *tunnel) mode() netlink.Mode {
+	if t.remoteTrafficPort != 0 {
+		return netlink.XFRM_MODE_TRANSPORT
+	}
+
+	return netlink.XFRM_MODE_TUNNEL
+}
+
+func (t *tunnel) newXfrmState(spi uint32, key []byte, src, dst *net.UDPAddr) *netlink.XfrmState {
+	return &netlink.XfrmState{
+		Src:          src.IP,
+		Dst:          dst.IP,
+		Proto:        netlink.XFRM_PROTO_ESP,
+		Mode:         t.mode(),
+		Spi:          int(spi),
+		Reqid:        t.reqID,
+		ReplayWindow: 32,
+		// The time limits are a safety net should the SAs not be re-negotiated at the end of their lifetime.
+		Limits: netlink.XfrmStateLimits{
+			PacketSoft: saPacketSoftLimit,
+			PacketHard: saPacketHardLimit,
+			TimeSoft:   uint64(SALifetime.Seconds()),
+			TimeHard:   uint64((SALifetime * 2).Seconds()),
+		},
+		Aead: &netlink.XfrmStateAlgo{
+			Name:   espAlgorithm,
+			Key:    key,
+			ICVLen: ikev2.ICVBits,
+		},
+		Encap: &netlink.XfrmStateEncap{
+			Type:            netlink.XFRM_ENCAP_ESPINUDP,
+			SrcPort:         src.Port,
+			DstPort:         dst.Port,
+			OriginalAddress: net.IPv4zero,
+		},
+	}
+}
+
+func (t *tunnel) states() []*netlink.XfrmState {
+	return []*netlink.XfrmState{
+		t.newXfrmState(t.child.OutboundSPI, t.child.OutboundKey, t.localAddr, t.remoteAddr),
+		t.newXfrmState(t.child.InboundSPI, t.child.InboundKey, t.remoteAddr, t.localAddr),
+	}
+}
+
+func (t *tunnel) newXfrmPolicy(src, dst *net.IPNet, dir netlink.Dir, tmplSrc, tmplDst net.IP) *netlink.XfrmPolicy {
+	return &netlink.XfrmPolicy{
+		Src: src,
+		Dst: dst,
+		Dir: dir,
+		Tmpls: []netlink.XfrmPolicyTmpl{{
+			Src:   tmplSrc,
+			Dst:   tmplDst,
+			Proto: netlink.XFRM_PROTO_ESP,
+			Mode:  t.mode(),
+			Reqid: t.reqID,
+		}},
+	}
+}
+
+// policies returns the outbound, inbound and forward policies for each pair of local and remote subnets of the same family.
+// In transport mode, they return the outbound and inbound policies for the protected traffic between the gateways instead.
+func (t *tunnel) policies() []*netlink.XfrmPolicy {
+	var policies []*netlink.XfrmPolicy
+
+	localIP, remoteIP := t.localAddr.IP, t.remoteAddr.IP
+
+	if t.mode() == netlink.XFRM_MODE_TRANSPORT {
+		return []*netlink.XfrmPolicy{
+			t.newTrafficPolicy(localIP, remoteIP, t.remoteTrafficPort, netlink.XFRM_DIR_OUT),
+			t.newTrafficPolicy(remoteIP, localIP, t.localTrafficPort, netlink.XFRM_DIR_IN),
+		}
+	}
+
+	for _, local := range t.localTS {
+		for _, remote := range t.remoteTS {
+			if (local.IP.To4() == nil) != (remote.IP.To4() == nil) {
+				continue
+			}
+
+			policies = append(policies,
+				t.newXfrmPolicy(local, remote, netlink.XFRM_DIR_OUT, localIP, remoteIP),
+				t.newXfrmPolicy(remote, local, netlink.XFRM_DIR_IN, remoteIP, localIP),
+				t.newXfrmPolicy(remote, local, netlink.XFRM_DIR_FWD, remoteIP, localIP))
+		}
+	}
+
+	return policies
+}
+
+// newTrafficPolicy returns the policy selecting the protected UDP traffic from src to the given port on dst. The IKE messages
+// and ESP packets are exchanged on another port so they aren't selected. The inbound policy drops plaintext traffic.
+func (t *tunnel) newTrafficPolicy(src, dst net.IP, port int, dir netlink.Dir) *netlink.XfrmPolicy {
+	policy := t.newXfrmPolicy(hostNet(src), hostNet(dst), dir, src, dst)
+	policy.Proto = netlink.Proto(syscall.IPPROTO_UDP)
+	policy.DstPort = port
+
+	return policy
+}
+
+func hostNet(ip net.IP) *net.IPNet {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}
+}
+
+func (d *xfrmDriver) installTunnel(t *tunnel) error {
+	for _, state := range t.states() {
+		if err := d.netLink.XfrmStateAdd(state); err != nil && !os.IsExist(err) {
+			return errors.Wrapf(err, "error adding the XFRM state from %s to %s", state.Src, state.Dst)
+		}
+	}
+
+	return d.addPolicies(t.policies())
+}
+
+func (d *xfrmDriver) addPolicies(policies []*netlink.XfrmPolicy) error {
+	for _, policy := range policies {
+		if err := d.netLink.XfrmPolicyAdd(policy); err != nil && !os.IsExist(err) {
+			return errors.Wrapf(err, "error adding the XFRM policy from %s to %s", policy.Src, policy.Dst)
+		}
+	}
+
+	return nil
+}
+
+// checkLifetime returns whether the tunnel's SAs reached a soft limit and should be re-negotiated, and whether they
+// reached a hard limit and were removed by the kernel.
+func (d *xfrmDriver) checkLifetime(t *tunnel) (bool, bool) {
+	expiring := time.Since(t.established) >= SALifetime
+
+	installed, err := d.netLink.XfrmStateList(netlink.FAMILY_ALL)
+	if err != nil {
+		logger.Errorf(err, "Error listing the XFRM states")
+		return expiring, false
+	}
+
+	for _, state := range t.states() {
+		i := slices.IndexFunc(installed, func(s netlink.XfrmState) bool {
+			return s.Spi == state.Spi && s.Dst.Equal(state.Dst)
+		})
+
+		if i < 0 {
+			return true, true
+		}
+
+		if installed[i].Statistics.Packets >= saPacketSoftLimit {
+			expiring = true
+		}
+	}
+
+	return expiring, false
+}
+
+// removeTunnel removes the tunnel's states and, in tunnel mode, its policies. In transport mode, the policies are kept until
+// the connection is removed so the protected traffic isn't sent in plaintext while the SAs are re-negotiated.
+func (d *xfrmDriver) removeTunnel(t *tunnel) {
+	if t.mode() == netlink.XFRM_MODE_TUNNEL {
+		d.removePolicies(t.policies())
+	}
+
+	for _, state := range t.states() {
+		if err := d.netLink.XfrmStateDel(state); err != nil && !os.IsNotExist(err) {
+			logger.Errorf(err, "Error deleting the XFRM state from %s to %s", state.Src, state.Dst)
+		}
+	}
+}
+
+func (d *xfrmDriver) removePolicies(policies []*netlink.XfrmPolicy) {
+	for _, policy := range policies {
+		if err := d.netLink.XfrmPolicyDel(policy); err != nil && !os.IsNotExist(err) {
+			logger.Errorf(err, "Error deleting the XFRM policy from %s to %s", policy.Src, policy.Dst)
+		}
+	}
+}
+
+// cleanupXfrm removes all the XFRM policies and states owned by this driver, ie with its reqid.
+func (d *xfrmDriver) cleanupXfrm() error {
+	policies, err := d.netLink.XfrmPolicyList(netlink.FAMILY_ALL)
+	if err != nil {
+		return errors.Wrap(err, "error listing the XFRM policies")
+	}
+
+	for i := range policies {
+		if len(policies[i].Tmpls) > 0 && policies[i].Tmpls[0].Reqid == d.reqID {
+			if err := d.netLink.XfrmPolicyDel(&policies[i]); err != nil {
+				logger.Errorf(err, "Error deleting XFRM policy %s", policies[i])
+			}
+		}
+	}
+
+	states, err := d.netLink.XfrmStateList(netlink.FAMILY_ALL)
+	if err != nil {
+		return errors.Wrap(err, "error listing the XFRM states")
+	}
+
+	for i := range states {
+		if states[i].Reqid == d.reqID {
+			if err := d.netLink.XfrmStateDel(&states[i]); err != nil {
+				logger.Errorf(err, "Error deleting XFRM state %s", states[i])
+			}
+		}
+	}
+
+	return nil
+}
@@ -21,6 +21,7 @@ package cableengine
 //nolint:gci // The supported driver imports are kept separate.
 import (
 	"reflect"
+	"slices"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -31,18 +32,23 @@ import (
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
 	"github.com/submariner-io/submariner/pkg/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	// Add supported drivers.
+	_ "github.com/submariner-io/submariner/pkg/cable/geneve"
 	_ "github.com/submariner-io/submariner/pkg/cable/libreswan"
 	_ "github.com/submariner-io/submariner/pkg/cable/vxlan"
 	_ "github.com/submariner-io/submariner/pkg/cable/wireguard"
+	_ "github.com/submariner-io/submariner/pkg/cable/xfrm"
 )
 
 // Engine represents an implementation of some remote connectivity mechanism, such as
 // a VPN gateway.
-// An Engine cooperates with, and delegates work to, a cable.Driver for implementing
-// a secure connection to remote clusters.
+// An Engine cooperates with, and delegates work to, one or more cable.Drivers for implementing
+// a secure connection to remote clusters. The driver used for each remote cluster is determined by
+// cable.GetDriverNameFor and a remote cluster for which no driver can be agreed on is reported as a connection error.
 type Engine interface {
 	// StartEngine performs any general set up work needed independent of any remote connections.
 	StartEngine() error
@@ -62,6 +68,9 @@ type Engine interface {
 	GetHAStatus() v1.HAStatus
 	// SetupNATDiscovery configures the handler for nat discovery of the endpoints.
 	SetupNATDiscovery(natDiscovery natdiscovery.Interface)
+	// ReconnectCable disconnects the installed cable with the given name and connects it again, optionally re-running
+	// NAT discovery for the remote endpoint first. ErrCableNotInstalled is returned if the cable isn't known.
+	ReconnectCable(cableName string, rediscoverNAT bool) error
 
 	// Cleanup performs the necessary steps to uninstall the cable driver.
 	Cleanup() error
@@ -69,7 +78,8 @@ type Engine interface {
 
 type engine struct {
 	sync.Mutex
-	driver              cable.Driver
+	drivers             map[string]cable.Driver
+	cableDrivers        map[string]cable.Driver
 	running             bool
 	localCluster        types.SubmarinerCluster
 	localEndpoint       *submendpoint.Local
@@ -77,10 +87,22 @@ type engine struct {
 	natEndpointInfoCh   chan *natdiscovery.NATEndpointInfo
 	natDiscoveryPending map[string]int
 	installedCables     map[string]metav1.Time
+	// installedEndpoints retains the NAT info of the installed cables so they can be reconnected.
+	installedEndpoints map[string]*natdiscovery.NATEndpointInfo
+	// driverErrors tracks the remote endpoints for which no cable driver could be agreed on, by cable name.
+	driverErrors map[string]*v1.Connection
+	// pairedEndpoints tracks the discovered endpoints of the remote clusters whose cables are paired, because either
+	// cluster runs active-active gateways, by cluster ID and cable name. A cable is installed to the endpoint paired
+	// with the local gateway, if any.
+	pairedEndpoints map[string]map[string]*natdiscovery.NATEndpointInfo
+	// localActiveActive tracks the cable names of the other gateways of the local cluster in active-active mode.
+	localActiveActive sets.Set[string]
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("CableEngine")}
 
+var ErrCableNotInstalled = errors.New("cable is not installed")
+
 // NewEngine creates a new Engine for the local cluster.
 func NewEngine(localCluster *types.SubmarinerCluster, localEndpoint *submendpoint.Local) Engine {
 	// We'll panic if localCluster or localEndpoint are nil, this is intentional
@@ -89,6 +111,13 @@ func NewEngine(localCluster *types.SubmarinerCluster, localEndpoint *submendpoin
 		localEndpoint:       localEndpoint,
 		natDiscoveryPending: map[string]int{},
 		installedCables:     map[string]metav1.Time{},
+		installedEndpoints:  map[string]*natdiscovery.NATEndpointInfo{},
+		driverErrors:        map[string]*v1.Connection{},
+		drivers:             map[string]cable.Driver{},
+		cableDrivers:        map[string]cable.Driver{},
+
+		pairedEndpoints:   map[string]map[string]*natdiscovery.NATEndpointInfo{},
+		localActiveActive: sets.New[string](),
 	}
 }
 
@@ -102,13 +131,13 @@ func (i *engine) StartEngine() error {
 	i.Lock()
 	defer i.Unlock()
 
-	if err := i.startDriver(); err != nil {
+	if err := i.startDrivers(); err != nil {
 		return err
 	}
 
 	i.running = true
 
-	logger.Infof("CableEngine started with driver %q", i.driver.GetName())
+	logger.Infof("CableEngine started with driver(s) %q", cable.GetDriverNames(i.localEndpoint.Spec()))
 
 	return nil
 }
@@ -122,18 +151,53 @@ func (i *engine) Stop() {
 	logger.Info("CableEngine stopped")
 }
 
-func (i *engine) startDriver() error {
-	if i.driver != nil {
-		return nil
+func (i *engine) startDrivers() error {
+	for _, name := range cable.GetDriverNames(i.localEndpoint.Spec()) {
+		if _, ok := i.drivers[name]; ok {
+			continue
+		}
+
+		driver, err := cable.NewDriverByName(name, i.localEndpoint, &i.localCluster)
+		if err != nil {
+			return errors.Wrap(err, "error creating the cable driver")
+		}
+
+		if err := driver.Init(); err != nil {
+			return errors.Wrapf(err, "error initializing the %q cable driver", name)
+		}
+
+		i.drivers[name] = driver
 	}
 
-	var err error
+	return nil
+}
+
+// driverFor returns the cable driver to use for the given remote endpoint. This must be called with the lock held.
+func (i *engine) driverFor(endpoint *v1.EndpointSpec) (cable.Driver, error) {
+	name, err := cable.GetDriverNameFor(i.localEndpoint.Spec(), endpoint)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // No need to wrap
+	}
 
-	if i.driver, err = cable.NewDriver(i.localEndpoint, &i.localCluster); err != nil {
-		return errors.Wrap(err, "error creating the cable driver")
+	driver, ok := i.drivers[name]
+	if !ok {
+		return nil, errors.Errorf("the %q cable driver for cluster %q is not running", name, endpoint.ClusterID)
 	}
 
-	return errors.Wrap(i.driver.Init(), "error initializing the cable driver")
+	return driver, nil
+}
+
+// sortedDriverNames returns the names of the running drivers in a deterministic order. This must be called with the
+// lock held.
+func (i *engine) sortedDriverNames() []string {
+	names := make([]string, 0, len(i.drivers))
+	for name := range i.drivers {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
 }
 
 func (i *engine) SetupNATDiscovery(natDiscovery natdiscovery.Interface) {
@@ -169,9 +233,73 @@ func (i *engine) installCableWithNATInfo(rnat *natdiscovery.NATEndpointInfo) err
 		return nil
 	}
 
-	activeConnections, err := i.driver.GetActiveConnections()
+	if endpoint.Spec.IsActiveActive() || i.localEndpoint.Spec().IsActiveActive() {
+		if i.pairedEndpoints[endpoint.Spec.ClusterID] == nil {
+			i.pairedEndpoints[endpoint.Spec.ClusterID] = map[string]*natdiscovery.NATEndpointInfo{}
+		}
+
+		i.pairedEndpoints[endpoint.Spec.ClusterID][endpoint.Spec.CableName] = rnat
+
+		if !i.pairedWith(endpoint.Spec.ClusterID).Has(endpoint.Spec.CableName) {
+			logger.Infof("Not installing Endpoint cable %q as it isn't paired with the local gateway for cluster %q",
+				endpoint.Spec.CableName, endpoint.Spec.ClusterID)
+		}
+
+		return i.reconcilePaired(endpoint.Spec.ClusterID)
+	}
+
+	return i.installCable(rnat)
+}
+
+// installCable connects to the given remote endpoint, replacing any existing connection to its cluster. This must be
+// called with the lock held.
+func (i *engine) installCable(rnat *natdiscovery.NATEndpointInfo) error {
+	endpoint := &rnat.Endpoint
+
+	driver, err := i.driverFor(&endpoint.Spec)
 	if err != nil {
-		return errors.Wrap(err, "error getting the active connections")
+		i.driverErrors[endpoint.Spec.CableName] = &v1.Connection{
+			Status:        v1.ConnectionError,
+			StatusMessage: err.Error(),
+			Endpoint:      endpoint.Spec,
+		}
+
+		return err
+	}
+
+	delete(i.driverErrors, endpoint.Spec.CableName)
+
+	for _, name := range i.sortedDriverNames() {
+		done, err := i.replaceActiveConnections(i.drivers[name], driver, rnat)
+		if done || err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("Installing Endpoint cable %q using the %q driver", endpoint.Spec.CableName, driver.GetName())
+
+	remoteEndpointIP, err := driver.ConnectToEndpoint(rnat)
+	if err != nil {
+		return errors.Wrapf(err, "error installing Endpoint cable %q", endpoint.Spec.CableName)
+	}
+
+	logger.Infof("Successfully installed Endpoint cable %q with remote IP %s", endpoint.Spec.CableName, remoteEndpointIP)
+
+	i.installedCables[rnat.Endpoint.Spec.CableName] = endpoint.CreationTimestamp
+	i.cableDrivers[rnat.Endpoint.Spec.CableName] = driver
+	i.installedEndpoints[rnat.Endpoint.Spec.CableName] = rnat
+
+	return nil
+}
+
+// replaceActiveConnections disconnects the given driver's active connections to the remote endpoint's cluster which are
+// to be replaced by the new endpoint. It returns true if the new endpoint is not to be installed.
+func (i *engine) replaceActiveConnections(driver, newDriver cable.Driver, rnat *natdiscovery.NATEndpointInfo) (bool, error) {
+	endpoint := &rnat.Endpoint
+
+	activeConnections, err := driver.GetActiveConnections()
+	if err != nil {
+		return false, errors.Wrap(err, "error getting the active connections")
 	}
 
 	for j := range activeConnections {
@@ -190,17 +318,18 @@ func (i *engine) installCableWithNATInfo(rnat *natdiscovery.NATEndpointInfo) err
 		if endpoint.CreationTimestamp.Before(&prevTimestamp) {
 			logger.Warningf("The timestamp (%s) for new cable %q is older than the timestamp (%s) of the pre-existing "+
 				"cable %q - not replacing", endpoint.CreationTimestamp, endpoint.Spec.CableName, prevTimestamp, active.Endpoint.CableName)
-			return nil
+			return true, nil
 		}
 
-		if endpoint.CreationTimestamp.Equal(&prevTimestamp) && active.Endpoint.CableName == endpoint.Spec.CableName {
+		if endpoint.CreationTimestamp.Equal(&prevTimestamp) && active.Endpoint.CableName == endpoint.Spec.CableName &&
+			driver == newDriver {
 			// There could be scenarios where the cableName would be the same but the endpoint IP or specific driver
-			// config has changed.
+			// config has changed. Driver configs scoped to other remote clusters don't apply to this cable.
 			if active.UsingIP == rnat.UseIP && active.UsingNAT == rnat.UseNAT &&
-				reflect.DeepEqual(active.Endpoint.BackendConfig, endpoint.Spec.BackendConfig) {
+				reflect.DeepEqual(active.Endpoint.BackendConfigFor(i.localCluster.ID), endpoint.Spec.BackendConfigFor(i.localCluster.ID)) {
 				logger.V(log.TRACE).Infof("Connection info (IP: %s, NAT: %v, BackendConfig: %v) for cable %q is unchanged"+
 					" - not re-installing", active.UsingIP, active.UsingNAT, active.Endpoint.BackendConfig, active.Endpoint.CableName)
-				return nil
+				return true, nil
 			}
 
 			logger.V(log.DEBUG).Infof("New connection info (IP: %s, NAT: %v, BackendConfig: %v) for cable %q differs from"+
@@ -208,32 +337,22 @@ func (i *engine) installCableWithNATInfo(rnat *natdiscovery.NATEndpointInfo) err
 				active.Endpoint.CableName, active.UsingIP, active.UsingNAT, endpoint.Spec.BackendConfig)
 		}
 
-		logger.V(log.DEBUG).Infof("Disconnecting pre-existing cable %q", active.Endpoint.CableName)
+		logger.V(log.DEBUG).Infof("Disconnecting pre-existing cable %q from the %q driver", active.Endpoint.CableName,
+			driver.GetName())
 
-		err = i.driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: active.Endpoint})
+		err = driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: active.Endpoint})
 		if err != nil {
-			return errors.Wrapf(err, "error disconnecting previous Endpoint cable %#v", active.Endpoint)
+			return false, errors.Wrapf(err, "error disconnecting previous Endpoint cable %#v", active.Endpoint)
 		}
 	}
 
-	logger.Infof("Installing Endpoint cable %q", endpoint.Spec.CableName)
-
-	remoteEndpointIP, err := i.driver.ConnectToEndpoint(rnat)
-	if err != nil {
-		return errors.Wrapf(err, "error installing Endpoint cable %q", endpoint.Spec.CableName)
-	}
-
-	logger.Infof("Successfully installed Endpoint cable %q with remote IP %s", endpoint.Spec.CableName, remoteEndpointIP)
-
-	i.installedCables[rnat.Endpoint.Spec.CableName] = endpoint.CreationTimestamp
-
-	return nil
+	return false, nil
 }
 
 func (i *engine) InstallCable(endpoint *v1.Endpoint) error {
 	if endpoint.Spec.ClusterID == i.localCluster.ID {
 		logger.V(log.TRACE).Infof("Not installing cable for local cluster")
-		return nil
+		return i.updateLocalActiveActive(&endpoint.Spec, endpoint.Spec.IsActiveActive())
 	}
 
 	if reflect.DeepEqual(endpoint.Spec, *i.localEndpoint.Spec()) {
@@ -253,7 +372,7 @@ func (i *engine) InstallCable(endpoint *v1.Endpoint) error {
 func (i *engine) RemoveCable(endpoint *v1.Endpoint) error {
 	if endpoint.Spec.ClusterID == i.localCluster.ID {
 		logger.V(log.DEBUG).Infof("Cables are not added/removed for the local cluster, skipping removal")
-		return nil
+		return i.updateLocalActiveActive(&endpoint.Spec, false)
 	}
 
 	logger.Infof("Removing Endpoint cable %q", endpoint.Spec.CableName)
@@ -264,23 +383,211 @@ func (i *engine) RemoveCable(endpoint *v1.Endpoint) error {
 	defer i.Unlock()
 
 	delete(i.natDiscoveryPending, endpoint.Spec.CableName)
+	delete(i.installedEndpoints, endpoint.Spec.CableName)
+	delete(i.driverErrors, endpoint.Spec.CableName)
+
+	if clusterEndpoints := i.pairedEndpoints[endpoint.Spec.ClusterID]; clusterEndpoints != nil {
+		delete(clusterEndpoints, endpoint.Spec.CableName)
+
+		if len(clusterEndpoints) == 0 {
+			delete(i.pairedEndpoints, endpoint.Spec.ClusterID)
+		}
+	}
+
+	if _, installed := i.installedCables[endpoint.Spec.CableName]; installed {
+		driver, ok := i.cableDrivers[endpoint.Spec.CableName]
+		if !ok {
+			var err error
+
+			driver, err = i.driverFor(&endpoint.Spec)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: endpoint.Spec})
+		if err != nil {
+			return errors.Wrapf(err, "error disconnecting Endpoint cable %q", endpoint.Spec.CableName)
+		}
+
+		delete(i.installedCables, endpoint.Spec.CableName)
+		delete(i.cableDrivers, endpoint.Spec.CableName)
+
+		logger.Infof("Successfully removed Endpoint cable %q", endpoint.Spec.CableName)
+	}
+
+	if i.pairedEndpoints[endpoint.Spec.ClusterID] == nil || !i.running {
+		return nil
+	}
+
+	// Another endpoint of the cluster may now be paired with the local gateway.
+	return i.reconcilePaired(endpoint.Spec.ClusterID)
+}
+
+func (i *engine) ReconnectCable(cableName string, rediscoverNAT bool) error {
+	i.Lock()
+
+	// The NAT info is retained if a previous reconnect failed so it can be retried.
+	rnat, ok := i.installedEndpoints[cableName]
+	if !ok || !i.running {
+		i.Unlock()
+		return errors.Wrapf(ErrCableNotInstalled, "unable to reconnect cable %q", cableName)
+	}
+
+	logger.Infof("Reconnecting Endpoint cable %q", cableName)
+
+	if err := i.disconnectCable(cableName); err != nil {
+		i.Unlock()
+		return err
+	}
+
+	if rediscoverNAT {
+		// The cable is installed once NAT discovery for the endpoint completes.
+		i.natDiscoveryPending[cableName]++
+		i.Unlock()
+
+		i.natDiscovery.RemoveEndpoint(cableName)
+		i.natDiscovery.AddEndpoint(&rnat.Endpoint)
 
-	if _, ok := i.installedCables[endpoint.Spec.CableName]; !ok {
 		return nil
 	}
 
-	err := i.driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: endpoint.Spec})
+	defer i.Unlock()
+
+	return i.installCable(rnat)
+}
+
+// disconnectCable disconnects the installed cable with the given name while retaining its NAT info. This must be
+// called with the lock held.
+func (i *engine) disconnectCable(cableName string) error {
+	driver, ok := i.cableDrivers[cableName]
+	if !ok {
+		return nil
+	}
+
+	rnat := i.installedEndpoints[cableName]
+
+	err := driver.DisconnectFromEndpoint(&types.SubmarinerEndpoint{Spec: rnat.Endpoint.Spec})
 	if err != nil {
-		return errors.Wrapf(err, "error disconnecting Endpoint cable %q", endpoint.Spec.CableName)
+		return errors.Wrapf(err, "error disconnecting Endpoint cable %q", cableName)
 	}
 
-	delete(i.installedCables, endpoint.Spec.CableName)
+	delete(i.installedCables, cableName)
+	delete(i.cableDrivers, cableName)
+
+	return nil
+}
+
+// updateLocalActiveActive records whether the given endpoint of the local cluster is an active-active gateway and, if
+// that changes the pairing, reconciles the cables of the active-active remote clusters.
+func (i *engine) updateLocalActiveActive(endpoint *v1.EndpointSpec, active bool) error {
+	i.Lock()
+	defer i.Unlock()
+
+	if endpoint.CableName == i.localEndpoint.Spec().CableName || i.localActiveActive.Has(endpoint.CableName) == active {
+		return nil
+	}
+
+	if active {
+		i.localActiveActive.Insert(endpoint.CableName)
+	} else {
+		i.localActiveActive.Delete(endpoint.CableName)
+	}
+
+	if !i.running {
+		return nil
+	}
+
+	var errs []error
+
+	for clusterID := range i.pairedEndpoints {
+		errs = append(errs, i.reconcilePaired(clusterID))
+	}
+
+	return k8serrors.NewAggregate(errs)
+}
+
+// pairedWith returns the cable name of the endpoint of the given remote cluster which the local gateway is paired
+// with, if any. Each cluster's gateways are sorted by cable name and the i-th gateway of one cluster is paired with the
+// i-th gateway of the other, a cluster which doesn't run active-active gateways counting as its newest endpoint alone.
+// Both sides thus compute the same pairs and every gateway is paired with at most one gateway of each remote cluster,
+// as a cable driver only holds a single cable to a cluster. The gateways beyond the other cluster's number of
+// gateways aren't paired so both clusters should run the same number of active-active gateways. This must be called
+// with the lock held.
+func (i *engine) pairedWith(clusterID string) sets.Set[string] {
+	localName := i.localEndpoint.Spec().CableName
+	localNames := []string{localName}
+
+	if i.localEndpoint.Spec().IsActiveActive() {
+		localNames = append(localNames, i.localActiveActive.UnsortedList()...)
+	}
 
-	logger.Infof("Successfully removed Endpoint cable %q", endpoint.Spec.CableName)
+	slices.Sort(localNames)
+	localIndex := slices.Index(localNames, localName)
+
+	var (
+		remoteNames []string
+		newest      *natdiscovery.NATEndpointInfo
+	)
+
+	for cableName, rnat := range i.pairedEndpoints[clusterID] {
+		if rnat.Endpoint.Spec.IsActiveActive() {
+			remoteNames = append(remoteNames, cableName)
+		} else if newest == nil || newest.Endpoint.CreationTimestamp.Before(&rnat.Endpoint.CreationTimestamp) ||
+			(newest.Endpoint.CreationTimestamp.Equal(&rnat.Endpoint.CreationTimestamp) && cableName < newest.Endpoint.Spec.CableName) {
+			newest = rnat
+		}
+	}
+
+	if len(remoteNames) == 0 && newest != nil {
+		remoteNames = []string{newest.Endpoint.Spec.CableName}
+	}
+
+	slices.Sort(remoteNames)
+
+	paired := sets.New[string]()
+
+	if localIndex < len(remoteNames) {
+		paired.Insert(remoteNames[localIndex])
+	}
+
+	return paired
+}
+
+// reconcilePaired disconnects the cables to the endpoints of the given remote cluster which aren't paired with the
+// local gateway and then installs the cable to the paired endpoint. This must be called with the lock held.
+func (i *engine) reconcilePaired(clusterID string) error {
+	paired := i.pairedWith(clusterID)
+
+	for cableName := range i.pairedEndpoints[clusterID] {
+		if !paired.Has(cableName) && i.isInstalled(cableName) {
+			logger.Infof("Disconnecting Endpoint cable %q as it's no longer paired with the local gateway", cableName)
+
+			if err := i.disconnectCable(cableName); err != nil {
+				return err
+			}
+
+			delete(i.installedEndpoints, cableName)
+		}
+	}
+
+	for cableName, rnat := range i.pairedEndpoints[clusterID] {
+		if paired.Has(cableName) && !i.isInstalled(cableName) {
+			if err := i.installCable(rnat); err != nil {
+				return err
+			}
+		}
+	}
 
 	return nil
 }
 
+// isInstalled returns true if the given cable is installed. This must be called with the lock held.
+func (i *engine) isInstalled(cableName string) bool {
+	_, ok := i.installedCables[cableName]
+	return ok
+}
+
 func (i *engine) GetHAStatus() v1.HAStatus {
 	i.Lock()
 	defer i.Unlock()
@@ -299,18 +606,46 @@ func (i *engine) ListCableConnections() ([]v1.Connection, error) {
 	i.Lock()
 	defer i.Unlock()
 
-	if i.running {
-		return i.driver.GetConnections() //nolint:wrapcheck  // Let the caller wrap it
-	}
+	connections := []v1.Connection{}
 
 	// if not running, we can safely report that no connections exist.
-	return []v1.Connection{}, nil
+	if !i.running {
+		return connections, nil
+	}
+
+	for _, name := range i.sortedDriverNames() {
+		driverConnections, err := i.drivers[name].GetConnections()
+		if err != nil {
+			return nil, err //nolint:wrapcheck  // Let the caller wrap it
+		}
+
+		for j := range driverConnections {
+			connection := driverConnections[j]
+			connection.CableDriver = name
+			connections = append(connections, connection)
+		}
+	}
+
+	cableNames := make([]string, 0, len(i.driverErrors))
+	for cableName := range i.driverErrors {
+		cableNames = append(cableNames, cableName)
+	}
+
+	slices.Sort(cableNames)
+
+	for _, cableName := range cableNames {
+		connections = append(connections, *i.driverErrors[cableName])
+	}
+
+	return connections, nil
 }
 
 func (i *engine) Cleanup() error {
-	if i.driver != nil {
-		return i.driver.Cleanup() //nolint:wrapcheck  // No need to wrap this error
+	var errs []error
+
+	for _, name := range i.sortedDriverNames() {
+		errs = append(errs, i.drivers[name].Cleanup())
 	}
 
-	return nil
+	return k8serrors.NewAggregate(errs)
 }
@@ -44,13 +44,21 @@ func init() {
 	kzerolog.AddFlags(nil)
 }
 
-var fakeDriver *fake.Driver
+const otherDriverName = "other-fake-driver"
+
+var (
+	fakeDriver  *fake.Driver
+	otherDriver *fake.Driver
+)
 
 var _ = BeforeSuite(func() {
 	kzerolog.InitK8sLogging()
 	cable.AddDriver(fake.DriverName, func(_ *submendpoint.Local, _ *types.SubmarinerCluster) (cable.Driver, error) {
 		return fakeDriver, nil
 	})
+	cable.AddDriver(otherDriverName, func(_ *submendpoint.Local, _ *types.SubmarinerCluster) (cable.Driver, error) {
+		return otherDriver, nil
+	})
 })
 
 var _ = Describe("Cable Engine", func() {
@@ -65,6 +73,18 @@ var _ = Describe("Cable Engine", func() {
 		skipStart      bool
 	)
 
+	newEngine := func() {
+		engine = cableengine.NewEngine(&types.SubmarinerCluster{
+			ID: localClusterID,
+			Spec: subv1.ClusterSpec{
+				ClusterID: localClusterID,
+			},
+		}, submendpoint.NewLocal(&localEndpoint.Spec, dynamicfake.NewSimpleDynamicClient(scheme.Scheme), ""))
+
+		natDiscovery = &fakeNATDiscovery{removeEndpoint: make(chan string, 20), readyChannel: make(chan *natdiscovery.NATEndpointInfo, 100)}
+		engine.SetupNATDiscovery(natDiscovery)
+	}
+
 	BeforeEach(func() {
 		skipStart = false
 
@@ -95,15 +115,8 @@ var _ = Describe("Cable Engine", func() {
 		}
 
 		fakeDriver = fake.New()
-		engine = cableengine.NewEngine(&types.SubmarinerCluster{
-			ID: localClusterID,
-			Spec: subv1.ClusterSpec{
-				ClusterID: localClusterID,
-			},
-		}, submendpoint.NewLocal(&localEndpoint.Spec, dynamicfake.NewSimpleDynamicClient(scheme.Scheme), ""))
-
-		natDiscovery = &fakeNATDiscovery{removeEndpoint: make(chan string, 20), readyChannel: make(chan *natdiscovery.NATEndpointInfo, 100)}
-		engine.SetupNATDiscovery(natDiscovery)
+		otherDriver = fake.New()
+		newEngine()
 	})
 
 	JustBeforeEach(func() {
@@ -209,6 +222,20 @@ var _ = Describe("Cable Engine", func() {
 					})
 				})
 
+				Context("but different backend configuration scoped to another cluster", func() {
+					BeforeEach(func() {
+						newEndpoint.Spec.BackendConfig = map[string]string{
+							"port": "1234",
+							subv1.ClusterScopedConfig("nonce", "other"): "5678",
+						}
+					})
+
+					It("should not disconnect from the previous endpoint nor connect to the new one", func() {
+						fakeDriver.AwaitNoDisconnectFromEndpoint()
+						fakeDriver.AwaitNoConnectToEndpoint()
+					})
+				})
+
 				Context(" and connection info", func() {
 					It("should not disconnect from the previous endpoint nor connect to the new one", func() {
 						fakeDriver.AwaitNoDisconnectFromEndpoint()
@@ -253,6 +280,191 @@ var _ = Describe("Cable Engine", func() {
 		})
 	})
 
+	When("install cable for the endpoints of an active-active remote cluster", func() {
+		var otherEndpoint *subv1.Endpoint
+
+		BeforeEach(func() {
+			remoteEndpoint.Spec.BackendConfig[subv1.GatewayHAModeConfig] = subv1.HAModeActiveActive
+
+			otherEndpoint = remoteEndpoint.DeepCopy()
+			otherEndpoint.Spec.CableName = fmt.Sprintf("submariner-cable-%s-1.1.1.2", remoteClusterID)
+			otherEndpoint.Spec.PrivateIP = "1.1.1.2"
+			otherEndpoint.Spec.Hostname = "other-gateway"
+		})
+
+		JustBeforeEach(func() {
+			Expect(engine.InstallCable(remoteEndpoint)).To(Succeed())
+			fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(remoteEndpoint))
+
+			Expect(engine.InstallCable(otherEndpoint)).To(Succeed())
+		})
+
+		activeCables := func(count int) []string {
+			var conns []subv1.Connection
+
+			Eventually(func() []subv1.Connection {
+				conns, _ = fakeDriver.GetActiveConnections()
+				return conns
+			}).Should(HaveLen(count))
+
+			Consistently(func() []subv1.Connection {
+				conns, _ = fakeDriver.GetActiveConnections()
+				return conns
+			}).Should(HaveLen(count))
+
+			names := make([]string, len(conns))
+			for i := range conns {
+				names[i] = conns[i].Endpoint.CableName
+			}
+
+			return names
+		}
+
+		Context("and the local cluster has a single gateway", func() {
+			It("should connect to the single endpoint paired with the local gateway", func() {
+				Expect(activeCables(1)).To(ConsistOf(remoteEndpoint.Spec.CableName))
+				fakeDriver.AwaitNoConnectToEndpoint()
+			})
+
+			Context("and the paired endpoint is removed", func() {
+				It("should fail over to the other endpoint", func() {
+					Expect(activeCables(1)).To(ConsistOf(remoteEndpoint.Spec.CableName))
+
+					Expect(engine.RemoveCable(remoteEndpoint)).To(Succeed())
+					fakeDriver.AwaitDisconnectFromEndpoint(&remoteEndpoint.Spec)
+					fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(otherEndpoint))
+					Expect(activeCables(1)).To(ConsistOf(otherEndpoint.Spec.CableName))
+				})
+			})
+		})
+
+		Context("and the local cluster has the same number of active-active gateways", func() {
+			var otherLocalEndpoint *subv1.Endpoint
+
+			BeforeEach(func() {
+				localEndpoint.Spec.BackendConfig = map[string]string{subv1.GatewayHAModeConfig: subv1.HAModeActiveActive}
+				newEngine()
+
+				// The other local gateway sorts first so the local gateway is paired with the second remote endpoint.
+				otherLocalEndpoint = localEndpoint.DeepCopy()
+				otherLocalEndpoint.Spec.CableName = fmt.Sprintf("submariner-cable-%s-1.1.1.0", localClusterID)
+				otherLocalEndpoint.Spec.PrivateIP = "1.1.1.0"
+			})
+
+			JustBeforeEach(func() {
+				Expect(engine.InstallCable(otherLocalEndpoint)).To(Succeed())
+			})
+
+			It("should connect to the single endpoint paired with the local gateway", func() {
+				fakeDriver.AwaitDisconnectFromEndpoint(&remoteEndpoint.Spec)
+				fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(otherEndpoint))
+				Expect(activeCables(1)).To(ConsistOf(otherEndpoint.Spec.CableName))
+			})
+
+			Context("and the other local gateway is removed", func() {
+				It("should switch to the endpoint now paired with the local gateway", func() {
+					fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(otherEndpoint))
+					Expect(activeCables(1)).To(ConsistOf(otherEndpoint.Spec.CableName))
+
+					Expect(engine.RemoveCable(otherLocalEndpoint)).To(Succeed())
+					fakeDriver.AwaitDisconnectFromEndpoint(&otherEndpoint.Spec)
+					fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(remoteEndpoint))
+					Expect(activeCables(1)).To(ConsistOf(remoteEndpoint.Spec.CableName))
+				})
+			})
+
+			Context("and the paired endpoint is removed", func() {
+				It("should not connect to the endpoint paired with the other local gateway", func() {
+					fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(otherEndpoint))
+					Expect(activeCables(1)).To(ConsistOf(otherEndpoint.Spec.CableName))
+
+					Expect(engine.RemoveCable(otherEndpoint)).To(Succeed())
+					fakeDriver.AwaitDisconnectFromEndpoint(&otherEndpoint.Spec)
+					fakeDriver.AwaitNoConnectToEndpoint()
+					Expect(activeCables(0)).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("and the local cluster has fewer active-active gateways", func() {
+			var thirdEndpoint *subv1.Endpoint
+
+			BeforeEach(func() {
+				localEndpoint.Spec.BackendConfig = map[string]string{subv1.GatewayHAModeConfig: subv1.HAModeActiveActive}
+				newEngine()
+
+				thirdEndpoint = remoteEndpoint.DeepCopy()
+				thirdEndpoint.Spec.CableName = fmt.Sprintf("submariner-cable-%s-1.1.1.3", remoteClusterID)
+				thirdEndpoint.Spec.PrivateIP = "1.1.1.3"
+				thirdEndpoint.Spec.Hostname = "third-gateway"
+			})
+
+			JustBeforeEach(func() {
+				otherLocalEndpoint := localEndpoint.DeepCopy()
+				otherLocalEndpoint.Spec.CableName = fmt.Sprintf("submariner-cable-%s-1.1.1.2", localClusterID)
+				otherLocalEndpoint.Spec.PrivateIP = "1.1.1.2"
+
+				Expect(engine.InstallCable(otherLocalEndpoint)).To(Succeed())
+				Expect(engine.InstallCable(thirdEndpoint)).To(Succeed())
+			})
+
+			It("should hold a single cable to the cluster", func() {
+				Expect(activeCables(1)).To(ConsistOf(remoteEndpoint.Spec.CableName))
+				fakeDriver.AwaitNoConnectToEndpoint()
+				fakeDriver.AwaitNoDisconnectFromEndpoint()
+			})
+		})
+
+		Context("and the local cluster has more active-active gateways", func() {
+			BeforeEach(func() {
+				localEndpoint.Spec.BackendConfig = map[string]string{subv1.GatewayHAModeConfig: subv1.HAModeActiveActive}
+				newEngine()
+			})
+
+			JustBeforeEach(func() {
+				for _, ip := range []string{"1.1.1.0", "1.1.1.2"} {
+					otherLocalEndpoint := localEndpoint.DeepCopy()
+					otherLocalEndpoint.Spec.CableName = fmt.Sprintf("submariner-cable-%s-%s", localClusterID, ip)
+					otherLocalEndpoint.Spec.PrivateIP = ip
+
+					Expect(engine.InstallCable(otherLocalEndpoint)).To(Succeed())
+				}
+			})
+
+			It("should hold a single cable to the cluster", func() {
+				fakeDriver.AwaitDisconnectFromEndpoint(&remoteEndpoint.Spec)
+				fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(otherEndpoint))
+				Expect(activeCables(1)).To(ConsistOf(otherEndpoint.Spec.CableName))
+			})
+		})
+	})
+
+	When("the local cluster runs active-active gateways and the remote cluster doesn't", func() {
+		BeforeEach(func() {
+			localEndpoint.Spec.BackendConfig = map[string]string{subv1.GatewayHAModeConfig: subv1.HAModeActiveActive}
+			newEngine()
+		})
+
+		Context("and the local gateway sorts first", func() {
+			It("should connect to the remote endpoint", func() {
+				Expect(engine.InstallCable(remoteEndpoint)).To(Succeed())
+				fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(remoteEndpoint))
+			})
+		})
+
+		Context("and another local gateway sorts first", func() {
+			It("should not connect to the remote endpoint", func() {
+				otherLocalEndpoint := localEndpoint.DeepCopy()
+				otherLocalEndpoint.Spec.CableName = fmt.Sprintf("submariner-cable-%s-1.1.1.0", localClusterID)
+				otherLocalEndpoint.Spec.PrivateIP = "1.1.1.0"
+				Expect(engine.InstallCable(otherLocalEndpoint)).To(Succeed())
+
+				Expect(engine.InstallCable(remoteEndpoint)).To(Succeed())
+				fakeDriver.AwaitNoConnectToEndpoint()
+			})
+		})
+	})
+
 	When("install cable for a local endpoint", func() {
 		It("should not connect to the endpoint", func() {
 			Expect(engine.InstallCable(localEndpoint)).To(Succeed())
@@ -283,6 +495,50 @@ var _ = Describe("Cable Engine", func() {
 		})
 	})
 
+	When("reconnect cable for an installed remote endpoint", func() {
+		JustBeforeEach(func() {
+			Expect(engine.InstallCable(remoteEndpoint)).To(Succeed())
+			fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(remoteEndpoint))
+		})
+
+		It("should disconnect from and reconnect to the endpoint", func() {
+			Expect(engine.ReconnectCable(remoteEndpoint.Spec.CableName, false)).To(Succeed())
+			fakeDriver.AwaitDisconnectFromEndpoint(&remoteEndpoint.Spec)
+			fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(remoteEndpoint))
+		})
+
+		Context("with NAT rediscovery", func() {
+			It("should re-run NAT discovery and reconnect to the endpoint", func() {
+				Expect(engine.ReconnectCable(remoteEndpoint.Spec.CableName, true)).To(Succeed())
+				fakeDriver.AwaitDisconnectFromEndpoint(&remoteEndpoint.Spec)
+				Eventually(natDiscovery.removeEndpoint).Should(Receive(Equal(remoteEndpoint.Spec.CableName)))
+				fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(remoteEndpoint))
+			})
+		})
+
+		Context("and the driver fails to reconnect to the endpoint", func() {
+			JustBeforeEach(func() {
+				fakeDriver.ErrOnConnectToEndpoint = errors.New("fake connect error")
+			})
+
+			It("should return an error and succeed on retry", func() {
+				Expect(engine.ReconnectCable(remoteEndpoint.Spec.CableName, false)).ToNot(Succeed())
+				fakeDriver.AwaitDisconnectFromEndpoint(&remoteEndpoint.Spec)
+
+				Expect(engine.ReconnectCable(remoteEndpoint.Spec.CableName, false)).To(Succeed())
+				fakeDriver.AwaitNoDisconnectFromEndpoint()
+				fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(remoteEndpoint))
+			})
+		})
+
+		Context("and the cable was removed", func() {
+			It("should return ErrCableNotInstalled", func() {
+				Expect(engine.RemoveCable(remoteEndpoint)).To(Succeed())
+				Expect(engine.ReconnectCable(remoteEndpoint.Spec.CableName, false)).To(MatchError(cableengine.ErrCableNotInstalled))
+			})
+		})
+	})
+
 	When("remove cable for a local endpoint", func() {
 		JustBeforeEach(func() {
 			Expect(engine.InstallCable(remoteEndpoint)).To(Succeed())
@@ -302,7 +558,10 @@ var _ = Describe("Cable Engine", func() {
 		})
 
 		It("should retrieve the connections from the driver", func() {
-			Expect(engine.ListCableConnections()).To(Equal(fakeDriver.Connections))
+			Expect(engine.ListCableConnections()).To(Equal([]subv1.Connection{{
+				Endpoint:    remoteEndpoint.Spec,
+				CableDriver: fake.DriverName,
+			}}))
 		})
 
 		Context("and retrieval of the driver's connections fails", func() {
@@ -317,6 +576,101 @@ var _ = Describe("Cable Engine", func() {
 		})
 	})
 
+	When("a different cable driver is configured for the remote cluster", func() {
+		BeforeEach(func() {
+			Expect(cable.SetClusterCableDrivers(map[string]string{remoteClusterID: otherDriverName})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(cable.SetClusterCableDrivers(nil)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			otherDriver.AwaitInit()
+			Expect(engine.InstallCable(remoteEndpoint)).To(Succeed())
+		})
+
+		It("should connect to the endpoint using the configured driver", func() {
+			otherDriver.AwaitConnectToEndpoint(natEndpointInfoFor(remoteEndpoint))
+			fakeDriver.AwaitNoConnectToEndpoint()
+		})
+
+		It("should disconnect from the endpoint using the configured driver", func() {
+			otherDriver.AwaitConnectToEndpoint(natEndpointInfoFor(remoteEndpoint))
+
+			Expect(engine.RemoveCable(remoteEndpoint)).To(Succeed())
+			otherDriver.AwaitDisconnectFromEndpoint(&remoteEndpoint.Spec)
+			fakeDriver.AwaitNoDisconnectFromEndpoint()
+		})
+
+		It("should report the driver used for each connection", func() {
+			fakeDriver.Connections = []subv1.Connection{{Endpoint: localEndpoint.Spec}}
+			otherDriver.Connections = []subv1.Connection{{Endpoint: remoteEndpoint.Spec}}
+
+			Expect(engine.ListCableConnections()).To(ConsistOf(
+				subv1.Connection{Endpoint: localEndpoint.Spec, CableDriver: fake.DriverName},
+				subv1.Connection{Endpoint: remoteEndpoint.Spec, CableDriver: otherDriverName}))
+		})
+
+		Context("and the remote cluster selected the Backend driver but runs both drivers", func() {
+			BeforeEach(func() {
+				remoteEndpoint.Spec.Backend = fake.DriverName
+				remoteEndpoint.Spec.BackendConfig[subv1.CableDriversConfig] = fake.DriverName + "," + otherDriverName
+			})
+
+			It("should connect to the endpoint using the driver selected by the cluster with the lowest ID", func() {
+				otherDriver.AwaitConnectToEndpoint(natEndpointInfoFor(remoteEndpoint))
+				fakeDriver.AwaitNoConnectToEndpoint()
+			})
+		})
+
+		Context("and the remote cluster only runs the Backend driver", func() {
+			BeforeEach(func() {
+				remoteEndpoint.Spec.Backend = fake.DriverName
+				remoteEndpoint.Spec.BackendConfig[subv1.CableDriversConfig] = fake.DriverName
+			})
+
+			It("should connect to the endpoint using the driver run by both clusters", func() {
+				fakeDriver.AwaitConnectToEndpoint(natEndpointInfoFor(remoteEndpoint))
+				otherDriver.AwaitNoConnectToEndpoint()
+			})
+		})
+
+		Context("and the clusters don't run a common driver", func() {
+			BeforeEach(func() {
+				remoteEndpoint.Spec.Backend = "bogus"
+			})
+
+			It("should report a connection error", func() {
+				Eventually(func() []subv1.Connection {
+					conns, _ := engine.ListCableConnections()
+					return conns
+				}).Should(ContainElement(And(
+					HaveField("Status", subv1.ConnectionError),
+					HaveField("Endpoint.CableName", remoteEndpoint.Spec.CableName))))
+
+				fakeDriver.AwaitNoConnectToEndpoint()
+				otherDriver.AwaitNoConnectToEndpoint()
+			})
+
+			It("should clear the connection error when the cable is removed", func() {
+				Eventually(func() []subv1.Connection {
+					conns, _ := engine.ListCableConnections()
+					return conns
+				}).Should(HaveLen(1))
+
+				Expect(engine.RemoveCable(remoteEndpoint)).To(Succeed())
+				Expect(engine.ListCableConnections()).To(BeEmpty())
+			})
+		})
+	})
+
+	When("an unsupported cable driver is configured for a remote cluster", func() {
+		It("should return an error", func() {
+			Expect(cable.SetClusterCableDrivers(map[string]string{remoteClusterID: "bogus"})).ToNot(Succeed())
+		})
+	})
+
 	When("the HA status is queried", func() {
 		It("should return active", func() {
 			Expect(engine.GetHAStatus()).To(Equal(subv1.HAStatusActive))
@@ -401,6 +755,10 @@ func (n *fakeNATDiscovery) RemoveEndpoint(endpointName string) {
 	n.removeEndpoint <- endpointName
 }
 
+func (n *fakeNATDiscovery) SymmetricNATDetected() bool {
+	return false
+}
+
 func (n *fakeNATDiscovery) GetReadyChannel() chan *natdiscovery.NATEndpointInfo {
 	return n.readyChannel
 }
@@ -20,6 +20,7 @@ package fake
 
 import (
 	"sync"
+	"time"
 
 	. "github.com/onsi/gomega"
 	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
@@ -38,6 +39,8 @@ type Engine struct { //nolint:gocritic // This mutex is exposed but we tweak it
 	ErrOnInstallCable         error
 	removeCable               chan *v1.EndpointSpec
 	ErrOnRemoveCable          error
+	reconnectCable            chan string
+	ErrOnReconnectCable       error
 	ErrOnStart                error
 	ErrOnCleanup              error
 	onCleanup                 chan struct{}
@@ -47,11 +50,12 @@ var _ cableengine.Engine = &Engine{}
 
 func New() *Engine {
 	return &Engine{
-		HAStatus:     v1.HAStatusPassive,
-		Connections:  []v1.Connection{},
-		installCable: make(chan *v1.EndpointSpec, 100),
-		removeCable:  make(chan *v1.EndpointSpec, 100),
-		onCleanup:    make(chan struct{}, 1),
+		HAStatus:       v1.HAStatusPassive,
+		Connections:    []v1.Connection{},
+		installCable:   make(chan *v1.EndpointSpec, 100),
+		removeCable:    make(chan *v1.EndpointSpec, 100),
+		reconnectCable: make(chan string, 100),
+		onCleanup:      make(chan struct{}, 1),
 	}
 }
 
@@ -86,6 +90,16 @@ func (e *Engine) RemoveCable(endpoint *v1.Endpoint) error {
 	return nil
 }
 
+func (e *Engine) ReconnectCable(cableName string, _ bool) error {
+	e.Lock()
+	err := e.ErrOnReconnectCable
+	e.Unlock()
+
+	e.reconnectCable <- cableName
+
+	return err
+}
+
 func (e *Engine) GetLocalEndpoint() *types.SubmarinerEndpoint {
 	return e.LocalEndPoint
 }
@@ -117,6 +131,14 @@ func (e *Engine) VerifyRemoveCable(expected *v1.EndpointSpec) {
 	Eventually(e.removeCable, 5).Should(Receive(Equal(expected)), "RemoveCable was not invoked")
 }
 
+func (e *Engine) VerifyReconnectCable(expected string) {
+	Eventually(e.reconnectCable, 5).Should(Receive(Equal(expected)), "ReconnectCable was not invoked")
+}
+
+func (e *Engine) VerifyNoReconnectCable() {
+	Consistently(e.reconnectCable, 300*time.Millisecond).ShouldNot(Receive(), "ReconnectCable was unexpectedly invoked")
+}
+
 func (e *Engine) SetupNATDiscovery(_ natdiscovery.Interface) {
 }
 
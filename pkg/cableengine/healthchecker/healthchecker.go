@@ -19,6 +19,7 @@ limitations under the License.
 package healthchecker
 
 import (
+	"net"
 	"sync"
 	"time"
 
@@ -43,21 +44,32 @@ type Config struct {
 	ClusterID          string
 	PingInterval       int
 	MaxPacketLossCount int
-	NewPinger          func(pinger.Config) pinger.Interface
+	// ProbeType is used for remote clusters not present in ClusterProbeTypes, which is keyed by cluster ID.
+	ProbeType         pinger.ProbeType
+	ClusterProbeTypes map[string]pinger.ProbeType
+	// ProbePort is the port used by the UDP and TCP probes of remote Endpoints which don't advertise theirs in their
+	// BackendConfig. If set, a pinger.Responder is also run on this port.
+	ProbePort int
+	// IsAllowedProbeSource returns whether the responder should answer the probes from the given source IP. If nil, the
+	// probes from any source are answered.
+	IsAllowedProbeSource func(ip net.IP) bool
+	NewPinger            func(pinger.Config) pinger.Interface
 }
 
 type controller struct {
 	sync.RWMutex
-	pingers map[string]pinger.Interface
-	config  *Config
+	pingers     map[string]pinger.Interface
+	pingerPorts map[string]int
+	config      *Config
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("HealthChecker")}
 
 func New(config *Config) (Interface, error) {
 	controller := &controller{
-		config:  config,
-		pingers: map[string]pinger.Interface{},
+		config:      config,
+		pingers:     map[string]pinger.Interface{},
+		pingerPorts: map[string]int{},
 	}
 
 	config.WatcherConfig.ResourceConfigs = []watcher.ResourceConfig{
@@ -97,6 +109,12 @@ func (h *controller) Start(stopCh <-chan struct{}) error {
 		return errors.Wrapf(err, "error starting watcher")
 	}
 
+	if h.config.ProbePort != 0 {
+		if err := pinger.NewResponder(h.config.ProbePort, h.config.IsAllowedProbeSource).Start(stopCh); err != nil {
+			logger.Errorf(err, "Error starting the health check responder - remote UDP and TCP probes will fail")
+		}
+	}
+
 	logger.Infof("CableEngine HealthChecker started with PingInterval: %v, MaxPacketLossCount: %v", h.config.PingInterval,
 		h.config.MaxPacketLossCount)
 
@@ -112,6 +130,7 @@ func (h *controller) Stop() {
 	}
 
 	h.pingers = map[string]pinger.Interface{}
+	h.pingerPorts = map[string]int{}
 }
 
 func (h *controller) endpointCreatedOrUpdated(obj runtime.Object, _ int) bool {
@@ -128,22 +147,32 @@ func (h *controller) endpointCreatedOrUpdated(obj runtime.Object, _ int) bool {
 		return false
 	}
 
+	probePort, err := endpointCreated.Spec.GetBackendPort(submarinerv1.HealthCheckProbePortConfig, int32(h.config.ProbePort))
+	if err != nil {
+		logger.Warningf("Error getting the health check probe port of Endpoint %q - using %d: %v", endpointCreated.Name,
+			probePort, err)
+	}
+
 	h.Lock()
 	defer h.Unlock()
 
 	if pingerObject, found := h.pingers[endpointCreated.Spec.CableName]; found {
-		if pingerObject.GetIP() == endpointCreated.Spec.HealthCheckIP {
+		if pingerObject.GetIP() == endpointCreated.Spec.HealthCheckIP &&
+			h.pingerPorts[endpointCreated.Spec.CableName] == int(probePort) {
 			return false
 		}
 
 		logger.V(log.DEBUG).Infof("HealthChecker is already running for %q - stopping", endpointCreated.Name)
 		pingerObject.Stop()
 		delete(h.pingers, endpointCreated.Spec.CableName)
+		delete(h.pingerPorts, endpointCreated.Spec.CableName)
 	}
 
 	pingerConfig := pinger.Config{
 		IP:                 endpointCreated.Spec.HealthCheckIP,
 		MaxPacketLossCount: h.config.MaxPacketLossCount,
+		ProbeType:          h.probeTypeFor(endpointCreated.Spec.ClusterID),
+		Port:               int(probePort),
 	}
 
 	if h.config.PingInterval != 0 {
@@ -157,14 +186,27 @@ func (h *controller) endpointCreatedOrUpdated(obj runtime.Object, _ int) bool {
 
 	pingerObject := newPingerFunc(pingerConfig)
 	h.pingers[endpointCreated.Spec.CableName] = pingerObject
+	h.pingerPorts[endpointCreated.Spec.CableName] = pingerConfig.Port
 	pingerObject.Start()
 
-	logger.Infof("CableEngine HealthChecker started pinger for CableName: %q with HealthCheckIP %q",
-		endpointCreated.Spec.CableName, endpointCreated.Spec.HealthCheckIP)
+	logger.Infof("CableEngine HealthChecker started %s pinger for CableName: %q with HealthCheckIP %q",
+		pingerConfig.ProbeType, endpointCreated.Spec.CableName, endpointCreated.Spec.HealthCheckIP)
 
 	return false
 }
 
+func (h *controller) probeTypeFor(clusterID string) pinger.ProbeType {
+	if probeType, ok := h.config.ClusterProbeTypes[clusterID]; ok {
+		return probeType
+	}
+
+	if h.config.ProbeType != "" {
+		return h.config.ProbeType
+	}
+
+	return pinger.ICMPProbe
+}
+
 func (h *controller) endpointDeleted(obj runtime.Object, _ int) bool {
 	endpointDeleted := obj.(*submarinerv1.Endpoint)
 
@@ -174,6 +216,7 @@ func (h *controller) endpointDeleted(obj runtime.Object, _ int) bool {
 	if pingerObject, found := h.pingers[endpointDeleted.Spec.CableName]; found {
 		pingerObject.Stop()
 		delete(h.pingers, endpointDeleted.Spec.CableName)
+		delete(h.pingerPorts, endpointDeleted.Spec.CableName)
 	}
 
 	return false
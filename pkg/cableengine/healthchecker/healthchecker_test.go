@@ -21,6 +21,7 @@ package healthchecker_test
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -46,15 +47,21 @@ var _ = Describe("Controller", func() {
 	const healthCheckIP1 = "1.1.1.1"
 	const healthCheckIP2 = "2.2.2.2"
 	const healthCheckIP3 = "3.3.3.3"
+	const probePort = 4801
+	const remoteProbePort = 5801
 
 	var (
 		healthChecker healthchecker.Interface
 		endpoints     dynamic.ResourceInterface
 		pingerMap     map[string]*fake.Pinger
+		probeTypes    map[string]pinger.ProbeType
+		probePorts    map[string]int
 		stopCh        chan struct{}
 	)
 
 	BeforeEach(func() {
+		probeTypes = map[string]pinger.ProbeType{}
+		probePorts = map[string]int{}
 		pingerMap = map[string]*fake.Pinger{
 			healthCheckIP1: fake.NewPinger(healthCheckIP1),
 			healthCheckIP2: fake.NewPinger(healthCheckIP2),
@@ -83,6 +90,8 @@ var _ = Describe("Controller", func() {
 			ClusterID:          localClusterID,
 			PingInterval:       3,
 			MaxPacketLossCount: 4,
+			ClusterProbeTypes:  map[string]pinger.ProbeType{remoteClusterID2: pinger.UDPProbe},
+			ProbePort:          probePort,
 		}
 
 		config.NewPinger = func(pingerCfg pinger.Config) pinger.Interface {
@@ -92,6 +101,10 @@ var _ = Describe("Controller", func() {
 
 			p, ok := pingerMap[pingerCfg.IP]
 			Expect(ok).To(BeTrue())
+
+			probeTypes[pingerCfg.IP] = pingerCfg.ProbeType
+			probePorts[pingerCfg.IP] = pingerCfg.Port
+
 			return p
 		}
 
@@ -105,11 +118,12 @@ var _ = Describe("Controller", func() {
 		close(stopCh)
 	})
 
-	createEndpoint := func(clusterID, healthCheckIP string) *submarinerv1.Endpoint {
+	createEndpointWithConfig := func(clusterID, healthCheckIP string, backendConfig map[string]string) *submarinerv1.Endpoint {
 		endpointSpec := &submarinerv1.EndpointSpec{
 			ClusterID:     clusterID,
 			CableName:     fmt.Sprintf("submariner-cable-%s-192-68-1-20", clusterID),
 			HealthCheckIP: healthCheckIP,
+			BackendConfig: backendConfig,
 		}
 
 		endpointName, err := endpointSpec.GenerateName()
@@ -127,6 +141,10 @@ var _ = Describe("Controller", func() {
 		return endpoint
 	}
 
+	createEndpoint := func(clusterID, healthCheckIP string) *submarinerv1.Endpoint {
+		return createEndpointWithConfig(clusterID, healthCheckIP, nil)
+	}
+
 	newLatencyInfo := func() *pinger.LatencyInfo {
 		return &pinger.LatencyInfo{
 			ConnectionStatus: pinger.Connected,
@@ -190,6 +208,37 @@ var _ = Describe("Controller", func() {
 		})
 	})
 
+	When("remote Endpoints are created for clusters with and without a configured probe type", func() {
+		It("should start Pingers with the respective probe types", func() {
+			createEndpoint(remoteClusterID1, healthCheckIP1)
+			pingerMap[healthCheckIP1].AwaitStart()
+
+			createEndpoint(remoteClusterID2, healthCheckIP2)
+			pingerMap[healthCheckIP2].AwaitStart()
+
+			Expect(probeTypes).To(Equal(map[string]pinger.ProbeType{
+				healthCheckIP1: pinger.ICMPProbe,
+				healthCheckIP2: pinger.UDPProbe,
+			}))
+		})
+	})
+
+	When("a remote Endpoint advertises its health check probe port", func() {
+		It("should start a Pinger with the remote port", func() {
+			createEndpoint(remoteClusterID1, healthCheckIP1)
+			pingerMap[healthCheckIP1].AwaitStart()
+
+			createEndpointWithConfig(remoteClusterID2, healthCheckIP2,
+				map[string]string{submarinerv1.HealthCheckProbePortConfig: strconv.Itoa(remoteProbePort)})
+			pingerMap[healthCheckIP2].AwaitStart()
+
+			Expect(probePorts).To(Equal(map[string]int{
+				healthCheckIP1: probePort,
+				healthCheckIP2: remoteProbePort,
+			}))
+		})
+	})
+
 	When("a local Endpoint is created", func() {
 		It("should not start a Pinger", func() {
 			createEndpoint(localClusterID, healthCheckIP1)
@@ -236,6 +285,19 @@ var _ = Describe("Controller", func() {
 			})
 		})
 
+		When("the health check probe port was changed", func() {
+			It("should stop the Pinger and start a new one", func() {
+				oldPinger := pingerMap[healthCheckIP1]
+				pingerMap[healthCheckIP1] = fake.NewPinger(healthCheckIP1)
+
+				endpoint.Spec.BackendConfig = map[string]string{submarinerv1.HealthCheckProbePortConfig: strconv.Itoa(remoteProbePort)}
+
+				test.UpdateResource(endpoints, endpoint)
+				oldPinger.AwaitStop()
+				pingerMap[healthCheckIP1].AwaitStart()
+			})
+		})
+
 		When("the HealthCheckIP did not changed", func() {
 			It("should not start a new Pinger", func() {
 				endpoint.Spec.Hostname = "raiders"
@@ -0,0 +1,197 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cableengine
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/pinger"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	defaultRemediationInterval   = time.Second
+	defaultRemediationMaxBackoff = 5 * time.Minute
+
+	ReasonCableReconnecting    = "CableReconnecting"
+	ReasonCableReconnectFailed = "CableReconnectFailed"
+	ReasonCableReconnected     = "CableReconnected"
+)
+
+type LatencyInfoProvider interface {
+	GetLatencyInfo(endpoint *v1.EndpointSpec) *pinger.LatencyInfo
+}
+
+type RemediatorConfig struct {
+	Engine        Engine
+	HealthChecker LatencyInfoProvider
+	// EventRecorder records the remediation Events on EventObjectRef.
+	EventRecorder  record.EventRecorder
+	EventObjectRef *corev1.ObjectReference
+	// FailureThreshold is the number of consecutive health check intervals a cable must be reported as failed before it's
+	// reconnected.
+	FailureThreshold int
+	// Interval is the health check interval. The backoff between reconnect attempts for a cable starts at
+	// FailureThreshold intervals and doubles on each attempt up to MaxBackoff.
+	Interval      time.Duration
+	MaxBackoff    time.Duration
+	RediscoverNAT bool
+}
+
+// Remediator reconnects cables which the health checker reports as failed.
+type Remediator struct {
+	sync.Mutex
+	config RemediatorConfig
+	cables map[string]*cableRemediation
+}
+
+type cableRemediation struct {
+	clusterID   string
+	failures    int
+	attempts    int
+	nextAttempt time.Time
+}
+
+func NewRemediator(config *RemediatorConfig) *Remediator {
+	r := &Remediator{
+		config: *config,
+		cables: map[string]*cableRemediation{},
+	}
+
+	if r.config.Interval == 0 {
+		r.config.Interval = defaultRemediationInterval
+	}
+
+	if r.config.MaxBackoff == 0 {
+		r.config.MaxBackoff = defaultRemediationMaxBackoff
+	}
+
+	return r
+}
+
+func (r *Remediator) Run(stopCh <-chan struct{}) {
+	logger.Infof("Starting the cable remediator with FailureThreshold: %d, RediscoverNAT: %v", r.config.FailureThreshold,
+		r.config.RediscoverNAT)
+
+	wait.Until(r.remediate, r.config.Interval, stopCh)
+}
+
+func (r *Remediator) remediate() {
+	r.Lock()
+	defer r.Unlock()
+
+	connections, err := r.config.Engine.ListCableConnections()
+	if err != nil {
+		logger.Errorf(err, "Error listing the cable connections")
+		return
+	}
+
+	seen := map[string]bool{}
+
+	for i := range connections {
+		endpoint := &connections[i].Endpoint
+		seen[endpoint.CableName] = true
+
+		latencyInfo := r.config.HealthChecker.GetLatencyInfo(endpoint)
+		if latencyInfo == nil {
+			continue
+		}
+
+		state := r.cables[endpoint.CableName]
+
+		if latencyInfo.ConnectionStatus != pinger.ConnectionError {
+			if state != nil && state.attempts > 0 {
+				logger.Infof("Cable %q is healthy after %d reconnect attempt(s)", endpoint.CableName, state.attempts)
+				r.recordEvent(corev1.EventTypeNormal, ReasonCableReconnected, "Cable %q to cluster %q is healthy after %d "+
+					"reconnect attempt(s)", endpoint.CableName, endpoint.ClusterID, state.attempts)
+			}
+
+			delete(r.cables, endpoint.CableName)
+
+			continue
+		}
+
+		if state == nil {
+			state = &cableRemediation{clusterID: endpoint.ClusterID}
+			r.cables[endpoint.CableName] = state
+		}
+
+		state.failures++
+	}
+
+	for cableName, state := range r.cables {
+		// A cable being reconnected may be transiently missing from the connections.
+		if !seen[cableName] && state.attempts == 0 {
+			delete(r.cables, cableName)
+			continue
+		}
+
+		if state.failures < r.config.FailureThreshold || time.Now().Before(state.nextAttempt) {
+			continue
+		}
+
+		r.reconnect(cableName, state)
+	}
+}
+
+func (r *Remediator) reconnect(cableName string, state *cableRemediation) {
+	state.attempts++
+	state.nextAttempt = time.Now().Add(r.backoff(state.attempts))
+
+	logger.Warningf("Reconnecting cable %q after %d failed health checks (attempt %d)", cableName, state.failures, state.attempts)
+	r.recordEvent(corev1.EventTypeWarning, ReasonCableReconnecting, "Reconnecting cable %q to cluster %q after %d failed "+
+		"health checks (attempt %d)", cableName, state.clusterID, state.failures, state.attempts)
+
+	err := r.config.Engine.ReconnectCable(cableName, r.config.RediscoverNAT)
+	if errors.Is(err, ErrCableNotInstalled) {
+		logger.Infof("Cable %q is no longer installed - stopping remediation", cableName)
+		delete(r.cables, cableName)
+
+		return
+	}
+
+	if err != nil {
+		logger.Errorf(err, "Error reconnecting cable %q", cableName)
+		r.recordEvent(corev1.EventTypeWarning, ReasonCableReconnectFailed, "Error reconnecting cable %q to cluster %q: %v",
+			cableName, state.clusterID, err)
+	}
+}
+
+func (r *Remediator) backoff(attempts int) time.Duration {
+	backoff := r.config.Interval * time.Duration(max(r.config.FailureThreshold, 1))
+
+	for i := 1; i < attempts && backoff < r.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, r.config.MaxBackoff)
+}
+
+func (r *Remediator) recordEvent(eventType, reason, messageFmt string, args ...interface{}) {
+	if r.config.EventRecorder == nil || r.config.EventObjectRef == nil {
+		return
+	}
+
+	r.config.EventRecorder.Eventf(r.config.EventObjectRef, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,141 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cableengine_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	subv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cableengine"
+	fakeengine "github.com/submariner-io/submariner/pkg/cableengine/fake"
+	"github.com/submariner-io/submariner/pkg/pinger"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+var _ = Describe("Remediator", func() {
+	const cableName = "submariner-cable-remote-1-1-1-1"
+
+	var (
+		engine        *fakeengine.Engine
+		healthChecker *fakeLatencyInfoProvider
+		recorder      *record.FakeRecorder
+		stopCh        chan struct{}
+	)
+
+	BeforeEach(func() {
+		engine = fakeengine.New()
+		engine.Connections = []subv1.Connection{{
+			Endpoint: subv1.EndpointSpec{ClusterID: "remote", CableName: cableName},
+		}}
+
+		healthChecker = &fakeLatencyInfoProvider{status: pinger.Connected}
+		recorder = record.NewFakeRecorder(100)
+		stopCh = make(chan struct{})
+	})
+
+	JustBeforeEach(func() {
+		remediator := cableengine.NewRemediator(&cableengine.RemediatorConfig{
+			Engine:           engine,
+			HealthChecker:    healthChecker,
+			EventRecorder:    recorder,
+			EventObjectRef:   &corev1.ObjectReference{Kind: "Gateway", Namespace: "submariner", Name: "gw"},
+			FailureThreshold: 3,
+			Interval:         50 * time.Millisecond,
+			MaxBackoff:       200 * time.Millisecond,
+		})
+
+		go remediator.Run(stopCh)
+	})
+
+	AfterEach(func() {
+		close(stopCh)
+	})
+
+	When("the cable is healthy", func() {
+		It("should not reconnect it", func() {
+			engine.VerifyNoReconnectCable()
+		})
+	})
+
+	When("the cable is reported as failed", func() {
+		BeforeEach(func() {
+			healthChecker.setStatus(pinger.ConnectionError)
+		})
+
+		It("should reconnect it and record Events", func() {
+			engine.VerifyReconnectCable(cableName)
+			Eventually(recorder.Events).Should(Receive(HavePrefix(corev1.EventTypeWarning + " " + cableengine.ReasonCableReconnecting)))
+
+			healthChecker.setStatus(pinger.Connected)
+			Eventually(recorder.Events).Should(Receive(HavePrefix(corev1.EventTypeNormal + " " + cableengine.ReasonCableReconnected)))
+		})
+
+		Context("and continues to fail after reconnecting", func() {
+			It("should retry reconnecting it", func() {
+				engine.VerifyReconnectCable(cableName)
+				engine.VerifyReconnectCable(cableName)
+			})
+		})
+
+		Context("and reconnecting fails", func() {
+			BeforeEach(func() {
+				engine.ErrOnReconnectCable = errors.New("fake reconnect error")
+			})
+
+			It("should record a failure Event", func() {
+				Eventually(recorder.Events).Should(Receive(HavePrefix(corev1.EventTypeWarning + " " + cableengine.ReasonCableReconnectFailed)))
+			})
+		})
+
+		Context("and the cable is no longer installed", func() {
+			BeforeEach(func() {
+				engine.ErrOnReconnectCable = cableengine.ErrCableNotInstalled
+				engine.Connections = []subv1.Connection{}
+			})
+
+			It("should not reconnect it", func() {
+				engine.VerifyNoReconnectCable()
+			})
+		})
+	})
+})
+
+type fakeLatencyInfoProvider struct {
+	sync.Mutex
+	status pinger.ConnectionStatus
+}
+
+func (f *fakeLatencyInfoProvider) GetLatencyInfo(_ *subv1.EndpointSpec) *pinger.LatencyInfo {
+	f.Lock()
+	defer f.Unlock()
+
+	return &pinger.LatencyInfo{ConnectionStatus: f.status}
+}
+
+func (f *fakeLatencyInfoProvider) setStatus(status pinger.ConnectionStatus) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.status = status
+}
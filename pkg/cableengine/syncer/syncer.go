@@ -22,6 +22,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -35,7 +36,11 @@ import (
 	"github.com/submariner-io/submariner/pkg/cableengine"
 	"github.com/submariner-io/submariner/pkg/cableengine/healthchecker"
 	v1typed "github.com/submariner-io/submariner/pkg/client/clientset/versioned/typed/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/controllers/datastoresyncer"
+	"github.com/submariner-io/submariner/pkg/natdiscovery"
 	"github.com/submariner-io/submariner/pkg/pinger"
+	"github.com/submariner-io/submariner/pkg/pmtu"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -43,12 +48,21 @@ import (
 )
 
 type GatewaySyncer struct {
-	mutex       sync.Mutex
-	client      v1typed.GatewayInterface
-	engine      cableengine.Engine
-	version     string
-	statusError error
-	healthCheck healthchecker.Interface
+	mutex          sync.Mutex
+	client         v1typed.GatewayInterface
+	engine         cableengine.Engine
+	version        string
+	statusError    error
+	healthCheck    healthchecker.Interface
+	natDiscovery   natdiscovery.Interface
+	pathMTU        pmtu.Interface
+	remoteClusters RemoteClusterStatusProvider
+}
+
+// RemoteClusterStatusProvider reports the remote clusters whose Endpoints aren't synced.
+type RemoteClusterStatusProvider interface {
+	GetCIDROverlaps() []datastoresyncer.CIDROverlap
+	GetFilteredPeers() []datastoresyncer.FilteredPeer
 }
 
 var (
@@ -72,13 +86,17 @@ func init() {
 
 // NewEngine creates a new Engine for the local cluster.
 func NewGatewaySyncer(engine cableengine.Engine, client v1typed.GatewayInterface,
-	version string, healthCheck healthchecker.Interface,
+	version string, healthCheck healthchecker.Interface, natDiscovery natdiscovery.Interface, pathMTU pmtu.Interface,
+	remoteClusters RemoteClusterStatusProvider,
 ) *GatewaySyncer {
 	return &GatewaySyncer{
-		client:      client,
-		engine:      engine,
-		version:     version,
-		healthCheck: healthCheck,
+		client:         client,
+		engine:         engine,
+		version:        version,
+		healthCheck:    healthCheck,
+		natDiscovery:   natDiscovery,
+		pathMTU:        pathMTU,
+		remoteClusters: remoteClusters,
 	}
 }
 
@@ -121,7 +139,14 @@ func (gs *GatewaySyncer) syncGatewayStatusSafe(ctx context.Context) {
 
 	result, err := util.CreateOrUpdate(ctx, gs.gatewayResourceInterface(), gatewayObj,
 		func(existing *v1.Gateway) (*v1.Gateway, error) {
+			// Merge the conditions so their last transition times are preserved.
+			conditions := existing.Status.Conditions
+			for i := range gatewayObj.Status.Conditions {
+				meta.SetStatusCondition(&conditions, gatewayObj.Status.Conditions[i])
+			}
+
 			existing.Status = gatewayObj.Status
+			existing.Status.Conditions = conditions
 
 			if existing.Annotations == nil {
 				existing.Annotations = map[string]string{}
@@ -267,13 +292,91 @@ func (gs *GatewaySyncer) generateGatewayObject() *v1.Gateway {
 		}
 	}
 
+	if gs.pathMTU != nil {
+		for index := range connections {
+			connections[index].PathMTU = gs.pathMTU.GetPathMTU(&connections[index])
+		}
+	}
+
 	gateway.Status.Connections = connections
 
+	if gs.natDiscovery != nil {
+		gateway.Status.Conditions = []metav1.Condition{symmetricNATCondition(gs.natDiscovery.SymmetricNATDetected())}
+	}
+
+	if gs.remoteClusters != nil {
+		gateway.Status.Conditions = append(gateway.Status.Conditions,
+			cidrOverlapCondition(gs.remoteClusters.GetCIDROverlaps()),
+			peeringFilteredCondition(gs.remoteClusters.GetFilteredPeers()))
+	}
+
 	logger.V(log.TRACE).Infof("Generated Gateway object: %+v", gateway)
 
 	return &gateway
 }
 
+func symmetricNATCondition(detected bool) metav1.Condition {
+	if detected {
+		return metav1.Condition{
+			Type:    string(v1.GatewaySymmetricNAT),
+			Status:  metav1.ConditionTrue,
+			Reason:  "SymmetricNATDetected",
+			Message: "Remote endpoints observed different NAT mappings for the gateway",
+		}
+	}
+
+	return metav1.Condition{
+		Type:   string(v1.GatewaySymmetricNAT),
+		Status: metav1.ConditionFalse,
+		Reason: "NoSymmetricNATDetected",
+	}
+}
+
+func cidrOverlapCondition(overlaps []datastoresyncer.CIDROverlap) metav1.Condition {
+	if len(overlaps) == 0 {
+		return metav1.Condition{
+			Type:   string(v1.GatewayCIDROverlap),
+			Status: metav1.ConditionFalse,
+			Reason: "NoCIDROverlap",
+		}
+	}
+
+	conflicts := make([]string, len(overlaps))
+	for i := range overlaps {
+		conflicts[i] = fmt.Sprintf("cluster %q: remote CIDRs %v overlap local CIDRs %v (resolvable by Globalnet: %v)",
+			overlaps[i].ClusterID, overlaps[i].RemoteCIDRs, overlaps[i].LocalCIDRs, overlaps[i].GlobalnetResolvable)
+	}
+
+	return metav1.Condition{
+		Type:    string(v1.GatewayCIDROverlap),
+		Status:  metav1.ConditionTrue,
+		Reason:  "CIDROverlapDetected",
+		Message: "The Endpoints of the following remote clusters aren't synced: " + strings.Join(conflicts, "; "),
+	}
+}
+
+func peeringFilteredCondition(peers []datastoresyncer.FilteredPeer) metav1.Condition {
+	if len(peers) == 0 {
+		return metav1.Condition{
+			Type:   string(v1.GatewayPeeringFiltered),
+			Status: metav1.ConditionFalse,
+			Reason: "NoPeersFiltered",
+		}
+	}
+
+	filtered := make([]string, len(peers))
+	for i := range peers {
+		filtered[i] = fmt.Sprintf("cluster %q: %s", peers[i].ClusterID, peers[i].Reason)
+	}
+
+	return metav1.Condition{
+		Type:    string(v1.GatewayPeeringFiltered),
+		Status:  metav1.ConditionTrue,
+		Reason:  "PeersFiltered",
+		Message: "The following remote clusters aren't connected due to the peering policy: " + strings.Join(filtered, "; "),
+	}
+}
+
 // CleanupGatewayEntry removes this Gateway entry from the k8s API, it does not
 // propagate error up because it's a termination function that we also provide externally.
 func (gs *GatewaySyncer) CleanupGatewayEntry(ctx context.Context) {
@@ -23,6 +23,8 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -43,8 +45,11 @@ import (
 	fakeClientset "github.com/submariner-io/submariner/pkg/client/clientset/versioned/fake"
 	submarinerClientsetv1 "github.com/submariner-io/submariner/pkg/client/clientset/versioned/typed/submariner.io/v1"
 	submarinerInformers "github.com/submariner-io/submariner/pkg/client/informers/externalversions"
+	"github.com/submariner-io/submariner/pkg/controllers/datastoresyncer"
+	"github.com/submariner-io/submariner/pkg/natdiscovery"
 	"github.com/submariner-io/submariner/pkg/pinger"
 	"github.com/submariner-io/submariner/pkg/pinger/fake"
+	"github.com/submariner-io/submariner/pkg/pmtu"
 	"github.com/submariner-io/submariner/pkg/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -195,6 +200,118 @@ func testGatewaySyncing() {
 		})
 	})
 
+	When("NAT discovery is configured", func() {
+		BeforeEach(func() {
+			t.natDiscovery = &fakeNATDiscovery{}
+			t.expectedGateway.Status.Conditions = []metav1.Condition{{
+				Type:   string(submarinerv1.GatewaySymmetricNAT),
+				Status: metav1.ConditionFalse,
+				Reason: "NoSymmetricNATDetected",
+			}}
+		})
+
+		It("should report the symmetric NAT condition", func() {
+			t.awaitGatewayUpdated(t.expectedGateway)
+
+			t.natDiscovery.symmetricNAT.Store(true)
+
+			t.expectedGateway.Status.Conditions[0].Status = metav1.ConditionTrue
+			t.expectedGateway.Status.Conditions[0].Reason = "SymmetricNATDetected"
+			t.expectedGateway.Status.Conditions[0].Message = "Remote endpoints observed different NAT mappings for the gateway"
+			t.awaitGatewayUpdated(t.expectedGateway)
+		})
+	})
+
+	When("remote cluster status reporting is configured", func() {
+		BeforeEach(func() {
+			t.remoteClusters = &fakeRemoteClusters{}
+			t.expectedGateway.Status.Conditions = []metav1.Condition{
+				{
+					Type:   string(submarinerv1.GatewayCIDROverlap),
+					Status: metav1.ConditionFalse,
+					Reason: "NoCIDROverlap",
+				},
+				{
+					Type:   string(submarinerv1.GatewayPeeringFiltered),
+					Status: metav1.ConditionFalse,
+					Reason: "NoPeersFiltered",
+				},
+			}
+		})
+
+		It("should report the CIDR overlap condition", func() {
+			t.awaitGatewayUpdated(t.expectedGateway)
+
+			t.remoteClusters.setOverlaps(datastoresyncer.CIDROverlap{
+				ClusterID:           "north",
+				LocalCIDRs:          []string{"10.0.0.0/16"},
+				RemoteCIDRs:         []string{"10.0.0.0/24"},
+				GlobalnetResolvable: true,
+			})
+
+			t.expectedGateway.Status.Conditions[0].Status = metav1.ConditionTrue
+			t.expectedGateway.Status.Conditions[0].Reason = "CIDROverlapDetected"
+			t.expectedGateway.Status.Conditions[0].Message = "The Endpoints of the following remote clusters aren't synced: " +
+				"cluster \"north\": remote CIDRs [10.0.0.0/24] overlap local CIDRs [10.0.0.0/16] (resolvable by Globalnet: true)"
+			t.awaitGatewayUpdated(t.expectedGateway)
+
+			t.remoteClusters.setOverlaps()
+
+			t.expectedGateway.Status.Conditions[0].Status = metav1.ConditionFalse
+			t.expectedGateway.Status.Conditions[0].Reason = "NoCIDROverlap"
+			t.expectedGateway.Status.Conditions[0].Message = ""
+			t.awaitGatewayUpdated(t.expectedGateway)
+		})
+
+		It("should report the peering filtered condition", func() {
+			t.awaitGatewayUpdated(t.expectedGateway)
+
+			t.remoteClusters.setFilteredPeers(datastoresyncer.FilteredPeer{
+				ClusterID: "north",
+				Reason:    "the cluster is in the denied list",
+			})
+
+			t.expectedGateway.Status.Conditions[1].Status = metav1.ConditionTrue
+			t.expectedGateway.Status.Conditions[1].Reason = "PeersFiltered"
+			t.expectedGateway.Status.Conditions[1].Message = "The following remote clusters aren't connected due to the peering " +
+				"policy: cluster \"north\": the cluster is in the denied list"
+			t.awaitGatewayUpdated(t.expectedGateway)
+
+			t.remoteClusters.setFilteredPeers()
+
+			t.expectedGateway.Status.Conditions[1].Status = metav1.ConditionFalse
+			t.expectedGateway.Status.Conditions[1].Reason = "NoPeersFiltered"
+			t.expectedGateway.Status.Conditions[1].Message = ""
+			t.awaitGatewayUpdated(t.expectedGateway)
+		})
+	})
+
+	When("path MTU discovery is configured", func() {
+		BeforeEach(func() {
+			t.pathMTU = &fakePathMTU{pathMTU: 1400}
+
+			connection := submarinerv1.Connection{
+				Status:  submarinerv1.Connected,
+				UsingIP: "1.2.3.5",
+				Endpoint: submarinerv1.EndpointSpec{
+					ClusterID: "north",
+					CableName: "submariner-cable-north-192-68-1-20",
+					PrivateIP: "192.6.1.21",
+					Backend:   "libreswan",
+				},
+			}
+
+			t.engine.Connections = []submarinerv1.Connection{connection}
+
+			connection.PathMTU = 1400
+			t.expectedGateway.Status.Connections = []submarinerv1.Connection{connection}
+		})
+
+		It("should publish the path MTU on the connections", func() {
+			t.awaitGatewayUpdated(t.expectedGateway)
+		})
+	})
+
 	Context("", func() {
 		BeforeEach(func() {
 			t.expectedGateway.Annotations = map[string]string{"foo": "bar"}
@@ -486,6 +603,9 @@ type testDriver struct {
 	stopInformer         chan struct{}
 	savedErrorHandlers   []utilruntime.ErrorHandler
 	handledError         chan error
+	natDiscovery         *fakeNATDiscovery
+	pathMTU              *fakePathMTU
+	remoteClusters       *fakeRemoteClusters
 }
 
 func newTestDriver() *testDriver {
@@ -560,7 +680,23 @@ func (t *testDriver) run() {
 
 	t.endpoints = dynamicClient.Resource(*test.GetGroupVersionResourceFor(restMapper, &submarinerv1.Endpoint{})).Namespace(namespace)
 
-	t.syncer = syncer.NewGatewaySyncer(t.engine, t.gateways, t.expectedGateway.Status.Version, t.healthChecker)
+	var natDiscovery natdiscovery.Interface
+	if t.natDiscovery != nil {
+		natDiscovery = t.natDiscovery
+	}
+
+	var pathMTU pmtu.Interface
+	if t.pathMTU != nil {
+		pathMTU = t.pathMTU
+	}
+
+	var remoteClusters syncer.RemoteClusterStatusProvider
+	if t.remoteClusters != nil {
+		remoteClusters = t.remoteClusters
+	}
+
+	t.syncer = syncer.NewGatewaySyncer(t.engine, t.gateways, t.expectedGateway.Status.Version, t.healthChecker, natDiscovery,
+		pathMTU, remoteClusters)
 
 	informerFactory := submarinerInformers.NewSharedInformerFactory(t.client, 0)
 	informer := informerFactory.Submariner().V1().Gateways().Informer()
@@ -624,6 +760,61 @@ func (t *testDriver) awaitGateway(gatewayChan chan *submarinerv1.Gateway, expect
 	}, 5).Should(equalGateway(expected))
 }
 
+type fakeNATDiscovery struct {
+	natdiscovery.Interface
+	symmetricNAT atomic.Bool
+}
+
+func (n *fakeNATDiscovery) SymmetricNATDetected() bool {
+	return n.symmetricNAT.Load()
+}
+
+type fakePathMTU struct {
+	pathMTU int
+}
+
+func (p *fakePathMTU) GetPathMTU(connection *submarinerv1.Connection) int {
+	if connection.Status != submarinerv1.Connected {
+		return 0
+	}
+
+	return p.pathMTU
+}
+
+type fakeRemoteClusters struct {
+	sync.Mutex
+	overlaps      []datastoresyncer.CIDROverlap
+	filteredPeers []datastoresyncer.FilteredPeer
+}
+
+func (r *fakeRemoteClusters) GetCIDROverlaps() []datastoresyncer.CIDROverlap {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.overlaps
+}
+
+func (r *fakeRemoteClusters) GetFilteredPeers() []datastoresyncer.FilteredPeer {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.filteredPeers
+}
+
+func (r *fakeRemoteClusters) setOverlaps(overlaps ...datastoresyncer.CIDROverlap) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.overlaps = overlaps
+}
+
+func (r *fakeRemoteClusters) setFilteredPeers(peers ...datastoresyncer.FilteredPeer) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.filteredPeers = peers
+}
+
 func TestSyncer(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Cable engine syncer Suite")
@@ -649,6 +840,10 @@ func (m *equalGatewayMatcher) Match(x interface{}) (bool, error) {
 
 	actual = actual.DeepCopy()
 
+	for i := range actual.Status.Conditions {
+		actual.Status.Conditions[i].LastTransitionTime = metav1.Time{}
+	}
+
 	if m.expected.Status.StatusFailure != "" {
 		if !strings.Contains(actual.Status.StatusFailure, m.expected.Status.StatusFailure) {
 			return false, nil
@@ -88,13 +88,28 @@ func IsOverlapping(cidrList []string, cidr string) (bool, error) {
 }
 
 func ExtractIPv4Subnets(cidrList []string) []string {
-	var ipv4Cidrs []string
+	return ExtractSubnets(k8snet.IPv4, cidrList)
+}
+
+func ExtractIPv6Subnets(cidrList []string) []string {
+	return ExtractSubnets(k8snet.IPv6, cidrList)
+}
+
+// ExtractSubnets returns the CIDRs in the given list that belong to the given IP family.
+func ExtractSubnets(family k8snet.IPFamily, cidrList []string) []string {
+	var cidrs []string
 
 	for _, subnet := range cidrList {
-		if k8snet.IsIPv4CIDRString(subnet) {
-			ipv4Cidrs = append(ipv4Cidrs, subnet)
+		if k8snet.IPFamilyOfCIDRString(subnet) == family {
+			cidrs = append(cidrs, subnet)
 		}
 	}
 
-	return ipv4Cidrs
+	return cidrs
+}
+
+// SameFamily returns true if the given CIDRs belong to the same IP family.
+func SameFamily(cidr1, cidr2 string) bool {
+	family := k8snet.IPFamilyOfCIDRString(cidr1)
+	return family != k8snet.IPFamilyUnknown && family == k8snet.IPFamilyOfCIDRString(cidr2)
 }
@@ -27,12 +27,15 @@ import (
 // ConnectionApplyConfiguration represents a declarative configuration of the Connection type for use
 // with apply.
 type ConnectionApplyConfiguration struct {
-	Status        *v1.ConnectionStatus              `json:"status,omitempty"`
-	StatusMessage *string                           `json:"statusMessage,omitempty"`
-	Endpoint      *EndpointSpecApplyConfiguration   `json:"endpoint,omitempty"`
-	UsingIP       *string                           `json:"usingIP,omitempty"`
-	UsingNAT      *bool                             `json:"usingNAT,omitempty"`
-	LatencyRTT    *LatencyRTTSpecApplyConfiguration `json:"latencyRTT,omitempty"`
+	Status        *v1.ConnectionStatus                 `json:"status,omitempty"`
+	StatusMessage *string                              `json:"statusMessage,omitempty"`
+	Endpoint      *EndpointSpecApplyConfiguration      `json:"endpoint,omitempty"`
+	UsingIP       *string                              `json:"usingIP,omitempty"`
+	UsingNAT      *bool                                `json:"usingNAT,omitempty"`
+	LatencyRTT    *LatencyRTTSpecApplyConfiguration    `json:"latencyRTT,omitempty"`
+	CableDriver   *string                              `json:"cableDriver,omitempty"`
+	PathMTU       *int                                 `json:"pathMTU,omitempty"`
+	Traffic       *ConnectionTrafficApplyConfiguration `json:"traffic,omitempty"`
 }
 
 // ConnectionApplyConfiguration constructs a declarative configuration of the Connection type for use with
@@ -88,3 +91,27 @@ func (b *ConnectionApplyConfiguration) WithLatencyRTT(value *LatencyRTTSpecApply
 	b.LatencyRTT = value
 	return b
 }
+
+// WithCableDriver sets the CableDriver field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CableDriver field is set to the value of the last call.
+func (b *ConnectionApplyConfiguration) WithCableDriver(value string) *ConnectionApplyConfiguration {
+	b.CableDriver = &value
+	return b
+}
+
+// WithPathMTU sets the PathMTU field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PathMTU field is set to the value of the last call.
+func (b *ConnectionApplyConfiguration) WithPathMTU(value int) *ConnectionApplyConfiguration {
+	b.PathMTU = &value
+	return b
+}
+
+// WithTraffic sets the Traffic field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Traffic field is set to the value of the last call.
+func (b *ConnectionApplyConfiguration) WithTraffic(value *ConnectionTrafficApplyConfiguration) *ConnectionApplyConfiguration {
+	b.Traffic = value
+	return b
+}
@@ -23,16 +23,20 @@ package v1
 // EndpointSpecApplyConfiguration represents a declarative configuration of the EndpointSpec type for use
 // with apply.
 type EndpointSpecApplyConfiguration struct {
-	ClusterID     *string           `json:"cluster_id,omitempty"`
-	CableName     *string           `json:"cable_name,omitempty"`
-	HealthCheckIP *string           `json:"healthCheckIP,omitempty"`
-	Hostname      *string           `json:"hostname,omitempty"`
-	Subnets       []string          `json:"subnets,omitempty"`
-	PrivateIP     *string           `json:"private_ip,omitempty"`
-	PublicIP      *string           `json:"public_ip,omitempty"`
-	NATEnabled    *bool             `json:"nat_enabled,omitempty"`
-	Backend       *string           `json:"backend,omitempty"`
-	BackendConfig map[string]string `json:"backend_config,omitempty"`
+	ClusterID      *string                          `json:"cluster_id,omitempty"`
+	CableName      *string                          `json:"cable_name,omitempty"`
+	HealthCheckIP  *string                          `json:"healthCheckIP,omitempty"`
+	Hostname       *string                          `json:"hostname,omitempty"`
+	Subnets        []string                         `json:"subnets,omitempty"`
+	PrivateIP      *string                          `json:"private_ip,omitempty"`
+	PublicIP       *string                          `json:"public_ip,omitempty"`
+	NATEnabled     *bool                            `json:"nat_enabled,omitempty"`
+	Backend        *string                          `json:"backend,omitempty"`
+	BackendConfig  map[string]string                `json:"backend_config,omitempty"`
+	HealthCheckIPs []string                         `json:"healthCheckIPs,omitempty"`
+	PrivateIPs     []string                         `json:"private_ips,omitempty"`
+	PublicIPs      []string                         `json:"public_ips,omitempty"`
+	TransitRoutes  []TransitRouteApplyConfiguration `json:"transitRoutes,omitempty"`
 }
 
 // EndpointSpecApplyConfiguration constructs a declarative configuration of the EndpointSpec type for use with
@@ -128,3 +132,46 @@ func (b *EndpointSpecApplyConfiguration) WithBackendConfig(entries map[string]st
 	}
 	return b
 }
+
+// WithHealthCheckIPs adds the given value to the HealthCheckIPs field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the HealthCheckIPs field.
+func (b *EndpointSpecApplyConfiguration) WithHealthCheckIPs(values ...string) *EndpointSpecApplyConfiguration {
+	for i := range values {
+		b.HealthCheckIPs = append(b.HealthCheckIPs, values[i])
+	}
+	return b
+}
+
+// WithPrivateIPs adds the given value to the PrivateIPs field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the PrivateIPs field.
+func (b *EndpointSpecApplyConfiguration) WithPrivateIPs(values ...string) *EndpointSpecApplyConfiguration {
+	for i := range values {
+		b.PrivateIPs = append(b.PrivateIPs, values[i])
+	}
+	return b
+}
+
+// WithPublicIPs adds the given value to the PublicIPs field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the PublicIPs field.
+func (b *EndpointSpecApplyConfiguration) WithPublicIPs(values ...string) *EndpointSpecApplyConfiguration {
+	for i := range values {
+		b.PublicIPs = append(b.PublicIPs, values[i])
+	}
+	return b
+}
+
+// WithTransitRoutes adds the given value to the TransitRoutes field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the TransitRoutes field.
+func (b *EndpointSpecApplyConfiguration) WithTransitRoutes(values ...*TransitRouteApplyConfiguration) *EndpointSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithTransitRoutes")
+		}
+		b.TransitRoutes = append(b.TransitRoutes, *values[i])
+	}
+	return b
+}
@@ -22,16 +22,18 @@ package v1
 
 import (
 	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	metav1 "k8s.io/client-go/applyconfigurations/meta/v1"
 )
 
 // GatewayStatusApplyConfiguration represents a declarative configuration of the GatewayStatus type for use
 // with apply.
 type GatewayStatusApplyConfiguration struct {
-	Version       *string                         `json:"version,omitempty"`
-	HAStatus      *v1.HAStatus                    `json:"haStatus,omitempty"`
-	LocalEndpoint *EndpointSpecApplyConfiguration `json:"localEndpoint,omitempty"`
-	StatusFailure *string                         `json:"statusFailure,omitempty"`
-	Connections   []ConnectionApplyConfiguration  `json:"connections,omitempty"`
+	Version       *string                              `json:"version,omitempty"`
+	HAStatus      *v1.HAStatus                         `json:"haStatus,omitempty"`
+	LocalEndpoint *EndpointSpecApplyConfiguration      `json:"localEndpoint,omitempty"`
+	StatusFailure *string                              `json:"statusFailure,omitempty"`
+	Connections   []ConnectionApplyConfiguration       `json:"connections,omitempty"`
+	Conditions    []metav1.ConditionApplyConfiguration `json:"conditions,omitempty"`
 }
 
 // GatewayStatusApplyConfiguration constructs a declarative configuration of the GatewayStatus type for use with
@@ -84,3 +86,16 @@ func (b *GatewayStatusApplyConfiguration) WithConnections(values ...*ConnectionA
 	}
 	return b
 }
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *GatewayStatusApplyConfiguration) WithConditions(values ...*metav1.ConditionApplyConfiguration) *GatewayStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithConditions")
+		}
+		b.Conditions = append(b.Conditions, *values[i])
+	}
+	return b
+}
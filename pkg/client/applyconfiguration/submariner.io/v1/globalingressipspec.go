@@ -23,14 +23,17 @@ package v1
 import (
 	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // GlobalIngressIPSpecApplyConfiguration represents a declarative configuration of the GlobalIngressIPSpec type for use
 // with apply.
 type GlobalIngressIPSpecApplyConfiguration struct {
-	Target     *v1.TargetType               `json:"target,omitempty"`
-	ServiceRef *corev1.LocalObjectReference `json:"serviceRef,omitempty"`
-	PodRef     *corev1.LocalObjectReference `json:"podRef,omitempty"`
+	Target          *v1.TargetType               `json:"target,omitempty"`
+	ServiceRef      *corev1.LocalObjectReference `json:"serviceRef,omitempty"`
+	PodRef          *corev1.LocalObjectReference `json:"podRef,omitempty"`
+	RequestedIP     *string                      `json:"requestedIP,omitempty"`
+	RetentionPeriod *metav1.Duration             `json:"retentionPeriod,omitempty"`
 }
 
 // GlobalIngressIPSpecApplyConfiguration constructs a declarative configuration of the GlobalIngressIPSpec type for use with
@@ -62,3 +65,19 @@ func (b *GlobalIngressIPSpecApplyConfiguration) WithPodRef(value corev1.LocalObj
 	b.PodRef = &value
 	return b
 }
+
+// WithRequestedIP sets the RequestedIP field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RequestedIP field is set to the value of the last call.
+func (b *GlobalIngressIPSpecApplyConfiguration) WithRequestedIP(value string) *GlobalIngressIPSpecApplyConfiguration {
+	b.RequestedIP = &value
+	return b
+}
+
+// WithRetentionPeriod sets the RetentionPeriod field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RetentionPeriod field is set to the value of the last call.
+func (b *GlobalIngressIPSpecApplyConfiguration) WithRetentionPeriod(value metav1.Duration) *GlobalIngressIPSpecApplyConfiguration {
+	b.RetentionPeriod = &value
+	return b
+}
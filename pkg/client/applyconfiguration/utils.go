@@ -42,14 +42,28 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &submarineriov1.ClusterGlobalEgressIPSpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("ClusterSpec"):
 		return &submarineriov1.ClusterSpecApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("ClusterTrafficPolicy"):
+		return &submarineriov1.ClusterTrafficPolicyApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("ClusterTrafficPolicySpec"):
+		return &submarineriov1.ClusterTrafficPolicySpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("Connection"):
 		return &submarineriov1.ConnectionApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("ConnectionTraffic"):
+		return &submarineriov1.ConnectionTrafficApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("Endpoint"):
 		return &submarineriov1.EndpointApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("EndpointSpec"):
 		return &submarineriov1.EndpointSpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("Gateway"):
 		return &submarineriov1.GatewayApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("GatewayBenchmark"):
+		return &submarineriov1.GatewayBenchmarkApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("GatewayBenchmarkResults"):
+		return &submarineriov1.GatewayBenchmarkResultsApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("GatewayBenchmarkSpec"):
+		return &submarineriov1.GatewayBenchmarkSpecApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("GatewayBenchmarkStatus"):
+		return &submarineriov1.GatewayBenchmarkStatusApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("GatewayRoute"):
 		return &submarineriov1.GatewayRouteApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("GatewayStatus"):
@@ -68,6 +82,10 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &submarineriov1.GlobalIngressIPStatusApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("LatencyRTTSpec"):
 		return &submarineriov1.LatencyRTTSpecApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("MultiNamespaceGlobalEgressIP"):
+		return &submarineriov1.MultiNamespaceGlobalEgressIPApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("MultiNamespaceGlobalEgressIPSpec"):
+		return &submarineriov1.MultiNamespaceGlobalEgressIPSpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("NonGatewayRoute"):
 		return &submarineriov1.NonGatewayRouteApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("RemoteEndpoint"):
@@ -78,6 +96,10 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &submarineriov1.RouteAgentStatusApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("RoutePolicySpec"):
 		return &submarineriov1.RoutePolicySpecApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("TrafficPolicyPort"):
+		return &submarineriov1.TrafficPolicyPortApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("TransitRoute"):
+		return &submarineriov1.TransitRouteApplyConfiguration{}
 
 	}
 	return nil
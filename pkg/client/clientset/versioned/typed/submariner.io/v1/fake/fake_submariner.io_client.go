@@ -38,6 +38,10 @@ func (c *FakeSubmarinerV1) ClusterGlobalEgressIPs(namespace string) v1.ClusterGl
 	return &FakeClusterGlobalEgressIPs{c, namespace}
 }
 
+func (c *FakeSubmarinerV1) ClusterTrafficPolicies() v1.ClusterTrafficPolicyInterface {
+	return &FakeClusterTrafficPolicies{c}
+}
+
 func (c *FakeSubmarinerV1) Endpoints(namespace string) v1.EndpointInterface {
 	return &FakeEndpoints{c, namespace}
 }
@@ -46,6 +50,10 @@ func (c *FakeSubmarinerV1) Gateways(namespace string) v1.GatewayInterface {
 	return &FakeGateways{c, namespace}
 }
 
+func (c *FakeSubmarinerV1) GatewayBenchmarks(namespace string) v1.GatewayBenchmarkInterface {
+	return &FakeGatewayBenchmarks{c, namespace}
+}
+
 func (c *FakeSubmarinerV1) GatewayRoutes(namespace string) v1.GatewayRouteInterface {
 	return &FakeGatewayRoutes{c, namespace}
 }
@@ -58,6 +66,10 @@ func (c *FakeSubmarinerV1) GlobalIngressIPs(namespace string) v1.GlobalIngressIP
 	return &FakeGlobalIngressIPs{c, namespace}
 }
 
+func (c *FakeSubmarinerV1) MultiNamespaceGlobalEgressIPs() v1.MultiNamespaceGlobalEgressIPInterface {
+	return &FakeMultiNamespaceGlobalEgressIPs{c}
+}
+
 func (c *FakeSubmarinerV1) NonGatewayRoutes(namespace string) v1.NonGatewayRouteInterface {
 	return &FakeNonGatewayRoutes{c, namespace}
 }
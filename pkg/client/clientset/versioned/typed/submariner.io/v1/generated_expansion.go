@@ -24,16 +24,22 @@ type ClusterExpansion interface{}
 
 type ClusterGlobalEgressIPExpansion interface{}
 
+type ClusterTrafficPolicyExpansion interface{}
+
 type EndpointExpansion interface{}
 
 type GatewayExpansion interface{}
 
+type GatewayBenchmarkExpansion interface{}
+
 type GatewayRouteExpansion interface{}
 
 type GlobalEgressIPExpansion interface{}
 
 type GlobalIngressIPExpansion interface{}
 
+type MultiNamespaceGlobalEgressIPExpansion interface{}
+
 type NonGatewayRouteExpansion interface{}
 
 type RouteAgentExpansion interface{}
@@ -32,11 +32,14 @@ type SubmarinerV1Interface interface {
 	RESTClient() rest.Interface
 	ClustersGetter
 	ClusterGlobalEgressIPsGetter
+	ClusterTrafficPoliciesGetter
 	EndpointsGetter
 	GatewaysGetter
+	GatewayBenchmarksGetter
 	GatewayRoutesGetter
 	GlobalEgressIPsGetter
 	GlobalIngressIPsGetter
+	MultiNamespaceGlobalEgressIPsGetter
 	NonGatewayRoutesGetter
 	RouteAgentsGetter
 }
@@ -54,6 +57,10 @@ func (c *SubmarinerV1Client) ClusterGlobalEgressIPs(namespace string) ClusterGlo
 	return newClusterGlobalEgressIPs(c, namespace)
 }
 
+func (c *SubmarinerV1Client) ClusterTrafficPolicies() ClusterTrafficPolicyInterface {
+	return newClusterTrafficPolicies(c)
+}
+
 func (c *SubmarinerV1Client) Endpoints(namespace string) EndpointInterface {
 	return newEndpoints(c, namespace)
 }
@@ -62,6 +69,10 @@ func (c *SubmarinerV1Client) Gateways(namespace string) GatewayInterface {
 	return newGateways(c, namespace)
 }
 
+func (c *SubmarinerV1Client) GatewayBenchmarks(namespace string) GatewayBenchmarkInterface {
+	return newGatewayBenchmarks(c, namespace)
+}
+
 func (c *SubmarinerV1Client) GatewayRoutes(namespace string) GatewayRouteInterface {
 	return newGatewayRoutes(c, namespace)
 }
@@ -74,6 +85,10 @@ func (c *SubmarinerV1Client) GlobalIngressIPs(namespace string) GlobalIngressIPI
 	return newGlobalIngressIPs(c, namespace)
 }
 
+func (c *SubmarinerV1Client) MultiNamespaceGlobalEgressIPs() MultiNamespaceGlobalEgressIPInterface {
+	return newMultiNamespaceGlobalEgressIPs(c)
+}
+
 func (c *SubmarinerV1Client) NonGatewayRoutes(namespace string) NonGatewayRouteInterface {
 	return newNonGatewayRoutes(c, namespace)
 }
@@ -59,16 +59,22 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().Clusters().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("clusterglobalegressips"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().ClusterGlobalEgressIPs().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("clustertrafficpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().ClusterTrafficPolicies().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("endpoints"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().Endpoints().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("gateways"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().Gateways().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("gatewaybenchmarks"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().GatewayBenchmarks().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("gatewayroutes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().GatewayRoutes().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("globalegressips"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().GlobalEgressIPs().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("globalingressips"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().GlobalIngressIPs().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("multinamespaceglobalegressips"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().MultiNamespaceGlobalEgressIPs().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("nongatewayroutes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Submariner().V1().NonGatewayRoutes().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("routeagents"):
@@ -30,16 +30,22 @@ type Interface interface {
 	Clusters() ClusterInformer
 	// ClusterGlobalEgressIPs returns a ClusterGlobalEgressIPInformer.
 	ClusterGlobalEgressIPs() ClusterGlobalEgressIPInformer
+	// ClusterTrafficPolicies returns a ClusterTrafficPolicyInformer.
+	ClusterTrafficPolicies() ClusterTrafficPolicyInformer
 	// Endpoints returns a EndpointInformer.
 	Endpoints() EndpointInformer
 	// Gateways returns a GatewayInformer.
 	Gateways() GatewayInformer
+	// GatewayBenchmarks returns a GatewayBenchmarkInformer.
+	GatewayBenchmarks() GatewayBenchmarkInformer
 	// GatewayRoutes returns a GatewayRouteInformer.
 	GatewayRoutes() GatewayRouteInformer
 	// GlobalEgressIPs returns a GlobalEgressIPInformer.
 	GlobalEgressIPs() GlobalEgressIPInformer
 	// GlobalIngressIPs returns a GlobalIngressIPInformer.
 	GlobalIngressIPs() GlobalIngressIPInformer
+	// MultiNamespaceGlobalEgressIPs returns a MultiNamespaceGlobalEgressIPInformer.
+	MultiNamespaceGlobalEgressIPs() MultiNamespaceGlobalEgressIPInformer
 	// NonGatewayRoutes returns a NonGatewayRouteInformer.
 	NonGatewayRoutes() NonGatewayRouteInformer
 	// RouteAgents returns a RouteAgentInformer.
@@ -67,6 +73,11 @@ func (v *version) ClusterGlobalEgressIPs() ClusterGlobalEgressIPInformer {
 	return &clusterGlobalEgressIPInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ClusterTrafficPolicies returns a ClusterTrafficPolicyInformer.
+func (v *version) ClusterTrafficPolicies() ClusterTrafficPolicyInformer {
+	return &clusterTrafficPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Endpoints returns a EndpointInformer.
 func (v *version) Endpoints() EndpointInformer {
 	return &endpointInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -77,6 +88,11 @@ func (v *version) Gateways() GatewayInformer {
 	return &gatewayInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// GatewayBenchmarks returns a GatewayBenchmarkInformer.
+func (v *version) GatewayBenchmarks() GatewayBenchmarkInformer {
+	return &gatewayBenchmarkInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // GatewayRoutes returns a GatewayRouteInformer.
 func (v *version) GatewayRoutes() GatewayRouteInformer {
 	return &gatewayRouteInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -92,6 +108,11 @@ func (v *version) GlobalIngressIPs() GlobalIngressIPInformer {
 	return &globalIngressIPInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// MultiNamespaceGlobalEgressIPs returns a MultiNamespaceGlobalEgressIPInformer.
+func (v *version) MultiNamespaceGlobalEgressIPs() MultiNamespaceGlobalEgressIPInformer {
+	return &multiNamespaceGlobalEgressIPInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // NonGatewayRoutes returns a NonGatewayRouteInformer.
 func (v *version) NonGatewayRoutes() NonGatewayRouteInformer {
 	return &nonGatewayRouteInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -36,6 +36,10 @@ type ClusterGlobalEgressIPListerExpansion interface{}
 // ClusterGlobalEgressIPNamespaceLister.
 type ClusterGlobalEgressIPNamespaceListerExpansion interface{}
 
+// ClusterTrafficPolicyListerExpansion allows custom methods to be added to
+// ClusterTrafficPolicyLister.
+type ClusterTrafficPolicyListerExpansion interface{}
+
 // EndpointListerExpansion allows custom methods to be added to
 // EndpointLister.
 type EndpointListerExpansion interface{}
@@ -52,6 +56,14 @@ type GatewayListerExpansion interface{}
 // GatewayNamespaceLister.
 type GatewayNamespaceListerExpansion interface{}
 
+// GatewayBenchmarkListerExpansion allows custom methods to be added to
+// GatewayBenchmarkLister.
+type GatewayBenchmarkListerExpansion interface{}
+
+// GatewayBenchmarkNamespaceListerExpansion allows custom methods to be added to
+// GatewayBenchmarkNamespaceLister.
+type GatewayBenchmarkNamespaceListerExpansion interface{}
+
 // GatewayRouteListerExpansion allows custom methods to be added to
 // GatewayRouteLister.
 type GatewayRouteListerExpansion interface{}
@@ -76,6 +88,10 @@ type GlobalIngressIPListerExpansion interface{}
 // GlobalIngressIPNamespaceLister.
 type GlobalIngressIPNamespaceListerExpansion interface{}
 
+// MultiNamespaceGlobalEgressIPListerExpansion allows custom methods to be added to
+// MultiNamespaceGlobalEgressIPLister.
+type MultiNamespaceGlobalEgressIPListerExpansion interface{}
+
 // NonGatewayRouteListerExpansion allows custom methods to be added to
 // NonGatewayRouteLister.
 type NonGatewayRouteListerExpansion interface{}
@@ -20,6 +20,7 @@ package cni
 
 import (
 	"net"
+	"slices"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
@@ -27,8 +28,11 @@ import (
 )
 
 type Interface struct {
-	Name      string
+	Name string
+	// IPAddress is the IPv4 address of the interface from the cluster CIDRs.
 	IPAddress string
+	// IPv6Address is the IPv6 address of the interface from the cluster CIDRs, if any.
+	IPv6Address string
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("CNI")}
@@ -45,41 +49,76 @@ func Discover(clusterCIDRs []string) (*Interface, error) {
 }
 
 func discover(clusterCIDRs []string) (*Interface, error) {
+	clusterNetworks := make([]*net.IPNet, 0, len(clusterCIDRs))
+
 	for _, clusterCIDR := range clusterCIDRs {
 		_, clusterNetwork, err := net.ParseCIDR(clusterCIDR)
 		if err != nil {
 			return nil, errors.Wrapf(err, "unable to ParseCIDR %q", clusterCIDR)
 		}
 
-		hostInterfaces, err := net.Interfaces()
-		if err != nil {
-			return nil, errors.Wrapf(err, "net.Interfaces() returned error")
-		}
+		clusterNetworks = append(clusterNetworks, clusterNetwork)
+	}
+
+	hostInterfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, errors.Wrapf(err, "net.Interfaces() returned error")
+	}
 
+	for _, clusterNetwork := range clusterNetworks {
 		for _, iface := range hostInterfaces {
 			addrs, err := iface.Addrs()
 			if err != nil {
 				return nil, errors.Wrapf(err, "for interface %q, iface.Addrs returned error", iface.Name)
 			}
 
-			for i := range addrs {
-				ipAddr, _, err := net.ParseCIDR(addrs[i].String())
-				if err != nil {
-					logger.Errorf(err, "Unable to ParseCIDR : %q", addrs[i].String())
-				} else if ipAddr.To4() != nil {
-					logger.V(log.DEBUG).Infof("Interface %q has %q address", iface.Name, ipAddr)
-					address := net.ParseIP(ipAddr.String())
-
-					// Verify that interface has an address from cluster CIDR
-					if clusterNetwork.Contains(address) {
-						logger.V(log.DEBUG).Infof("Found CNI Interface %q that has IP %q from ClusterCIDR %q",
-							iface.Name, ipAddr, clusterCIDR)
-						return &Interface{IPAddress: ipAddr.String(), Name: iface.Name}, nil
+			ipAddrs := parseAddrs(iface.Name, addrs)
+
+			// Verify that interface has an address from cluster CIDR
+			if !slices.ContainsFunc(ipAddrs, clusterNetwork.Contains) {
+				continue
+			}
+
+			logger.V(log.DEBUG).Infof("Found CNI Interface %q that has an IP from ClusterCIDR %q", iface.Name, clusterNetwork)
+
+			// In a dual-stack cluster, the interface has an address from the cluster CIDR of each IP family.
+			cniIface := &Interface{Name: iface.Name}
+
+			for _, ipAddr := range ipAddrs {
+				if !slices.ContainsFunc(clusterNetworks, func(n *net.IPNet) bool { return n.Contains(ipAddr) }) {
+					continue
+				}
+
+				if ipAddr.To4() != nil {
+					if cniIface.IPAddress == "" {
+						cniIface.IPAddress = ipAddr.String()
 					}
+				} else if cniIface.IPv6Address == "" {
+					cniIface.IPv6Address = ipAddr.String()
 				}
 			}
+
+			return cniIface, nil
 		}
 	}
 
 	return nil, errors.Errorf("unable to find CNI Interface on the host which has IP from %q", clusterCIDRs)
 }
+
+func parseAddrs(ifaceName string, addrs []net.Addr) []net.IP {
+	ipAddrs := make([]net.IP, 0, len(addrs))
+
+	for i := range addrs {
+		ipAddr, _, err := net.ParseCIDR(addrs[i].String())
+		if err != nil {
+			logger.Errorf(err, "Unable to ParseCIDR : %q", addrs[i].String())
+			continue
+		}
+
+		logger.V(log.DEBUG).Infof("Interface %q has %q address", ifaceName, ipAddr)
+
+		ipAddrs = append(ipAddrs, ipAddr)
+	}
+
+	return ipAddrs
+}
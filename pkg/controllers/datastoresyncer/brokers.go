@@ -0,0 +1,321 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer
+
+import (
+	"context"
+	"sort"
+
+	"github.com/submariner-io/admiral/pkg/federate"
+	"github.com/submariner-io/admiral/pkg/resource"
+	resourceSyncer "github.com/submariner-io/admiral/pkg/syncer"
+	"github.com/submariner-io/admiral/pkg/syncer/broker"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// BrokerLabelKey is the label of the remote resources synced locally identifying the broker they were synced from.
+	BrokerLabelKey = "submariner.io/broker"
+
+	// DefaultBrokerName is the name of the broker configured by the syncer config passed to New.
+	DefaultBrokerName = "default"
+
+	// ReasonClusterIDCollision is the reason of the Event recorded when different clusters use the same cluster ID on
+	// different brokers.
+	ReasonClusterIDCollision = "ClusterIDCollision"
+)
+
+// BrokerConfig configures an additional broker the local cluster is joined to. The local Cluster and Endpoints are
+// published to every broker and the remote resources of all brokers are synced locally.
+type BrokerConfig struct {
+	Name         string
+	SyncerConfig broker.SyncerConfig
+}
+
+type brokerConnection struct {
+	name   string
+	config broker.SyncerConfig
+	syncer *broker.Syncer
+}
+
+// remoteOrigins tracks, per remote cluster ID, the resources learned from each broker and the broker whose resources are
+// synced locally. A cluster joined to several of the local cluster's brokers is owned by the first broker it's learned
+// from, until that broker no longer has any of its resources, at which point the resources of the next broker that has
+// them, in configuration order, are synced instead.
+type remoteOrigins struct {
+	owners map[string]string
+	// resources maps cluster IDs to broker names to resource keys to the broker resources.
+	resources map[string]map[string]map[string]runtime.Object
+	// collisions are the cluster IDs used by different clusters on different brokers.
+	collisions sets.Set[string]
+}
+
+func newRemoteOrigins() remoteOrigins {
+	return remoteOrigins{
+		owners:     map[string]string{},
+		resources:  map[string]map[string]map[string]runtime.Object{},
+		collisions: sets.New[string](),
+	}
+}
+
+func gvrFor(obj runtime.Object) schema.GroupVersionResource {
+	if _, ok := obj.(*submarinerv1.Cluster); ok {
+		return submarinerv1.ClusterGVR
+	}
+
+	return submarinerv1.EndpointGVR
+}
+
+func resourceKey(gvr schema.GroupVersionResource, name string) string {
+	return gvr.Resource + "/" + name
+}
+
+func withBrokerLabel(obj runtime.Object, brokerName string) runtime.Object {
+	obj = obj.DeepCopyObject()
+	metaObj := resource.MustToMeta(obj)
+
+	labels := metaObj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	labels[BrokerLabelKey] = brokerName
+	metaObj.SetLabels(labels)
+
+	return obj
+}
+
+// brokerFor returns the broker a remote resource was synced from.
+func (d *DatastoreSyncer) brokerFor(obj runtime.Object) *brokerConnection {
+	name := resource.MustToMeta(obj).GetLabels()[BrokerLabelKey]
+
+	for _, b := range d.brokers {
+		if b.name == name {
+			return b
+		}
+	}
+
+	return d.brokers[0]
+}
+
+// fromBroker returns the broker to local transform function for the given broker. It labels the resources with their
+// broker and only lets those of the broker owning their cluster through.
+func (d *DatastoreSyncer) fromBroker(b *brokerConnection, transform resourceSyncer.TransformFunc) resourceSyncer.TransformFunc {
+	return func(from runtime.Object, numRequeues int, op resourceSyncer.Operation) (runtime.Object, bool) {
+		obj := withBrokerLabel(from, b.name)
+
+		proceed, failover := d.arbitrateOrigin(b.name, obj, op)
+
+		for _, toSync := range failover {
+			d.syncFailover(toSync)
+		}
+
+		if !proceed {
+			return nil, false
+		}
+
+		return transform(obj, numRequeues, op)
+	}
+}
+
+// arbitrateOrigin records the given broker resource and returns whether it should be synced locally, and the resources
+// of another broker to sync if the cluster's ownership failed over to it.
+func (d *DatastoreSyncer) arbitrateOrigin(brokerName string, obj runtime.Object, op resourceSyncer.Operation,
+) (bool, []runtime.Object) {
+	metaObj := resource.MustToMeta(obj)
+
+	clusterID := metaObj.GetLabels()[federate.ClusterIDLabelKey]
+	if clusterID == "" {
+		return true, nil
+	}
+
+	key := resourceKey(gvrFor(obj), metaObj.GetName())
+
+	d.originsMutex.Lock()
+	defer d.originsMutex.Unlock()
+
+	byBroker := d.origins.resources[clusterID]
+	if byBroker == nil {
+		byBroker = map[string]map[string]runtime.Object{}
+		d.origins.resources[clusterID] = byBroker
+	}
+
+	if op != resourceSyncer.Delete {
+		if byBroker[brokerName] == nil {
+			byBroker[brokerName] = map[string]runtime.Object{}
+		}
+
+		byBroker[brokerName][key] = obj
+
+		d.detectClusterIDCollision(clusterID)
+
+		if d.origins.owners[clusterID] == "" {
+			d.origins.owners[clusterID] = brokerName
+		}
+
+		return d.origins.owners[clusterID] == brokerName, nil
+	}
+
+	delete(byBroker[brokerName], key)
+
+	if len(byBroker[brokerName]) == 0 {
+		delete(byBroker, brokerName)
+	}
+
+	d.detectClusterIDCollision(clusterID)
+
+	owner := d.origins.owners[clusterID]
+	if owner == "" {
+		// The cluster isn't known from any broker, eg when the syncer reconciles the local resources on startup. The
+		// local resource is deleted unless it was synced from another broker.
+		existing, found, _ := d.syncer.GetLocalResource(metaObj.GetName(), d.syncerConfig.LocalNamespace, obj)
+		if found {
+			origin := resource.MustToMeta(existing).GetLabels()[BrokerLabelKey]
+			return origin == "" || origin == brokerName, nil
+		}
+
+		return true, nil
+	}
+
+	if owner != brokerName || len(byBroker[brokerName]) > 0 {
+		return owner == brokerName, nil
+	}
+
+	delete(d.origins.owners, clusterID)
+
+	for _, b := range d.brokers {
+		resources := byBroker[b.name]
+		if len(resources) == 0 {
+			continue
+		}
+
+		logger.Infof("Remote cluster %q is no longer present on broker %q - syncing its resources from broker %q",
+			clusterID, brokerName, b.name)
+
+		d.origins.owners[clusterID] = b.name
+
+		failover := make([]runtime.Object, 0, len(resources))
+		for _, r := range resources {
+			failover = append(failover, r)
+		}
+
+		// The local resource is updated from the other broker rather than deleted if the other broker also has it.
+		_, stillPresent := resources[key]
+
+		return !stillPresent, failover
+	}
+
+	delete(d.origins.resources, clusterID)
+
+	return true, nil
+}
+
+// detectClusterIDCollision compares the Cluster resources of the given cluster ID learned from the different brokers and
+// reports when their cluster or service CIDRs differ, ie when distinct clusters use the same ID, as only the resources of
+// the owning broker are synced. The global CIDRs aren't compared as they can be appended at runtime. It must be called
+// with the originsMutex held.
+func (d *DatastoreSyncer) detectClusterIDCollision(clusterID string) {
+	var (
+		first       *submarinerv1.Cluster
+		firstBroker string
+		colliding   []string
+	)
+
+	for _, b := range d.brokers {
+		for _, obj := range d.origins.resources[clusterID][b.name] {
+			cluster, ok := obj.(*submarinerv1.Cluster)
+			if !ok {
+				continue
+			}
+
+			if first == nil {
+				first, firstBroker = cluster, b.name
+			} else if !isSameCluster(&first.Spec, &cluster.Spec) {
+				colliding = append(colliding, b.name)
+			}
+		}
+	}
+
+	switch {
+	case len(colliding) > 0 && !d.origins.collisions.Has(clusterID):
+		d.origins.collisions.Insert(clusterID)
+
+		logger.Warningf("Remote cluster ID %q is used by different clusters on brokers %q and %v", clusterID, firstBroker,
+			colliding)
+
+		if d.eventRecorder != nil {
+			d.eventRecorder.Eventf(d.localGatewayRef(), corev1.EventTypeWarning, ReasonClusterIDCollision,
+				"Remote cluster ID %q is used by different clusters on brokers %q and %v - only the resources of one are synced",
+				clusterID, firstBroker, colliding)
+		}
+	case len(colliding) == 0 && d.origins.collisions.Has(clusterID):
+		d.origins.collisions.Delete(clusterID)
+
+		logger.Infof("Remote cluster ID %q is no longer used by different clusters", clusterID)
+	}
+}
+
+func isSameCluster(spec, other *submarinerv1.ClusterSpec) bool {
+	return sets.New(spec.ClusterCIDR...).Equal(sets.New(other.ClusterCIDR...)) &&
+		sets.New(spec.ServiceCIDR...).Equal(sets.New(other.ServiceCIDR...))
+}
+
+func (d *DatastoreSyncer) syncFailover(obj runtime.Object) {
+	transform := d.shouldSyncRemoteEndpoint
+	if _, ok := obj.(*submarinerv1.Cluster); ok {
+		transform = d.shouldSyncRemoteCluster
+	}
+
+	toSync, _ := transform(obj, 0, resourceSyncer.Update)
+	if toSync == nil {
+		return
+	}
+
+	if err := d.syncer.GetLocalFederator().Distribute(context.TODO(), toSync); err != nil {
+		logger.Errorf(err, "Error syncing %s %q from broker %q", gvrFor(obj).Resource, resource.MustToMeta(obj).GetName(),
+			resource.MustToMeta(obj).GetLabels()[BrokerLabelKey])
+	}
+}
+
+// cachedBrokerEndpoints returns the broker Endpoints of the remote clusters, as last seen by the syncers, from the brokers
+// whose resources are synced locally, sorted by name.
+func (d *DatastoreSyncer) cachedBrokerEndpoints() []*submarinerv1.Endpoint {
+	d.originsMutex.Lock()
+	defer d.originsMutex.Unlock()
+
+	endpoints := []*submarinerv1.Endpoint{}
+
+	for clusterID, byBroker := range d.origins.resources {
+		for _, obj := range byBroker[d.origins.owners[clusterID]] {
+			if endpoint, ok := obj.(*submarinerv1.Endpoint); ok {
+				endpoints = append(endpoints, endpoint.DeepCopy())
+			}
+		}
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Name < endpoints[j].Name
+	})
+
+	return endpoints
+}
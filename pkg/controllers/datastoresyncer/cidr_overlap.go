@@ -0,0 +1,147 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/submariner-io/admiral/pkg/resource"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cidr"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const ReasonCIDROverlap = "CIDROverlap"
+
+// CIDROverlap describes a remote cluster whose Endpoint isn't synced because its CIDRs overlap the local CIDRs.
+type CIDROverlap struct {
+	ClusterID   string
+	LocalCIDRs  []string
+	RemoteCIDRs []string
+	// GlobalnetResolvable indicates whether deploying Globalnet would resolve the overlap. When Globalnet is already
+	// deployed, the overlapping CIDRs are global CIDRs and a distinct global CIDR has to be assigned instead.
+	GlobalnetResolvable bool
+}
+
+func (o *CIDROverlap) equals(other *CIDROverlap) bool {
+	return o.GlobalnetResolvable == other.GlobalnetResolvable && slices.Equal(o.LocalCIDRs, other.LocalCIDRs) &&
+		slices.Equal(o.RemoteCIDRs, other.RemoteCIDRs)
+}
+
+// GetCIDROverlaps returns the remote clusters currently excluded because of overlapping CIDRs, sorted by cluster ID.
+func (d *DatastoreSyncer) GetCIDROverlaps() []CIDROverlap {
+	d.overlapsMutex.Lock()
+	defer d.overlapsMutex.Unlock()
+
+	overlaps := make([]CIDROverlap, 0, len(d.cidrOverlaps))
+	for _, overlap := range d.cidrOverlaps {
+		overlaps = append(overlaps, *overlap)
+	}
+
+	sort.Slice(overlaps, func(i, j int) bool {
+		return overlaps[i].ClusterID < overlaps[j].ClusterID
+	})
+
+	return overlaps
+}
+
+func (d *DatastoreSyncer) findCIDROverlap(remoteEndpoint *submarinerv1.Endpoint) (*CIDROverlap, error) {
+	var overlap *CIDROverlap
+
+	for _, localSubnet := range d.localEndpoint.Spec().Subnets {
+		for _, remoteSubnet := range remoteEndpoint.Spec.Subnets {
+			overlapping, err := cidr.IsOverlapping([]string{remoteSubnet}, localSubnet)
+			if err != nil {
+				return nil, err //nolint:wrapcheck // No need to wrap
+			}
+
+			if !overlapping {
+				continue
+			}
+
+			if overlap == nil {
+				overlap = &CIDROverlap{
+					ClusterID:           remoteEndpoint.Spec.ClusterID,
+					GlobalnetResolvable: len(d.localClusterSpec().GlobalCIDR) == 0,
+				}
+			}
+
+			if !slices.Contains(overlap.LocalCIDRs, localSubnet) {
+				overlap.LocalCIDRs = append(overlap.LocalCIDRs, localSubnet)
+			}
+
+			if !slices.Contains(overlap.RemoteCIDRs, remoteSubnet) {
+				overlap.RemoteCIDRs = append(overlap.RemoteCIDRs, remoteSubnet)
+			}
+		}
+	}
+
+	return overlap, nil
+}
+
+func (d *DatastoreSyncer) setCIDROverlap(overlap *CIDROverlap) {
+	d.overlapsMutex.Lock()
+	defer d.overlapsMutex.Unlock()
+
+	existing := d.cidrOverlaps[overlap.ClusterID]
+	if existing != nil && existing.equals(overlap) {
+		return
+	}
+
+	if existing != nil {
+		recordCIDROverlapResolved(existing)
+	}
+
+	d.cidrOverlaps[overlap.ClusterID] = overlap
+	recordCIDROverlap(overlap)
+
+	logger.Errorf(nil, "Not syncing the Endpoint of remote cluster %q as its CIDRs %v overlap the local CIDRs %v "+
+		"(resolvable by Globalnet: %v)", overlap.ClusterID, overlap.RemoteCIDRs, overlap.LocalCIDRs, overlap.GlobalnetResolvable)
+
+	if d.eventRecorder != nil {
+		d.eventRecorder.Eventf(d.localGatewayRef(), corev1.EventTypeWarning, ReasonCIDROverlap,
+			"The CIDRs %v of remote cluster %q overlap the local CIDRs %v (resolvable by Globalnet: %v)",
+			overlap.RemoteCIDRs, overlap.ClusterID, overlap.LocalCIDRs, overlap.GlobalnetResolvable)
+	}
+}
+
+func (d *DatastoreSyncer) clearCIDROverlap(clusterID string) {
+	d.overlapsMutex.Lock()
+	defer d.overlapsMutex.Unlock()
+
+	existing := d.cidrOverlaps[clusterID]
+	if existing == nil {
+		return
+	}
+
+	delete(d.cidrOverlaps, clusterID)
+	recordCIDROverlapResolved(existing)
+
+	logger.Infof("The CIDR overlap with remote cluster %q is resolved", clusterID)
+}
+
+func (d *DatastoreSyncer) localGatewayRef() *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: submarinerv1.SchemeGroupVersion.String(),
+		Kind:       "Gateway",
+		Namespace:  d.syncerConfig.LocalNamespace,
+		Name:       resource.EnsureValidName(d.localEndpoint.Spec().Hostname),
+	}
+}
@@ -0,0 +1,180 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/fake"
+	"github.com/submariner-io/admiral/pkg/syncer/broker"
+	"github.com/submariner-io/admiral/pkg/syncer/test"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/controllers/datastoresyncer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const otherBrokerName = "other"
+
+var _ = Describe("Multiple brokers", testMultipleBrokers)
+
+func testMultipleBrokers() {
+	t := newTestDriver()
+
+	var (
+		otherBrokerClient    *dynamicfake.FakeDynamicClient
+		otherBrokerClusters  dynamic.ResourceInterface
+		otherBrokerEndpoints dynamic.ResourceInterface
+		remoteEndpoint       *submarinerv1.Endpoint
+	)
+
+	BeforeEach(func() {
+		otherBrokerClient = dynamicfake.NewSimpleDynamicClient(t.syncerScheme)
+		fake.AddBasicReactors(&otherBrokerClient.Fake)
+
+		otherBrokerClusters = otherBrokerClient.Resource(submarinerv1.ClusterGVR).Namespace(brokerNamespace)
+		otherBrokerEndpoints = otherBrokerClient.Resource(submarinerv1.EndpointGVR).Namespace(brokerNamespace)
+
+		t.additionalBrokers = []datastoresyncer.BrokerConfig{{
+			Name: otherBrokerName,
+			SyncerConfig: broker.SyncerConfig{
+				BrokerClient:    otherBrokerClient,
+				BrokerNamespace: brokerNamespace,
+			},
+		}}
+
+		remoteEndpoint = test.SetClusterIDLabel(newEndpoint(&submarinerv1.EndpointSpec{
+			CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+			ClusterID: otherClusterID,
+			Hostname:  "bruins",
+			PrivateIP: "10.253.1.2",
+			Subnets:   []string{"20.0.0.0/14"},
+		}), otherClusterID)
+	})
+
+	It("should publish the local Cluster and Endpoint to every broker", func() {
+		awaitCluster(t.brokerClusters, &t.localCluster.Spec)
+		awaitEndpoint(t.brokerEndpoints, t.localEndpoint)
+		awaitCluster(otherBrokerClusters, &t.localCluster.Spec)
+		awaitEndpoint(otherBrokerEndpoints, t.localEndpoint)
+	})
+
+	When("remote clusters are joined to different brokers", func() {
+		It("should sync the remote Endpoints of both brokers labelled by origin", func() {
+			thirdEndpoint := test.SetClusterIDLabel(newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: "submariner-cable-north-10-254-1-2",
+				ClusterID: "north",
+				Hostname:  "rangers",
+				PrivateIP: "10.254.1.2",
+				Subnets:   []string{"30.0.0.0/14"},
+			}), "north")
+
+			test.CreateResource(t.brokerEndpoints, remoteEndpoint)
+			test.CreateResource(otherBrokerEndpoints, thirdEndpoint)
+
+			awaitEndpointFromBroker(t.localEndpoints, &remoteEndpoint.Spec, datastoresyncer.DefaultBrokerName)
+			awaitEndpointFromBroker(t.localEndpoints, &thirdEndpoint.Spec, otherBrokerName)
+
+			Expect(otherBrokerEndpoints.Delete(context.TODO(), thirdEndpoint.Name, metav1.DeleteOptions{})).To(Succeed())
+			test.AwaitNoResource(t.localEndpoints, thirdEndpoint.Name)
+			test.AwaitResource(t.localEndpoints, remoteEndpoint.Name)
+		})
+	})
+
+	When("a remote cluster is joined to both brokers", func() {
+		It("should sync its Endpoint from one broker and fail over to the other", func() {
+			test.CreateResource(t.brokerEndpoints, remoteEndpoint)
+			awaitEndpointFromBroker(t.localEndpoints, &remoteEndpoint.Spec, datastoresyncer.DefaultBrokerName)
+
+			otherEndpoint := remoteEndpoint.DeepCopy()
+			otherEndpoint.Spec.Hostname = "celtics"
+			test.CreateResource(otherBrokerEndpoints, otherEndpoint)
+
+			time.Sleep(300 * time.Millisecond)
+			awaitEndpointFromBroker(t.localEndpoints, &remoteEndpoint.Spec, datastoresyncer.DefaultBrokerName)
+
+			Expect(t.brokerEndpoints.Delete(context.TODO(), remoteEndpoint.Name, metav1.DeleteOptions{})).To(Succeed())
+			awaitEndpointFromBroker(t.localEndpoints, &otherEndpoint.Spec, otherBrokerName)
+
+			Expect(otherBrokerEndpoints.Delete(context.TODO(), otherEndpoint.Name, metav1.DeleteOptions{})).To(Succeed())
+			test.AwaitNoResource(t.localEndpoints, remoteEndpoint.Name)
+		})
+	})
+
+	When("different remote clusters use the same cluster ID on different brokers", func() {
+		It("should record an Event", func() {
+			cluster := test.SetClusterIDLabel(newCluster(&submarinerv1.ClusterSpec{
+				ClusterID:   otherClusterID,
+				ClusterCIDR: []string{"20.0.0.0/16"},
+				ServiceCIDR: []string{"21.0.0.0/16"},
+			}), otherClusterID)
+
+			test.CreateResource(t.brokerClusters, cluster)
+
+			otherCluster := cluster.DeepCopy()
+			otherCluster.Spec.ClusterCIDR = []string{"30.0.0.0/16"}
+			test.CreateResource(otherBrokerClusters, otherCluster)
+
+			Eventually(t.eventRecorder.Events).Should(Receive(ContainSubstring(datastoresyncer.ReasonClusterIDCollision)))
+		})
+	})
+
+	When("an additional broker is unavailable", func() {
+		BeforeEach(func() {
+			t.transitRouting = true
+			fake.FailOnAction(&otherBrokerClient.Fake, "*", "list", errors.New("fake list error"), false)
+		})
+
+		It("should still evaluate the transit routes of the remote Endpoints of the other brokers", func() {
+			remoteEndpoint.Spec.TransitRoutes = []submarinerv1.TransitRoute{
+				{ClusterID: "north", Subnets: []string{"30.0.0.0/16"}, Path: []string{otherClusterID}},
+			}
+
+			test.CreateResource(t.brokerEndpoints, remoteEndpoint)
+
+			expected := t.localEndpoint.DeepCopy()
+			expected.TransitRoutes = []submarinerv1.TransitRoute{
+				{ClusterID: "north", Subnets: []string{"30.0.0.0/16"}, Path: []string{clusterID, otherClusterID}},
+				{ClusterID: otherClusterID, Subnets: []string{"20.0.0.0/14"}, Path: []string{clusterID}},
+			}
+
+			awaitEndpoint(t.brokerEndpoints, expected)
+		})
+	})
+}
+
+func awaitEndpointFromBroker(endpoints dynamic.ResourceInterface, expected *submarinerv1.EndpointSpec, brokerName string) {
+	test.AwaitAndVerifyResource(endpoints, getEndpointName(expected), func(obj *unstructured.Unstructured) bool {
+		defer GinkgoRecover()
+
+		actual := &submarinerv1.Endpoint{}
+		Expect(scheme.Scheme.Convert(obj, actual, nil)).To(Succeed())
+
+		return reflect.DeepEqual(actual.Spec, *expected) && actual.Labels[datastoresyncer.BrokerLabelKey] == brokerName
+	})
+}
@@ -59,6 +59,25 @@ func testClusterSyncing() {
 			})
 		})
 
+		When("a local Cluster with appended global CIDRs already exists", func() {
+			var existing *submarinerv1.ClusterSpec
+
+			BeforeEach(func() {
+				existing = t.localCluster.Spec.DeepCopy()
+				existing.GlobalCIDR = append(existing.GlobalCIDR, "201.0.0.0/16")
+				test.CreateResource(t.localClusters, newCluster(existing))
+			})
+
+			It("should preserve the appended global CIDRs", func() {
+				awaitCluster(t.localClusters, existing)
+				awaitCluster(t.brokerClusters, existing)
+
+				expected := t.localEndpoint.DeepCopy()
+				expected.Subnets = append(expected.Subnets, "201.0.0.0/16")
+				awaitEndpoint(t.localEndpoints, expected)
+			})
+		})
+
 		When("creation of the local Cluster fails", func() {
 			BeforeEach(func() {
 				t.expectedStartErr = errors.New("mock Create error")
@@ -70,6 +89,22 @@ func testClusterSyncing() {
 		})
 	})
 
+	When("a global CIDR is appended to the local Cluster", func() {
+		It("should add it to the local Endpoint subnets", func() {
+			awaitEndpoint(t.localEndpoints, t.localEndpoint)
+
+			updated := t.localCluster.Spec.DeepCopy()
+			updated.GlobalCIDR = append(updated.GlobalCIDR, "201.0.0.0/16")
+			test.UpdateResource(t.localClusters, newCluster(updated))
+			awaitCluster(t.brokerClusters, updated)
+
+			expected := t.localEndpoint.DeepCopy()
+			expected.Subnets = append(expected.Subnets, "201.0.0.0/16")
+			awaitEndpoint(t.localEndpoints, expected)
+			awaitEndpoint(t.brokerEndpoints, expected)
+		})
+	})
+
 	When("a local Cluster is deleted", func() {
 		It("should delete it from the broker", func() {
 			awaitCluster(t.brokerClusters, &t.localCluster.Spec)
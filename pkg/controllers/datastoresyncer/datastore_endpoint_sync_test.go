@@ -31,7 +31,9 @@ import (
 	"github.com/submariner-io/admiral/pkg/syncer/test"
 	testutil "github.com/submariner-io/admiral/pkg/test"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/controllers/datastoresyncer"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+	"github.com/submariner-io/submariner/pkg/peering"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -106,6 +108,206 @@ func testEndpointSyncing() {
 		})
 	})
 
+	When("a remote Endpoint's subnets overlap the local subnets", func() {
+		It("should not sync it and report the overlap until it's resolved", func() {
+			awaitEndpoint(t.brokerEndpoints, t.localEndpoint)
+
+			endpoint := newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+				ClusterID: otherClusterID,
+				Hostname:  "bruins",
+				PrivateIP: "10-253-1-2",
+				Subnets:   []string{"200.0.0.0/16", "10.1.0.0/16"},
+			})
+
+			test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(endpoint, endpoint.Spec.ClusterID))
+
+			Eventually(t.syncer.GetCIDROverlaps).Should(Equal([]datastoresyncer.CIDROverlap{{
+				ClusterID:   otherClusterID,
+				LocalCIDRs:  []string{"10.0.0.0/14"},
+				RemoteCIDRs: []string{"10.1.0.0/16"},
+			}}))
+
+			Eventually(t.eventRecorder.Events).Should(Receive(ContainSubstring(datastoresyncer.ReasonCIDROverlap)))
+			Consistently(func() bool {
+				_, err := t.localEndpoints.Get(context.TODO(), endpoint.GetName(), metav1.GetOptions{})
+				return apierrors.IsNotFound(err)
+			}, 300*time.Millisecond).Should(BeTrue())
+
+			endpoint.Spec.Subnets = []string{"200.0.0.0/16", "20.0.0.0/14"}
+			test.UpdateResource(t.brokerEndpoints, endpoint)
+			awaitEndpoint(t.localEndpoints, &endpoint.Spec)
+			Expect(t.syncer.GetCIDROverlaps()).To(BeEmpty())
+		})
+	})
+
+	When("the peering policy denies a remote cluster", func() {
+		BeforeEach(func() {
+			var err error
+
+			t.peeringPolicy, err = peering.NewPolicy(nil, []string{otherClusterID}, "")
+			Expect(err).To(Succeed())
+		})
+
+		It("should not sync its Endpoint and report it as filtered", func() {
+			awaitEndpoint(t.brokerEndpoints, t.localEndpoint)
+
+			endpoint := newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+				ClusterID: otherClusterID,
+				Hostname:  "bruins",
+				PrivateIP: "10-253-1-2",
+				Subnets:   []string{"200.0.0.0/16", "20.0.0.0/14"},
+			})
+
+			test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(endpoint, endpoint.Spec.ClusterID))
+
+			Eventually(t.syncer.GetFilteredPeers).Should(Equal([]datastoresyncer.FilteredPeer{{
+				ClusterID: otherClusterID,
+				Reason:    "the cluster is in the denied list",
+			}}))
+
+			Consistently(func() bool {
+				_, err := t.localEndpoints.Get(context.TODO(), endpoint.GetName(), metav1.GetOptions{})
+				return apierrors.IsNotFound(err)
+			}, 300*time.Millisecond).Should(BeTrue())
+
+			Expect(t.brokerEndpoints.Delete(context.TODO(), endpoint.GetName(), metav1.DeleteOptions{})).To(Succeed())
+			Eventually(t.syncer.GetFilteredPeers).Should(BeEmpty())
+		})
+	})
+
+	When("the peering policy has a Cluster label selector", func() {
+		BeforeEach(func() {
+			var err error
+
+			t.peeringPolicy, err = peering.NewPolicy(nil, nil, "region=us")
+			Expect(err).To(Succeed())
+		})
+
+		It("should only sync the Endpoints of remote clusters whose Cluster matches", func() {
+			awaitEndpoint(t.brokerEndpoints, t.localEndpoint)
+
+			endpoint := newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+				ClusterID: otherClusterID,
+				Hostname:  "bruins",
+				PrivateIP: "10-253-1-2",
+				Subnets:   []string{"200.0.0.0/16", "20.0.0.0/14"},
+			})
+
+			test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(endpoint, endpoint.Spec.ClusterID))
+			Eventually(t.syncer.GetFilteredPeers).Should(HaveLen(1))
+
+			By("Creating the remote Cluster with matching labels")
+
+			cluster := newCluster(&submarinerv1.ClusterSpec{
+				ClusterID:   otherClusterID,
+				ServiceCIDR: []string{"200.0.0.0/16"},
+			})
+
+			test.SetClusterIDLabel(cluster, cluster.Spec.ClusterID)
+			cluster.Labels["region"] = "us"
+			test.CreateResource(t.brokerClusters, cluster)
+
+			awaitEndpoint(t.localEndpoints, &endpoint.Spec)
+			Expect(t.syncer.GetFilteredPeers()).To(BeEmpty())
+
+			By("Updating the remote Cluster's labels so they no longer match")
+
+			cluster.Labels["region"] = "eu"
+			test.UpdateResource(t.brokerClusters, cluster)
+
+			test.AwaitNoResource(t.localEndpoints, endpoint.GetName())
+			Expect(t.syncer.GetFilteredPeers()).To(HaveLen(1))
+		})
+	})
+
+	When("a remote Endpoint advertises transit routes", func() {
+		It("should route the subnets of the clusters that aren't directly connected through it", func() {
+			awaitEndpoint(t.brokerEndpoints, t.localEndpoint)
+
+			west := newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+				ClusterID: otherClusterID,
+				Hostname:  "bruins",
+				PrivateIP: "10-253-1-2",
+				Subnets:   []string{"20.0.0.0/14"},
+			})
+
+			test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(west, west.Spec.ClusterID))
+			awaitEndpoint(t.localEndpoints, &west.Spec)
+
+			hub := newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: "submariner-cable-hub-10-253-1-3",
+				ClusterID: "hub",
+				Hostname:  "patriots",
+				PrivateIP: "10-253-1-3",
+				Subnets:   []string{"50.0.0.0/16"},
+				TransitRoutes: []submarinerv1.TransitRoute{
+					{ClusterID: clusterID, Subnets: []string{"10.0.0.0/14"}, Path: []string{"hub"}},
+					{ClusterID: otherClusterID, Subnets: []string{"20.0.0.0/14"}, Path: []string{"hub"}},
+					{ClusterID: "north", Subnets: []string{"30.0.0.0/16"}, Path: []string{"hub"}},
+					{ClusterID: "south", Subnets: []string{"40.0.0.0/16"}, Path: []string{"hub", clusterID}},
+				},
+			})
+
+			test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(hub, hub.Spec.ClusterID))
+
+			expected := hub.Spec.DeepCopy()
+			expected.Subnets = []string{"50.0.0.0/16", "30.0.0.0/16"}
+			awaitEndpoint(t.localEndpoints, expected)
+
+			By("Creating an Endpoint for the transit cluster")
+
+			north := newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: "submariner-cable-north-10-253-1-4",
+				ClusterID: "north",
+				Hostname:  "yankees",
+				PrivateIP: "10-253-1-4",
+				Subnets:   []string{"30.0.0.0/16"},
+			})
+
+			test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(north, north.Spec.ClusterID))
+			awaitEndpoint(t.localEndpoints, &hub.Spec)
+		})
+	})
+
+	When("transit routing is enabled", func() {
+		BeforeEach(func() {
+			t.transitRouting = true
+		})
+
+		It("should advertise transit routes to the directly connected clusters", func() {
+			awaitEndpoint(t.brokerEndpoints, t.localEndpoint)
+
+			west := newEndpoint(&submarinerv1.EndpointSpec{
+				CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+				ClusterID: otherClusterID,
+				Hostname:  "bruins",
+				PrivateIP: "10-253-1-2",
+				Subnets:   []string{"20.0.0.0/14"},
+				TransitRoutes: []submarinerv1.TransitRoute{
+					{ClusterID: "north", Subnets: []string{"30.0.0.0/16"}, Path: []string{otherClusterID}},
+					{ClusterID: "south", Subnets: []string{"40.0.0.0/16"}, Path: []string{otherClusterID, clusterID}},
+				},
+			})
+
+			test.CreateResource(t.brokerEndpoints, test.SetClusterIDLabel(west, west.Spec.ClusterID))
+
+			expected := t.localEndpoint.DeepCopy()
+			expected.TransitRoutes = []submarinerv1.TransitRoute{
+				{ClusterID: "north", Subnets: []string{"30.0.0.0/16"}, Path: []string{clusterID, otherClusterID}},
+				{ClusterID: otherClusterID, Subnets: []string{"20.0.0.0/14"}, Path: []string{clusterID}},
+			}
+
+			awaitEndpoint(t.brokerEndpoints, expected)
+
+			Expect(t.brokerEndpoints.Delete(context.TODO(), west.GetName(), metav1.DeleteOptions{})).To(Succeed())
+			awaitEndpoint(t.brokerEndpoints, t.localEndpoint)
+		})
+	})
+
 	When("a remote Endpoint is synced locally", func() {
 		It("should not try to re-sync to the broker", func() {
 			awaitEndpoint(t.brokerEndpoints, t.localEndpoint)
@@ -161,6 +363,28 @@ func testEndpointSyncing() {
 			})
 		})
 	})
+
+	When("the local Gateway's global IP is in an appended global CIDR", func() {
+		BeforeEach(func() {
+			cluster := t.localCluster.Spec.DeepCopy()
+			cluster.GlobalCIDR = append(cluster.GlobalCIDR, "201.0.0.0/16")
+			test.CreateResource(t.localClusters, newCluster(cluster))
+
+			test.CreateResource(t.localGateways, &submarinerv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        t.localEndpoint.Hostname,
+					Annotations: map[string]string{constants.SmGlobalIP: "201.0.0.40"},
+				},
+			})
+		})
+
+		It("should update the local Endpoint's HealthCheckIP", func() {
+			expected := t.localEndpoint.DeepCopy()
+			expected.HealthCheckIP = "201.0.0.40"
+			expected.Subnets = append(expected.Subnets, "201.0.0.0/16")
+			awaitEndpoint(t.localEndpoints, expected)
+		})
+	})
 }
 
 func testEndpointExclusivity() {
@@ -206,6 +430,43 @@ func testEndpointExclusivity() {
 		})
 	})
 
+	When("active-active gateways are enabled", func() {
+		var otherGatewayEndpoint, staleEndpoint *submarinerv1.Endpoint
+
+		BeforeEach(func() {
+			t.localEndpoint.BackendConfig = map[string]string{
+				submarinerv1.GatewayHAModeConfig: submarinerv1.HAModeActiveActive,
+			}
+
+			DeferCleanup(func() {
+				t.localEndpoint.BackendConfig = nil
+			})
+
+			otherGatewayEndpoint = newEndpoint(&submarinerv1.EndpointSpec{
+				CableName:     "submariner-cable-east-1-2-3-4",
+				ClusterID:     clusterID,
+				Hostname:      "yankees",
+				BackendConfig: t.localEndpoint.BackendConfig,
+			})
+
+			staleEndpoint = newEndpoint(&submarinerv1.EndpointSpec{
+				CableName:     "submariner-cable-east-1-2-3-5",
+				ClusterID:     clusterID,
+				Hostname:      t.localEndpoint.Hostname,
+				BackendConfig: t.localEndpoint.BackendConfig,
+			})
+
+			test.CreateResource(t.localEndpoints, otherGatewayEndpoint)
+			test.CreateResource(t.localEndpoints, staleEndpoint)
+		})
+
+		It("should only delete the stale Endpoint of the local gateway", func() {
+			test.AwaitNoResource(t.localEndpoints, staleEndpoint.GetName())
+			awaitEndpoint(t.localEndpoints, t.localEndpoint)
+			test.AwaitResource(t.localEndpoints, otherGatewayEndpoint.GetName())
+		})
+	})
+
 	When("an Endpoint initially exists that matches the local Endpoint", func() {
 		BeforeEach(func() {
 			test.CreateResource(t.localEndpoints, newEndpoint(t.localEndpoint))
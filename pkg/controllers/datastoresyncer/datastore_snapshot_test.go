@@ -0,0 +1,203 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/fake"
+	"github.com/submariner-io/admiral/pkg/federate"
+	"github.com/submariner-io/admiral/pkg/syncer/test"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/controllers/datastoresyncer"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ = Describe("Datastore snapshot", testDatastoreSnapshot)
+
+func testDatastoreSnapshot() {
+	t := newTestDriver()
+
+	var (
+		remoteEndpoint *submarinerv1.Endpoint
+		remoteCluster  *submarinerv1.Cluster
+	)
+
+	BeforeEach(func() {
+		remoteEndpoint = test.SetClusterIDLabel(newEndpoint(&submarinerv1.EndpointSpec{
+			CableName: fmt.Sprintf("submariner-cable-%s-10-253-1-2", otherClusterID),
+			ClusterID: otherClusterID,
+			Hostname:  "bruins",
+			PrivateIP: "10.253.1.2",
+			Subnets:   []string{"20.0.0.0/14"},
+		}), otherClusterID)
+
+		remoteCluster = test.SetClusterIDLabel(newCluster(&submarinerv1.ClusterSpec{
+			ClusterID:   otherClusterID,
+			ServiceCIDR: []string{"21.0.0.0/16"},
+			ClusterCIDR: []string{"20.0.0.0/14"},
+			GlobalCIDR:  []string{},
+		}), otherClusterID)
+	})
+
+	When("remote Endpoints and Clusters are synced from the broker", func() {
+		It("should record them in the snapshot", func() {
+			test.CreateResource(t.brokerClusters, remoteCluster)
+			test.CreateResource(t.brokerEndpoints, remoteEndpoint)
+
+			Eventually(func() []string {
+				endpoints, clusters := t.getSnapshot()
+				names := []string{}
+
+				for i := range endpoints {
+					names = append(names, endpoints[i].Name)
+				}
+
+				for i := range clusters {
+					names = append(names, clusters[i].Name)
+				}
+
+				return names
+			}, 5*time.Second).Should(ConsistOf(remoteEndpoint.Name, remoteCluster.Name))
+
+			endpoints, _ := t.getSnapshot()
+			Expect(endpoints[0].Spec).To(Equal(remoteEndpoint.Spec))
+			Expect(endpoints[0].Labels).To(HaveKeyWithValue(federate.ClusterIDLabelKey, otherClusterID))
+
+			Expect(t.brokerEndpoints.Delete(context.TODO(), remoteEndpoint.Name, metav1.DeleteOptions{})).To(Succeed())
+			test.AwaitNoResource(t.localEndpoints, remoteEndpoint.Name)
+
+			Eventually(func() int {
+				endpoints, _ := t.getSnapshot()
+				return len(endpoints)
+			}, 5*time.Second).Should(BeZero())
+		})
+	})
+
+	When("the broker is unreachable on startup", func() {
+		var brokerFailure *fake.FailOnActionReactor
+
+		BeforeEach(func() {
+			brokerFailure = fake.FailOnAction(&t.brokerClient.Fake, "endpoints", "list", errors.New("broker unreachable"), false)
+
+			staleEndpoint := remoteEndpoint.DeepCopy()
+			staleEndpoint.Spec.Hostname = "celtics"
+
+			t.createSnapshot([]*submarinerv1.Endpoint{staleEndpoint}, []*submarinerv1.Cluster{remoteCluster})
+		})
+
+		It("should restore the remote resources from the snapshot and reconcile once the broker is reachable", func() {
+			test.AwaitResource(t.localClusters, remoteCluster.Name)
+
+			restored := remoteEndpoint.Spec
+			restored.Hostname = "celtics"
+			awaitEndpoint(t.localEndpoints, &restored)
+
+			time.Sleep(500 * time.Millisecond)
+			awaitEndpoint(t.localEndpoints, &restored)
+
+			test.CreateResource(t.brokerEndpoints, remoteEndpoint)
+			brokerFailure.Fail(false)
+
+			awaitEndpoint(t.localEndpoints, &remoteEndpoint.Spec)
+			test.AwaitNoResource(t.localClusters, remoteCluster.Name)
+		})
+	})
+
+	When("the broker loses the local and remote resources", func() {
+		BeforeEach(func() {
+			// Ensure the broker resources are deleted before the local resources are republished.
+			datastoresyncer.BrokerReconcileInterval = time.Second
+			datastoresyncer.RetainedResourceTimeout = 500 * time.Millisecond
+
+			DeferCleanup(func() {
+				datastoresyncer.BrokerReconcileInterval = 300 * time.Millisecond
+				datastoresyncer.RetainedResourceTimeout = 300 * time.Millisecond
+			})
+		})
+
+		It("should retain the remote resources until the broker is available and republish the local resources", func() {
+			test.CreateResource(t.brokerEndpoints, remoteEndpoint)
+			awaitEndpoint(t.localEndpoints, &remoteEndpoint.Spec)
+			awaitCluster(t.brokerClusters, &t.localCluster.Spec)
+
+			Expect(t.brokerClusters.Delete(context.TODO(), clusterID, metav1.DeleteOptions{})).To(Succeed())
+			Expect(t.brokerEndpoints.Delete(context.TODO(), getEndpointName(t.localEndpoint), metav1.DeleteOptions{})).
+				To(Succeed())
+			Expect(t.brokerEndpoints.Delete(context.TODO(), remoteEndpoint.Name, metav1.DeleteOptions{})).To(Succeed())
+
+			awaitCluster(t.brokerClusters, &t.localCluster.Spec)
+			awaitEndpoint(t.brokerEndpoints, t.localEndpoint)
+			test.AwaitResource(t.localEndpoints, remoteEndpoint.Name)
+
+			test.AwaitNoResource(t.localEndpoints, remoteEndpoint.Name)
+		})
+	})
+}
+
+func (t *testDriver) getSnapshot() ([]submarinerv1.Endpoint, []submarinerv1.Cluster) {
+	obj, err := t.localConfigMaps().Get(context.TODO(), datastoresyncer.SnapshotConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	Expect(scheme.Scheme.Convert(obj, configMap, nil)).To(Succeed())
+
+	var (
+		endpoints []submarinerv1.Endpoint
+		clusters  []submarinerv1.Cluster
+	)
+
+	Expect(json.Unmarshal([]byte(configMap.Data["endpoints"]), &endpoints)).To(Succeed())
+	Expect(json.Unmarshal([]byte(configMap.Data["clusters"]), &clusters)).To(Succeed())
+
+	return endpoints, clusters
+}
+
+func (t *testDriver) createSnapshot(endpoints []*submarinerv1.Endpoint, clusters []*submarinerv1.Cluster) {
+	endpointsData, err := json.Marshal(endpoints)
+	Expect(err).To(Succeed())
+
+	clustersData, err := json.Marshal(clusters)
+	Expect(err).To(Succeed())
+
+	test.CreateResource(t.localConfigMaps(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: datastoresyncer.SnapshotConfigMapName,
+		},
+		Data: map[string]string{
+			"endpoints": string(endpointsData),
+			"clusters":  string(clustersData),
+		},
+	})
+}
+
+func (t *testDriver) localConfigMaps() dynamic.ResourceInterface {
+	return t.localClient.Resource(corev1.SchemeGroupVersion.WithResource("configmaps")).Namespace(localNamespace)
+}
@@ -20,6 +20,7 @@ package datastoresyncer
 
 import (
 	"context"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/federate"
@@ -29,8 +30,8 @@ import (
 	"github.com/submariner-io/admiral/pkg/syncer/broker"
 	"github.com/submariner-io/admiral/pkg/watcher"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
-	"github.com/submariner-io/submariner/pkg/cidr"
 	"github.com/submariner-io/submariner/pkg/endpoint"
+	"github.com/submariner-io/submariner/pkg/peering"
 	"github.com/submariner-io/submariner/pkg/types"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -38,29 +39,72 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 type DatastoreSyncer struct {
-	localCluster  types.SubmarinerCluster
-	localEndpoint *endpoint.Local
-	syncerConfig  broker.SyncerConfig
+	localClusterMutex     sync.Mutex
+	localCluster          types.SubmarinerCluster
+	localEndpoint         *endpoint.Local
+	syncerConfig          broker.SyncerConfig
+	eventRecorder         record.EventRecorder
+	overlapsMutex         sync.Mutex
+	cidrOverlaps          map[string]*CIDROverlap
+	peeringPolicy         *peering.Policy
+	peersMutex            sync.Mutex
+	filteredPeers         map[string]string
+	clusterLabels         map[string]map[string]string
+	syncer                *broker.Syncer
+	transitRoutingEnabled bool
+	snapshotMutex         sync.Mutex
+	snapshot              datastoreSnapshot
+	retained              map[string]*retainedResource
+	brokers               []*brokerConnection
+	originsMutex          sync.Mutex
+	origins               remoteOrigins
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("DSSyncer")}
 
 func New(syncerConfig *broker.SyncerConfig, localCluster *types.SubmarinerCluster,
-	localEndpoint *endpoint.Local,
+	localEndpoint *endpoint.Local, eventRecorder record.EventRecorder, peeringPolicy *peering.Policy, transitRoutingEnabled bool,
+	additionalBrokers []BrokerConfig,
 ) *DatastoreSyncer {
 	// We'll panic if syncerConfig, localCluster or localEndpoint are nil, this is intentional
 	syncerConfig.LocalClusterID = localCluster.Spec.ClusterID
 
+	brokers := []*brokerConnection{{name: DefaultBrokerName, config: *syncerConfig}}
+
+	for i := range additionalBrokers {
+		config := additionalBrokers[i].SyncerConfig
+		config.LocalClusterID = syncerConfig.LocalClusterID
+		config.LocalNamespace = syncerConfig.LocalNamespace
+		config.LocalRestConfig = syncerConfig.LocalRestConfig
+		config.LocalClient = syncerConfig.LocalClient
+		config.RestMapper = syncerConfig.RestMapper
+		config.Scheme = syncerConfig.Scheme
+
+		brokers = append(brokers, &brokerConnection{name: additionalBrokers[i].Name, config: config})
+	}
+
 	return &DatastoreSyncer{
-		localCluster:  *localCluster,
-		localEndpoint: localEndpoint,
-		syncerConfig:  *syncerConfig,
+		localCluster:          *localCluster,
+		localEndpoint:         localEndpoint,
+		syncerConfig:          *syncerConfig,
+		eventRecorder:         eventRecorder,
+		cidrOverlaps:          map[string]*CIDROverlap{},
+		peeringPolicy:         peeringPolicy,
+		filteredPeers:         map[string]string{},
+		clusterLabels:         map[string]map[string]string{},
+		transitRoutingEnabled: transitRoutingEnabled,
+		snapshot:              newDatastoreSnapshot(),
+		retained:              map[string]*retainedResource{},
+		brokers:               brokers,
+		origins:               newRemoteOrigins(),
 	}
 }
 
@@ -69,9 +113,24 @@ func (d *DatastoreSyncer) Start(ctx context.Context) error {
 
 	logger.Info("Starting the datastore syncer")
 
-	syncer, err := d.createSyncer()
-	if err != nil {
-		return err
+	var err error
+
+	for _, b := range d.brokers {
+		b.syncer, err = d.createSyncer(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	syncer := d.brokers[0].syncer
+	d.syncer = syncer
+
+	// Restore the remote resources from the snapshot before starting the syncer, as starting it blocks until the broker
+	// is reachable.
+	if err := d.loadSnapshot(ctx); err != nil {
+		logger.Errorf(err, "Error loading the datastore snapshot")
+	} else if err := d.restoreSnapshot(ctx); err != nil {
+		logger.Errorf(err, "Error restoring the datastore snapshot")
 	}
 
 	err = syncer.Start(ctx.Done())
@@ -79,10 +138,28 @@ func (d *DatastoreSyncer) Start(ctx context.Context) error {
 		return errors.WithMessage(err, "error starting the syncer")
 	}
 
+	// The additional brokers are started asynchronously so an unavailable one doesn't hold up the others.
+	for _, b := range d.brokers[1:] {
+		go func(b *brokerConnection) {
+			if err := b.syncer.Start(ctx.Done()); err != nil {
+				logger.Errorf(err, "Error starting the syncer for broker %q", b.name)
+			}
+		}(b)
+	}
+
 	if err := d.ensureExclusiveEndpoint(ctx, syncer); err != nil {
 		return errors.WithMessage(err, "could not ensure exclusive submariner Endpoint")
 	}
 
+	// Preserve the global CIDRs appended to the existing local Cluster at runtime.
+	for _, obj := range syncer.ListLocalResources(&submarinerv1.Cluster{}) {
+		if existing := obj.(*submarinerv1.Cluster); existing.Spec.ClusterID == d.localCluster.Spec.ClusterID {
+			if err := d.adoptGlobalCIDRs(ctx, existing.Spec.GlobalCIDR); err != nil {
+				return errors.WithMessage(err, "error adopting the global CIDRs of the existing local submariner Cluster")
+			}
+		}
+	}
+
 	if err := d.createLocalCluster(ctx, syncer.GetLocalFederator()); err != nil {
 		return errors.WithMessage(err, "error creating the local submariner Cluster")
 	}
@@ -91,21 +168,34 @@ func (d *DatastoreSyncer) Start(ctx context.Context) error {
 		return errors.WithMessage(err, "error creating the local submariner Endpoint")
 	}
 
-	if len(d.localCluster.Spec.GlobalCIDR) > 0 {
+	if len(d.localClusterSpec().GlobalCIDR) > 0 {
 		if err := d.startGatewayWatcher(ctx.Done()); err != nil {
 			return errors.WithMessage(err, "startGatewayWatcher returned error")
 		}
 	}
 
+	go func() {
+		_ = wait.PollUntilContextCancel(ctx, BrokerReconcileInterval, false, func(ctx context.Context) (bool, error) {
+			d.reconcileWithBrokers(ctx)
+			return false, nil
+		})
+	}()
+
 	logger.Info("Datastore syncer started")
 
 	return nil
 }
 
 func (d *DatastoreSyncer) Cleanup(ctx context.Context) error {
-	syncer, err := d.createSyncer()
-	if err != nil {
-		return err
+	syncers := make([]*broker.Syncer, len(d.brokers))
+
+	var err error
+
+	for i, b := range d.brokers {
+		syncers[i], err = d.createSyncer(b)
+		if err != nil {
+			return err
+		}
 	}
 
 	localClient := d.syncerConfig.LocalClient
@@ -116,21 +206,21 @@ func (d *DatastoreSyncer) Cleanup(ctx context.Context) error {
 		}
 	}
 
-	err = d.cleanupResources(ctx, localClient.Resource(submarinerv1.EndpointGVR), syncer)
+	err = d.cleanupResources(ctx, localClient.Resource(submarinerv1.EndpointGVR), syncers)
 	if err != nil {
 		return err
 	}
 
-	err = d.cleanupResources(ctx, localClient.Resource(submarinerv1.ClusterGVR), syncer)
+	err = d.cleanupResources(ctx, localClient.Resource(submarinerv1.ClusterGVR), syncers)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return d.deleteSnapshot(ctx, localClient)
 }
 
 func (d *DatastoreSyncer) cleanupResources(ctx context.Context, client dynamic.NamespaceableResourceInterface,
-	syncer *broker.Syncer,
+	syncers []*broker.Syncer,
 ) error {
 	list, err := client.Namespace(d.syncerConfig.LocalNamespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -140,7 +230,7 @@ func (d *DatastoreSyncer) cleanupResources(ctx context.Context, client dynamic.N
 	for i := range list.Items {
 		obj := &list.Items[i]
 
-		err = syncer.GetLocalFederator().Delete(ctx, obj)
+		err = syncers[0].GetLocalFederator().Delete(ctx, obj)
 		if err != nil && !apierrors.IsNotFound(err) {
 			return errors.Wrapf(err, "error deleting submariner %s %q from the local datastore", obj.GetKind(), obj.GetName())
 		}
@@ -152,9 +242,11 @@ func (d *DatastoreSyncer) cleanupResources(ctx context.Context, client dynamic.N
 			continue
 		}
 
-		err = syncer.GetBrokerFederator().Delete(ctx, obj)
-		if err != nil && !apierrors.IsNotFound(err) {
-			return errors.Wrapf(err, "error deleting submariner %s %q from the remote datastore", obj.GetKind(), obj.GetName())
+		for _, syncer := range syncers {
+			err = syncer.GetBrokerFederator().Delete(ctx, obj)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "error deleting submariner %s %q from the remote datastore", obj.GetKind(), obj.GetName())
+			}
 		}
 
 		logger.Infof("Successfully deleted local submariner %s %q from the remote datastore", obj.GetKind(), obj.GetName())
@@ -163,49 +255,79 @@ func (d *DatastoreSyncer) cleanupResources(ctx context.Context, client dynamic.N
 	return nil
 }
 
-func (d *DatastoreSyncer) createSyncer() (*broker.Syncer, error) {
-	d.syncerConfig.ResourceConfigs = []broker.ResourceConfig{
+func (d *DatastoreSyncer) createSyncer(b *brokerConnection) (*broker.Syncer, error) {
+	b.config.ResourceConfigs = []broker.ResourceConfig{
 		{
-			LocalSourceNamespace: d.syncerConfig.LocalNamespace,
-			LocalResourceType:    &submarinerv1.Cluster{},
-			BrokerResourceType:   &submarinerv1.Cluster{},
+			LocalSourceNamespace:       d.syncerConfig.LocalNamespace,
+			LocalResourceType:          &submarinerv1.Cluster{},
+			OnSuccessfulSyncToBroker:   d.onLocalClusterSynced,
+			TransformBrokerToLocal:     d.fromBroker(b, d.shouldSyncRemoteCluster),
+			OnSuccessfulSyncFromBroker: d.onRemoteClusterSynced,
+			BrokerResourceType:         &submarinerv1.Cluster{},
 		},
 		{
-			LocalSourceNamespace:   d.syncerConfig.LocalNamespace,
-			LocalResourceType:      &submarinerv1.Endpoint{},
-			TransformBrokerToLocal: d.shouldSyncRemoteEndpoint,
-			BrokerResourceType:     &submarinerv1.Endpoint{},
+			LocalSourceNamespace:       d.syncerConfig.LocalNamespace,
+			LocalResourceType:          &submarinerv1.Endpoint{},
+			TransformBrokerToLocal:     d.fromBroker(b, d.shouldSyncRemoteEndpoint),
+			OnSuccessfulSyncFromBroker: d.onRemoteEndpointSynced,
+			BrokerResourceType:         &submarinerv1.Endpoint{},
 		},
 	}
 
-	syncer, err := broker.NewSyncer(d.syncerConfig)
+	syncer, err := broker.NewSyncer(b.config)
 
-	return syncer, errors.Wrap(err, "error creating the syncer")
+	return syncer, errors.Wrapf(err, "error creating the syncer for broker %q", b.name)
 }
 
 func (d *DatastoreSyncer) shouldSyncRemoteEndpoint(obj runtime.Object, _ int,
-	_ resourceSyncer.Operation,
+	op resourceSyncer.Operation,
 ) (runtime.Object, bool) {
 	remoteEndpoint := obj.(*submarinerv1.Endpoint)
 
-	for _, localSubnet := range d.localEndpoint.Spec().Subnets {
-		overlap, err := cidr.IsOverlapping(remoteEndpoint.Spec.Subnets, localSubnet)
-		if err != nil {
-			logger.Errorf(err, "Unable to validate if remote CIDR overlaps with local CIDR")
-			return nil, false
-		}
+	if op == resourceSyncer.Delete && d.retainOnUntrustedDelete(obj, submarinerv1.EndpointGVR) {
+		return nil, false
+	}
 
-		if overlap {
-			logger.Errorf(nil, "Skip processing the remote endpoint %#v as subnets are overlapping", remoteEndpoint)
-			return nil, false
+	if op == resourceSyncer.Delete {
+		d.clearFilteredPeer(remoteEndpoint.Spec.ClusterID)
+	} else if !d.isPeeringAllowed(remoteEndpoint.Spec.ClusterID) {
+		if err := d.deleteFilteredEndpoint(remoteEndpoint); err != nil {
+			logger.Errorf(err, "Unable to remove the Endpoint of a filtered remote cluster")
+			return nil, true
 		}
+
+		return nil, false
+	}
+
+	overlap, err := d.findCIDROverlap(remoteEndpoint)
+	if err != nil {
+		logger.Errorf(err, "Unable to validate if remote CIDR overlaps with local CIDR")
+		return nil, false
 	}
 
-	return obj, false
+	if overlap == nil || op == resourceSyncer.Delete {
+		d.clearCIDROverlap(remoteEndpoint.Spec.ClusterID)
+	} else {
+		d.setCIDROverlap(overlap)
+	}
+
+	if overlap != nil {
+		return nil, false
+	}
+
+	if op == resourceSyncer.Delete {
+		return obj, false
+	}
+
+	return d.withTransitSubnets(remoteEndpoint), false
 }
 
 func (d *DatastoreSyncer) ensureExclusiveEndpoint(ctx context.Context, syncer *broker.Syncer) error {
-	logger.Info("Ensuring we are the only endpoint active for this cluster")
+	if d.localEndpoint.Spec().IsActiveActive() {
+		logger.Info("Removing stale endpoints for this gateway")
+	} else {
+		logger.Info("Ensuring we are the only endpoint active for this cluster")
+	}
 
 	endpoints := syncer.ListLocalResources(&submarinerv1.Endpoint{})
 	for i := range endpoints {
@@ -218,6 +340,13 @@ func (d *DatastoreSyncer) ensureExclusiveEndpoint(ctx context.Context, syncer *b
 			continue
 		}
 
+		if d.localEndpoint.Spec().IsActiveActive() && existing.Spec.IsActiveActive() &&
+			existing.Spec.Hostname != d.localEndpoint.Spec().Hostname {
+			// In active-active mode, the Endpoints of the other gateways are in use. Only stale Endpoints previously
+			// published by this gateway are removed.
+			continue
+		}
+
 		err := syncer.GetLocalFederator().Delete(ctx, existing)
 		if err != nil && !apierrors.IsNotFound(err) {
 			return errors.Wrapf(err, "error deleting submariner Endpoint %q from the local datastore", existing.Name)
@@ -263,13 +392,15 @@ func (d *DatastoreSyncer) startGatewayWatcher(stopCh <-chan struct{}) error {
 }
 
 func (d *DatastoreSyncer) createLocalCluster(ctx context.Context, federator federate.Federator) error {
-	logger.Infof("Creating local submariner Cluster: %s", resource.ToJSON(d.localCluster))
+	spec := d.localClusterSpec()
+
+	logger.Infof("Creating local submariner Cluster: %s", resource.ToJSON(spec))
 
 	cluster := &submarinerv1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: resource.EnsureValidName(d.localCluster.Spec.ClusterID),
+			Name: resource.EnsureValidName(spec.ClusterID),
 		},
-		Spec: d.localCluster.Spec,
+		Spec: spec,
 	}
 
 	return federator.Distribute(ctx, cluster) //nolint:wrapcheck  // Let the caller wrap it
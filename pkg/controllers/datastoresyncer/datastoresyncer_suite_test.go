@@ -36,6 +36,7 @@ import (
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/controllers/datastoresyncer"
 	"github.com/submariner-io/submariner/pkg/endpoint"
+	"github.com/submariner-io/submariner/pkg/peering"
 	"github.com/submariner-io/submariner/pkg/types"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -45,6 +46,7 @@ import (
 	"k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 )
 
 const (
@@ -70,25 +72,32 @@ func init() {
 
 var _ = BeforeSuite(func() {
 	kzerolog.InitK8sLogging()
+
+	datastoresyncer.BrokerReconcileInterval = 300 * time.Millisecond
+	datastoresyncer.RetainedResourceTimeout = 300 * time.Millisecond
 })
 
 type testDriver struct {
-	syncer           *datastoresyncer.DatastoreSyncer
-	localCluster     *types.SubmarinerCluster
-	localEndpoint    *submarinerv1.EndpointSpec
-	localClient      *dynamicfake.FakeDynamicClient
-	brokerClient     *dynamicfake.FakeDynamicClient
-	localClusters    dynamic.ResourceInterface
-	brokerClusters   dynamic.ResourceInterface
-	localEndpoints   dynamic.ResourceInterface
-	localGateways    dynamic.ResourceInterface
-	brokerEndpoints  dynamic.ResourceInterface
-	syncerScheme     *runtime.Scheme
-	restMapper       meta.RESTMapper
-	stopFn           context.CancelFunc
-	startCompleted   chan error
-	expectedStartErr error
-	doStart          bool
+	syncer            *datastoresyncer.DatastoreSyncer
+	localCluster      *types.SubmarinerCluster
+	localEndpoint     *submarinerv1.EndpointSpec
+	localClient       *dynamicfake.FakeDynamicClient
+	brokerClient      *dynamicfake.FakeDynamicClient
+	localClusters     dynamic.ResourceInterface
+	brokerClusters    dynamic.ResourceInterface
+	localEndpoints    dynamic.ResourceInterface
+	localGateways     dynamic.ResourceInterface
+	brokerEndpoints   dynamic.ResourceInterface
+	syncerScheme      *runtime.Scheme
+	restMapper        meta.RESTMapper
+	stopFn            context.CancelFunc
+	startCompleted    chan error
+	expectedStartErr  error
+	doStart           bool
+	eventRecorder     *record.FakeRecorder
+	peeringPolicy     *peering.Policy
+	transitRouting    bool
+	additionalBrokers []datastoresyncer.BrokerConfig
 }
 
 func newTestDriver() *testDriver {
@@ -115,6 +124,10 @@ func newTestDriver() *testDriver {
 	BeforeEach(func() {
 		t.expectedStartErr = nil
 		t.doStart = true
+		t.eventRecorder = record.NewFakeRecorder(10)
+		t.peeringPolicy = nil
+		t.transitRouting = false
+		t.additionalBrokers = nil
 
 		t.syncerScheme = runtime.NewScheme()
 		Expect(submarinerv1.AddToScheme(t.syncerScheme)).To(Succeed())
@@ -160,7 +173,8 @@ func (t *testDriver) run() {
 		BrokerNamespace: brokerNamespace,
 		RestMapper:      t.restMapper,
 		Scheme:          t.syncerScheme,
-	}, t.localCluster, endpoint.NewLocal(t.localEndpoint, t.localClient, localNamespace))
+	}, t.localCluster, endpoint.NewLocal(t.localEndpoint, t.localClient, localNamespace), t.eventRecorder,
+		t.peeringPolicy, t.transitRouting, t.additionalBrokers)
 
 	if t.doStart {
 		var ctx context.Context
@@ -32,13 +32,17 @@ import (
 func (d *DatastoreSyncer) handleCreateOrUpdateGateway(obj runtime.Object, _ int) bool {
 	globalIP := resource.MustToMeta(obj).GetAnnotations()[constants.SmGlobalIP]
 
-	// Validate that the global IP falls in the global CIDR allocated to the cluster.
-	if globalIP != "" {
-		_, ipnet, err := net.ParseCIDR(d.localCluster.Spec.GlobalCIDR[0])
+	if globalIP == "" {
+		return false
+	}
+
+	// Validate that the global IP falls in one of the global CIDRs allocated to the cluster.
+	for _, globalCIDR := range d.localClusterSpec().GlobalCIDR {
+		_, ipnet, err := net.ParseCIDR(globalCIDR)
 		if err != nil {
 			// Ideally this will not happen as globalCIDR is expected to be a valid CIDR.
-			logger.Errorf(err, "Error parsing the GlobalCIDR %q", d.localCluster.Spec.GlobalCIDR)
-			return false
+			logger.Errorf(err, "Error parsing the GlobalCIDR %q", globalCIDR)
+			continue
 		}
 
 		if ipnet.Contains(net.ParseIP(globalIP)) {
@@ -0,0 +1,83 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer
+
+import (
+	"context"
+	"slices"
+
+	"github.com/pkg/errors"
+	resourceSyncer "github.com/submariner-io/admiral/pkg/syncer"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// onLocalClusterSynced adopts the global CIDRs appended to the local Cluster at runtime.
+func (d *DatastoreSyncer) onLocalClusterSynced(obj runtime.Object, op resourceSyncer.Operation) bool {
+	cluster := obj.(*submarinerv1.Cluster)
+	if op == resourceSyncer.Delete || cluster.Spec.ClusterID != d.localCluster.Spec.ClusterID {
+		return false
+	}
+
+	if err := d.adoptGlobalCIDRs(context.TODO(), cluster.Spec.GlobalCIDR); err != nil {
+		logger.Errorf(err, "Error adopting the global CIDRs %v of the local Cluster", cluster.Spec.GlobalCIDR)
+		return true
+	}
+
+	return false
+}
+
+// adoptGlobalCIDRs adds the global CIDRs appended to the given ones to the local Cluster and Endpoint so they're preserved when
+// the Cluster is re-created and routed by the remote clusters. If the given CIDRs don't extend the current ones, they're stale
+// or the global CIDR was reconfigured, so they're ignored.
+func (d *DatastoreSyncer) adoptGlobalCIDRs(ctx context.Context, globalCIDRs []string) error {
+	d.localClusterMutex.Lock()
+	defer d.localClusterMutex.Unlock()
+
+	current := d.localCluster.Spec.GlobalCIDR
+	if len(current) == 0 || len(globalCIDRs) <= len(current) || !slices.Equal(globalCIDRs[:len(current)], current) {
+		return nil
+	}
+
+	appended := globalCIDRs[len(current):]
+
+	logger.Infof("Adding the appended global CIDRs %v to the local Cluster and Endpoint", appended)
+
+	err := d.localEndpoint.Update(ctx, func(existing *submarinerv1.EndpointSpec) {
+		for _, cidr := range appended {
+			if !slices.Contains(existing.Subnets, cidr) {
+				existing.Subnets = append(existing.Subnets, cidr)
+			}
+		}
+	})
+	if err != nil {
+		return errors.Wrap(err, "error updating the local Endpoint subnets")
+	}
+
+	d.localCluster.Spec.GlobalCIDR = slices.Clone(globalCIDRs)
+
+	return nil
+}
+
+func (d *DatastoreSyncer) localClusterSpec() submarinerv1.ClusterSpec {
+	d.localClusterMutex.Lock()
+	defer d.localClusterMutex.Unlock()
+
+	return *d.localCluster.Spec.DeepCopy()
+}
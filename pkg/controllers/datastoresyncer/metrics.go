@@ -0,0 +1,60 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	remoteClusterLabel       = "remote_cluster"
+	globalnetResolvableLabel = "globalnet_resolvable"
+)
+
+var cidrOverlapsGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "submariner_cidr_overlaps",
+		Help: "Remote clusters whose Endpoint isn't synced because their CIDRs overlap the local CIDRs (by remote cluster)",
+	},
+	[]string{
+		remoteClusterLabel,
+		globalnetResolvableLabel,
+	},
+)
+
+func init() {
+	prometheus.MustRegister(cidrOverlapsGauge)
+}
+
+func cidrOverlapLabels(overlap *CIDROverlap) prometheus.Labels {
+	return prometheus.Labels{
+		remoteClusterLabel:       overlap.ClusterID,
+		globalnetResolvableLabel: strconv.FormatBool(overlap.GlobalnetResolvable),
+	}
+}
+
+func recordCIDROverlap(overlap *CIDROverlap) {
+	cidrOverlapsGauge.With(cidrOverlapLabels(overlap)).Set(1)
+}
+
+func recordCIDROverlapResolved(overlap *CIDROverlap) {
+	cidrOverlapsGauge.Delete(cidrOverlapLabels(overlap))
+}
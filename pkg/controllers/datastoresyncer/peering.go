@@ -0,0 +1,183 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	resourceSyncer "github.com/submariner-io/admiral/pkg/syncer"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// FilteredPeer describes a remote cluster whose Endpoint isn't synced because of the peering policy.
+type FilteredPeer struct {
+	ClusterID string
+	Reason    string
+}
+
+// GetFilteredPeers returns the remote clusters currently excluded by the peering policy, sorted by cluster ID.
+func (d *DatastoreSyncer) GetFilteredPeers() []FilteredPeer {
+	d.peersMutex.Lock()
+	defer d.peersMutex.Unlock()
+
+	peers := make([]FilteredPeer, 0, len(d.filteredPeers))
+	for clusterID, reason := range d.filteredPeers {
+		peers = append(peers, FilteredPeer{ClusterID: clusterID, Reason: reason})
+	}
+
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].ClusterID < peers[j].ClusterID
+	})
+
+	return peers
+}
+
+func (d *DatastoreSyncer) isPeeringAllowed(clusterID string) bool {
+	allowed, reason := d.peeringPolicy.Allows(clusterID, d.remoteClusterLabels(clusterID))
+
+	d.peersMutex.Lock()
+	defer d.peersMutex.Unlock()
+
+	if allowed {
+		if _, found := d.filteredPeers[clusterID]; found {
+			delete(d.filteredPeers, clusterID)
+			logger.Infof("Peering with remote cluster %q is now allowed by the peering policy", clusterID)
+		}
+
+		return true
+	}
+
+	if d.filteredPeers[clusterID] != reason {
+		d.filteredPeers[clusterID] = reason
+		logger.Infof("Not syncing the Endpoint of remote cluster %q as peering isn't allowed: %s", clusterID, reason)
+	}
+
+	return false
+}
+
+func (d *DatastoreSyncer) clearFilteredPeer(clusterID string) {
+	d.peersMutex.Lock()
+	defer d.peersMutex.Unlock()
+
+	delete(d.filteredPeers, clusterID)
+}
+
+func (d *DatastoreSyncer) remoteClusterLabels(clusterID string) map[string]string {
+	d.peersMutex.Lock()
+	defer d.peersMutex.Unlock()
+
+	return d.clusterLabels[clusterID]
+}
+
+// deleteFilteredEndpoint removes the local copy of a remote Endpoint that's no longer allowed by the peering policy.
+func (d *DatastoreSyncer) deleteFilteredEndpoint(endpoint *submarinerv1.Endpoint) error {
+	if d.syncer == nil {
+		return nil
+	}
+
+	_, found, err := d.syncer.GetLocalResource(endpoint.Name, d.syncerConfig.LocalNamespace, &submarinerv1.Endpoint{})
+	if err != nil || !found {
+		return err //nolint:wrapcheck // No need to wrap
+	}
+
+	err = d.syncer.GetLocalFederator().Delete(context.TODO(), &submarinerv1.Endpoint{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      endpoint.Name,
+			Namespace: d.syncerConfig.LocalNamespace,
+		},
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "error deleting the submariner Endpoint %q", endpoint.Name)
+	}
+
+	logger.Infof("Deleted the submariner Endpoint %q of filtered remote cluster %q", endpoint.Name, endpoint.Spec.ClusterID)
+
+	d.updateSnapshot(endpoint, resourceSyncer.Delete)
+
+	return nil
+}
+
+// onRemoteClusterSynced records a remote cluster's Cluster resource in the snapshot, records its labels and
+// re-evaluates the peering policy for its Endpoints, as the policy's label selector may no longer, or now, match.
+func (d *DatastoreSyncer) onRemoteClusterSynced(obj runtime.Object, op resourceSyncer.Operation) bool {
+	cluster := obj.(*submarinerv1.Cluster)
+	if cluster.Spec.ClusterID == d.localCluster.Spec.ClusterID {
+		return false
+	}
+
+	d.updateSnapshot(cluster, op)
+
+	if !d.peeringPolicy.HasSelector() {
+		return false
+	}
+
+	d.peersMutex.Lock()
+
+	if op == resourceSyncer.Delete {
+		delete(d.clusterLabels, cluster.Spec.ClusterID)
+	} else {
+		d.clusterLabels[cluster.Spec.ClusterID] = cluster.Labels
+	}
+
+	d.peersMutex.Unlock()
+
+	err := d.reconcileRemoteEndpoints(cluster.Spec.ClusterID)
+	if err != nil {
+		logger.Errorf(err, "Error applying the peering policy to the Endpoints of remote cluster %q", cluster.Spec.ClusterID)
+		return true
+	}
+
+	return false
+}
+
+func (d *DatastoreSyncer) reconcileRemoteEndpoints(clusterID string) error {
+	for _, endpoint := range d.cachedBrokerEndpoints() {
+		if endpoint.Spec.ClusterID != clusterID {
+			continue
+		}
+
+		if err := d.syncRemoteEndpoint(endpoint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncRemoteEndpoint applies the broker to local transformation to the given broker Endpoint and syncs the result
+// locally, as the syncer would.
+func (d *DatastoreSyncer) syncRemoteEndpoint(endpoint *submarinerv1.Endpoint) error {
+	toSync, requeue := d.shouldSyncRemoteEndpoint(endpoint, 0, resourceSyncer.Update)
+	if requeue {
+		return errors.Errorf("unable to process the broker Endpoint %q", endpoint.Name)
+	}
+
+	if toSync == nil {
+		return nil
+	}
+
+	err := d.syncer.GetLocalFederator().Distribute(context.TODO(), toSync)
+
+	return errors.Wrapf(err, "error syncing the broker Endpoint %q", endpoint.Name)
+}
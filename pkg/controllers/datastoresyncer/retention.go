@@ -0,0 +1,212 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/resource"
+	resourceSyncer "github.com/submariner-io/admiral/pkg/syncer"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// BrokerReconcileInterval is the interval at which the local datastore is reconciled with the broker.
+	BrokerReconcileInterval = 30 * time.Second
+
+	// RetainedResourceTimeout is how long a remote resource deleted from the broker while the broker was unavailable is
+	// kept in the local datastore once the broker is available again, before it's deleted.
+	RetainedResourceTimeout = 5 * time.Minute
+)
+
+// retainedResource is a remote resource whose deletion from the broker couldn't be trusted, as the broker was unavailable
+// or had lost the local cluster's resources, and whose local copy was therefore kept.
+type retainedResource struct {
+	broker *brokerConnection
+	gvr    schema.GroupVersionResource
+	name   string
+	// since is the time from which the broker was available again; it's zero while it's unavailable.
+	since time.Time
+}
+
+// isBrokerAvailable returns whether the given broker is reachable and has the local Cluster, in which case its view of
+// the remote resources can be trusted. An error is returned if the broker is unreachable.
+func (d *DatastoreSyncer) isBrokerAvailable(ctx context.Context, b *brokerConnection) (bool, error) {
+	_, err := b.syncer.GetBrokerClient().Resource(submarinerv1.ClusterGVR).Namespace(b.syncer.GetBrokerNamespace()).Get(ctx,
+		resource.EnsureValidName(d.localCluster.Spec.ClusterID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, errors.Wrap(err, "error retrieving the local Cluster from the broker")
+	}
+
+	return true, nil
+}
+
+// retainOnUntrustedDelete keeps the local copy of a remote resource deleted from the broker if the broker isn't available,
+// so the existing cables stay in place. It returns true if the resource was retained.
+func (d *DatastoreSyncer) retainOnUntrustedDelete(obj runtime.Object, gvr schema.GroupVersionResource) bool {
+	if d.syncer == nil {
+		return false
+	}
+
+	b := d.brokerFor(obj)
+
+	available, err := d.isBrokerAvailable(context.TODO(), b)
+	if available {
+		return false
+	}
+
+	name := resource.MustToMeta(obj).GetName()
+
+	_, found, _ := d.syncer.GetLocalResource(name, d.syncerConfig.LocalNamespace, obj)
+	if !found {
+		return false
+	}
+
+	d.snapshotMutex.Lock()
+	defer d.snapshotMutex.Unlock()
+
+	key := resourceKey(gvr, name)
+	if _, exists := d.retained[key]; !exists {
+		d.retained[key] = &retainedResource{broker: b, gvr: gvr, name: name}
+	}
+
+	if err != nil {
+		logger.Warningf("Retaining the local copy of %s %q deleted from broker %q as the broker is unavailable: %v",
+			gvr.Resource, name, b.name, err)
+	} else {
+		logger.Warningf("Retaining the local copy of %s %q deleted from broker %q as the broker no longer has the local Cluster",
+			gvr.Resource, name, b.name)
+	}
+
+	return true
+}
+
+func (d *DatastoreSyncer) shouldSyncRemoteCluster(obj runtime.Object, _ int, op resourceSyncer.Operation) (runtime.Object, bool) {
+	if op == resourceSyncer.Delete && d.retainOnUntrustedDelete(obj, submarinerv1.ClusterGVR) {
+		return nil, false
+	}
+
+	return obj, false
+}
+
+func (d *DatastoreSyncer) reconcileWithBrokers(ctx context.Context) {
+	for _, b := range d.brokers {
+		d.reconcileWithBroker(ctx, b)
+	}
+}
+
+// reconcileWithBroker republishes the local cluster's resources if the broker lost them, and deletes the retained remote
+// resources that didn't reappear on the broker within the RetainedResourceTimeout once it's available again.
+func (d *DatastoreSyncer) reconcileWithBroker(ctx context.Context, b *brokerConnection) {
+	available, err := d.isBrokerAvailable(ctx, b)
+	if err != nil {
+		logger.Warningf("Broker %q is unavailable: %v", b.name, err)
+		return
+	}
+
+	if !available {
+		logger.Infof("The local Cluster is missing from broker %q - republishing the local resources", b.name)
+
+		if err := d.republishLocalResources(ctx, b); err != nil {
+			logger.Errorf(err, "Error republishing the local resources to broker %q", b.name)
+		}
+
+		return
+	}
+
+	d.snapshotMutex.Lock()
+	defer d.snapshotMutex.Unlock()
+
+	for key, retained := range d.retained {
+		if retained.broker != b {
+			continue
+		}
+
+		_, err := b.syncer.GetBrokerClient().Resource(retained.gvr).Namespace(b.syncer.GetBrokerNamespace()).Get(ctx,
+			retained.name, metav1.GetOptions{})
+		if err == nil {
+			delete(d.retained, key)
+			continue
+		}
+
+		if !apierrors.IsNotFound(err) {
+			logger.Warningf("Error retrieving %s %q from the broker: %v", retained.gvr.Resource, retained.name, err)
+			continue
+		}
+
+		if retained.since.IsZero() {
+			retained.since = time.Now()
+		}
+
+		if time.Since(retained.since) < RetainedResourceTimeout {
+			continue
+		}
+
+		err = d.syncer.GetLocalClient().Resource(retained.gvr).Namespace(d.syncerConfig.LocalNamespace).Delete(ctx,
+			retained.name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			logger.Errorf(err, "Error deleting the retained %s %q", retained.gvr.Resource, retained.name)
+			continue
+		}
+
+		logger.Infof("Deleted the retained %s %q as it's no longer present on the broker", retained.gvr.Resource, retained.name)
+
+		delete(d.retained, key)
+
+		if retained.gvr == submarinerv1.EndpointGVR {
+			delete(d.snapshot.endpoints, retained.name)
+		} else {
+			delete(d.snapshot.clusters, retained.name)
+		}
+
+		if err := d.writeSnapshot(ctx); err != nil {
+			logger.Errorf(err, "Error writing the datastore snapshot")
+		}
+	}
+}
+
+func (d *DatastoreSyncer) republishLocalResources(ctx context.Context, b *brokerConnection) error {
+	if err := d.createLocalCluster(ctx, b.syncer.GetBrokerFederator()); err != nil {
+		return errors.Wrap(err, "error republishing the local Cluster")
+	}
+
+	endpoints := d.syncer.ListLocalResources(&submarinerv1.Endpoint{})
+	for i := range endpoints {
+		endpoint := endpoints[i].(*submarinerv1.Endpoint)
+		if endpoint.Spec.ClusterID != d.localCluster.Spec.ClusterID {
+			continue
+		}
+
+		if err := b.syncer.GetBrokerFederator().Distribute(ctx, endpoint); err != nil {
+			return errors.Wrapf(err, "error republishing the local Endpoint %q", endpoint.Name)
+		}
+	}
+
+	return nil
+}
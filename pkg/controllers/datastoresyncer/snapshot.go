@@ -0,0 +1,279 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/resource"
+	resourceSyncer "github.com/submariner-io/admiral/pkg/syncer"
+	"github.com/submariner-io/admiral/pkg/util"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+)
+
+// SnapshotConfigMapName is the name of the ConfigMap holding the last known remote Endpoints and Clusters.
+const SnapshotConfigMapName = "submariner-datastore-snapshot"
+
+const (
+	snapshotEndpointsKey = "endpoints"
+	snapshotClustersKey  = "clusters"
+)
+
+var configMapGVR = corev1.SchemeGroupVersion.WithResource("configmaps")
+
+// datastoreSnapshot is a durable, last known good copy of the remote Endpoints and Clusters synced from the broker. It
+// is used to bootstrap the tunnels when the broker is unavailable.
+type datastoreSnapshot struct {
+	endpoints map[string]*submarinerv1.Endpoint
+	clusters  map[string]*submarinerv1.Cluster
+}
+
+func newDatastoreSnapshot() datastoreSnapshot {
+	return datastoreSnapshot{
+		endpoints: map[string]*submarinerv1.Endpoint{},
+		clusters:  map[string]*submarinerv1.Cluster{},
+	}
+}
+
+func (d *DatastoreSyncer) loadSnapshot(ctx context.Context) error {
+	obj, err := d.configMaps().Get(ctx, SnapshotConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "error retrieving the datastore snapshot")
+	}
+
+	data, _, _ := unstructured.NestedStringMap(obj.Object, "data")
+
+	var (
+		endpoints []*submarinerv1.Endpoint
+		clusters  []*submarinerv1.Cluster
+	)
+
+	if err := unmarshalSnapshotData(data[snapshotEndpointsKey], &endpoints); err != nil {
+		return err
+	}
+
+	if err := unmarshalSnapshotData(data[snapshotClustersKey], &clusters); err != nil {
+		return err
+	}
+
+	d.snapshotMutex.Lock()
+	defer d.snapshotMutex.Unlock()
+
+	for _, endpoint := range endpoints {
+		d.snapshot.endpoints[endpoint.Name] = endpoint
+	}
+
+	for _, cluster := range clusters {
+		d.snapshot.clusters[cluster.Name] = cluster
+	}
+
+	return nil
+}
+
+// restoreSnapshot creates the remote Endpoints and Clusters of the snapshot that don't exist in the local datastore, so
+// the tunnels can be established even if the broker is unavailable. Once the broker is available, they're reconciled
+// with the broker resources.
+func (d *DatastoreSyncer) restoreSnapshot(ctx context.Context) error {
+	d.snapshotMutex.Lock()
+
+	toRestore := make([]runtime.Object, 0, len(d.snapshot.endpoints)+len(d.snapshot.clusters))
+
+	for _, cluster := range d.snapshot.clusters {
+		toRestore = append(toRestore, cluster.DeepCopy())
+	}
+
+	for _, endpoint := range d.snapshot.endpoints {
+		toRestore = append(toRestore, endpoint.DeepCopy())
+	}
+
+	d.snapshotMutex.Unlock()
+
+	for _, obj := range toRestore {
+		metaObj := resource.MustToMeta(obj)
+
+		gvr := submarinerv1.EndpointGVR
+		if _, ok := obj.(*submarinerv1.Cluster); ok {
+			gvr = submarinerv1.ClusterGVR
+		}
+
+		_, err := d.syncer.GetLocalClient().Resource(gvr).Namespace(d.syncerConfig.LocalNamespace).Get(ctx,
+			metaObj.GetName(), metav1.GetOptions{})
+		if err == nil {
+			continue
+		}
+
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "error retrieving %s %q", gvr.Resource, metaObj.GetName())
+		}
+
+		metaObj.SetNamespace(d.syncerConfig.LocalNamespace)
+
+		err = d.syncer.GetLocalFederator().Distribute(ctx, obj)
+		if err != nil {
+			return errors.Wrapf(err, "error restoring %s %q from the datastore snapshot", gvr.Resource, metaObj.GetName())
+		}
+
+		logger.Infof("Restored %s %q from the datastore snapshot", gvr.Resource, metaObj.GetName())
+	}
+
+	return nil
+}
+
+// updateSnapshot records the given remote Endpoint or Cluster, synced with the given operation, in the snapshot.
+func (d *DatastoreSyncer) updateSnapshot(obj runtime.Object, op resourceSyncer.Operation) {
+	d.snapshotMutex.Lock()
+	defer d.snapshotMutex.Unlock()
+
+	changed := false
+
+	switch t := obj.(type) {
+	case *submarinerv1.Endpoint:
+		delete(d.retained, resourceKey(submarinerv1.EndpointGVR, t.Name))
+
+		existing, found := d.snapshot.endpoints[t.Name]
+		if op == resourceSyncer.Delete {
+			delete(d.snapshot.endpoints, t.Name)
+			changed = found
+		} else if !found || !equality.Semantic.DeepEqual(existing.Labels, t.Labels) ||
+			!equality.Semantic.DeepEqual(existing.Spec, t.Spec) {
+			d.snapshot.endpoints[t.Name] = &submarinerv1.Endpoint{
+				ObjectMeta: metav1.ObjectMeta{Name: t.Name, Labels: t.Labels},
+				Spec:       t.Spec,
+			}
+			changed = true
+		}
+	case *submarinerv1.Cluster:
+		delete(d.retained, resourceKey(submarinerv1.ClusterGVR, t.Name))
+
+		existing, found := d.snapshot.clusters[t.Name]
+		if op == resourceSyncer.Delete {
+			delete(d.snapshot.clusters, t.Name)
+			changed = found
+		} else if !found || !equality.Semantic.DeepEqual(existing.Labels, t.Labels) ||
+			!equality.Semantic.DeepEqual(existing.Spec, t.Spec) {
+			d.snapshot.clusters[t.Name] = &submarinerv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: t.Name, Labels: t.Labels},
+				Spec:       t.Spec,
+			}
+			changed = true
+		}
+	}
+
+	if !changed {
+		return
+	}
+
+	if err := d.writeSnapshot(context.TODO()); err != nil {
+		logger.Errorf(err, "Error writing the datastore snapshot")
+	}
+}
+
+// writeSnapshot must be called with the snapshot lock held.
+func (d *DatastoreSyncer) writeSnapshot(ctx context.Context) error {
+	if d.syncer == nil {
+		return nil
+	}
+
+	endpoints := make([]*submarinerv1.Endpoint, 0, len(d.snapshot.endpoints))
+	for _, endpoint := range d.snapshot.endpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Name < endpoints[j].Name
+	})
+
+	clusters := make([]*submarinerv1.Cluster, 0, len(d.snapshot.clusters))
+	for _, cluster := range d.snapshot.clusters {
+		clusters = append(clusters, cluster)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+
+	endpointsData, err := json.Marshal(endpoints)
+	if err != nil {
+		return errors.Wrap(err, "error marshalling the snapshot Endpoints")
+	}
+
+	clustersData, err := json.Marshal(clusters)
+	if err != nil {
+		return errors.Wrap(err, "error marshalling the snapshot Clusters")
+	}
+
+	configMap := resource.MustToUnstructured(&corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SnapshotConfigMapName,
+			Namespace: d.syncerConfig.LocalNamespace,
+		},
+		Data: map[string]string{
+			snapshotEndpointsKey: string(endpointsData),
+			snapshotClustersKey:  string(clustersData),
+		},
+	})
+
+	_, err = util.CreateOrUpdate[*unstructured.Unstructured](ctx, resource.ForDynamic(d.configMaps()), configMap,
+		func(existing *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			existing.Object["data"] = configMap.Object["data"]
+			return existing, nil
+		})
+
+	return errors.Wrap(err, "error writing the datastore snapshot ConfigMap")
+}
+
+func (d *DatastoreSyncer) deleteSnapshot(ctx context.Context, localClient dynamic.Interface) error {
+	err := localClient.Resource(configMapGVR).Namespace(d.syncerConfig.LocalNamespace).Delete(ctx, SnapshotConfigMapName,
+		metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "error deleting the datastore snapshot ConfigMap")
+	}
+
+	return nil
+}
+
+func (d *DatastoreSyncer) configMaps() dynamic.ResourceInterface {
+	return d.syncer.GetLocalClient().Resource(configMapGVR).Namespace(d.syncerConfig.LocalNamespace)
+}
+
+func unmarshalSnapshotData[T any](data string, into *T) error {
+	if data == "" {
+		return nil
+	}
+
+	return errors.Wrap(json.Unmarshal([]byte(data), into), "error unmarshalling the datastore snapshot")
+}
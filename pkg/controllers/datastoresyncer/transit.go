@@ -0,0 +1,275 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastoresyncer
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/pkg/errors"
+	resourceSyncer "github.com/submariner-io/admiral/pkg/syncer"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/cidr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// withTransitSubnets returns the given remote Endpoint with the subnets of the transit routes the local cluster uses
+// through it appended, so the cable drivers and route agents route them over the cable to the Endpoint.
+func (d *DatastoreSyncer) withTransitSubnets(remoteEndpoint *submarinerv1.Endpoint) *submarinerv1.Endpoint {
+	if len(remoteEndpoint.Spec.TransitRoutes) == 0 {
+		return remoteEndpoint
+	}
+
+	endpoints := d.cachedBrokerEndpoints()
+
+	routes := d.selectTransitRoutes(endpoints, d.directlyConnectedClusters(endpoints))[remoteEndpoint.Name]
+	if len(routes) == 0 {
+		return remoteEndpoint
+	}
+
+	augmented := remoteEndpoint.DeepCopy()
+
+	for i := range routes {
+		for _, subnet := range routes[i].Subnets {
+			if !slices.Contains(augmented.Spec.Subnets, subnet) {
+				augmented.Spec.Subnets = append(augmented.Spec.Subnets, subnet)
+			}
+		}
+	}
+
+	logger.Infof("Routing the subnets of remote clusters %v through the Endpoint %q of cluster %q",
+		transitClusterIDs(routes), remoteEndpoint.Name, remoteEndpoint.Spec.ClusterID)
+
+	return augmented
+}
+
+// directlyConnectedClusters returns the IDs of the remote clusters with an Endpoint that's synced locally, ie allowed by
+// the peering policy and without overlapping CIDRs.
+func (d *DatastoreSyncer) directlyConnectedClusters(endpoints []*submarinerv1.Endpoint) sets.Set[string] {
+	direct := sets.New[string]()
+
+	for _, endpoint := range endpoints {
+		clusterID := endpoint.Spec.ClusterID
+		if clusterID == d.localCluster.Spec.ClusterID || direct.Has(clusterID) {
+			continue
+		}
+
+		if allowed, _ := d.peeringPolicy.Allows(clusterID, d.remoteClusterLabels(clusterID)); !allowed {
+			continue
+		}
+
+		if overlap, err := d.findCIDROverlap(endpoint); err != nil || overlap != nil {
+			continue
+		}
+
+		direct.Insert(clusterID)
+	}
+
+	return direct
+}
+
+// selectTransitRoutes returns the transit routes the local cluster uses, keyed by the name of the advertising Endpoint.
+// A route is rejected if it leads to the local cluster or traverses it, if its cluster is directly connected or if its
+// subnets overlap the local subnets. If several Endpoints advertise a route to the same cluster, the one with the
+// shortest path is used, with ties broken by the lowest advertising cluster ID.
+func (d *DatastoreSyncer) selectTransitRoutes(endpoints []*submarinerv1.Endpoint, direct sets.Set[string],
+) map[string][]submarinerv1.TransitRoute {
+	type candidate struct {
+		advertiser *submarinerv1.Endpoint
+		route      *submarinerv1.TransitRoute
+	}
+
+	localClusterID := d.localCluster.Spec.ClusterID
+	best := map[string]candidate{}
+
+	for _, endpoint := range endpoints {
+		if !direct.Has(endpoint.Spec.ClusterID) {
+			continue
+		}
+
+		for i := range endpoint.Spec.TransitRoutes {
+			route := &endpoint.Spec.TransitRoutes[i]
+
+			if route.ClusterID == localClusterID || slices.Contains(route.Path, localClusterID) ||
+				direct.Has(route.ClusterID) || len(route.Path) == 0 || route.Path[0] != endpoint.Spec.ClusterID {
+				continue
+			}
+
+			if d.overlapsLocalSubnets(route.Subnets) {
+				continue
+			}
+
+			existing, found := best[route.ClusterID]
+			if found && !isPreferredTransitRoute(endpoint, route, existing.advertiser, existing.route) {
+				continue
+			}
+
+			best[route.ClusterID] = candidate{advertiser: endpoint, route: route}
+		}
+	}
+
+	selected := map[string][]submarinerv1.TransitRoute{}
+
+	for _, c := range best {
+		selected[c.advertiser.Name] = append(selected[c.advertiser.Name], *c.route)
+	}
+
+	for name := range selected {
+		sortTransitRoutes(selected[name])
+	}
+
+	return selected
+}
+
+func (d *DatastoreSyncer) overlapsLocalSubnets(subnets []string) bool {
+	localSubnets := d.localEndpoint.Spec().Subnets
+
+	for _, subnet := range subnets {
+		overlapping, err := cidr.IsOverlapping(localSubnets, subnet)
+		if err != nil || overlapping {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isPreferredTransitRoute(endpoint *submarinerv1.Endpoint, route *submarinerv1.TransitRoute,
+	otherEndpoint *submarinerv1.Endpoint, otherRoute *submarinerv1.TransitRoute,
+) bool {
+	if len(route.Path) != len(otherRoute.Path) {
+		return len(route.Path) < len(otherRoute.Path)
+	}
+
+	if endpoint.Spec.ClusterID != otherEndpoint.Spec.ClusterID {
+		return endpoint.Spec.ClusterID < otherEndpoint.Spec.ClusterID
+	}
+
+	return endpoint.Name < otherEndpoint.Name
+}
+
+// advertisedTransitRoutes returns the transit routes the local gateway advertises: a route to each directly connected
+// cluster and, extended with the local cluster ID, the transit routes the local cluster uses.
+func (d *DatastoreSyncer) advertisedTransitRoutes(endpoints []*submarinerv1.Endpoint) []submarinerv1.TransitRoute {
+	localClusterID := d.localCluster.Spec.ClusterID
+	direct := d.directlyConnectedClusters(endpoints)
+	selected := d.selectTransitRoutes(endpoints, direct)
+	advertised := map[string]submarinerv1.TransitRoute{}
+
+	for _, endpoint := range endpoints {
+		if !direct.Has(endpoint.Spec.ClusterID) {
+			continue
+		}
+
+		if _, found := advertised[endpoint.Spec.ClusterID]; !found {
+			advertised[endpoint.Spec.ClusterID] = submarinerv1.TransitRoute{
+				ClusterID: endpoint.Spec.ClusterID,
+				Subnets:   endpoint.Spec.Subnets,
+				Path:      []string{localClusterID},
+			}
+		}
+
+		for _, route := range selected[endpoint.Name] {
+			advertised[route.ClusterID] = submarinerv1.TransitRoute{
+				ClusterID: route.ClusterID,
+				Subnets:   route.Subnets,
+				Path:      append([]string{localClusterID}, route.Path...),
+			}
+		}
+	}
+
+	if len(advertised) == 0 {
+		return nil
+	}
+
+	routes := make([]submarinerv1.TransitRoute, 0, len(advertised))
+	for clusterID := range advertised {
+		routes = append(routes, advertised[clusterID])
+	}
+
+	sortTransitRoutes(routes)
+
+	return routes
+}
+
+func (d *DatastoreSyncer) updateAdvertisedTransitRoutes(ctx context.Context, endpoints []*submarinerv1.Endpoint) error {
+	routes := d.advertisedTransitRoutes(endpoints)
+	if equality.Semantic.DeepEqual(d.localEndpoint.Spec().TransitRoutes, routes) {
+		return nil
+	}
+
+	logger.Infof("Advertising transit routes to remote clusters %v", transitClusterIDs(routes))
+
+	err := d.localEndpoint.Update(ctx, func(existing *submarinerv1.EndpointSpec) {
+		existing.TransitRoutes = routes
+	})
+
+	return errors.Wrap(err, "error updating the transit routes of the local Endpoint")
+}
+
+// onRemoteEndpointSynced records a remote Endpoint in the snapshot and re-evaluates the transit routes, as the routes
+// advertised by the local gateway and those used through other remote Endpoints depend on the set of remote Endpoints.
+func (d *DatastoreSyncer) onRemoteEndpointSynced(obj runtime.Object, op resourceSyncer.Operation) bool {
+	synced := obj.(*submarinerv1.Endpoint)
+
+	if synced.Spec.ClusterID != d.localCluster.Spec.ClusterID {
+		d.updateSnapshot(synced, op)
+	}
+
+	endpoints := d.cachedBrokerEndpoints()
+
+	if d.transitRoutingEnabled {
+		if err := d.updateAdvertisedTransitRoutes(context.TODO(), endpoints); err != nil {
+			logger.Error(err, "Error re-evaluating the transit routes")
+			return true
+		}
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.Spec.ClusterID == d.localCluster.Spec.ClusterID || endpoint.Name == synced.Name ||
+			len(endpoint.Spec.TransitRoutes) == 0 {
+			continue
+		}
+
+		if err := d.syncRemoteEndpoint(endpoint); err != nil {
+			logger.Errorf(err, "Error re-evaluating the transit routes through Endpoint %q", endpoint.Name)
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortTransitRoutes(routes []submarinerv1.TransitRoute) {
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].ClusterID < routes[j].ClusterID
+	})
+}
+
+func transitClusterIDs(routes []submarinerv1.TransitRoute) []string {
+	clusterIDs := make([]string, len(routes))
+	for i := range routes {
+		clusterIDs[i] = routes[i].ClusterID
+	}
+
+	return clusterIDs
+}
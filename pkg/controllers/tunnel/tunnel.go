@@ -19,6 +19,7 @@ limitations under the License.
 package tunnel
 
 import (
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -26,20 +27,38 @@ import (
 	"github.com/submariner-io/admiral/pkg/watcher"
 	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/cableengine"
+	"github.com/submariner-io/submariner/pkg/peering"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 type controller struct {
-	engine cableengine.Engine
+	engine        cableengine.Engine
+	peeringPolicy *peering.Policy
+	mutex         sync.Mutex
+	clusterLabels map[string]map[string]string
+	endpoints     map[string]*v1.Endpoint
+	filtered      sets.Set[string]
+	// The transit routes of the local Endpoint the cables were last connected with
+	transitRoutes []v1.TransitRoute
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("Tunnel")}
 
-func StartController(engine cableengine.Engine, namespace string, config *watcher.Config, stopCh <-chan struct{}) error {
+func StartController(engine cableengine.Engine, namespace string, peeringPolicy *peering.Policy, config *watcher.Config,
+	stopCh <-chan struct{},
+) error {
 	logger.Info("Starting the tunnel controller")
 
-	c := &controller{engine: engine}
+	c := &controller{
+		engine:        engine,
+		peeringPolicy: peeringPolicy,
+		clusterLabels: map[string]map[string]string{},
+		endpoints:     map[string]*v1.Endpoint{},
+		filtered:      sets.New[string](),
+	}
 
 	config.ResourceConfigs = []watcher.ResourceConfig{
 		{
@@ -54,6 +73,19 @@ func StartController(engine cableengine.Engine, namespace string, config *watche
 		},
 	}
 
+	if peeringPolicy.HasSelector() {
+		config.ResourceConfigs = append(config.ResourceConfigs, watcher.ResourceConfig{
+			Name:         "Tunnel Controller Cluster watcher",
+			ResourceType: &v1.Cluster{},
+			Handler: watcher.EventHandlerFuncs{
+				OnCreateFunc: c.handleCreatedOrUpdatedCluster,
+				OnUpdateFunc: c.handleCreatedOrUpdatedCluster,
+				OnDeleteFunc: c.handleRemovedCluster,
+			},
+			SourceNamespace: namespace,
+		})
+	}
+
 	if config.ResyncPeriod == 0 {
 		config.ResyncPeriod = time.Second * 30
 	}
@@ -76,6 +108,22 @@ func (c *controller) handleCreatedOrUpdatedEndpoint(obj runtime.Object, _ int) b
 
 	logger.V(log.TRACE).Infof("Tunnel controller processing added or updated submariner Endpoint object: %#v", endpoint)
 
+	if endpoint.Spec.CableName == c.engine.GetLocalEndpoint().Spec.CableName {
+		return c.handleLocalEndpoint(endpoint)
+	}
+
+	c.mutex.Lock()
+	c.endpoints[endpoint.Name] = endpoint
+	c.mutex.Unlock()
+
+	if !c.isPeeringAllowed(endpoint) {
+		return c.removeFilteredCable(endpoint)
+	}
+
+	c.mutex.Lock()
+	c.filtered.Delete(endpoint.Spec.CableName)
+	c.mutex.Unlock()
+
 	err := c.engine.InstallCable(endpoint)
 	if err != nil {
 		logger.Errorf(err, "Error installing cable for Endpoint %#v", endpoint)
@@ -90,6 +138,11 @@ func (c *controller) handleRemovedEndpoint(obj runtime.Object, _ int) bool {
 
 	logger.V(log.DEBUG).Infof("Tunnel controller processing removed submariner Endpoint object: %#v", endpoint)
 
+	c.mutex.Lock()
+	delete(c.endpoints, endpoint.Name)
+	c.filtered.Delete(endpoint.Spec.CableName)
+	c.mutex.Unlock()
+
 	if err := c.engine.RemoveCable(endpoint); err != nil {
 		logger.Errorf(err, "Tunnel controller failed to remove Endpoint cable %#v from the engine", endpoint)
 		return true
@@ -99,3 +152,119 @@ func (c *controller) handleRemovedEndpoint(obj runtime.Object, _ int) bool {
 
 	return false
 }
+
+// handleLocalEndpoint reconnects the installed cables whose transit subnets changed when the transit routes advertised by
+// the local Endpoint change, so the cable drivers forward the traffic of the new set of transit subnets.
+func (c *controller) handleLocalEndpoint(endpoint *v1.Endpoint) bool {
+	if equality.Semantic.DeepEqual(c.transitRoutes, endpoint.Spec.TransitRoutes) {
+		return false
+	}
+
+	previous := &v1.EndpointSpec{TransitRoutes: c.transitRoutes}
+
+	c.mutex.Lock()
+
+	cableNames := []string{}
+
+	for _, remote := range c.endpoints {
+		if !sets.New(previous.TransitSubnetsExcluding(remote.Spec.ClusterID)...).Equal(
+			sets.New(endpoint.Spec.TransitSubnetsExcluding(remote.Spec.ClusterID)...)) {
+			cableNames = append(cableNames, remote.Spec.CableName)
+		}
+	}
+
+	c.mutex.Unlock()
+
+	if len(cableNames) > 0 {
+		logger.Infof("The transit routes of the local Endpoint changed - reconnecting the cables %v", cableNames)
+	}
+
+	for _, cableName := range cableNames {
+		err := c.engine.ReconnectCable(cableName, false)
+		if err != nil && !errors.Is(err, cableengine.ErrCableNotInstalled) {
+			logger.Errorf(err, "Error reconnecting cable %q", cableName)
+			return true
+		}
+	}
+
+	c.transitRoutes = endpoint.Spec.TransitRoutes
+
+	return false
+}
+
+func (c *controller) isPeeringAllowed(endpoint *v1.Endpoint) bool {
+	c.mutex.Lock()
+	clusterLabels := c.clusterLabels[endpoint.Spec.ClusterID]
+	c.mutex.Unlock()
+
+	allowed, reason := c.peeringPolicy.Allows(endpoint.Spec.ClusterID, clusterLabels)
+	if !allowed {
+		logger.V(log.DEBUG).Infof("Peering with remote cluster %q isn't allowed: %s", endpoint.Spec.ClusterID, reason)
+	}
+
+	return allowed
+}
+
+func (c *controller) removeFilteredCable(endpoint *v1.Endpoint) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.filtered.Has(endpoint.Spec.CableName) {
+		return false
+	}
+
+	if err := c.engine.RemoveCable(endpoint); err != nil {
+		logger.Errorf(err, "Tunnel controller failed to remove the cable of filtered Endpoint %q", endpoint.Spec.CableName)
+		return true
+	}
+
+	logger.Infof("Removed the cable %q as peering with remote cluster %q isn't allowed", endpoint.Spec.CableName,
+		endpoint.Spec.ClusterID)
+
+	c.filtered.Insert(endpoint.Spec.CableName)
+
+	return false
+}
+
+func (c *controller) handleCreatedOrUpdatedCluster(obj runtime.Object, _ int) bool {
+	cluster := obj.(*v1.Cluster)
+
+	c.mutex.Lock()
+	c.clusterLabels[cluster.Spec.ClusterID] = cluster.Labels
+	c.mutex.Unlock()
+
+	return c.reprocessEndpointsOf(cluster.Spec.ClusterID)
+}
+
+func (c *controller) handleRemovedCluster(obj runtime.Object, _ int) bool {
+	cluster := obj.(*v1.Cluster)
+
+	c.mutex.Lock()
+	delete(c.clusterLabels, cluster.Spec.ClusterID)
+	c.mutex.Unlock()
+
+	return c.reprocessEndpointsOf(cluster.Spec.ClusterID)
+}
+
+// reprocessEndpointsOf re-applies the peering policy to the Endpoints of a cluster whose labels changed.
+func (c *controller) reprocessEndpointsOf(clusterID string) bool {
+	c.mutex.Lock()
+
+	var endpoints []*v1.Endpoint
+
+	for _, endpoint := range c.endpoints {
+		if endpoint.Spec.ClusterID == clusterID {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	c.mutex.Unlock()
+
+	requeue := false
+
+	for _, endpoint := range endpoints {
+		requeue = c.handleCreatedOrUpdatedEndpoint(endpoint, 0) || requeue
+	}
+
+	return requeue
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/controllers/tunnel"
 	submendpoint "github.com/submariner-io/submariner/pkg/endpoint"
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
+	"github.com/submariner-io/submariner/pkg/peering"
 	"github.com/submariner-io/submariner/pkg/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -63,14 +64,18 @@ var _ = BeforeSuite(func() {
 
 var _ = Describe("Managing tunnels", func() {
 	var (
-		config    *watcher.Config
-		endpoints dynamic.ResourceInterface
-		endpoint  *v1.Endpoint
-		stopCh    chan struct{}
+		config        *watcher.Config
+		endpoints     dynamic.ResourceInterface
+		clusters      dynamic.ResourceInterface
+		endpoint      *v1.Endpoint
+		localEndpoint *v1.Endpoint
+		peeringPolicy *peering.Policy
+		stopCh        chan struct{}
 	)
 
 	BeforeEach(func() {
 		fakeDriver = fake.New()
+		peeringPolicy = nil
 
 		endpoint = &v1.Endpoint{
 			ObjectMeta: metav1.ObjectMeta{
@@ -85,6 +90,20 @@ var _ = Describe("Managing tunnels", func() {
 			},
 		}
 
+		localEndpoint = &v1.Endpoint{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "west-submariner-cable-west-192-68-2-1",
+				Namespace: namespace,
+			},
+			Spec: v1.EndpointSpec{
+				CableName: "submariner-cable-west-192-68-2-1",
+				ClusterID: "west",
+				Hostname:  "bruins",
+				PrivateIP: "192.68.2.1",
+				Backend:   fake.DriverName,
+			},
+		}
+
 		Expect(v1.AddToScheme(kubeScheme.Scheme)).To(Succeed())
 
 		scheme := runtime.NewScheme()
@@ -96,6 +115,7 @@ var _ = Describe("Managing tunnels", func() {
 		gvr := test.GetGroupVersionResourceFor(restMapper, &v1.Endpoint{})
 
 		endpoints = client.Resource(*gvr).Namespace(namespace)
+		clusters = client.Resource(*test.GetGroupVersionResourceFor(restMapper, &v1.Cluster{})).Namespace(namespace)
 
 		config = &watcher.Config{
 			RestMapper: restMapper,
@@ -105,11 +125,9 @@ var _ = Describe("Managing tunnels", func() {
 	})
 
 	JustBeforeEach(func() {
-		localEp := submendpoint.NewLocal(&v1.EndpointSpec{
-			Backend: fake.DriverName,
-		}, fakeClient.NewSimpleDynamicClient(kubeScheme.Scheme), "")
+		localEp := submendpoint.NewLocal(&localEndpoint.Spec, fakeClient.NewSimpleDynamicClient(kubeScheme.Scheme), "")
 
-		engine := cableengine.NewEngine(&types.SubmarinerCluster{}, localEp)
+		engine := cableengine.NewEngine(&types.SubmarinerCluster{ID: localEndpoint.Spec.ClusterID}, localEp)
 
 		nat, err := natdiscovery.New(localEp)
 		Expect(err).To(Succeed())
@@ -120,7 +138,7 @@ var _ = Describe("Managing tunnels", func() {
 
 		stopCh = make(chan struct{})
 
-		Expect(tunnel.StartController(engine, namespace, config, stopCh)).To(Succeed())
+		Expect(tunnel.StartController(engine, namespace, peeringPolicy, config, stopCh)).To(Succeed())
 	})
 
 	AfterEach(func() {
@@ -168,6 +186,86 @@ var _ = Describe("Managing tunnels", func() {
 		})
 	})
 
+	When("the peering policy denies the Endpoint's cluster", func() {
+		BeforeEach(func() {
+			var err error
+
+			peeringPolicy, err = peering.NewPolicy([]string{"west"}, nil, "")
+			Expect(err).To(Succeed())
+		})
+
+		It("should not install the cable", func() {
+			test.CreateResource(endpoints, endpoint)
+			fakeDriver.AwaitNoConnectToEndpoint()
+		})
+	})
+
+	When("the peering policy has a Cluster label selector", func() {
+		var cluster *v1.Cluster
+
+		BeforeEach(func() {
+			var err error
+
+			peeringPolicy, err = peering.NewPolicy(nil, nil, "region=us")
+			Expect(err).To(Succeed())
+
+			cluster = &v1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "east",
+					Namespace: namespace,
+					Labels:    map[string]string{"region": "us"},
+				},
+				Spec: v1.ClusterSpec{
+					ClusterID: "east",
+				},
+			}
+		})
+
+		It("should only install the cable while the Endpoint's Cluster matches", func() {
+			test.CreateResource(endpoints, endpoint)
+			fakeDriver.AwaitNoConnectToEndpoint()
+
+			test.CreateResource(clusters, cluster)
+			verifyConnectToEndpoint()
+
+			cluster.Labels["region"] = "eu"
+			test.UpdateResource(clusters, cluster)
+			verifyDisconnectFromEndpoint()
+		})
+	})
+
+	When("the transit routes of the local Endpoint change", func() {
+		It("should reconnect the cables", func() {
+			test.CreateResource(endpoints, endpoint)
+			verifyConnectToEndpoint()
+
+			test.CreateResource(endpoints, localEndpoint)
+			fakeDriver.AwaitNoDisconnectFromEndpoint()
+
+			localEndpoint.Spec.TransitRoutes = []v1.TransitRoute{
+				{ClusterID: "north", Subnets: []string{"30.0.0.0/16"}, Path: []string{"west"}},
+			}
+
+			test.UpdateResource(endpoints, localEndpoint)
+			verifyDisconnectFromEndpoint()
+			verifyConnectToEndpoint()
+		})
+		It("should not reconnect the cables whose transit subnets didn't change", func() {
+			test.CreateResource(endpoints, endpoint)
+			verifyConnectToEndpoint()
+
+			test.CreateResource(endpoints, localEndpoint)
+			fakeDriver.AwaitNoDisconnectFromEndpoint()
+
+			localEndpoint.Spec.TransitRoutes = []v1.TransitRoute{
+				{ClusterID: "east", Subnets: []string{"20.0.0.0/16"}, Path: []string{"west"}},
+			}
+
+			test.UpdateResource(endpoints, localEndpoint)
+			fakeDriver.AwaitNoDisconnectFromEndpoint()
+		})
+	})
+
 	When("install cable initially fails", func() {
 		BeforeEach(func() {
 			config.ResyncPeriod = time.Millisecond * 500
@@ -159,6 +159,8 @@ func GetLocalSpec(submSpec *types.SubmarinerSpecification, k8sClient kubernetes.
 	} else {
 		localSubnets = append(localSubnets, cidr.ExtractIPv4Subnets(submSpec.ServiceCidr)...)
 		localSubnets = append(localSubnets, cidr.ExtractIPv4Subnets(submSpec.ClusterCidr)...)
+		localSubnets = append(localSubnets, cidr.ExtractIPv6Subnets(submSpec.ServiceCidr)...)
+		localSubnets = append(localSubnets, cidr.ExtractIPv6Subnets(submSpec.ClusterCidr)...)
 	}
 
 	backendConfig, err := getBackendConfig(gwNode)
@@ -170,6 +172,10 @@ func GetLocalSpec(submSpec *types.SubmarinerSpecification, k8sClient kubernetes.
 		backendConfig[submv1.UsingLoadBalancer] = "true"
 	}
 
+	if submSpec.ActiveActiveGateways {
+		backendConfig[submv1.GatewayHAModeConfig] = submv1.HAModeActiveActive
+	}
+
 	endpointSpec := &submv1.EndpointSpec{
 		CableName:     fmt.Sprintf("submariner-cable-%s-%s", submSpec.ClusterID, strings.ReplaceAll(privateIP, ".", "-")),
 		ClusterID:     submSpec.ClusterID,
@@ -188,6 +194,17 @@ func GetLocalSpec(submSpec *types.SubmarinerSpecification, k8sClient kubernetes.
 
 	endpointSpec.PublicIP = publicIP
 
+	if len(cidr.ExtractIPv6Subnets(submSpec.ClusterCidr)) > 0 {
+		// Dual-stack cluster - also advertise the IPv6 addresses so remote clusters can connect over IPv6.
+		if privateIPv6 := GetLocalIPv6(); privateIPv6 != "" {
+			endpointSpec.SetPrivateIP(privateIPv6)
+		}
+
+		if publicIPv6 := getPublicIPv6(submSpec, k8sClient, backendConfig); publicIPv6 != "" {
+			endpointSpec.SetPublicIP(publicIPv6)
+		}
+	}
+
 	if submSpec.HealthCheckEnabled && !globalnetEnabled {
 		// When globalnet is enabled, HealthCheckIP will be the globalIP assigned to the Active GatewayNode.
 		// In a fresh deployment, globalIP annotation for the node might take few seconds. So we listen on NodeEvents
@@ -200,6 +217,10 @@ func GetLocalSpec(submSpec *types.SubmarinerSpecification, k8sClient kubernetes.
 		}
 
 		endpointSpec.HealthCheckIP = cniIface.IPAddress
+
+		if cniIface.IPv6Address != "" {
+			endpointSpec.SetHealthCheckIP(cniIface.IPv6Address)
+		}
 	}
 
 	return endpointSpec, nil
@@ -249,7 +270,8 @@ func addConfigFrom(nodeName string, configs, backendConfig map[string]string, wa
 	for cfg, value := range configs {
 		if strings.HasPrefix(cfg, submv1.GatewayConfigPrefix) {
 			config := cfg[len(submv1.GatewayConfigPrefix):]
-			if !validConfigs.Has(config) {
+			// Cable driver specific UDP ports (eg "vxlan-udp-port") are allowed so several cable drivers can run side by side.
+			if !validConfigs.Has(config) && !strings.HasSuffix(config, "-"+submv1.UDPPortConfig) {
 				return errors.Errorf("unknown config annotation %q on node %q", cfg, nodeName)
 			}
 
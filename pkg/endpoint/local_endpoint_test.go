@@ -110,6 +110,19 @@ var _ = Describe("GetLocalSpec", func() {
 		Expect(spec.HealthCheckIP).To(BeEmpty())
 	})
 
+	When("active-active gateways are enabled", func() {
+		BeforeEach(func() {
+			submSpec.ActiveActiveGateways = true
+		})
+
+		It("should advertise the HA mode in the backend config", func() {
+			spec, err := endpoint.GetLocalSpec(submSpec, client, true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(spec.BackendConfig).To(HaveKeyWithValue(submarinerv1.GatewayHAModeConfig, submarinerv1.HAModeActiveActive))
+			Expect(spec.IsActiveActive()).To(BeTrue())
+		})
+	})
+
 	When("the gateway node is not annotated with udp port", func() {
 		BeforeEach(func() {
 			delete(node.Labels, backendConfigPrefix+testUDPPortLabel)
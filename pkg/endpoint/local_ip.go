@@ -20,10 +20,11 @@ package endpoint
 
 import (
 	"net"
+
+	"github.com/submariner-io/admiral/pkg/log"
 )
 
 func GetLocalIPForDestination(dst string) string {
-	// Revisit when IPv6 support is added.
 	conn, err := net.Dial("udp4", dst+":53")
 	logger.FatalOnError(err, "Error getting local IP")
 
@@ -37,3 +38,19 @@ func GetLocalIPForDestination(dst string) string {
 func GetLocalIP() string {
 	return GetLocalIPForDestination("8.8.8.8")
 }
+
+// GetLocalIPv6 returns the local IPv6 address used to reach external destinations or an empty string if the host has
+// no IPv6 route.
+func GetLocalIPv6() string {
+	conn, err := net.Dial("udp6", "[2001:4860:4860::8888]:53")
+	if err != nil {
+		logger.V(log.DEBUG).Infof("Unable to determine the local IPv6 address: %v", err)
+		return ""
+	}
+
+	defer conn.Close()
+
+	localAddr := conn.LocalAddr().(*net.UDPAddr)
+
+	return localAddr.IP.String()
+}
@@ -45,11 +45,16 @@ type publicIPResolverFunction func(clientset kubernetes.Interface, namespace, va
 var publicIPMethods = map[string]publicIPResolverFunction{
 	v1.API:          publicAPI,
 	v1.IPv4:         publicIP,
+	v1.IPv6:         publicIPv6,
 	v1.LoadBalancer: publicLoadBalancerIP,
 	v1.DNS:          publicDNSIP,
 }
 
-var IPv4RE = regexp.MustCompile(`(?:\d{1,3}\.){3}\d{1,3}`)
+var (
+	IPv4RE = regexp.MustCompile(`(?:\d{1,3}\.){3}\d{1,3}`)
+	// IPv6RE matches IPv6 address candidates which are further validated via net.ParseIP.
+	IPv6RE = regexp.MustCompile(`[0-9a-fA-F]{0,4}(?::[0-9a-fA-F]{0,4}){2,7}`)
+)
 
 func getPublicIPResolvers() string {
 	serverList := []string{
@@ -63,9 +68,7 @@ func getPublicIPResolvers() string {
 	return strings.Join(serverList, ",")
 }
 
-func getPublicIP(submSpec *types.SubmarinerSpecification, k8sClient kubernetes.Interface,
-	backendConfig map[string]string, airGapped bool,
-) (string, error) {
+func getPublicIPConfig(submSpec *types.SubmarinerSpecification, backendConfig map[string]string) string {
 	// If the node is annotated with a public-ip, the same is used as the public-ip of local endpoint.
 	config, ok := backendConfig[v1.PublicIP]
 	if !ok {
@@ -76,6 +79,14 @@ func getPublicIP(submSpec *types.SubmarinerSpecification, k8sClient kubernetes.I
 		}
 	}
 
+	return config
+}
+
+func getPublicIP(submSpec *types.SubmarinerSpecification, k8sClient kubernetes.Interface,
+	backendConfig map[string]string, airGapped bool,
+) (string, error) {
+	config := getPublicIPConfig(submSpec, backendConfig)
+
 	if airGapped {
 		ip, err := resolveIPInAirGappedDeployment(k8sClient, submSpec.Namespace, config)
 		if err != nil {
@@ -90,11 +101,14 @@ func getPublicIP(submSpec *types.SubmarinerSpecification, k8sClient kubernetes.I
 	errs := make([]error, 0, len(resolvers))
 
 	for _, resolver := range resolvers {
-		resolver = strings.Trim(resolver, " ")
+		parts, err := splitResolver(resolver, config)
+		if err != nil {
+			return "", err
+		}
 
-		parts := strings.Split(resolver, ":")
-		if len(parts) != 2 {
-			return "", errors.Errorf("invalid format for %q annotation: %q", v1.GatewayConfigPrefix+v1.PublicIP, config)
+		// IPv6 resolvers are only used by getPublicIPv6, the legacy public IP is IPv4.
+		if parts[0] == v1.IPv6 {
+			continue
 		}
 
 		ip, err := resolvePublicIP(k8sClient, submSpec.Namespace, parts)
@@ -117,11 +131,9 @@ func resolveIPInAirGappedDeployment(k8sClient kubernetes.Interface, namespace, c
 	resolvers := strings.Split(config, ",")
 
 	for _, resolver := range resolvers {
-		resolver = strings.Trim(resolver, " ")
-
-		parts := strings.Split(resolver, ":")
-		if len(parts) != 2 {
-			return "", errors.Errorf("invalid format for %q annotation: %q", v1.GatewayConfigPrefix+v1.PublicIP, config)
+		parts, err := splitResolver(resolver, config)
+		if err != nil {
+			return "", err
 		}
 
 		if parts[0] != v1.IPv4 {
@@ -134,6 +146,42 @@ func resolveIPInAirGappedDeployment(k8sClient kubernetes.Interface, namespace, c
 	return "", nil
 }
 
+// getPublicIPv6 returns the IPv6 public IP explicitly configured via an "ipv6:" resolver, if any. The default resolvers
+// only return IPv4 addresses so IPv6 public IPs must be configured.
+func getPublicIPv6(submSpec *types.SubmarinerSpecification, k8sClient kubernetes.Interface,
+	backendConfig map[string]string,
+) string {
+	config := getPublicIPConfig(submSpec, backendConfig)
+
+	for _, resolver := range strings.Split(config, ",") {
+		parts, err := splitResolver(resolver, config)
+		if err != nil || parts[0] != v1.IPv6 {
+			continue
+		}
+
+		ip, err := resolvePublicIP(k8sClient, submSpec.Namespace, parts)
+		if err != nil {
+			logger.Warningf("Unable to resolve the IPv6 public IP from %q: %v", resolver, err)
+			continue
+		}
+
+		return ip
+	}
+
+	return ""
+}
+
+// splitResolver splits a "<method>:<value>" resolver. Only the first colon is significant as the value may be an IPv6
+// address.
+func splitResolver(resolver, config string) ([]string, error) {
+	parts := strings.SplitN(strings.Trim(resolver, " "), ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.Errorf("invalid format for %q annotation: %q", v1.GatewayConfigPrefix+v1.PublicIP, config)
+	}
+
+	return parts, nil
+}
+
 func resolvePublicIP(k8sClient kubernetes.Interface, namespace string, parts []string) (string, error) {
 	method, ok := publicIPMethods[parts[0]]
 	if !ok {
@@ -172,6 +220,10 @@ func publicIP(_ kubernetes.Interface, _, value string) (string, error) {
 	return firstIPv4InString(value)
 }
 
+func publicIPv6(_ kubernetes.Interface, _, value string) (string, error) {
+	return firstIPv6InString(value)
+}
+
 var loadBalancerRetryConfig = wait.Backoff{
 	Cap:      6 * time.Minute,
 	Duration: 5 * time.Second,
@@ -237,3 +289,14 @@ func firstIPv4InString(body string) (string, error) {
 
 	return matches[0], nil
 }
+
+func firstIPv6InString(body string) (string, error) {
+	for _, match := range IPv6RE.FindAllString(body, -1) {
+		ip := net.ParseIP(match)
+		if ip != nil && ip.To4() == nil {
+			return ip.String(), nil
+		}
+	}
+
+	return "", errors.Errorf("No IPv6 found in: %q", body)
+}
@@ -51,6 +51,24 @@ var _ = Describe("firstIPv4InString", func() {
 	})
 })
 
+var _ = Describe("firstIPv6InString", func() {
+	When("the content has an IPv6", func() {
+		It("should return the IP", func() {
+			ip, err := firstIPv6InString("{\"ip\": \"2001:db8::1\", \"time\": \"10:20:30\"}")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ip).To(Equal("2001:db8::1"))
+		})
+	})
+
+	When("the content only has an IPv4", func() {
+		It("should result in error", func() {
+			ip, err := firstIPv6InString("1.2.3.4")
+			Expect(err).To(HaveOccurred())
+			Expect(ip).To(Equal(""))
+		})
+	})
+})
+
 const (
 	testServiceName = "my-loadbalancer"
 	testNamespace   = "namespace"
@@ -122,6 +140,38 @@ var _ = Describe("public ip resolvers", func() {
 		})
 	})
 
+	When("IPv4 and IPv6 entries are specified", func() {
+		It("should return the IPv4 IP and resolve the IPv6 IP separately", func() {
+			backendConfig[publicIPConfig] = ipv4PublicIP + ",ipv6:2001:db8::1"
+			client := fake.NewClientset()
+			ip, err := getPublicIP(submSpec, client, backendConfig, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ip).To(Equal(testIP))
+			Expect(getPublicIPv6(submSpec, client, backendConfig)).To(Equal("2001:db8::1"))
+		})
+	})
+
+	When("IPv6 and IPv4 entries are specified in that order", func() {
+		It("should return the IPv4 IP", func() {
+			backendConfig[publicIPConfig] = "ipv6:2001:db8::1," + ipv4PublicIP
+			client := fake.NewClientset()
+			ip, err := getPublicIP(submSpec, client, backendConfig, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ip).To(Equal(testIP))
+			Expect(getPublicIPv6(submSpec, client, backendConfig)).To(Equal("2001:db8::1"))
+		})
+	})
+
+	When("IPv6 and IPv4 entries are specified in that order in air-gapped deployment", func() {
+		It("should return the IPv4 IP", func() {
+			backendConfig[publicIPConfig] = "ipv6:2001:db8::1," + ipv4PublicIP
+			client := fake.NewClientset()
+			ip, err := getPublicIP(submSpec, client, backendConfig, true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ip).To(Equal(testIP))
+		})
+	})
+
 	When("an IPv4 entry specified in air-gapped deployment", func() {
 		It("should return the IP and not an empty value", func() {
 			backendConfig[publicIPConfig] = ipv4PublicIP
@@ -148,6 +148,31 @@ var _ = Describe("Event controller", func() {
 			t.awaitEvent(testing.EvRemoteEndpointCreated, staleEndpoint)
 		})
 	})
+
+	When("multiple remote Endpoints for an active-active cluster are created and removed", func() {
+		It("should notify the handler of each Endpoint and only of the last removal", func() {
+			now := time.Now()
+			activeActive := map[string]string{submV1.GatewayHAModeConfig: submV1.HAModeActiveActive}
+
+			endpoint1 := t.CreateEndpoint(&submV1.Endpoint{
+				ObjectMeta: v1meta.ObjectMeta{Name: "gw1", CreationTimestamp: v1meta.NewTime(now.Add(2 * time.Second))},
+				Spec:       submV1.EndpointSpec{ClusterID: "east", BackendConfig: activeActive},
+			})
+			t.awaitEvent(testing.EvRemoteEndpointCreated, endpoint1)
+
+			endpoint2 := t.CreateEndpoint(&submV1.Endpoint{
+				ObjectMeta: v1meta.ObjectMeta{Name: "gw2", CreationTimestamp: v1meta.NewTime(now)},
+				Spec:       submV1.EndpointSpec{ClusterID: "east", BackendConfig: activeActive},
+			})
+			t.awaitEvent(testing.EvRemoteEndpointCreated, endpoint2)
+
+			t.DeleteEndpoint(endpoint1.GetName())
+			t.ensureNoEvents()
+
+			t.DeleteEndpoint(endpoint2.GetName())
+			t.awaitEvent(testing.EvRemoteEndpointRemoved, endpoint2)
+		})
+	})
 })
 
 type testDriver struct {
@@ -68,9 +68,10 @@ func (c *handlerController) handleCreatedLocalEndpoint(endpoint *smv1.Endpoint)
 }
 
 func (c *handlerController) handleCreatedRemoteEndpoint(endpoint *smv1.Endpoint) error {
+	// Each gateway of an active-active cluster publishes its own Endpoint so they don't supersede one another.
 	lastProcessedTime, ok := c.remoteEndpointTimeStamp[endpoint.Spec.ClusterID]
 
-	if ok && lastProcessedTime.After(endpoint.CreationTimestamp.Time) {
+	if ok && !endpoint.Spec.IsActiveActive() && lastProcessedTime.After(endpoint.CreationTimestamp.Time) {
 		logger.Infof("Ignoring new remote %#v since a later endpoint was already processed", endpoint)
 		return nil
 	}
@@ -67,6 +67,10 @@ func (c *handlerController) handleRemovedLocalEndpoint(endpoint *smv1.Endpoint)
 }
 
 func (c *handlerController) handleRemovedRemoteEndpoint(endpoint *smv1.Endpoint) error {
+	if endpoint.Spec.IsActiveActive() {
+		return c.handleRemovedActiveActiveRemoteEndpoint(endpoint)
+	}
+
 	lastProcessedTime, ok := c.remoteEndpointTimeStamp[endpoint.Spec.ClusterID]
 
 	if ok && lastProcessedTime.After(endpoint.CreationTimestamp.Time) {
@@ -80,3 +84,25 @@ func (c *handlerController) handleRemovedRemoteEndpoint(endpoint *smv1.Endpoint)
 
 	return c.handler.RemoteEndpointRemoved(endpoint) //nolint:wrapcheck  // Let the caller wrap it
 }
+
+func (c *handlerController) handleRemovedActiveActiveRemoteEndpoint(endpoint *smv1.Endpoint) error {
+	c.handlerState.remoteEndpoints.Delete(endpoint.Name)
+
+	remaining := false
+
+	c.handlerState.remoteEndpoints.Range(func(_, value any) bool {
+		remaining = value.(*smv1.Endpoint).Spec.ClusterID == endpoint.Spec.ClusterID
+		return !remaining
+	})
+
+	// The routes to the cluster remain valid while any of its gateways are still present.
+	if remaining {
+		logger.Infof("Ignoring deleted remote %q since other gateways for cluster %q remain", endpoint.Name,
+			endpoint.Spec.ClusterID)
+		return nil
+	}
+
+	delete(c.remoteEndpointTimeStamp, endpoint.Spec.ClusterID)
+
+	return c.handler.RemoteEndpointRemoved(endpoint) //nolint:wrapcheck  // Let the caller wrap it
+}
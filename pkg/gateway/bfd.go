@@ -0,0 +1,191 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	subv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const leaderIdentitySuffix = "-submariner-gateway"
+
+// startBFD starts the BFD server and monitors the leader election lease so that, while this gateway is a passive
+// candidate, a BFD session is maintained with the active gateway. The active gateway responds to the candidates'
+// sessions passively. If the active gateway stops responding, the lease is taken over immediately rather than waiting
+// for it to expire.
+func (g *gatewayType) startBFD(ctx context.Context) error {
+	err := g.bfdServer.Run(ctx.Done())
+	if err != nil {
+		return errors.Wrap(err, "error starting the BFD server")
+	}
+
+	lock, err := g.newLeaderLock()
+	if err != nil {
+		return err
+	}
+
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		g.checkBFDPeer(ctx, lock)
+	}, g.RetryPeriod)
+
+	return nil
+}
+
+func (g *gatewayType) checkBFDPeer(ctx context.Context, lock resourcelock.Interface) {
+	record, _, err := lock.Get(ctx)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Warningf("Error retrieving the leader election record: %v", err)
+		}
+
+		g.setBFDPeer(nil, "")
+
+		return
+	}
+
+	if record.HolderIdentity == "" || record.HolderIdentity == g.leaderIdentity() {
+		g.setBFDPeer(nil, "")
+		return
+	}
+
+	peer, err := g.bfdPeerFor(ctx, record.HolderIdentity)
+	if err != nil {
+		logger.Warningf("Error determining the BFD peer address for leader %q: %v", record.HolderIdentity, err)
+		return
+	}
+
+	g.setBFDPeer(peer, record.HolderIdentity)
+}
+
+// bfdPeerFor returns the BFD address advertised in the local Endpoint published by the given leader, or nil if the
+// leader doesn't have BFD enabled.
+func (g *gatewayType) bfdPeerFor(ctx context.Context, holderIdentity string) (*net.UDPAddr, error) {
+	hostname := strings.TrimSuffix(holderIdentity, leaderIdentitySuffix)
+
+	list, err := g.SyncerConfig.LocalClient.Resource(subv1.EndpointGVR).Namespace(g.Spec.Namespace).List(ctx,
+		metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing Endpoints")
+	}
+
+	for i := range list.Items {
+		endpoint := &subv1.Endpoint{}
+
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.Items[i].Object, endpoint)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error converting Endpoint %q", list.Items[i].GetName())
+		}
+
+		if endpoint.Spec.ClusterID != g.Spec.ClusterID || endpoint.Spec.Hostname != hostname {
+			continue
+		}
+
+		port, err := endpoint.Spec.GetBackendPort(subv1.BFDPortConfig, 0)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing the BFD port of Endpoint %q", endpoint.Name)
+		}
+
+		if port == 0 {
+			return nil, nil
+		}
+
+		return &net.UDPAddr{IP: net.ParseIP(endpoint.Spec.PrivateIP), Port: int(port)}, nil
+	}
+
+	return nil, nil
+}
+
+func (g *gatewayType) setBFDPeer(peer *net.UDPAddr, holderIdentity string) {
+	g.bfdMutex.Lock()
+	defer g.bfdMutex.Unlock()
+
+	if g.bfdPeer != nil && (peer == nil || g.bfdPeer.String() != peer.String()) {
+		g.bfdServer.RemovePeer(g.bfdPeer)
+		g.bfdPeer = nil
+	}
+
+	if peer == nil || g.bfdPeer != nil {
+		return
+	}
+
+	g.bfdPeer = peer
+	g.bfdServer.AddPeer(peer, func(detectionTime time.Duration) {
+		g.onLeaderDown(holderIdentity, detectionTime)
+	})
+}
+
+// onLeaderDown takes over the lease from the failed leader, provided it still holds it. The leader elector running on
+// this gateway then observes that it holds the lease and acquires leadership on its next retry.
+func (g *gatewayType) onLeaderDown(holderIdentity string, detectionTime time.Duration) {
+	failedHostname := strings.TrimSuffix(holderIdentity, leaderIdentitySuffix)
+
+	logger.Warningf("BFD detected failure of the active gateway %q after %v", failedHostname, detectionTime)
+
+	recordFailoverDetection(g.hostName, failedHostname, detectionTime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), g.RenewDeadline)
+	defer cancel()
+
+	lock, err := g.newLeaderLock()
+	if err != nil {
+		logger.Error(err, "")
+		return
+	}
+
+	record, _, err := lock.Get(ctx)
+	if err != nil {
+		logger.Errorf(err, "Error retrieving the leader election record")
+		return
+	}
+
+	if record.HolderIdentity != holderIdentity {
+		logger.Infof("The lease is now held by %q - not taking it over", record.HolderIdentity)
+		return
+	}
+
+	now := metav1.NewTime(time.Now())
+	record.HolderIdentity = g.leaderIdentity()
+	record.AcquireTime = now
+	record.RenewTime = now
+	record.LeaderTransitions++
+
+	err = lock.Update(ctx, *record)
+	if apierrors.IsConflict(err) {
+		logger.Info("The lease was updated concurrently - not taking it over")
+		return
+	}
+
+	if err != nil {
+		logger.Errorf(err, "Error taking over the lease from %q", holderIdentity)
+		return
+	}
+
+	logger.V(log.DEBUG).Infof("Took over the lease from %q", holderIdentity)
+}
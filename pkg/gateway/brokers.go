@@ -0,0 +1,100 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/resource"
+	"github.com/submariner-io/admiral/pkg/syncer/broker"
+	"github.com/submariner-io/submariner/pkg/controllers/datastoresyncer"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
+)
+
+// brokerSpecification mirrors the settings of the default broker, which are read from the BROKER_K8S_ environment
+// variables. The settings of an additional broker are read from the BROKER_K8S_<NAME>_ environment variables.
+type brokerSpecification struct {
+	APIServer       string
+	APIServerToken  string
+	RemoteNamespace string
+	Insecure        bool `default:"false"`
+	Ca              string
+	Secret          string
+}
+
+// AdditionalBrokerConfigs returns the configuration of the given additional brokers, read from the environment.
+func AdditionalBrokerConfigs(names []string) ([]datastoresyncer.BrokerConfig, error) {
+	configs := make([]datastoresyncer.BrokerConfig, 0, len(names))
+	seen := map[string]bool{datastoresyncer.DefaultBrokerName: true}
+
+	for _, name := range names {
+		if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid broker name %q: %s", name, strings.Join(errs, ", "))
+		}
+
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate broker name %q", name)
+		}
+
+		seen[name] = true
+
+		prefix := "broker_k8s_" + strings.ReplaceAll(name, "-", "_")
+
+		spec := brokerSpecification{}
+		if err := envconfig.Process(prefix, &spec); err != nil {
+			return nil, errors.Wrapf(err, "error processing the env configuration of broker %q", name)
+		}
+
+		if spec.APIServer == "" || spec.RemoteNamespace == "" {
+			return nil, fmt.Errorf("the API server and remote namespace of broker %q must be set", name)
+		}
+
+		restConfig, err := brokerRestConfig(&spec)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error building the REST config of broker %q", name)
+		}
+
+		configs = append(configs, datastoresyncer.BrokerConfig{
+			Name: name,
+			SyncerConfig: broker.SyncerConfig{
+				BrokerRestConfig: restConfig,
+				BrokerNamespace:  spec.RemoteNamespace,
+			},
+		})
+	}
+
+	return configs, nil
+}
+
+func brokerRestConfig(spec *brokerSpecification) (*rest.Config, error) {
+	tlsConfig := &rest.TLSClientConfig{Insecure: spec.Insecure}
+
+	if spec.Secret != "" {
+		return resource.BuildRestConfigFromFiles(spec.APIServer, filepath.Join(broker.SecretPath(spec.Secret), "token"),
+			filepath.Join(broker.SecretPath(spec.Secret), "ca.crt"), tlsConfig), nil
+	}
+
+	//nolint:wrapcheck // Let the caller wrap it
+	return resource.BuildRestConfigFromData(spec.APIServer, spec.APIServerToken, spec.Ca, tlsConfig)
+}
@@ -0,0 +1,74 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner/pkg/controllers/datastoresyncer"
+	"github.com/submariner-io/submariner/pkg/gateway"
+)
+
+var _ = Describe("AdditionalBrokerConfigs", func() {
+	BeforeEach(func() {
+		os.Setenv("BROKER_K8S_TEAM_B_APISERVER", "team-b.example.com:6443")
+		os.Setenv("BROKER_K8S_TEAM_B_APISERVERTOKEN", "token")
+		os.Setenv("BROKER_K8S_TEAM_B_REMOTENAMESPACE", "team-b-broker")
+		os.Setenv("BROKER_K8S_TEAM_B_INSECURE", "true")
+
+		DeferCleanup(func() {
+			os.Unsetenv("BROKER_K8S_TEAM_B_APISERVER")
+			os.Unsetenv("BROKER_K8S_TEAM_B_APISERVERTOKEN")
+			os.Unsetenv("BROKER_K8S_TEAM_B_REMOTENAMESPACE")
+			os.Unsetenv("BROKER_K8S_TEAM_B_INSECURE")
+		})
+	})
+
+	It("should read the broker settings from the environment", func() {
+		configs, err := gateway.AdditionalBrokerConfigs([]string{"team-b"})
+		Expect(err).To(Succeed())
+		Expect(configs).To(HaveLen(1))
+		Expect(configs[0].Name).To(Equal("team-b"))
+		Expect(configs[0].SyncerConfig.BrokerNamespace).To(Equal("team-b-broker"))
+		Expect(configs[0].SyncerConfig.BrokerRestConfig.Host).To(Equal("https://team-b.example.com:6443"))
+		Expect(configs[0].SyncerConfig.BrokerRestConfig.BearerToken).To(Equal("token"))
+	})
+
+	When("a broker's settings are missing", func() {
+		It("should return an error", func() {
+			_, err := gateway.AdditionalBrokerConfigs([]string{"team-c"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("a broker name is invalid or duplicated", func() {
+		It("should return an error", func() {
+			_, err := gateway.AdditionalBrokerConfigs([]string{"Team_B"})
+			Expect(err).To(HaveOccurred())
+
+			_, err = gateway.AdditionalBrokerConfigs([]string{"team-b", "team-b"})
+			Expect(err).To(HaveOccurred())
+
+			_, err = gateway.AdditionalBrokerConfigs([]string{datastoresyncer.DefaultBrokerName})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
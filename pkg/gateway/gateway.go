@@ -20,8 +20,10 @@ package gateway
 
 import (
 	"context"
+	"net"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -32,6 +34,8 @@ import (
 	"github.com/submariner-io/admiral/pkg/syncer/broker"
 	"github.com/submariner-io/admiral/pkg/watcher"
 	subv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/benchmark"
+	"github.com/submariner-io/submariner/pkg/bfd"
 	"github.com/submariner-io/submariner/pkg/cable"
 	"github.com/submariner-io/submariner/pkg/cableengine"
 	"github.com/submariner-io/submariner/pkg/cableengine/healthchecker"
@@ -42,13 +46,18 @@ import (
 	"github.com/submariner-io/submariner/pkg/controllers/tunnel"
 	"github.com/submariner-io/submariner/pkg/endpoint"
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
+	"github.com/submariner-io/submariner/pkg/peering"
+	"github.com/submariner-io/submariner/pkg/pinger"
+	"github.com/submariner-io/submariner/pkg/pmtu"
 	"github.com/submariner-io/submariner/pkg/pod"
+	"github.com/submariner-io/submariner/pkg/port"
 	"github.com/submariner-io/submariner/pkg/types"
 	"github.com/submariner-io/submariner/pkg/versions"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/client-go/tools/record"
@@ -71,12 +80,15 @@ type LeaderElectionConfig struct {
 	LeaseDuration time.Duration
 	RenewDeadline time.Duration
 	RetryPeriod   time.Duration
+	// BFD configures liveness detection between the active gateway and the passive candidates for faster failover.
+	BFD bfd.Config
 }
 
 type Config struct {
 	LeaderElectionConfig
 	Spec                 types.SubmarinerSpecification
 	SyncerConfig         broker.SyncerConfig
+	AdditionalBrokers    []datastoresyncer.BrokerConfig
 	WatcherConfig        watcher.Config
 	SubmarinerClient     submclientset.Interface
 	KubeClient           kubernetes.Interface
@@ -100,6 +112,13 @@ type gatewayType struct {
 	fatalError              chan error
 	leaderComponentsStarted *sync.WaitGroup
 	recorder                record.EventRecorder
+	gatewayRecorder         record.EventRecorder
+	bfdServer               *bfd.Server
+	bfdMutex                sync.Mutex
+	bfdPeer                 *net.UDPAddr
+	pmtuDiscovery           *pmtu.Discovery
+	benchmarkServer         *benchmark.Server
+	peeringPolicy           *peering.Policy
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("Gateway")}
@@ -141,6 +160,16 @@ func New(config *Config) (Interface, error) {
 
 	g.Spec.CableDriver = strings.ToLower(g.Spec.CableDriver)
 
+	if err := cable.SetClusterCableDrivers(g.Spec.ClusterCableDrivers); err != nil {
+		return nil, errors.Wrap(err, "error configuring the per-cluster cable drivers")
+	}
+
+	cable.SetKubeClient(g.KubeClient, g.Spec.Namespace)
+
+	if g.Spec.ActiveActiveGateways && len(g.Spec.GlobalCidr) > 0 {
+		return nil, errors.New("active-active gateways are not supported with globalnet")
+	}
+
 	g.airGapped = os.Getenv("AIR_GAPPED_DEPLOYMENT") == "true"
 	logger.Infof("AIR_GAPPED_DEPLOYMENT is set to %t", g.airGapped)
 
@@ -149,6 +178,57 @@ func New(config *Config) (Interface, error) {
 		return nil, errors.Wrap(err, "error creating local endpoint object")
 	}
 
+	cable.AddDriverPorts(localEndpointSpec)
+	cable.AddDriverSelection(localEndpointSpec)
+
+	if err := cable.ValidateDriverPorts(localEndpointSpec); err != nil {
+		return nil, errors.Wrap(err, "error validating the cable driver ports")
+	}
+
+	if g.BFD.Enabled && !g.Spec.ActiveActiveGateways {
+		g.bfdServer = bfd.NewServer(g.BFD)
+
+		if localEndpointSpec.BackendConfig == nil {
+			localEndpointSpec.BackendConfig = map[string]string{}
+		}
+
+		localEndpointSpec.BackendConfig[subv1.BFDPortConfig] = strconv.Itoa(g.bfdServer.Port())
+	}
+
+	var pathMTU pmtu.Interface
+
+	if g.Spec.PMTUDiscoveryEnabled {
+		g.pmtuDiscovery = pmtu.NewDiscovery(pmtu.Config{
+			Port:            g.Spec.PMTUDiscoveryPort,
+			RefreshInterval: time.Duration(g.Spec.PMTUDiscoveryInterval) * time.Second,
+		})
+		pathMTU = g.pmtuDiscovery
+
+		if localEndpointSpec.BackendConfig == nil {
+			localEndpointSpec.BackendConfig = map[string]string{}
+		}
+
+		localEndpointSpec.BackendConfig[subv1.PMTUPortConfig] = strconv.Itoa(g.pmtuDiscovery.Port())
+	}
+
+	if g.Spec.BenchmarkEnabled {
+		g.benchmarkServer = benchmark.NewServer(g.Spec.BenchmarkPort, g.isRemoteEndpointIP)
+
+		if localEndpointSpec.BackendConfig == nil {
+			localEndpointSpec.BackendConfig = map[string]string{}
+		}
+
+		localEndpointSpec.BackendConfig[subv1.BenchmarkPortConfig] = strconv.Itoa(g.benchmarkServer.Port())
+	}
+
+	if g.Spec.HealthCheckEnabled {
+		if localEndpointSpec.BackendConfig == nil {
+			localEndpointSpec.BackendConfig = map[string]string{}
+		}
+
+		localEndpointSpec.BackendConfig[subv1.HealthCheckProbePortConfig] = strconv.Itoa(g.healthCheckProbePort())
+	}
+
 	g.localEndpoint = endpoint.NewLocal(localEndpointSpec, g.SyncerConfig.LocalClient, g.Spec.Namespace)
 
 	g.cableEngine = g.NewCableEngine(localCluster, g.localEndpoint)
@@ -158,22 +238,32 @@ func New(config *Config) (Interface, error) {
 		return nil, errors.Wrap(err, "error creating the NAT discovery handler")
 	}
 
+	g.peeringPolicy, err = peering.NewPolicy(g.Spec.PeeringAllowedClusters, g.Spec.PeeringDeniedClusters,
+		g.Spec.PeeringClusterSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the peering policy")
+	}
+
 	logger.Info("Creating the datastore syncer")
 
 	g.SyncerConfig.LocalNamespace = g.Spec.Namespace
 
-	g.datastoreSyncer = datastoresyncer.New(&g.SyncerConfig, localCluster, g.localEndpoint)
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(logger.V(log.DEBUG).Infof)
+	g.recorder = eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "submariner-controller"})
+	g.gatewayRecorder = g.newGatewayEventRecorder()
+
+	g.datastoreSyncer = datastoresyncer.New(&g.SyncerConfig, localCluster, g.localEndpoint, g.gatewayRecorder, g.peeringPolicy,
+		g.Spec.TransitRoutingEnabled, g.AdditionalBrokers)
 
-	g.initCableHealthChecker()
+	if err := g.initCableHealthChecker(); err != nil {
+		return nil, err
+	}
 
 	g.cableEngineSyncer = syncer.NewGatewaySyncer(
 		g.cableEngine,
 		g.SubmarinerClient.SubmarinerV1().Gateways(g.Spec.Namespace),
-		versions.Submariner(), g.cableHealthChecker)
-
-	eventBroadcaster := record.NewBroadcaster()
-	eventBroadcaster.StartLogging(logger.V(log.DEBUG).Infof)
-	g.recorder = eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "submariner-controller"})
+		versions.Submariner(), g.cableHealthChecker, g.natDiscovery, pathMTU, g.datastoreSyncer)
 
 	return g, nil
 }
@@ -194,6 +284,20 @@ func (g *gatewayType) Run(ctx context.Context) error {
 		return errors.Wrap(err, "error starting NAT discovery server")
 	}
 
+	if g.pmtuDiscovery != nil {
+		err = g.pmtuDiscovery.Run(ctx.Done())
+		if err != nil {
+			return errors.Wrap(err, "error starting the path MTU discovery responder")
+		}
+	}
+
+	if g.benchmarkServer != nil {
+		err = g.benchmarkServer.Run(ctx.Done())
+		if err != nil {
+			return errors.Wrap(err, "error starting the benchmark server")
+		}
+	}
+
 	g.gatewayPod, err = pod.NewGatewayPod(ctx, g.KubeClient)
 	if err != nil {
 		return errors.Wrap(err, "error creating a handler to update the gateway pod")
@@ -210,9 +314,20 @@ func (g *gatewayType) Run(ctx context.Context) error {
 		g.initPublicIPWatcher()
 	}
 
-	err = g.startLeaderElection(ctx)
-	if err != nil {
-		return errors.Wrap(err, "error starting leader election")
+	if g.Spec.ActiveActiveGateways {
+		g.startActiveActive(ctx)
+	} else {
+		if g.bfdServer != nil {
+			err = g.startBFD(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = g.startLeaderElection(ctx)
+		if err != nil {
+			return errors.Wrap(err, "error starting leader election")
+		}
 	}
 
 	select {
@@ -248,13 +363,9 @@ func (g *gatewayType) startLeaderElection(ctx context.Context) error {
 
 	g.leaderComponentsStarted = &sync.WaitGroup{}
 
-	rl, err := resourcelock.New(resourcelock.LeasesResourceLock, g.Spec.Namespace, LeaderElectionLockName,
-		g.LeaderElectionClient.CoreV1(), g.LeaderElectionClient.CoordinationV1(), resourcelock.ResourceLockConfig{
-			Identity:      g.hostName + "-submariner-gateway",
-			EventRecorder: g.recorder,
-		})
+	rl, err := g.newLeaderLock()
 	if err != nil {
-		return errors.Wrap(err, "error creating leader election resource lock")
+		return err
 	}
 
 	leCtx, cancel := context.WithCancel(ctx)
@@ -264,6 +375,8 @@ func (g *gatewayType) startLeaderElection(ctx context.Context) error {
 		LeaseDuration: g.LeaseDuration,
 		RenewDeadline: g.RenewDeadline,
 		RetryPeriod:   g.RetryPeriod,
+		// With BFD enabled, release the lease on shutdown so a candidate can take over without waiting for it to expire.
+		ReleaseOnCancel: g.bfdServer != nil,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: g.onStartedLeading,
 			OnStoppedLeading: func() {
@@ -276,6 +389,45 @@ func (g *gatewayType) startLeaderElection(ctx context.Context) error {
 	return nil
 }
 
+// newGatewayEventRecorder returns the recorder of the Events on the Gateway resources, eg when remote clusters have
+// overlapping CIDRs, which are published in the gateway's namespace.
+func (g *gatewayType) newGatewayEventRecorder() record.EventRecorder {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(logger.V(log.DEBUG).Infof)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: g.KubeClient.CoreV1().Events(g.Spec.Namespace)})
+
+	return eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "submariner-controller"})
+}
+
+// isRemoteEndpointIP is used by the health check responder and the benchmark server to only answer the traffic of the
+// remote gateways.
+func (g *gatewayType) isRemoteEndpointIP(ip net.IP) bool {
+	return benchmark.IsRemoteEndpointIP(g.cableEngine, ip)
+}
+
+func (g *gatewayType) newLeaderLock() (resourcelock.Interface, error) {
+	rl, err := resourcelock.New(resourcelock.LeasesResourceLock, g.Spec.Namespace, LeaderElectionLockName,
+		g.LeaderElectionClient.CoreV1(), g.LeaderElectionClient.CoordinationV1(), resourcelock.ResourceLockConfig{
+			Identity:      g.leaderIdentity(),
+			EventRecorder: g.recorder,
+		})
+
+	return rl, errors.Wrap(err, "error creating leader election resource lock")
+}
+
+func (g *gatewayType) leaderIdentity() string {
+	return g.hostName + leaderIdentitySuffix
+}
+
+// startActiveActive starts the components which otherwise only run on the elected leader. In active-active mode every
+// gateway publishes its own Endpoint and carries traffic so there's no leader election.
+func (g *gatewayType) startActiveActive(ctx context.Context) {
+	logger.Info("Running in active-active mode")
+
+	g.leaderComponentsStarted = &sync.WaitGroup{}
+	g.onStartedLeading(ctx)
+}
+
 func (g *gatewayType) onStartedLeading(ctx context.Context) {
 	logger.Info("Leadership acquired - starting controllers")
 
@@ -301,7 +453,7 @@ func (g *gatewayType) onStartedLeading(ctx context.Context) {
 
 	g.runAsync(g.leaderComponentsStarted, func() {
 		watcherConfig := g.WatcherConfig
-		if err := tunnel.StartController(g.cableEngine, g.Spec.Namespace, &watcherConfig, ctx.Done()); err != nil {
+		if err := tunnel.StartController(g.cableEngine, g.Spec.Namespace, g.peeringPolicy, &watcherConfig, ctx.Done()); err != nil {
 			g.fatalError <- errors.Wrap(err, "error running the tunnel controller")
 		}
 	})
@@ -314,11 +466,48 @@ func (g *gatewayType) onStartedLeading(ctx context.Context) {
 		})
 	}
 
+	if g.benchmarkServer != nil {
+		g.runAsync(g.leaderComponentsStarted, func() {
+			watcherConfig := g.WatcherConfig
+			if err := benchmark.StartController(&benchmark.ControllerConfig{
+				Engine:        g.cableEngine,
+				DynamicClient: g.SyncerConfig.LocalClient,
+				WatcherConfig: &watcherConfig,
+				Namespace:     g.Spec.Namespace,
+				GatewayName:   resource.EnsureValidName(g.localEndpoint.Spec().Hostname),
+			}, ctx.Done()); err != nil {
+				logger.Errorf(err, "Error starting the benchmark controller")
+			}
+		})
+	}
+
+	if g.cableHealthChecker != nil && g.Spec.HealthCheckRemediationThreshold > 0 {
+		go g.newCableRemediator().Run(ctx.Done())
+	}
+
 	if g.publicIPWatcher != nil {
 		go g.publicIPWatcher.Run(ctx.Done())
 	}
 }
 
+func (g *gatewayType) newCableRemediator() *cableengine.Remediator {
+	return cableengine.NewRemediator(&cableengine.RemediatorConfig{
+		Engine:        g.cableEngine,
+		HealthChecker: g.cableHealthChecker,
+		EventRecorder: g.gatewayRecorder,
+		EventObjectRef: &corev1.ObjectReference{
+			APIVersion: subv1.SchemeGroupVersion.String(),
+			Kind:       "Gateway",
+			Namespace:  g.Spec.Namespace,
+			Name:       resource.EnsureValidName(g.localEndpoint.Spec().Hostname),
+		},
+		FailureThreshold: g.Spec.HealthCheckRemediationThreshold,
+		Interval:         time.Duration(g.Spec.HealthCheckInterval) * time.Second,
+		MaxBackoff:       time.Duration(g.Spec.HealthCheckRemediationMaxBackoff) * time.Second,
+		RediscoverNAT:    g.Spec.HealthCheckRemediationNATDiscovery,
+	})
+}
+
 func (g *gatewayType) onStoppedLeading(ctx context.Context) {
 	logger.Info("Leadership lost")
 
@@ -384,25 +573,52 @@ func (g *gatewayType) initPublicIPWatcher() {
 	g.publicIPWatcher = endpoint.NewPublicIPWatcher(publicIPConfig)
 }
 
-func (g *gatewayType) initCableHealthChecker() {
-	var err error
+func (g *gatewayType) healthCheckProbePort() int {
+	if g.Spec.HealthCheckProbePort != 0 {
+		return g.Spec.HealthCheckProbePort
+	}
 
+	return port.HealthCheckProbe
+}
+
+func (g *gatewayType) initCableHealthChecker() error {
 	if !g.Spec.HealthCheckEnabled {
 		logger.Info("The CableEngine HealthChecker is disabled")
-	} else {
-		watcherConfig := g.WatcherConfig
+		return nil
+	}
 
-		g.cableHealthChecker, err = healthchecker.New(&healthchecker.Config{
-			WatcherConfig:      &watcherConfig,
-			EndpointNamespace:  g.Spec.Namespace,
-			ClusterID:          g.Spec.ClusterID,
-			PingInterval:       g.Spec.HealthCheckInterval,
-			MaxPacketLossCount: g.Spec.HealthCheckMaxPacketLossCount,
-		})
+	probeType, err := pinger.ParseProbeType(g.Spec.HealthCheckProbe)
+	if err != nil {
+		return errors.Wrap(err, "error configuring the health check probe")
+	}
+
+	clusterProbeTypes := make(map[string]pinger.ProbeType, len(g.Spec.ClusterHealthCheckProbes))
+
+	for clusterID, s := range g.Spec.ClusterHealthCheckProbes {
+		clusterProbeTypes[clusterID], err = pinger.ParseProbeType(s)
 		if err != nil {
-			logger.Errorf(err, "Error creating healthChecker")
+			return errors.Wrapf(err, "error configuring the health check probe for cluster %q", clusterID)
 		}
 	}
+
+	watcherConfig := g.WatcherConfig
+
+	g.cableHealthChecker, err = healthchecker.New(&healthchecker.Config{
+		WatcherConfig:        &watcherConfig,
+		EndpointNamespace:    g.Spec.Namespace,
+		ClusterID:            g.Spec.ClusterID,
+		PingInterval:         g.Spec.HealthCheckInterval,
+		MaxPacketLossCount:   g.Spec.HealthCheckMaxPacketLossCount,
+		ProbeType:            probeType,
+		ClusterProbeTypes:    clusterProbeTypes,
+		ProbePort:            g.healthCheckProbePort(),
+		IsAllowedProbeSource: g.isRemoteEndpointIP,
+	})
+	if err != nil {
+		logger.Errorf(err, "Error creating healthChecker")
+	}
+
+	return nil
 }
 
 func (g *gatewayType) uninstall(ctx context.Context) error {
@@ -21,8 +21,11 @@ package gateway_test
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"reflect"
+	"strconv"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -37,14 +40,18 @@ import (
 	testutil "github.com/submariner-io/admiral/pkg/test"
 	"github.com/submariner-io/admiral/pkg/watcher"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/bfd"
 	fakecable "github.com/submariner-io/submariner/pkg/cable/fake"
+	"github.com/submariner-io/submariner/pkg/cable/vxlan"
 	"github.com/submariner-io/submariner/pkg/cableengine"
 	enginefake "github.com/submariner-io/submariner/pkg/cableengine/fake"
 	submfake "github.com/submariner-io/submariner/pkg/client/clientset/versioned/fake"
+	"github.com/submariner-io/submariner/pkg/controllers/datastoresyncer"
 	submendpoint "github.com/submariner-io/submariner/pkg/endpoint"
 	"github.com/submariner-io/submariner/pkg/gateway"
 	"github.com/submariner-io/submariner/pkg/natdiscovery"
 	"github.com/submariner-io/submariner/pkg/types"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -55,6 +62,7 @@ import (
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 )
 
 const publicIP = "1.2.3.4"
@@ -79,6 +87,86 @@ var _ = Describe("Run", func() {
 		t.cableEngine.VerifyInstallCable(&endpoint.Spec)
 	})
 
+	When("a cable driver is configured for a remote cluster", func() {
+		BeforeEach(func() {
+			t.config.Spec.ClusterCableDrivers = map[string]string{"west": vxlan.CableDriverName}
+		})
+
+		It("should publish the driver's own UDP port in the local Endpoint", func() {
+			Eventually(func() map[string]string {
+				l, err := t.endpoints.Namespace(t.config.Spec.Namespace).List(context.Background(), metav1.ListOptions{})
+				Expect(err).To(Succeed())
+
+				for i := range l.Items {
+					if endpoint := toEndpoint(&l.Items[i]); endpoint.Spec.ClusterID == t.config.Spec.ClusterID {
+						return endpoint.Spec.BackendConfig
+					}
+				}
+
+				return nil
+			}, 3).Should(HaveKeyWithValue(submarinerv1.CableDriverUDPPortConfig(vxlan.CableDriverName),
+				strconv.Itoa(vxlan.DefaultPort)))
+		})
+	})
+
+	When("a health check probe port is configured", func() {
+		BeforeEach(func() {
+			t.config.Spec.HealthCheckProbePort = 5801
+		})
+
+		It("should publish it in the local Endpoint", func() {
+			Eventually(func() map[string]string {
+				l, err := t.endpoints.Namespace(t.config.Spec.Namespace).List(context.Background(), metav1.ListOptions{})
+				Expect(err).To(Succeed())
+
+				for i := range l.Items {
+					if endpoint := toEndpoint(&l.Items[i]); endpoint.Spec.ClusterID == t.config.Spec.ClusterID {
+						return endpoint.Spec.BackendConfig
+					}
+				}
+
+				return nil
+			}, 3).Should(HaveKeyWithValue(submarinerv1.HealthCheckProbePortConfig, "5801"))
+		})
+	})
+
+	When("a remote cluster's CIDRs overlap the local cluster's", func() {
+		It("should create the CIDR overlap Event in the gateway's namespace", func() {
+			remoteEndpoint := t.newRemoteEndpoint()
+			remoteEndpoint.Spec.Subnets = []string{"224.0.1.0/24"}
+			t.createEndpoint(t.config.SyncerConfig.BrokerNamespace, remoteEndpoint)
+
+			t.awaitEvent(datastoresyncer.ReasonCIDROverlap)
+		})
+	})
+
+	When("cable remediation is enabled and a cable fails its health checks", func() {
+		BeforeEach(func() {
+			t.config.Spec.HealthCheckProbe = "tcp"
+			t.config.Spec.HealthCheckInterval = 1
+			t.config.Spec.HealthCheckMaxPacketLossCount = 1
+			t.config.Spec.HealthCheckRemediationThreshold = 1
+		})
+
+		It("should reconnect it and create the remediation Event in the gateway's namespace", func() {
+			remoteEndpoint := t.newRemoteEndpoint()
+			remoteEndpoint.Spec.HealthCheckIP = "127.0.0.1"
+			remoteEndpoint.Spec.BackendConfig = map[string]string{
+				submarinerv1.HealthCheckProbePortConfig: strconv.Itoa(closedTCPPort()),
+			}
+
+			endpoint := t.awaitRemoteEndpointSyncedLocal(t.createEndpoint(t.config.SyncerConfig.BrokerNamespace, remoteEndpoint))
+			t.cableEngine.VerifyInstallCable(&endpoint.Spec)
+
+			t.cableEngine.Lock()
+			t.cableEngine.Connections = []submarinerv1.Connection{{Endpoint: endpoint.Spec}}
+			t.cableEngine.Unlock()
+
+			t.cableEngine.VerifyReconnectCable(endpoint.Spec.CableName)
+			t.awaitEvent(cableengine.ReasonCableReconnecting)
+		})
+	})
+
 	When("starting the Cable Engine fails", func() {
 		BeforeEach(func() {
 			t.expectedRunErr = errors.New("mock Cable Engine Start error")
@@ -98,8 +186,9 @@ var _ = Describe("Run", func() {
 					Endpoint: submarinerv1.EndpointSpec{
 						CableName: "submariner-cable-north-5-5-5-5",
 					},
-					UsingIP:  "5.6.7.8",
-					UsingNAT: true,
+					UsingIP:     "5.6.7.8",
+					UsingNAT:    true,
+					CableDriver: fakecable.DriverName,
 				},
 			}
 
@@ -193,6 +282,77 @@ var _ = Describe("Run", func() {
 		})
 	})
 
+	When("BFD is enabled and another gateway holds the lease", func() {
+		var stopPeer func()
+
+		BeforeEach(func() {
+			t.config.LeaseDuration = time.Minute
+			t.config.RenewDeadline = time.Millisecond * 200
+			t.config.RetryPeriod = time.Millisecond * 20
+			t.config.BFD = bfd.Config{
+				Enabled:    true,
+				Port:       freeUDPPort(),
+				TxInterval: time.Millisecond * 50,
+			}
+
+			var peerPort int
+
+			peerPort, stopPeer = startFakeBFDPeer()
+			DeferCleanup(stopPeer)
+
+			now := metav1.NewMicroTime(time.Now())
+
+			_, err := t.kubeClient.CoordinationV1().Leases(t.config.Spec.Namespace).Create(context.Background(),
+				&coordinationv1.Lease{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: gateway.LeaderElectionLockName,
+					},
+					Spec: coordinationv1.LeaseSpec{
+						HolderIdentity:       ptr.To("other-submariner-gateway"),
+						LeaseDurationSeconds: ptr.To(int32(60)),
+						AcquireTime:          &now,
+						RenewTime:            &now,
+					},
+				}, metav1.CreateOptions{})
+			Expect(err).To(Succeed())
+
+			t.createEndpoint(t.config.Spec.Namespace, &submarinerv1.Endpoint{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "other",
+				},
+				Spec: submarinerv1.EndpointSpec{
+					ClusterID: t.config.Spec.ClusterID,
+					CableName: "submariner-cable-east-127-0-0-1",
+					Hostname:  "other",
+					PrivateIP: "127.0.0.1",
+					BackendConfig: map[string]string{
+						submarinerv1.BFDPortConfig: strconv.Itoa(peerPort),
+					},
+				},
+			})
+		})
+
+		It("should take over the lease when the active gateway stops responding", func() {
+			hostName, err := os.Hostname()
+			Expect(err).To(Succeed())
+
+			Consistently(func() string {
+				return t.leaderElection.GetRecord().HolderIdentity
+			}, 300*time.Millisecond).Should(Equal("other-submariner-gateway"))
+
+			stopPeer()
+
+			Eventually(func() string {
+				return t.leaderElection.GetRecord().HolderIdentity
+			}, 3).Should(Equal(hostName + "-submariner-gateway"))
+
+			t.awaitHAStatus(submarinerv1.HAStatusActive)
+
+			Expect(t.cableEngine.LocalEndPoint.Spec.BackendConfig).To(HaveKeyWithValue(submarinerv1.BFDPortConfig,
+				strconv.Itoa(t.config.BFD.Port)))
+		})
+	})
+
 	Context("on uninstall", func() {
 		BeforeEach(func() {
 			t.config.Spec.Uninstall = true
@@ -413,7 +573,7 @@ func (t *testDriver) newRemoteEndpoint() *submarinerv1.Endpoint {
 			Subnets:   []string{"169.254.3.0/24"},
 			PrivateIP: "11.1.2.3",
 			PublicIP:  "ipv4:12.1.2.3",
-			Backend:   "libreswan",
+			Backend:   fakecable.DriverName,
 		},
 	}
 
@@ -438,6 +598,85 @@ func (t *testDriver) ensureNoRemoteEndpointSyncedLocal(endpoint *submarinerv1.En
 	testutil.EnsureNoResource(resource.ForDynamic(t.endpoints.Namespace(t.config.Spec.Namespace)), endpoint.Name)
 }
 
+// closedTCPPort returns a local TCP port on which nothing listens, so the health check probes to it fail.
+func closedTCPPort() int {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	Expect(err).To(Succeed())
+
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// startFakeBFDPeer starts a minimal BFD peer which responds to control packets until the returned function is called.
+func startFakeBFDPeer() (int, func()) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	Expect(err).To(Succeed())
+
+	go func() {
+		buf := make([]byte, 100)
+
+		for {
+			n, from, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+
+			received, err := bfd.UnmarshalControlPacket(buf[:n])
+			if err != nil {
+				continue
+			}
+
+			state := bfd.StateUp
+			if received.State == bfd.StateDown {
+				state = bfd.StateInit
+			}
+
+			reply := &bfd.ControlPacket{
+				State:                 state,
+				DetectMultiplier:      3,
+				MyDiscriminator:       1,
+				YourDiscriminator:     received.MyDiscriminator,
+				DesiredMinTxInterval:  received.DesiredMinTxInterval,
+				RequiredMinRxInterval: received.RequiredMinRxInterval,
+			}
+
+			_, _ = conn.WriteToUDP(reply.Marshal(), from)
+		}
+	}()
+
+	var once sync.Once
+
+	return conn.LocalAddr().(*net.UDPAddr).Port, func() {
+		once.Do(func() {
+			conn.Close()
+		})
+	}
+}
+
+func freeUDPPort() int {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	Expect(err).To(Succeed())
+
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func (t *testDriver) awaitEvent(reason string) {
+	Eventually(func() []string {
+		l, err := t.kubeClient.CoreV1().Events(t.config.Spec.Namespace).List(context.Background(), metav1.ListOptions{})
+		Expect(err).To(Succeed())
+
+		reasons := []string{}
+		for i := range l.Items {
+			reasons = append(reasons, l.Items[i].Reason)
+		}
+
+		return reasons
+	}, 5).Should(ContainElement(reason))
+}
+
 func (t *testDriver) awaitHAStatus(status submarinerv1.HAStatus) {
 	Eventually(func() string {
 		pod, err := t.config.KubeClient.CoreV1().Pods(t.config.Spec.Namespace).Get(context.Background(), t.localPodName, metav1.GetOptions{})
@@ -496,6 +735,10 @@ func (n *fakeNATDiscovery) AddEndpoint(ep *submarinerv1.Endpoint) {
 func (n *fakeNATDiscovery) RemoveEndpoint(_ string) {
 }
 
+func (n *fakeNATDiscovery) SymmetricNATDetected() bool {
+	return false
+}
+
 func (n *fakeNATDiscovery) GetReadyChannel() chan *natdiscovery.NATEndpointInfo {
 	n.readyChannel = make(chan *natdiscovery.NATEndpointInfo, 100)
 	return n.readyChannel
@@ -0,0 +1,52 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	localHostnameLabel  = "local_hostname"
+	failedHostnameLabel = "failed_hostname"
+)
+
+var failoverDetectionGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "submariner_gateway_failover_detection_seconds",
+		Help: "Time taken to detect the failure of the active gateway via BFD (by local and failed gateway)",
+	},
+	[]string{
+		localHostnameLabel,
+		failedHostnameLabel,
+	},
+)
+
+func init() {
+	prometheus.MustRegister(failoverDetectionGauge)
+}
+
+func recordFailoverDetection(localHostname, failedHostname string, detectionTime time.Duration) {
+	failoverDetectionGauge.With(prometheus.Labels{
+		localHostnameLabel:  localHostname,
+		failedHostnameLabel: failedHostname,
+	}).Set(detectionTime.Seconds())
+}
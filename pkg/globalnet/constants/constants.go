@@ -36,4 +36,9 @@ const (
 	NATTable = "nat"
 
 	SmGlobalIP = "submariner.io/globalIp"
+
+	// Annotations on an exported Service to request a specific global IP and to retain its global IP after the Service is
+	// deleted for the given duration (eg "24h").
+	RequestedGlobalIP       = "submariner.io/requested-global-ip"
+	GlobalIPRetentionPeriod = "submariner.io/global-ip-retention"
 )
@@ -27,10 +27,10 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/federate"
-	"github.com/submariner-io/admiral/pkg/ipam"
 	"github.com/submariner-io/admiral/pkg/util"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	pfiface "github.com/submariner-io/submariner/pkg/globalnet/controllers/packetfilter"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -60,7 +60,7 @@ func newBaseSyncerController() *baseSyncerController {
 	}
 }
 
-func newBaseIPAllocationController(pool *ipam.IPPool, pfIface pfiface.Interface) *baseIPAllocationController {
+func newBaseIPAllocationController(pool *ippool.MultiPool, pfIface pfiface.Interface) *baseIPAllocationController {
 	return &baseIPAllocationController{
 		baseSyncerController: newBaseSyncerController(),
 		pool:                 pool,
@@ -173,13 +173,8 @@ func (c *baseIPAllocationController) flushRulesAndReleaseIPs(key string, numRequ
 
 	logger.Infof("Releasing previously allocated IPs %v for %q", allocatedIPs, key)
 
-	err := flushRules(allocatedIPs)
-	if err != nil {
-		logger.Errorf(err, "Error flushing the IP table rules for %q", key)
-
-		if shouldRequeue(numRequeues) {
-			return true
-		}
+	if c.flushRules(key, numRequeues, flushRules, allocatedIPs) {
+		return true
 	}
 
 	if err := c.pool.Release(allocatedIPs...); err != nil {
@@ -189,6 +184,19 @@ func (c *baseIPAllocationController) flushRulesAndReleaseIPs(key string, numRequ
 	return false
 }
 
+func (c *baseIPAllocationController) flushRules(key string, numRequeues int, flushRules func(allocatedIPs []string) error,
+	allocatedIPs []string,
+) bool {
+	err := flushRules(allocatedIPs)
+	if err != nil {
+		logger.Errorf(err, "Error flushing the IP table rules for %q", key)
+
+		return shouldRequeue(numRequeues)
+	}
+
+	return false
+}
+
 func shouldRequeue(numRequeues int) bool {
 	return numRequeues < maxRequeues
 }
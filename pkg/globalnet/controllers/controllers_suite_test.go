@@ -31,7 +31,6 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/format"
 	fakeDynClient "github.com/submariner-io/admiral/pkg/fake"
-	"github.com/submariner-io/admiral/pkg/ipam"
 	"github.com/submariner-io/admiral/pkg/log/kzerolog"
 	"github.com/submariner-io/admiral/pkg/resource"
 	"github.com/submariner-io/admiral/pkg/syncer/test"
@@ -40,6 +39,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/cni"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
 	fakePF "github.com/submariner-io/submariner/pkg/packetfilter/fake"
 	routeAgent "github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
@@ -105,17 +105,19 @@ type testDriverBase struct {
 	dynClient              *dynamicfake.FakeDynamicClient
 	scheme                 *runtime.Scheme
 	pFilter                *fakePF.PacketFilter
-	pool                   *ipam.IPPool
+	pool                   *ippool.MultiPool
 	localSubnets           []string
 	globalCIDR             string
 	hostName               string
 	globalEgressIPs        dynamic.ResourceInterface
 	clusterGlobalEgressIPs dynamic.ResourceInterface
+	multiNamespaceEIPs     dynamic.ResourceInterface
 	globalIngressIPs       dynamic.ResourceInterface
 	services               dynamic.ResourceInterface
 	serviceExports         dynamic.ResourceInterface
 	endpoints              dynamic.ResourceInterface
 	pods                   dynamic.NamespaceableResourceInterface
+	namespaces             dynamic.ResourceInterface
 	gateways               dynamic.ResourceInterface
 	watches                *fakeDynClient.WatchReactor
 }
@@ -124,7 +126,8 @@ func newTestDriverBase() *testDriverBase {
 	t := &testDriverBase{
 		restMapper: test.GetRESTMapperFor(&submarinerv1.Endpoint{}, &corev1.Service{}, &corev1.Pod{}, &corev1.Endpoints{},
 			&submarinerv1.GlobalEgressIP{}, &submarinerv1.ClusterGlobalEgressIP{}, &submarinerv1.GlobalIngressIP{},
-			&submarinerv1.Gateway{}, &mcsv1a1.ServiceExport{}),
+			&submarinerv1.Gateway{}, &mcsv1a1.ServiceExport{}, &submarinerv1.MultiNamespaceGlobalEgressIP{}, &corev1.Namespace{},
+			&submarinerv1.Cluster{}),
 		scheme:       runtime.NewScheme(),
 		pFilter:      fakePF.New(),
 		globalCIDR:   globalCIDR,
@@ -145,6 +148,11 @@ func newTestDriverBase() *testDriverBase {
 
 	t.clusterGlobalEgressIPs = t.dynClient.Resource(*test.GetGroupVersionResourceFor(t.restMapper, &submarinerv1.ClusterGlobalEgressIP{}))
 
+	t.multiNamespaceEIPs = t.dynClient.Resource(*test.GetGroupVersionResourceFor(t.restMapper,
+		&submarinerv1.MultiNamespaceGlobalEgressIP{}))
+
+	t.namespaces = t.dynClient.Resource(*test.GetGroupVersionResourceFor(t.restMapper, &corev1.Namespace{}))
+
 	t.pods = t.dynClient.Resource(*test.GetGroupVersionResourceFor(t.restMapper, &corev1.Pod{}))
 
 	t.endpoints = t.dynClient.Resource(*test.GetGroupVersionResourceFor(t.restMapper, &corev1.Endpoints{})).Namespace(namespace)
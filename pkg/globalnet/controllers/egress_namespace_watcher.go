@@ -0,0 +1,180 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/admiral/pkg/util"
+	"github.com/submariner-io/admiral/pkg/watcher"
+	"github.com/submariner-io/submariner/pkg/globalnet/controllers/packetfilter"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func startEgressNamespaceWatcher(name string, namedSet packetfilter.NamedSet, config *watcher.Config,
+	namespaceSelector metav1.LabelSelector, podSelector *metav1.LabelSelector,
+) (*egressNamespaceWatcher, error) {
+	_, gvr, err := util.ToUnstructuredResource(&corev1.Pod{}, config.RestMapper)
+	if err != nil {
+		return nil, errors.Wrap(err, "error converting resource")
+	}
+
+	sel, err := metav1.LabelSelectorAsSelector(&namespaceSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting namespace label selector")
+	}
+
+	nw := &egressNamespaceWatcher{
+		name:              name,
+		selector:          sel,
+		stopCh:            make(chan struct{}),
+		namedSet:          namedSet,
+		namespaceSelector: namespaceSelector,
+		podSelector:       podSelector,
+		watcherConfig:     config,
+		pods:              config.Client.Resource(*gvr),
+		podWatchers:       map[string]*egressPodWatcher{},
+	}
+
+	// The namespace selector is evaluated on every event rather than by the server so relabeled namespaces are picked up
+	// or dropped.
+	w, err := watcher.New(&watcher.Config{
+		RestMapper: config.RestMapper,
+		Client:     config.Client,
+		Scheme:     config.Scheme,
+		ResourceConfigs: []watcher.ResourceConfig{
+			{
+				Name:         fmt.Sprintf("Namespace watcher %s", name),
+				ResourceType: &corev1.Namespace{},
+				Handler: watcher.EventHandlerFuncs{
+					OnCreateFunc: nw.onCreateOrUpdate,
+					OnUpdateFunc: nw.onCreateOrUpdate,
+					OnDeleteFunc: nw.onDelete,
+				},
+				ResourcesEquivalent: areLabelsEquivalent,
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating resource watcher")
+	}
+
+	err = w.Start(nw.stopCh)
+	if err != nil {
+		return nil, errors.Wrap(err, "error starting resource watcher")
+	}
+
+	return nw, nil
+}
+
+func (w *egressNamespaceWatcher) stop() {
+	close(w.stopCh)
+
+	w.Lock()
+	defer w.Unlock()
+
+	for _, podWatcher := range w.podWatchers {
+		close(podWatcher.stopCh)
+	}
+
+	w.podWatchers = map[string]*egressPodWatcher{}
+}
+
+func areLabelsEquivalent(oldObj, newObj *unstructured.Unstructured) bool {
+	return equality.Semantic.DeepEqual(oldObj.GetLabels(), newObj.GetLabels())
+}
+
+func (w *egressNamespaceWatcher) onCreateOrUpdate(obj runtime.Object, numRequeues int) bool {
+	namespace := obj.(*corev1.Namespace)
+
+	if !w.selector.Matches(labels.Set(namespace.Labels)) {
+		return w.onDelete(obj, numRequeues)
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	if _, found := w.podWatchers[namespace.Name]; found {
+		return false
+	}
+
+	logger.V(log.DEBUG).Infof("Namespace %q selected by %q", namespace.Name, w.name)
+
+	podWatcher, err := startEgressPodWatcher(w.name+"/"+namespace.Name, namespace.Name, w.namedSet, w.watcherConfig, w.podSelector)
+	if err != nil {
+		logger.Errorf(err, "Error starting pod watcher for namespace %q of %q", namespace.Name, w.name)
+		return true
+	}
+
+	w.podWatchers[namespace.Name] = podWatcher
+
+	return false
+}
+
+// onDelete is invoked when a namespace is deleted or no longer matches the namespace selector. In the latter case its pods
+// still exist so their IPs are removed from the IP set.
+func (w *egressNamespaceWatcher) onDelete(obj runtime.Object, _ int) bool {
+	namespace := obj.(*corev1.Namespace)
+
+	w.Lock()
+	defer w.Unlock()
+
+	podWatcher, found := w.podWatchers[namespace.Name]
+	if !found {
+		return false
+	}
+
+	logger.V(log.DEBUG).Infof("Namespace %q no longer selected by %q", namespace.Name, w.name)
+
+	close(podWatcher.stopCh)
+	delete(w.podWatchers, namespace.Name)
+
+	sel, err := metav1.LabelSelectorAsSelector(w.podSelector)
+	if err != nil {
+		logger.Errorf(err, "Error getting pod label selector for %q", w.name)
+		return false
+	}
+
+	list, err := w.pods.Namespace(namespace.Name).List(context.TODO(), metav1.ListOptions{LabelSelector: sel.String()})
+	if err != nil {
+		logger.Errorf(err, "Error listing the pods in namespace %q", namespace.Name)
+		return false
+	}
+
+	for i := range list.Items {
+		podIP, _, _ := unstructured.NestedString(list.Items[i].Object, "status", "podIP")
+		if podIP == "" {
+			continue
+		}
+
+		if err := w.namedSet.DelEntry(podIP); err != nil {
+			logger.Errorf(err, "Error deleting pod IP %q from IP set %q", podIP, w.namedSet.Name())
+		}
+	}
+
+	return false
+}
@@ -23,12 +23,12 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/federate"
-	"github.com/submariner-io/admiral/pkg/ipam"
 	"github.com/submariner-io/admiral/pkg/syncer"
 	admUtil "github.com/submariner-io/admiral/pkg/util"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers/packetfilter"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -36,8 +36,8 @@ import (
 	"k8s.io/client-go/tools/cache"
 )
 
-func NewGatewayController(config *syncer.ResourceSyncerConfig, informer cache.SharedInformer, pool *ipam.IPPool, hostName,
-	namespace, cniIP string,
+func NewGatewayController(config *syncer.ResourceSyncerConfig, informer cache.SharedInformer, pool *ippool.MultiPool, hostName,
+	namespace, cniIP string, ports []int,
 ) (Interface, error) {
 	// We'll panic if config is nil, this is intentional
 	var err error
@@ -53,6 +53,7 @@ func NewGatewayController(config *syncer.ResourceSyncerConfig, informer cache.Sh
 		baseIPAllocationController: newBaseIPAllocationController(pool, pfIface),
 		hostName:                   hostName,
 		cniIP:                      cniIP,
+		ports:                      ports,
 	}
 
 	config = NewGatewayResourceSyncerConfig(config, namespace)
@@ -154,7 +155,7 @@ func (n *gatewayController) allocateIP(gateway *submarinerv1.Gateway) (runtime.O
 
 	logger.Infof("Adding ingress rules for Gateway %q with global IP %s, CNI IP %s", gateway.Name, globalIP, n.cniIP)
 
-	if err := n.pfIface.AddIngressRulesForHealthCheck(n.cniIP, globalIP); err != nil {
+	if err := n.pfIface.AddIngressRulesForGateway(n.cniIP, globalIP, n.ports); err != nil {
 		logger.Errorf(err, "Error programming ingress rules for Gateway %q", gateway.Name)
 
 		_ = n.pool.Release(globalIP)
@@ -179,7 +180,7 @@ func (n *gatewayController) reserveAllocatedIP(federator federate.Federator, obj
 
 	err := n.pool.Reserve(existingGlobalIP)
 	if err == nil && obj.GetName() == n.hostName {
-		err = n.pfIface.AddIngressRulesForHealthCheck(n.cniIP, existingGlobalIP)
+		err = n.pfIface.AddIngressRulesForGateway(n.cniIP, existingGlobalIP, n.ports)
 		if err != nil {
 			_ = n.pool.Release(existingGlobalIP)
 		}
@@ -195,7 +196,7 @@ func (n *gatewayController) reserveAllocatedIP(federator federate.Federator, obj
 			return nil
 		}
 
-		if err := n.pfIface.RemoveIngressRulesForHealthCheck(n.cniIP, existingGlobalIP); err != nil {
+		if err := n.pfIface.RemoveIngressRulesForGateway(n.cniIP, existingGlobalIP, n.ports); err != nil {
 			logger.Errorf(err, "Error deleting rules for Gateway %q", n.hostName)
 		}
 
@@ -20,20 +20,26 @@ package controllers_test
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/submariner-io/admiral/pkg/ipam"
 	"github.com/submariner-io/admiral/pkg/syncer"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
 	"github.com/submariner-io/submariner/pkg/globalnet/metrics"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	healthCheckProbePort = 4801
+	benchmarkPort        = 4520
+)
+
 var _ = Describe("Gateway controller", func() {
 	t := newGatewayControllerTestDriver()
 
@@ -145,7 +151,7 @@ func newGatewayControllerTestDriver() *gatewayControllerTestDriver {
 
 		var err error
 
-		t.pool, err = ipam.NewIPPool(t.globalCIDR, metrics.GlobalnetMetricsReporter)
+		t.pool, err = ippool.NewMultiPool([]string{t.globalCIDR}, metrics.GlobalnetMetricsReporter)
 		Expect(err).To(Succeed())
 
 		t.localCIDRs = []string{localCIDR}
@@ -181,7 +187,8 @@ func (t *gatewayControllerTestDriver) start() {
 		close(stopCh)
 	})
 
-	t.controller, err = controllers.NewGatewayController(syncerConfig, informer, t.pool, t.hostName, namespace, cniInterfaceIP)
+	t.controller, err = controllers.NewGatewayController(syncerConfig, informer, t.pool, t.hostName, namespace, cniInterfaceIP,
+		[]int{healthCheckProbePort, benchmarkPort})
 	Expect(err).To(Succeed())
 
 	t.verifyIPsReservedInPool(t.expectReservedIPs...)
@@ -194,8 +201,17 @@ func (t *gatewayControllerTestDriver) start() {
 }
 
 func (t *gatewayControllerTestDriver) awaitPacketFilterRules(globalIP string) {
+	for _, port := range []int{healthCheckProbePort, benchmarkPort} {
+		for _, proto := range []packetfilter.RuleProto{packetfilter.RuleProtoUDP, packetfilter.RuleProtoTCP} {
+			t.pFilter.AwaitRule(packetfilter.TableTypeNAT,
+				constants.SmGlobalnetIngressChain, And(ContainSubstring(globalIP), ContainSubstring(cniInterfaceIP),
+					ContainSubstring(fmt.Sprintf("%q:%d", "Proto", proto)), ContainSubstring(fmt.Sprintf("%q:\"%d\"", "DPort", port))))
+		}
+	}
+
 	t.pFilter.AwaitRule(packetfilter.TableTypeNAT,
-		constants.SmGlobalnetIngressChain, And(ContainSubstring(globalIP), ContainSubstring(cniInterfaceIP)))
+		constants.SmGlobalnetIngressChain, And(ContainSubstring(globalIP), ContainSubstring(cniInterfaceIP),
+			ContainSubstring(fmt.Sprintf("%q:%d", "Proto", packetfilter.RuleProtoICMP))))
 }
 
 func (t *gatewayControllerTestDriver) ensureNoPacketFilterRules(globalIP string) {
@@ -21,12 +21,12 @@ package controllers
 import (
 	"context"
 	"os"
+	"slices"
 	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/federate"
-	"github.com/submariner-io/admiral/pkg/ipam"
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/admiral/pkg/syncer"
 	"github.com/submariner-io/admiral/pkg/syncer/broker"
@@ -37,9 +37,12 @@ import (
 	"github.com/submariner-io/submariner/pkg/event/controller"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	gnpacketfilter "github.com/submariner-io/submariner/pkg/globalnet/controllers/packetfilter"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
 	"github.com/submariner-io/submariner/pkg/globalnet/metrics"
 	"github.com/submariner-io/submariner/pkg/netlink"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
+	"github.com/submariner-io/submariner/pkg/packetfilter/configure"
+	"github.com/submariner-io/submariner/pkg/port"
 	routeAgent "github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -227,21 +230,21 @@ func (g *gatewayMonitor) RemoteEndpointCreated(endpoint *v1.Endpoint) error {
 	logger.V(log.DEBUG).Infof("Endpoint %q, host: %q belongs to a remote cluster",
 		endpoint.Spec.ClusterID, endpoint.Spec.Hostname)
 
-	globalCIDR := g.GatewayMonitorConfig.Spec.GlobalCIDR[0]
-
-	overlap, err := cidr.IsOverlapping(endpoint.Spec.Subnets, globalCIDR)
-	if err != nil {
-		// Ideally this case will never hit, as the subnets are valid CIDRs
-		logger.Warningf("unable to validate overlapping Service CIDR: %s", err)
-	}
+	for _, globalCIDR := range g.GatewayMonitorConfig.Spec.GlobalCIDR {
+		overlap, err := cidr.IsOverlapping(endpoint.Spec.Subnets, globalCIDR)
+		if err != nil {
+			// Ideally this case will never hit, as the subnets are valid CIDRs
+			logger.Warningf("unable to validate overlapping Service CIDR: %s", err)
+		}
 
-	if overlap {
-		// When GlobalNet is used, globalCIDRs allocated to the clusters should not overlap.
-		// If they overlap, skip the endpoint as its an invalid configuration which is not supported.
-		logger.Errorf(nil, "GlobalCIDR %q of local cluster %q overlaps with remote cluster %s",
-			globalCIDR, g.GatewayMonitorConfig.Spec.ClusterID, endpoint.Spec.ClusterID)
+		if overlap {
+			// When GlobalNet is used, globalCIDRs allocated to the clusters should not overlap.
+			// If they overlap, skip the endpoint as its an invalid configuration which is not supported.
+			logger.Errorf(nil, "GlobalCIDR %q of local cluster %q overlaps with remote cluster %s",
+				globalCIDR, g.GatewayMonitorConfig.Spec.ClusterID, endpoint.Spec.ClusterID)
 
-		return nil
+			return nil
+		}
 	}
 
 	g.markRemoteClusterTraffic(AddRules, endpoint.Spec.Subnets...)
@@ -352,14 +355,22 @@ func (g *gatewayMonitor) startControllers() error {
 		return err
 	}
 
-	pool, err := ipam.NewIPPool(g.GatewayMonitorConfig.Spec.GlobalCIDR[0], metrics.GlobalnetMetricsReporter)
+	pool, err := ippool.NewMultiPool(g.GatewayMonitorConfig.Spec.GlobalCIDR, metrics.GlobalnetMetricsReporter)
 	if err != nil {
 		return errors.Wrap(err, "error creating the IP pool")
 	}
 
 	g.controllers = nil
 
-	c, err := NewClusterGlobalEgressIPController(g.syncerConfig, g.LocalCIDRs, pool)
+	c, err := NewGlobalCIDRController(g.syncerConfig, pool, g.Spec.ClusterID, g.Spec.Namespace, g.Hostname,
+		g.Spec.GlobalIPPoolUsageThreshold)
+	if err != nil {
+		return errors.Wrap(err, "error creating the global CIDR controller")
+	}
+
+	g.controllers = append(g.controllers, c)
+
+	c, err = NewClusterGlobalEgressIPController(g.syncerConfig, g.LocalCIDRs, pool)
 	if err != nil {
 		return errors.Wrap(err, "error creating the ClusterGlobalEgressIP controller")
 	}
@@ -373,6 +384,13 @@ func (g *gatewayMonitor) startControllers() error {
 
 	g.controllers = append(g.controllers, c)
 
+	c, err = NewMultiNamespaceGlobalEgressIPController(g.syncerConfig, pool)
+	if err != nil {
+		return errors.Wrap(err, "error creating the MultiNamespaceGlobalEgressIP controller")
+	}
+
+	g.controllers = append(g.controllers, c)
+
 	// A user is not normally expected to delete the internal service created by the Globalnet controller.
 	// However, when it's accidentally done while the globalnet controller is down, the internal service
 	// remains until the finalizer is removed. We have seen that this intermediate state of
@@ -386,7 +404,7 @@ func (g *gatewayMonitor) startControllers() error {
 
 	// The GlobalIngressIP controller needs to be started before the ServiceExport and Service controllers to ensure
 	// reconciliation works properly.
-	gipController, err := NewGlobalIngressIPController(g.syncerConfig, pool)
+	gipController, err := NewGlobalIngressIPController(g.syncerConfig, pool, g.Spec.Namespace)
 	if err != nil {
 		return errors.Wrap(err, "error creating the GlobalIngressIP controller")
 	}
@@ -424,7 +442,8 @@ func (g *gatewayMonitor) startControllers() error {
 	g.controllers = append(g.controllers, c)
 
 	if g.cniIP != "" {
-		c, err := NewGatewayController(g.syncerConfig, g.gatewaySharedInformer, pool, g.Hostname, g.Spec.Namespace, g.cniIP)
+		c, err := NewGatewayController(g.syncerConfig, g.gatewaySharedInformer, pool, g.Hostname, g.Spec.Namespace, g.cniIP,
+			g.gatewayPorts())
 		if err != nil {
 			return errors.Wrap(err, "error creating the Gateway controller")
 		}
@@ -495,7 +514,7 @@ func (g *gatewayMonitor) startLocalGatewayCleanupController() error {
 
 		logger.Infof("Local Gateway %q deleted - removing ingress rules for global IP %q", gateway.Name, globalIP)
 
-		if err := pfIface.RemoveIngressRulesForHealthCheck(g.cniIP, globalIP); err != nil {
+		if err := pfIface.RemoveIngressRulesForGateway(g.cniIP, globalIP, g.gatewayPorts()); err != nil {
 			logger.Errorf(err, "Error removing rules for local Gateway %q", gateway.Name)
 			return nil, true
 		}
@@ -522,8 +541,8 @@ func (g *gatewayMonitor) createNATChain(chainName string) error {
 	return errors.Wrapf(err, "error creating packetfilter chain %q", chainName)
 }
 
-func (g *gatewayMonitor) createGlobalnetChains() error {
-	ipHookChains := []packetfilter.ChainIPHook{
+var (
+	globalnetIPHookChains = []packetfilter.ChainIPHook{
 		{
 			Name:     constants.SmGlobalnetIngressChain,
 			Type:     packetfilter.ChainTypeNAT,
@@ -538,15 +557,7 @@ func (g *gatewayMonitor) createGlobalnetChains() error {
 		},
 	}
 
-	for i := range ipHookChains {
-		logger.V(log.DEBUG).Infof("Install/ensure IP hook chain %q exists", ipHookChains[i].Name)
-
-		if err := g.pFilter.CreateIPHookChainIfNotExists(&ipHookChains[i]); err != nil {
-			return errors.Wrapf(err, "error creating IPHook chain %q", ipHookChains[i].Name)
-		}
-	}
-
-	for _, chain := range []string{
+	globalnetNATChains = []string{
 		constants.SmGlobalnetEgressChain,
 		constants.SmGlobalnetMarkChain,
 		constants.SmGlobalnetEgressChainForPods,
@@ -554,7 +565,32 @@ func (g *gatewayMonitor) createGlobalnetChains() error {
 		constants.SmGlobalnetEgressChainForHeadlessSvcEPs,
 		constants.SmGlobalnetEgressChainForNamespace,
 		constants.SmGlobalnetEgressChainForCluster,
-	} {
+	}
+)
+
+// PacketFilterMigrationSpec returns the chains programmed by globalnet.
+func PacketFilterMigrationSpec() *configure.MigrationSpec {
+	spec := &configure.MigrationSpec{
+		IPHookChains: slices.Clone(globalnetIPHookChains),
+	}
+
+	for _, chain := range globalnetNATChains {
+		spec.Chains = append(spec.Chains, configure.TableChain{Table: packetfilter.TableTypeNAT, Name: chain})
+	}
+
+	return spec
+}
+
+func (g *gatewayMonitor) createGlobalnetChains() error {
+	for i := range globalnetIPHookChains {
+		logger.V(log.DEBUG).Infof("Install/ensure IP hook chain %q exists", globalnetIPHookChains[i].Name)
+
+		if err := g.pFilter.CreateIPHookChainIfNotExists(&globalnetIPHookChains[i]); err != nil {
+			return errors.Wrapf(err, "error creating IPHook chain %q", globalnetIPHookChains[i].Name)
+		}
+	}
+
+	for _, chain := range globalnetNATChains {
 		if err := g.createNATChain(chain); err != nil {
 			return err
 		}
@@ -674,3 +710,21 @@ func configureTCPMTUProbe() {
 		logger.Warning(err.Error())
 	}
 }
+
+// gatewayPorts returns the UDP and TCP ports of the Gateway which remote gateways reach via its global IP.
+func (g *gatewayMonitor) gatewayPorts() []int {
+	ports := []int{port.HealthCheckProbe}
+	if g.Spec.HealthCheckProbePort != 0 {
+		ports[0] = g.Spec.HealthCheckProbePort
+	}
+
+	if g.Spec.BenchmarkEnabled {
+		if g.Spec.BenchmarkPort != 0 {
+			ports = append(ports, g.Spec.BenchmarkPort)
+		} else {
+			ports = append(ports, port.Benchmark)
+		}
+	}
+
+	return ports
+}
@@ -37,6 +37,7 @@ import (
 	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
 	fakeNetlink "github.com/submariner-io/submariner/pkg/netlink/fake"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
+	"github.com/submariner-io/submariner/pkg/packetfilter/configure"
 	routeAgent "github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -86,6 +87,19 @@ var _ = Describe("Endpoint monitoring", func() {
 				t.awaitHeadlessGlobalIngressIP(service.Name, backendPod.Name)
 				t.awaitGatewayGlobalIP("")
 			})
+
+			It("should only program chains included in the packet filter migration spec", func() {
+				t.awaitControllersStarted()
+
+				t.createServiceExport(t.createService(newClusterIPService()))
+				t.awaitIngressIPStatusAllocated(serviceName)
+
+				// The kube-proxy chain is created by the test and isn't owned by globalnet.
+				t.pFilter.EnsureChainsIncludedIn(configure.MergeMigrationSpecs(controllers.PacketFilterMigrationSpec(),
+					&configure.MigrationSpec{
+						Chains: []configure.TableChain{{Table: packetfilter.TableTypeNAT, Name: kubeProxyIPTableChainName}},
+					}).Includes)
+			})
 		})
 
 		Context("and then deleted and recreated", func() {
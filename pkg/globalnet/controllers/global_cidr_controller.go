@@ -0,0 +1,174 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/federate"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/admiral/pkg/resource"
+	"github.com/submariner-io/admiral/pkg/syncer"
+	"github.com/submariner-io/admiral/pkg/util"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+const defaultPoolUsageCheckInterval = 30 * time.Second
+
+// NewGlobalCIDRController creates a controller that adds the global CIDRs appended to the local Cluster resource to the pool at
+// runtime and raises a condition on the local Gateway when the usage of any of the pool's CIDRs passes the given threshold.
+func NewGlobalCIDRController(config *syncer.ResourceSyncerConfig, pool *ippool.MultiPool, clusterID, namespace, hostName string,
+	threshold int,
+) (Interface, error) {
+	var err error
+
+	logger.Info("Creating global CIDR controller")
+
+	_, gvr, err := util.ToUnstructuredResource(&submarinerv1.Gateway{}, config.RestMapper)
+	if err != nil {
+		return nil, errors.Wrap(err, "error converting resource")
+	}
+
+	controller := &globalCIDRController{
+		baseSyncerController: newBaseSyncerController(),
+		pool:                 pool,
+		gateways:             config.SourceClient.Resource(*gvr).Namespace(namespace),
+		hostName:             hostName,
+		threshold:            threshold,
+		usageCheckTime:       defaultPoolUsageCheckInterval,
+	}
+
+	controller.resourceSyncer, err = syncer.NewResourceSyncer(&syncer.ResourceSyncerConfig{
+		Name:            "Global CIDR syncer",
+		ResourceType:    &submarinerv1.Cluster{},
+		SourceClient:    config.SourceClient,
+		SourceNamespace: namespace,
+		RestMapper:      config.RestMapper,
+		Federator:       federate.NewNoopFederator(),
+		Scheme:          config.Scheme,
+		Transform:       controller.process,
+		ShouldProcess: func(obj *unstructured.Unstructured, op syncer.Operation) bool {
+			return op != syncer.Delete && obj.GetName() == clusterID
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the syncer")
+	}
+
+	return controller, nil
+}
+
+func (c *globalCIDRController) Start() error {
+	if err := c.baseSyncerController.Start(); err != nil {
+		return err
+	}
+
+	go wait.Until(c.checkPoolUsage, c.usageCheckTime, c.stopCh)
+
+	return nil
+}
+
+func (c *globalCIDRController) process(from runtime.Object, _ int, _ syncer.Operation) (runtime.Object, bool) {
+	cluster := from.(*submarinerv1.Cluster)
+
+	for _, cidr := range cluster.Spec.GlobalCIDR {
+		added, err := c.pool.AddCIDR(cidr)
+		if err != nil {
+			logger.Errorf(err, "Error adding global CIDR %q of cluster %q to the pool", cidr, cluster.Name)
+			continue
+		}
+
+		if added {
+			logger.Infof("Added global CIDR %q to the pool - the CIDRs are now %v", cidr, c.pool.GetCIDRs())
+		}
+	}
+
+	c.checkPoolUsage()
+
+	return nil, false
+}
+
+func (c *globalCIDRController) checkPoolUsage() {
+	var overThreshold []string
+
+	for _, usage := range c.pool.GetUsage() {
+		if usage.UsedPercent() >= c.threshold {
+			overThreshold = append(overThreshold, fmt.Sprintf("%s (%d%%)", usage.CIDR, usage.UsedPercent()))
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:   string(submarinerv1.GatewayGlobalIPPoolUsageHigh),
+		Status: metav1.ConditionFalse,
+		Reason: "UsageBelowThreshold",
+	}
+
+	if len(overThreshold) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "UsageAboveThreshold"
+		condition.Message = fmt.Sprintf("The usage of the global CIDRs %s has passed the %d%% threshold",
+			strings.Join(overThreshold, ", "), c.threshold)
+	}
+
+	if err := c.updateGatewayCondition(&condition); err != nil {
+		logger.Errorf(err, "Error updating the %q condition of Gateway %q", condition.Type, c.hostName)
+	}
+}
+
+func (c *globalCIDRController) updateGatewayCondition(condition *metav1.Condition) error {
+	//nolint:wrapcheck // No need to wrap
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := c.gateways.Get(context.TODO(), c.hostName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		gateway := &submarinerv1.Gateway{}
+		_ = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, gateway)
+
+		existing := meta.FindStatusCondition(gateway.Status.Conditions, condition.Type)
+		if existing != nil && existing.Status == condition.Status && existing.Message == condition.Message {
+			return nil
+		}
+
+		logger.V(log.DEBUG).Infof("Setting the %q condition of Gateway %q to %q", condition.Type, c.hostName, condition.Status)
+
+		meta.SetStatusCondition(&gateway.Status.Conditions, *condition)
+
+		_, err = c.gateways.Update(context.TODO(), resource.MustToUnstructured(gateway), metav1.UpdateOptions{})
+
+		return err
+	})
+}
@@ -0,0 +1,158 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/syncer"
+	"github.com/submariner-io/admiral/pkg/syncer/test"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
+	"github.com/submariner-io/submariner/pkg/globalnet/metrics"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	localClusterID = "east"
+	globalCIDR2    = "242.10.2.0/24"
+)
+
+var _ = Describe("Global CIDR controller", func() {
+	t := newGlobalCIDRControllerTestDriver()
+
+	When("the local Cluster has an additional global CIDR appended", func() {
+		JustBeforeEach(func() {
+			t.cluster.Spec.GlobalCIDR = append(t.cluster.Spec.GlobalCIDR, globalCIDR2)
+			test.UpdateResource(t.clusters, t.cluster)
+		})
+
+		It("should add it to the pool", func() {
+			Eventually(t.pool.GetCIDRs).Should(Equal([]string{globalCIDR, globalCIDR2}))
+		})
+
+		Context("and the first global CIDR is exhausted", func() {
+			BeforeEach(func() {
+				_, err := t.pool.Allocate(t.pool.Size())
+				Expect(err).To(Succeed())
+			})
+
+			It("should allocate from the additional global CIDR", func() {
+				Eventually(t.pool.GetCIDRs).Should(HaveLen(2))
+
+				ips, err := t.pool.Allocate(1)
+				Expect(err).To(Succeed())
+				Expect(t.pool.GetCIDRFor(ips)).To(Equal(globalCIDR2))
+			})
+		})
+	})
+
+	When("the usage of a global CIDR is below the threshold", func() {
+		It("should set the Gateway condition to false", func() {
+			t.awaitGatewayCondition(metav1.ConditionFalse)
+		})
+	})
+
+	When("the usage of a global CIDR passes the threshold", func() {
+		BeforeEach(func() {
+			_, err := t.pool.Allocate(220)
+			Expect(err).To(Succeed())
+		})
+
+		It("should set the Gateway condition to true", func() {
+			t.awaitGatewayCondition(metav1.ConditionTrue)
+		})
+	})
+})
+
+type globalCIDRControllerTestDriver struct {
+	*testDriverBase
+	clusters dynamic.ResourceInterface
+	cluster  *submarinerv1.Cluster
+}
+
+func newGlobalCIDRControllerTestDriver() *globalCIDRControllerTestDriver {
+	t := &globalCIDRControllerTestDriver{}
+
+	BeforeEach(func() {
+		t.testDriverBase = newTestDriverBase()
+
+		var err error
+
+		t.pool, err = ippool.NewMultiPool([]string{t.globalCIDR}, metrics.GlobalnetMetricsReporter)
+		Expect(err).To(Succeed())
+
+		t.clusters = t.dynClient.Resource(*test.GetGroupVersionResourceFor(t.restMapper, &submarinerv1.Cluster{})).
+			Namespace(namespace)
+
+		t.cluster = &submarinerv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: localClusterID,
+			},
+			Spec: submarinerv1.ClusterSpec{
+				ClusterID:  localClusterID,
+				GlobalCIDR: []string{t.globalCIDR},
+			},
+		}
+
+		t.createGateway(t.hostName, "")
+		test.CreateResource(t.clusters, t.cluster)
+	})
+
+	JustBeforeEach(func() {
+		var err error
+
+		t.controller, err = controllers.NewGlobalCIDRController(&syncer.ResourceSyncerConfig{
+			SourceClient: t.dynClient,
+			RestMapper:   t.restMapper,
+			Scheme:       t.scheme,
+		}, t.pool, localClusterID, namespace, t.hostName, 80)
+		Expect(err).To(Succeed())
+		Expect(t.controller.Start()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		t.testDriverBase.afterEach()
+	})
+
+	return t
+}
+
+func (t *globalCIDRControllerTestDriver) awaitGatewayCondition(status metav1.ConditionStatus) {
+	Eventually(func() metav1.ConditionStatus {
+		obj, err := t.gateways.Get(context.TODO(), t.hostName, metav1.GetOptions{})
+		Expect(err).To(Succeed())
+
+		gateway := &submarinerv1.Gateway{}
+		Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, gateway)).To(Succeed())
+
+		cond := meta.FindStatusCondition(gateway.Status.Conditions, string(submarinerv1.GatewayGlobalIPPoolUsageHigh))
+		if cond == nil {
+			return ""
+		}
+
+		return cond.Status
+	}).Should(Equal(status))
+}
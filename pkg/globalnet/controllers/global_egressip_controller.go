@@ -26,12 +26,12 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/federate"
-	"github.com/submariner-io/admiral/pkg/ipam"
 	"github.com/submariner-io/admiral/pkg/syncer"
 	"github.com/submariner-io/admiral/pkg/util"
 	"github.com/submariner-io/admiral/pkg/watcher"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers/packetfilter"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
 	"github.com/submariner-io/submariner/pkg/globalnet/metrics"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -41,7 +41,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 )
 
-func NewGlobalEgressIPController(config *syncer.ResourceSyncerConfig, pool *ipam.IPPool) (Interface, error) {
+func NewGlobalEgressIPController(config *syncer.ResourceSyncerConfig, pool *ippool.MultiPool) (Interface, error) {
 	// We'll panic if config is nil, this is intentional
 	var err error
 
@@ -78,7 +78,7 @@ func NewGlobalEgressIPController(config *syncer.ResourceSyncerConfig, pool *ipam
 
 	for i := range list.Items {
 		err = controller.reserveAllocatedIPs(federator, &list.Items[i], func(reservedIPs []string) error {
-			metrics.RecordAllocateGlobalEgressIPs(pool.GetCIDR(), len(reservedIPs))
+			metrics.RecordAllocateGlobalEgressIPs(pool.GetCIDRFor(reservedIPs), len(reservedIPs))
 
 			specObj := util.GetSpec(&list.Items[i])
 			spec := &submarinerv1.GlobalEgressIPSpec{}
@@ -141,7 +141,7 @@ func (c *globalEgressIPController) process(from runtime.Object, numRequeues int,
 		trimAllocatedStatusCondition(&globalEgressIP.Status.Conditions)
 
 		requeue := false
-		if c.validate(numberOfIPs, globalEgressIP) {
+		if c.validateNumberOfIPs(numberOfIPs, &globalEgressIP.Status) {
 			requeue = c.onCreateOrUpdate(key, numberOfIPs, globalEgressIP, numRequeues)
 		}
 
@@ -160,15 +160,16 @@ func (c *globalEgressIPController) onCreateOrUpdate(key string, numberOfIPs int,
 
 	requeue := false
 	if numberOfIPs != len(globalEgressIP.Status.AllocatedIPs) {
-		requeue = c.flushGlobalEgressRulesAndReleaseIPs(key, namedSet.Name(), numRequeues, globalEgressIP)
+		requeue = c.flushGlobalEgressRulesAndReleaseIPs(key, namedSet.Name(), numRequeues, globalEgressIP.Spec.PodSelector,
+			globalEgressIP.Status.AllocatedIPs)
 	}
 
-	return requeue || c.allocateGlobalIPs(key, numberOfIPs, globalEgressIP, namedSet) ||
+	return requeue || c.allocateGlobalIPs(key, numberOfIPs, globalEgressIP.Spec.PodSelector, &globalEgressIP.Status, namedSet) ||
 		!c.createPodWatcher(key, namedSet, numberOfIPs, globalEgressIP)
 }
 
 //nolint:wrapcheck  // No need to wrap these errors.
-func (c *globalEgressIPController) programGlobalEgressRules(key string, allocatedIPs []string, podSelector *metav1.LabelSelector,
+func (c *baseIPAllocationController) programGlobalEgressRules(key string, allocatedIPs []string, podSelector *metav1.LabelSelector,
 	namedSet packetfilter.NamedSet,
 ) error {
 	err := namedSet.Create(true)
@@ -192,15 +193,15 @@ func (c *globalEgressIPController) programGlobalEgressRules(key string, allocate
 	return nil
 }
 
-func (c *globalEgressIPController) allocateGlobalIPs(key string, numberOfIPs int,
-	globalEgressIP *submarinerv1.GlobalEgressIP, namedSet packetfilter.NamedSet,
+func (c *baseIPAllocationController) allocateGlobalIPs(key string, numberOfIPs int, podSelector *metav1.LabelSelector,
+	status *submarinerv1.GlobalEgressIPStatus, namedSet packetfilter.NamedSet,
 ) bool {
 	logger.Infof("Allocating %d global IP(s) for %q", numberOfIPs, key)
 
 	if numberOfIPs == 0 {
-		globalEgressIP.Status.AllocatedIPs = nil
+		status.AllocatedIPs = nil
 
-		meta.SetStatusCondition(&globalEgressIP.Status.Conditions, metav1.Condition{
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
 			Type:    string(submarinerv1.GlobalEgressIPAllocated),
 			Status:  metav1.ConditionFalse,
 			Reason:  "ZeroInput",
@@ -210,17 +211,17 @@ func (c *globalEgressIPController) allocateGlobalIPs(key string, numberOfIPs int
 		return false
 	}
 
-	if numberOfIPs == len(globalEgressIP.Status.AllocatedIPs) {
+	if numberOfIPs == len(status.AllocatedIPs) {
 		return false
 	}
 
-	globalEgressIP.Status.AllocatedIPs = nil
+	status.AllocatedIPs = nil
 
 	allocatedIPs, err := c.pool.Allocate(numberOfIPs)
 	if err != nil {
 		logger.Errorf(err, "Error allocating IPs for %q", key)
 
-		meta.SetStatusCondition(&globalEgressIP.Status.Conditions, metav1.Condition{
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
 			Type:    string(submarinerv1.GlobalEgressIPAllocated),
 			Status:  metav1.ConditionFalse,
 			Reason:  "IPPoolAllocationFailed",
@@ -230,11 +231,11 @@ func (c *globalEgressIPController) allocateGlobalIPs(key string, numberOfIPs int
 		return true
 	}
 
-	err = c.programGlobalEgressRules(key, allocatedIPs, globalEgressIP.Spec.PodSelector, namedSet)
+	err = c.programGlobalEgressRules(key, allocatedIPs, podSelector, namedSet)
 	if err != nil {
 		logger.Errorf(err, "Error programming egress IP table rules for %q", key)
 
-		meta.SetStatusCondition(&globalEgressIP.Status.Conditions, metav1.Condition{
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
 			Type:    string(submarinerv1.GlobalEgressIPAllocated),
 			Status:  metav1.ConditionFalse,
 			Reason:  "ProgramIPTableRulesFailed",
@@ -246,25 +247,25 @@ func (c *globalEgressIPController) allocateGlobalIPs(key string, numberOfIPs int
 		return true
 	}
 
-	metrics.RecordAllocateGlobalEgressIPs(c.pool.GetCIDR(), numberOfIPs)
+	metrics.RecordAllocateGlobalEgressIPs(c.pool.GetCIDRFor(allocatedIPs), numberOfIPs)
 
-	meta.SetStatusCondition(&globalEgressIP.Status.Conditions, metav1.Condition{
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
 		Type:    string(submarinerv1.GlobalEgressIPAllocated),
 		Status:  metav1.ConditionTrue,
 		Reason:  "Success",
 		Message: fmt.Sprintf("Allocated %d global IP(s)", numberOfIPs),
 	})
 
-	globalEgressIP.Status.AllocatedIPs = allocatedIPs
+	status.AllocatedIPs = allocatedIPs
 
-	logger.Infof("Allocated %v global IP(s) for %q", globalEgressIP.Status.AllocatedIPs, key)
+	logger.Infof("Allocated %v global IP(s) for %q", status.AllocatedIPs, key)
 
 	return false
 }
 
-func (c *globalEgressIPController) validate(numberOfIPs int, egressIP *submarinerv1.GlobalEgressIP) bool {
+func (c *baseIPAllocationController) validateNumberOfIPs(numberOfIPs int, status *submarinerv1.GlobalEgressIPStatus) bool {
 	if numberOfIPs < 0 {
-		meta.SetStatusCondition(&egressIP.Status.Conditions, metav1.Condition{
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
 			Type:    string(submarinerv1.GlobalEgressIPAllocated),
 			Status:  metav1.ConditionFalse,
 			Reason:  "InvalidInput",
@@ -297,7 +298,8 @@ func (c *globalEgressIPController) onDelete(numRequeues int, globalEgressIP *sub
 		globalEgressIP.Status.AllocatedIPs = podWatcher.allocatedIPs
 	}
 
-	requeue := c.flushGlobalEgressRulesAndReleaseIPs(key, namedSet.Name(), numRequeues, globalEgressIP)
+	requeue := c.flushGlobalEgressRulesAndReleaseIPs(key, namedSet.Name(), numRequeues, globalEgressIP.Spec.PodSelector,
+		globalEgressIP.Status.AllocatedIPs)
 	if requeue {
 		return requeue
 	}
@@ -319,7 +321,7 @@ func (c *globalEgressIPController) onDelete(numRequeues int, globalEgressIP *sub
 	return false
 }
 
-func (c *globalEgressIPController) getIPSetName(key string) string {
+func getIPSetName(key string) string {
 	hash := sha256.Sum256([]byte(key))
 	encoded := base32.StdEncoding.EncodeToString(hash[:])
 	// Max length of IPSet name can be 31
@@ -367,21 +369,21 @@ func (c *globalEgressIPController) createPodWatcher(key string, namedSet packetf
 	return true
 }
 
-func (c *globalEgressIPController) flushGlobalEgressRulesAndReleaseIPs(key, namedSetName string, numRequeues int,
-	globalEgressIP *submarinerv1.GlobalEgressIP,
+func (c *baseIPAllocationController) flushGlobalEgressRulesAndReleaseIPs(key, namedSetName string, numRequeues int,
+	podSelector *metav1.LabelSelector, allocatedIPs []string,
 ) bool {
 	return c.flushRulesAndReleaseIPs(key, numRequeues, func(allocatedIPs []string) error {
-		metrics.RecordDeallocateGlobalEgressIPs(c.pool.GetCIDR(), len(allocatedIPs))
+		metrics.RecordDeallocateGlobalEgressIPs(c.pool.GetCIDRFor(allocatedIPs), len(allocatedIPs))
 
-		if globalEgressIP.Spec.PodSelector != nil {
+		if podSelector != nil {
 			return c.pfIface.RemoveEgressRulesForPods(key, namedSetName,
 				getTargetSNATIPaddress(allocatedIPs), globalNetIPTableMark)
 		}
 
 		return c.pfIface.RemoveEgressRulesForNamespace(key, namedSetName, getTargetSNATIPaddress(allocatedIPs), globalNetIPTableMark)
-	}, globalEgressIP.Status.AllocatedIPs...)
+	}, allocatedIPs...)
 }
 
-func (c *globalEgressIPController) newNamedSet(key string) packetfilter.NamedSet {
-	return c.pfIface.NewNamedSet(c.getIPSetName(key))
+func (c *baseIPAllocationController) newNamedSet(key string) packetfilter.NamedSet {
+	return c.pfIface.NewNamedSet(getIPSetName(key))
 }
@@ -25,12 +25,12 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	fakeDynClient "github.com/submariner-io/admiral/pkg/fake"
-	"github.com/submariner-io/admiral/pkg/ipam"
 	"github.com/submariner-io/admiral/pkg/syncer"
 	"github.com/submariner-io/admiral/pkg/syncer/test"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
 	"github.com/submariner-io/submariner/pkg/globalnet/metrics"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
 	corev1 "k8s.io/api/core/v1"
@@ -598,7 +598,7 @@ func newGlobalEgressIPControllerTestDriver() *globalEgressIPControllerTestDriver
 
 		var err error
 
-		t.pool, err = ipam.NewIPPool(t.globalCIDR, metrics.GlobalnetMetricsReporter)
+		t.pool, err = ippool.NewMultiPool([]string{t.globalCIDR}, metrics.GlobalnetMetricsReporter)
 		Expect(err).To(Succeed())
 
 		t.watches = fakeDynClient.NewWatchReactor(&t.dynClient.Fake)
@@ -25,12 +25,12 @@ import (
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/federate"
 	"github.com/submariner-io/admiral/pkg/finalizer"
-	"github.com/submariner-io/admiral/pkg/ipam"
 	"github.com/submariner-io/admiral/pkg/resource"
 	"github.com/submariner-io/admiral/pkg/syncer"
 	"github.com/submariner-io/admiral/pkg/util"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	pfiface "github.com/submariner-io/submariner/pkg/globalnet/controllers/packetfilter"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
 	"github.com/submariner-io/submariner/pkg/globalnet/metrics"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -41,7 +41,8 @@ import (
 )
 
 //nolint:revive // Ignore "unexported-return:... which can be annoying to use"; it's only used by unit tests.
-func NewGlobalIngressIPController(config *syncer.ResourceSyncerConfig, pool *ipam.IPPool) (*globalIngressIPController, error) {
+func NewGlobalIngressIPController(config *syncer.ResourceSyncerConfig, pool *ippool.MultiPool, namespace string,
+) (*globalIngressIPController, error) {
 	// We'll panic if config is nil, this is intentional
 	var err error
 
@@ -61,6 +62,8 @@ func NewGlobalIngressIPController(config *syncer.ResourceSyncerConfig, pool *ipa
 		baseIPAllocationController: newBaseIPAllocationController(pool, pfIface),
 		services:                   config.SourceClient.Resource(*gvr),
 		scheme:                     config.Scheme,
+		configMaps:                 config.SourceClient.Resource(corev1.SchemeGroupVersion.WithResource("configmaps")).Namespace(namespace),
+		retained:                   map[string]retainedIPs{},
 	}
 
 	_, gvr, err = util.ToUnstructuredResource(&submarinerv1.GlobalIngressIP{}, config.RestMapper)
@@ -87,9 +90,9 @@ func NewGlobalIngressIPController(config *syncer.ResourceSyncerConfig, pool *ipa
 			var target string
 			var tType pfiface.TargetType
 
-			metrics.RecordAllocateGlobalIngressIPs(pool.GetCIDR(), len(reservedIPs))
+			metrics.RecordAllocateGlobalIngressIPs(pool.GetCIDRFor(reservedIPs), len(reservedIPs))
 
-			if gip.Spec.Target == submarinerv1.ClusterIPService {
+			if usesInternalService(gip.Spec.Target) {
 				return controller.ensureInternalServiceExists(gip)
 			} else if gip.Spec.Target == submarinerv1.HeadlessServicePod {
 				target = gip.GetAnnotations()[headlessSvcPodIP]
@@ -115,6 +118,11 @@ func NewGlobalIngressIPController(config *syncer.ResourceSyncerConfig, pool *ipa
 		}
 	}
 
+	if err := controller.loadRetainedIPs(context.TODO()); err != nil {
+		// Just log an error as the retained IPs are only a best effort
+		logger.Errorf(err, "Error loading the retained global IPs")
+	}
+
 	controller.resourceSyncer, err = syncer.NewResourceSyncer(&syncer.ResourceSyncerConfig{
 		Name:                "GlobalIngressIP syncer",
 		ResourceType:        &submarinerv1.GlobalIngressIP{},
@@ -169,14 +177,19 @@ func (c *globalIngressIPController) onCreate(ingressIP *submarinerv1.GlobalIngre
 
 	key, _ := cache.MetaNamespaceKeyFunc(ingressIP)
 
-	ips, err := c.pool.Allocate(1)
+	ips, err := c.allocateIP(key, ingressIP.Spec.RequestedIP)
 	if err != nil {
 		logger.Errorf(err, "Error allocating IP for %q", key)
 
+		reason := "IPPoolAllocationFailed"
+		if ingressIP.Spec.RequestedIP != "" {
+			reason = "RequestedIPUnavailable"
+		}
+
 		meta.SetStatusCondition(&ingressIP.Status.Conditions, metav1.Condition{
 			Type:    string(submarinerv1.GlobalEgressIPAllocated),
 			Status:  metav1.ConditionFalse,
-			Reason:  "IPPoolAllocationFailed",
+			Reason:  reason,
 			Message: fmt.Sprintf("Error allocating a global IP from the pool: %v", err),
 		})
 
@@ -185,7 +198,7 @@ func (c *globalIngressIPController) onCreate(ingressIP *submarinerv1.GlobalIngre
 
 	logger.Infof("Allocated global IP %q for %q", ips, key)
 
-	if ingressIP.Spec.Target == submarinerv1.ClusterIPService {
+	if usesInternalService(ingressIP.Spec.Target) {
 		serviceRef := ingressIP.Spec.ServiceRef
 
 		service, exists, err := getService(serviceRef.Name, ingressIP.Namespace, c.services, c.scheme)
@@ -264,7 +277,7 @@ func (c *globalIngressIPController) onCreate(ingressIP *submarinerv1.GlobalIngre
 		}
 	}
 
-	metrics.RecordAllocateGlobalIngressIPs(c.pool.GetCIDR(), 1)
+	metrics.RecordAllocateGlobalIngressIPs(c.pool.GetCIDRFor(ips), 1)
 
 	ingressIP.Status.AllocatedIP = ips[0]
 
@@ -278,7 +291,51 @@ func (c *globalIngressIPController) onCreate(ingressIP *submarinerv1.GlobalIngre
 	return false
 }
 
+// allocateIP returns the global IP retained for the given key after a prior deletion, if any and it matches the requested IP.
+// Otherwise the requested IP is reserved, if specified, else the next available IP is allocated.
+func (c *globalIngressIPController) allocateIP(key, requestedIP string) ([]string, error) {
+	if retained := c.reclaimIPs(key); len(retained) > 0 {
+		if requestedIP == "" || requestedIP == retained[0] {
+			logger.Infof("Reusing retained global IP %q for %q", retained, key)
+			return retained, nil
+		}
+
+		_ = c.pool.Release(retained...)
+	}
+
+	if requestedIP != "" {
+		if err := c.pool.Reserve(requestedIP); err != nil {
+			return nil, errors.Wrapf(err, "error reserving the requested global IP %q", requestedIP)
+		}
+
+		return []string{requestedIP}, nil
+	}
+
+	return c.pool.Allocate(1) //nolint:wrapcheck  // No need to wrap
+}
+
+// releaseIP releases the allocated global IP or, if a retention period is specified, holds it for the same key.
+func (c *globalIngressIPController) releaseIP(key string, ingressIP *submarinerv1.GlobalIngressIP) {
+	if ingressIP.Spec.RetentionPeriod != nil && ingressIP.Spec.RetentionPeriod.Duration > 0 {
+		logger.Infof("Retaining global IP %q for %q for %v", ingressIP.Status.AllocatedIP, key, ingressIP.Spec.RetentionPeriod.Duration)
+		c.retainIPs(key, ingressIP.Spec.RetentionPeriod.Duration, ingressIP.Status.AllocatedIP)
+
+		return
+	}
+
+	if err := c.pool.Release(ingressIP.Status.AllocatedIP); err != nil {
+		logger.Errorf(err, "Error while releasing the global IPs for %q", key)
+	}
+}
+
 func (c *globalIngressIPController) createOrUpdateInternalService(from *corev1.Service, extIP string) error {
+	// The internal Service is a ClusterIP Service so it can't have node ports, which LoadBalancer and NodePort Services have.
+	ports := make([]corev1.ServicePort, len(from.Spec.Ports))
+	for i := range from.Spec.Ports {
+		ports[i] = from.Spec.Ports[i]
+		ports[i].NodePort = 0
+	}
+
 	internalService := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: GetInternalSvcName(from.Name),
@@ -288,7 +345,7 @@ func (c *globalIngressIPController) createOrUpdateInternalService(from *corev1.S
 			Finalizers: []string{InternalServiceFinalizer},
 		},
 		Spec: corev1.ServiceSpec{
-			Ports:          from.Spec.Ports,
+			Ports:          ports,
 			Selector:       from.Spec.Selector,
 			ExternalIPs:    []string{extIP},
 			IPFamilyPolicy: ptr.To(corev1.IPFamilyPolicySingleStack),
@@ -308,7 +365,7 @@ func (c *globalIngressIPController) createOrUpdateInternalService(from *corev1.S
 }
 
 func (c *globalIngressIPController) onUpdate(ingressIP *submarinerv1.GlobalIngressIP) bool {
-	if ingressIP.Spec.Target != submarinerv1.ClusterIPService {
+	if !usesInternalService(ingressIP.Spec.Target) {
 		return false
 	}
 
@@ -340,7 +397,7 @@ func (c *globalIngressIPController) onDelete(ingressIP *submarinerv1.GlobalIngre
 
 	key, _ := cache.MetaNamespaceKeyFunc(ingressIP)
 
-	if ingressIP.Spec.Target == submarinerv1.ClusterIPService {
+	if usesInternalService(ingressIP.Spec.Target) {
 		intSvcName := GetInternalSvcName(ingressIP.Spec.ServiceRef.Name)
 		logger.Infof("Deleting the service %q/%q created by Globalnet controller", ingressIP.Namespace, intSvcName)
 
@@ -364,18 +421,18 @@ func (c *globalIngressIPController) onDelete(ingressIP *submarinerv1.GlobalIngre
 			}
 		}
 
-		if err = c.pool.Release(ingressIP.Status.AllocatedIP); err != nil {
-			logger.Errorf(err, "Error while releasing the global IPs for %q", key)
-		}
+		c.releaseIP(key, ingressIP)
 
 		return false
 	}
 
-	return c.flushRulesAndReleaseIPs(key, numRequeues, func(allocatedIPs []string) error {
+	logger.Infof("Releasing previously allocated IP %q for %q", ingressIP.Status.AllocatedIP, key)
+
+	if c.flushRules(key, numRequeues, func(allocatedIPs []string) error {
 		var target string
 		var tType pfiface.TargetType
 
-		metrics.RecordDeallocateGlobalIngressIPs(c.pool.GetCIDR(), len(allocatedIPs))
+		metrics.RecordDeallocateGlobalIngressIPs(c.pool.GetCIDRFor(allocatedIPs), len(allocatedIPs))
 
 		if ingressIP.Spec.Target == submarinerv1.HeadlessServicePod {
 			target = ingressIP.GetAnnotations()[headlessSvcPodIP]
@@ -394,7 +451,13 @@ func (c *globalIngressIPController) onDelete(ingressIP *submarinerv1.GlobalIngre
 		}
 
 		return nil
-	}, ingressIP.Status.AllocatedIP)
+	}, []string{ingressIP.Status.AllocatedIP}) {
+		return true
+	}
+
+	c.releaseIP(key, ingressIP)
+
+	return false
 }
 
 func (c *globalIngressIPController) ensureInternalServiceExists(ingressIP *submarinerv1.GlobalIngressIP) error {
@@ -434,7 +497,7 @@ func (c *globalIngressIPController) getServiceExternalIP(service *corev1.Service
 }
 
 func (c *globalIngressIPController) getTargetReference(giip *submarinerv1.GlobalIngressIP) string {
-	if giip.Spec.Target == submarinerv1.ClusterIPService {
+	if usesInternalService(giip.Spec.Target) {
 		return giip.Spec.ServiceRef.Name
 	} else if giip.Spec.Target == submarinerv1.HeadlessServicePod {
 		return giip.Spec.PodRef.Name
@@ -442,3 +505,10 @@ func (c *globalIngressIPController) getTargetReference(giip *submarinerv1.Global
 
 	return ""
 }
+
+// usesInternalService returns true for the targets reached via an internal Service whose external IP is the global IP, so
+// kube-proxy load balances the ingress traffic to the backend Pods.
+func usesInternalService(target submarinerv1.TargetType) bool {
+	return target == submarinerv1.ClusterIPService || target == submarinerv1.LoadBalancerService ||
+		target == submarinerv1.NodePortService
+}
@@ -24,11 +24,11 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/submariner-io/admiral/pkg/ipam"
 	"github.com/submariner-io/admiral/pkg/syncer"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
 	"github.com/submariner-io/submariner/pkg/globalnet/metrics"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
 	corev1 "k8s.io/api/core/v1"
@@ -107,6 +107,18 @@ var _ = Describe("GlobalIngressIP controller", func() {
 		t.awaitNoEndpointsIngressRules(endpointsIP, ip)
 	}
 
+	loadBalancerServiceIngress := &submarinerv1.GlobalIngressIP{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: globalIngressIPName,
+		},
+		Spec: submarinerv1.GlobalIngressIPSpec{
+			Target: submarinerv1.LoadBalancerService,
+			ServiceRef: &corev1.LocalObjectReference{
+				Name: "nginx",
+			},
+		},
+	}
+
 	When("a GlobalIngressIP for a cluster IP Service is created", func() {
 		testGlobalIngressIPCreatedClusterIPSvc(t, clusterIPServiceIngress)
 	})
@@ -127,8 +139,125 @@ var _ = Describe("GlobalIngressIP controller", func() {
 		testGlobalIngressIPCreatedHeadlessSvc(t, headlessServiceWithoutSelectorIngress, awaitHeadlessServiceEndpointsRules,
 			awaitNoHeadlessServiceEndpointsRules, endpointsIP)
 	})
+
+	When("a GlobalIngressIP for a LoadBalancer Service is created", func() {
+		testGlobalIngressIPCreatedClusterIPSvc(t, loadBalancerServiceIngress)
+	})
+
+	When("a GlobalIngressIP for a NodePort Service is created", func() {
+		It("should create an internal submariner service without the node ports", func() {
+			service := newClusterIPService()
+			service.Spec.Type = corev1.ServiceTypeNodePort
+			service.Spec.Ports[0].NodePort = 31000
+			t.createService(service)
+
+			ingressIP := loadBalancerServiceIngress.DeepCopy()
+			ingressIP.Spec.Target = submarinerv1.NodePortService
+			t.createGlobalIngressIP(ingressIP)
+
+			t.awaitIngressIPStatusAllocated(globalIngressIPName)
+
+			intSvc := t.awaitService(controllers.GetInternalSvcName(serviceName))
+			Expect(intSvc.Spec.ExternalIPs).To(Equal([]string{t.getGlobalIngressIPStatus(globalIngressIPName).AllocatedIP}))
+			Expect(intSvc.Spec.Ports).To(HaveLen(1))
+			Expect(intSvc.Spec.Ports[0].Port).To(Equal(service.Spec.Ports[0].Port))
+			Expect(intSvc.Spec.Ports[0].NodePort).To(BeZero())
+		})
+	})
+
+	When("a GlobalIngressIP for a LoadBalancer Service exists on startup", func() {
+		testExistingGlobalIngressIPClusterIPSvc(t, loadBalancerServiceIngress)
+	})
+
+	When("a GlobalIngressIP with a requested IP and retention period is created", func() {
+		testGlobalIngressIPWithRequestedIPAndRetention(t, headlessServiceIngress, awaitHeadlessServicePodRules,
+			awaitNoHeadlessServicePodRules)
+	})
 })
 
+func testGlobalIngressIPWithRequestedIPAndRetention(t *globalIngressIPControllerTestDriver, from *submarinerv1.GlobalIngressIP,
+	awaitPacketFilterRules, awaitNoPacketFilterRules func(string),
+) {
+	var ingressIP *submarinerv1.GlobalIngressIP
+
+	BeforeEach(func() {
+		ingressIP = from.DeepCopy()
+		ingressIP.Spec.RequestedIP = globalIP1
+		ingressIP.Spec.RetentionPeriod = &metav1.Duration{Duration: time.Hour}
+	})
+
+	JustBeforeEach(func() {
+		t.createService(newClusterIPService())
+		t.createGlobalIngressIP(ingressIP)
+	})
+
+	It("should allocate the requested global IP", func() {
+		t.awaitIngressIPStatusAllocated(globalIngressIPName)
+		Expect(t.getGlobalIngressIPStatus(globalIngressIPName).AllocatedIP).To(Equal(globalIP1))
+		awaitPacketFilterRules(globalIP1)
+	})
+
+	Context("and the requested global IP is already allocated", func() {
+		BeforeEach(func() {
+			Expect(t.pool.Reserve(globalIP1)).To(Succeed())
+		})
+
+		It("should add an appropriate Status condition", func() {
+			t.awaitStatusConditions(t.globalIngressIPs, globalIngressIPName, metav1.Condition{
+				Type:   string(submarinerv1.GlobalEgressIPAllocated),
+				Status: metav1.ConditionFalse,
+				Reason: "RequestedIPUnavailable",
+			})
+		})
+	})
+
+	Context("and then removed and recreated", func() {
+		JustBeforeEach(func() {
+			t.awaitIngressIPStatusAllocated(globalIngressIPName)
+
+			Expect(t.globalIngressIPs.Delete(context.TODO(), globalIngressIPName, metav1.DeleteOptions{})).To(Succeed())
+			awaitNoPacketFilterRules(globalIP1)
+		})
+
+		It("should retain the global IP and reallocate it", func() {
+			Expect(t.pool.Reserve(globalIP1)).ToNot(Succeed())
+
+			recreated := from.DeepCopy()
+			recreated.Spec.RetentionPeriod = ingressIP.Spec.RetentionPeriod
+			t.createGlobalIngressIP(recreated)
+
+			Eventually(func() string {
+				return t.getGlobalIngressIPStatus(globalIngressIPName).AllocatedIP
+			}, 3*time.Second).Should(Equal(globalIP1))
+			awaitPacketFilterRules(globalIP1)
+		})
+
+		Context("and the controller is restarted", func() {
+			JustBeforeEach(func() {
+				t.controller.Stop()
+
+				var err error
+
+				t.pool, err = ippool.NewMultiPool([]string{t.globalCIDR}, metrics.GlobalnetMetricsReporter)
+				Expect(err).To(Succeed())
+
+				t.start()
+			})
+
+			It("should still retain the global IP and reallocate it", func() {
+				Expect(t.pool.Reserve(globalIP1)).ToNot(Succeed())
+
+				recreated := from.DeepCopy()
+				t.createGlobalIngressIP(recreated)
+
+				Eventually(func() string {
+					return t.getGlobalIngressIPStatus(globalIngressIPName).AllocatedIP
+				}, 3*time.Second).Should(Equal(globalIP1))
+			})
+		})
+	})
+}
+
 func testGlobalIngressIPCreatedClusterIPSvc(t *globalIngressIPControllerTestDriver, ingressIP *submarinerv1.GlobalIngressIP) {
 	var service *corev1.Service
 
@@ -510,7 +639,7 @@ func newGlobalIngressIPControllerDriver() *globalIngressIPControllerTestDriver {
 
 		var err error
 
-		t.pool, err = ipam.NewIPPool(t.globalCIDR, metrics.GlobalnetMetricsReporter)
+		t.pool, err = ippool.NewMultiPool([]string{t.globalCIDR}, metrics.GlobalnetMetricsReporter)
 		Expect(err).To(Succeed())
 	})
 
@@ -530,7 +659,7 @@ func (t *globalIngressIPControllerTestDriver) start() syncer.Interface {
 		SourceClient: t.dynClient,
 		RestMapper:   t.restMapper,
 		Scheme:       t.scheme,
-	}, t.pool)
+	}, t.pool, namespace)
 	t.controller = controller
 
 	Expect(err).To(Succeed())
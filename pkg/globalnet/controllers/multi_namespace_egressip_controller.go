@@ -0,0 +1,244 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/federate"
+	"github.com/submariner-io/admiral/pkg/syncer"
+	"github.com/submariner-io/admiral/pkg/util"
+	"github.com/submariner-io/admiral/pkg/watcher"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/globalnet/controllers/packetfilter"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
+	"github.com/submariner-io/submariner/pkg/globalnet/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func NewMultiNamespaceGlobalEgressIPController(config *syncer.ResourceSyncerConfig, pool *ippool.MultiPool) (Interface, error) {
+	// We'll panic if config is nil, this is intentional
+	var err error
+
+	logger.Info("Creating MultiNamespaceGlobalEgressIP controller")
+
+	pfIface, err := packetfilter.New()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating the packetfilter Interface handler")
+	}
+
+	controller := &multiNamespaceGlobalEgressIPController{
+		baseIPAllocationController: newBaseIPAllocationController(pool, pfIface),
+		namespaceWatchers:          map[string]*egressNamespaceWatcher{},
+		watcherConfig: watcher.Config{
+			RestMapper: config.RestMapper,
+			Client:     config.SourceClient,
+			Scheme:     config.Scheme,
+		},
+	}
+
+	_, gvr, err := util.ToUnstructuredResource(&submarinerv1.MultiNamespaceGlobalEgressIP{}, config.RestMapper)
+	if err != nil {
+		return nil, errors.Wrap(err, "error converting resource")
+	}
+
+	list, err := config.SourceClient.Resource(*gvr).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing the resources")
+	}
+
+	federator := federate.NewUpdateStatusFederator(config.SourceClient, config.RestMapper, corev1.NamespaceAll)
+
+	for i := range list.Items {
+		err = controller.reserveAllocatedIPs(federator, &list.Items[i], func(reservedIPs []string) error {
+			metrics.RecordAllocateGlobalEgressIPs(pool.GetCIDRFor(reservedIPs), len(reservedIPs))
+
+			specObj := util.GetSpec(&list.Items[i])
+			spec := &submarinerv1.MultiNamespaceGlobalEgressIPSpec{}
+			_ = runtime.DefaultUnstructuredConverter.FromUnstructured(specObj.(map[string]interface{}), spec)
+			key := list.Items[i].GetName()
+
+			return controller.programGlobalEgressRules(key, reservedIPs, spec.PodSelector, controller.newNamedSet(key))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	controller.resourceSyncer, err = syncer.NewResourceSyncer(&syncer.ResourceSyncerConfig{
+		Name:                "MultiNamespaceGlobalEgressIP syncer",
+		ResourceType:        &submarinerv1.MultiNamespaceGlobalEgressIP{},
+		SourceClient:        config.SourceClient,
+		SourceNamespace:     corev1.NamespaceAll,
+		RestMapper:          config.RestMapper,
+		Federator:           federator,
+		Scheme:              config.Scheme,
+		Transform:           controller.process,
+		ResourcesEquivalent: syncer.AreSpecsEquivalent,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the syncer")
+	}
+
+	return controller, nil
+}
+
+func (c *multiNamespaceGlobalEgressIPController) Stop() {
+	c.baseController.Stop()
+
+	c.Lock()
+	defer c.Unlock()
+
+	for _, namespaceWatcher := range c.namespaceWatchers {
+		namespaceWatcher.stop()
+	}
+}
+
+func (c *multiNamespaceGlobalEgressIPController) process(from runtime.Object, numRequeues int, op syncer.Operation) (runtime.Object, bool) {
+	egressIP := from.(*submarinerv1.MultiNamespaceGlobalEgressIP)
+
+	numberOfIPs := 1
+	if egressIP.Spec.NumberOfIPs != nil {
+		numberOfIPs = *egressIP.Spec.NumberOfIPs
+	}
+
+	key := egressIP.Name
+
+	logger.Infof("Processing %sd MultiNamespaceGlobalEgressIP %q, NumberOfIPs: %d, NamespaceSelector: %#v, PodSelector: %#v, "+
+		"Status: %#v", op, key, numberOfIPs, egressIP.Spec.NamespaceSelector, egressIP.Spec.PodSelector, egressIP.Status)
+
+	switch op {
+	case syncer.Create, syncer.Update:
+		prevStatus := egressIP.Status
+
+		trimAllocatedStatusCondition(&egressIP.Status.Conditions)
+
+		requeue := false
+		if c.validateNumberOfIPs(numberOfIPs, &egressIP.Status) {
+			requeue = c.onCreateOrUpdate(key, numberOfIPs, egressIP, numRequeues)
+		}
+
+		return checkStatusChanged(&prevStatus, &egressIP.Status, egressIP), requeue
+	case syncer.Delete:
+		return nil, c.onDelete(key, numRequeues, egressIP)
+	}
+
+	return nil, false
+}
+
+func (c *multiNamespaceGlobalEgressIPController) onCreateOrUpdate(key string, numberOfIPs int,
+	egressIP *submarinerv1.MultiNamespaceGlobalEgressIP, numRequeues int,
+) bool {
+	namedSet := c.newNamedSet(key)
+
+	requeue := false
+	if numberOfIPs != len(egressIP.Status.AllocatedIPs) {
+		requeue = c.flushGlobalEgressRulesAndReleaseIPs(key, namedSet.Name(), numRequeues, egressIP.Spec.PodSelector,
+			egressIP.Status.AllocatedIPs)
+	}
+
+	return requeue || c.allocateGlobalIPs(key, numberOfIPs, egressIP.Spec.PodSelector, &egressIP.Status, namedSet) ||
+		!c.createNamespaceWatcher(key, namedSet, numberOfIPs, egressIP)
+}
+
+func (c *multiNamespaceGlobalEgressIPController) createNamespaceWatcher(key string, namedSet packetfilter.NamedSet, numberOfIPs int,
+	egressIP *submarinerv1.MultiNamespaceGlobalEgressIP,
+) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	prevWatcher, found := c.namespaceWatchers[key]
+	if found {
+		if !equality.Semantic.DeepEqual(prevWatcher.namespaceSelector, egressIP.Spec.NamespaceSelector) ||
+			!equality.Semantic.DeepEqual(prevWatcher.podSelector, egressIP.Spec.PodSelector) {
+			logger.Errorf(nil, "NamespaceSelector and PodSelector for %q cannot be updated after creation", key)
+
+			meta.SetStatusCondition(&egressIP.Status.Conditions, metav1.Condition{
+				Type:    string(submarinerv1.GlobalEgressIPUpdated),
+				Status:  metav1.ConditionFalse,
+				Reason:  "SelectorUpdateNotSupported",
+				Message: "The NamespaceSelector and PodSelector cannot be updated after creation",
+			})
+		}
+
+		return true
+	}
+
+	if numberOfIPs == 0 {
+		return true
+	}
+
+	namespaceWatcher, err := startEgressNamespaceWatcher(key, namedSet, &c.watcherConfig, egressIP.Spec.NamespaceSelector,
+		egressIP.Spec.PodSelector)
+	if err != nil {
+		logger.Errorf(err, "Error starting namespace watcher for %q", key)
+		return false
+	}
+
+	c.namespaceWatchers[key] = namespaceWatcher
+	namespaceWatcher.allocatedIPs = egressIP.Status.AllocatedIPs
+
+	logger.Infof("Started namespace watcher for %q", key)
+
+	return true
+}
+
+func (c *multiNamespaceGlobalEgressIPController) onDelete(key string, numRequeues int,
+	egressIP *submarinerv1.MultiNamespaceGlobalEgressIP,
+) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	namespaceWatcher, found := c.namespaceWatchers[key]
+	if found {
+		namespaceWatcher.stop()
+		delete(c.namespaceWatchers, key)
+
+		if len(egressIP.Status.AllocatedIPs) == 0 && len(namespaceWatcher.allocatedIPs) > 0 {
+			logger.Warningf("Using the cached allocatedIPs %q to delete the packetfilter rules for %q",
+				namespaceWatcher.allocatedIPs, key)
+			egressIP.Status.AllocatedIPs = namespaceWatcher.allocatedIPs
+		}
+	}
+
+	namedSet := c.newNamedSet(key)
+
+	requeue := c.flushGlobalEgressRulesAndReleaseIPs(key, namedSet.Name(), numRequeues, egressIP.Spec.PodSelector,
+		egressIP.Status.AllocatedIPs)
+	if requeue {
+		return requeue
+	}
+
+	if err := namedSet.Destroy(); err != nil {
+		logger.Errorf(err, "Error destroying the ipSet %q for %q", namedSet.Name(), key)
+
+		if shouldRequeue(numRequeues) {
+			return true
+		}
+	}
+
+	logger.Infof("Deleted the packetfilter/namedset rules for %q", key)
+
+	return false
+}
@@ -0,0 +1,210 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	fakeDynClient "github.com/submariner-io/admiral/pkg/fake"
+	"github.com/submariner-io/admiral/pkg/syncer"
+	"github.com/submariner-io/admiral/pkg/syncer/test"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/globalnet/constants"
+	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
+	"github.com/submariner-io/submariner/pkg/globalnet/metrics"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const multiNamespaceEgressIPName = "tenant-a"
+
+var _ = Describe("MultiNamespaceGlobalEgressIP controller", func() {
+	t := newMultiNamespaceGlobalEgressIPControllerTestDriver()
+
+	var (
+		egressIP    *submarinerv1.MultiNamespaceGlobalEgressIP
+		egressChain string
+		tenantNS    *corev1.Namespace
+		otherNS     *corev1.Namespace
+	)
+
+	BeforeEach(func() {
+		egressIP = &submarinerv1.MultiNamespaceGlobalEgressIP{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: multiNamespaceEgressIPName,
+			},
+			Spec: submarinerv1.MultiNamespaceGlobalEgressIPSpec{
+				NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}},
+			},
+		}
+
+		egressChain = constants.SmGlobalnetEgressChainForNamespace
+
+		tenantNS = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "tenant-a-ns",
+			Labels: map[string]string{"tenant": "a"},
+		}}
+
+		otherNS = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "other-ns",
+		}}
+	})
+
+	When("a MultiNamespaceGlobalEgressIP is created", func() {
+		var ipSet string
+
+		JustBeforeEach(func() {
+			test.CreateResource(t.namespaces, tenantNS)
+			test.CreateResource(t.namespaces, otherNS)
+			test.CreateResource(t.multiNamespaceEIPs, egressIP)
+
+			t.awaitEgressIPStatusAllocated(t.multiNamespaceEIPs, multiNamespaceEgressIPName, 1)
+			ipSet = t.awaitPacketFilterRules(egressChain,
+				getGlobalEgressIPStatus(t.multiNamespaceEIPs, multiNamespaceEgressIPName).AllocatedIPs...)
+		})
+
+		It("should add the IPs of the Pods in the selected namespaces to the IP set", func() {
+			pod := newPod(tenantNS.Name)
+			t.createPod(pod)
+			t.pFilter.AwaitEntry(ipSet, pod.Status.PodIP)
+		})
+
+		It("should not add the IPs of the Pods in other namespaces to the IP set", func() {
+			pod := newPod(otherNS.Name)
+			t.createPod(pod)
+			t.pFilter.AwaitNoEntry(ipSet, pod.Status.PodIP)
+		})
+
+		Context("and a namespace is subsequently labeled to match", func() {
+			It("should add the IPs of its Pods to the IP set", func() {
+				pod := newPod(otherNS.Name)
+				pod.Status.PodIP = "5.6.7.8"
+				t.createPod(pod)
+
+				otherNS.Labels = tenantNS.Labels
+				test.UpdateResource(t.namespaces, otherNS)
+
+				t.pFilter.AwaitEntry(ipSet, pod.Status.PodIP)
+			})
+		})
+
+		Context("and a selected namespace is relabeled to no longer match", func() {
+			It("should remove the IPs of its Pods from the IP set", func() {
+				pod := newPod(tenantNS.Name)
+				t.createPod(pod)
+				t.pFilter.AwaitEntry(ipSet, pod.Status.PodIP)
+
+				tenantNS.Labels = nil
+				test.UpdateResource(t.namespaces, tenantNS)
+
+				t.pFilter.AwaitEntryDeleted(ipSet, pod.Status.PodIP)
+			})
+		})
+
+		Context("with a Pod selector", func() {
+			BeforeEach(func() {
+				egressChain = constants.SmGlobalnetEgressChainForPods
+				egressIP.Spec.PodSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}
+			})
+
+			It("should only add the IPs of the matching Pods in the selected namespaces to the IP set", func() {
+				pod := newPod(tenantNS.Name)
+				pod.Labels = egressIP.Spec.PodSelector.MatchLabels
+				t.createPod(pod)
+				t.pFilter.AwaitEntry(ipSet, pod.Status.PodIP)
+
+				pod = newPod(tenantNS.Name)
+				pod.Name = "other"
+				pod.Status.PodIP = "5.6.7.8"
+				t.createPod(pod)
+				t.pFilter.AwaitNoEntry(ipSet, pod.Status.PodIP)
+			})
+		})
+
+		Context("and then deleted", func() {
+			It("should release the allocated global IPs and clean up the IP tables", func() {
+				allocatedIPs := getGlobalEgressIPStatus(t.multiNamespaceEIPs, multiNamespaceEgressIPName).AllocatedIPs
+
+				Expect(t.multiNamespaceEIPs.Delete(context.TODO(), multiNamespaceEgressIPName, metav1.DeleteOptions{})).To(Succeed())
+				t.awaitIPsReleasedFromPool(allocatedIPs...)
+				t.pFilter.AwaitSetDeleted(ipSet)
+				t.awaitNoPacketFilterRules(egressChain, allocatedIPs...)
+				t.watches.AwaitWatchStopped("namespaces")
+			})
+		})
+	})
+
+	When("a MultiNamespaceGlobalEgressIP with allocated IPs exists on startup", func() {
+		BeforeEach(func() {
+			egressIP.Status.AllocatedIPs = []string{globalIP1, globalIP2}
+			test.CreateResource(t.multiNamespaceEIPs, egressIP)
+		})
+
+		It("should reserve the previously allocated IPs and program the IP table rules", func() {
+			t.verifyIPsReservedInPool(egressIP.Status.AllocatedIPs...)
+			t.awaitPacketFilterRules(egressChain, egressIP.Status.AllocatedIPs...)
+		})
+	})
+})
+
+type multiNamespaceGlobalEgressIPControllerTestDriver struct {
+	*globalEgressIPControllerTestDriver
+}
+
+func newMultiNamespaceGlobalEgressIPControllerTestDriver() *multiNamespaceGlobalEgressIPControllerTestDriver {
+	t := &multiNamespaceGlobalEgressIPControllerTestDriver{globalEgressIPControllerTestDriver: &globalEgressIPControllerTestDriver{}}
+
+	BeforeEach(func() {
+		t.testDriverBase = newTestDriverBase()
+		t.testDriverBase.initChains()
+
+		var err error
+
+		t.pool, err = ippool.NewMultiPool([]string{t.globalCIDR}, metrics.GlobalnetMetricsReporter)
+		Expect(err).To(Succeed())
+
+		t.watches = fakeDynClient.NewWatchReactor(&t.dynClient.Fake)
+	})
+
+	JustBeforeEach(func() {
+		t.start()
+	})
+
+	AfterEach(func() {
+		t.testDriverBase.afterEach()
+	})
+
+	return t
+}
+
+func (t *multiNamespaceGlobalEgressIPControllerTestDriver) start() {
+	var err error
+
+	t.controller, err = controllers.NewMultiNamespaceGlobalEgressIPController(&syncer.ResourceSyncerConfig{
+		SourceClient: t.dynClient,
+		RestMapper:   t.restMapper,
+		Scheme:       t.scheme,
+	}, t.pool)
+
+	Expect(err).To(Succeed())
+	Expect(t.controller.Start()).To(Succeed())
+}
@@ -20,6 +20,7 @@ package packetfilter
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
@@ -35,8 +36,8 @@ type Interface interface {
 	RemoveClusterEgressRules(sourceIP, snatIP, globalNetIPTableMark string) error
 	AddIngressRulesForHeadlessSvc(globalIP, podIP string, targetType TargetType) error
 	RemoveIngressRulesForHeadlessSvc(globalIP, podIP string, targetType TargetType) error
-	AddIngressRulesForHealthCheck(cniIfaceIP, globalIP string) error
-	RemoveIngressRulesForHealthCheck(cniIfaceIP, globalIP string) error
+	AddIngressRulesForGateway(cniIfaceIP, globalIP string, ports []int) error
+	RemoveIngressRulesForGateway(cniIfaceIP, globalIP string, ports []int) error
 	AddEgressRulesForHeadlessSvc(key, sourceIP, snatIP, globalNetIPTableMark string, targetType TargetType) error
 	RemoveEgressRulesForHeadlessSvc(key, sourceIP, snatIP, globalNetIPTableMark string, targetType TargetType) error
 
@@ -147,28 +148,53 @@ func (i *pfilter) RemoveIngressRulesForHeadlessSvc(globalIP, ip string, targetTy
 	return i.deleteNATRule(constants.SmGlobalnetIngressChain, &ruleSpec)
 }
 
-func (i *pfilter) AddIngressRulesForHealthCheck(cniIfaceIP, globalIP string) error {
-	ruleSpec := packetfilter.Rule{
-		Proto:    packetfilter.RuleProtoICMP,
-		DestCIDR: globalIP,
-		DnatCIDR: cniIfaceIP,
-		Action:   packetfilter.RuleActionDNAT,
+func (i *pfilter) AddIngressRulesForGateway(cniIfaceIP, globalIP string, ports []int) error {
+	for _, ruleSpec := range gatewayIngressRules(cniIfaceIP, globalIP, ports) {
+		logger.V(log.DEBUG).Infof("Installing packetfilter ingress rules for Node: %q", ruleSpec)
+
+		if err := i.appendNATRule(constants.SmGlobalnetIngressChain, ruleSpec); err != nil {
+			return err
+		}
 	}
-	logger.V(log.DEBUG).Infof("Installing packetfilter ingress rules for Node: %q", &ruleSpec)
 
-	return i.appendNATRule(constants.SmGlobalnetIngressChain, &ruleSpec)
+	return nil
 }
 
-func (i *pfilter) RemoveIngressRulesForHealthCheck(cniIfaceIP, globalIP string) error {
-	ruleSpec := packetfilter.Rule{
+func (i *pfilter) RemoveIngressRulesForGateway(cniIfaceIP, globalIP string, ports []int) error {
+	for _, ruleSpec := range gatewayIngressRules(cniIfaceIP, globalIP, ports) {
+		logger.V(log.DEBUG).Infof("Deleting packetfilter ingress rules for Node: %+v", ruleSpec)
+
+		if err := i.deleteNATRule(constants.SmGlobalnetIngressChain, ruleSpec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// gatewayIngressRules DNATs the ICMP health check probes and the UDP and TCP traffic to the given ports, sent to the global
+// IP of the Gateway, to its CNI interface IP.
+func gatewayIngressRules(cniIfaceIP, globalIP string, ports []int) []*packetfilter.Rule {
+	rules := []*packetfilter.Rule{{
 		Proto:    packetfilter.RuleProtoICMP,
 		DestCIDR: globalIP,
 		DnatCIDR: cniIfaceIP,
 		Action:   packetfilter.RuleActionDNAT,
+	}}
+
+	for _, port := range ports {
+		for _, proto := range []packetfilter.RuleProto{packetfilter.RuleProtoUDP, packetfilter.RuleProtoTCP} {
+			rules = append(rules, &packetfilter.Rule{
+				Proto:    proto,
+				DestCIDR: globalIP,
+				DPort:    strconv.Itoa(port),
+				DnatCIDR: cniIfaceIP,
+				Action:   packetfilter.RuleActionDNAT,
+			})
+		}
 	}
-	logger.V(log.DEBUG).Infof("Deleting packetfilter ingress rules for Node: %+v", &ruleSpec)
 
-	return i.deleteNATRule(constants.SmGlobalnetIngressChain, &ruleSpec)
+	return rules
 }
 
 func (i *pfilter) AddEgressRulesForHeadlessSvc(key, sourceIP, snatIP, globalNetIPTableMark string, targetType TargetType) error {
@@ -0,0 +1,154 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/resource"
+	"github.com/submariner-io/admiral/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RetainedIPsConfigMapName is the name of the ConfigMap persisting the global IPs retained for deleted GlobalIngressIPs.
+const RetainedIPsConfigMapName = "submariner-globalnet-retained-ips"
+
+const retainedIPsKey = "retained"
+
+type retainedIPs struct {
+	IPs    []string    `json:"ips"`
+	Expiry metav1.Time `json:"expiry"`
+}
+
+// loadRetainedIPs holds the global IPs retained before a restart again for the rest of their retention period. It must be
+// called after the IPs allocated to the existing GlobalIngressIPs are reserved.
+func (c *globalIngressIPController) loadRetainedIPs(ctx context.Context) error {
+	obj, err := c.configMaps.Get(ctx, RetainedIPsConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "error retrieving the retained global IPs")
+	}
+
+	data, _, _ := unstructured.NestedStringMap(obj.Object, "data")
+
+	retained := map[string]retainedIPs{}
+
+	if data[retainedIPsKey] != "" {
+		if err := json.Unmarshal([]byte(data[retainedIPsKey]), &retained); err != nil {
+			return errors.Wrap(err, "error unmarshalling the retained global IPs")
+		}
+	}
+
+	c.retainedMutex.Lock()
+	defer c.retainedMutex.Unlock()
+
+	for key, r := range retained {
+		remaining := time.Until(r.Expiry.Time)
+		if remaining <= 0 {
+			continue
+		}
+
+		if err := c.pool.Reserve(r.IPs...); err != nil {
+			logger.Warningf("Unable to reserve the global IPs %v retained for %q: %v", r.IPs, key, err)
+			continue
+		}
+
+		logger.Infof("Retaining global IP %q for %q for the remaining %v", r.IPs, key, remaining)
+
+		c.pool.Hold(key, remaining, r.IPs...)
+		c.retained[key] = r
+	}
+
+	return c.writeRetainedIPs(ctx)
+}
+
+// retainIPs holds the given global IPs for the given key for the retention period and persists them so they're still retained
+// after a restart.
+func (c *globalIngressIPController) retainIPs(key string, retention time.Duration, ips ...string) {
+	c.retainedMutex.Lock()
+	defer c.retainedMutex.Unlock()
+
+	c.pool.Hold(key, retention, ips...)
+	c.retained[key] = retainedIPs{IPs: ips, Expiry: metav1.NewTime(time.Now().Add(retention))}
+
+	if err := c.writeRetainedIPs(context.TODO()); err != nil {
+		logger.Errorf(err, "Error persisting the global IPs retained for %q", key)
+	}
+}
+
+// reclaimIPs returns the global IPs retained for the given key, if any, and no longer persists them.
+func (c *globalIngressIPController) reclaimIPs(key string) []string {
+	c.retainedMutex.Lock()
+	defer c.retainedMutex.Unlock()
+
+	ips := c.pool.Reclaim(key)
+
+	if _, ok := c.retained[key]; ok {
+		delete(c.retained, key)
+
+		if err := c.writeRetainedIPs(context.TODO()); err != nil {
+			logger.Errorf(err, "Error persisting the global IPs retained after reclaiming those for %q", key)
+		}
+	}
+
+	return ips
+}
+
+func (c *globalIngressIPController) writeRetainedIPs(ctx context.Context) error {
+	for key, r := range c.retained {
+		if time.Now().After(r.Expiry.Time) {
+			delete(c.retained, key)
+		}
+	}
+
+	data, err := json.Marshal(c.retained)
+	if err != nil {
+		return errors.Wrap(err, "error marshalling the retained global IPs")
+	}
+
+	configMap := resource.MustToUnstructured(&corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: RetainedIPsConfigMapName,
+		},
+		Data: map[string]string{
+			retainedIPsKey: string(data),
+		},
+	})
+
+	_, err = util.CreateOrUpdate[*unstructured.Unstructured](ctx, resource.ForDynamic(c.configMaps), configMap,
+		func(existing *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			existing.Object["data"] = configMap.Object["data"]
+			return existing, nil
+		})
+
+	return errors.Wrap(err, "error writing the retained global IPs ConfigMap")
+}
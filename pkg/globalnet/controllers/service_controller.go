@@ -100,7 +100,7 @@ func (c *serviceController) Start() error {
 func (c *serviceController) process(from runtime.Object, _ int, op syncer.Operation) (runtime.Object, bool) {
 	service := from.(*corev1.Service)
 
-	if service.Spec.Type != corev1.ServiceTypeClusterIP {
+	if _, supported := serviceTargetTypes[service.Spec.Type]; !supported {
 		return nil, false
 	}
 
@@ -19,11 +19,14 @@ limitations under the License.
 package controllers
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/federate"
 	"github.com/submariner-io/admiral/pkg/syncer"
 	"github.com/submariner-io/admiral/pkg/util"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	gnpacketfilter "github.com/submariner-io/submariner/pkg/globalnet/controllers/packetfilter"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -123,6 +126,13 @@ func (c *serviceExportController) Start() error {
 	return nil
 }
 
+// serviceTargetTypes maps the supported exported Service types to their GlobalIngressIP target.
+var serviceTargetTypes = map[corev1.ServiceType]submarinerv1.TargetType{
+	corev1.ServiceTypeClusterIP:    submarinerv1.ClusterIPService,
+	corev1.ServiceTypeLoadBalancer: submarinerv1.LoadBalancerService,
+	corev1.ServiceTypeNodePort:     submarinerv1.NodePortService,
+}
+
 func (c *serviceExportController) process(from runtime.Object, _ int, op syncer.Operation) (runtime.Object, bool) {
 	serviceExport := from.(*mcsv1a1.ServiceExport)
 
@@ -146,7 +156,8 @@ func (c *serviceExportController) onCreate(serviceExport *mcsv1a1.ServiceExport)
 		return nil, true
 	}
 
-	if service.Spec.Type != corev1.ServiceTypeClusterIP {
+	targetType, supported := serviceTargetTypes[service.Spec.Type]
+	if !supported {
 		logger.Infof("Exported Service %q with type %q is not supported", key, service.Spec.Type)
 
 		return nil, false
@@ -178,17 +189,38 @@ func (c *serviceExportController) onCreate(serviceExport *mcsv1a1.ServiceExport)
 			Namespace: serviceExport.Namespace,
 		},
 		Spec: submarinerv1.GlobalIngressIPSpec{
-			Target:     submarinerv1.ClusterIPService,
+			Target:     targetType,
 			ServiceRef: &corev1.LocalObjectReference{Name: serviceExport.Name},
 		},
 	}
 
+	setRequestedIPAndRetention(key, service, ingressIP)
+
 	logger.Infof("Creating GlobalIngressIP object %s/%s, TargetRef: %q, %q ", serviceExport.Namespace,
-		serviceExport.Name, submarinerv1.ClusterIPService, serviceExport.Name)
+		serviceExport.Name, targetType, serviceExport.Name)
 
 	return ingressIP, false
 }
 
+func setRequestedIPAndRetention(key string, service *corev1.Service,
+	ingressIP *submarinerv1.GlobalIngressIP,
+) {
+	ingressIP.Spec.RequestedIP = service.GetAnnotations()[constants.RequestedGlobalIP]
+
+	retention, ok := service.GetAnnotations()[constants.GlobalIPRetentionPeriod]
+	if !ok {
+		return
+	}
+
+	d, err := time.ParseDuration(retention)
+	if err != nil || d < 0 {
+		logger.Warningf("Ignoring invalid %q annotation value %q on Service %q", constants.GlobalIPRetentionPeriod, retention, key)
+		return
+	}
+
+	ingressIP.Spec.RetentionPeriod = &metav1.Duration{Duration: d}
+}
+
 func (c *serviceExportController) onDelete(serviceExport *mcsv1a1.ServiceExport) (runtime.Object, bool) {
 	key, _ := cache.MetaNamespaceKeyFunc(serviceExport)
 
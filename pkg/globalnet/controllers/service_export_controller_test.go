@@ -24,11 +24,12 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/submariner-io/admiral/pkg/ipam"
 	"github.com/submariner-io/admiral/pkg/syncer"
 	"github.com/submariner-io/admiral/pkg/syncer/test"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/globalnet/constants"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
 	"github.com/submariner-io/submariner/pkg/globalnet/metrics"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
 	corev1 "k8s.io/api/core/v1"
@@ -87,12 +88,54 @@ func testClusterIPService() {
 		})
 	})
 
-	When("an unsupported type Service is exported", func() {
+	When("a LoadBalancer Service is exported", func() {
+		BeforeEach(func() {
+			service.Spec.Type = corev1.ServiceTypeLoadBalancer
+			t.createServiceExport(t.createService(service))
+		})
+
+		It("should create an appropriate GlobalIngressIP", func() {
+			ingressIP := t.awaitGlobalIngressIP(service.Name)
+			Expect(ingressIP.Spec.Target).To(Equal(submarinerv1.LoadBalancerService))
+			Expect(ingressIP.Spec.ServiceRef).ToNot(BeNil())
+			Expect(ingressIP.Spec.ServiceRef.Name).To(Equal(service.Name))
+		})
+	})
+
+	When("a Service with requested global IP and retention annotations is exported", func() {
+		BeforeEach(func() {
+			service.SetAnnotations(map[string]string{
+				constants.RequestedGlobalIP:       "169.254.1.10",
+				constants.GlobalIPRetentionPeriod: "1h",
+			})
+			t.createServiceExport(t.createService(service))
+		})
+
+		It("should set them on the GlobalIngressIP", func() {
+			ingressIP := t.awaitGlobalIngressIP(service.Name)
+			Expect(ingressIP.Spec.RequestedIP).To(Equal("169.254.1.10"))
+			Expect(ingressIP.Spec.RetentionPeriod).To(Equal(&metav1.Duration{Duration: time.Hour}))
+		})
+	})
+
+	When("a NodePort Service is exported", func() {
 		BeforeEach(func() {
 			service.Spec.Type = corev1.ServiceTypeNodePort
 			t.createServiceExport(t.createService(service))
 		})
 
+		It("should create an appropriate GlobalIngressIP", func() {
+			ingressIP := t.awaitGlobalIngressIP(service.Name)
+			Expect(ingressIP.Spec.Target).To(Equal(submarinerv1.NodePortService))
+		})
+	})
+
+	When("an unsupported type Service is exported", func() {
+		BeforeEach(func() {
+			service.Spec.Type = corev1.ServiceTypeExternalName
+			t.createServiceExport(t.createService(service))
+		})
+
 		It("should not create a GlobalIngressIP", func() {
 			t.ensureNoGlobalIngressIP(service.Name)
 		})
@@ -466,7 +509,7 @@ func newServiceExportControllerTestDriver() *serviceExportControllerTestDriver {
 func (t *serviceExportControllerTestDriver) start() (*syncer.ResourceSyncerConfig, *controllers.IngressPodControllers, syncer.Interface) {
 	var err error
 
-	t.pool, err = ipam.NewIPPool(t.globalCIDR, metrics.GlobalnetMetricsReporter)
+	t.pool, err = ippool.NewMultiPool([]string{t.globalCIDR}, metrics.GlobalnetMetricsReporter)
 	Expect(err).To(Succeed())
 
 	config := &syncer.ResourceSyncerConfig{
@@ -24,15 +24,16 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/submariner-io/admiral/pkg/ipam"
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/admiral/pkg/syncer"
 	"github.com/submariner-io/admiral/pkg/watcher"
 	"github.com/submariner-io/submariner/pkg/event"
 	pfIface "github.com/submariner-io/submariner/pkg/globalnet/controllers/packetfilter"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -90,6 +91,17 @@ type Specification struct {
 	GlobalCIDR  []string
 	MetricsPort int `default:"32781"`
 	Uninstall   bool
+	// PacketFilterDriver overrides the auto-detected packet filter driver ("iptables" or "nftables").
+	PacketFilterDriver string `split_words:"true"`
+	// GlobalIPPoolUsageThreshold is the percentage of used global IPs in a global CIDR past which a condition is raised on the
+	// local Gateway.
+	GlobalIPPoolUsageThreshold int `default:"80" split_words:"true"`
+	// HealthCheckProbePort is the port of the Gateway's UDP and TCP health check probes that's DNAT'ed from its global IP.
+	HealthCheckProbePort int `split_words:"true"`
+	// BenchmarkEnabled and BenchmarkPort configure the port of the Gateway's benchmark server that's DNAT'ed from its
+	// global IP.
+	BenchmarkEnabled bool `split_words:"true"`
+	BenchmarkPort    int  `split_words:"true"`
 }
 
 type LeaderElectionConfig struct {
@@ -147,7 +159,7 @@ type baseSyncerController struct {
 
 type baseIPAllocationController struct {
 	*baseSyncerController
-	pool    *ipam.IPPool
+	pool    *ippool.MultiPool
 	pfIface pfIface.Interface
 }
 
@@ -166,6 +178,27 @@ type egressPodWatcher struct {
 	allocatedIPs []string
 }
 
+type multiNamespaceGlobalEgressIPController struct {
+	*baseIPAllocationController
+	sync.Mutex
+	namespaceWatchers map[string]*egressNamespaceWatcher
+	watcherConfig     watcher.Config
+}
+
+type egressNamespaceWatcher struct {
+	sync.Mutex
+	name              string
+	stopCh            chan struct{}
+	namedSet          packetfilter.NamedSet
+	namespaceSelector metav1.LabelSelector
+	selector          labels.Selector
+	podSelector       *metav1.LabelSelector
+	allocatedIPs      []string
+	watcherConfig     *watcher.Config
+	pods              dynamic.NamespaceableResourceInterface
+	podWatchers       map[string]*egressPodWatcher
+}
+
 type clusterGlobalEgressIPController struct {
 	*baseIPAllocationController
 	localSubnets []string
@@ -173,8 +206,11 @@ type clusterGlobalEgressIPController struct {
 
 type globalIngressIPController struct {
 	*baseIPAllocationController
-	services dynamic.NamespaceableResourceInterface
-	scheme   *runtime.Scheme
+	services      dynamic.NamespaceableResourceInterface
+	scheme        *runtime.Scheme
+	configMaps    dynamic.ResourceInterface
+	retainedMutex sync.Mutex
+	retained      map[string]retainedIPs
 }
 
 type serviceExportController struct {
@@ -196,10 +232,20 @@ type serviceController struct {
 	gipSyncer           syncer.Interface
 }
 
+type globalCIDRController struct {
+	*baseSyncerController
+	pool           *ippool.MultiPool
+	gateways       dynamic.ResourceInterface
+	hostName       string
+	threshold      int
+	usageCheckTime time.Duration
+}
+
 type gatewayController struct {
 	*baseIPAllocationController
 	hostName string
 	cniIP    string
+	ports    []int
 }
 
 type ingressPodController struct {
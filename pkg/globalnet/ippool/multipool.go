@@ -0,0 +1,309 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ippool
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/ipam"
+)
+
+// MultiPool allocates global IPs from an ordered list of CIDRs, each backed by its own ipam.IPPool. Allocations are satisfied by
+// the first CIDR with sufficient available IPs, so a CIDR appended later is only used once the preceding ones run out.
+type MultiPool struct {
+	mutex   sync.RWMutex
+	pools   []*ipam.IPPool
+	metrics ipam.MetricsReporter
+	// Guards held; always acquired before mutex.
+	holdMutex sync.Mutex
+	held      map[string]*heldIPs
+}
+
+type heldIPs struct {
+	ips   []string
+	timer *time.Timer
+}
+
+// Usage describes the allocation state of a single CIDR of a MultiPool.
+type Usage struct {
+	CIDR      string
+	Size      int
+	Available int
+}
+
+func NewMultiPool(cidrs []string, metrics ipam.MetricsReporter) (*MultiPool, error) {
+	if len(cidrs) == 0 {
+		return nil, errors.New("at least one CIDR is required")
+	}
+
+	p := &MultiPool{metrics: metrics, held: map[string]*heldIPs{}}
+
+	for _, cidr := range cidrs {
+		if _, err := p.AddCIDR(cidr); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// AddCIDR appends the given CIDR to the pool, if not already present, and returns whether it was added.
+func (p *MultiPool) AddCIDR(cidr string) (bool, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false, errors.Wrapf(err, "error parsing CIDR %q", cidr)
+	}
+
+	for _, pool := range p.pools {
+		if pool.GetCIDR() == cidr {
+			return false, nil
+		}
+
+		_, existing, _ := net.ParseCIDR(pool.GetCIDR())
+		if existing.Contains(network.IP) || network.Contains(existing.IP) {
+			return false, fmt.Errorf("CIDR %q overlaps with existing CIDR %q", cidr, pool.GetCIDR())
+		}
+	}
+
+	pool, err := ipam.NewIPPool(cidr, p.metrics)
+	if err != nil {
+		return false, errors.Wrapf(err, "error creating the IP pool for CIDR %q", cidr)
+	}
+
+	p.pools = append(p.pools, pool)
+
+	return true, nil
+}
+
+// GetCIDRs returns the CIDRs of the pool in allocation order.
+func (p *MultiPool) GetCIDRs() []string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.cidrs()
+}
+
+// GetCIDRFor returns the CIDR containing the first of the given IPs, or the first CIDR if none is given or found.
+func (p *MultiPool) GetCIDRFor(ips []string) string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if len(ips) > 0 {
+		if pool := p.poolFor(ips[0]); pool != nil {
+			return pool.GetCIDR()
+		}
+	}
+
+	return p.pools[0].GetCIDR()
+}
+
+// Allocate allocates the given number of contiguous IPs from the first CIDR able to satisfy the request.
+func (p *MultiPool) Allocate(num int) ([]string, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	var err error
+
+	for _, pool := range p.pools {
+		var ips []string
+
+		ips, err = pool.Allocate(num)
+		if err == nil {
+			return ips, nil
+		}
+	}
+
+	return nil, err //nolint:wrapcheck // No need to wrap
+}
+
+func (p *MultiPool) Release(ips ...string) error {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	for _, ip := range ips {
+		pool := p.poolFor(ip)
+		if pool == nil {
+			return fmt.Errorf("released IP %s is not contained in any of the CIDRs %v", ip, p.cidrs())
+		}
+
+		if err := pool.Release(ip); err != nil {
+			return err //nolint:wrapcheck // No need to wrap
+		}
+	}
+
+	return nil
+}
+
+// Reserve reserves the given IPs, which may span several CIDRs. Either all the IPs are reserved or none are.
+func (p *MultiPool) Reserve(ips ...string) error {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	byPool := map[*ipam.IPPool][]string{}
+
+	for _, ip := range ips {
+		pool := p.poolFor(ip)
+		if pool == nil {
+			return fmt.Errorf("the requested IP %s is not contained in any of the CIDRs %v", ip, p.cidrs())
+		}
+
+		byPool[pool] = append(byPool[pool], ip)
+	}
+
+	var reserved []*ipam.IPPool
+
+	for _, pool := range p.pools {
+		if len(byPool[pool]) == 0 {
+			continue
+		}
+
+		if err := pool.Reserve(byPool[pool]...); err != nil {
+			for _, r := range reserved {
+				_ = r.Release(byPool[r]...)
+			}
+
+			return err //nolint:wrapcheck // No need to wrap
+		}
+
+		reserved = append(reserved, pool)
+	}
+
+	return nil
+}
+
+// Hold keeps the given allocated IPs reserved for the given key for the retention period, after which they're released
+// unless reclaimed via Reclaim. Any IPs previously held for the key are released.
+func (p *MultiPool) Hold(key string, retention time.Duration, ips ...string) {
+	p.holdMutex.Lock()
+	defer p.holdMutex.Unlock()
+
+	p.releaseHeld(key)
+
+	h := &heldIPs{ips: ips}
+	h.timer = time.AfterFunc(retention, func() {
+		p.holdMutex.Lock()
+		defer p.holdMutex.Unlock()
+
+		if p.held[key] == h {
+			p.releaseHeld(key)
+		}
+	})
+
+	p.held[key] = h
+}
+
+// Reclaim returns the IPs held for the given key, if any. The IPs remain allocated and are now owned by the caller.
+func (p *MultiPool) Reclaim(key string) []string {
+	p.holdMutex.Lock()
+	defer p.holdMutex.Unlock()
+
+	h, ok := p.held[key]
+	if !ok {
+		return nil
+	}
+
+	h.timer.Stop()
+	delete(p.held, key)
+
+	return h.ips
+}
+
+// Size returns the total number of available IPs across all the CIDRs.
+func (p *MultiPool) Size() int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	size := 0
+	for _, pool := range p.pools {
+		size += pool.Size()
+	}
+
+	return size
+}
+
+// GetUsage returns the allocation state of each CIDR in allocation order.
+func (p *MultiPool) GetUsage() []Usage {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	usage := make([]Usage, len(p.pools))
+
+	for i, pool := range p.pools {
+		_, network, _ := net.ParseCIDR(pool.GetCIDR())
+		ones, bits := network.Mask.Size()
+
+		usage[i] = Usage{
+			CIDR:      pool.GetCIDR(),
+			Size:      (1 << (bits - ones)) - 2,
+			Available: pool.Size(),
+		}
+	}
+
+	return usage
+}
+
+// UsedPercent returns the percentage of the IPs of the CIDR that are allocated.
+func (u *Usage) UsedPercent() int {
+	if u.Size == 0 {
+		return 0
+	}
+
+	return (u.Size - u.Available) * 100 / u.Size
+}
+
+func (p *MultiPool) releaseHeld(key string) {
+	h, ok := p.held[key]
+	if !ok {
+		return
+	}
+
+	h.timer.Stop()
+	delete(p.held, key)
+
+	_ = p.Release(h.ips...)
+}
+
+func (p *MultiPool) poolFor(ip string) *ipam.IPPool {
+	parsed := net.ParseIP(ip)
+
+	for _, pool := range p.pools {
+		_, network, _ := net.ParseCIDR(pool.GetCIDR())
+		if network.Contains(parsed) {
+			return pool
+		}
+	}
+
+	return nil
+}
+
+func (p *MultiPool) cidrs() []string {
+	cidrs := make([]string, len(p.pools))
+	for i, pool := range p.pools {
+		cidrs[i] = pool.GetCIDR()
+	}
+
+	return cidrs
+}
@@ -0,0 +1,141 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ippool_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner/pkg/globalnet/ippool"
+)
+
+const (
+	cidr1 = "242.0.0.0/29"
+	cidr2 = "242.0.1.0/29"
+)
+
+var _ = Describe("MultiPool", func() {
+	var pool *ippool.MultiPool
+
+	BeforeEach(func() {
+		var err error
+
+		pool, err = ippool.NewMultiPool([]string{cidr1}, nil)
+		Expect(err).To(Succeed())
+	})
+
+	Context("Allocate", func() {
+		It("should allocate from the CIDRs in order", func() {
+			_, err := pool.AddCIDR(cidr2)
+			Expect(err).To(Succeed())
+
+			ips, err := pool.Allocate(6)
+			Expect(err).To(Succeed())
+			Expect(pool.GetCIDRFor(ips)).To(Equal(cidr1))
+
+			ips, err = pool.Allocate(2)
+			Expect(err).To(Succeed())
+			Expect(pool.GetCIDRFor(ips)).To(Equal(cidr2))
+		})
+
+		It("should fail when none of the CIDRs can satisfy the request", func() {
+			_, err := pool.Allocate(7)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("AddCIDR", func() {
+		It("should ignore a CIDR that was already added", func() {
+			added, err := pool.AddCIDR(cidr1)
+			Expect(err).To(Succeed())
+			Expect(added).To(BeFalse())
+			Expect(pool.GetCIDRs()).To(Equal([]string{cidr1}))
+		})
+
+		It("should reject an overlapping CIDR", func() {
+			_, err := pool.AddCIDR("242.0.0.0/24")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Reserve and Release", func() {
+		BeforeEach(func() {
+			_, err := pool.AddCIDR(cidr2)
+			Expect(err).To(Succeed())
+		})
+
+		It("should handle IPs spanning several CIDRs", func() {
+			Expect(pool.Reserve("242.0.0.1", "242.0.1.1")).To(Succeed())
+			Expect(pool.Size()).To(Equal(10))
+
+			Expect(pool.Release("242.0.0.1", "242.0.1.1")).To(Succeed())
+			Expect(pool.Size()).To(Equal(12))
+		})
+
+		It("should reserve none of the IPs if one is already allocated", func() {
+			Expect(pool.Reserve("242.0.1.1")).To(Succeed())
+			Expect(pool.Reserve("242.0.0.1", "242.0.1.1")).ToNot(Succeed())
+			Expect(pool.Size()).To(Equal(11))
+		})
+
+		It("should fail for an IP not contained in any CIDR", func() {
+			Expect(pool.Reserve("10.0.0.1")).ToNot(Succeed())
+			Expect(pool.Release("10.0.0.1")).ToNot(Succeed())
+		})
+	})
+
+	Context("Hold and Reclaim", func() {
+		It("should keep the held IPs allocated until reclaimed", func() {
+			Expect(pool.Reserve("242.0.0.1")).To(Succeed())
+
+			pool.Hold("ns/svc", time.Hour, "242.0.0.1")
+			Expect(pool.Size()).To(Equal(5))
+			Expect(pool.Reclaim("other/svc")).To(BeEmpty())
+
+			Expect(pool.Reclaim("ns/svc")).To(Equal([]string{"242.0.0.1"}))
+			Expect(pool.Size()).To(Equal(5))
+			Expect(pool.Reclaim("ns/svc")).To(BeEmpty())
+		})
+
+		It("should release the held IPs after the retention period", func() {
+			Expect(pool.Reserve("242.0.0.1")).To(Succeed())
+
+			pool.Hold("ns/svc", 100*time.Millisecond, "242.0.0.1")
+			Eventually(pool.Size).Should(Equal(6))
+			Expect(pool.Reclaim("ns/svc")).To(BeEmpty())
+		})
+	})
+
+	Context("GetUsage", func() {
+		It("should return the usage of each CIDR", func() {
+			_, err := pool.AddCIDR(cidr2)
+			Expect(err).To(Succeed())
+
+			_, err = pool.Allocate(3)
+			Expect(err).To(Succeed())
+
+			usage := pool.GetUsage()
+			Expect(usage).To(HaveLen(2))
+			Expect(usage[0]).To(Equal(ippool.Usage{CIDR: cidr1, Size: 6, Available: 3}))
+			Expect(usage[0].UsedPercent()).To(Equal(50))
+			Expect(usage[1].UsedPercent()).To(Equal(0))
+		})
+	})
+})
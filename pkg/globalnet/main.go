@@ -35,8 +35,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/cidr"
 	submarinerClientset "github.com/submariner-io/submariner/pkg/client/clientset/versioned"
 	"github.com/submariner-io/submariner/pkg/globalnet/controllers"
-	"github.com/submariner-io/submariner/pkg/packetfilter"
-	"github.com/submariner-io/submariner/pkg/packetfilter/iptables"
+	"github.com/submariner-io/submariner/pkg/packetfilter/configure"
 	"github.com/submariner-io/submariner/pkg/versions"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/dynamic"
@@ -86,8 +85,15 @@ func main() {
 	dynClient, err := dynamic.NewForConfig(cfg)
 	logger.FatalOnError(err, "Unable to create dynamic client")
 
-	// Set packetfilter driver to iptables. Once nftables is available, we'll check which driver is supported.
-	packetfilter.SetNewDriverFn(iptables.New)
+	pfDriver, err := configure.Configure(spec.PacketFilterDriver)
+	logger.FatalOnError(err, "Error configuring the packet filter driver")
+
+	if pfDriver == configure.DriverNFTables && !spec.Uninstall {
+		err = configure.MigrateFromIPTables(controllers.PacketFilterMigrationSpec())
+		if err != nil {
+			logger.Errorf(err, "Error migrating the packet filter rules from iptables")
+		}
+	}
 
 	if spec.Uninstall {
 		logger.Info("Uninstalling submariner-globalnet")
@@ -0,0 +1,200 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ikev2
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	nonceLength = 32
+	prfLength   = sha256.Size
+	// KeyLength is the length of the AES-GCM keys, including the 4 byte salt, for both the IKE SA and the child SAs.
+	KeyLength = aesKeyLengthBits/8 + saltLength
+	// ICVBits is the length in bits of the AES-GCM integrity check value.
+	ICVBits = 128
+
+	saltLength = 4
+	ivLength   = 8
+	icvLength  = ICVBits / 8
+
+	authKeyPad = "Key Pad for IKEv2"
+)
+
+func prf(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(sha256.New, key)
+
+	for _, d := range data {
+		mac.Write(d)
+	}
+
+	return mac.Sum(nil)
+}
+
+// prfPlus expands the key to the given length, per section 2.13 of RFC 7296.
+func prfPlus(key, seed []byte, length int) []byte {
+	var (
+		out []byte
+		t   []byte
+	)
+
+	for i := byte(1); len(out) < length; i++ {
+		t = prf(key, t, seed, []byte{i})
+		out = append(out, t...)
+	}
+
+	return out[:length]
+}
+
+// ikeKeys are the keys of an IKE SA. No integrity keys are needed with AES-GCM.
+type ikeKeys struct {
+	d  []byte
+	ei []byte
+	er []byte
+	pi []byte
+	pr []byte
+}
+
+// deriveIKEKeys derives the IKE SA keys, per section 2.14 of RFC 7296.
+func deriveIKEKeys(sharedSecret, nonceI, nonceR []byte, spiI, spiR uint64) *ikeKeys {
+	skeyseed := prf(append(append([]byte{}, nonceI...), nonceR...), sharedSecret)
+
+	seed := make([]byte, 0, len(nonceI)+len(nonceR)+16)
+	seed = append(seed, nonceI...)
+	seed = append(seed, nonceR...)
+	seed = binary.BigEndian.AppendUint64(seed, spiI)
+	seed = binary.BigEndian.AppendUint64(seed, spiR)
+
+	keymat := prfPlus(skeyseed, seed, 3*prfLength+2*KeyLength)
+
+	keys := &ikeKeys{}
+	for _, k := range []struct {
+		key    *[]byte
+		length int
+	}{{&keys.d, prfLength}, {&keys.ei, KeyLength}, {&keys.er, KeyLength}, {&keys.pi, prfLength}, {&keys.pr, prfLength}} {
+		*k.key = keymat[:k.length]
+		keymat = keymat[k.length:]
+	}
+
+	return keys
+}
+
+// deriveChildKeys derives the initiator to responder and responder to initiator ESP keys, per section 2.17 of RFC 7296.
+func deriveChildKeys(skD, nonceI, nonceR []byte) ([]byte, []byte) {
+	keymat := prfPlus(skD, append(append([]byte{}, nonceI...), nonceR...), 2*KeyLength)
+	return keymat[:KeyLength], keymat[KeyLength:]
+}
+
+// pskAuth computes the AUTH value for shared key authentication, per section 2.15 of RFC 7296.
+func pskAuth(psk, signedMessage, peerNonce, skP, idBody []byte) []byte {
+	return prf(prf(psk, []byte(authKeyPad)), signedMessage, peerNonce, prf(skP, idBody))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key[:len(key)-saltLength])
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating the AES cipher")
+	}
+
+	aead, err := cipher.NewGCM(block)
+
+	return aead, errors.Wrap(err, "error creating the GCM cipher")
+}
+
+// encryptMessage encodes a message whose payloads are protected by an encrypted payload, per section 3.14 of RFC 7296 and
+// RFC 5282.
+func encryptMessage(h *header, payloads []payload, key []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	first, plaintext := encodePayloads(payloads)
+	// No padding is needed for GCM, just the pad length.
+	plaintext = append(plaintext, 0)
+
+	iv := make([]byte, ivLength)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, errors.Wrap(err, "error generating the IV")
+	}
+
+	skLength := payloadHdrLen + ivLength + len(plaintext) + icvLength
+
+	h.nextPayload = payloadSK
+	h.length = uint32(headerLength + skLength) //nolint:gosec // Bounded by the message size
+
+	aad := h.encode()
+	aad = append(aad, byte(first), 0, 0, 0)
+	binary.BigEndian.PutUint16(aad[headerLength+2:], uint16(skLength)) //nolint:gosec // Bounded by the message size
+
+	nonce := append(append([]byte{}, key[len(key)-saltLength:]...), iv...)
+
+	msg := append(slices.Clone(aad), iv...)
+
+	return aead.Seal(msg, nonce, plaintext, aad), nil
+}
+
+// decryptMessage decrypts the encrypted payload of the given message and returns the inner payloads.
+func decryptMessage(b []byte, h *header, key []byte) ([]payload, error) {
+	if h.nextPayload != payloadSK {
+		return nil, errors.New("the message isn't encrypted")
+	}
+
+	if len(b) < headerLength+payloadHdrLen+ivLength+icvLength+1 {
+		return nil, errors.New("encrypted message too short")
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aad := b[:headerLength+payloadHdrLen]
+	iv := b[len(aad) : len(aad)+ivLength]
+	nonce := append(append([]byte{}, key[len(key)-saltLength:]...), iv...)
+
+	plaintext, err := aead.Open(nil, nonce, b[len(aad)+ivLength:], aad)
+	if err != nil {
+		return nil, errors.Wrap(err, "error decrypting the message")
+	}
+
+	padLength := int(plaintext[len(plaintext)-1])
+	if padLength+1 > len(plaintext) {
+		return nil, errors.New("invalid pad length")
+	}
+
+	return decodePayloads(payloadType(b[headerLength]), plaintext[:len(plaintext)-padLength-1])
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+
+	return b, errors.Wrap(err, "error generating random bytes")
+}
+
+var randReader = rand.Reader
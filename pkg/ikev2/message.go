@@ -0,0 +1,198 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ikev2
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+type exchangeType uint8
+
+const (
+	exchangeIKESAInit     exchangeType = 34
+	exchangeIKEAuth       exchangeType = 35
+	exchangeInformational exchangeType = 37
+)
+
+type payloadType uint8
+
+const (
+	payloadNone   payloadType = 0
+	payloadSA     payloadType = 33
+	payloadKE     payloadType = 34
+	payloadIDi    payloadType = 35
+	payloadIDr    payloadType = 36
+	payloadAuth   payloadType = 39
+	payloadNonce  payloadType = 40
+	payloadNotify payloadType = 41
+	payloadTSi    payloadType = 44
+	payloadTSr    payloadType = 45
+	payloadSK     payloadType = 46
+)
+
+const (
+	version         = 0x20
+	headerLength    = 28
+	payloadHdrLen   = 4
+	flagInitiator   = 0x08
+	flagResponse    = 0x20
+	maxMessageBytes = 64 * 1024
+)
+
+// header is the IKE header, per section 3.1 of RFC 7296.
+type header struct {
+	spiI        uint64
+	spiR        uint64
+	nextPayload payloadType
+	exchange    exchangeType
+	flags       uint8
+	messageID   uint32
+	length      uint32
+}
+
+func (h *header) encode() []byte {
+	b := make([]byte, headerLength)
+	binary.BigEndian.PutUint64(b[0:], h.spiI)
+	binary.BigEndian.PutUint64(b[8:], h.spiR)
+	b[16] = byte(h.nextPayload)
+	b[17] = version
+	b[18] = byte(h.exchange)
+	b[19] = h.flags
+	binary.BigEndian.PutUint32(b[20:], h.messageID)
+	binary.BigEndian.PutUint32(b[24:], h.length)
+
+	return b
+}
+
+func decodeHeader(b []byte) (*header, error) {
+	if len(b) < headerLength {
+		return nil, fmt.Errorf("message too short: %d bytes", len(b))
+	}
+
+	h := &header{
+		spiI:        binary.BigEndian.Uint64(b[0:]),
+		spiR:        binary.BigEndian.Uint64(b[8:]),
+		nextPayload: payloadType(b[16]),
+		exchange:    exchangeType(b[18]),
+		flags:       b[19],
+		messageID:   binary.BigEndian.Uint32(b[20:]),
+		length:      binary.BigEndian.Uint32(b[24:]),
+	}
+
+	if b[17] != version {
+		return nil, fmt.Errorf("unsupported IKE version 0x%x", b[17])
+	}
+
+	if int(h.length) != len(b) || h.length > maxMessageBytes {
+		return nil, fmt.Errorf("invalid message length %d for %d bytes", h.length, len(b))
+	}
+
+	return h, nil
+}
+
+// SPIs returns the initiator and responder IKE SPIs of the given message, which can be used to dispatch it to the right
+// IKE SA. It also returns whether the message is a response.
+func SPIs(b []byte) (uint64, uint64, bool, error) {
+	h, err := decodeHeader(b)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	return h.spiI, h.spiR, h.flags&flagResponse != 0, nil
+}
+
+type payload struct {
+	typ  payloadType
+	body []byte
+}
+
+// encodePayloads chains the given payloads, returning the type of the first one and the encoding.
+func encodePayloads(payloads []payload) (payloadType, []byte) {
+	var b []byte
+
+	for i := range payloads {
+		next := payloadNone
+		if i+1 < len(payloads) {
+			next = payloads[i+1].typ
+		}
+
+		hdr := make([]byte, payloadHdrLen)
+		hdr[0] = byte(next)
+		binary.BigEndian.PutUint16(hdr[2:], uint16(payloadHdrLen+len(payloads[i].body))) //nolint:gosec // Bounded by the message size
+
+		b = append(b, hdr...)
+		b = append(b, payloads[i].body...)
+	}
+
+	if len(payloads) == 0 {
+		return payloadNone, b
+	}
+
+	return payloads[0].typ, b
+}
+
+func decodePayloads(first payloadType, b []byte) ([]payload, error) {
+	var payloads []payload
+
+	for next := first; next != payloadNone; {
+		if len(b) < payloadHdrLen {
+			return nil, errors.New("truncated payload header")
+		}
+
+		length := int(binary.BigEndian.Uint16(b[2:]))
+		if length < payloadHdrLen || length > len(b) {
+			return nil, fmt.Errorf("invalid length %d for payload type %d", length, next)
+		}
+
+		payloads = append(payloads, payload{typ: next, body: b[payloadHdrLen:length]})
+
+		if next == payloadSK {
+			// The encrypted payload is always the last one.
+			break
+		}
+
+		next = payloadType(b[0])
+		b = b[length:]
+	}
+
+	return payloads, nil
+}
+
+func findPayload(payloads []payload, typ payloadType) []byte {
+	for i := range payloads {
+		if payloads[i].typ == typ {
+			return payloads[i].body
+		}
+	}
+
+	return nil
+}
+
+// encodeMessage encodes an unencrypted message.
+func encodeMessage(h *header, payloads []payload) []byte {
+	first, body := encodePayloads(payloads)
+
+	h.nextPayload = first
+	h.length = uint32(headerLength + len(body)) //nolint:gosec // Bounded by the message size
+
+	return append(h.encode(), body...)
+}
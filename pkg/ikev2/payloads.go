@@ -0,0 +1,395 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ikev2
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"net"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	protocolIKE = 1
+	protocolESP = 3
+
+	transformEncr = 1
+	transformPRF  = 2
+	transformDH   = 4
+	transformESN  = 5
+
+	encrAESGCM16     = 20
+	prfHMACSHA256    = 5
+	dhCurve25519     = 31
+	esnNone          = 0
+	keyLengthAttr    = 14
+	attrFormatTV     = 0x8000
+	aesKeyLengthBits = 256
+
+	idKeyID = 11
+
+	authSharedKeyMIC = 2
+
+	tsIPv4AddrRange = 7
+	tsIPv6AddrRange = 8
+)
+
+// NotifyType is the type of a Notify payload. Only the error types used here are defined.
+type NotifyType uint16
+
+const (
+	NotifyInvalidSyntax        NotifyType = 7
+	NotifyNoProposalChosen     NotifyType = 14
+	NotifyAuthenticationFailed NotifyType = 24
+	NotifyTSUnacceptable       NotifyType = 38
+)
+
+func (n NotifyType) Error() string {
+	switch n {
+	case NotifyInvalidSyntax:
+		return "INVALID_SYNTAX"
+	case NotifyNoProposalChosen:
+		return "NO_PROPOSAL_CHOSEN"
+	case NotifyAuthenticationFailed:
+		return "AUTHENTICATION_FAILED"
+	case NotifyTSUnacceptable:
+		return "TS_UNACCEPTABLE"
+	}
+
+	return fmt.Sprintf("notify type %d", uint16(n))
+}
+
+type transform struct {
+	typ       uint8
+	id        uint16
+	keyLength uint16
+}
+
+type proposal struct {
+	protocol   uint8
+	spi        []byte
+	transforms []transform
+}
+
+// The only supported suites: AES-GCM with a 16 byte ICV and a 256 bit key, HMAC-SHA256 as the PRF and Curve25519 for the
+// key exchange.
+var (
+	ikeTransforms = []transform{
+		{typ: transformEncr, id: encrAESGCM16, keyLength: aesKeyLengthBits},
+		{typ: transformPRF, id: prfHMACSHA256},
+		{typ: transformDH, id: dhCurve25519},
+	}
+
+	espTransforms = []transform{
+		{typ: transformEncr, id: encrAESGCM16, keyLength: aesKeyLengthBits},
+		{typ: transformESN, id: esnNone},
+	}
+)
+
+func encodeSA(p *proposal) []byte {
+	var transforms []byte
+
+	for i, t := range p.transforms {
+		b := make([]byte, 8)
+		if i+1 < len(p.transforms) {
+			b[0] = 3
+		}
+
+		b[4] = t.typ
+		binary.BigEndian.PutUint16(b[6:], t.id)
+
+		if t.keyLength != 0 {
+			attr := make([]byte, 4)
+			binary.BigEndian.PutUint16(attr, attrFormatTV|keyLengthAttr)
+			binary.BigEndian.PutUint16(attr[2:], t.keyLength)
+			b = append(b, attr...)
+		}
+
+		binary.BigEndian.PutUint16(b[2:], uint16(len(b))) //nolint:gosec // Bounded
+		transforms = append(transforms, b...)
+	}
+
+	b := make([]byte, 8, 8+len(p.spi)+len(transforms))
+	b[4] = 1
+	b[5] = p.protocol
+	b[6] = uint8(len(p.spi))        //nolint:gosec // Bounded
+	b[7] = uint8(len(p.transforms)) //nolint:gosec // Bounded
+	b = append(b, p.spi...)
+	b = append(b, transforms...)
+	binary.BigEndian.PutUint16(b[2:], uint16(len(b))) //nolint:gosec // Bounded
+
+	return b
+}
+
+func decodeSA(b []byte) ([]proposal, error) {
+	var proposals []proposal
+
+	for len(b) > 0 {
+		if len(b) < 8 {
+			return nil, errors.New("truncated proposal")
+		}
+
+		last := b[0] == 0
+		length := int(binary.BigEndian.Uint16(b[2:]))
+		spiSize := int(b[6])
+
+		if length < 8+spiSize || length > len(b) {
+			return nil, fmt.Errorf("invalid proposal length %d", length)
+		}
+
+		p := proposal{protocol: b[5], spi: b[8 : 8+spiSize]}
+
+		transforms, err := decodeTransforms(b[8+spiSize : length])
+		if err != nil {
+			return nil, err
+		}
+
+		p.transforms = transforms
+		proposals = append(proposals, p)
+
+		if last {
+			break
+		}
+
+		b = b[length:]
+	}
+
+	return proposals, nil
+}
+
+func decodeTransforms(b []byte) ([]transform, error) {
+	var transforms []transform
+
+	for len(b) > 0 {
+		if len(b) < 8 {
+			return nil, errors.New("truncated transform")
+		}
+
+		length := int(binary.BigEndian.Uint16(b[2:]))
+		if length < 8 || length > len(b) {
+			return nil, fmt.Errorf("invalid transform length %d", length)
+		}
+
+		t := transform{typ: b[4], id: binary.BigEndian.Uint16(b[6:])}
+
+		for attrs := b[8:length]; len(attrs) >= 4; {
+			attrType := binary.BigEndian.Uint16(attrs)
+			if attrType&attrFormatTV == 0 {
+				// Variable length attributes aren't used by any transform we support.
+				attrLen := int(binary.BigEndian.Uint16(attrs[2:]))
+				if 4+attrLen > len(attrs) {
+					return nil, errors.New("truncated transform attribute")
+				}
+
+				attrs = attrs[4+attrLen:]
+
+				continue
+			}
+
+			if attrType&^attrFormatTV == keyLengthAttr {
+				t.keyLength = binary.BigEndian.Uint16(attrs[2:])
+			}
+
+			attrs = attrs[4:]
+		}
+
+		transforms = append(transforms, t)
+		b = b[length:]
+	}
+
+	return transforms, nil
+}
+
+// selectProposal returns the first proposal for the protocol which offers all the wanted transforms.
+func selectProposal(proposals []proposal, protocol uint8, wanted []transform) (*proposal, error) {
+	for i := range proposals {
+		if proposals[i].protocol != protocol {
+			continue
+		}
+
+		if !slices.ContainsFunc(wanted, func(t transform) bool {
+			return !slices.Contains(proposals[i].transforms, t)
+		}) {
+			return &proposals[i], nil
+		}
+	}
+
+	return nil, NotifyNoProposalChosen
+}
+
+func encodeKE(group uint16, data []byte) []byte {
+	b := make([]byte, 4, 4+len(data))
+	binary.BigEndian.PutUint16(b, group)
+
+	return append(b, data...)
+}
+
+func decodeKE(b []byte) (uint16, []byte, error) {
+	if len(b) < 4 {
+		return 0, nil, errors.New("truncated KE payload")
+	}
+
+	return binary.BigEndian.Uint16(b), b[4:], nil
+}
+
+// encodeTypedData encodes the ID and AUTH payloads, which consist of a type, 3 reserved bytes and the data.
+func encodeTypedData(typ uint8, data []byte) []byte {
+	b := make([]byte, 4, 4+len(data))
+	b[0] = typ
+
+	return append(b, data...)
+}
+
+func decodeTypedData(b []byte) (uint8, []byte, error) {
+	if len(b) < 4 {
+		return 0, nil, errors.New("truncated payload")
+	}
+
+	return b[0], b[4:], nil
+}
+
+func encodeNotify(typ NotifyType) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint16(b[2:], uint16(typ))
+
+	return b
+}
+
+// notifyError returns the error type of the first error Notify payload, if any.
+func notifyError(payloads []payload) error {
+	for i := range payloads {
+		if payloads[i].typ == payloadNotify && len(payloads[i].body) >= 4 {
+			typ := NotifyType(binary.BigEndian.Uint16(payloads[i].body[2:]))
+			// Types below 16384 are errors.
+			if typ < 16384 {
+				return typ
+			}
+		}
+	}
+
+	return nil
+}
+
+func encodeTS(subnets []*net.IPNet) []byte {
+	b := make([]byte, 4)
+	b[0] = uint8(len(subnets)) //nolint:gosec // Bounded
+
+	for _, subnet := range subnets {
+		start, end := addressRange(subnet)
+
+		tsType := uint8(tsIPv4AddrRange)
+		if start.To4() == nil {
+			tsType = tsIPv6AddrRange
+		} else {
+			start, end = start.To4(), end.To4()
+		}
+
+		ts := make([]byte, 8, 8+2*len(start))
+		ts[0] = tsType
+		binary.BigEndian.PutUint16(ts[2:], uint16(8+2*len(start))) //nolint:gosec // Bounded
+		binary.BigEndian.PutUint16(ts[6:], 0xffff)
+		ts = append(ts, start...)
+		ts = append(ts, end...)
+
+		b = append(b, ts...)
+	}
+
+	return b
+}
+
+func decodeTS(b []byte) ([]*net.IPNet, error) {
+	if len(b) < 4 {
+		return nil, errors.New("truncated TS payload")
+	}
+
+	count := int(b[0])
+	b = b[4:]
+
+	subnets := make([]*net.IPNet, 0, count)
+
+	for range count {
+		if len(b) < 8 {
+			return nil, errors.New("truncated traffic selector")
+		}
+
+		length := int(binary.BigEndian.Uint16(b[2:]))
+		if length > len(b) {
+			return nil, fmt.Errorf("invalid traffic selector length %d", length)
+		}
+
+		var addrLen int
+
+		switch b[0] {
+		case tsIPv4AddrRange:
+			addrLen = net.IPv4len
+		case tsIPv6AddrRange:
+			addrLen = net.IPv6len
+		default:
+			return nil, fmt.Errorf("unsupported traffic selector type %d", b[0])
+		}
+
+		if length != 8+2*addrLen {
+			return nil, fmt.Errorf("invalid traffic selector length %d", length)
+		}
+
+		subnet, err := rangeToSubnet(b[8:8+addrLen], b[8+addrLen:length])
+		if err != nil {
+			return nil, err
+		}
+
+		subnets = append(subnets, subnet)
+		b = b[length:]
+	}
+
+	return subnets, nil
+}
+
+func addressRange(subnet *net.IPNet) (net.IP, net.IP) {
+	start := subnet.IP.Mask(subnet.Mask)
+	end := make(net.IP, len(start))
+
+	for i := range start {
+		end[i] = start[i] | ^subnet.Mask[i]
+	}
+
+	return start, end
+}
+
+// rangeToSubnet converts an address range to the equivalent CIDR. Only ranges which correspond to a CIDR are supported,
+// as that's all we ever send.
+func rangeToSubnet(start, end net.IP) (*net.IPNet, error) {
+	bits := len(start) * 8
+	size := new(big.Int).Sub(new(big.Int).SetBytes(end), new(big.Int).SetBytes(start))
+	size.Add(size, big.NewInt(1))
+
+	hostBits := size.BitLen() - 1
+	if size.Sign() <= 0 || new(big.Int).Lsh(big.NewInt(1), uint(hostBits)).Cmp(size) != 0 { //nolint:gosec // Non-negative
+		return nil, fmt.Errorf("address range %s-%s isn't a CIDR", start, end)
+	}
+
+	subnet := &net.IPNet{IP: slices.Clone(start), Mask: net.CIDRMask(bits-hostBits, bits)}
+	if !subnet.IP.Mask(subnet.Mask).Equal(subnet.IP) {
+		return nil, fmt.Errorf("address range %s-%s isn't a CIDR", start, end)
+	}
+
+	return subnet, nil
+}
@@ -0,0 +1,575 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ikev2
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/hmac"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// HalfOpenTimeout is how long the responder keeps the state of an exchange, so retransmitted requests can be answered.
+	HalfOpenTimeout = 30 * time.Second
+
+	// MaxHalfOpenExchanges is the maximum number of exchanges whose state the responder keeps. IKE_SA_INIT requests
+	// received beyond it are dropped until earlier exchanges expire.
+	MaxHalfOpenExchanges = 256
+)
+
+// Peer is the configuration for negotiating a child SA with a remote gateway.
+type Peer struct {
+	// ID is the remote gateway's identity.
+	ID       string
+	LocalTS  []*net.IPNet
+	RemoteTS []*net.IPNet
+}
+
+// ChildSA is a negotiated pair of ESP SAs.
+type ChildSA struct {
+	InboundSPI  uint32
+	OutboundSPI uint32
+	InboundKey  []byte
+	OutboundKey []byte
+}
+
+type initiatorState int
+
+const (
+	initSent initiatorState = iota
+	authSent
+	established
+)
+
+// Initiator negotiates an IKE SA and a child SA with a remote responder, using the IKE_SA_INIT and IKE_AUTH exchanges.
+type Initiator struct {
+	psk          []byte
+	localID      string
+	peer         *Peer
+	state        initiatorState
+	spiI         uint64
+	spiR         uint64
+	nonceI       []byte
+	nonceR       []byte
+	dhKey        *ecdh.PrivateKey
+	espSPI       uint32
+	initRequest  []byte
+	initResponse []byte
+	keys         *ikeKeys
+	request      []byte
+}
+
+// NewInitiator creates an Initiator and its IKE_SA_INIT request.
+func NewInitiator(psk []byte, localID string, peer *Peer) (*Initiator, error) {
+	i := &Initiator{
+		psk:     psk,
+		localID: localID,
+		peer:    peer,
+	}
+
+	var err error
+
+	if i.spiI, err = randomSPI(); err != nil {
+		return nil, err
+	}
+
+	if i.nonceI, err = randomBytes(nonceLength); err != nil {
+		return nil, err
+	}
+
+	if i.espSPI, err = randomESPSPI(); err != nil {
+		return nil, err
+	}
+
+	if i.dhKey, err = ecdh.X25519().GenerateKey(randReader); err != nil {
+		return nil, errors.Wrap(err, "error generating the DH key")
+	}
+
+	i.initRequest = encodeMessage(&header{spiI: i.spiI, exchange: exchangeIKESAInit, flags: flagInitiator}, []payload{
+		{typ: payloadSA, body: encodeSA(&proposal{protocol: protocolIKE, transforms: ikeTransforms})},
+		{typ: payloadKE, body: encodeKE(dhCurve25519, i.dhKey.PublicKey().Bytes())},
+		{typ: payloadNonce, body: i.nonceI},
+	})
+	i.request = i.initRequest
+
+	return i, nil
+}
+
+// SPI returns the initiator's IKE SPI, which identifies the exchange.
+func (i *Initiator) SPI() uint64 {
+	return i.spiI
+}
+
+// Request returns the pending request, to be sent or retransmitted until a response is received.
+func (i *Initiator) Request() []byte {
+	return i.request
+}
+
+// HandleResponse processes a response. Once the IKE_AUTH exchange completes, the negotiated child SA is returned.
+// Otherwise the next request is available via Request.
+func (i *Initiator) HandleResponse(b []byte) (*ChildSA, error) {
+	h, err := decodeHeader(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.flags&flagResponse == 0 || h.spiI != i.spiI {
+		return nil, errors.New("unexpected message")
+	}
+
+	switch i.state {
+	case initSent:
+		if h.exchange != exchangeIKESAInit || h.messageID != 0 {
+			return nil, errors.New("unexpected IKE_SA_INIT response")
+		}
+
+		return nil, i.handleInitResponse(b, h)
+	case authSent:
+		if h.exchange != exchangeIKEAuth || h.messageID != 1 || h.spiR != i.spiR {
+			return nil, errors.New("unexpected IKE_AUTH response")
+		}
+
+		return i.handleAuthResponse(b, h)
+	case established:
+	}
+
+	return nil, errors.New("the exchange is already complete")
+}
+
+func (i *Initiator) handleInitResponse(b []byte, h *header) error {
+	payloads, err := decodePayloads(h.nextPayload, b[headerLength:])
+	if err != nil {
+		return err
+	}
+
+	if err := notifyError(payloads); err != nil {
+		return errors.Wrap(err, "the responder rejected the IKE_SA_INIT request")
+	}
+
+	proposals, err := decodeSA(findPayload(payloads, payloadSA))
+	if err != nil {
+		return err
+	}
+
+	if _, err := selectProposal(proposals, protocolIKE, ikeTransforms); err != nil {
+		return errors.Wrap(err, "the responder chose an unsupported IKE proposal")
+	}
+
+	sharedSecret, err := computeSharedSecret(i.dhKey, findPayload(payloads, payloadKE))
+	if err != nil {
+		return err
+	}
+
+	i.nonceR = findPayload(payloads, payloadNonce)
+	if len(i.nonceR) < 16 {
+		return errors.New("missing or invalid responder nonce")
+	}
+
+	i.spiR = h.spiR
+	i.initResponse = b
+	i.keys = deriveIKEKeys(sharedSecret, i.nonceI, i.nonceR, i.spiI, i.spiR)
+
+	idBody := encodeTypedData(idKeyID, []byte(i.localID))
+
+	espSPI := binary.BigEndian.AppendUint32(nil, i.espSPI)
+
+	i.request, err = encryptMessage(&header{spiI: i.spiI, spiR: i.spiR, exchange: exchangeIKEAuth, flags: flagInitiator, messageID: 1},
+		[]payload{
+			{typ: payloadIDi, body: idBody},
+			{typ: payloadAuth, body: encodeTypedData(authSharedKeyMIC, pskAuth(i.psk, i.initRequest, i.nonceR, i.keys.pi, idBody))},
+			{typ: payloadSA, body: encodeSA(&proposal{protocol: protocolESP, spi: espSPI, transforms: espTransforms})},
+			{typ: payloadTSi, body: encodeTS(i.peer.LocalTS)},
+			{typ: payloadTSr, body: encodeTS(i.peer.RemoteTS)},
+		}, i.keys.ei)
+	if err != nil {
+		return err
+	}
+
+	i.state = authSent
+
+	return nil
+}
+
+func (i *Initiator) handleAuthResponse(b []byte, h *header) (*ChildSA, error) {
+	payloads, err := decryptMessage(b, h, i.keys.er)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := notifyError(payloads); err != nil {
+		return nil, errors.Wrap(err, "the responder rejected the IKE_AUTH request")
+	}
+
+	idBody := findPayload(payloads, payloadIDr)
+
+	_, id, err := decodeTypedData(idBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(id) != i.peer.ID {
+		return nil, fmt.Errorf("unexpected responder identity %q", id)
+	}
+
+	_, auth, err := decodeTypedData(findPayload(payloads, payloadAuth))
+	if err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal(auth, pskAuth(i.psk, i.initResponse, i.nonceI, i.keys.pr, idBody)) {
+		return nil, NotifyAuthenticationFailed
+	}
+
+	proposals, err := decodeSA(findPayload(payloads, payloadSA))
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := selectProposal(proposals, protocolESP, espTransforms)
+	if err != nil || len(p.spi) != 4 {
+		return nil, errors.New("the responder chose an unsupported ESP proposal")
+	}
+
+	if err := verifyTS(payloads, i.peer.LocalTS, i.peer.RemoteTS); err != nil {
+		return nil, err
+	}
+
+	keyIR, keyRI := deriveChildKeys(i.keys.d, i.nonceI, i.nonceR)
+
+	i.state = established
+	i.request = nil
+
+	return &ChildSA{
+		InboundSPI:  i.espSPI,
+		OutboundSPI: binary.BigEndian.Uint32(p.spi),
+		InboundKey:  keyRI,
+		OutboundKey: keyIR,
+	}, nil
+}
+
+// Responder responds to IKE_SA_INIT and IKE_AUTH exchanges from initiators.
+type Responder struct {
+	mutex   sync.Mutex
+	psk     []byte
+	localID string
+	lookup  func(id string) *Peer
+	sas     map[uint64]*responderSA
+}
+
+type responderSA struct {
+	spiR         uint64
+	nonceI       []byte
+	nonceR       []byte
+	initRequest  []byte
+	initResponse []byte
+	keys         *ikeKeys
+	authResponse []byte
+	created      time.Time
+}
+
+// NewResponder creates a Responder. The lookup function returns the configuration for the initiator with the given
+// identity, or nil if it's unknown.
+func NewResponder(psk []byte, localID string, lookup func(id string) *Peer) *Responder {
+	return &Responder{
+		psk:     psk,
+		localID: localID,
+		lookup:  lookup,
+		sas:     map[uint64]*responderSA{},
+	}
+}
+
+// HandleRequest processes a request and returns the response to send. Once an IKE_AUTH exchange completes, the negotiated
+// child SA and the initiator's Peer configuration are also returned. The returned response may be non-nil even if an
+// error is returned, to notify the initiator of the error.
+func (r *Responder) HandleRequest(b []byte) ([]byte, *ChildSA, *Peer, error) {
+	h, err := decodeHeader(b)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if h.flags&flagResponse != 0 || h.flags&flagInitiator == 0 {
+		return nil, nil, nil, errors.New("unexpected message")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	switch {
+	case h.exchange == exchangeIKESAInit && h.messageID == 0:
+		response, err := r.handleInitRequest(b, h)
+		return response, nil, nil, err
+	case h.exchange == exchangeIKEAuth && h.messageID == 1:
+		return r.handleAuthRequest(b, h)
+	}
+
+	return nil, nil, nil, fmt.Errorf("unsupported exchange %d with message ID %d", h.exchange, h.messageID)
+}
+
+func (r *Responder) handleInitRequest(b []byte, h *header) ([]byte, error) {
+	if sa, ok := r.sas[h.spiI]; ok && bytes.Equal(sa.initRequest, b) {
+		// Retransmission
+		return sa.initResponse, nil
+	}
+
+	for spi, sa := range r.sas {
+		if time.Since(sa.created) > HalfOpenTimeout {
+			delete(r.sas, spi)
+		}
+	}
+
+	if len(r.sas) >= MaxHalfOpenExchanges {
+		return nil, fmt.Errorf("dropping IKE_SA_INIT request: the maximum of %d half-open exchanges has been reached",
+			MaxHalfOpenExchanges)
+	}
+
+	reject := func(n NotifyType) ([]byte, error) {
+		return encodeMessage(&header{spiI: h.spiI, exchange: exchangeIKESAInit, flags: flagResponse},
+			[]payload{{typ: payloadNotify, body: encodeNotify(n)}}), n
+	}
+
+	payloads, err := decodePayloads(h.nextPayload, b[headerLength:])
+	if err != nil {
+		return reject(NotifyInvalidSyntax)
+	}
+
+	proposals, err := decodeSA(findPayload(payloads, payloadSA))
+	if err != nil {
+		return reject(NotifyInvalidSyntax)
+	}
+
+	if _, err := selectProposal(proposals, protocolIKE, ikeTransforms); err != nil {
+		return reject(NotifyNoProposalChosen)
+	}
+
+	nonceI := findPayload(payloads, payloadNonce)
+	if len(nonceI) < 16 {
+		return reject(NotifyInvalidSyntax)
+	}
+
+	dhKey, err := ecdh.X25519().GenerateKey(randReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "error generating the DH key")
+	}
+
+	sharedSecret, err := computeSharedSecret(dhKey, findPayload(payloads, payloadKE))
+	if err != nil {
+		return reject(NotifyInvalidSyntax)
+	}
+
+	sa := &responderSA{nonceI: nonceI, initRequest: b, created: time.Now()}
+
+	if sa.spiR, err = randomSPI(); err != nil {
+		return nil, err
+	}
+
+	if sa.nonceR, err = randomBytes(nonceLength); err != nil {
+		return nil, err
+	}
+
+	sa.keys = deriveIKEKeys(sharedSecret, sa.nonceI, sa.nonceR, h.spiI, sa.spiR)
+	sa.initResponse = encodeMessage(&header{spiI: h.spiI, spiR: sa.spiR, exchange: exchangeIKESAInit, flags: flagResponse},
+		[]payload{
+			{typ: payloadSA, body: encodeSA(&proposal{protocol: protocolIKE, transforms: ikeTransforms})},
+			{typ: payloadKE, body: encodeKE(dhCurve25519, dhKey.PublicKey().Bytes())},
+			{typ: payloadNonce, body: sa.nonceR},
+		})
+
+	r.sas[h.spiI] = sa
+
+	return sa.initResponse, nil
+}
+
+func (r *Responder) handleAuthRequest(b []byte, h *header) ([]byte, *ChildSA, *Peer, error) {
+	sa, ok := r.sas[h.spiI]
+	if !ok || sa.spiR != h.spiR {
+		return nil, nil, nil, errors.New("no IKE SA found for the IKE_AUTH request")
+	}
+
+	if sa.authResponse != nil {
+		// Retransmission
+		return sa.authResponse, nil, nil, nil
+	}
+
+	payloads, err := decryptMessage(b, h, sa.keys.ei)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	respHeader := func() *header {
+		return &header{spiI: h.spiI, spiR: sa.spiR, exchange: exchangeIKEAuth, flags: flagResponse, messageID: 1}
+	}
+
+	reject := func(n NotifyType, err error) ([]byte, *ChildSA, *Peer, error) {
+		delete(r.sas, h.spiI)
+
+		response, encErr := encryptMessage(respHeader(), []payload{{typ: payloadNotify, body: encodeNotify(n)}}, sa.keys.er)
+		if encErr != nil {
+			return nil, nil, nil, encErr
+		}
+
+		return response, nil, nil, err
+	}
+
+	idBody := findPayload(payloads, payloadIDi)
+
+	_, id, err := decodeTypedData(idBody)
+	if err != nil {
+		return reject(NotifyInvalidSyntax, err)
+	}
+
+	peer := r.lookup(string(id))
+	if peer == nil {
+		return reject(NotifyAuthenticationFailed, fmt.Errorf("unknown initiator identity %q", id))
+	}
+
+	_, auth, err := decodeTypedData(findPayload(payloads, payloadAuth))
+	if err != nil {
+		return reject(NotifyInvalidSyntax, err)
+	}
+
+	if !hmac.Equal(auth, pskAuth(r.psk, sa.initRequest, sa.nonceR, sa.keys.pi, idBody)) {
+		return reject(NotifyAuthenticationFailed, fmt.Errorf("authentication of initiator %q failed", id))
+	}
+
+	proposals, err := decodeSA(findPayload(payloads, payloadSA))
+	if err != nil {
+		return reject(NotifyInvalidSyntax, err)
+	}
+
+	p, err := selectProposal(proposals, protocolESP, espTransforms)
+	if err != nil || len(p.spi) != 4 {
+		return reject(NotifyNoProposalChosen, fmt.Errorf("no acceptable ESP proposal from initiator %q", id))
+	}
+
+	// The initiator's TSi are our remote selectors.
+	if err := verifyTS(payloads, peer.RemoteTS, peer.LocalTS); err != nil {
+		return reject(NotifyTSUnacceptable, errors.Wrapf(err, "unacceptable traffic selectors from initiator %q", id))
+	}
+
+	espSPI, err := randomESPSPI()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	idrBody := encodeTypedData(idKeyID, []byte(r.localID))
+
+	sa.authResponse, err = encryptMessage(respHeader(), []payload{
+		{typ: payloadIDr, body: idrBody},
+		{typ: payloadAuth, body: encodeTypedData(authSharedKeyMIC, pskAuth(r.psk, sa.initResponse, sa.nonceI, sa.keys.pr, idrBody))},
+		{typ: payloadSA, body: encodeSA(&proposal{
+			protocol: protocolESP, spi: binary.BigEndian.AppendUint32(nil, espSPI),
+			transforms: espTransforms,
+		})},
+		{typ: payloadTSi, body: findPayload(payloads, payloadTSi)},
+		{typ: payloadTSr, body: findPayload(payloads, payloadTSr)},
+	}, sa.keys.er)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	keyIR, keyRI := deriveChildKeys(sa.keys.d, sa.nonceI, sa.nonceR)
+
+	return sa.authResponse, &ChildSA{
+		InboundSPI:  espSPI,
+		OutboundSPI: binary.BigEndian.Uint32(p.spi),
+		InboundKey:  keyIR,
+		OutboundKey: keyRI,
+	}, peer, nil
+}
+
+func computeSharedSecret(key *ecdh.PrivateKey, kePayload []byte) ([]byte, error) {
+	group, data, err := decodeKE(kePayload)
+	if err != nil {
+		return nil, err
+	}
+
+	if group != dhCurve25519 {
+		return nil, fmt.Errorf("unsupported DH group %d", group)
+	}
+
+	peerKey, err := ecdh.X25519().NewPublicKey(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid KE data")
+	}
+
+	secret, err := key.ECDH(peerKey)
+
+	return secret, errors.Wrap(err, "error computing the DH shared secret")
+}
+
+// verifyTS verifies that the TSi and TSr payloads match the expected subnets.
+func verifyTS(payloads []payload, expectedTSi, expectedTSr []*net.IPNet) error {
+	for _, ts := range []struct {
+		typ      payloadType
+		expected []*net.IPNet
+	}{{payloadTSi, expectedTSi}, {payloadTSr, expectedTSr}} {
+		subnets, err := decodeTS(findPayload(payloads, ts.typ))
+		if err != nil {
+			return err
+		}
+
+		if !sameSubnets(subnets, ts.expected) {
+			return fmt.Errorf("traffic selectors %v don't match %v", subnets, ts.expected)
+		}
+	}
+
+	return nil
+}
+
+func sameSubnets(a, b []*net.IPNet) bool {
+	toStrings := func(subnets []*net.IPNet) []string {
+		s := make([]string, len(subnets))
+		for i := range subnets {
+			s[i] = subnets[i].String()
+		}
+
+		slices.Sort(s)
+
+		return s
+	}
+
+	return slices.Equal(toStrings(a), toStrings(b))
+}
+
+func randomSPI() (uint64, error) {
+	b, err := randomBytes(8)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(b) | 1, nil
+}
+
+func randomESPSPI() (uint32, error) {
+	b, err := randomBytes(4)
+	if err != nil {
+		return 0, err
+	}
+
+	// SPIs 1-255 are reserved.
+	return binary.BigEndian.Uint32(b) | 0x100, nil
+}
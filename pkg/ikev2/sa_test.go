@@ -0,0 +1,249 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ikev2_test
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner/pkg/ikev2"
+)
+
+var _ = Describe("IKEv2 exchanges", func() {
+	var (
+		initiatorPeer *ikev2.Peer
+		responderPeer *ikev2.Peer
+		initiatorPSK  []byte
+		responderPSK  []byte
+		initiator     *ikev2.Initiator
+		responder     *ikev2.Responder
+	)
+
+	BeforeEach(func() {
+		initiatorPeer = &ikev2.Peer{
+			ID:       "responder",
+			LocalTS:  subnets("10.0.0.0/16", "100.0.0.0/16"),
+			RemoteTS: subnets("10.1.0.0/16", "fd00:1::/64"),
+		}
+
+		responderPeer = &ikev2.Peer{
+			ID:       "initiator",
+			LocalTS:  subnets("fd00:1::/64", "10.1.0.0/16"),
+			RemoteTS: subnets("100.0.0.0/16", "10.0.0.0/16"),
+		}
+
+		initiatorPSK = []byte("secret")
+		responderPSK = []byte("secret")
+	})
+
+	JustBeforeEach(func() {
+		var err error
+
+		initiator, err = ikev2.NewInitiator(initiatorPSK, "initiator", initiatorPeer)
+		Expect(err).To(Succeed())
+
+		responder = ikev2.NewResponder(responderPSK, "responder", func(id string) *ikev2.Peer {
+			if id == responderPeer.ID {
+				return responderPeer
+			}
+
+			return nil
+		})
+	})
+
+	// exchange sends the initiator's pending request to the responder and returns the responder's results.
+	exchange := func() ([]byte, *ikev2.ChildSA, *ikev2.Peer, error) {
+		request := initiator.Request()
+		Expect(request).ToNot(BeNil())
+
+		return responder.HandleRequest(request)
+	}
+
+	doInitExchange := func() {
+		response, child, _, err := exchange()
+		Expect(err).To(Succeed())
+		Expect(child).To(BeNil())
+
+		child, err = initiator.HandleResponse(response)
+		Expect(err).To(Succeed())
+		Expect(child).To(BeNil())
+	}
+
+	When("both sides are configured consistently", func() {
+		It("should negotiate matching child SAs", func() {
+			doInitExchange()
+
+			response, responderChild, peer, err := exchange()
+			Expect(err).To(Succeed())
+			Expect(responderChild).ToNot(BeNil())
+			Expect(peer).To(Equal(responderPeer))
+
+			initiatorChild, err := initiator.HandleResponse(response)
+			Expect(err).To(Succeed())
+			Expect(initiatorChild).ToNot(BeNil())
+
+			Expect(initiatorChild.OutboundSPI).To(Equal(responderChild.InboundSPI))
+			Expect(initiatorChild.InboundSPI).To(Equal(responderChild.OutboundSPI))
+			Expect(initiatorChild.OutboundKey).To(Equal(responderChild.InboundKey))
+			Expect(initiatorChild.InboundKey).To(Equal(responderChild.OutboundKey))
+			Expect(initiatorChild.OutboundKey).To(HaveLen(ikev2.KeyLength))
+			Expect(initiatorChild.OutboundKey).ToNot(Equal(initiatorChild.InboundKey))
+			Expect(initiator.Request()).To(BeNil())
+		})
+
+		It("should answer retransmitted requests with the same response", func() {
+			request := initiator.Request()
+
+			response1, _, _, err := responder.HandleRequest(request)
+			Expect(err).To(Succeed())
+
+			response2, _, _, err := responder.HandleRequest(request)
+			Expect(err).To(Succeed())
+			Expect(response2).To(Equal(response1))
+
+			spiI, spiR, isResponse, err := ikev2.SPIs(response1)
+			Expect(err).To(Succeed())
+			Expect(spiI).To(Equal(initiator.SPI()))
+			Expect(spiR).ToNot(BeZero())
+			Expect(isResponse).To(BeTrue())
+		})
+	})
+
+	When("the pre-shared keys differ", func() {
+		BeforeEach(func() {
+			responderPSK = []byte("other")
+		})
+
+		It("should fail authentication", func() {
+			doInitExchange()
+
+			response, child, _, err := exchange()
+			Expect(err).To(HaveOccurred())
+			Expect(child).To(BeNil())
+
+			_, err = initiator.HandleResponse(response)
+			Expect(errors.Is(err, ikev2.NotifyAuthenticationFailed)).To(BeTrue())
+		})
+	})
+
+	When("the initiator's identity is unknown", func() {
+		BeforeEach(func() {
+			responderPeer.ID = "unknown"
+		})
+
+		It("should fail authentication", func() {
+			doInitExchange()
+
+			response, _, _, err := exchange()
+			Expect(err).To(HaveOccurred())
+
+			_, err = initiator.HandleResponse(response)
+			Expect(errors.Is(err, ikev2.NotifyAuthenticationFailed)).To(BeTrue())
+		})
+	})
+
+	When("the traffic selectors don't match", func() {
+		BeforeEach(func() {
+			responderPeer.LocalTS = subnets("10.2.0.0/16")
+		})
+
+		It("should reject the child SA", func() {
+			doInitExchange()
+
+			response, child, _, err := exchange()
+			Expect(err).To(HaveOccurred())
+			Expect(child).To(BeNil())
+
+			_, err = initiator.HandleResponse(response)
+			Expect(errors.Is(err, ikev2.NotifyTSUnacceptable)).To(BeTrue())
+		})
+	})
+
+	When("the maximum number of half-open exchanges is reached", func() {
+		var (
+			origMaxHalfOpen int
+			origTimeout     time.Duration
+		)
+
+		BeforeEach(func() {
+			origMaxHalfOpen = ikev2.MaxHalfOpenExchanges
+			origTimeout = ikev2.HalfOpenTimeout
+			ikev2.MaxHalfOpenExchanges = 2
+			ikev2.HalfOpenTimeout = 100 * time.Millisecond
+		})
+
+		AfterEach(func() {
+			ikev2.MaxHalfOpenExchanges = origMaxHalfOpen
+			ikev2.HalfOpenTimeout = origTimeout
+		})
+
+		newInitRequest := func() []byte {
+			other, err := ikev2.NewInitiator(initiatorPSK, "initiator", initiatorPeer)
+			Expect(err).To(Succeed())
+
+			return other.Request()
+		}
+
+		It("should drop new IKE_SA_INIT requests until the earlier exchanges expire", func() {
+			for range ikev2.MaxHalfOpenExchanges {
+				response, _, _, err := responder.HandleRequest(newInitRequest())
+				Expect(err).To(Succeed())
+				Expect(response).ToNot(BeNil())
+			}
+
+			request := initiator.Request()
+
+			response, _, _, err := responder.HandleRequest(request)
+			Expect(err).To(HaveOccurred())
+			Expect(response).To(BeNil())
+
+			time.Sleep(ikev2.HalfOpenTimeout * 2)
+
+			response, _, _, err = responder.HandleRequest(request)
+			Expect(err).To(Succeed())
+			Expect(response).ToNot(BeNil())
+		})
+	})
+
+	When("a message is malformed", func() {
+		It("should return an error", func() {
+			_, _, _, err := responder.HandleRequest([]byte{1, 2, 3})
+			Expect(err).To(HaveOccurred())
+
+			_, err = initiator.HandleResponse([]byte{1, 2, 3})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+func subnets(cidrs ...string) []*net.IPNet {
+	result := make([]*net.IPNet, len(cidrs))
+
+	for i := range cidrs {
+		_, subnet, err := net.ParseCIDR(cidrs[i])
+		Expect(err).To(Succeed())
+
+		result[i] = subnet
+	}
+
+	return result
+}
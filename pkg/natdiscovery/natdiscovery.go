@@ -22,6 +22,7 @@ import (
 	"math/rand/v2"
 	"net"
 	"reflect"
+	"strconv"
 	"sync"
 	"time"
 
@@ -29,7 +30,9 @@ import (
 	"github.com/submariner-io/admiral/pkg/log"
 	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/endpoint"
+	natproto "github.com/submariner-io/submariner/pkg/natdiscovery/proto"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/set"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -38,6 +41,7 @@ type Interface interface {
 	AddEndpoint(endpoint *v1.Endpoint)
 	RemoveEndpoint(endpointName string)
 	GetReadyChannel() chan *NATEndpointInfo
+	SymmetricNATDetected() bool
 }
 
 type (
@@ -54,6 +58,9 @@ type natDiscovery struct {
 	findSrcIP       findSrcIPFunction
 	serverPort      int32
 	readyChannel    chan *NATEndpointInfo
+	// The local endpoint's NAT mappings reported by the remote endpoints, keyed by remote endpoint name.
+	natMappings  map[string]string
+	symmetricNAT bool
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("NAT")}
@@ -76,6 +83,7 @@ func newNATDiscovery(localEndpoint *endpoint.Local) (*natDiscovery, error) {
 		findSrcIP:       endpoint.GetLocalIPForDestination,
 		requestCounter:  rand.Uint64(),
 		readyChannel:    make(chan *NATEndpointInfo, 100),
+		natMappings:     map[string]string{},
 	}, nil
 }
 
@@ -151,6 +159,47 @@ func (nd *natDiscovery) RemoveEndpoint(endpointName string) {
 	nd.Lock()
 	defer nd.Unlock()
 	delete(nd.remoteEndpoints, endpointName)
+
+	if _, ok := nd.natMappings[endpointName]; ok {
+		delete(nd.natMappings, endpointName)
+		nd.updateSymmetricNAT()
+	}
+}
+
+func (nd *natDiscovery) SymmetricNATDetected() bool {
+	nd.Lock()
+	defer nd.Unlock()
+
+	return nd.symmetricNAT
+}
+
+// recordNATMapping records the NAT mapping of the local endpoint reported by a remote endpoint. All the requests are sent
+// from the same local port so, if remote endpoints report different mappings, the NAT maps the local endpoint differently
+// for each destination, ie it's a symmetric NAT.
+func (nd *natDiscovery) recordNATMapping(remoteEndpointName string, reflected *natproto.IPPortPair) {
+	nd.natMappings[remoteEndpointName] = net.JoinHostPort(reflected.GetIP(), strconv.Itoa(int(reflected.GetPort())))
+	nd.updateSymmetricNAT()
+}
+
+func (nd *natDiscovery) updateSymmetricNAT() {
+	mappings := set.New[string]()
+	for _, mapping := range nd.natMappings {
+		mappings.Insert(mapping)
+	}
+
+	symmetricNAT := mappings.Len() > 1
+	if symmetricNAT == nd.symmetricNAT {
+		return
+	}
+
+	nd.symmetricNAT = symmetricNAT
+
+	if symmetricNAT {
+		logger.Warningf("Symmetric NAT detected, the local endpoint is mapped to different addresses for different remote endpoints: %v",
+			nd.natMappings)
+	} else {
+		logger.Info("Symmetric NAT no longer detected")
+	}
 }
 
 func (nd *natDiscovery) checkEndpointList() {
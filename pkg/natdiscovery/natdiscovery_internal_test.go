@@ -27,6 +27,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	natproto "github.com/submariner-io/submariner/pkg/natdiscovery/proto"
 )
 
 const (
@@ -64,6 +65,25 @@ var _ = When("a remote Endpoint is added", func() {
 		})
 
 		t.testRemoteEndpointAdded(testRemotePublicIP, natExpected)
+
+		Context("and the remote NAT remaps the source port of its responses", func() {
+			mappedPort := testRemoteNATPort + 1000
+
+			BeforeEach(func() {
+				t.remoteUDPAddr.Port = int(mappedPort)
+				t.remoteND.AddEndpoint(&t.localEndpoint)
+			})
+
+			It("should notify with the reflected port", func() {
+				var info *NATEndpointInfo
+
+				Eventually(t.readyChannel, 5).Should(Receive(&info))
+				Expect(info.UseIP).To(Equal(testRemotePublicIP))
+				Expect(info.UseNAT).To(BeTrue())
+				Expect(info.UsePort).To(Equal(mappedPort))
+				Expect(info.RemotePort(4500)).To(Equal(int32(5500)))
+			})
+		})
 	})
 
 	Context("with both the public IP and private IP set", func() {
@@ -385,3 +405,81 @@ func (t *discoveryTestDriver) testRemoteEndpointAdded(expIP string, expectNAT bo
 		Consistently(t.readyChannel).ShouldNot(Receive())
 	})
 }
+
+var _ = Describe("Symmetric NAT detection", func() {
+	var (
+		nd          *natDiscovery
+		remote1     submarinerv1.Endpoint
+		remote2     submarinerv1.Endpoint
+		requestID   uint64
+		localEP     submarinerv1.Endpoint
+		udpSentChan chan []byte
+	)
+
+	BeforeEach(func() {
+		localEP = createTestLocalEndpoint()
+		nd, udpSentChan, _ = createTestListener(&localEP)
+
+		remote1 = createTestRemoteEndpoint()
+		remote2 = createTestRemoteEndpoint()
+		remote2.Spec.CableName = "cluster-c-ep-1"
+		remote2.Spec.ClusterID = "cluster-c"
+
+		nd.AddEndpoint(&remote1)
+		nd.AddEndpoint(&remote2)
+	})
+
+	AfterEach(func() {
+		close(udpSentChan)
+	})
+
+	respond := func(remote *submarinerv1.Endpoint, reflectedPort int32) {
+		requestID++
+
+		nd.Lock()
+		remoteNAT := nd.remoteEndpoints[remote.Spec.CableName]
+		remoteNAT.lastPublicIPRequestID = requestID
+		remoteNAT.checkSent()
+		nd.Unlock()
+
+		Expect(nd.handleResponseFromAddress(&natproto.SubmarinerNATDiscoveryResponse{
+			RequestNumber:      requestID,
+			Response:           natproto.ResponseType_NAT_DETECTED,
+			Sender:             &natproto.EndpointDetails{ClusterId: remote.Spec.ClusterID, EndpointId: remote.Spec.CableName},
+			Receiver:           &natproto.EndpointDetails{ClusterId: localEP.Spec.ClusterID, EndpointId: localEP.Spec.CableName},
+			SrcIpNatDetected:   true,
+			SrcPortNatDetected: true,
+			ReceivedSrc:        &natproto.IPPortPair{IP: testLocalPublicIP, Port: reflectedPort},
+		}, &net.UDPAddr{IP: net.ParseIP(remote.Spec.PublicIP), Port: int(testRemoteNATPort)})).To(Succeed())
+	}
+
+	When("the remote endpoints observe the same NAT mapping", func() {
+		It("should not detect symmetric NAT", func() {
+			respond(&remote1, 5000)
+			respond(&remote2, 5000)
+
+			Expect(nd.SymmetricNATDetected()).To(BeFalse())
+		})
+	})
+
+	When("the remote endpoints observe different NAT mappings", func() {
+		It("should detect symmetric NAT", func() {
+			respond(&remote1, 5000)
+			Expect(nd.SymmetricNATDetected()).To(BeFalse())
+
+			respond(&remote2, 5001)
+			Expect(nd.SymmetricNATDetected()).To(BeTrue())
+		})
+
+		Context("and one of them is removed", func() {
+			It("should no longer detect symmetric NAT", func() {
+				respond(&remote1, 5000)
+				respond(&remote2, 5001)
+				Expect(nd.SymmetricNATDetected()).To(BeTrue())
+
+				nd.RemoveEndpoint(remote2.Spec.CableName)
+				Expect(nd.SymmetricNATDetected()).To(BeFalse())
+			})
+		})
+	})
+})
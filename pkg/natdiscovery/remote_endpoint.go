@@ -54,12 +54,37 @@ type remoteEndpointNAT struct {
 	lastPrivateIPRequestID uint64
 	useNAT                 bool
 	usingLoadBalancer      bool
+	// mappedIP is the source IP of the remote endpoint's NAT discovery requests as seen locally, ie the address its NAT
+	// maps it to towards the local endpoint.
+	mappedIP string
+	// mappedPort is the source port of the remote endpoint's NAT discovery requests as seen locally, if its NAT remapped
+	// it.
+	mappedPort int32
+	usePort    int32
 }
 
 type NATEndpointInfo struct {
 	Endpoint v1.Endpoint
 	UseNAT   bool
 	UseIP    string
+	// UsePort is the port the remote endpoint's NAT maps its NAT discovery port to, as reflected in the source of its NAT
+	// discovery traffic, or 0 if the NAT preserves it.
+	UsePort int32
+}
+
+// RemotePort returns the port to connect to for the given port advertised by the remote endpoint. If the remote
+// endpoint's NAT remaps its NAT discovery port, it's assumed to remap its other ports by the same offset.
+func (i *NATEndpointInfo) RemotePort(advertised int32) int32 {
+	if i.UsePort == 0 {
+		return advertised
+	}
+
+	natDiscoveryPort, err := extractNATDiscoveryPort(&i.Endpoint.Spec)
+	if err != nil {
+		return advertised
+	}
+
+	return advertised + i.UsePort - natDiscoveryPort
 }
 
 func (rn *remoteEndpointNAT) toNATEndpointInfo() *NATEndpointInfo {
@@ -67,9 +92,21 @@ func (rn *remoteEndpointNAT) toNATEndpointInfo() *NATEndpointInfo {
 		Endpoint: rn.endpoint,
 		UseNAT:   rn.useNAT,
 		UseIP:    rn.useIP,
+		UsePort:  rn.usePort,
 	}
 }
 
+// reflectedPort returns the given source port of the remote endpoint's NAT discovery traffic if it differs from its
+// advertised NAT discovery port, ie its NAT remapped it, or 0 otherwise.
+func (rn *remoteEndpointNAT) reflectedPort(port int) int32 {
+	natDiscoveryPort, err := extractNATDiscoveryPort(&rn.endpoint.Spec)
+	if err != nil || int32(port) == natDiscoveryPort { //nolint:gosec // We can safely ignore integer conversion error
+		return 0
+	}
+
+	return int32(port) //nolint:gosec // We can safely ignore integer conversion error
+}
+
 func newRemoteEndpointNAT(endpoint *v1.Endpoint) *remoteEndpointNAT {
 	rnat := &remoteEndpointNAT{
 		endpoint:       *endpoint,
@@ -110,13 +147,25 @@ func (rn *remoteEndpointNAT) useLegacyNATSettings() {
 	case rn.usingLoadBalancer:
 		rn.useNAT = true
 		rn.useIP = rn.endpoint.Spec.PublicIP
+		rn.usePort = 0
 		rn.transitionToState(selectedPublicIP)
 		logger.V(log.DEBUG).Infof("using NAT for the load balancer backed endpoint %q, using public IP %q", rn.endpoint.Spec.CableName,
 			rn.useIP)
 
+	case rn.endpoint.Spec.NATEnabled && rn.mappedIP != "" && rn.mappedIP != rn.endpoint.Spec.PublicIP:
+		// The remote endpoint's requests reached us but ours didn't reach its advertised public IP, which may be stale or
+		// wrong, so use the address its NAT actually maps it to.
+		rn.useNAT = true
+		rn.useIP = rn.mappedIP
+		rn.usePort = rn.mappedPort
+		rn.transitionToState(selectedPublicIP)
+		logger.Infof("using NAT legacy settings for endpoint %q, using the mapped IP %q and port %d seen in its requests "+
+			"instead of its public IP %q", rn.endpoint.Spec.CableName, rn.useIP, rn.usePort, rn.endpoint.Spec.PublicIP)
+
 	case rn.endpoint.Spec.NATEnabled:
 		rn.useNAT = true
 		rn.useIP = rn.endpoint.Spec.PublicIP
+		rn.usePort = 0
 		rn.transitionToState(selectedPublicIP)
 		logger.V(log.DEBUG).Infof("using NAT legacy settings for endpoint %q, using public IP %q", rn.endpoint.Spec.CableName,
 			rn.useIP)
@@ -124,6 +173,7 @@ func (rn *remoteEndpointNAT) useLegacyNATSettings() {
 	default:
 		rn.useNAT = false
 		rn.useIP = rn.endpoint.Spec.PrivateIP
+		rn.usePort = 0
 		rn.transitionToState(selectedPrivateIP)
 		logger.V(log.DEBUG).Infof("using NAT legacy settings for endpoint %q, using private IP %q", rn.endpoint.Spec.CableName,
 			rn.useIP)
@@ -155,13 +205,15 @@ func (rn *remoteEndpointNAT) checkSent() {
 	rn.lastCheck = time.Now()
 }
 
-func (rn *remoteEndpointNAT) transitionToPublicIP(remoteEndpointID string, useNAT bool) bool {
+func (rn *remoteEndpointNAT) transitionToPublicIP(remoteEndpointID string, useNAT bool, usePort int32) bool {
 	switch rn.state {
 	case waitingForResponse:
 		rn.useIP = rn.endpoint.Spec.PublicIP
 		rn.useNAT = useNAT
+		rn.usePort = usePort
 		rn.transitionToState(selectedPublicIP)
-		logger.V(log.DEBUG).Infof("selected public IP %q for endpoint %q", rn.useIP, rn.endpoint.Spec.CableName)
+		logger.V(log.DEBUG).Infof("selected public IP %q and port %d for endpoint %q", rn.useIP, rn.usePort,
+			rn.endpoint.Spec.CableName)
 
 		return true
 	case selectedPrivateIP:
@@ -180,6 +232,7 @@ func (rn *remoteEndpointNAT) transitionToPrivateIP(remoteEndpointID string, useN
 	case waitingForResponse:
 		rn.useIP = rn.endpoint.Spec.PrivateIP
 		rn.useNAT = useNAT
+		rn.usePort = 0
 		rn.transitionToState(selectedPrivateIP)
 		logger.V(log.DEBUG).Infof("selected private IP %q for endpoint %q", rn.useIP, rn.endpoint.Spec.CableName)
 
@@ -195,6 +248,7 @@ func (rn *remoteEndpointNAT) transitionToPrivateIP(remoteEndpointID string, useN
 
 		rn.useIP = rn.endpoint.Spec.PrivateIP
 		rn.useNAT = useNAT
+		rn.usePort = 0
 		rn.transitionToState(selectedPrivateIP)
 		logger.V(log.DEBUG).Infof("updated to private IP %q for endpoint %q", rn.useIP, rn.endpoint.Spec.CableName)
 
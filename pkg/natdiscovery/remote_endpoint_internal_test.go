@@ -86,6 +86,18 @@ var _ = Describe("remoteEndpointNAT", func() {
 				Expect(rnat.useIP).To(Equal(rnat.endpoint.Spec.PublicIP))
 			})
 		})
+		Context("and NAT is enabled and the remote endpoint's requests came from a different IP", func() {
+			It("should select the mapped IP", func() {
+				rnat.endpoint.Spec.NATEnabled = true
+				rnat.mappedIP = "10.20.30.40"
+				rnat.mappedPort = 5678
+				rnat.useLegacyNATSettings()
+				Expect(rnat.state).To(Equal(selectedPublicIP))
+				Expect(rnat.useIP).To(Equal("10.20.30.40"))
+				Expect(rnat.useNAT).To(BeTrue())
+				Expect(rnat.toNATEndpointInfo().UsePort).To(Equal(int32(5678)))
+			})
+		})
 		Context("and targeting a load balancer", func() {
 			It("should select the public IP and NAT", func() {
 				remoteEndpoint.Spec.BackendConfig[submarinerv1.UsingLoadBalancer] = "true"
@@ -102,7 +114,7 @@ var _ = Describe("remoteEndpointNAT", func() {
 	When("the public IP is selected but no check was sent", func() {
 		It("it should not transition the state", func() {
 			oldState := rnat.state
-			Expect(rnat.transitionToPublicIP(testRemoteEndpointName, false)).To(BeFalse())
+			Expect(rnat.transitionToPublicIP(testRemoteEndpointName, false, 0)).To(BeFalse())
 			Expect(rnat.state).To(Equal(oldState))
 			Expect(rnat.useIP).To(Equal(""))
 		})
@@ -156,7 +168,7 @@ var _ = Describe("remoteEndpointNAT", func() {
 
 		JustBeforeEach(func() {
 			rnat.checkSent()
-			Expect(rnat.transitionToPublicIP(testRemoteEndpointName, useNAT)).To(BeTrue())
+			Expect(rnat.transitionToPublicIP(testRemoteEndpointName, useNAT, 0)).To(BeTrue())
 			Expect(rnat.state).To(Equal(selectedPublicIP))
 		})
 
@@ -189,7 +201,7 @@ var _ = Describe("remoteEndpointNAT", func() {
 		Context("and the grace period has not elapsed", func() {
 			It("should use the private IP", func() {
 				rnat.checkSent()
-				Expect(rnat.transitionToPublicIP(testRemoteEndpointName, true)).To(BeTrue())
+				Expect(rnat.transitionToPublicIP(testRemoteEndpointName, true, 0)).To(BeTrue())
 				Expect(rnat.transitionToPrivateIP(testRemoteEndpointName, false)).To(BeTrue())
 				Expect(rnat.state).To(Equal(selectedPrivateIP))
 				Expect(rnat.useIP).To(Equal(rnat.endpoint.Spec.PrivateIP))
@@ -200,7 +212,7 @@ var _ = Describe("remoteEndpointNAT", func() {
 		Context("and the grace period has elapsed", func() {
 			It("should still use the public IP", func() {
 				rnat.checkSent()
-				Expect(rnat.transitionToPublicIP(testRemoteEndpointName, true)).To(BeTrue())
+				Expect(rnat.transitionToPublicIP(testRemoteEndpointName, true, 0)).To(BeTrue())
 				rnat.lastTransition = rnat.lastTransition.Add(-time.Duration(publicToPrivateFailoverTimeout))
 				Expect(rnat.transitionToPrivateIP(testRemoteEndpointName, false)).To(BeFalse())
 				Expect(rnat.state).To(Equal(selectedPublicIP))
@@ -73,6 +73,8 @@ func (nd *natDiscovery) handleRequestFromAddress(req *proto.SubmarinerNATDiscove
 		return nd.sendResponseToAddress(&response, addr)
 	}
 
+	nd.recordRemoteMapping(req.Sender.GetEndpointId(), addr)
+
 	if req.UsingSrc.GetIP() != "" && req.UsingSrc.GetIP() != addr.IP.String() {
 		logger.V(log.DEBUG).Infof("Received NAT packet from endpoint %q, cluster %q, where NAT has been detected, "+
 			"source IP changed",
@@ -107,6 +109,18 @@ func (nd *natDiscovery) handleRequestFromAddress(req *proto.SubmarinerNATDiscove
 	return nd.sendResponseToAddress(&response, addr)
 }
 
+// recordRemoteMapping records the source IP and port of a remote endpoint's request, ie the address its NAT maps it to
+// towards the local endpoint. It's used in preference to its advertised public IP if our requests to the latter time out.
+func (nd *natDiscovery) recordRemoteMapping(remoteEndpointName string, addr *net.UDPAddr) {
+	nd.Lock()
+	defer nd.Unlock()
+
+	if remoteNAT, ok := nd.remoteEndpoints[remoteEndpointName]; ok {
+		remoteNAT.mappedIP = addr.IP.String()
+		remoteNAT.mappedPort = remoteNAT.reflectedPort(addr.Port)
+	}
+}
+
 func (nd *natDiscovery) sendResponseToAddress(response *proto.SubmarinerNATDiscoveryResponse, addr *net.UDPAddr) error {
 	msgResponse := proto.SubmarinerNATDiscoveryMessage_Response{Response: response}
 	message := proto.SubmarinerNATDiscoveryMessage{Message: &msgResponse}
@@ -88,6 +88,13 @@ var _ = Describe("Request handling", func() {
 				Expect(response[0].SrcIpNatDetected).To(BeTrue())
 				Expect(response[0].SrcPortNatDetected).To(BeFalse())
 			})
+
+			It("should record the remote endpoint's mapped IP", func() {
+				remoteUDPAddr.IP = net.ParseIP(testRemotePublicIP)
+				localListener.AddEndpoint(&remoteEndpoint)
+				requestResponseFromRemoteToLocal(&remoteUDPAddr)
+				Expect(localListener.remoteEndpoints[remoteEndpoint.Spec.CableName].mappedIP).To(Equal(testRemotePublicIP))
+			})
 		})
 
 		Context("with a modified port", func() {
@@ -56,8 +56,12 @@ func (nd *natDiscovery) handleResponseFromAddress(req *proto.SubmarinerNATDiscov
 
 	// response to a PublicIP request
 	if remoteNAT.lastPublicIPRequestID == req.RequestNumber {
+		if req.SrcIpNatDetected || req.SrcPortNatDetected {
+			nd.recordNATMapping(req.GetSender().EndpointId, req.GetReceivedSrc())
+		}
+
 		useNAT := req.Response == proto.ResponseType_NAT_DETECTED
-		if !remoteNAT.transitionToPublicIP(req.GetSender().EndpointId, useNAT) {
+		if !remoteNAT.transitionToPublicIP(req.GetSender().EndpointId, useNAT, remoteNAT.reflectedPort(addr.Port)) {
 			return nil
 		}
 
@@ -47,6 +47,10 @@ type basicType struct {
 	neighbors    map[int][]netlink.Neigh
 	rules        map[int][]netlink.Rule
 	addrs        map[int][]netlink.Addr
+	xfrmPolicies []netlink.XfrmPolicy
+	xfrmStates   []netlink.XfrmState
+	qdiscs       []netlink.Qdisc
+	filters      []netlink.Filter
 	addrUpdateCh atomic.Value
 }
 
@@ -332,20 +336,49 @@ func (n *basicType) RouteGet(destination net.IP) ([]netlink.Route, error) {
 	return routes, nil
 }
 
-func (n *basicType) RouteList(link netlink.Link, _ int) ([]netlink.Route, error) {
+func (n *basicType) RouteList(link netlink.Link, family int) ([]netlink.Route, error) {
 	n.mutex.Lock()
 	defer n.mutex.Unlock()
 
 	r := n.routes[link.Attrs().Index]
-	to := make([]netlink.Route, len(r))
-	copy(to, r)
+	to := make([]netlink.Route, 0, len(r))
+
+	for i := range r {
+		if family == netlink.FAMILY_ALL || r[i].Dst == nil || ipFamily(r[i].Dst.IP) == family {
+			to = append(to, r[i])
+		}
+	}
 
 	return to, nil
 }
 
+func ipFamily(ip net.IP) int {
+	if ip.To4() != nil {
+		return netlink.FAMILY_V4
+	} else if ip.To16() != nil {
+		return netlink.FAMILY_V6
+	}
+
+	return 0
+}
+
+//nolint:gocritic // Ignore hugeParam.
+func ruleFamily(r netlink.Rule) int {
+	if r.Src != nil {
+		return ipFamily(r.Src.IP)
+	} else if r.Dst != nil {
+		return ipFamily(r.Dst.IP)
+	} else if r.Family != 0 {
+		return r.Family
+	}
+
+	// The kernel defaults to IPv4.
+	return netlink.FAMILY_V4
+}
+
 //nolint:gocritic // Ignore hugeParam.
 func ruleKey(r netlink.Rule) string {
-	k := ""
+	k := strconv.Itoa(ruleFamily(r))
 	if r.Src != nil {
 		k = r.Src.String()
 	}
@@ -361,21 +394,9 @@ func (n *basicType) RuleAdd(rule *netlink.Rule) error {
 	n.mutex.Lock()
 	defer n.mutex.Unlock()
 
-	family := func(ip net.IP) int {
-		if ip.To4() != nil {
-			return netlink.FAMILY_V4
-		} else if ip.To16() != nil {
-			return netlink.FAMILY_V6
-		}
-
-		return 0
-	}
-
 	r := *rule
-	if r.Src != nil {
-		r.Family = family(r.Src.IP)
-	} else if r.Dst != nil {
-		r.Family = family(r.Dst.IP)
+	if r.Src != nil || r.Dst != nil {
+		r.Family = ruleFamily(r)
 	}
 
 	var added bool
@@ -409,7 +430,7 @@ func (n *basicType) RuleList(family int) ([]netlink.Rule, error) {
 	var rules []netlink.Rule
 	for _, r := range n.rules {
 		for i := range r {
-			if r[i].Family == family {
+			if family == netlink.FAMILY_ALL || r[i].Family == family {
 				rules = append(rules, r[i])
 			}
 		}
@@ -418,16 +439,144 @@ func (n *basicType) RuleList(family int) ([]netlink.Rule, error) {
 	return rules, nil
 }
 
-func (n *basicType) XfrmPolicyAdd(_ *netlink.XfrmPolicy) error {
+func xfrmPolicyKey(p netlink.XfrmPolicy) string {
+	return p.Src.String() + "-" + p.Dst.String() + "-" + p.Dir.String() + "-" + strconv.Itoa(p.DstPort)
+}
+
+func (n *basicType) XfrmPolicyAdd(policy *netlink.XfrmPolicy) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	var added bool
+
+	n.xfrmPolicies, added = slices.AppendIfNotPresent(n.xfrmPolicies, *policy, xfrmPolicyKey)
+	if !added {
+		return syscall.EEXIST
+	}
+
+	return nil
+}
+
+func (n *basicType) XfrmPolicyDel(policy *netlink.XfrmPolicy) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	index := slices.IndexOf(n.xfrmPolicies, xfrmPolicyKey(*policy), xfrmPolicyKey)
+	if index < 0 {
+		return syscall.ENOENT
+	}
+
+	n.xfrmPolicies = append(n.xfrmPolicies[:index], n.xfrmPolicies[index+1:]...)
+
+	return nil
+}
+
+func (n *basicType) XfrmPolicyList(family int) ([]netlink.XfrmPolicy, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	policies := []netlink.XfrmPolicy{}
+
+	for i := range n.xfrmPolicies {
+		if family == netlink.FAMILY_ALL || n.xfrmPolicies[i].Dst == nil || ipFamily(n.xfrmPolicies[i].Dst.IP) == family {
+			policies = append(policies, n.xfrmPolicies[i])
+		}
+	}
+
+	return policies, nil
+}
+
+func xfrmStateKey(s netlink.XfrmState) string {
+	return s.Src.String() + "-" + s.Dst.String() + "-" + strconv.Itoa(s.Spi)
+}
+
+func (n *basicType) XfrmStateAdd(state *netlink.XfrmState) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	var added bool
+
+	n.xfrmStates, added = slices.AppendIfNotPresent(n.xfrmStates, *state, xfrmStateKey)
+	if !added {
+		return syscall.EEXIST
+	}
+
+	return nil
+}
+
+func (n *basicType) XfrmStateDel(state *netlink.XfrmState) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	index := slices.IndexOf(n.xfrmStates, xfrmStateKey(*state), xfrmStateKey)
+	if index < 0 {
+		return syscall.ENOENT
+	}
+
+	n.xfrmStates = append(n.xfrmStates[:index], n.xfrmStates[index+1:]...)
+
+	return nil
+}
+
+func (n *basicType) XfrmStateList(family int) ([]netlink.XfrmState, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	states := []netlink.XfrmState{}
+
+	for i := range n.xfrmStates {
+		if family == netlink.FAMILY_ALL || ipFamily(n.xfrmStates[i].Dst) == family {
+			states = append(states, n.xfrmStates[i])
+		}
+	}
+
+	return states, nil
+}
+
+func qdiscKey(q netlink.Qdisc) string {
+	return strconv.Itoa(q.Attrs().LinkIndex) + "-" + strconv.Itoa(int(q.Attrs().Parent))
+}
+
+func (n *basicType) QdiscReplace(qdisc netlink.Qdisc) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.qdiscs, _ = slices.AppendIfNotPresent(n.qdiscs, qdisc, qdiscKey)
+
 	return nil
 }
 
-func (n *basicType) XfrmPolicyDel(_ *netlink.XfrmPolicy) error {
+func filterKey(f netlink.Filter) string {
+	return strconv.Itoa(f.Attrs().LinkIndex) + "-" + strconv.Itoa(int(f.Attrs().Parent)) + "-" +
+		strconv.Itoa(int(f.Attrs().Priority)) + "-" + strconv.Itoa(int(f.Attrs().Handle))
+}
+
+func (n *basicType) FilterReplace(filter netlink.Filter) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	index := slices.IndexOf(n.filters, filterKey(filter), filterKey)
+	if index >= 0 {
+		n.filters[index] = filter
+	} else {
+		n.filters = append(n.filters, filter)
+	}
+
 	return nil
 }
 
-func (n *basicType) XfrmPolicyList(_ int) ([]netlink.XfrmPolicy, error) {
-	return []netlink.XfrmPolicy{}, nil
+func (n *basicType) FilterDel(filter netlink.Filter) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	index := slices.IndexOf(n.filters, filterKey(filter), filterKey)
+	if index < 0 {
+		return syscall.ENOENT
+	}
+
+	n.filters = append(n.filters[:index], n.filters[index+1:]...)
+
+	return nil
 }
 
 func (n *basicType) EnableLooseModeReversePathFilter(_ string) error {
@@ -627,3 +776,83 @@ func (n *NetLink) AwaitNoRule(table int, src, dst string) {
 		return n.getRule(table, src, dst)
 	}, 5).Should(BeNil(), "Rule for %v exists", table)
 }
+
+func (n *NetLink) xfrmStateList() []netlink.XfrmState {
+	states, _ := n.XfrmStateList(netlink.FAMILY_ALL)
+	return states
+}
+
+// AwaitXfrmState waits for an XFRM state from src to dst to be present and returns it.
+func (n *NetLink) AwaitXfrmState(src, dst string) *netlink.XfrmState {
+	var state *netlink.XfrmState
+
+	Eventually(func() *netlink.XfrmState {
+		state = nil
+
+		states := n.xfrmStateList()
+		for i := range states {
+			if states[i].Src.String() == src && states[i].Dst.String() == dst {
+				state = &states[i]
+			}
+		}
+
+		return state
+	}, 5).ShouldNot(BeNil(), "XFRM state from %s to %s not found", src, dst)
+
+	return state
+}
+
+func (n *NetLink) AwaitNoXfrmStates() {
+	Eventually(n.xfrmStateList, 5).Should(BeEmpty(), "Unexpected XFRM states")
+}
+
+func (n *NetLink) AwaitXfrmPolicies(count int) []netlink.XfrmPolicy {
+	var policies []netlink.XfrmPolicy
+
+	Eventually(func() []netlink.XfrmPolicy {
+		policies, _ = n.XfrmPolicyList(netlink.FAMILY_ALL)
+		return policies
+	}, 5).Should(HaveLen(count), "Unexpected number of XFRM policies")
+
+	return policies
+}
+
+// AwaitQdisc waits for a qdisc of the given type to be present on the given link.
+func (n *NetLink) AwaitQdisc(linkIndex int, qdiscType string) {
+	Eventually(func() []string {
+		n.basic().mutex.Lock()
+		defer n.basic().mutex.Unlock()
+
+		var types []string
+
+		for _, q := range n.basic().qdiscs {
+			if q.Attrs().LinkIndex == linkIndex {
+				types = append(types, q.Type())
+			}
+		}
+
+		return types
+	}, 5).Should(ContainElement(qdiscType), "Qdisc %q not found on link %d", qdiscType, linkIndex)
+}
+
+// AwaitFilters waits for the given number of tc filters to be present on the given link and returns them.
+func (n *NetLink) AwaitFilters(linkIndex, count int) []netlink.Filter {
+	var filters []netlink.Filter
+
+	Eventually(func() []netlink.Filter {
+		n.basic().mutex.Lock()
+		defer n.basic().mutex.Unlock()
+
+		filters = nil
+
+		for _, f := range n.basic().filters {
+			if f.Attrs().LinkIndex == linkIndex {
+				filters = append(filters, f)
+			}
+		}
+
+		return filters
+	}, 5).Should(HaveLen(count), "Unexpected number of filters on link %d", linkIndex)
+
+	return filters
+}
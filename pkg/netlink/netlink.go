@@ -32,6 +32,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -58,6 +60,12 @@ type Basic interface {
 	XfrmPolicyAdd(policy *netlink.XfrmPolicy) error
 	XfrmPolicyDel(policy *netlink.XfrmPolicy) error
 	XfrmPolicyList(family int) ([]netlink.XfrmPolicy, error)
+	XfrmStateAdd(state *netlink.XfrmState) error
+	XfrmStateDel(state *netlink.XfrmState) error
+	XfrmStateList(family int) ([]netlink.XfrmState, error)
+	QdiscReplace(qdisc netlink.Qdisc) error
+	FilterReplace(filter netlink.Filter) error
+	FilterDel(filter netlink.Filter) error
 	EnableLooseModeReversePathFilter(interfaceName string) error
 	EnsureLooseModeIsConfigured(interfaceName string) error
 	EnableForwarding(interfaceName string) error
@@ -75,6 +83,12 @@ type Interface interface {
 	DeleteDestinationRoutes(destIPs []net.IPNet, linkIndex, tableID int) error
 }
 
+// EncodedFilter is a tc filter whose options the netlink library can't encode, eg a flower filter matching tunnel options.
+type EncodedFilter interface {
+	netlink.Filter
+	EncodeOptions(options *nl.RtAttr) error
+}
+
 var logger = log.Logger{Logger: logf.Log.WithName("netlink")}
 
 var NewFunc func() Interface
@@ -177,6 +191,56 @@ func (n *netlinkType) XfrmPolicyList(family int) ([]netlink.XfrmPolicy, error) {
 	return netlink.XfrmPolicyList(family)
 }
 
+func (n *netlinkType) XfrmStateAdd(state *netlink.XfrmState) error {
+	return netlink.XfrmStateAdd(state)
+}
+
+func (n *netlinkType) XfrmStateDel(state *netlink.XfrmState) error {
+	return netlink.XfrmStateDel(state)
+}
+
+func (n *netlinkType) XfrmStateList(family int) ([]netlink.XfrmState, error) {
+	return netlink.XfrmStateList(family)
+}
+
+func (n *netlinkType) QdiscReplace(qdisc netlink.Qdisc) error {
+	return netlink.QdiscReplace(qdisc)
+}
+
+func (n *netlinkType) FilterReplace(filter netlink.Filter) error {
+	encoded, ok := filter.(EncodedFilter)
+	if !ok {
+		return netlink.FilterReplace(filter)
+	}
+
+	attrs := filter.Attrs()
+
+	req := nl.NewNetlinkRequest(unix.RTM_NEWTFILTER, unix.NLM_F_CREATE|unix.NLM_F_ACK)
+	req.AddData(&nl.TcMsg{
+		Family:  nl.FAMILY_ALL,
+		Ifindex: int32(attrs.LinkIndex), //nolint:gosec // Link indexes are positive int32s
+		Handle:  attrs.Handle,
+		Parent:  attrs.Parent,
+		Info:    netlink.MakeHandle(attrs.Priority, nl.Swap16(attrs.Protocol)),
+	})
+	req.AddData(nl.NewRtAttr(nl.TCA_KIND, nl.ZeroTerminated(filter.Type())))
+
+	options := nl.NewRtAttr(nl.TCA_OPTIONS, nil)
+	if err := encoded.EncodeOptions(options); err != nil {
+		return err
+	}
+
+	req.AddData(options)
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+
+	return err
+}
+
+func (n *netlinkType) FilterDel(filter netlink.Filter) error {
+	return netlink.FilterDel(filter)
+}
+
 func (n *netlinkType) EnableLooseModeReversePathFilter(interfaceName string) error {
 	// Enable loose mode (rp_filter=2) reverse path filtering on the vxlan interface.
 	err := setSysctl(ipv4ConfPath(interfaceName)+"/rp_filter", []byte("2"))
@@ -209,7 +273,19 @@ func (n *netlinkType) EnsureLooseModeIsConfigured(interfaceName string) error {
 
 func (n *netlinkType) EnableForwarding(interfaceName string) error {
 	err := setSysctl(ipv4ConfPath(interfaceName)+"/forwarding", []byte("1"))
-	return errors.Wrapf(err, "unable to update forwarding on interface %q", interfaceName)
+	if err != nil {
+		return errors.Wrapf(err, "unable to update forwarding on interface %q", interfaceName)
+	}
+
+	// The IPv6 conf entry only exists if IPv6 is enabled on the host.
+	ipv6Path := ipv6ConfPath(interfaceName) + "/forwarding"
+	if _, statErr := os.Stat(ipv6Path); statErr != nil {
+		return nil
+	}
+
+	err = setSysctl(ipv6Path, []byte("1"))
+
+	return errors.Wrapf(err, "unable to update IPv6 forwarding on interface %q", interfaceName)
 }
 
 func (n *netlinkType) GetReversePathFilter(interfaceName string) ([]byte, error) {
@@ -264,6 +340,10 @@ func ipv4ConfPath(interfaceName string) string {
 	return "/proc/sys/net/ipv4/conf/" + interfaceName
 }
 
+func ipv6ConfPath(interfaceName string) string {
+	return "/proc/sys/net/ipv6/conf/" + interfaceName
+}
+
 //nolint:wrapcheck // Let the caller wrap external errors
 func GetDefaultGatewayInterface() (*net.Interface, error) {
 	routes, err := netlink.RouteList(nil, syscall.AF_INET)
@@ -363,3 +443,12 @@ func NewTableRule(tableID int) *netlink.Rule {
 
 	return rule
 }
+
+// NewTableRuleForFamily returns a rule to lookup the given table for the given address family (netlink.FAMILY_V4 or
+// netlink.FAMILY_V6).
+func NewTableRuleForFamily(tableID, family int) *netlink.Rule {
+	rule := NewTableRule(tableID)
+	rule.Family = family
+
+	return rule
+}
@@ -0,0 +1,185 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configure
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/submariner/pkg/packetfilter"
+	"github.com/submariner-io/submariner/pkg/packetfilter/iptables"
+	"github.com/submariner-io/submariner/pkg/packetfilter/nftables"
+	k8snet "k8s.io/utils/net"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/knftables"
+)
+
+const (
+	DriverAuto     = ""
+	DriverIPTables = "iptables"
+	DriverNFTables = "nftables"
+
+	// kube-proxy reports its mode on the metrics endpoint which, by default, listens on the host loopback.
+	kubeProxyModeURL = "http://127.0.0.1:10249/proxyMode"
+)
+
+var logger = log.Logger{Logger: logf.Log.WithName("PacketFilterConfig")}
+
+// HostProbe abstracts the host checks used to select the packet filter driver.
+type HostProbe interface {
+	// NFTablesAvailable returns true if the nft binary is present and the kernel supports nftables.
+	NFTablesAvailable() bool
+	// IPTablesAvailable returns true if an iptables binary is present.
+	IPTablesAvailable() bool
+	// KubeProxyMode returns the mode kube-proxy is running in (eg "iptables", "ipvs", "nftables") or an empty string if
+	// it could not be determined.
+	KubeProxyMode() string
+}
+
+// SelectDriver determines which packet filter driver to use based on the given explicit override (typically
+// configured via an environment variable) and, if not set, by probing the host.
+func SelectDriver(override string, probe HostProbe) (string, error) {
+	switch strings.ToLower(override) {
+	case DriverIPTables:
+		return DriverIPTables, nil
+	case DriverNFTables:
+		return DriverNFTables, nil
+	case DriverAuto, "auto":
+	default:
+		return "", errors.Errorf("invalid packet filter driver %q - supported values are %q and %q", override,
+			DriverIPTables, DriverNFTables)
+	}
+
+	nftAvailable := probe.NFTablesAvailable()
+	iptAvailable := probe.IPTablesAvailable()
+
+	if !nftAvailable && !iptAvailable {
+		return "", errors.New("neither nftables nor iptables is available on the host")
+	}
+
+	if !iptAvailable {
+		logger.Info("iptables is not available on the host - using the nftables driver")
+		return DriverNFTables, nil
+	}
+
+	if !nftAvailable {
+		logger.Info("nftables is not available on the host - using the iptables driver")
+		return DriverIPTables, nil
+	}
+
+	mode := probe.KubeProxyMode()
+
+	switch mode {
+	case DriverNFTables:
+		logger.Info("kube-proxy is running in nftables mode - using the nftables driver")
+		return DriverNFTables, nil
+	case "":
+		logger.Info("Unable to determine the kube-proxy mode - defaulting to the iptables driver")
+	default:
+		logger.Infof("kube-proxy is running in %q mode - using the iptables driver", mode)
+	}
+
+	return DriverIPTables, nil
+}
+
+// NewDriverFn returns the packetfilter.Driver constructor for the given driver name.
+func NewDriverFn(driver string) (func(family k8snet.IPFamily) (packetfilter.Driver, error), error) {
+	switch driver {
+	case DriverIPTables:
+		return iptables.New, nil
+	case DriverNFTables:
+		return nftables.New, nil
+	}
+
+	return nil, errors.Errorf("unknown packet filter driver %q", driver)
+}
+
+// Configure selects the packet filter driver, registers it via packetfilter.SetNewDriverFn and returns its name.
+func Configure(override string) (string, error) {
+	driver, err := SelectDriver(override, &hostProbe{})
+	if err != nil {
+		return "", err
+	}
+
+	newDriverFn, err := NewDriverFn(driver)
+	if err != nil {
+		return "", err
+	}
+
+	packetfilter.SetNewDriverFn(newDriverFn)
+
+	logger.Infof("Using the %q packet filter driver", driver)
+
+	return driver, nil
+}
+
+type hostProbe struct{}
+
+func (h *hostProbe) NFTablesAvailable() bool {
+	_, err := knftables.New(knftables.IPv4Family, "submariner")
+	if err != nil {
+		logger.V(log.DEBUG).Infof("nftables is not available: %v", err)
+	}
+
+	return err == nil
+}
+
+func (h *hostProbe) IPTablesAvailable() bool {
+	_, err := exec.LookPath("iptables")
+	if err != nil {
+		logger.V(log.DEBUG).Infof("iptables is not available: %v", err)
+	}
+
+	return err == nil
+}
+
+func (h *hostProbe) KubeProxyMode() string {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, kubeProxyModeURL, http.NoBody)
+	if err != nil {
+		return ""
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		logger.V(log.DEBUG).Infof("Unable to query the kube-proxy mode: %v", err)
+		return ""
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,244 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configure_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner/pkg/packetfilter"
+	"github.com/submariner-io/submariner/pkg/packetfilter/configure"
+	fakePF "github.com/submariner-io/submariner/pkg/packetfilter/fake"
+)
+
+type fakeProbe struct {
+	nftAvailable  bool
+	iptAvailable  bool
+	kubeProxyMode string
+}
+
+func (f *fakeProbe) NFTablesAvailable() bool {
+	return f.nftAvailable
+}
+
+func (f *fakeProbe) IPTablesAvailable() bool {
+	return f.iptAvailable
+}
+
+func (f *fakeProbe) KubeProxyMode() string {
+	return f.kubeProxyMode
+}
+
+var _ = Describe("SelectDriver", func() {
+	var (
+		probe    *fakeProbe
+		override string
+	)
+
+	BeforeEach(func() {
+		probe = &fakeProbe{nftAvailable: true, iptAvailable: true}
+		override = ""
+	})
+
+	selectDriver := func() string {
+		driver, err := configure.SelectDriver(override, probe)
+		Expect(err).To(Succeed())
+
+		return driver
+	}
+
+	When("an explicit override is specified", func() {
+		It("should return it regardless of the host", func() {
+			override = "NFTables"
+			probe.nftAvailable = false
+			Expect(selectDriver()).To(Equal(configure.DriverNFTables))
+
+			override = configure.DriverIPTables
+			probe.kubeProxyMode = "nftables"
+			Expect(selectDriver()).To(Equal(configure.DriverIPTables))
+		})
+	})
+
+	When("an invalid override is specified", func() {
+		It("should return an error", func() {
+			_, err := configure.SelectDriver("bogus", probe)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("only nftables is available", func() {
+		It("should select nftables", func() {
+			probe.iptAvailable = false
+			Expect(selectDriver()).To(Equal(configure.DriverNFTables))
+		})
+	})
+
+	When("only iptables is available", func() {
+		It("should select iptables", func() {
+			probe.nftAvailable = false
+			probe.kubeProxyMode = "nftables"
+			Expect(selectDriver()).To(Equal(configure.DriverIPTables))
+		})
+	})
+
+	When("both are available", func() {
+		It("should select based on the kube-proxy mode", func() {
+			probe.kubeProxyMode = "nftables"
+			Expect(selectDriver()).To(Equal(configure.DriverNFTables))
+
+			probe.kubeProxyMode = "ipvs"
+			Expect(selectDriver()).To(Equal(configure.DriverIPTables))
+
+			probe.kubeProxyMode = ""
+			Expect(selectDriver()).To(Equal(configure.DriverIPTables))
+		})
+	})
+
+	When("neither is available", func() {
+		It("should return an error", func() {
+			probe.nftAvailable = false
+			probe.iptAvailable = false
+			_, err := configure.SelectDriver("", probe)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Migrate", func() {
+	const (
+		hookChain  = "SUBMARINER-POSTROUTING"
+		childChain = "SUBMARINER-CHILD"
+		otherChain = "OTHER-CHAIN"
+		setName    = "SUBMARINER-SET"
+	)
+
+	var (
+		from *fakePF.PacketFilter
+		to   *fakePF.PacketFilter
+		spec *configure.MigrationSpec
+	)
+
+	jumpToChild := &packetfilter.Rule{Action: packetfilter.RuleActionJump, TargetChain: childChain}
+	jumpToOther := &packetfilter.Rule{Action: packetfilter.RuleActionJump, TargetChain: otherChain}
+	setRule := &packetfilter.Rule{Action: packetfilter.RuleActionAccept, SrcSetName: setName}
+	snatRule := &packetfilter.Rule{Action: packetfilter.RuleActionSNAT, SrcCIDR: "10.0.0.0/16", SnatCIDR: "169.254.1.1"}
+
+	BeforeEach(func() {
+		from = fakePF.New()
+		to = fakePF.New()
+
+		spec = &configure.MigrationSpec{
+			IPHookChains: []packetfilter.ChainIPHook{{
+				Name:     hookChain,
+				Type:     packetfilter.ChainTypeNAT,
+				Hook:     packetfilter.ChainHookPostrouting,
+				Priority: packetfilter.ChainPriorityFirst,
+			}},
+			Chains: []configure.TableChain{{Table: packetfilter.TableTypeNAT, Name: childChain}},
+		}
+	})
+
+	When("the chains exist in the source", func() {
+		BeforeEach(func() {
+			Expect(from.CreateIPHookChainIfNotExists(&spec.IPHookChains[0])).To(Succeed())
+			Expect(from.CreateChainIfNotExists(packetfilter.TableTypeNAT, &packetfilter.Chain{Name: childChain})).To(Succeed())
+			Expect(from.CreateChainIfNotExists(packetfilter.TableTypeNAT, &packetfilter.Chain{Name: otherChain})).To(Succeed())
+			Expect(from.Append(packetfilter.TableTypeNAT, hookChain, jumpToChild)).To(Succeed())
+			Expect(from.Append(packetfilter.TableTypeNAT, hookChain, jumpToOther)).To(Succeed())
+			Expect(from.Append(packetfilter.TableTypeNAT, childChain, setRule)).To(Succeed())
+			Expect(from.Append(packetfilter.TableTypeNAT, childChain, snatRule)).To(Succeed())
+
+			set := from.NewNamedSet(&packetfilter.SetInfo{Name: setName})
+			Expect(set.Create(true)).To(Succeed())
+			Expect(set.AddEntry("10.1.0.0/16", true)).To(Succeed())
+		})
+
+		It("should move the chains, rules and sets to the target", func() {
+			Expect(configure.Migrate(from, to, spec)).To(Succeed())
+
+			rules, err := to.List(packetfilter.TableTypeNAT, hookChain)
+			Expect(err).To(Succeed())
+			Expect(rules).To(Equal([]*packetfilter.Rule{jumpToChild}))
+
+			rules, err = to.List(packetfilter.TableTypeNAT, childChain)
+			Expect(err).To(Succeed())
+			Expect(rules).To(Equal([]*packetfilter.Rule{setRule, snatRule}))
+
+			to.AwaitEntry(setName, "10.1.0.0/16")
+
+			from.AwaitNoIPHookChain(packetfilter.ChainTypeNAT, hookChain)
+			from.AwaitNoChain(packetfilter.TableTypeNAT, childChain)
+			from.AwaitSetDeleted(setName)
+		})
+
+		Context("and some rules already exist in the target", func() {
+			BeforeEach(func() {
+				Expect(to.CreateChainIfNotExists(packetfilter.TableTypeNAT, &packetfilter.Chain{Name: childChain})).To(Succeed())
+				Expect(to.Append(packetfilter.TableTypeNAT, childChain, snatRule)).To(Succeed())
+			})
+
+			It("should not duplicate them", func() {
+				Expect(configure.Migrate(from, to, spec)).To(Succeed())
+
+				rules, err := to.List(packetfilter.TableTypeNAT, childChain)
+				Expect(err).To(Succeed())
+				Expect(rules).To(HaveLen(2))
+				Expect(rules).To(ContainElements(setRule, snatRule))
+			})
+		})
+	})
+
+	When("the chains don't exist in the source", func() {
+		It("should not create anything in the target", func() {
+			Expect(configure.Migrate(from, to, spec)).To(Succeed())
+
+			to.AwaitNoIPHookChain(packetfilter.ChainTypeNAT, hookChain)
+			to.AwaitNoChain(packetfilter.TableTypeNAT, childChain)
+		})
+	})
+})
+
+var _ = Describe("MergeMigrationSpecs", func() {
+	natHook := packetfilter.ChainIPHook{
+		Name:     "SUBMARINER-POSTROUTING",
+		Type:     packetfilter.ChainTypeNAT,
+		Hook:     packetfilter.ChainHookPostrouting,
+		Priority: packetfilter.ChainPriorityFirst,
+	}
+
+	routeHook := natHook
+	routeHook.Type = packetfilter.ChainTypeRoute
+
+	filterChain := configure.TableChain{Table: packetfilter.TableTypeFilter, Name: "SUBMARINER-CHAIN"}
+
+	It("should include each chain once", func() {
+		spec := configure.MergeMigrationSpecs(
+			&configure.MigrationSpec{IPHookChains: []packetfilter.ChainIPHook{natHook}, Chains: []configure.TableChain{filterChain}},
+			&configure.MigrationSpec{IPHookChains: []packetfilter.ChainIPHook{natHook, routeHook}},
+			&configure.MigrationSpec{Chains: []configure.TableChain{filterChain}})
+
+		Expect(spec.IPHookChains).To(Equal([]packetfilter.ChainIPHook{natHook, routeHook}))
+		Expect(spec.Chains).To(Equal([]configure.TableChain{filterChain}))
+
+		Expect(spec.Includes(packetfilter.TableTypeNAT, natHook.Name)).To(BeTrue())
+		Expect(spec.Includes(packetfilter.TableTypeRoute, routeHook.Name)).To(BeTrue())
+		Expect(spec.Includes(packetfilter.TableTypeFilter, filterChain.Name)).To(BeTrue())
+		Expect(spec.Includes(packetfilter.TableTypeFilter, natHook.Name)).To(BeFalse())
+		Expect(spec.Includes(packetfilter.TableTypeNAT, filterChain.Name)).To(BeFalse())
+	})
+})
@@ -0,0 +1,360 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configure
+
+import (
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/submariner/pkg/packetfilter"
+	"github.com/submariner-io/submariner/pkg/packetfilter/iptables"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	k8snet "k8s.io/utils/net"
+	"k8s.io/utils/set"
+)
+
+type TableChain struct {
+	Table packetfilter.TableType
+	Name  string
+}
+
+// MigrationSpec specifies the chains owned by a component that are to be moved from one driver to another.
+type MigrationSpec struct {
+	// IPHookChains are the chains attached to netfilter hooks.
+	IPHookChains []packetfilter.ChainIPHook
+	// Chains are the regular chains referenced from the IPHookChains. They're migrated before the IPHookChains
+	// so jump rules resolve.
+	Chains []TableChain
+}
+
+// MergeMigrationSpecs combines the chains of the given specs, eg those owned by separate handlers of a component. A chain
+// specified more than once is only included once.
+func MergeMigrationSpecs(specs ...*MigrationSpec) *MigrationSpec {
+	merged := &MigrationSpec{}
+
+	for _, spec := range specs {
+		for i := range spec.IPHookChains {
+			if !merged.hasIPHookChain(spec.IPHookChains[i].Type, spec.IPHookChains[i].Name) {
+				merged.IPHookChains = append(merged.IPHookChains, spec.IPHookChains[i])
+			}
+		}
+
+		for _, chain := range spec.Chains {
+			if !slices.Contains(merged.Chains, chain) {
+				merged.Chains = append(merged.Chains, chain)
+			}
+		}
+	}
+
+	return merged
+}
+
+// Includes returns whether the spec contains the given regular chain or IP hook chain in the given table.
+func (s *MigrationSpec) Includes(table packetfilter.TableType, name string) bool {
+	if slices.Contains(s.Chains, TableChain{Table: table, Name: name}) {
+		return true
+	}
+
+	for i := range s.IPHookChains {
+		if s.IPHookChains[i].Name == name && ipHookChainTypeToTableType[s.IPHookChains[i].Type] == table {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *MigrationSpec) hasIPHookChain(chainType packetfilter.ChainType, name string) bool {
+	return slices.ContainsFunc(s.IPHookChains, func(c packetfilter.ChainIPHook) bool {
+		return c.Type == chainType && c.Name == name
+	})
+}
+
+var ipHookChainTypeToTableType = map[packetfilter.ChainType]packetfilter.TableType{
+	packetfilter.ChainTypeFilter: packetfilter.TableTypeFilter,
+	packetfilter.ChainTypeRoute:  packetfilter.TableTypeRoute,
+	packetfilter.ChainTypeNAT:    packetfilter.TableTypeNAT,
+}
+
+type chainRules struct {
+	table packetfilter.TableType
+	name  string
+	rules []*packetfilter.Rule
+}
+
+// Migrate copies the chains, rules and referenced named sets in the given spec from one driver to another and then
+// removes them from the source driver. The target is fully programmed before anything is removed from the source so
+// traffic continues to be handled throughout the migration.
+func Migrate(from, to packetfilter.Driver, spec *MigrationSpec) error {
+	chains, err := readChains(from, spec)
+	if err != nil {
+		return err
+	}
+
+	if len(chains) == 0 {
+		logger.V(log.DEBUG).Info("No existing chains found to migrate")
+		return nil
+	}
+
+	logger.Infof("Migrating %d packet filter chain(s)", len(chains))
+
+	setNames := referencedSets(chains)
+
+	if err := copySets(from, to, setNames); err != nil {
+		return err
+	}
+
+	migrated := set.New[string]()
+
+	for i := range spec.Chains {
+		c := findChain(chains, spec.Chains[i].Table, spec.Chains[i].Name)
+		if c == nil {
+			continue
+		}
+
+		if err := to.CreateChainIfNotExists(c.table, &packetfilter.Chain{Name: c.name}); err != nil {
+			return errors.Wrapf(err, "error creating chain %q", c.name)
+		}
+
+		migrated.Insert(c.name)
+	}
+
+	for i := range spec.Chains {
+		c := findChain(chains, spec.Chains[i].Table, spec.Chains[i].Name)
+		if c != nil {
+			copyRules(to, c, migrated)
+		}
+	}
+
+	for i := range spec.IPHookChains {
+		hook := &spec.IPHookChains[i]
+
+		c := findChain(chains, ipHookChainTypeToTableType[hook.Type], hook.Name)
+		if c == nil {
+			continue
+		}
+
+		if err := to.CreateIPHookChainIfNotExists(hook); err != nil {
+			return errors.Wrapf(err, "error creating IP hook chain %q", hook.Name)
+		}
+
+		copyRules(to, c, migrated)
+	}
+
+	removeFromSource(from, spec, chains, setNames)
+
+	logger.Info("Packet filter migration complete")
+
+	return nil
+}
+
+// MigrateFromIPTables migrates the given chains from the iptables driver, if present, to the driver currently
+// registered via packetfilter.SetNewDriverFn. Both the IPv4 and IPv6 (ip6tables) chains are migrated.
+func MigrateFromIPTables(spec *MigrationSpec) error {
+	var errs []error
+
+	for _, family := range []k8snet.IPFamily{k8snet.IPv4, k8snet.IPv6} {
+		from, err := iptables.New(family)
+		if err != nil {
+			logger.V(log.DEBUG).Infof("Unable to create the IPv%s iptables driver - nothing to migrate: %v", family, err)
+			continue
+		}
+
+		to, err := packetfilter.NewForFamily(family)
+		if err != nil {
+			errs = append(errs, errors.Wrap(err, "error creating the packet filter driver"))
+			continue
+		}
+
+		errs = append(errs, errors.Wrapf(Migrate(from, to, spec), "error migrating the IPv%s chains", family))
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func readChains(from packetfilter.Driver, spec *MigrationSpec) ([]chainRules, error) {
+	var chains []chainRules
+
+	read := func(table packetfilter.TableType, name string) error {
+		if findChain(chains, table, name) != nil {
+			return nil
+		}
+
+		exists, err := from.ChainExists(table, name)
+		if err != nil {
+			return errors.Wrapf(err, "error checking if chain %q exists", name)
+		}
+
+		if !exists {
+			return nil
+		}
+
+		rules, err := from.List(table, name)
+		if err != nil {
+			return errors.Wrapf(err, "error listing the rules for chain %q", name)
+		}
+
+		chains = append(chains, chainRules{table: table, name: name, rules: rules})
+
+		return nil
+	}
+
+	for i := range spec.Chains {
+		if err := read(spec.Chains[i].Table, spec.Chains[i].Name); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range spec.IPHookChains {
+		if err := read(ipHookChainTypeToTableType[spec.IPHookChains[i].Type], spec.IPHookChains[i].Name); err != nil {
+			return nil, err
+		}
+	}
+
+	return chains, nil
+}
+
+func findChain(chains []chainRules, table packetfilter.TableType, name string) *chainRules {
+	for i := range chains {
+		if chains[i].table == table && chains[i].name == name {
+			return &chains[i]
+		}
+	}
+
+	return nil
+}
+
+func referencedSets(chains []chainRules) set.Set[string] {
+	names := set.New[string]()
+
+	for i := range chains {
+		for _, r := range chains[i].rules {
+			if r.SrcSetName != "" {
+				names.Insert(r.SrcSetName)
+			}
+
+			if r.DestSetName != "" {
+				names.Insert(r.DestSetName)
+			}
+		}
+	}
+
+	return names
+}
+
+func copySets(from, to packetfilter.Driver, names set.Set[string]) error {
+	for _, name := range names.SortedList() {
+		info := &packetfilter.SetInfo{Name: name}
+
+		entries, err := from.NewNamedSet(info).ListEntries()
+		if err != nil {
+			return errors.Wrapf(err, "error listing the entries for set %q", name)
+		}
+
+		target := to.NewNamedSet(info)
+
+		if err := target.Create(true); err != nil {
+			return errors.Wrapf(err, "error creating set %q", name)
+		}
+
+		for _, entry := range entries {
+			if err := target.AddEntry(entry, true); err != nil {
+				return errors.Wrapf(err, "error adding entry %q to set %q", entry, name)
+			}
+		}
+
+		logger.V(log.DEBUG).Infof("Migrated set %q with %d entries", name, len(entries))
+	}
+
+	return nil
+}
+
+// copyRules appends the rules that aren't already present in the target. Rules that jump to a chain outside the
+// migration are skipped as the target chain may not exist yet - the owning component re-adds them when it programs
+// its own chains.
+func copyRules(to packetfilter.Driver, c *chainRules, migrated set.Set[string]) {
+	existing, err := to.List(c.table, c.name)
+	if err != nil {
+		logger.Warningf("Unable to list the existing rules for chain %q: %v", c.name, err)
+	}
+
+	for _, r := range c.rules {
+		if r.Action == packetfilter.RuleActionJump && !migrated.Has(r.TargetChain) {
+			logger.V(log.DEBUG).Infof("Skipping rule %q in chain %q as the target chain is not being migrated", r, c.name)
+			continue
+		}
+
+		if containsRule(existing, r) {
+			continue
+		}
+
+		if err := to.Append(c.table, c.name, r); err != nil {
+			logger.Warningf("Unable to migrate rule %q in chain %q: %v", r, c.name, err)
+		}
+	}
+}
+
+func containsRule(rules []*packetfilter.Rule, rule *packetfilter.Rule) bool {
+	for _, r := range rules {
+		if *r == *rule {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeFromSource is best effort - failures are logged and any leftovers are equivalent to the migrated rules.
+func removeFromSource(from packetfilter.Driver, spec *MigrationSpec, chains []chainRules, setNames set.Set[string]) {
+	for i := range chains {
+		if err := from.ClearChain(chains[i].table, chains[i].name); err != nil {
+			logger.Warningf("Unable to clear chain %q: %v", chains[i].name, err)
+		}
+	}
+
+	for i := range spec.IPHookChains {
+		hook := &spec.IPHookChains[i]
+		if findChain(chains, ipHookChainTypeToTableType[hook.Type], hook.Name) == nil {
+			continue
+		}
+
+		if err := from.DeleteIPHookChain(hook); err != nil {
+			logger.Warningf("Unable to delete IP hook chain %q: %v", hook.Name, err)
+		}
+	}
+
+	for i := range spec.Chains {
+		if findChain(chains, spec.Chains[i].Table, spec.Chains[i].Name) == nil {
+			continue
+		}
+
+		if err := from.DeleteChain(spec.Chains[i].Table, spec.Chains[i].Name); err != nil {
+			logger.Warningf("Unable to delete chain %q: %v", spec.Chains[i].Name, err)
+		}
+	}
+
+	if setNames.Len() == 0 {
+		return
+	}
+
+	if err := from.DestroySets(setNames.Has); err != nil {
+		logger.Warningf("Unable to destroy migrated sets: %v", err)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
+	k8snet "k8s.io/utils/net"
 	"k8s.io/utils/set"
 )
 
@@ -50,7 +51,7 @@ func New() *PacketFilter {
 		sets:       map[string]set.Set[string]{},
 	}
 
-	packetfilter.SetNewDriverFn(func() (packetfilter.Driver, error) {
+	packetfilter.SetNewDriverFn(func(_ k8snet.IPFamily) (packetfilter.Driver, error) {
 		return pf, nil
 	})
 
@@ -324,6 +325,16 @@ func (i *PacketFilter) AwaitNoIPHookChain(chainType packetfilter.ChainType, stri
 	i.awaitNoChain(uint32(chainType), stringOrMatcher)
 }
 
+// EnsureChainsIncludedIn verifies that every existing regular and IP hook chain is included according to the given
+// predicate, eg the Includes method of a migration spec.
+func (i *PacketFilter) EnsureChainsIncludedIn(includes func(table packetfilter.TableType, chain string) bool) {
+	for _, table := range []packetfilter.TableType{packetfilter.TableTypeFilter, packetfilter.TableTypeRoute, packetfilter.TableTypeNAT} {
+		for _, chain := range i.listChains(uint32(table)) {
+			Expect(includes(table, chain)).To(BeTrue(), "Chain %q in table %v is not included", chain, table)
+		}
+	}
+}
+
 func (i *PacketFilter) AwaitRule(table packetfilter.TableType, chain string, stringOrMatcher interface{}) {
 	Eventually(func() []string {
 		return i.listRules(table, chain)
@@ -26,6 +26,7 @@ import (
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/submariner/pkg/ipset"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
+	k8snet "k8s.io/utils/net"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -58,6 +59,8 @@ var (
 		packetfilter.RuleActionMark:   "MARK",
 		packetfilter.RuleActionSNAT:   "SNAT",
 		packetfilter.RuleActionDNAT:   "DNAT",
+		packetfilter.RuleActionDrop:   "DROP",
+		packetfilter.RuleActionReturn: "RETURN",
 	}
 
 	logger = log.Logger{Logger: logf.Log.WithName("IPTables")}
@@ -72,12 +75,19 @@ func (r RuleSpec) String() string {
 type packetFilter struct {
 	ipt        *iptables.IPTables
 	ipSetIface ipset.Interface
+	family     k8snet.IPFamily
 }
 
-func New() (packetfilter.Driver, error) {
-	ipt, err := iptables.New(iptables.IPFamily(iptables.ProtocolIPv4), iptables.Timeout(5))
+// New returns a Driver that programs iptables rules for the given IP family (ie ip6tables for IPv6).
+func New(family k8snet.IPFamily) (packetfilter.Driver, error) {
+	protocol := iptables.ProtocolIPv4
+	if family == k8snet.IPv6 {
+		protocol = iptables.ProtocolIPv6
+	}
+
+	ipt, err := iptables.New(iptables.IPFamily(protocol), iptables.Timeout(5))
 	if err != nil {
-		return nil, errors.Wrap(err, "error creating IP tables")
+		return nil, errors.Wrapf(err, "error creating IP tables for IPv%s", family)
 	}
 
 	ipSetIface := ipset.New()
@@ -85,6 +95,7 @@ func New() (packetfilter.Driver, error) {
 	return &packetFilter{
 		ipt:        ipt,
 		ipSetIface: ipSetIface,
+		family:     family,
 	}, nil
 }
 
@@ -94,7 +105,7 @@ func (p *packetFilter) ChainExists(table packetfilter.TableType, chain string) (
 }
 
 func (p *packetFilter) AppendUnique(table packetfilter.TableType, chain string, rule *packetfilter.Rule) error {
-	ruleSpec := ToRuleSpec(rule)
+	ruleSpec := p.toRuleSpec(rule)
 	return errors.Wrapf(p.ipt.AppendUnique(tableTypeToStr[table], chain, ruleSpec...), "AppendUnique failed for table %q, chain %q, rule %q",
 		tableTypeToStr[table], chain, ruleSpec)
 }
@@ -116,7 +127,7 @@ func (p *packetFilter) CreateIPHookChainIfNotExists(chain *packetfilter.ChainIPH
 	}
 
 	if chain.Priority == packetfilter.ChainPriorityFirst {
-		ruleSpec := ToRuleSpec(jumpRule)
+		ruleSpec := p.toRuleSpec(jumpRule)
 		if err := p.ipt.InsertUnique(table, chainHookToStr[chain.Hook], 1, ruleSpec...); err != nil {
 			return errors.Wrapf(err, "error creating IP hook chain %q for table %q, InsertUnique failed for rule: %q",
 				chainHookToStr[chain.Hook], table, ruleSpec)
@@ -165,7 +176,7 @@ func (p *packetFilter) ClearChain(table packetfilter.TableType, chain string) er
 }
 
 func (p *packetFilter) Delete(table packetfilter.TableType, chain string, rule *packetfilter.Rule) error {
-	ruleSpec := ToRuleSpec(rule)
+	ruleSpec := p.toRuleSpec(rule)
 	err := p.ipt.Delete(tableTypeToStr[table], chain, ruleSpec...)
 
 	var iptError *iptables.Error
@@ -207,13 +218,13 @@ func (p *packetFilter) List(table packetfilter.TableType, chain string) ([]*pack
 }
 
 func (p *packetFilter) Insert(table packetfilter.TableType, chain string, pos int, rule *packetfilter.Rule) error {
-	ruleSpec := ToRuleSpec(rule)
+	ruleSpec := p.toRuleSpec(rule)
 	return errors.Wrapf(p.ipt.Insert(tableTypeToStr[table], chain, pos, ruleSpec...), "Insert failed for table %q, chain %q, rule %q",
 		tableTypeToStr[table], chain, ruleSpec)
 }
 
 func (p *packetFilter) Append(table packetfilter.TableType, chain string, rule *packetfilter.Rule) error {
-	ruleSpec := ToRuleSpec(rule)
+	ruleSpec := p.toRuleSpec(rule)
 	return errors.Wrapf(p.ipt.Append(tableTypeToStr[table], chain, ruleSpec...), "Append failed for table %q, chain %q, rule %q",
 		tableTypeToStr[table], chain, ruleSpec)
 }
@@ -233,14 +244,22 @@ func (p *packetFilter) createChainIfNotExists(tableType packetfilter.TableType,
 	return errors.Wrapf(p.ipt.NewChain(table, chain), "error creating IP table chain %q for table %q", table, chain)
 }
 
-func protoToRuleSpec(ruleSpec *RuleSpec, proto packetfilter.RuleProto) {
+func (p *packetFilter) toRuleSpec(rule *packetfilter.Rule) RuleSpec {
+	return ToRuleSpecForFamily(rule, p.family)
+}
+
+func protoToRuleSpec(ruleSpec *RuleSpec, proto packetfilter.RuleProto, family k8snet.IPFamily) {
 	switch proto {
 	case packetfilter.RuleProtoUDP:
 		*ruleSpec = append(*ruleSpec, "-p", "udp", "-m", "udp")
 	case packetfilter.RuleProtoTCP:
 		*ruleSpec = append(*ruleSpec, "-p", "tcp", "-m", "tcp")
 	case packetfilter.RuleProtoICMP:
-		*ruleSpec = append(*ruleSpec, "-p", "icmp")
+		if family == k8snet.IPv6 {
+			*ruleSpec = append(*ruleSpec, "-p", "ipv6-icmp")
+		} else {
+			*ruleSpec = append(*ruleSpec, "-p", "icmp")
+		}
 	case packetfilter.RuleProtoAll:
 		*ruleSpec = append(*ruleSpec, "-p", "all")
 	case packetfilter.RuleProtoUndefined:
@@ -268,8 +287,13 @@ func setToRuleSpec(ruleSpec *RuleSpec, srcSetName, destSetName string) {
 }
 
 func ToRuleSpec(rule *packetfilter.Rule) RuleSpec {
+	return ToRuleSpecForFamily(rule, k8snet.IPv4)
+}
+
+// ToRuleSpecForFamily converts the given rule to an iptables or, for IPv6, an ip6tables rule spec.
+func ToRuleSpecForFamily(rule *packetfilter.Rule, family k8snet.IPFamily) RuleSpec {
 	var ruleSpec RuleSpec
-	protoToRuleSpec(&ruleSpec, rule.Proto)
+	protoToRuleSpec(&ruleSpec, rule.Proto, family)
 
 	if rule.SrcCIDR != "" {
 		ruleSpec = append(ruleSpec, "-s", rule.SrcCIDR)
@@ -297,6 +321,10 @@ func ToRuleSpec(rule *packetfilter.Rule) RuleSpec {
 		ruleSpec = append(ruleSpec, "--dport", rule.DPort)
 	}
 
+	if rule.ConnState == packetfilter.ConnStateEstablished {
+		ruleSpec = append(ruleSpec, "-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED")
+	}
+
 	if rule.Action == packetfilter.RuleActionJump {
 		ruleSpec = append(ruleSpec, "-j", rule.TargetChain)
 	} else {
@@ -406,6 +434,14 @@ func parseRuleMatch(spec []string, i int, rule *packetfilter.Rule) int {
 
 			i += 3
 		}
+	case "conntrack":
+		if i+2 < len(spec) && spec[i+1] == "--ctstate" {
+			if strings.Contains(spec[i+2], "ESTABLISHED") {
+				rule.ConnState = packetfilter.ConnStateEstablished
+			}
+
+			i += 2
+		}
 	}
 
 	return i
@@ -435,7 +471,7 @@ func parseProtocol(s string) packetfilter.RuleProto {
 		return packetfilter.RuleProtoUDP
 	case "tcp":
 		return packetfilter.RuleProtoTCP
-	case "icmp":
+	case "icmp", "ipv6-icmp":
 		return packetfilter.RuleProtoICMP
 	case "all":
 		return packetfilter.RuleProtoAll
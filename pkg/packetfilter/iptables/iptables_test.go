@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
 	"github.com/submariner-io/submariner/pkg/packetfilter/iptables"
+	k8snet "k8s.io/utils/net"
 )
 
 var _ = Describe("Rule conversion", func() {
@@ -98,6 +99,22 @@ var _ = Describe("Rule conversion", func() {
 			Action:    packetfilter.RuleActionMark,
 		})
 
+		// -p tcp -m tcp -m set --match-set src-set src -m conntrack --ctstate RELATED,ESTABLISHED -j RETURN
+		testRuleConversion(&packetfilter.Rule{
+			Proto:      packetfilter.RuleProtoTCP,
+			SrcSetName: "src-set",
+			ConnState:  packetfilter.ConnStateEstablished,
+			Action:     packetfilter.RuleActionReturn,
+		})
+
+		// -p tcp -m tcp -d 171.254.1.0/24 --dport 22 -j DROP
+		testRuleConversion(&packetfilter.Rule{
+			Proto:    packetfilter.RuleProtoTCP,
+			DestCIDR: "171.254.1.0/24",
+			DPort:    "22",
+			Action:   packetfilter.RuleActionDrop,
+		})
+
 		// -p udp -m udp -j target-chain
 		testRuleConversion(&packetfilter.Rule{
 			Proto:       packetfilter.RuleProtoUDP,
@@ -116,6 +133,20 @@ var _ = Describe("Rule conversion", func() {
 			MssValue:  "1500",
 		}))
 	})
+
+	Specify("should correctly convert IPv6 rules to and from a rule spec string", func() {
+		// -p ipv6-icmp -d fd00:1::/64 -j DNAT --to-destination fd00:2::1
+		rule := &packetfilter.Rule{
+			Proto:    packetfilter.RuleProtoICMP,
+			DestCIDR: "fd00:1::/64",
+			DnatCIDR: "fd00:2::1",
+			Action:   packetfilter.RuleActionDNAT,
+		}
+
+		spec := iptables.ToRuleSpecForFamily(rule, k8snet.IPv6)
+		Expect(spec.String()).To(Equal("-p ipv6-icmp -d fd00:1::/64 -j DNAT --to-destination fd00:2::1"))
+		Expect(iptables.FromRuleSpec(spec)).To(Equal(rule))
+	})
 })
 
 func testRuleConversion(rule *packetfilter.Rule) {
@@ -35,6 +35,9 @@ type namedSet struct {
 
 func (p *packetFilter) NewNamedSet(set *packetfilter.SetInfo) packetfilter.NamedSet {
 	setType := "ipv4_addr"
+	if set.Family == packetfilter.SetFamilyV6 {
+		setType = "ipv6_addr"
+	}
 
 	return &namedSet{
 		set: knftables.Set{
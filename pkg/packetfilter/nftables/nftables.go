@@ -26,6 +26,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
+	k8snet "k8s.io/utils/net"
 	"k8s.io/utils/ptr"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/knftables"
@@ -64,6 +65,8 @@ var (
 		packetfilter.RuleActionSNAT:   {"snat"},
 		packetfilter.RuleActionDNAT:   {"dnat"},
 		packetfilter.RuleActionJump:   {"jump"},
+		packetfilter.RuleActionDrop:   {"drop"},
+		packetfilter.RuleActionReturn: {"return"},
 	}
 
 	logger = log.Logger{Logger: logf.Log.WithName("NFTables")}
@@ -77,20 +80,28 @@ func (r RuleSpec) String() string {
 
 type packetFilter struct {
 	nftables knftables.Interface
+	family   k8snet.IPFamily
 }
 
-func New() (packetfilter.Driver, error) {
-	nft, err := knftables.New(knftables.IPv4Family, submarinerTable)
+// New returns a Driver that programs the 'submariner' table in the ip or, for IPv6, the ip6 nftables family.
+func New(family k8snet.IPFamily) (packetfilter.Driver, error) {
+	nftFamily := knftables.IPv4Family
+	if family == k8snet.IPv6 {
+		nftFamily = knftables.IPv6Family
+	}
+
+	nft, err := knftables.New(nftFamily, submarinerTable)
 	if err != nil {
-		return nil, errors.Wrap(err, "error creating knftables")
+		return nil, errors.Wrapf(err, "error creating knftables for IPv%s", family)
 	}
 
-	return NewWithNft(nft), nil
+	return NewWithNft(nft, family), nil
 }
 
-func NewWithNft(nft knftables.Interface) packetfilter.Driver {
+func NewWithNft(nft knftables.Interface, family k8snet.IPFamily) packetfilter.Driver {
 	return &packetFilter{
 		nftables: nft,
+		family:   family,
 	}
 }
 
@@ -227,7 +238,7 @@ func (p *packetFilter) getNftablesRuleFromList(chain string, rule *packetfilter.
 	}
 
 	for _, existingRule := range existingRules {
-		if ToRuleSpec(rule).String() == ptr.Deref(existingRule.Comment, "") {
+		if p.toRuleSpec(rule).String() == ptr.Deref(existingRule.Comment, "") {
 			return existingRule, true, nil
 		}
 	}
@@ -265,7 +276,7 @@ func (p *packetFilter) Append(_ packetfilter.TableType, chain string, rule *pack
 }
 
 func (p *packetFilter) insertRuleAtPosition(chain string, rule *packetfilter.Rule, pos int) error {
-	ruleSpec := ToRuleSpec(rule).String()
+	ruleSpec := p.toRuleSpec(rule).String()
 
 	knftRule := knftables.Rule{
 		Chain:   chain,
@@ -291,20 +302,46 @@ func (p *packetFilter) insertRuleAtPosition(chain string, rule *packetfilter.Rul
 	return errors.Wrap(err, "error inserting rule")
 }
 
-func protoToRuleSpec(ruleSpec *RuleSpec, proto packetfilter.RuleProto, dPort string) {
+func (p *packetFilter) toRuleSpec(rule *packetfilter.Rule) RuleSpec {
+	return ToRuleSpecForFamily(rule, p.family)
+}
+
+// addrKeyword returns the nftables payload expression keyword for the given IP family.
+func addrKeyword(family k8snet.IPFamily) string {
+	if family == k8snet.IPv6 {
+		return "ip6"
+	}
+
+	return "ip"
+}
+
+func protoMatch(proto string, family k8snet.IPFamily) []string {
+	if family == k8snet.IPv6 {
+		// The ip6 nexthdr may be an extension header so match the transport protocol instead.
+		return []string{"meta", "l4proto", proto}
+	}
+
+	return []string{"ip", "protocol", proto}
+}
+
+func protoToRuleSpec(ruleSpec *RuleSpec, proto packetfilter.RuleProto, dPort string, family k8snet.IPFamily) {
 	switch proto {
 	case packetfilter.RuleProtoUDP:
-		*ruleSpec = append(*ruleSpec, "ip", "protocol", "udp")
+		*ruleSpec = append(*ruleSpec, protoMatch("udp", family)...)
 		if dPort != "" {
 			*ruleSpec = append(*ruleSpec, "udp", "dport", dPort)
 		}
 	case packetfilter.RuleProtoTCP:
-		*ruleSpec = append(*ruleSpec, "ip", "protocol", "tcp")
+		*ruleSpec = append(*ruleSpec, protoMatch("tcp", family)...)
 		if dPort != "" {
 			*ruleSpec = append(*ruleSpec, "tcp", "dport", dPort)
 		}
 	case packetfilter.RuleProtoICMP:
-		*ruleSpec = append(*ruleSpec, "ip", "protocol", "icmp")
+		if family == k8snet.IPv6 {
+			*ruleSpec = append(*ruleSpec, protoMatch("ipv6-icmp", family)...)
+		} else {
+			*ruleSpec = append(*ruleSpec, protoMatch("icmp", family)...)
+		}
 	case packetfilter.RuleProtoAll:
 	case packetfilter.RuleProtoUndefined:
 	}
@@ -320,26 +357,33 @@ func mssClampToRuleSpec(ruleSpec *RuleSpec, clampType packetfilter.MssClampType,
 	}
 }
 
-func setToRuleSpec(ruleSpec *RuleSpec, srcSetName, destSetName string) {
+func setToRuleSpec(ruleSpec *RuleSpec, srcSetName, destSetName, addr string) {
 	if srcSetName != "" {
-		*ruleSpec = append(*ruleSpec, "ip", "saddr", "@"+srcSetName)
+		*ruleSpec = append(*ruleSpec, addr, "saddr", "@"+srcSetName)
 	}
 
 	if destSetName != "" {
-		*ruleSpec = append(*ruleSpec, "ip", "daddr", "@"+destSetName)
+		*ruleSpec = append(*ruleSpec, addr, "daddr", "@"+destSetName)
 	}
 }
 
 func ToRuleSpec(rule *packetfilter.Rule) RuleSpec {
+	return ToRuleSpecForFamily(rule, k8snet.IPv4)
+}
+
+// ToRuleSpecForFamily converts the given rule to an nftables rule spec for a table in the given IP family.
+func ToRuleSpecForFamily(rule *packetfilter.Rule, family k8snet.IPFamily) RuleSpec {
 	var ruleSpec RuleSpec
-	protoToRuleSpec(&ruleSpec, rule.Proto, rule.DPort)
+	protoToRuleSpec(&ruleSpec, rule.Proto, rule.DPort, family)
+
+	addr := addrKeyword(family)
 
 	if rule.SrcCIDR != "" {
-		ruleSpec = append(ruleSpec, "ip", "saddr", rule.SrcCIDR)
+		ruleSpec = append(ruleSpec, addr, "saddr", rule.SrcCIDR)
 	}
 
 	if rule.DestCIDR != "" {
-		ruleSpec = append(ruleSpec, "ip", "daddr", rule.DestCIDR)
+		ruleSpec = append(ruleSpec, addr, "daddr", rule.DestCIDR)
 	}
 
 	if rule.MarkValue != "" && rule.Action != packetfilter.RuleActionMark {
@@ -347,7 +391,7 @@ func ToRuleSpec(rule *packetfilter.Rule) RuleSpec {
 		ruleSpec = append(ruleSpec, "meta", "mark", "&", rule.MarkValue, "==", rule.MarkValue)
 	}
 
-	setToRuleSpec(&ruleSpec, rule.SrcSetName, rule.DestSetName)
+	setToRuleSpec(&ruleSpec, rule.SrcSetName, rule.DestSetName, addr)
 
 	if rule.OutInterface != "" {
 		ruleSpec = append(ruleSpec, "oifname", rule.OutInterface)
@@ -357,6 +401,10 @@ func ToRuleSpec(rule *packetfilter.Rule) RuleSpec {
 		ruleSpec = append(ruleSpec, "iifname", rule.InInterface)
 	}
 
+	if rule.ConnState == packetfilter.ConnStateEstablished {
+		ruleSpec = append(ruleSpec, "ct", "state", "established,related")
+	}
+
 	if rule.Action == packetfilter.RuleActionMss {
 		ruleSpec = append(ruleSpec, "tcp", "flags", "syn / syn,rst")
 	}
@@ -388,6 +436,7 @@ func ToRuleSpec(rule *packetfilter.Rule) RuleSpec {
 	return ruleSpec
 }
 
+//nolint:gocyclo // This function has a lot of small case statements so ignore cyclomatic complexity.
 func FromRuleSpec(spec RuleSpec) *packetfilter.Rule {
 	rule := &packetfilter.Rule{}
 
@@ -396,8 +445,21 @@ func FromRuleSpec(spec RuleSpec) *packetfilter.Rule {
 
 	for i < length {
 		switch spec[i] {
-		case "ip":
+		case "ip", "ip6":
 			i = parseIPMatch(spec, i, rule)
+		case "meta":
+			if i+2 < length && spec[i+1] == "l4proto" {
+				rule.Proto = parseProtocol(spec[i+2])
+				i += 2
+			}
+		case "ct":
+			if i+2 < length && spec[i+1] == "state" {
+				if strings.Contains(spec[i+2], "established") {
+					rule.ConnState = packetfilter.ConnStateEstablished
+				}
+
+				i += 2
+			}
 		case "iifname":
 			rule.InInterface, i = parseNextTerm(spec, i, noopParse)
 		case "oifname":
@@ -534,7 +596,7 @@ func parseProtocol(s string) packetfilter.RuleProto {
 		return packetfilter.RuleProtoUDP
 	case "tcp":
 		return packetfilter.RuleProtoTCP
-	case "icmp":
+	case "icmp", "ipv6-icmp":
 		return packetfilter.RuleProtoICMP
 	case "all":
 		return packetfilter.RuleProtoAll
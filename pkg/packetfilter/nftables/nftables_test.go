@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
 	"github.com/submariner-io/submariner/pkg/packetfilter/nftables"
+	k8snet "k8s.io/utils/net"
 	"sigs.k8s.io/knftables"
 )
 
@@ -91,6 +92,22 @@ var _ = Describe("Rule conversion", func() {
 			Action:    packetfilter.RuleActionMark,
 		})
 
+		// ip protocol tcp ip saddr @src-set ct state established,related counter return
+		testRuleConversion(&packetfilter.Rule{
+			Proto:      packetfilter.RuleProtoTCP,
+			SrcSetName: "src-set",
+			ConnState:  packetfilter.ConnStateEstablished,
+			Action:     packetfilter.RuleActionReturn,
+		})
+
+		// ip protocol tcp ip daddr 171.254.1.0/24 tcp dport 22 counter drop
+		testRuleConversion(&packetfilter.Rule{
+			Proto:    packetfilter.RuleProtoTCP,
+			DestCIDR: "171.254.1.0/24",
+			DPort:    "22",
+			Action:   packetfilter.RuleActionDrop,
+		})
+
 		// ip protocol udp counter jump target-chain
 		testRuleConversion(&packetfilter.Rule{
 			Proto:       packetfilter.RuleProtoUDP,
@@ -98,6 +115,35 @@ var _ = Describe("Rule conversion", func() {
 			Action:      packetfilter.RuleActionJump,
 		})
 	})
+
+	Specify("should correctly convert IPv6 rules to and from a rule spec string", func() {
+		// meta l4proto udp udp dport d-port ip6 saddr fd00:1::/64 ip6 daddr fd00:2::/64 counter accept
+		spec := testRuleConversionForFamily(&packetfilter.Rule{
+			Proto:    packetfilter.RuleProtoUDP,
+			DestCIDR: "fd00:2::/64",
+			SrcCIDR:  "fd00:1::/64",
+			DPort:    "d-port",
+			Action:   packetfilter.RuleActionAccept,
+		}, k8snet.IPv6)
+		Expect(spec.String()).To(Equal("meta l4proto udp udp dport d-port ip6 saddr fd00:1::/64 ip6 daddr fd00:2::/64 counter accept"))
+
+		// meta l4proto ipv6-icmp ip6 daddr fd00:1::/64 counter dnat to fd00:2::1
+		testRuleConversionForFamily(&packetfilter.Rule{
+			Proto:    packetfilter.RuleProtoICMP,
+			DestCIDR: "fd00:1::/64",
+			DnatCIDR: "fd00:2::1",
+			Action:   packetfilter.RuleActionDNAT,
+		}, k8snet.IPv6)
+
+		// ip6 saddr @src-set ip6 daddr @dest-set mark & 0xc0000 == 0xc0000 counter snat to fd00:3::1
+		testRuleConversionForFamily(&packetfilter.Rule{
+			SrcSetName:  "src-set",
+			DestSetName: "dest-set",
+			MarkValue:   "0xc0000",
+			SnatCIDR:    "fd00:3::1",
+			Action:      packetfilter.RuleActionSNAT,
+		}, k8snet.IPv6)
+	})
 })
 
 var _ = Describe("Interface", func() {
@@ -119,7 +165,7 @@ var _ = Describe("Interface", func() {
 
 	BeforeEach(func() {
 		fakeKnftables = &fakeKnftablesWrapper{knftables.NewFake(knftables.IPv4Family, "submariner")}
-		pf = nftables.NewWithNft(fakeKnftables)
+		pf = nftables.NewWithNft(fakeKnftables, k8snet.IPv4)
 	})
 
 	assertRules := func(r ...*packetfilter.Rule) {
@@ -368,7 +414,11 @@ var _ = Describe("Interface", func() {
 })
 
 func testRuleConversion(rule *packetfilter.Rule) {
-	spec := nftables.ToRuleSpec(rule)
+	testRuleConversionForFamily(rule, k8snet.IPv4)
+}
+
+func testRuleConversionForFamily(rule *packetfilter.Rule, family k8snet.IPFamily) nftables.RuleSpec {
+	spec := nftables.ToRuleSpecForFamily(rule, family)
 	parsed := nftables.FromRuleSpec(spec)
 
 	// in nftables syntax protoAll represented by empty string
@@ -377,6 +427,8 @@ func testRuleConversion(rule *packetfilter.Rule) {
 	}
 
 	Expect(parsed).To(Equal(rule))
+
+	return spec
 }
 
 type fakeKnftablesWrapper struct {
@@ -23,6 +23,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
+	k8snet "k8s.io/utils/net"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -60,6 +61,8 @@ const (
 	RuleActionMark
 	RuleActionSNAT
 	RuleActionDNAT
+	RuleActionDrop
+	RuleActionReturn
 )
 
 func (r RuleAction) String() string {
@@ -76,6 +79,10 @@ func (r RuleAction) String() string {
 		return "SNAT"
 	case RuleActionDNAT:
 		return "DNAT"
+	case RuleActionDrop:
+		return "Drop"
+	case RuleActionReturn:
+		return "Return"
 	}
 
 	return unknown
@@ -108,6 +115,26 @@ func (r RuleProto) String() string {
 	return unknown
 }
 
+// ConnState is a connection tracking state matched by a rule.
+type ConnState uint32
+
+const (
+	ConnStateUndefined ConnState = iota
+	// ConnStateEstablished matches the packets of established connections and of the connections related to them.
+	ConnStateEstablished
+)
+
+func (c ConnState) String() string {
+	switch c {
+	case ConnStateUndefined:
+		return "Undefined"
+	case ConnStateEstablished:
+		return "Established"
+	}
+
+	return unknown
+}
+
 type MssClampType uint32
 
 const (
@@ -233,6 +260,7 @@ type Rule struct {
 	Action    RuleAction
 	Proto     RuleProto
 	ClampType MssClampType
+	ConnState ConnState
 }
 
 // Supported policy values are accept (which is the default) or drop.
@@ -253,10 +281,19 @@ type ChainIPHook struct {
 type SetFamily uint32
 
 const (
-	// curently only IPV4 sets are supported.
 	SetFamilyV4 SetFamily = iota
+	SetFamilyV6
 )
 
+// SetFamilyFor returns the SetFamily corresponding to the given IP family.
+func SetFamilyFor(family k8snet.IPFamily) SetFamily {
+	if family == k8snet.IPv6 {
+		return SetFamilyV6
+	}
+
+	return SetFamilyV4
+}
+
 // named set.
 type SetInfo struct {
 	// Name is the set name.
@@ -307,9 +344,10 @@ type Interface interface {
 	UpdateChainRules(table TableType, chain string, rules []*Rule) error
 }
 
-var newDriverFn func() (Driver, error)
+var newDriverFn func(family k8snet.IPFamily) (Driver, error)
 
-func SetNewDriverFn(f func() (Driver, error)) {
+// SetNewDriverFn registers the function used to create a Driver for a given IP family.
+func SetNewDriverFn(f func(family k8snet.IPFamily) (Driver, error)) {
 	newDriverFn = f
 }
 
@@ -317,14 +355,20 @@ type Adapter struct {
 	Driver
 }
 
+// New returns an Interface that programs IPv4 rules.
 func New() (Interface, error) {
+	return NewForFamily(k8snet.IPv4)
+}
+
+// NewForFamily returns an Interface that programs rules for the given IP family.
+func NewForFamily(family k8snet.IPFamily) (Interface, error) {
 	if newDriverFn == nil {
 		return nil, errors.New("no driver registered")
 	}
 
-	driver, err := newDriverFn()
+	driver, err := newDriverFn(family)
 	if err != nil {
-		return nil, errors.Wrap(err, "error creating packet filter Driver")
+		return nil, errors.Wrapf(err, "error creating packet filter Driver for IPv%s", family)
 	}
 
 	return &Adapter{Driver: driver}, nil
@@ -346,6 +390,11 @@ func (r *Rule) String() string {
 		b.WriteString(r.ClampType.String())
 	}
 
+	if r.ConnState != ConnStateUndefined {
+		b.WriteString(", ConnState: ")
+		b.WriteString(r.ConnState.String())
+	}
+
 	if r.SrcCIDR != "" {
 		b.WriteString(", SrcCIDR: ")
 		b.WriteString(r.SrcCIDR)
@@ -0,0 +1,87 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package peering
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Policy restricts the remote clusters the local cluster peers with. A remote cluster is allowed if it's not in the
+// denied list and, when configured, it's in the allowed list and its Cluster resource matches the label selector.
+// A nil Policy allows all clusters.
+type Policy struct {
+	allowed  sets.Set[string]
+	denied   sets.Set[string]
+	selector labels.Selector
+}
+
+// NewPolicy returns a Policy for the given allowed and denied cluster IDs and Cluster label selector. If none are set,
+// nil is returned.
+func NewPolicy(allowed, denied []string, clusterSelector string) (*Policy, error) {
+	if len(allowed) == 0 && len(denied) == 0 && clusterSelector == "" {
+		return nil, nil //nolint:nilnil // A nil Policy is valid and allows all clusters
+	}
+
+	p := &Policy{
+		allowed: sets.New(allowed...),
+		denied:  sets.New(denied...),
+	}
+
+	if clusterSelector != "" {
+		selector, err := labels.Parse(clusterSelector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing the peering cluster selector %q", clusterSelector)
+		}
+
+		p.selector = selector
+	}
+
+	return p, nil
+}
+
+// HasSelector returns true if the Policy depends on the labels of the remote Cluster resources.
+func (p *Policy) HasSelector() bool {
+	return p != nil && p.selector != nil
+}
+
+// Allows checks whether peering with the given remote cluster is allowed. The cluster labels are those of the remote
+// cluster's Cluster resource, nil if it isn't known. If peering isn't allowed, the returned string gives the reason.
+func (p *Policy) Allows(clusterID string, clusterLabels map[string]string) (bool, string) {
+	if p == nil {
+		return true, ""
+	}
+
+	if p.denied.Has(clusterID) {
+		return false, "the cluster is in the denied list"
+	}
+
+	if p.allowed.Len() > 0 && !p.allowed.Has(clusterID) {
+		return false, "the cluster isn't in the allowed list"
+	}
+
+	if p.selector != nil && !p.selector.Matches(labels.Set(clusterLabels)) {
+		return false, fmt.Sprintf("the cluster labels don't match the selector %q", p.selector.String())
+	}
+
+	return true, ""
+}
@@ -0,0 +1,123 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package peering_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner/pkg/peering"
+)
+
+var _ = Describe("Policy", func() {
+	var (
+		allowed  []string
+		denied   []string
+		selector string
+		policy   *peering.Policy
+	)
+
+	BeforeEach(func() {
+		allowed = nil
+		denied = nil
+		selector = ""
+	})
+
+	JustBeforeEach(func() {
+		var err error
+
+		policy, err = peering.NewPolicy(allowed, denied, selector)
+		Expect(err).To(Succeed())
+	})
+
+	assertAllowed := func(clusterID string, labels map[string]string) {
+		allows, reason := policy.Allows(clusterID, labels)
+		Expect(allows).To(BeTrue())
+		Expect(reason).To(BeEmpty())
+	}
+
+	assertDenied := func(clusterID string, labels map[string]string) {
+		allows, reason := policy.Allows(clusterID, labels)
+		Expect(allows).To(BeFalse())
+		Expect(reason).ToNot(BeEmpty())
+	}
+
+	When("nothing is configured", func() {
+		It("should return a nil policy that allows all clusters", func() {
+			Expect(policy).To(BeNil())
+			Expect(policy.HasSelector()).To(BeFalse())
+			assertAllowed("east", nil)
+		})
+	})
+
+	When("an allowed list is configured", func() {
+		BeforeEach(func() {
+			allowed = []string{"east", "west"}
+		})
+
+		It("should only allow the listed clusters", func() {
+			assertAllowed("east", nil)
+			assertAllowed("west", nil)
+			assertDenied("north", nil)
+		})
+	})
+
+	When("a denied list is configured", func() {
+		BeforeEach(func() {
+			denied = []string{"north"}
+		})
+
+		It("should allow all but the listed clusters", func() {
+			assertAllowed("east", nil)
+			assertDenied("north", nil)
+		})
+	})
+
+	When("a cluster is in both the allowed and denied lists", func() {
+		BeforeEach(func() {
+			allowed = []string{"east", "north"}
+			denied = []string{"north"}
+		})
+
+		It("should deny the cluster", func() {
+			assertAllowed("east", nil)
+			assertDenied("north", nil)
+		})
+	})
+
+	When("a cluster selector is configured", func() {
+		BeforeEach(func() {
+			selector = "region in (us,eu),tier!=test"
+		})
+
+		It("should only allow the clusters whose labels match", func() {
+			Expect(policy.HasSelector()).To(BeTrue())
+			assertAllowed("east", map[string]string{"region": "us"})
+			assertDenied("west", map[string]string{"region": "apac"})
+			assertDenied("north", map[string]string{"region": "eu", "tier": "test"})
+			assertDenied("south", nil)
+		})
+	})
+
+	When("the cluster selector is invalid", func() {
+		It("should return an error", func() {
+			_, err := peering.NewPolicy(nil, nil, "region in (")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
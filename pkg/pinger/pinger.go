@@ -79,6 +79,9 @@ type Config struct {
 	Interval           time.Duration
 	Timeout            time.Duration
 	MaxPacketLossCount int
+	// ProbeType defaults to ICMPProbe. The other probe types require a Responder listening on Port at the IP.
+	ProbeType ProbeType
+	Port      int
 }
 
 type pingerImpl struct {
@@ -94,6 +97,10 @@ type pingerImpl struct {
 }
 
 func NewPinger(config Config) Interface {
+	if config.ProbeType == UDPProbe || config.ProbeType == TCPProbe {
+		return newProber(&config)
+	}
+
 	p := &pingerImpl{
 		ip:                 config.IP,
 		pingInterval:       config.Interval,
@@ -223,20 +230,24 @@ func (p *pingerImpl) GetLatencyInfo() *LatencyInfo {
 	p.Lock()
 	defer p.Unlock()
 
+	return newLatencyInfo(p.ip, p.connectionStatus, p.failureMsg, &p.statistics)
+}
+
+func newLatencyInfo(ip string, status ConnectionStatus, failureMsg string, stats *statistics) *LatencyInfo {
 	toDurationString := func(v int64) string {
 		return time.Duration(v).String()
 	}
 
 	return &LatencyInfo{
-		IP:               p.ip,
-		ConnectionStatus: p.connectionStatus,
-		ConnectionError:  p.failureMsg,
+		IP:               ip,
+		ConnectionStatus: status,
+		ConnectionError:  failureMsg,
 		Spec: &submarinerv1.LatencyRTTSpec{
-			Last:    toDurationString(p.statistics.lastRtt),
-			Min:     toDurationString(p.statistics.minRtt),
-			Average: toDurationString(p.statistics.mean),
-			Max:     toDurationString(p.statistics.maxRtt),
-			StdDev:  toDurationString(p.statistics.stdDev),
+			Last:    toDurationString(stats.lastRtt),
+			Min:     toDurationString(stats.minRtt),
+			Average: toDurationString(stats.mean),
+			Max:     toDurationString(stats.maxRtt),
+			StdDev:  toDurationString(stats.stdDev),
 		},
 	}
 }
@@ -0,0 +1,212 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pinger
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ProbeType selects how the health of a remote endpoint is checked.
+type ProbeType string
+
+const (
+	// ICMPProbe sends ICMP echo requests.
+	ICMPProbe ProbeType = "icmp"
+	// UDPProbe sends UDP datagrams to a Responder, which echoes them back.
+	UDPProbe ProbeType = "udp"
+	// TCPProbe opens TCP connections to a Responder.
+	TCPProbe ProbeType = "tcp"
+)
+
+var probePayload = []byte("submariner-health-check")
+
+func ParseProbeType(s string) (ProbeType, error) {
+	switch t := ProbeType(s); t {
+	case ICMPProbe, UDPProbe, TCPProbe:
+		return t, nil
+	case "":
+		return ICMPProbe, nil
+	}
+
+	return "", fmt.Errorf("unsupported health check probe type %q", s)
+}
+
+type proberImpl struct {
+	sync.Mutex
+	ip                 string
+	address            string
+	probeType          ProbeType
+	probeInterval      time.Duration
+	maxPacketLossCount int
+	lostCount          int
+	statistics         statistics
+	failureMsg         string
+	connectionStatus   ConnectionStatus
+	stopCh             chan struct{}
+	probe              func(address string, timeout time.Duration) error
+}
+
+func newProber(config *Config) *proberImpl {
+	p := &proberImpl{
+		ip:                 config.IP,
+		address:            net.JoinHostPort(config.IP, strconv.Itoa(config.Port)),
+		probeType:          config.ProbeType,
+		probeInterval:      config.Interval,
+		maxPacketLossCount: config.MaxPacketLossCount,
+		statistics: statistics{
+			size:         size,
+			previousRtts: make([]int64, size),
+		},
+		stopCh: make(chan struct{}),
+		probe:  probeTCP,
+	}
+
+	if config.ProbeType == UDPProbe {
+		p.probe = probeUDP
+	}
+
+	if p.maxPacketLossCount == 0 {
+		p.maxPacketLossCount = defaultMaxPacketLossCount
+	}
+
+	if p.probeInterval == 0 {
+		p.probeInterval = defaultPingInterval
+	}
+
+	return p
+}
+
+func (p *proberImpl) Start() {
+	logger.Infof("Starting %s prober for %q", p.probeType, p.address)
+
+	go func() {
+		ticker := time.NewTicker(p.probeInterval)
+		defer ticker.Stop()
+
+		for {
+			p.doProbe()
+
+			select {
+			case <-p.stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (p *proberImpl) Stop() {
+	select {
+	case <-p.stopCh:
+		return
+	default:
+		close(p.stopCh)
+	}
+}
+
+func (p *proberImpl) doProbe() {
+	start := time.Now()
+	err := p.probe(p.address, p.probeInterval)
+	rtt := time.Since(start)
+
+	p.Lock()
+	defer p.Unlock()
+
+	if err != nil {
+		p.lostCount++
+
+		// Like the ICMP pinger, mark the connection as an error once the loss count exceeds the threshold.
+		if p.lostCount > p.maxPacketLossCount {
+			if p.connectionStatus != ConnectionError {
+				logger.Errorf(err, "Failed to successfully probe the remote endpoint %q over %s", p.address, p.probeType)
+			}
+
+			p.connectionStatus = ConnectionError
+			p.failureMsg = fmt.Sprintf("Failed to successfully probe the remote endpoint %q over %s: %v", p.address, p.probeType, err)
+		}
+
+		return
+	}
+
+	if p.connectionStatus != Connected {
+		logger.Infof("Probe to remote endpoint %q over %s is successful", p.address, p.probeType)
+	}
+
+	p.connectionStatus = Connected
+	p.failureMsg = ""
+	p.lostCount = 0
+	p.statistics.update(rtt.Nanoseconds())
+}
+
+func (p *proberImpl) GetIP() string {
+	return p.ip
+}
+
+func (p *proberImpl) GetLatencyInfo() *LatencyInfo {
+	p.Lock()
+	defer p.Unlock()
+
+	return newLatencyInfo(p.ip, p.connectionStatus, p.failureMsg, &p.statistics)
+}
+
+func probeUDP(address string, timeout time.Duration) error {
+	conn, err := net.DialTimeout("udp", address, timeout)
+	if err != nil {
+		return errors.Wrap(err, "error dialing")
+	}
+
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return errors.Wrap(err, "error setting the deadline")
+	}
+
+	if _, err := conn.Write(probePayload); err != nil {
+		return errors.Wrap(err, "error sending the probe")
+	}
+
+	reply := make([]byte, len(probePayload))
+
+	n, err := conn.Read(reply)
+	if err != nil {
+		return errors.Wrap(err, "error reading the probe reply")
+	}
+
+	if !bytes.Equal(reply[:n], probePayload) {
+		return errors.New("unexpected probe reply")
+	}
+
+	return nil
+}
+
+func probeTCP(address string, timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return errors.Wrap(err, "error connecting")
+	}
+
+	return conn.Close() //nolint:wrapcheck // No need to wrap
+}
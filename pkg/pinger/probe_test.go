@@ -0,0 +1,125 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pinger_test
+
+import (
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/submariner-io/submariner/pkg/pinger"
+)
+
+var _ = Describe("Prober", func() {
+	var (
+		prober    pinger.Interface
+		probeType pinger.ProbeType
+		stopCh    chan struct{}
+		port      = 14800
+	)
+
+	// Each test uses its own port as the responder of the previous test is stopped asynchronously.
+	BeforeEach(func() {
+		port++
+	})
+
+	JustBeforeEach(func() {
+		prober = pinger.NewPinger(pinger.Config{
+			IP:                 "127.0.0.1",
+			Interval:           100 * time.Millisecond,
+			MaxPacketLossCount: 2,
+			ProbeType:          probeType,
+			Port:               port,
+		})
+		prober.Start()
+	})
+
+	AfterEach(func() {
+		prober.Stop()
+	})
+
+	testProbe := func() {
+		When("the responder is running", func() {
+			BeforeEach(func() {
+				stopCh = make(chan struct{})
+				Expect(pinger.NewResponder(port, nil).Start(stopCh)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				close(stopCh)
+			})
+
+			It("should mark the connection as connected and update the statistics", func() {
+				Eventually(func() pinger.ConnectionStatus {
+					return prober.GetLatencyInfo().ConnectionStatus
+				}, 3*time.Second).Should(Equal(pinger.Connected))
+
+				Eventually(func() string {
+					return prober.GetLatencyInfo().Spec.Max
+				}, 3*time.Second).ShouldNot(Equal("0s"))
+			})
+		})
+
+		When("the responder is not running", func() {
+			It("should mark a failure", func() {
+				Eventually(func() pinger.ConnectionStatus {
+					return prober.GetLatencyInfo().ConnectionStatus
+				}, 3*time.Second).Should(Equal(pinger.ConnectionError))
+				Expect(prober.GetLatencyInfo().ConnectionError).ToNot(BeEmpty())
+			})
+		})
+	}
+
+	Context("with the UDP probe type", func() {
+		BeforeEach(func() {
+			probeType = pinger.UDPProbe
+		})
+
+		testProbe()
+
+		When("the responder doesn't allow the source", func() {
+			BeforeEach(func() {
+				stopCh = make(chan struct{})
+				Expect(pinger.NewResponder(port, func(_ net.IP) bool {
+					return false
+				}).Start(stopCh)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				close(stopCh)
+			})
+
+			It("should not reply to the probes", func() {
+				Consistently(func() pinger.ConnectionStatus {
+					return prober.GetLatencyInfo().ConnectionStatus
+				}, 2*time.Second).ShouldNot(Equal(pinger.Connected))
+				Expect(prober.GetLatencyInfo().ConnectionStatus).To(Equal(pinger.ConnectionError))
+			})
+		})
+	})
+
+	Context("with the TCP probe type", func() {
+		BeforeEach(func() {
+			probeType = pinger.TCPProbe
+		})
+
+		testProbe()
+	})
+})
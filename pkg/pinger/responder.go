@@ -0,0 +1,123 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pinger
+
+import (
+	"errors"
+	"net"
+	"strconv"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+)
+
+// Responder answers the UDP and TCP health check probes sent by remote gateways: UDP datagrams are echoed back and TCP
+// connections are accepted and closed.
+type Responder struct {
+	port      int
+	isAllowed func(ip net.IP) bool
+}
+
+// NewResponder creates a Responder which only answers the probes from the source IPs for which isAllowed returns true. A
+// nil isAllowed answers the probes from any source.
+func NewResponder(port int, isAllowed func(ip net.IP) bool) *Responder {
+	if isAllowed == nil {
+		isAllowed = func(_ net.IP) bool {
+			return true
+		}
+	}
+
+	return &Responder{
+		port:      port,
+		isAllowed: isAllowed,
+	}
+}
+
+func (r *Responder) Start(stopCh <-chan struct{}) error {
+	address := net.JoinHostPort("", strconv.Itoa(r.port))
+
+	udpConn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return pkgerrors.Wrapf(err, "error listening on UDP port %d", r.port)
+	}
+
+	tcpListener, err := net.Listen("tcp", address)
+	if err != nil {
+		udpConn.Close()
+		return pkgerrors.Wrapf(err, "error listening on TCP port %d", r.port)
+	}
+
+	go func() {
+		<-stopCh
+		udpConn.Close()
+		tcpListener.Close()
+	}()
+
+	go r.serveUDP(udpConn)
+	go r.serveTCP(tcpListener)
+
+	logger.Infof("Health check responder listening on UDP and TCP port %d", r.port)
+
+	return nil
+}
+
+func (r *Responder) serveUDP(conn net.PacketConn) {
+	buf := make([]byte, 512)
+
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+
+		if err != nil {
+			logger.Warningf("Error reading a UDP health check probe: %v", err)
+			continue
+		}
+
+		if udpAddr, ok := addr.(*net.UDPAddr); !ok || !r.isAllowed(udpAddr.IP) {
+			logger.V(log.TRACE).Infof("Ignoring health check probe from unknown source %s", addr)
+			continue
+		}
+
+		if _, err := conn.WriteTo(buf[:n], addr); err != nil {
+			logger.Warningf("Error replying to the UDP health check probe from %q: %v", addr, err)
+		}
+	}
+}
+
+func (r *Responder) serveTCP(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+
+		if err != nil {
+			logger.Warningf("Error accepting a TCP health check probe: %v", err)
+			continue
+		}
+
+		if addr, ok := conn.RemoteAddr().(*net.TCPAddr); !ok || !r.isAllowed(addr.IP) {
+			logger.V(log.TRACE).Infof("Rejecting a health check probe connection from unknown source %s", conn.RemoteAddr())
+		}
+
+		conn.Close()
+	}
+}
@@ -0,0 +1,75 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pmtu
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	packetTypeProbe = 1
+	packetTypeReply = 2
+
+	headerLength = 12
+)
+
+var magic = []byte("SMPM")
+
+// header is the header of the probe and reply packets. A probe is padded to the size being probed, a reply echoes the
+// probe's identifier along with the number of bytes received so that it can be kept small.
+type header struct {
+	packetType uint8
+	id         uint32
+	size       uint16
+}
+
+func (h *header) marshal(length int) []byte {
+	b := make([]byte, max(length, headerLength))
+	copy(b, magic)
+	b[4] = h.packetType
+	binary.BigEndian.PutUint32(b[6:], h.id)
+	binary.BigEndian.PutUint16(b[10:], h.size)
+
+	return b
+}
+
+func unmarshalHeader(b []byte) (*header, error) {
+	if len(b) < headerLength {
+		return nil, errors.Errorf("packet too short: %d bytes", len(b))
+	}
+
+	if !bytes.Equal(b[:len(magic)], magic) {
+		return nil, errors.New("invalid packet magic")
+	}
+
+	h := &header{
+		packetType: b[4],
+		id:         binary.BigEndian.Uint32(b[6:]),
+		size:       binary.BigEndian.Uint16(b[10:]),
+	}
+
+	if h.packetType != packetTypeProbe && h.packetType != packetTypeReply {
+		return nil, errors.Errorf("invalid packet type %d", h.packetType)
+	}
+
+	return h, nil
+}
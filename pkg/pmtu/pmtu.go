@@ -0,0 +1,230 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pmtu
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	DefaultPort            = 4510
+	DefaultRefreshInterval = 5 * time.Minute
+	DefaultProbeTimeout    = 500 * time.Millisecond
+)
+
+type Interface interface {
+	GetPathMTU(connection *v1.Connection) int
+}
+
+type Config struct {
+	// Port is the UDP port on which probes are answered.
+	Port int
+	// RefreshInterval is the interval at which the path MTU to each remote endpoint is measured again.
+	RefreshInterval time.Duration
+	// ProbeTimeout is the time to wait for the reply to a probe.
+	ProbeTimeout time.Duration
+	// MaxMTU is the largest path MTU probed. It defaults to the MTU of the default gateway interface.
+	MaxMTU int
+}
+
+// Discovery answers path MTU probes from remote gateways and measures the path MTU to the remote endpoints of the
+// local gateway's connections. Measurements are made in the background so GetPathMTU returns the last known value.
+type Discovery struct {
+	mutex   sync.Mutex
+	config  Config
+	results map[string]*result
+}
+
+type result struct {
+	target   string
+	pathMTU  int
+	probedAt time.Time
+	usedAt   time.Time
+	probing  bool
+}
+
+var logger = log.Logger{Logger: logf.Log.WithName("PMTU")}
+
+func NewDiscovery(config Config) *Discovery {
+	if config.Port == 0 {
+		config.Port = DefaultPort
+	}
+
+	if config.RefreshInterval == 0 {
+		config.RefreshInterval = DefaultRefreshInterval
+	}
+
+	if config.ProbeTimeout == 0 {
+		config.ProbeTimeout = DefaultProbeTimeout
+	}
+
+	return &Discovery{
+		config:  config,
+		results: map[string]*result{},
+	}
+}
+
+func (d *Discovery) Port() int {
+	return d.config.Port
+}
+
+// Run starts answering probes on the configured port until the stop channel is closed.
+func (d *Discovery) Run(stopCh <-chan struct{}) error {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{Port: d.config.Port})
+	if err != nil {
+		return errors.Wrapf(err, "error listening on UDP port %d", d.config.Port)
+	}
+
+	logger.Infof("Path MTU discovery responder started on port %d", d.config.Port)
+
+	go d.respond(conn)
+
+	go func() {
+		<-stopCh
+		conn.Close()
+	}()
+
+	return nil
+}
+
+func (d *Discovery) respond(conn *net.UDPConn) {
+	buf := make([]byte, maxPacketSize)
+
+	for {
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			logger.Warningf("Error reading path MTU probe: %v", err)
+
+			continue
+		}
+
+		h, err := unmarshalHeader(buf[:n])
+		if err != nil || h.packetType != packetTypeProbe {
+			logger.V(log.TRACE).Infof("Ignoring invalid path MTU probe from %s: %v", addr, err)
+			continue
+		}
+
+		if int(h.size) != n {
+			continue
+		}
+
+		reply := &header{packetType: packetTypeReply, id: h.id, size: h.size}
+
+		if _, err := conn.WriteToUDP(reply.marshal(headerLength), addr); err != nil {
+			logger.V(log.DEBUG).Infof("Error replying to path MTU probe from %s: %v", addr, err)
+		}
+	}
+}
+
+// GetPathMTU returns the last measured path MTU to the remote endpoint of the given connection, or 0 if it's unknown.
+// A measurement is started in the background if there's none yet or the last one is older than the refresh interval.
+// Nothing is measured for endpoints which don't advertise a path MTU discovery port.
+func (d *Discovery) GetPathMTU(connection *v1.Connection) int {
+	port, err := connection.Endpoint.GetBackendPort(v1.PMTUPortConfig, 0)
+	if err != nil || port == 0 || connection.UsingIP == "" || connection.Status != v1.Connected {
+		return 0
+	}
+
+	target := net.JoinHostPort(connection.UsingIP, strconv.Itoa(int(port)))
+	now := time.Now()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	r, found := d.results[connection.Endpoint.CableName]
+	if !found || r.target != target {
+		d.pruneResults(now)
+
+		r = &result{target: target}
+		d.results[connection.Endpoint.CableName] = r
+	}
+
+	r.usedAt = now
+
+	if !r.probing && now.Sub(r.probedAt) >= d.config.RefreshInterval {
+		r.probing = true
+
+		go d.measure(connection.Endpoint.CableName, r)
+	}
+
+	return r.pathMTU
+}
+
+func (d *Discovery) measure(cableName string, r *result) {
+	pathMTU, err := d.probe(r.target)
+	if err != nil {
+		logger.Errorf(err, "Error probing the path MTU to %q at %s", cableName, r.target)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if err == nil {
+		if pathMTU != r.pathMTU {
+			logger.Infof("Path MTU to %q at %s is %d", cableName, r.target, pathMTU)
+		}
+
+		r.pathMTU = pathMTU
+	}
+
+	r.probedAt = time.Now()
+	r.probing = false
+}
+
+func (d *Discovery) probe(target string) (int, error) {
+	remote, err := net.ResolveUDPAddr("udp", target)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error resolving %q", target)
+	}
+
+	maxMTU := d.config.MaxMTU
+	if maxMTU == 0 {
+		iface, err := netlinkAPI.GetDefaultGatewayInterface()
+		if err != nil {
+			return 0, errors.Wrap(err, "error retrieving the default gateway interface")
+		}
+
+		maxMTU = iface.MTU
+	}
+
+	return Probe(remote, maxMTU, d.config.ProbeTimeout)
+}
+
+// pruneResults removes the results for endpoints which haven't been queried for a few refresh intervals, ie which
+// aren't connected anymore.
+func (d *Discovery) pruneResults(now time.Time) {
+	for cableName, r := range d.results {
+		if now.Sub(r.usedAt) > 3*d.config.RefreshInterval {
+			delete(d.results, cableName)
+		}
+	}
+}
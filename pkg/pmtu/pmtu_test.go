@@ -0,0 +1,203 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pmtu_test
+
+import (
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/pmtu"
+)
+
+const (
+	maxMTU       = 1500
+	probeTimeout = 50 * time.Millisecond
+)
+
+var _ = Describe("Probe", func() {
+	var responderPort int
+
+	BeforeEach(func() {
+		responderPort = startDiscovery(pmtu.Config{})
+	})
+
+	When("the path supports the maximum MTU", func() {
+		It("should return the maximum MTU", func() {
+			Expect(pmtu.Probe(loopbackAddr(responderPort), maxMTU, probeTimeout)).To(Equal(maxMTU))
+		})
+	})
+
+	When("the path drops packets larger than its MTU", func() {
+		It("should return the path MTU", func() {
+			relayPort := startRelay(responderPort, 1400)
+			Expect(pmtu.Probe(loopbackAddr(relayPort), maxMTU, probeTimeout)).To(Equal(1400))
+
+			relayPort = startRelay(responderPort, 1111)
+			Expect(pmtu.Probe(loopbackAddr(relayPort), maxMTU, probeTimeout)).To(Equal(1111))
+		})
+	})
+
+	When("the maximum MTU is lower than the minimum MTU", func() {
+		It("should probe the minimum MTU", func() {
+			Expect(pmtu.Probe(loopbackAddr(responderPort), 100, probeTimeout)).To(Equal(pmtu.MinIPv4MTU))
+		})
+	})
+
+	When("there's no responder", func() {
+		It("should return 0", func() {
+			Expect(pmtu.Probe(loopbackAddr(freePort()), maxMTU, probeTimeout)).To(Equal(0))
+		})
+	})
+})
+
+var _ = Describe("Discovery", func() {
+	var (
+		discovery  *pmtu.Discovery
+		connection *v1.Connection
+	)
+
+	BeforeEach(func() {
+		responderPort := startDiscovery(pmtu.Config{})
+
+		discovery = pmtu.NewDiscovery(pmtu.Config{
+			MaxMTU:          maxMTU,
+			ProbeTimeout:    probeTimeout,
+			RefreshInterval: 100 * time.Millisecond,
+		})
+
+		connection = &v1.Connection{
+			Status:  v1.Connected,
+			UsingIP: "127.0.0.1",
+			Endpoint: v1.EndpointSpec{
+				CableName: "submariner-cable-east-192-68-2-1",
+				BackendConfig: map[string]string{
+					v1.PMTUPortConfig: strconv.Itoa(startRelay(responderPort, 1400)),
+				},
+			},
+		}
+	})
+
+	It("should measure the path MTU to the remote endpoint in the background", func() {
+		Expect(discovery.GetPathMTU(connection)).To(Equal(0))
+		Eventually(func() int {
+			return discovery.GetPathMTU(connection)
+		}).Should(Equal(1400))
+	})
+
+	When("the remote endpoint doesn't advertise a port", func() {
+		It("should not measure the path MTU", func() {
+			delete(connection.Endpoint.BackendConfig, v1.PMTUPortConfig)
+			Consistently(func() int {
+				return discovery.GetPathMTU(connection)
+			}, 300*time.Millisecond).Should(Equal(0))
+		})
+	})
+
+	When("the connection isn't established", func() {
+		It("should not measure the path MTU", func() {
+			connection.Status = v1.Connecting
+			Consistently(func() int {
+				return discovery.GetPathMTU(connection)
+			}, 300*time.Millisecond).Should(Equal(0))
+		})
+	})
+})
+
+func startDiscovery(config pmtu.Config) int {
+	config.Port = freePort()
+	discovery := pmtu.NewDiscovery(config)
+
+	stopCh := make(chan struct{})
+	Expect(discovery.Run(stopCh)).To(Succeed())
+	DeferCleanup(func() {
+		close(stopCh)
+	})
+
+	return discovery.Port()
+}
+
+// startRelay starts a UDP relay to the given port on the loopback interface, which drops the packets larger than the
+// given MTU to simulate a path with a lower MTU.
+func startRelay(port, mtu int) int {
+	relay, err := net.ListenUDP("udp4", loopbackAddr(0))
+	Expect(err).To(Succeed())
+
+	upstream, err := net.DialUDP("udp4", nil, loopbackAddr(port))
+	Expect(err).To(Succeed())
+
+	DeferCleanup(func() {
+		relay.Close()
+		upstream.Close()
+	})
+
+	var client atomic.Pointer[net.UDPAddr]
+
+	go func() {
+		buf := make([]byte, 65535)
+
+		for {
+			n, addr, err := relay.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+
+			client.Store(addr)
+
+			// The IPv4 and UDP headers account for 28 bytes.
+			if n+28 <= mtu {
+				_, _ = upstream.Write(buf[:n])
+			}
+		}
+	}()
+
+	go func() {
+		buf := make([]byte, 65535)
+
+		for {
+			n, err := upstream.Read(buf)
+			if err != nil {
+				return
+			}
+
+			if addr := client.Load(); addr != nil {
+				_, _ = relay.WriteToUDP(buf[:n], addr)
+			}
+		}
+	}()
+
+	return relay.LocalAddr().(*net.UDPAddr).Port
+}
+
+func loopbackAddr(port int) *net.UDPAddr {
+	return &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}
+}
+
+func freePort() int {
+	conn, err := net.ListenUDP("udp4", loopbackAddr(0))
+	Expect(err).To(Succeed())
+
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
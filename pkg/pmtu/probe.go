@@ -0,0 +1,174 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pmtu
+
+import (
+	"math/rand/v2"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// MinIPv4MTU is the minimum MTU every IPv4 path must support, per RFC 791.
+	MinIPv4MTU = 576
+	// MinIPv6MTU is the minimum MTU every IPv6 path must support, per RFC 8200.
+	MinIPv6MTU = 1280
+
+	ipv4UDPHeaderLength = 28
+	ipv6UDPHeaderLength = 48
+	maxPacketSize       = 65535
+	probeAttempts       = 3
+)
+
+// Probe determines the path MTU to the responder at the given address. Probes are sent with the DF bit set, bypassing
+// the kernel's path MTU cache, and the largest packet size which gets a reply is found by a binary search between the
+// minimum MTU of the address family and maxMTU. Each size is attempted up to 3 times, waiting for a reply for the given
+// timeout. It returns 0 if no probe is answered.
+func Probe(remote *net.UDPAddr, maxMTU int, timeout time.Duration) (int, error) {
+	conn, err := net.DialUDP("udp", nil, remote)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error creating a UDP socket to %s", remote)
+	}
+
+	defer conn.Close()
+
+	minMTU, headerLen := MinIPv4MTU, ipv4UDPHeaderLength
+	if remote.IP.To4() == nil {
+		minMTU, headerLen = MinIPv6MTU, ipv6UDPHeaderLength
+	}
+
+	if err := setDontFragment(conn, remote.IP.To4() == nil); err != nil {
+		return 0, err
+	}
+
+	p := &prober{conn: conn, headerLen: headerLen, timeout: timeout}
+
+	maxMTU = min(maxMTU, maxPacketSize)
+	if maxMTU <= minMTU {
+		maxMTU = minMTU
+	}
+
+	// Most paths support the full local MTU so check that first.
+	ok, err := p.probe(maxMTU)
+	if ok || err != nil {
+		return maxMTU, err
+	}
+
+	ok, err = p.probe(minMTU)
+	if !ok || err != nil {
+		return 0, err
+	}
+
+	low, high := minMTU, maxMTU
+	for high-low > 1 {
+		mid := low + (high-low)/2
+
+		ok, err := p.probe(mid)
+		if err != nil {
+			return 0, err
+		}
+
+		if ok {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	return low, nil
+}
+
+type prober struct {
+	conn      *net.UDPConn
+	headerLen int
+	timeout   time.Duration
+}
+
+// probe returns whether a packet of the given size, including the IP and UDP headers, reaches the responder.
+func (p *prober) probe(size int) (bool, error) {
+	h := &header{packetType: packetTypeProbe, size: uint16(size - p.headerLen)} //nolint:gosec // Bounded by maxPacketSize
+	buf := make([]byte, 64)
+
+	for range probeAttempts {
+		h.id = rand.Uint32() //nolint:gosec // Doesn't need to be cryptographically secure
+
+		_, err := p.conn.Write(h.marshal(int(h.size)))
+		if errors.Is(err, syscall.EMSGSIZE) {
+			// The packet exceeds the MTU of the outgoing interface or a path MTU the kernel already knows about.
+			return false, nil
+		}
+
+		if err != nil {
+			return false, errors.Wrap(err, "error sending probe")
+		}
+
+		if err := p.conn.SetReadDeadline(time.Now().Add(p.timeout)); err != nil {
+			return false, errors.Wrap(err, "error setting the read deadline")
+		}
+
+		for {
+			n, err := p.conn.Read(buf)
+			if err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					break
+				}
+
+				// ICMP errors, eg port unreachable, are reported on the next read on connected sockets.
+				logger.V(log.DEBUG).Infof("Error reading probe reply from %s: %v", p.conn.RemoteAddr(), err)
+
+				break
+			}
+
+			reply, err := unmarshalHeader(buf[:n])
+			if err == nil && reply.packetType == packetTypeReply && reply.id == h.id && reply.size == h.size {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func setDontFragment(conn *net.UDPConn, ipv6 bool) error {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return errors.Wrap(err, "error retrieving the raw socket")
+	}
+
+	var sockErr error
+
+	err = rawConn.Control(func(fd uintptr) {
+		if ipv6 {
+			sockErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IPV6, unix.IPV6_MTU_DISCOVER, unix.IPV6_PMTUDISC_PROBE)
+		} else {
+			sockErr = unix.SetsockoptInt(int(fd), unix.IPPROTO_IP, unix.IP_MTU_DISCOVER, unix.IP_PMTUDISC_PROBE)
+		}
+	})
+	if err == nil {
+		err = sockErr
+	}
+
+	return errors.Wrap(err, "error setting the DF bit on the probe socket")
+}
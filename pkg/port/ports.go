@@ -22,4 +22,6 @@ const (
 	NATTDiscovery     = 4490
 	ExternalTunnel    = 4500
 	IntraClusterVxLAN = 4800
+	HealthCheckProbe  = 4801
+	Benchmark         = 4520
 )
@@ -30,6 +30,18 @@ const (
 	RemoteCIDRIPSet    = "SUBMARINER-REMOTECIDRS"
 	LocalCIDRIPSet     = "SUBMARINER-LOCALCIDRS"
 
+	// Prefix of the IP sets holding the CIDRs of a single remote cluster.
+	RemoteClusterCIDRIPSetPrefix = "SM-REMOTECIDRS-"
+
+	// Filter chain enforcing the ClusterTrafficPolicy resources on the gateway, the two chains it alternately jumps to
+	// when the rules are re-programmed and the prefix of the IP sets holding the CIDRs of a single remote cluster that
+	// they reference.
+	SmTrafficPolicyChain       = "SUBMARINER-TRAFFIC-POLICY"
+	SmTrafficPolicyChainA      = "SUBMARINER-TRAFFIC-POLICY-A"
+	SmTrafficPolicyChainB      = "SUBMARINER-TRAFFIC-POLICY-B"
+	TrafficPolicyIPSetPrefix   = "SM-TRAFFICPOL-"
+	TrafficPolicyIPv6SetPrefix = "SM-TRAFFICPOL6-"
+
 	RouteAgentInterClusterNetworkTableID = 149
 
 	// To support connectivity for Pods with HostNetworking on the GatewayNode, we program
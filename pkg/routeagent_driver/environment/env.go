@@ -27,4 +27,6 @@ type Specification struct {
 	ProfilePort int `default:"32782"`
 	Uninstall   bool
 	WaitForNode bool
+	// PacketFilterDriver overrides the auto-detected packet filter driver ("iptables" or "nftables").
+	PacketFilterDriver string `split_words:"true"`
 }
@@ -0,0 +1,167 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeproxy
+
+import (
+	"bytes"
+	"net"
+	"slices"
+	"syscall"
+
+	"github.com/pkg/errors"
+	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/vxlan"
+)
+
+// With active-active gateways, the non-gateway nodes don't point their VxLAN interface at a single gateway. Instead,
+// the interface has an FDB entry for each gateway and the routes to the remote subnets have a next hop via each
+// gateway's VTEP so the flows are spread across the gateways using ECMP.
+
+func (kp *SyncHandler) activeActiveEndpointCreated(endpoint *submV1.Endpoint) error {
+	gwIP := net.ParseIP(endpoint.Spec.PrivateIP)
+
+	prevIP, exists := kp.activeActiveGws[endpoint.Spec.Hostname]
+	kp.activeActiveGws[endpoint.Spec.Hostname] = gwIP
+
+	if kp.State().IsOnGateway() {
+		return nil
+	}
+
+	if !kp.isActiveActiveVxLAN() {
+		return kp.ensureActiveActiveVxLAN()
+	}
+
+	if exists && prevIP.Equal(gwIP) {
+		return nil
+	}
+
+	if exists {
+		if err := kp.vxlanDevice.DelFDB(prevIP, "00:00:00:00:00:00"); err != nil {
+			return errors.Wrapf(err, "failed to delete the FDB entry for gateway %q", endpoint.Spec.Hostname)
+		}
+	}
+
+	logger.Infof("Adding active-active gateway %q with IP %s to the vxlan interface", endpoint.Spec.Hostname, gwIP)
+
+	if err := kp.vxlanDevice.AddFDB(gwIP, "00:00:00:00:00:00"); err != nil {
+		return errors.Wrapf(err, "failed to add the FDB entry for gateway %q", endpoint.Spec.Hostname)
+	}
+
+	return errors.Wrap(kp.reconcileRoutes(), "error while reconciling routes")
+}
+
+func (kp *SyncHandler) activeActiveEndpointRemoved(endpoint *submV1.Endpoint) error {
+	gwIP, exists := kp.activeActiveGws[endpoint.Spec.Hostname]
+	if !exists {
+		return nil
+	}
+
+	delete(kp.activeActiveGws, endpoint.Spec.Hostname)
+
+	if kp.State().IsOnGateway() || !kp.isActiveActiveVxLAN() {
+		return nil
+	}
+
+	if len(kp.activeActiveGws) == 0 {
+		err := kp.vxlanDevice.DeleteLinkDevice()
+		kp.vxlanDevice = nil
+
+		return errors.Wrap(err, "failed to delete the vxlan interface on removal of the last gateway")
+	}
+
+	logger.Infof("Removing active-active gateway %q with IP %s from the vxlan interface", endpoint.Spec.Hostname, gwIP)
+
+	if err := kp.vxlanDevice.DelFDB(gwIP, "00:00:00:00:00:00"); err != nil {
+		return errors.Wrapf(err, "failed to delete the FDB entry for gateway %q", endpoint.Spec.Hostname)
+	}
+
+	return errors.Wrap(kp.reconcileRoutes(), "error while reconciling routes")
+}
+
+// isActiveActiveVxLAN returns true if the current VxLAN interface is a non-gateway interface that spans all the
+// active-active gateways.
+func (kp *SyncHandler) isActiveActiveVxLAN() bool {
+	return kp.vxlanDevice != nil && kp.activeEndpointHostname == "" && len(kp.activeActiveGws) > 0
+}
+
+// ensureActiveActiveVxLAN (re)creates the non-gateway VxLAN interface with an FDB entry for each active-active gateway
+// and reconciles the routes.
+func (kp *SyncHandler) ensureActiveActiveVxLAN() error {
+	if len(kp.activeActiveGws) == 0 {
+		return nil
+	}
+
+	// Any existing interface either points at a single gateway or was configured while this node was a gateway.
+	if kp.vxlanDevice != nil {
+		if err := kp.vxlanDevice.DeleteLinkDevice(); err != nil {
+			return errors.Wrap(err, "failed to delete the existing vxlan interface")
+		}
+
+		kp.vxlanDevice = nil
+	}
+
+	kp.activeEndpointHostname = ""
+	kp.vxlanGwIP = nil
+	kp.vxlanGwIPv6 = nil
+
+	logger.Infof("Creating the vxlan interface %s for %d active-active gateways", VxLANIface, len(kp.activeActiveGws))
+
+	if err := kp.createVxLANInterface(VxInterfaceWorker, nil); err != nil {
+		return errors.Wrap(err, "failed to create the vxlan interface for active-active gateways")
+	}
+
+	for hostname, gwIP := range kp.activeActiveGws {
+		if err := kp.vxlanDevice.AddFDB(gwIP, "00:00:00:00:00:00"); err != nil {
+			return errors.Wrapf(err, "failed to add the FDB entry for gateway %q", hostname)
+		}
+	}
+
+	return errors.Wrap(kp.reconcileRoutes(), "error while reconciling routes")
+}
+
+// activeActiveVtepIPs returns the sorted VTEP IPs of the given family for the active-active gateways.
+func (kp *SyncHandler) activeActiveVtepIPs(family int) []net.IP {
+	vtepIPs := make([]net.IP, 0, len(kp.activeActiveGws))
+
+	for _, gwIP := range kp.activeActiveGws {
+		var (
+			vtepIP net.IP
+			err    error
+		)
+
+		if family == syscall.AF_INET6 {
+			vtepIP, err = vxlan.GetVtepIPv6AddressFrom(gwIP.String(), net.ParseIP(VxLANVTepIPv6NetworkPrefix))
+		} else {
+			vtepIP, err = vxlan.GetVtepIPAddressFrom(gwIP.String(), VxLANVTepNetworkPrefix)
+		}
+
+		if err != nil {
+			logger.Errorf(err, "Failed to derive the VTEP IP for gateway IP %s", gwIP)
+			continue
+		}
+
+		vtepIPs = append(vtepIPs, vtepIP)
+	}
+
+	slices.SortFunc(vtepIPs, func(a, b net.IP) int {
+		return bytes.Compare(a, b)
+	})
+
+	return vtepIPs
+}
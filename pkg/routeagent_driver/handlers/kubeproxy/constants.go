@@ -44,6 +44,10 @@ const (
 
 	VxLANVTepNetworkPrefix = 240
 	SmRouteAgentFilter     = "app=submariner-routeagent"
+
+	// In dual-stack clusters, the IPv6 VxLAN vtepIP is derived by embedding the host IPv4 address in this
+	// unique local (fc00::/7) /96 prefix, eg "fd00:5ab::c0a8:164" for host IP "192.168.1.100".
+	VxLANVTepIPv6NetworkPrefix = "fd00:5ab::"
 )
 
 type Operation int
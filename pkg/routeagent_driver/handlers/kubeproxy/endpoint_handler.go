@@ -31,6 +31,10 @@ import (
 func (kp *SyncHandler) LocalEndpointCreated(endpoint *submV1.Endpoint) error {
 	kp.localEndpointIfaceName = endpoint.Spec.BackendConfig[cable.InterfaceNameConfig]
 
+	if endpoint.Spec.IsActiveActive() {
+		return kp.activeActiveEndpointCreated(endpoint)
+	}
+
 	// We are on nonGateway node
 	if !kp.State().IsOnGateway() {
 		// If the node already has a vxLAN interface that points to an oldEndpoint
@@ -63,7 +67,17 @@ func (kp *SyncHandler) LocalEndpointCreated(endpoint *submV1.Endpoint) error {
 		kp.vxlanGwIP = &remoteVtepIP
 		kp.activeEndpointHostname = endpoint.Spec.Hostname
 
-		err = kp.reconcileRoutes(remoteVtepIP)
+		if kp.isDualStack() {
+			remoteVtepIPv6, err := vxlan.GetVtepIPv6AddressFrom(localClusterGwNodeIP.String(),
+				net.ParseIP(VxLANVTepIPv6NetworkPrefix))
+			if err != nil {
+				return errors.Wrap(err, "failed to derive the remote IPv6 vtepIP")
+			}
+
+			kp.vxlanGwIPv6 = &remoteVtepIPv6
+		}
+
+		err = kp.reconcileRoutes()
 		if err != nil {
 			return errors.Wrap(err, "error while reconciling routes")
 		}
@@ -73,11 +87,16 @@ func (kp *SyncHandler) LocalEndpointCreated(endpoint *submV1.Endpoint) error {
 }
 
 func (kp *SyncHandler) LocalEndpointRemoved(endpoint *submV1.Endpoint) error {
+	if endpoint.Spec.IsActiveActive() {
+		return kp.activeActiveEndpointRemoved(endpoint)
+	}
+
 	// If the vxLAN device exists and it points to the same endpoint, delete it.
 	if kp.vxlanDevice != nil && kp.activeEndpointHostname == endpoint.Spec.Hostname {
 		err := kp.vxlanDevice.DeleteLinkDevice()
 		kp.vxlanDevice = nil
 		kp.vxlanGwIP = nil
+		kp.vxlanGwIPv6 = nil
 		kp.activeEndpointHostname = ""
 
 		if err != nil {
@@ -113,6 +132,13 @@ func (kp *SyncHandler) RemoteEndpointCreated(endpoint *submV1.Endpoint) error {
 	// Add routes to the new endpoint on the GatewayNode.
 	kp.updateRoutingRulesForHostNetworkSupport(endpoint.Spec.Subnets, Add)
 	kp.updateIptableRulesForInterClusterTraffic(endpoint.Spec.Subnets, Add)
+	kp.updateTrafficPolicies()
+
+	return nil
+}
+
+func (kp *SyncHandler) RemoteEndpointUpdated(_ *submV1.Endpoint) error {
+	kp.updateTrafficPolicies()
 
 	return nil
 }
@@ -131,6 +157,7 @@ func (kp *SyncHandler) RemoteEndpointRemoved(endpoint *submV1.Endpoint) error {
 
 	kp.updateRoutingRulesForHostNetworkSupport(endpoint.Spec.Subnets, Delete)
 	kp.updateIptableRulesForInterClusterTraffic(endpoint.Spec.Subnets, Delete)
+	kp.updateTrafficPolicies()
 
 	return nil
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/submariner-io/admiral/pkg/log"
 	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	"github.com/vishvananda/netlink"
 )
 
 func (kp *SyncHandler) TransitionToNonGateway() error {
@@ -31,13 +32,17 @@ func (kp *SyncHandler) TransitionToNonGateway() error {
 	// If the active Gateway transitions to a new node, we flush the HostNetwork routing table.
 	kp.updateRoutingRulesForHostNetworkSupport(nil, Flush)
 
-	err := kp.netLink.RuleDelIfPresent(netlinkAPI.NewTableRule(constants.RouteAgentHostNetworkTableID))
-	if err != nil {
-		logger.Errorf(err, "Unable to delete ip rule to table %d on non-Gateway node %s",
-			constants.RouteAgentHostNetworkTableID, kp.hostname)
+	for _, rule := range kp.hostNetworkTableRules() {
+		err := kp.netLink.RuleDelIfPresent(rule)
+		if err != nil {
+			logger.Errorf(err, "Unable to delete ip rule to table %d on non-Gateway node %s",
+				constants.RouteAgentHostNetworkTableID, kp.hostname)
+		}
 	}
 
-	return nil
+	kp.updateTrafficPolicies()
+
+	return kp.ensureActiveActiveVxLAN()
 }
 
 func (kp *SyncHandler) TransitionToGateway() error {
@@ -47,6 +52,13 @@ func (kp *SyncHandler) TransitionToGateway() error {
 
 	logger.Infof("Creating the vxlan interface: %s on the gateway node", VxLANIface)
 
+	// The non-gateway interface for active-active gateways has FDB entries for the other gateways so re-create it.
+	if kp.isActiveActiveVxLAN() {
+		if err := kp.vxlanDevice.DeleteLinkDevice(); err != nil {
+			logger.Errorf(err, "Unable to delete the active-active vxlan interface on gateway node (%s)", kp.hostname)
+		}
+	}
+
 	kp.activeEndpointHostname = kp.hostname
 
 	err := kp.createVxLANInterface(VxInterfaceGateway, nil)
@@ -54,14 +66,27 @@ func (kp *SyncHandler) TransitionToGateway() error {
 		logger.Fatalf("Unable to create VxLAN interface on gateway node (%s): %v", kp.hostname, err)
 	}
 
-	err = kp.netLink.RuleAddIfNotPresent(netlinkAPI.NewTableRule(constants.RouteAgentHostNetworkTableID))
-	if err != nil {
-		logger.Errorf(err, "Unable to add ip rule to table %d on Gateway node %s",
-			constants.RouteAgentHostNetworkTableID, kp.hostname)
+	for _, rule := range kp.hostNetworkTableRules() {
+		err = kp.netLink.RuleAddIfNotPresent(rule)
+		if err != nil {
+			logger.Errorf(err, "Unable to add ip rule to table %d on Gateway node %s",
+				constants.RouteAgentHostNetworkTableID, kp.hostname)
+		}
 	}
 
 	// Add routes to the new endpoint on the GatewayNode.
 	kp.updateRoutingRulesForHostNetworkSupport(kp.remoteSubnets.UnsortedList(), Add)
+	kp.updateTrafficPolicies()
 
 	return nil
 }
+
+func (kp *SyncHandler) hostNetworkTableRules() []*netlink.Rule {
+	rules := []*netlink.Rule{netlinkAPI.NewTableRule(constants.RouteAgentHostNetworkTableID)}
+
+	if kp.isDualStack() {
+		rules = append(rules, netlinkAPI.NewTableRuleForFamily(constants.RouteAgentHostNetworkTableID, netlink.FAMILY_V6))
+	}
+
+	return rules
+}
@@ -21,10 +21,13 @@ package kubeproxy
 import (
 	"net"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/admiral/pkg/watcher"
+	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/cidr"
 	cni "github.com/submariner-io/submariner/pkg/cni"
 	"github.com/submariner-io/submariner/pkg/event"
@@ -34,6 +37,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
+	k8snet "k8s.io/utils/net"
 	"k8s.io/utils/set"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -50,31 +54,70 @@ type SyncHandler struct {
 	remoteVTEPs            set.Set[string]
 	routeCacheGWNode       set.Set[string]
 	pFilter                packetfilter.Interface
+	pFilterV6              packetfilter.Interface
 	netLink                netlink.Interface
 	vxlanDevice            *vxlan.Interface
 	vxlanGwIP              *net.IP
+	vxlanGwIPv6            *net.IP
 	hostname               string
 	cniIface               *cni.Interface
 	defaultHostIface       *net.Interface
 	activeEndpointHostname string
+	// activeActiveGws maps the host name of each local active-active gateway to its private IP.
+	activeActiveGws map[string]net.IP
+
+	watcherConfig      *watcher.Config
+	stopCh             chan struct{}
+	trafficPolicyMutex sync.Mutex
+	trafficPolicies    map[string]*submV1.ClusterTrafficPolicySpec
+	trafficPolicySets  map[string]packetfilter.NamedSet
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("KubeProxy")}
 
-func NewSyncHandler(localClusterCidr, localServiceCidr []string) *SyncHandler {
+// NewSyncHandler creates the handler for the kube-proxy based CNIs. If a watcher config is given, the
+// ClusterTrafficPolicy resources are enforced on the gateway.
+func NewSyncHandler(localClusterCidr, localServiceCidr []string, watcherConfig *watcher.Config) *SyncHandler {
 	pFilter, err := packetfilter.New()
 	utilruntime.Must(err)
 
-	return &SyncHandler{
-		localClusterCidr: cidr.ExtractIPv4Subnets(localClusterCidr),
-		localServiceCidr: cidr.ExtractIPv4Subnets(localServiceCidr),
-		remoteSubnets:    set.New[string](),
-		remoteSubnetGw:   map[string]net.IP{},
-		remoteVTEPs:      set.New[string](),
-		routeCacheGWNode: set.New[string](),
-		netLink:          netlink.New(),
-		pFilter:          pFilter,
+	kp := &SyncHandler{
+		localClusterCidr:  append(cidr.ExtractIPv4Subnets(localClusterCidr), cidr.ExtractIPv6Subnets(localClusterCidr)...),
+		localServiceCidr:  append(cidr.ExtractIPv4Subnets(localServiceCidr), cidr.ExtractIPv6Subnets(localServiceCidr)...),
+		remoteSubnets:     set.New[string](),
+		remoteSubnetGw:    map[string]net.IP{},
+		remoteVTEPs:       set.New[string](),
+		routeCacheGWNode:  set.New[string](),
+		activeActiveGws:   map[string]net.IP{},
+		netLink:           netlink.New(),
+		pFilter:           pFilter,
+		watcherConfig:     watcherConfig,
+		stopCh:            make(chan struct{}),
+		trafficPolicies:   map[string]*submV1.ClusterTrafficPolicySpec{},
+		trafficPolicySets: map[string]packetfilter.NamedSet{},
 	}
+
+	// The IPv6 data path is only programmed in dual-stack clusters.
+	if len(cidr.ExtractIPv6Subnets(localClusterCidr)) > 0 {
+		kp.pFilterV6, err = packetfilter.NewForFamily(k8snet.IPv6)
+		utilruntime.Must(err)
+	}
+
+	return kp
+}
+
+func (kp *SyncHandler) isDualStack() bool {
+	return kp.pFilterV6 != nil
+}
+
+// pFilterFor returns the packet filter for the IP family of the given CIDR or nil if the local cluster doesn't support
+// that IP family.
+func (kp *SyncHandler) pFilterFor(cidrBlock string) packetfilter.Interface {
+	if k8snet.IsIPv6CIDRString(cidrBlock) {
+		return kp.pFilterV6
+	}
+
+	return kp.pFilter
 }
 
 func (kp *SyncHandler) GetName() string {
@@ -148,5 +191,8 @@ func (kp *SyncHandler) Init() error {
 		return errors.Wrapf(err, "createPFilterChains returned error")
 	}
 
-	return nil
+	// Remove the ClusterTrafficPolicy rules left over by a previous instance, they're re-programmed once on the gateway.
+	kp.updateTrafficPolicies()
+
+	return kp.startTrafficPolicyWatcher()
 }
@@ -19,17 +19,33 @@ limitations under the License.
 package kubeproxy
 
 import (
+	"slices"
 	"strconv"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/submariner/pkg/cidr"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
+	"github.com/submariner-io/submariner/pkg/packetfilter/configure"
 	"github.com/submariner-io/submariner/pkg/port"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	k8snet "k8s.io/utils/net"
 )
 
 func (kp *SyncHandler) createPFilterChains() error {
-	ipHookChains := []packetfilter.ChainIPHook{
+	if err := kp.createPFilterChainsFor(kp.pFilter, k8snet.IPv4); err != nil {
+		return err
+	}
+
+	if kp.pFilterV6 != nil {
+		return kp.createPFilterChainsFor(kp.pFilterV6, k8snet.IPv6)
+	}
+
+	return nil
+}
+
+var (
+	ipHookChains = []packetfilter.ChainIPHook{
 		{
 			Name:     constants.SmPostRoutingChain,
 			Type:     packetfilter.ChainTypeNAT,
@@ -55,14 +71,41 @@ func (kp *SyncHandler) createPFilterChains() error {
 		},
 	}
 
+	trafficPolicyChains = []string{constants.SmTrafficPolicyChain, constants.SmTrafficPolicyChainA, constants.SmTrafficPolicyChainB}
+)
+
+// PacketFilterMigrationSpec returns the chains programmed by the kubeproxy handler.
+func PacketFilterMigrationSpec() *configure.MigrationSpec {
+	spec := &configure.MigrationSpec{
+		IPHookChains: slices.Clone(ipHookChains),
+	}
+
+	for _, chain := range trafficPolicyChains {
+		spec.Chains = append(spec.Chains, configure.TableChain{Table: packetfilter.TableTypeFilter, Name: chain})
+	}
+
+	return spec
+}
+
+func (kp *SyncHandler) createPFilterChainsFor(pFilter packetfilter.Interface, family k8snet.IPFamily) error {
 	for i := range ipHookChains {
 		logger.V(log.DEBUG).Infof("Install/ensure %q/%s IPHook chain exists", ipHookChains[i].Name, "NAT")
 
-		if err := kp.pFilter.CreateIPHookChainIfNotExists(&ipHookChains[i]); err != nil {
+		if err := pFilter.CreateIPHookChainIfNotExists(&ipHookChains[i]); err != nil {
 			return errors.Wrapf(err, "error installing IPHook chain %q", ipHookChains[i].Name)
 		}
 	}
 
+	for _, chain := range trafficPolicyChains {
+		logger.V(log.DEBUG).Infof("Install/ensure %q chain exists", chain)
+
+		if err := pFilter.CreateChainIfNotExists(packetfilter.TableTypeFilter, &packetfilter.Chain{
+			Name: chain,
+		}); err != nil {
+			return errors.Wrapf(err, "error creating chain %q", chain)
+		}
+	}
+
 	logger.V(log.DEBUG).Infof("Allow VxLAN incoming traffic in %q Chain", constants.SmInputChain)
 
 	ruleSpec := packetfilter.Rule{
@@ -71,32 +114,34 @@ func (kp *SyncHandler) createPFilterChains() error {
 		Action: packetfilter.RuleActionAccept,
 	}
 
-	if err := kp.pFilter.AppendUnique(packetfilter.TableTypeFilter, constants.SmInputChain, &ruleSpec); err != nil {
+	if err := pFilter.AppendUnique(packetfilter.TableTypeFilter, constants.SmInputChain, &ruleSpec); err != nil {
 		return errors.Wrapf(err, "unable to append rule %+v", &ruleSpec)
 	}
 
 	logger.V(log.DEBUG).Infof("Insert rule to allow traffic over %s interface in %s Chain", VxLANIface, constants.SmForwardChain)
 
+	// The ClusterTrafficPolicy rules must be evaluated before the traffic over the VxLAN interface is accepted.
 	ruleSpec = packetfilter.Rule{
 		OutInterface: VxLANIface,
 		Action:       packetfilter.RuleActionAccept,
 	}
-	if err := kp.pFilter.PrependUnique(packetfilter.TableTypeFilter, constants.SmForwardChain, &ruleSpec); err != nil {
+	if err := pFilter.PrependUnique(packetfilter.TableTypeFilter, constants.SmForwardChain, trafficPolicyJumpRule(),
+		&ruleSpec); err != nil {
 		return errors.Wrapf(err, "unable to append rule %+v to allow vxlan traffic", &ruleSpec)
 	}
 
-	if kp.cniIface != nil {
+	if vtepCIDR, cniIP := kp.hostNetworkSNATFor(family); cniIP != "" {
 		// Program rules to support communication from HostNetwork to remoteCluster
 		ruleSpec = packetfilter.Rule{
 			OutInterface: VxLANIface,
-			SrcCIDR:      strconv.Itoa(VxLANVTepNetworkPrefix) + ".0.0.0/8",
-			SnatCIDR:     kp.cniIface.IPAddress,
+			SrcCIDR:      vtepCIDR,
+			SnatCIDR:     cniIP,
 			Action:       packetfilter.RuleActionSNAT,
 		}
 
 		logger.V(log.DEBUG).Infof("Installing rule for host network to remote cluster communication: %+v", ruleSpec)
 
-		if err := kp.pFilter.AppendUnique(packetfilter.TableTypeNAT, constants.SmPostRoutingChain, &ruleSpec); err != nil {
+		if err := pFilter.AppendUnique(packetfilter.TableTypeNAT, constants.SmPostRoutingChain, &ruleSpec); err != nil {
 			return errors.Wrapf(err, "unable to append rule %+v", &ruleSpec)
 		}
 	}
@@ -104,6 +149,20 @@ func (kp *SyncHandler) createPFilterChains() error {
 	return nil
 }
 
+// hostNetworkSNATFor returns the VTEP CIDR for the given IP family and the CNI interface IP to which host network
+// traffic sourced from it is SNATed, if any.
+func (kp *SyncHandler) hostNetworkSNATFor(family k8snet.IPFamily) (string, string) {
+	if kp.cniIface == nil {
+		return "", ""
+	}
+
+	if family == k8snet.IPv6 {
+		return VxLANVTepIPv6NetworkPrefix + "/96", kp.cniIface.IPv6Address
+	}
+
+	return strconv.Itoa(VxLANVTepNetworkPrefix) + ".0.0.0/8", kp.cniIface.IPAddress
+}
+
 func (kp *SyncHandler) updateIptableRulesForInterClusterTraffic(inputCidrBlocks []string, operation Operation) {
 	for _, inputCidrBlock := range inputCidrBlocks {
 		err := kp.programIptableRulesForInterClusterTraffic(inputCidrBlock, operation)
@@ -114,7 +173,18 @@ func (kp *SyncHandler) updateIptableRulesForInterClusterTraffic(inputCidrBlocks
 }
 
 func (kp *SyncHandler) programIptableRulesForInterClusterTraffic(remoteCidrBlock string, operation Operation) error {
+	pFilter := kp.pFilterFor(remoteCidrBlock)
+	if pFilter == nil {
+		logger.V(log.DEBUG).Infof("Skipping packetfilter rules for remote CIDR %q - its IP family isn't enabled locally",
+			remoteCidrBlock)
+		return nil
+	}
+
 	for _, localClusterCidr := range kp.localClusterCidr {
+		if !cidr.SameFamily(localClusterCidr, remoteCidrBlock) {
+			continue
+		}
+
 		outboundRule := packetfilter.Rule{
 			Action:   packetfilter.RuleActionAccept,
 			SrcCIDR:  localClusterCidr,
@@ -129,25 +199,25 @@ func (kp *SyncHandler) programIptableRulesForInterClusterTraffic(remoteCidrBlock
 		if operation == Add {
 			logger.V(log.DEBUG).Infof("Installing packetfilter rule for outgoing traffic: %+v", outboundRule)
 
-			if err := kp.pFilter.AppendUnique(packetfilter.TableTypeNAT, constants.SmPostRoutingChain, &outboundRule); err != nil {
+			if err := pFilter.AppendUnique(packetfilter.TableTypeNAT, constants.SmPostRoutingChain, &outboundRule); err != nil {
 				return errors.Wrapf(err, "error appending packetfilter rule %+v", outboundRule)
 			}
 
 			logger.V(log.DEBUG).Infof("Installing packetfilter rule for incoming traffic: %+v", incomingRule)
 
-			if err := kp.pFilter.AppendUnique(packetfilter.TableTypeNAT, constants.SmPostRoutingChain, &incomingRule); err != nil {
+			if err := pFilter.AppendUnique(packetfilter.TableTypeNAT, constants.SmPostRoutingChain, &incomingRule); err != nil {
 				return errors.Wrapf(err, "error appending packetfilter rule %+v", incomingRule)
 			}
 		} else if operation == Delete {
 			logger.V(log.DEBUG).Infof("Deleting packetfilter rule for outgoing traffic: %+v", outboundRule)
 
-			if err := kp.pFilter.Delete(packetfilter.TableTypeNAT, constants.SmPostRoutingChain, &outboundRule); err != nil {
+			if err := pFilter.Delete(packetfilter.TableTypeNAT, constants.SmPostRoutingChain, &outboundRule); err != nil {
 				return errors.Wrapf(err, "error deleting packetfilter rule %+v", outboundRule)
 			}
 
 			logger.V(log.DEBUG).Infof("Deleting packetfilter rule for incoming traffic: %+v", incomingRule)
 
-			if err := kp.pFilter.Delete(packetfilter.TableTypeNAT, constants.SmPostRoutingChain, &incomingRule); err != nil {
+			if err := pFilter.Delete(packetfilter.TableTypeNAT, constants.SmPostRoutingChain, &incomingRule); err != nil {
 				return errors.Wrapf(err, "error deleting packetfilter rule %+v", incomingRule)
 			}
 		}
@@ -21,6 +21,7 @@ package kubeproxy
 import (
 	"net"
 	"os"
+	"slices"
 	"syscall"
 
 	"github.com/pkg/errors"
@@ -28,6 +29,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	"github.com/vishvananda/netlink"
 	"golang.org/x/sys/unix"
+	k8snet "k8s.io/utils/net"
 )
 
 func (kp *SyncHandler) updateRoutingRulesForHostNetworkSupport(inputCidrBlocks []string, operation Operation) {
@@ -106,6 +108,15 @@ func (kp *SyncHandler) isGatewayInRemoteCIDR(remoteCIDR string) bool {
 
 func (kp *SyncHandler) configureRoute(remoteSubnet string, operation Operation, viaGw *net.IP) error {
 	src := net.ParseIP(kp.cniIface.IPAddress)
+	if k8snet.IsIPv6CIDRString(remoteSubnet) {
+		src = net.ParseIP(kp.cniIface.IPv6Address)
+	}
+
+	if src == nil {
+		logger.V(log.DEBUG).Infof("The CNI interface has no address of the IP family of %q - not configuring a host network route",
+			remoteSubnet)
+		return nil
+	}
 
 	_, dst, err := net.ParseCIDR(remoteSubnet)
 	if err != nil {
@@ -167,7 +178,13 @@ func (kp *SyncHandler) cleanVxSubmarinerRoutes() {
 		return
 	}
 
-	currentRouteList, err := kp.netLink.RouteList(link, syscall.AF_INET)
+	for _, family := range kp.routeFamilies() {
+		kp.cleanVxSubmarinerRoutesForFamily(link, family)
+	}
+}
+
+func (kp *SyncHandler) cleanVxSubmarinerRoutesForFamily(link netlink.Link, family int) {
+	currentRouteList, err := kp.netLink.RouteList(link, family)
 	if err != nil {
 		logger.Errorf(err, "Unable to cleanup routes, error retrieving routes on the link %s", VxLANIface)
 		return
@@ -176,7 +193,7 @@ func (kp *SyncHandler) cleanVxSubmarinerRoutes() {
 	for i := range currentRouteList {
 		logger.V(log.DEBUG).Infof("Processing route %v", currentRouteList[i])
 
-		if currentRouteList[i].Dst == nil || currentRouteList[i].Gw == nil {
+		if currentRouteList[i].Dst == nil || len(routeGws(&currentRouteList[i])) == 0 {
 			logger.V(log.DEBUG).Infof("Found nil gw or dst")
 		} else if kp.remoteSubnets.Has(currentRouteList[i].Dst.String()) {
 			logger.V(log.DEBUG).Infof("Removing route %s", currentRouteList[i])
@@ -188,49 +205,157 @@ func (kp *SyncHandler) cleanVxSubmarinerRoutes() {
 	}
 }
 
-// Reconcile the routes installed on this device using rtnetlink.
-func (kp *SyncHandler) reconcileRoutes(vxlanGw net.IP) error {
-	logger.V(log.DEBUG).Infof("Reconciling routes to gw: %s", vxlanGw.String())
+// routeFamilies returns the netlink address families for which routes are programmed.
+func (kp *SyncHandler) routeFamilies() []int {
+	if kp.isDualStack() {
+		return []int{syscall.AF_INET, syscall.AF_INET6}
+	}
+
+	return []int{syscall.AF_INET}
+}
+
+// vxlanGws returns the remote VTEP IPs of the given family through which the remote CIDRs are reached. With
+// active-active gateways, there's one per gateway.
+func (kp *SyncHandler) vxlanGws(family int) []net.IP {
+	if kp.isActiveActiveVxLAN() {
+		return kp.activeActiveVtepIPs(family)
+	}
+
+	gw := kp.vxlanGwIP
+	if family == syscall.AF_INET6 {
+		gw = kp.vxlanGwIPv6
+	}
+
+	if gw == nil {
+		return nil
+	}
+
+	return []net.IP{*gw}
+}
+
+// vxlanGwsFor returns the remote VTEP IPs through which the given remote CIDR is reached.
+func (kp *SyncHandler) vxlanGwsFor(cidrBlock string) []net.IP {
+	if k8snet.IsIPv6CIDRString(cidrBlock) {
+		return kp.vxlanGws(syscall.AF_INET6)
+	}
+
+	return kp.vxlanGws(syscall.AF_INET)
+}
+
+// newVxLANRoute returns the route to the given destination via the given VTEP IPs, using a multipath route if there's
+// more than one.
+func newVxLANRoute(dst *net.IPNet, vxlanGws []net.IP, linkIndex int) netlink.Route {
+	route := netlink.Route{
+		Dst:       dst,
+		Scope:     unix.RT_SCOPE_UNIVERSE,
+		LinkIndex: linkIndex,
+		Protocol:  4,
+	}
+
+	if len(vxlanGws) == 1 {
+		route.Gw = vxlanGws[0]
+		return route
+	}
+
+	for i := range vxlanGws {
+		route.MultiPath = append(route.MultiPath, &netlink.NexthopInfo{
+			LinkIndex: linkIndex,
+			Gw:        vxlanGws[i],
+		})
+	}
 
+	return route
+}
+
+// routeGws returns the gateway IPs of the given route, including those of its multipath next hops.
+func routeGws(route *netlink.Route) []net.IP {
+	if route.Gw != nil {
+		return []net.IP{route.Gw}
+	}
+
+	gws := make([]net.IP, 0, len(route.MultiPath))
+
+	for _, nh := range route.MultiPath {
+		if nh.Gw != nil {
+			gws = append(gws, nh.Gw)
+		}
+	}
+
+	return gws
+}
+
+// routeHasGws returns true if the given route's gateway IPs are exactly the given VTEP IPs.
+func routeHasGws(route *netlink.Route, vxlanGws []net.IP) bool {
+	gws := routeGws(route)
+	if len(gws) != len(vxlanGws) {
+		return false
+	}
+
+	for i := range vxlanGws {
+		if !slices.ContainsFunc(gws, vxlanGws[i].Equal) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Reconcile the routes installed on this device using rtnetlink.
+func (kp *SyncHandler) reconcileRoutes() error {
 	link, err := kp.netLink.LinkByName(VxLANIface)
 	if err != nil {
 		return errors.Wrapf(err, "error retrieving link by name %s", VxLANIface)
 	}
 
-	currentRouteList, err := kp.netLink.RouteList(link, syscall.AF_INET)
+	for _, family := range kp.routeFamilies() {
+		vxlanGws := kp.vxlanGws(family)
+		if len(vxlanGws) == 0 {
+			continue
+		}
+
+		if err := kp.reconcileRoutesForFamily(link, vxlanGws, family); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (kp *SyncHandler) reconcileRoutesForFamily(link netlink.Link, vxlanGws []net.IP, family int) error {
+	logger.V(log.DEBUG).Infof("Reconciling routes to gw: %v", vxlanGws)
+
+	currentRouteList, err := kp.netLink.RouteList(link, family)
 	if err != nil {
 		return errors.Wrapf(err, "error retrieving routes for link %s", VxLANIface)
 	}
 
 	// First lets delete all of the routes that don't match.
-	kp.removeUnknownRoutes(vxlanGw, currentRouteList)
+	kp.removeUnknownRoutes(vxlanGws, currentRouteList)
 
-	currentRouteList, err = kp.netLink.RouteList(link, syscall.AF_INET)
+	currentRouteList, err = kp.netLink.RouteList(link, family)
 	if err != nil {
 		return errors.Wrapf(err, "error retrieving routes for link %s", VxLANIface)
 	}
 
 	// Let's now add the routes that are missing.
 	for _, cidrBlock := range kp.remoteSubnets.UnsortedList() {
+		if (family == syscall.AF_INET6) != k8snet.IsIPv6CIDRString(cidrBlock) {
+			continue
+		}
+
 		_, dst, err := net.ParseCIDR(cidrBlock)
 		if err != nil {
 			logger.Errorf(err, "Error parsing cidr block %s", cidrBlock)
 			break
 		}
 
-		route := netlink.Route{
-			Dst:       dst,
-			Gw:        vxlanGw,
-			Scope:     unix.RT_SCOPE_UNIVERSE,
-			LinkIndex: link.Attrs().Index,
-			Protocol:  4,
-		}
+		route := newVxLANRoute(dst, vxlanGws, link.Attrs().Index)
 
 		found := false
 
 		for i := range currentRouteList {
-			if currentRouteList[i].Gw != nil && currentRouteList[i].Dst != nil &&
-				currentRouteList[i].Gw.Equal(route.Gw) && currentRouteList[i].Dst.String() == route.Dst.String() {
+			if currentRouteList[i].Dst != nil && routeHasGws(&currentRouteList[i], vxlanGws) &&
+				currentRouteList[i].Dst.String() == route.Dst.String() {
 				logger.V(log.DEBUG).Infof("Found equivalent route, not adding")
 
 				found = true
@@ -248,16 +373,16 @@ func (kp *SyncHandler) reconcileRoutes(vxlanGw net.IP) error {
 	return nil
 }
 
-func (kp *SyncHandler) removeUnknownRoutes(vxlanGw net.IP, currentRouteList []netlink.Route) {
+func (kp *SyncHandler) removeUnknownRoutes(vxlanGws []net.IP, currentRouteList []netlink.Route) {
 	for i := range currentRouteList {
 		// Contains(endpoint destinations, route destination string, and the route gateway is our actual destination.
 		logger.V(log.DEBUG).Infof("Processing route %v", currentRouteList[i])
 
-		if currentRouteList[i].Dst == nil || currentRouteList[i].Gw == nil {
+		if currentRouteList[i].Dst == nil || len(routeGws(&currentRouteList[i])) == 0 {
 			logger.V(log.DEBUG).Infof("Found nil gw or dst")
 		} else {
-			if kp.remoteSubnets.Has(currentRouteList[i].Dst.String()) && currentRouteList[i].Gw.Equal(vxlanGw) {
-				logger.V(log.DEBUG).Infof("Found route %s with gw %s already installed", currentRouteList[i], currentRouteList[i].Gw)
+			if kp.remoteSubnets.Has(currentRouteList[i].Dst.String()) && routeHasGws(&currentRouteList[i], vxlanGws) {
+				logger.V(log.DEBUG).Infof("Found route %s with gw %v already installed", currentRouteList[i], vxlanGws)
 			} else {
 				logger.V(log.DEBUG).Infof("Removing route %s", currentRouteList[i])
 
@@ -276,25 +401,24 @@ func (kp *SyncHandler) updateRoutingRulesForInterClusterSupport(remoteCIDRs []st
 		return nil
 	}
 
-	if kp.vxlanDevice != nil && kp.vxlanGwIP != nil {
+	if kp.vxlanDevice != nil {
 		link, err := kp.netLink.LinkByName(VxLANIface)
 		if err != nil {
 			return errors.Wrapf(err, "error retrieving link by name %s", VxLANIface)
 		}
 
 		for _, cidrBlock := range remoteCIDRs {
+			vxlanGws := kp.vxlanGwsFor(cidrBlock)
+			if len(vxlanGws) == 0 {
+				continue
+			}
+
 			_, dst, err := net.ParseCIDR(cidrBlock)
 			if err != nil {
 				return errors.Wrapf(err, "error parsing cidr block %s", cidrBlock)
 			}
 
-			route := netlink.Route{
-				Dst:       dst,
-				Gw:        *kp.vxlanGwIP,
-				Scope:     unix.RT_SCOPE_UNIVERSE,
-				LinkIndex: link.Attrs().Index,
-				Protocol:  4,
-			}
+			route := newVxLANRoute(dst, vxlanGws, link.Attrs().Index)
 
 			if operation == Add {
 				err = kp.netLink.RouteAddOrReplace(&route)
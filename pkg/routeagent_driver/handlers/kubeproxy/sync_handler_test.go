@@ -19,11 +19,18 @@ limitations under the License.
 package kubeproxy_test
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/json"
 	"net"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/resource"
+	"github.com/submariner-io/admiral/pkg/syncer/test"
+	"github.com/submariner-io/admiral/pkg/watcher"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/cni"
 	"github.com/submariner-io/submariner/pkg/event/testing"
@@ -38,19 +45,25 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
 )
 
 const (
-	localClusterCIDR = "169.254.1.0/24"
-	localServiceCIDR = "169.254.2.0/24"
-	remoteSubnet1    = "170.250.1.0/24"
-	remoteSubnet2    = "171.250.1.0/24"
-	localNodeName1   = "local-node1"
-	localNodeName2   = "local-node2"
-	remoteNodeName   = "remote-node"
-	nodeAddress1     = "10.253.10.2"
-	nodeAddress2     = "10.253.10.3"
-	cniIPAddress     = "192.168.5.1"
+	localClusterCIDR   = "169.254.1.0/24"
+	localServiceCIDR   = "169.254.2.0/24"
+	remoteSubnet1      = "170.250.1.0/24"
+	remoteSubnet2      = "171.250.1.0/24"
+	localNodeName1     = "local-node1"
+	localNodeName2     = "local-node2"
+	remoteNodeName     = "remote-node"
+	nodeAddress1       = "10.253.10.2"
+	nodeAddress2       = "10.253.10.3"
+	cniIPAddress       = "192.168.5.1"
+	localClusterCIDRv6 = "fd00:1::/64"
+	remoteSubnetv6     = "fd00:2::/64"
+	cniIPv6Address     = "fd00:1::5"
 )
 
 var _ = Describe("SyncHandler", func() {
@@ -58,6 +71,8 @@ var _ = Describe("SyncHandler", func() {
 	Describe("Gateway transition", testGatewayTransition)
 	Describe("Nodes", testNodes)
 	Describe("Uninstall", testUninstall)
+	Describe("Dual-stack", testDualStack)
+	Describe("Traffic policies", testTrafficPolicies)
 })
 
 func testEndpoints() {
@@ -108,6 +123,47 @@ func testEndpoints() {
 		})
 	})
 
+	When("local Endpoints for active-active gateways are created while on a non-gateway node", func() {
+		var otherEndpoint *submarinerv1.Endpoint
+
+		BeforeEach(func() {
+			t.localEndpoint.Spec.BackendConfig = map[string]string{
+				submarinerv1.GatewayHAModeConfig: submarinerv1.HAModeActiveActive,
+			}
+
+			otherEndpoint = newLocalEndpoint(localNodeName2)
+			otherEndpoint.Spec.PrivateIP = "192.68.1.3"
+			otherEndpoint.Spec.BackendConfig = t.localEndpoint.Spec.BackendConfig
+		})
+
+		JustBeforeEach(func() {
+			t.CreateEndpoint(t.localEndpoint)
+			t.CreateEndpoint(otherEndpoint)
+			t.CreateEndpoint(t.remoteEndpoint)
+		})
+
+		It("should add the VxLAN interface with an FDB entry for each gateway", func() {
+			Expect(toVxlan(t.netLink.AwaitLink(kubeproxy.VxLANIface)).Group).To(BeNil())
+			t.netLink.AwaitNeighbors(t.vxLanInterfaceIndex, t.localEndpoint.Spec.PrivateIP, otherEndpoint.Spec.PrivateIP)
+		})
+
+		It("should add multipath VxLAN routes via each gateway for the remote subnets", func() {
+			t.verifyVxLANRoutes()
+			t.awaitVxLANRouteGws(remoteSubnet1, "240.68.1.2", "240.68.1.3")
+		})
+
+		Context("and one is subsequently removed", func() {
+			It("should remove its FDB entry and route next hop", func() {
+				t.awaitVxLANRouteGws(remoteSubnet1, "240.68.1.2", "240.68.1.3")
+
+				t.DeleteEndpoint(otherEndpoint.Name)
+
+				t.netLink.AwaitNoNeighbors(t.vxLanInterfaceIndex, otherEndpoint.Spec.PrivateIP)
+				t.awaitVxLANRouteGws(remoteSubnet1, "240.68.1.2")
+			})
+		})
+	})
+
 	When("a local Endpoint is removed while on a non-gateway node", func() {
 		BeforeEach(func() {
 			t.CreateEndpoint(t.localEndpoint)
@@ -260,6 +316,11 @@ func testGatewayTransition() {
 			t.verifyHostNetworkingRoutes()
 		})
 
+		It("should only program chains included in the packet filter migration spec", func() {
+			t.verifyHostNetworkingRoutes()
+			t.pFilter.EnsureChainsIncludedIn(kubeproxy.PacketFilterMigrationSpec().Includes)
+		})
+
 		Context("and previous VxLAN routes are present", func() {
 			BeforeEach(func() {
 				t.addVxLANRoute(remoteSubnet1)
@@ -350,9 +411,196 @@ func testUninstall() {
 	})
 }
 
+func testTrafficPolicies() {
+	t := newTestDriver()
+
+	var remoteSetName string
+
+	BeforeEach(func() {
+		remoteSetName = trafficPolicySetName(t.remoteEndpoint.Spec.ClusterID)
+	})
+
+	It("should jump to the traffic policy chain before accepting the VxLAN traffic", func() {
+		Eventually(func() []string {
+			rules, err := t.pFilter.List(packetfilter.TableTypeFilter, constants.SmForwardChain)
+			Expect(err).To(Succeed())
+
+			targets := []string{}
+			for _, rule := range rules {
+				targets = append(targets, rule.TargetChain+rule.OutInterface)
+			}
+
+			return targets
+		}).Should(HaveExactElements(constants.SmTrafficPolicyChain, kubeproxy.VxLANIface))
+	})
+
+	When("a Deny policy is created while on a gateway node", func() {
+		JustBeforeEach(func() {
+			t.CreateLocalHostEndpoint()
+			t.CreateEndpoint(t.remoteEndpoint)
+			t.createTrafficPolicy("deny-ssh", &submarinerv1.ClusterTrafficPolicySpec{
+				Action:         submarinerv1.TrafficPolicyDeny,
+				RemoteClusters: []string{t.remoteEndpoint.Spec.ClusterID},
+				LocalCIDRs:     []string{localClusterCIDR},
+				Ports:          []submarinerv1.TrafficPolicyPort{{Protocol: corev1.ProtocolTCP, Port: 22}},
+			})
+		})
+
+		It("should add an IP set with the remote cluster's subnets", func() {
+			t.pFilter.AwaitSet(Equal(remoteSetName))
+			t.pFilter.AwaitEntry(remoteSetName, remoteSubnet1)
+			t.pFilter.AwaitEntry(remoteSetName, remoteSubnet2)
+		})
+
+		It("should drop the matching traffic after allowing established connections", func() {
+			t.awaitTrafficPolicyRules(&packetfilter.Rule{
+				ConnState: packetfilter.ConnStateEstablished,
+				Action:    packetfilter.RuleActionReturn,
+			}, &packetfilter.Rule{
+				Proto:      packetfilter.RuleProtoTCP,
+				SrcSetName: remoteSetName,
+				DestCIDR:   localClusterCIDR,
+				DPort:      "22",
+				Action:     packetfilter.RuleActionDrop,
+			})
+		})
+
+		Context("and another policy is subsequently created", func() {
+			It("should program the updated rules in the alternate chain and flush the previous one", func() {
+				t.awaitTrafficPolicyRules(&packetfilter.Rule{
+					ConnState: packetfilter.ConnStateEstablished,
+					Action:    packetfilter.RuleActionReturn,
+				}, &packetfilter.Rule{
+					Proto:      packetfilter.RuleProtoTCP,
+					SrcSetName: remoteSetName,
+					DestCIDR:   localClusterCIDR,
+					DPort:      "22",
+					Action:     packetfilter.RuleActionDrop,
+				})
+
+				previous := t.activeTrafficPolicyChain()
+
+				t.createTrafficPolicy("deny-host", &submarinerv1.ClusterTrafficPolicySpec{
+					Action:      submarinerv1.TrafficPolicyDeny,
+					RemoteCIDRs: []string{"170.250.1.10/32"},
+				})
+
+				t.awaitTrafficPolicyRules(&packetfilter.Rule{
+					ConnState: packetfilter.ConnStateEstablished,
+					Action:    packetfilter.RuleActionReturn,
+				}, &packetfilter.Rule{
+					SrcCIDR: "170.250.1.10/32",
+					Action:  packetfilter.RuleActionDrop,
+				}, &packetfilter.Rule{
+					Proto:      packetfilter.RuleProtoTCP,
+					SrcSetName: remoteSetName,
+					DestCIDR:   localClusterCIDR,
+					DPort:      "22",
+					Action:     packetfilter.RuleActionDrop,
+				})
+
+				Expect(t.activeTrafficPolicyChain()).ToNot(Equal(previous))
+				t.pFilter.AwaitNoRules(packetfilter.TableTypeFilter, previous)
+			})
+		})
+
+		Context("and subsequently deleted", func() {
+			It("should remove the rules and the IP set", func() {
+				t.pFilter.AwaitSet(Equal(remoteSetName))
+
+				t.deleteTrafficPolicy("deny-ssh")
+
+				t.pFilter.AwaitNoRules(packetfilter.TableTypeFilter, constants.SmTrafficPolicyChain)
+				t.pFilter.AwaitSetDeleted(remoteSetName)
+			})
+		})
+
+		Context("and the remote Endpoint is subsequently removed", func() {
+			It("should remove the IP set", func() {
+				t.pFilter.AwaitSet(Equal(remoteSetName))
+
+				t.DeleteEndpoint(t.remoteEndpoint.Name)
+
+				t.awaitTrafficPolicyRules(&packetfilter.Rule{
+					ConnState: packetfilter.ConnStateEstablished,
+					Action:    packetfilter.RuleActionReturn,
+				})
+				t.pFilter.AwaitSetDeleted(remoteSetName)
+			})
+		})
+	})
+
+	When("Allow and Deny policies are created while on a gateway node", func() {
+		var localHostEP *submarinerv1.Endpoint
+
+		JustBeforeEach(func() {
+			localHostEP = t.CreateLocalHostEndpoint()
+			t.CreateEndpoint(t.remoteEndpoint)
+			t.createTrafficPolicy("allow-web", &submarinerv1.ClusterTrafficPolicySpec{
+				Action:      submarinerv1.TrafficPolicyAllow,
+				RemoteCIDRs: []string{remoteSubnet1},
+				Ports:       []submarinerv1.TrafficPolicyPort{{Port: 443}, {Protocol: corev1.ProtocolUDP, Port: 53}},
+			})
+			t.createTrafficPolicy("deny-host", &submarinerv1.ClusterTrafficPolicySpec{
+				Action:      submarinerv1.TrafficPolicyDeny,
+				RemoteCIDRs: []string{"170.250.1.10/32"},
+			})
+		})
+
+		It("should drop the denied traffic, allow the allowed traffic and drop the rest from the allowed clusters", func() {
+			t.awaitTrafficPolicyRules(&packetfilter.Rule{
+				ConnState: packetfilter.ConnStateEstablished,
+				Action:    packetfilter.RuleActionReturn,
+			}, &packetfilter.Rule{
+				SrcCIDR: "170.250.1.10/32",
+				Action:  packetfilter.RuleActionDrop,
+			}, &packetfilter.Rule{
+				Proto:   packetfilter.RuleProtoTCP,
+				SrcCIDR: remoteSubnet1,
+				DPort:   "443",
+				Action:  packetfilter.RuleActionReturn,
+			}, &packetfilter.Rule{
+				Proto:   packetfilter.RuleProtoUDP,
+				SrcCIDR: remoteSubnet1,
+				DPort:   "53",
+				Action:  packetfilter.RuleActionReturn,
+			}, &packetfilter.Rule{
+				SrcSetName: remoteSetName,
+				Action:     packetfilter.RuleActionDrop,
+			})
+		})
+
+		Context("and then transition to non-gateway", func() {
+			It("should remove the rules and the IP set", func() {
+				t.pFilter.AwaitSet(Equal(remoteSetName))
+
+				t.DeleteEndpoint(localHostEP.Name)
+
+				t.pFilter.AwaitNoRules(packetfilter.TableTypeFilter, constants.SmTrafficPolicyChain)
+				t.pFilter.AwaitSetDeleted(remoteSetName)
+			})
+		})
+	})
+
+	When("a policy is created while on a non-gateway node", func() {
+		JustBeforeEach(func() {
+			t.CreateEndpoint(t.localEndpoint)
+			t.CreateEndpoint(t.remoteEndpoint)
+			t.createTrafficPolicy("deny-all", &submarinerv1.ClusterTrafficPolicySpec{
+				Action: submarinerv1.TrafficPolicyDeny,
+			})
+		})
+
+		It("should not add any rules", func() {
+			t.pFilter.EnsureNoRule(packetfilter.TableTypeFilter, constants.SmTrafficPolicyChain, Not(BeEmpty()))
+		})
+	})
+}
+
 type testDriver struct {
 	*testing.ControllerSupport
 	handler             *kubeproxy.SyncHandler
+	trafficPolicies     dynamic.ResourceInterface
 	pFilter             *fakePF.PacketFilter
 	netLink             *fakeNetlink.NetLink
 	localEndpoint       *submarinerv1.Endpoint
@@ -361,7 +609,52 @@ type testDriver struct {
 	vxLanInterfaceIndex int
 }
 
+func testDualStack() {
+	t := newTestDriverWith(localClusterCIDR, localClusterCIDRv6)
+
+	BeforeEach(func() {
+		t.remoteEndpoint.Spec.Subnets = append(t.remoteEndpoint.Spec.Subnets, remoteSubnetv6)
+	})
+
+	When("a remote Endpoint with IPv4 and IPv6 subnets is created while on a non-gateway node", func() {
+		JustBeforeEach(func() {
+			t.CreateEndpoint(t.localEndpoint)
+			t.CreateEndpoint(t.remoteEndpoint)
+		})
+
+		It("should add VxLAN routes for the remote subnets of both families", func() {
+			t.verifyVxLANRoutes()
+		})
+
+		It("should only add IP table rules between local and remote CIDRs of the same family", func() {
+			t.pFilter.AwaitRule(packetfilter.TableTypeNAT, constants.SmPostRoutingChain,
+				And(ContainSubstring(localClusterCIDR), ContainSubstring(remoteSubnet1)))
+			t.pFilter.AwaitRule(packetfilter.TableTypeNAT, constants.SmPostRoutingChain,
+				And(ContainSubstring(localClusterCIDRv6), ContainSubstring(remoteSubnetv6)))
+			t.pFilter.EnsureNoRule(packetfilter.TableTypeNAT, constants.SmPostRoutingChain,
+				And(ContainSubstring(localClusterCIDR), ContainSubstring(remoteSubnetv6)))
+			t.pFilter.EnsureNoRule(packetfilter.TableTypeNAT, constants.SmPostRoutingChain,
+				And(ContainSubstring(localClusterCIDRv6), ContainSubstring(remoteSubnet1)))
+		})
+	})
+
+	When("a remote Endpoint with IPv4 and IPv6 subnets is created while on a gateway node", func() {
+		JustBeforeEach(func() {
+			t.CreateLocalHostEndpoint()
+			t.CreateEndpoint(t.remoteEndpoint)
+		})
+
+		It("should add routing rules for host networking for both families", func() {
+			t.verifyHostNetworkingRoutes()
+		})
+	})
+}
+
 func newTestDriver() *testDriver {
+	return newTestDriverWith(localClusterCIDR)
+}
+
+func newTestDriverWith(localClusterCIDRs ...string) *testDriver {
 	t := &testDriver{
 		ControllerSupport: testing.NewControllerSupport(),
 	}
@@ -383,15 +676,23 @@ func newTestDriver() *testDriver {
 
 		cni.DiscoverFunc = func(_ []string) (*cni.Interface, error) {
 			return &cni.Interface{
-				Name:      "veth0",
-				IPAddress: cniIPAddress,
+				Name:        "veth0",
+				IPAddress:   cniIPAddress,
+				IPv6Address: cniIPv6Address,
 			}, nil
 		}
 
 		t.localEndpoint = newLocalEndpoint(localNodeName1)
 		t.remoteEndpoint = newRemoteEndpoint()
 
-		t.handler = kubeproxy.NewSyncHandler([]string{localClusterCIDR}, []string{localServiceCIDR})
+		dynClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+		restMapper := test.GetRESTMapperFor(&submarinerv1.ClusterTrafficPolicy{})
+		t.trafficPolicies = dynClient.Resource(*test.GetGroupVersionResourceFor(restMapper, &submarinerv1.ClusterTrafficPolicy{}))
+
+		t.handler = kubeproxy.NewSyncHandler(localClusterCIDRs, []string{localServiceCIDR}, &watcher.Config{
+			RestMapper: restMapper,
+			Client:     dynClient,
+		})
 
 		t.Start(t.handler)
 	})
@@ -408,6 +709,34 @@ func (t *testDriver) verifyVxLANRoutes() {
 	t.netLink.AwaitDstRoutes(t.netLink.AwaitLink(kubeproxy.VxLANIface).Attrs().Index, 0, t.remoteEndpoint.Spec.Subnets...)
 }
 
+func (t *testDriver) awaitVxLANRouteGws(cidr string, expGws ...string) {
+	_, dst, err := net.ParseCIDR(cidr)
+	Expect(err).To(Succeed())
+
+	Eventually(func() []string {
+		routes, err := t.netLink.RouteList(t.netLink.AwaitLink(kubeproxy.VxLANIface), netlink.FAMILY_ALL)
+		Expect(err).To(Succeed())
+
+		gws := []string{}
+
+		for i := range routes {
+			if routes[i].Dst == nil || routes[i].Dst.String() != dst.String() {
+				continue
+			}
+
+			if routes[i].Gw != nil {
+				gws = append(gws, routes[i].Gw.String())
+			}
+
+			for _, nh := range routes[i].MultiPath {
+				gws = append(gws, nh.Gw.String())
+			}
+		}
+
+		return gws
+	}).Should(ConsistOf(expGws))
+}
+
 func (t *testDriver) verifyNoVxLANRoutes() {
 	time.Sleep(200 * time.Millisecond)
 	t.netLink.AwaitNoDstRoutes(t.vxLanInterfaceIndex, 0, t.remoteEndpoint.Spec.Subnets...)
@@ -429,6 +758,65 @@ func (t *testDriver) verifyRemoteSubnetIPTableRules() {
 	}
 }
 
+func (t *testDriver) createTrafficPolicy(name string, spec *submarinerv1.ClusterTrafficPolicySpec) {
+	_, err := t.trafficPolicies.Create(context.TODO(), resource.MustToUnstructured(&submarinerv1.ClusterTrafficPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       *spec,
+	}), metav1.CreateOptions{})
+	Expect(err).To(Succeed())
+}
+
+func (t *testDriver) deleteTrafficPolicy(name string) {
+	Expect(t.trafficPolicies.Delete(context.TODO(), name, metav1.DeleteOptions{})).To(Succeed())
+}
+
+func (t *testDriver) awaitTrafficPolicyRules(expected ...*packetfilter.Rule) {
+	expStrings := make([]string, len(expected))
+
+	for i := range expected {
+		b, err := json.Marshal(expected[i])
+		Expect(err).To(Succeed())
+
+		expStrings[i] = string(b)
+	}
+
+	Eventually(func() []string {
+		jumps, err := t.pFilter.List(packetfilter.TableTypeFilter, constants.SmTrafficPolicyChain)
+		Expect(err).To(Succeed())
+
+		if len(jumps) != 1 {
+			return nil
+		}
+
+		rules, err := t.pFilter.List(packetfilter.TableTypeFilter, jumps[0].TargetChain)
+		Expect(err).To(Succeed())
+
+		ruleStrings := []string{}
+
+		for _, rule := range rules {
+			b, err := json.Marshal(rule)
+			Expect(err).To(Succeed())
+
+			ruleStrings = append(ruleStrings, string(b))
+		}
+
+		return ruleStrings
+	}, 5).Should(Equal(expStrings))
+}
+
+func (t *testDriver) activeTrafficPolicyChain() string {
+	jumps, err := t.pFilter.List(packetfilter.TableTypeFilter, constants.SmTrafficPolicyChain)
+	Expect(err).To(Succeed())
+	Expect(jumps).To(HaveLen(1))
+
+	return jumps[0].TargetChain
+}
+
+func trafficPolicySetName(clusterID string) string {
+	hash := sha256.Sum256([]byte(clusterID))
+	return constants.TrafficPolicyIPSetPrefix + base32.StdEncoding.EncodeToString(hash[:])[:16]
+}
+
 func (t *testDriver) addVxLANRoute(cidr string) {
 	_, dst, err := net.ParseCIDR(cidr)
 	Expect(err).To(Succeed())
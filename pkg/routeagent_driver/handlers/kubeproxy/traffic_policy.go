@@ -0,0 +1,483 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeproxy
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/admiral/pkg/watcher"
+	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	"github.com/submariner-io/submariner/pkg/packetfilter"
+	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	k8snet "k8s.io/utils/net"
+)
+
+// trafficPolicySource matches the source of the traffic selected by a ClusterTrafficPolicy, either all the subnets of a
+// remote cluster, through its IP set, or a single remote CIDR.
+type trafficPolicySource struct {
+	setName string
+	cidr    string
+}
+
+func trafficPolicyJumpRule() *packetfilter.Rule {
+	return &packetfilter.Rule{
+		Action:      packetfilter.RuleActionJump,
+		TargetChain: constants.SmTrafficPolicyChain,
+	}
+}
+
+func (kp *SyncHandler) startTrafficPolicyWatcher() error {
+	if kp.watcherConfig == nil {
+		return nil
+	}
+
+	config := *kp.watcherConfig
+	config.ResourceConfigs = []watcher.ResourceConfig{
+		{
+			Name:         "ClusterTrafficPolicy watcher",
+			ResourceType: &submV1.ClusterTrafficPolicy{},
+			Handler: watcher.EventHandlerFuncs{
+				OnCreateFunc: kp.trafficPolicyCreatedOrUpdated,
+				OnUpdateFunc: kp.trafficPolicyCreatedOrUpdated,
+				OnDeleteFunc: kp.trafficPolicyDeleted,
+			},
+		},
+	}
+
+	policyWatcher, err := watcher.New(&config)
+	if err != nil {
+		return errors.Wrap(err, "error creating the ClusterTrafficPolicy watcher")
+	}
+
+	return errors.Wrap(policyWatcher.Start(kp.stopCh), "error starting the ClusterTrafficPolicy watcher")
+}
+
+func (kp *SyncHandler) Stop() error {
+	close(kp.stopCh)
+
+	return nil
+}
+
+func (kp *SyncHandler) trafficPolicyCreatedOrUpdated(obj runtime.Object, _ int) bool {
+	policy := obj.(*submV1.ClusterTrafficPolicy)
+
+	for _, c := range append(append([]string{}, policy.Spec.RemoteCIDRs...), policy.Spec.LocalCIDRs...) {
+		if _, _, err := net.ParseCIDR(c); err != nil {
+			logger.Errorf(err, "ClusterTrafficPolicy %q has an invalid CIDR which is ignored", policy.Name)
+		}
+	}
+
+	kp.trafficPolicyMutex.Lock()
+	defer kp.trafficPolicyMutex.Unlock()
+
+	if reflect.DeepEqual(kp.trafficPolicies[policy.Name], &policy.Spec) {
+		return false
+	}
+
+	logger.Infof("ClusterTrafficPolicy %q created or updated: %#v", policy.Name, policy.Spec)
+
+	kp.trafficPolicies[policy.Name] = &policy.Spec
+
+	return kp.syncTrafficPolicies() != nil
+}
+
+func (kp *SyncHandler) trafficPolicyDeleted(obj runtime.Object, _ int) bool {
+	policy := obj.(*submV1.ClusterTrafficPolicy)
+
+	kp.trafficPolicyMutex.Lock()
+	defer kp.trafficPolicyMutex.Unlock()
+
+	logger.Infof("ClusterTrafficPolicy %q deleted", policy.Name)
+
+	delete(kp.trafficPolicies, policy.Name)
+
+	return kp.syncTrafficPolicies() != nil
+}
+
+// updateTrafficPolicies re-programs the ClusterTrafficPolicy rules after a change to the remote Endpoints or to the
+// gateway status of the local node.
+func (kp *SyncHandler) updateTrafficPolicies() {
+	kp.trafficPolicyMutex.Lock()
+	defer kp.trafficPolicyMutex.Unlock()
+
+	_ = kp.syncTrafficPolicies()
+}
+
+// syncTrafficPolicies programs the rules enforcing the ClusterTrafficPolicy resources in the traffic policy chain. The
+// rules are only programmed on the gateway, through which all the traffic from remote clusters enters the local
+// cluster. The caller must hold the trafficPolicyMutex.
+func (kp *SyncHandler) syncTrafficPolicies() error {
+	clusterSubnets := map[string][]string{}
+
+	for _, endpoint := range kp.State().GetRemoteEndpoints() {
+		clusterSubnets[endpoint.Spec.ClusterID] = append(clusterSubnets[endpoint.Spec.ClusterID], endpoint.Spec.Subnets...)
+	}
+
+	err := kp.syncTrafficPoliciesFor(kp.pFilter, k8snet.IPv4, clusterSubnets)
+
+	if err == nil && kp.pFilterV6 != nil {
+		err = kp.syncTrafficPoliciesFor(kp.pFilterV6, k8snet.IPv6, clusterSubnets)
+	}
+
+	if err != nil {
+		logger.Errorf(err, "Error programming the ClusterTrafficPolicy rules")
+	}
+
+	return err
+}
+
+func (kp *SyncHandler) syncTrafficPoliciesFor(pFilter packetfilter.Interface, family k8snet.IPFamily,
+	clusterSubnets map[string][]string,
+) error {
+	var rules []*packetfilter.Rule
+
+	setEntries := map[string][]string{}
+
+	if kp.State().IsOnGateway() && len(kp.trafficPolicies) > 0 {
+		rules = kp.trafficPolicyRules(family, clusterSubnets, setEntries)
+	}
+
+	for setName, entries := range setEntries {
+		if err := kp.syncTrafficPolicySet(pFilter, family, setName, entries); err != nil {
+			return err
+		}
+	}
+
+	if err := syncTrafficPolicyRules(pFilter, rules); err != nil {
+		return err
+	}
+
+	// Destroy the IP sets of remote clusters which are no longer referenced.
+	isStale := func(name string) bool {
+		_, found := setEntries[name]
+		return strings.HasPrefix(name, trafficPolicySetPrefix(family)) && !found
+	}
+
+	for setName := range kp.trafficPolicySets {
+		if isStale(setName) {
+			delete(kp.trafficPolicySets, setName)
+		}
+	}
+
+	return errors.Wrap(pFilter.DestroySets(isStale), "error destroying stale ClusterTrafficPolicy IP sets")
+}
+
+// trafficPolicyRules returns the rules enforcing the ClusterTrafficPolicy resources for the given IP family and adds the
+// entries of the IP sets they reference to setEntries. Replies to connections initiated locally are always allowed, then
+// the traffic matched by Deny policies is dropped, the traffic matched by Allow policies is allowed and the remaining
+// traffic from the remote clusters selected by Allow policies is dropped. Allowed traffic returns to the calling chain
+// so it's still subject to the other rules, eg network policies.
+func (kp *SyncHandler) trafficPolicyRules(family k8snet.IPFamily, clusterSubnets map[string][]string,
+	setEntries map[string][]string,
+) []*packetfilter.Rule {
+	rules := []*packetfilter.Rule{{
+		ConnState: packetfilter.ConnStateEstablished,
+		Action:    packetfilter.RuleActionReturn,
+	}}
+
+	clusterSets := map[string]string{}
+
+	for clusterID, subnets := range clusterSubnets {
+		subnets = filterCIDRs(subnets, family)
+		if len(subnets) > 0 {
+			clusterSets[clusterID] = trafficPolicySetName(clusterID, family)
+			setEntries[clusterSets[clusterID]] = subnets
+		}
+	}
+
+	names := sets.List(sets.KeySet(kp.trafficPolicies))
+	defaultDeny := sets.New[string]()
+
+	for _, action := range []submV1.TrafficPolicyAction{submV1.TrafficPolicyDeny, submV1.TrafficPolicyAllow} {
+		ruleAction := packetfilter.RuleActionDrop
+		if action == submV1.TrafficPolicyAllow {
+			ruleAction = packetfilter.RuleActionReturn
+		}
+
+		for _, name := range names {
+			spec := kp.trafficPolicies[name]
+			if spec.Action != action {
+				continue
+			}
+
+			clusters := spec.RemoteClusters
+			if len(clusters) == 0 {
+				clusters = sets.List(sets.KeySet(clusterSets))
+			}
+
+			if action == submV1.TrafficPolicyAllow {
+				defaultDeny.Insert(clusters...)
+			}
+
+			rules = append(rules, policyRules(spec, family, clusters, clusterSets, clusterSubnets, ruleAction)...)
+		}
+	}
+
+	for _, clusterID := range sets.List(defaultDeny) {
+		if setName, found := clusterSets[clusterID]; found {
+			rules = append(rules, &packetfilter.Rule{
+				SrcSetName: setName,
+				Action:     packetfilter.RuleActionDrop,
+			})
+		}
+	}
+
+	return rules
+}
+
+func policyRules(spec *submV1.ClusterTrafficPolicySpec, family k8snet.IPFamily, clusters []string,
+	clusterSets map[string]string, clusterSubnets map[string][]string, action packetfilter.RuleAction,
+) []*packetfilter.Rule {
+	var sources []trafficPolicySource
+
+	if len(spec.RemoteCIDRs) == 0 {
+		for _, clusterID := range clusters {
+			if setName, found := clusterSets[clusterID]; found {
+				sources = append(sources, trafficPolicySource{setName: setName})
+			}
+		}
+	} else {
+		for _, remoteCIDR := range filterCIDRs(spec.RemoteCIDRs, family) {
+			if len(spec.RemoteClusters) == 0 || containedInClusters(remoteCIDR, clusters, clusterSubnets) {
+				sources = append(sources, trafficPolicySource{cidr: remoteCIDR})
+			}
+		}
+	}
+
+	destinations := []string{""}
+
+	if len(spec.LocalCIDRs) > 0 {
+		destinations = filterCIDRs(spec.LocalCIDRs, family)
+	}
+
+	ports := []submV1.TrafficPolicyPort{{}}
+
+	if len(spec.Ports) > 0 {
+		ports = spec.Ports
+	}
+
+	var rules []*packetfilter.Rule
+
+	for _, source := range sources {
+		for _, destination := range destinations {
+			for i := range ports {
+				rule := &packetfilter.Rule{
+					SrcSetName: source.setName,
+					SrcCIDR:    source.cidr,
+					DestCIDR:   destination,
+					Action:     action,
+				}
+
+				if len(spec.Ports) > 0 {
+					rule.Proto = packetfilter.RuleProtoTCP
+					if ports[i].Protocol == corev1.ProtocolUDP {
+						rule.Proto = packetfilter.RuleProtoUDP
+					}
+
+					if ports[i].Port > 0 {
+						rule.DPort = strconv.Itoa(int(ports[i].Port))
+					}
+				}
+
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	return rules
+}
+
+// syncTrafficPolicyRules programs the given rules, which are evaluated in order, in one of two alternating chains and
+// then switches the jump from the traffic policy chain over to it so the traffic is never evaluated against an empty or
+// partially programmed rule set. Nothing is re-programmed if the rules haven't changed.
+func syncTrafficPolicyRules(pFilter packetfilter.Interface, rules []*packetfilter.Rule) error {
+	jumps, err := pFilter.List(packetfilter.TableTypeFilter, constants.SmTrafficPolicyChain)
+	if err != nil {
+		return errors.Wrapf(err, "error listing the rules in chain %q", constants.SmTrafficPolicyChain)
+	}
+
+	active := ""
+	if len(jumps) == 1 {
+		active = jumps[0].TargetChain
+	}
+
+	if active != "" && len(rules) > 0 {
+		existing, err := pFilter.List(packetfilter.TableTypeFilter, active)
+		if err != nil {
+			return errors.Wrapf(err, "error listing the rules in chain %q", active)
+		}
+
+		if equalRules(existing, rules) {
+			return nil
+		}
+	}
+
+	var jumpRules []*packetfilter.Rule
+
+	if len(rules) > 0 {
+		next := constants.SmTrafficPolicyChainA
+		if active == next {
+			next = constants.SmTrafficPolicyChainB
+		}
+
+		if err := pFilter.ClearChain(packetfilter.TableTypeFilter, next); err != nil {
+			return errors.Wrapf(err, "error flushing chain %q", next)
+		}
+
+		for _, rule := range rules {
+			logger.V(log.DEBUG).Infof("Installing ClusterTrafficPolicy rule: %s", rule)
+
+			if err := pFilter.Append(packetfilter.TableTypeFilter, next, rule); err != nil {
+				return errors.Wrapf(err, "error appending rule %q to chain %q", rule, next)
+			}
+		}
+
+		jumpRules = []*packetfilter.Rule{{
+			Action:      packetfilter.RuleActionJump,
+			TargetChain: next,
+		}}
+	}
+
+	if err := pFilter.UpdateChainRules(packetfilter.TableTypeFilter, constants.SmTrafficPolicyChain, jumpRules); err != nil {
+		return errors.Wrapf(err, "error updating the rules in chain %q", constants.SmTrafficPolicyChain)
+	}
+
+	if active != "" {
+		return errors.Wrapf(pFilter.ClearChain(packetfilter.TableTypeFilter, active), "error flushing chain %q", active)
+	}
+
+	return nil
+}
+
+func (kp *SyncHandler) syncTrafficPolicySet(pFilter packetfilter.Interface, family k8snet.IPFamily, setName string,
+	entries []string,
+) error {
+	set, found := kp.trafficPolicySets[setName]
+	if !found {
+		set = pFilter.NewNamedSet(&packetfilter.SetInfo{
+			Name:   setName,
+			Family: packetfilter.SetFamilyFor(family),
+		})
+
+		if err := set.Create(true); err != nil {
+			return errors.Wrapf(err, "error creating IP set %q", setName)
+		}
+
+		kp.trafficPolicySets[setName] = set
+	}
+
+	existing, err := set.ListEntries()
+	if err != nil {
+		return errors.Wrapf(err, "error listing the entries of IP set %q", setName)
+	}
+
+	desired := sets.New(entries...)
+
+	for _, entry := range existing {
+		if !desired.Has(entry) {
+			if err := set.DelEntry(entry); err != nil {
+				return errors.Wrapf(err, "error deleting entry %q from IP set %q", entry, setName)
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		if err := set.AddEntry(entry, true); err != nil {
+			return errors.Wrapf(err, "error adding entry %q to IP set %q", entry, setName)
+		}
+	}
+
+	return nil
+}
+
+func equalRules(a, b []*packetfilter.Rule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if *a[i] != *b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func filterCIDRs(cidrs []string, family k8snet.IPFamily) []string {
+	var filtered []string
+
+	for _, c := range cidrs {
+		if (family == k8snet.IPv6 && k8snet.IsIPv6CIDRString(c)) || (family == k8snet.IPv4 && k8snet.IsIPv4CIDRString(c)) {
+			filtered = append(filtered, c)
+		}
+	}
+
+	return filtered
+}
+
+// containedInClusters returns whether the given CIDR is within one of the subnets of the given remote clusters.
+func containedInClusters(cidrBlock string, clusters []string, clusterSubnets map[string][]string) bool {
+	_, cidrNet, err := net.ParseCIDR(cidrBlock)
+	if err != nil {
+		return false
+	}
+
+	cidrOnes, _ := cidrNet.Mask.Size()
+
+	for _, clusterID := range clusters {
+		for _, subnet := range clusterSubnets[clusterID] {
+			_, subnetNet, err := net.ParseCIDR(subnet)
+			if err != nil {
+				continue
+			}
+
+			subnetOnes, _ := subnetNet.Mask.Size()
+			if subnetNet.Contains(cidrNet.IP) && subnetOnes <= cidrOnes {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IP set names are global across IP families with iptables so each family uses its own prefix.
+func trafficPolicySetPrefix(family k8snet.IPFamily) string {
+	if family == k8snet.IPv6 {
+		return constants.TrafficPolicyIPv6SetPrefix
+	}
+
+	return constants.TrafficPolicyIPSetPrefix
+}
+
+func trafficPolicySetName(clusterID string, family k8snet.IPFamily) string {
+	hash := sha256.Sum256([]byte(clusterID))
+	return trafficPolicySetPrefix(family) + base32.StdEncoding.EncodeToString(hash[:])[:16]
+}
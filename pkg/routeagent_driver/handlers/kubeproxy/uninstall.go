@@ -20,6 +20,7 @@ package kubeproxy
 
 import (
 	"net"
+	"strings"
 
 	"github.com/submariner-io/admiral/pkg/log"
 	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
@@ -27,6 +28,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/port"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	"github.com/vishvananda/netlink"
+	k8snet "k8s.io/utils/net"
 )
 
 func (kp *SyncHandler) Uninstall() error {
@@ -40,14 +42,20 @@ func (kp *SyncHandler) Uninstall() error {
 			constants.RouteAgentHostNetworkTableID, err)
 	}
 
-	err = kp.netLink.RuleDelIfPresent(netlinkAPI.NewTableRule(constants.RouteAgentHostNetworkTableID))
-	if err != nil {
-		logger.V(log.TRACE).Infof("Deleting IP Rule pointing to %d table returned error: %v",
-			constants.RouteAgentHostNetworkTableID, err)
+	for _, rule := range kp.hostNetworkTableRules() {
+		err = kp.netLink.RuleDelIfPresent(rule)
+		if err != nil {
+			logger.V(log.TRACE).Infof("Deleting IP Rule pointing to %d table returned error: %v",
+				constants.RouteAgentHostNetworkTableID, err)
+		}
 	}
 
 	deleteVxLANInterface()
-	deleteIPTableChains()
+	deleteIPTableChains(k8snet.IPv4)
+
+	if kp.isDualStack() {
+		deleteIPTableChains(k8snet.IPv6)
+	}
 
 	return nil
 }
@@ -71,8 +79,8 @@ func deleteVxLANInterface() {
 	}
 }
 
-func deleteIPTableChains() {
-	pFilter, err := packetfilter.New()
+func deleteIPTableChains(family k8snet.IPFamily) {
+	pFilter, err := packetfilter.NewForFamily(family)
 	if err != nil {
 		logger.Errorf(err, "Failed to initialize packetfilter interface")
 		return
@@ -98,6 +106,28 @@ func deleteIPTableChains() {
 			constants.NATTable)
 	}
 
+	if err := pFilter.Delete(packetfilter.TableTypeFilter, constants.SmForwardChain, trafficPolicyJumpRule()); err != nil {
+		logger.Errorf(err, "Error deleting the jump rule to chain %q", constants.SmTrafficPolicyChain)
+	}
+
+	for _, chain := range trafficPolicyChains {
+		logger.Infof("Deleting packetfilter chain %q of %q table", chain, constants.FilterTable)
+
+		if err := pFilter.ClearChain(packetfilter.TableTypeFilter, chain); err != nil {
+			logger.Errorf(err, "Error flushing packetfilter chain %q of %q table", chain, constants.FilterTable)
+		}
+
+		if err := pFilter.DeleteChain(packetfilter.TableTypeFilter, chain); err != nil {
+			logger.Errorf(err, "Error deleting packetfilter chain %q of %q table", chain, constants.FilterTable)
+		}
+	}
+
+	if err := pFilter.DestroySets(func(name string) bool {
+		return strings.HasPrefix(name, trafficPolicySetPrefix(family))
+	}); err != nil {
+		logger.Errorf(err, "Error destroying the ClusterTrafficPolicy IP sets")
+	}
+
 	logger.Infof("Flushing iptable entries in %q chain of %q table", constants.SmInputChain, constants.FilterTable)
 
 	if err := pFilter.ClearChain(packetfilter.TableTypeFilter, constants.SmInputChain); err != nil {
@@ -98,6 +98,19 @@ func (kp *SyncHandler) createVxLANInterface(ifaceType int, gatewayNodeIP net.IP)
 		return errors.Wrap(err, "failed to configure vxlan interface ipaddress on the Gateway Node")
 	}
 
+	if kp.isDualStack() {
+		// The VxLAN tunnel itself runs over IPv4 but it carries the IPv6 inter-cluster traffic as well.
+		vtepIPv6, err := vxlan.GetVtepIPv6AddressFrom(ipAddr.String(), net.ParseIP(VxLANVTepIPv6NetworkPrefix))
+		if err != nil {
+			return errors.Wrapf(err, "failed to derive the vxlan IPv6 vtepIP for %s", ipAddr)
+		}
+
+		err = kp.vxlanDevice.ConfigureIPAddress(vtepIPv6, net.CIDRMask(96, 128))
+		if err != nil {
+			return errors.Wrap(err, "failed to configure vxlan interface IPv6 address")
+		}
+	}
+
 	err = kp.netLink.EnableForwarding(VxLANIface)
 	if err != nil {
 		return errors.Wrapf(err, "error enabling forwarding on the %q iface", VxLANIface)
@@ -19,18 +19,27 @@ limitations under the License.
 package mtu
 
 import (
+	"crypto/sha256"
+	"encoding/base32"
+	"maps"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/admiral/pkg/watcher"
 	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	vxlandriver "github.com/submariner-io/submariner/pkg/cable/vxlan"
 	"github.com/submariner-io/submariner/pkg/cidr"
 	"github.com/submariner-io/submariner/pkg/event"
 	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
+	"github.com/submariner-io/submariner/pkg/packetfilter/configure"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	"github.com/submariner-io/submariner/pkg/vxlan"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	k8snet "k8s.io/utils/net"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -46,21 +55,51 @@ const (
 const (
 	// TCP MSS = Default_Iface_MTU - TCP_H(20)-IP_H(20)-max_IpsecOverhed(80).
 	MaxIPSecOverhead = 120
+	// The inner IP and TCP headers, which vxlan.MTUOverhead doesn't include.
+	ipTCPOverhead = 40
 )
 
 type mtuHandler struct {
 	event.HandlerBase
+	mutex            sync.Mutex
 	localClusterCidr []string
 	pFilter          packetfilter.Interface
 	remoteIPSet      packetfilter.NamedSet
 	localIPSet       packetfilter.NamedSet
 	forceMss         forceMssSts
 	tcpMssValue      int
+	forcedMssValue   int
+	watcherConfig    *watcher.Config
+	namespace        string
+	stopCh           chan struct{}
+	// IP sets holding the CIDRs of each remote cluster, keyed by cluster ID.
+	clusterIPSets map[string]packetfilter.NamedSet
+	// TCP MSS values derived from the path MTU measured by each active Gateway, keyed by Gateway name then cluster ID.
+	gatewayMssValues map[string]map[string]int
 }
 
 var logger = log.Logger{Logger: logf.Log.WithName("MTU")}
 
-func NewMTUHandler(localClusterCidr []string, isGlobalnet bool, tcpMssValue int) event.Handler {
+var mssClampChain = packetfilter.ChainIPHook{
+	Name:     constants.SmPostRoutingChain,
+	Type:     packetfilter.ChainTypeRoute,
+	Hook:     packetfilter.ChainHookPostrouting,
+	Priority: packetfilter.ChainPriorityFirst,
+}
+
+// PacketFilterMigrationSpec returns the chains programmed by the MTU handler.
+func PacketFilterMigrationSpec() *configure.MigrationSpec {
+	return &configure.MigrationSpec{
+		IPHookChains: []packetfilter.ChainIPHook{mssClampChain},
+	}
+}
+
+// NewMTUHandler creates a handler which clamps the TCP MSS of the traffic between the local and remote clusters. If a
+// watcher config is given, the path MTU measured by the active Gateways is used to clamp the MSS per remote cluster,
+// unless the MSS is forced to a specific value.
+func NewMTUHandler(localClusterCidr []string, isGlobalnet bool, tcpMssValue int, watcherConfig *watcher.Config,
+	namespace string,
+) event.Handler {
 	forceMss := notNeeded
 	if isGlobalnet || tcpMssValue != 0 {
 		forceMss = needed
@@ -70,6 +109,11 @@ func NewMTUHandler(localClusterCidr []string, isGlobalnet bool, tcpMssValue int)
 		localClusterCidr: cidr.ExtractIPv4Subnets(localClusterCidr),
 		forceMss:         forceMss,
 		tcpMssValue:      tcpMssValue,
+		watcherConfig:    watcherConfig,
+		namespace:        namespace,
+		stopCh:           make(chan struct{}),
+		clusterIPSets:    map[string]packetfilter.NamedSet{},
+		gatewayMssValues: map[string]map[string]int{},
 	}
 }
 
@@ -89,12 +133,7 @@ func (h *mtuHandler) Init() error {
 		return errors.Wrap(err, "error initializing iptables")
 	}
 
-	if err := h.pFilter.CreateIPHookChainIfNotExists(&packetfilter.ChainIPHook{
-		Name:     constants.SmPostRoutingChain,
-		Type:     packetfilter.ChainTypeRoute,
-		Hook:     packetfilter.ChainHookPostrouting,
-		Priority: packetfilter.ChainPriorityFirst,
-	}); err != nil {
+	if err := h.pFilter.CreateIPHookChainIfNotExists(&mssClampChain); err != nil {
 		return errors.Wrapf(err, "error creating IPHookChain chain %s", constants.SmPostRoutingChain)
 	}
 
@@ -108,6 +147,10 @@ func (h *mtuHandler) Init() error {
 		return errors.Wrapf(err, "error creating ipset %q", constants.LocalCIDRIPSet)
 	}
 
+	if err := h.startGatewayWatcher(); err != nil {
+		return err
+	}
+
 	// packetfilter rules to clamp TCP MSS to a fixed value will be programmed when the local endpoint is created
 	if h.forceMss == needed {
 		return nil
@@ -115,32 +158,55 @@ func (h *mtuHandler) Init() error {
 
 	logger.Info("Creating packetfilter clamp-mss-to-pmtu rules")
 
-	ruleSpecSource := &packetfilter.Rule{
-		SrcSetName:  constants.LocalCIDRIPSet,
-		DestSetName: constants.RemoteCIDRIPSet,
-		Action:      packetfilter.RuleActionMss,
-		ClampType:   packetfilter.ToPMTU,
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if err := h.syncClampRules(); err != nil {
+		return err
 	}
 
-	ruleSpecDest := &packetfilter.Rule{
-		SrcSetName:  constants.RemoteCIDRIPSet,
-		DestSetName: constants.LocalCIDRIPSet,
-		Action:      packetfilter.RuleActionMss,
-		ClampType:   packetfilter.ToPMTU,
+	// Remove the IP sets of remote clusters left over by a previous instance, they're re-created as remote endpoints
+	// are processed.
+	return errors.Wrap(h.pFilter.DestroySets(isClusterIPSet), "error destroying the remote cluster IP sets")
+}
+
+func (h *mtuHandler) startGatewayWatcher() error {
+	if h.watcherConfig == nil {
+		return nil
 	}
 
-	if err := h.pFilter.AppendUnique(packetfilter.TableTypeRoute, constants.SmPostRoutingChain, ruleSpecSource); err != nil {
-		return errors.Wrapf(err, "error appending packetfilter rule %q", ruleSpecSource)
+	config := *h.watcherConfig
+	config.ResourceConfigs = []watcher.ResourceConfig{
+		{
+			Name:         "MTU Gateway watcher",
+			ResourceType: &submV1.Gateway{},
+			Handler: watcher.EventHandlerFuncs{
+				OnCreateFunc: h.gatewayCreatedOrUpdated,
+				OnUpdateFunc: h.gatewayCreatedOrUpdated,
+				OnDeleteFunc: h.gatewayDeleted,
+			},
+			SourceNamespace: h.namespace,
+		},
 	}
 
-	if err := h.pFilter.AppendUnique(packetfilter.TableTypeRoute, constants.SmPostRoutingChain, ruleSpecDest); err != nil {
-		return errors.Wrapf(err, "error appending packetfilter rule %q", ruleSpecDest)
+	gatewayWatcher, err := watcher.New(&config)
+	if err != nil {
+		return errors.Wrap(err, "error creating the Gateway watcher")
 	}
 
+	return errors.Wrap(gatewayWatcher.Start(h.stopCh), "error starting the Gateway watcher")
+}
+
+func (h *mtuHandler) Stop() error {
+	close(h.stopCh)
+
 	return nil
 }
 
 func (h *mtuHandler) LocalEndpointCreated(endpoint *submV1.Endpoint) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	subnets := extractIPv4Subnets(&endpoint.Spec)
 	for _, subnet := range subnets {
 		err := h.localIPSet.AddEntry(subnet, true)
@@ -163,14 +229,15 @@ func (h *mtuHandler) LocalEndpointCreated(endpoint *submV1.Endpoint) error {
 		if err != nil {
 			return errors.Wrap(err, "error forcing TCP MSS clamping")
 		}
-
-		h.forceMss = configured
 	}
 
 	return nil
 }
 
 func (h *mtuHandler) LocalEndpointRemoved(endpoint *submV1.Endpoint) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	subnets := extractIPv4Subnets(&endpoint.Spec)
 	for _, subnet := range subnets {
 		logError(h.localIPSet.DelEntry(subnet), "Error deleting the subnet %q from the local IPSet", subnet)
@@ -184,6 +251,9 @@ func (h *mtuHandler) LocalEndpointRemoved(endpoint *submV1.Endpoint) error {
 }
 
 func (h *mtuHandler) RemoteEndpointCreated(endpoint *submV1.Endpoint) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	subnets := extractIPv4Subnets(&endpoint.Spec)
 	for _, subnet := range subnets {
 		err := h.remoteIPSet.AddEntry(subnet, true)
@@ -192,18 +262,201 @@ func (h *mtuHandler) RemoteEndpointCreated(endpoint *submV1.Endpoint) error {
 		}
 	}
 
+	clusterIPSet, found := h.clusterIPSets[endpoint.Spec.ClusterID]
+	if !found {
+		clusterIPSet = h.newNamedSetSet(clusterIPSetName(endpoint.Spec.ClusterID))
+		if err := clusterIPSet.Create(true); err != nil {
+			return errors.Wrapf(err, "error creating ipset %q", clusterIPSet.Name())
+		}
+	}
+
+	for _, subnet := range subnets {
+		err := clusterIPSet.AddEntry(subnet, true)
+		if err != nil {
+			return errors.Wrap(err, "error adding remote cluster IP set entry")
+		}
+	}
+
+	if !found {
+		h.clusterIPSets[endpoint.Spec.ClusterID] = clusterIPSet
+
+		return h.syncClampRules()
+	}
+
 	return nil
 }
 
 func (h *mtuHandler) RemoteEndpointRemoved(endpoint *submV1.Endpoint) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
 	subnets := extractIPv4Subnets(&endpoint.Spec)
 	for _, subnet := range subnets {
 		logError(h.remoteIPSet.DelEntry(subnet), "Error deleting the subnet %q from the remote IPSet", subnet)
 	}
 
+	clusterIPSet, found := h.clusterIPSets[endpoint.Spec.ClusterID]
+	if !found {
+		return nil
+	}
+
+	for _, subnet := range subnets {
+		logError(clusterIPSet.DelEntry(subnet), "Error deleting the subnet %q from IPSet %q", subnet, clusterIPSet.Name())
+	}
+
+	entries, err := clusterIPSet.ListEntries()
+	if err != nil || len(entries) > 0 {
+		return errors.Wrapf(err, "error listing the entries of IPSet %q", clusterIPSet.Name())
+	}
+
+	delete(h.clusterIPSets, endpoint.Spec.ClusterID)
+
+	if err := h.syncClampRules(); err != nil {
+		return err
+	}
+
+	logError(clusterIPSet.Destroy(), "Error deleting ipset %q", clusterIPSet.Name())
+
 	return nil
 }
 
+func (h *mtuHandler) gatewayCreatedOrUpdated(obj runtime.Object, _ int) bool {
+	gateway := obj.(*submV1.Gateway)
+	mssValues := map[string]int{}
+
+	if gateway.Status.HAStatus == submV1.HAStatusActive {
+		for i := range gateway.Status.Connections {
+			connection := &gateway.Status.Connections[i]
+			if connection.PathMTU == 0 {
+				continue
+			}
+
+			backend := connection.CableDriver
+			if backend == "" {
+				backend = connection.Endpoint.Backend
+			}
+
+			mss := tcpMssFor(connection.PathMTU, backend)
+			if current, ok := mssValues[connection.Endpoint.ClusterID]; !ok || mss < current {
+				mssValues[connection.Endpoint.ClusterID] = mss
+			}
+		}
+	}
+
+	return h.setGatewayMssValues(gateway.Name, mssValues)
+}
+
+func (h *mtuHandler) gatewayDeleted(obj runtime.Object, _ int) bool {
+	return h.setGatewayMssValues(obj.(*submV1.Gateway).Name, map[string]int{})
+}
+
+func (h *mtuHandler) setGatewayMssValues(gatewayName string, mssValues map[string]int) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if maps.Equal(h.gatewayMssValues[gatewayName], mssValues) {
+		return false
+	}
+
+	logger.Infof("TCP MSS values derived from the path MTU measured by Gateway %q: %v", gatewayName, mssValues)
+
+	if len(mssValues) == 0 {
+		delete(h.gatewayMssValues, gatewayName)
+	} else {
+		h.gatewayMssValues[gatewayName] = mssValues
+	}
+
+	if err := h.syncClampRules(); err != nil {
+		logger.Errorf(err, "Error updating the TCP MSS clamping rules")
+
+		return true
+	}
+
+	return false
+}
+
+// clusterMssValue returns the lowest TCP MSS value derived from the path MTU to the given remote cluster measured by
+// the active Gateways, or 0 if there's none.
+func (h *mtuHandler) clusterMssValue(clusterID string) int {
+	mss := 0
+
+	for _, mssValues := range h.gatewayMssValues {
+		if value, ok := mssValues[clusterID]; ok && (mss == 0 || value < mss) {
+			mss = value
+		}
+	}
+
+	return mss
+}
+
+// syncClampRules programs the rules which clamp the TCP MSS of the traffic between the local and remote CIDRs, either to
+// the PMTU or to a fixed value, followed by the rules which clamp it per remote cluster with a measured path MTU. As MSS
+// clamping never increases the MSS, the lowest value applies.
+func (h *mtuHandler) syncClampRules() error {
+	var rules []*packetfilter.Rule
+
+	switch h.forceMss {
+	case notNeeded:
+		rules = append(rules, clampRules(constants.RemoteCIDRIPSet, packetfilter.ToPMTU, "")...)
+	case configured:
+		rules = append(rules, clampRules(constants.RemoteCIDRIPSet, packetfilter.ToValue, strconv.Itoa(h.forcedMssValue))...)
+	case needed:
+	}
+
+	// A TCP MSS value forced by the user overrides the measured values.
+	if h.tcpMssValue == 0 {
+		for _, clusterID := range sets.List(sets.KeySet(h.clusterIPSets)) {
+			if mss := h.clusterMssValue(clusterID); mss > 0 {
+				rules = append(rules, clampRules(h.clusterIPSets[clusterID].Name(), packetfilter.ToValue, strconv.Itoa(mss))...)
+			}
+		}
+	}
+
+	if err := h.pFilter.UpdateChainRules(packetfilter.TableTypeRoute, constants.SmPostRoutingChain, rules); err != nil {
+		return errors.Wrapf(err, "error updating chain %s table type Route", constants.SmPostRoutingChain)
+	}
+
+	return nil
+}
+
+func clampRules(remoteSetName string, clampType packetfilter.MssClampType, mssValue string) []*packetfilter.Rule {
+	return []*packetfilter.Rule{
+		{
+			SrcSetName:  constants.LocalCIDRIPSet,
+			DestSetName: remoteSetName,
+			Action:      packetfilter.RuleActionMss,
+			ClampType:   clampType,
+			MssValue:    mssValue,
+		},
+		{
+			SrcSetName:  remoteSetName,
+			DestSetName: constants.LocalCIDRIPSet,
+			Action:      packetfilter.RuleActionMss,
+			ClampType:   clampType,
+			MssValue:    mssValue,
+		},
+	}
+}
+
+func clusterIPSetName(clusterID string) string {
+	hash := sha256.Sum256([]byte(clusterID))
+	return constants.RemoteClusterCIDRIPSetPrefix + base32.StdEncoding.EncodeToString(hash[:])[:16]
+}
+
+func isClusterIPSet(name string) bool {
+	return strings.HasPrefix(name, constants.RemoteClusterCIDRIPSetPrefix)
+}
+
+// tcpMssFor returns the TCP MSS value for the given MTU, accounting for the overhead of the given cable driver.
+func tcpMssFor(mtu int, backend string) int {
+	overHeadSize := MaxIPSecOverhead
+	if backend == vxlandriver.CableDriverName {
+		overHeadSize = vxlan.MTUOverhead + ipTCPOverhead
+	}
+
+	return mtu - overHeadSize
+}
+
 func extractIPv4Subnets(endpoint *submV1.EndpointSpec) []string {
 	subnets := make([]string, 0, len(endpoint.Subnets))
 
@@ -232,12 +485,7 @@ func (h *mtuHandler) Uninstall() error {
 
 	logger.Infof("Deleting IPHook chain %q of table type Route", constants.SmPostRoutingChain)
 
-	logError(h.pFilter.DeleteIPHookChain(&packetfilter.ChainIPHook{
-		Name:     constants.SmPostRoutingChain,
-		Type:     packetfilter.ChainTypeRoute,
-		Hook:     packetfilter.ChainHookPostrouting,
-		Priority: packetfilter.ChainPriorityFirst,
-	}), "Error deleting IP hook chain %q of table type Route", constants.SmPostRoutingChain)
+	logError(h.pFilter.DeleteIPHookChain(&mssClampChain), "Error deleting IP hook chain %q of table type Route", constants.SmPostRoutingChain)
 
 	logError(h.localIPSet.Flush(), "Error flushing ipset %q", constants.LocalCIDRIPSet)
 
@@ -247,6 +495,8 @@ func (h *mtuHandler) Uninstall() error {
 
 	logError(h.remoteIPSet.Destroy(), "Error deleting ipset %q", constants.RemoteCIDRIPSet)
 
+	logError(h.pFilter.DestroySets(isClusterIPSet), "Error deleting the remote cluster ipsets")
+
 	return nil
 }
 
@@ -260,36 +510,18 @@ func (h *mtuHandler) forceMssClamping(endpoint *submV1.Endpoint) error {
 			return errors.Wrapf(err, "Unable to find the default interface on host")
 		}
 
-		overHeadSize := MaxIPSecOverhead
-		if endpoint.Spec.Backend == vxlandriver.CableDriverName {
-			overHeadSize = vxlan.MTUOverhead
-		}
-
-		tcpMssValue = defaultHostIface.MTU - overHeadSize
+		tcpMssValue = tcpMssFor(defaultHostIface.MTU, endpoint.Spec.Backend)
 		tcpMssSrc = "default"
 	}
 
 	logger.Infof("forceMssClamping to: %d (%s) ", tcpMssValue, tcpMssSrc)
 
-	rules := []*packetfilter.Rule{}
-
-	rules = append(rules, &packetfilter.Rule{
-		SrcSetName:  constants.LocalCIDRIPSet,
-		DestSetName: constants.RemoteCIDRIPSet,
-		Action:      packetfilter.RuleActionMss,
-		ClampType:   packetfilter.ToValue,
-		MssValue:    strconv.Itoa(tcpMssValue),
-	}, &packetfilter.Rule{
-		DestSetName: constants.LocalCIDRIPSet,
-		SrcSetName:  constants.RemoteCIDRIPSet,
-		Action:      packetfilter.RuleActionMss,
-		ClampType:   packetfilter.ToValue,
-		MssValue:    strconv.Itoa(tcpMssValue),
-	},
-	)
+	h.forcedMssValue = tcpMssValue
+	h.forceMss = configured
 
-	if err := h.pFilter.UpdateChainRules(packetfilter.TableTypeRoute, constants.SmPostRoutingChain, rules); err != nil {
-		return errors.Wrapf(err, "error updating chain %s table type Route", constants.SmPostRoutingChain)
+	if err := h.syncClampRules(); err != nil {
+		h.forceMss = needed
+		return err
 	}
 
 	return nil
@@ -23,10 +23,14 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/submariner-io/admiral/pkg/log/kzerolog"
+	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	kubeScheme "k8s.io/client-go/kubernetes/scheme"
 )
 
 func init() {
 	kzerolog.AddFlags(nil)
+	utilruntime.Must(submV1.AddToScheme(kubeScheme.Scheme))
 }
 
 var _ = BeforeSuite(func() {
@@ -18,10 +18,16 @@ limitations under the License.
 package mtu_test
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
 	"strconv"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/submariner-io/admiral/pkg/resource"
+	"github.com/submariner-io/admiral/pkg/syncer/test"
+	"github.com/submariner-io/admiral/pkg/watcher"
 	submV1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/event"
 	netlinkAPI "github.com/submariner-io/submariner/pkg/netlink"
@@ -29,9 +35,17 @@ import (
 	fakePF "github.com/submariner-io/submariner/pkg/packetfilter/fake"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/mtu"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubeScheme "k8s.io/client-go/kubernetes/scheme"
 )
 
-const localCIDR = "10.1.0.0/24"
+const (
+	localCIDR       = "10.1.0.0/24"
+	namespace       = "submariner"
+	remoteClusterID = "west"
+)
 
 var _ = Describe("MTUHandler", func() {
 	t := newTestDriver()
@@ -113,6 +127,98 @@ var _ = Describe("MTUHandler", func() {
 		})
 	})
 
+	When("an active Gateway publishes the path MTU to a remote cluster", func() {
+		var remoteEndpoint *submV1.Endpoint
+
+		BeforeEach(func() {
+			remoteEndpoint = newSubmEndpoint([]string{"10.0.0.0/24", "172.0.0.0/24"})
+			remoteEndpoint.Spec.ClusterID = remoteClusterID
+		})
+
+		JustBeforeEach(func() {
+			Expect(t.handler.RemoteEndpointCreated(remoteEndpoint)).To(Succeed())
+			t.createGateway(1400)
+		})
+
+		It("should add the remote cluster IP set and clamp the MSS for it", func() {
+			clusterIPSet := clusterIPSetName(remoteClusterID)
+			t.pFilter.AwaitSet(Equal(clusterIPSet))
+
+			for _, subnet := range remoteEndpoint.Spec.Subnets {
+				t.pFilter.AwaitEntry(clusterIPSet, subnet)
+			}
+
+			t.awaitClusterMSSRules(clusterIPSet, 1400-mtu.MaxIPSecOverhead)
+
+			t.pFilter.AwaitRule(packetfilter.TableTypeRoute,
+				constants.SmPostRoutingChain, And(
+					ContainSubstring("\"ClampType\":%d", packetfilter.ToPMTU),
+					ContainSubstring("\"SrcSetName\":%q", constants.RemoteCIDRIPSet)))
+
+			t.pFilter.EnsureChainsIncludedIn(mtu.PacketFilterMigrationSpec().Includes)
+
+			By("Updating the path MTU")
+
+			t.updateGateway(1300)
+			t.awaitClusterMSSRules(clusterIPSet, 1300-mtu.MaxIPSecOverhead)
+
+			By("Deleting the Gateway")
+
+			Expect(t.gateways.Delete(context.Background(), gatewayName, metav1.DeleteOptions{})).To(Succeed())
+			t.pFilter.AwaitNoRule(packetfilter.TableTypeRoute, constants.SmPostRoutingChain,
+				ContainSubstring("\"SrcSetName\":%q", clusterIPSet))
+
+			By("Removing the remote Endpoint")
+
+			Expect(t.handler.RemoteEndpointRemoved(remoteEndpoint)).To(Succeed())
+			t.pFilter.AwaitSetDeleted(clusterIPSet)
+		})
+
+		Context("and TCP MSS is forced to a specific value", func() {
+			BeforeEach(func() {
+				t.tcpMssValue = 1000
+			})
+
+			It("should not clamp the MSS for the remote cluster", func() {
+				t.testForcedMSS(t.tcpMssValue)
+
+				clusterIPSet := clusterIPSetName(remoteClusterID)
+				t.pFilter.AwaitSet(Equal(clusterIPSet))
+				t.pFilter.EnsureNoRule(packetfilter.TableTypeRoute, constants.SmPostRoutingChain,
+					ContainSubstring("\"SrcSetName\":%q", clusterIPSet))
+			})
+		})
+
+		Context("over the vxlan cable driver", func() {
+			BeforeEach(func() {
+				t.cableDriver = "vxlan"
+			})
+
+			It("should clamp the MSS to the path MTU less the VxLAN and inner IP and TCP headers", func() {
+				// 1400 - outer IP(20) - UDP(8) - VxLAN(8) - inner Ethernet(14) - inner IP(20) - TCP(20)
+				t.awaitClusterMSSRules(clusterIPSetName(remoteClusterID), 1310)
+			})
+		})
+
+		Context("and Globalnet is enabled", func() {
+			BeforeEach(func() {
+				t.isGlobalnet = true
+			})
+
+			It("should clamp the MSS for the remote cluster", func() {
+				Expect(t.handler.LocalEndpointCreated(newSubmEndpoint([]string{"172.1.0.0/24"}))).To(Succeed())
+
+				clusterIPSet := clusterIPSetName(remoteClusterID)
+				t.pFilter.AwaitSet(Equal(clusterIPSet))
+				t.awaitClusterMSSRules(clusterIPSet, 1400-mtu.MaxIPSecOverhead)
+				t.pFilter.AwaitRule(packetfilter.TableTypeRoute,
+					constants.SmPostRoutingChain, And(
+						ContainSubstring("\"ClampType\":%d", packetfilter.ToValue),
+						ContainSubstring("\"SrcSetName\":%q", constants.RemoteCIDRIPSet)))
+			})
+		})
+	})
+
 	Specify("Uninstall should remove IP sets and chains", func() {
 		Expect(t.handler.Uninstall()).To(Succeed())
 
@@ -122,11 +228,15 @@ var _ = Describe("MTUHandler", func() {
 	})
 })
 
+const gatewayName = "gateway-node"
+
 type testDriver struct {
 	pFilter     *fakePF.PacketFilter
 	handler     event.Handler
+	gateways    dynamic.ResourceInterface
 	tcpMssValue int
 	isGlobalnet bool
+	cableDriver string
 }
 
 func newTestDriver() *testDriver {
@@ -135,12 +245,21 @@ func newTestDriver() *testDriver {
 	BeforeEach(func() {
 		t.tcpMssValue = 0
 		t.isGlobalnet = false
+		t.cableDriver = ""
 		t.pFilter = fakePF.New()
 	})
 
 	JustBeforeEach(func() {
-		t.handler = mtu.NewMTUHandler([]string{localCIDR}, t.isGlobalnet, t.tcpMssValue)
+		dynClient := dynamicfake.NewSimpleDynamicClient(kubeScheme.Scheme)
+		restMapper := test.GetRESTMapperFor(&submV1.Gateway{})
+		t.gateways = dynClient.Resource(*test.GetGroupVersionResourceFor(restMapper, &submV1.Gateway{})).Namespace(namespace)
+
+		t.handler = mtu.NewMTUHandler([]string{localCIDR}, t.isGlobalnet, t.tcpMssValue, &watcher.Config{
+			RestMapper: restMapper,
+			Client:     dynClient,
+		}, namespace)
 		Expect(t.handler.Init()).To(Succeed())
+		DeferCleanup(t.handler.Stop)
 	})
 
 	return t
@@ -168,6 +287,59 @@ func (t *testDriver) testForcedMSS(expTCPMssValue int) {
 			ContainSubstring("\"MssValue\":%q", strconv.Itoa(expTCPMssValue))))
 }
 
+func (t *testDriver) awaitClusterMSSRules(clusterIPSet string, expTCPMssValue int) {
+	t.pFilter.AwaitRule(packetfilter.TableTypeRoute,
+		constants.SmPostRoutingChain, And(
+			ContainSubstring("\"ClampType\":%d", packetfilter.ToValue),
+			ContainSubstring("\"SrcSetName\":%q", clusterIPSet),
+			ContainSubstring("\"DestSetName\":%q", constants.LocalCIDRIPSet),
+			ContainSubstring("\"MssValue\":%q", strconv.Itoa(expTCPMssValue))))
+	t.pFilter.AwaitRule(packetfilter.TableTypeRoute,
+		constants.SmPostRoutingChain, And(
+			ContainSubstring("\"ClampType\":%d", packetfilter.ToValue),
+			ContainSubstring("\"SrcSetName\":%q", constants.LocalCIDRIPSet),
+			ContainSubstring("\"DestSetName\":%q", clusterIPSet),
+			ContainSubstring("\"MssValue\":%q", strconv.Itoa(expTCPMssValue))))
+}
+
+func (t *testDriver) createGateway(pathMTU int) {
+	_, err := t.gateways.Create(context.Background(), resource.MustToUnstructured(newGateway(pathMTU, t.cableDriver)), metav1.CreateOptions{})
+	Expect(err).To(Succeed())
+}
+
+func (t *testDriver) updateGateway(pathMTU int) {
+	_, err := t.gateways.Update(context.Background(), resource.MustToUnstructured(newGateway(pathMTU, t.cableDriver)), metav1.UpdateOptions{})
+	Expect(err).To(Succeed())
+}
+
+func clusterIPSetName(clusterID string) string {
+	hash := sha256.Sum256([]byte(clusterID))
+	return constants.RemoteClusterCIDRIPSetPrefix + base32.StdEncoding.EncodeToString(hash[:])[:16]
+}
+
+func newGateway(pathMTU int, cableDriver string) *submV1.Gateway {
+	return &submV1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gatewayName,
+			Namespace: namespace,
+		},
+		Status: submV1.GatewayStatus{
+			HAStatus: submV1.HAStatusActive,
+			Connections: []submV1.Connection{
+				{
+					Status: submV1.Connected,
+					Endpoint: submV1.EndpointSpec{
+						ClusterID: remoteClusterID,
+						Backend:   "libreswan",
+					},
+					PathMTU:     pathMTU,
+					CableDriver: cableDriver,
+				},
+			},
+		},
+	}
+}
+
 func newSubmEndpoint(subnets []string) *submV1.Endpoint {
 	return &submV1.Endpoint{
 		Spec: submV1.EndpointSpec{
@@ -162,7 +162,8 @@ func (c *OVSDBClient) hasModel(m any) bool {
 		switch t := m.(type) {
 		case *nbdb.LogicalRouterPolicy:
 			if strings.Contains(o.(*nbdb.LogicalRouterPolicy).Match, t.Match) &&
-				reflect.DeepEqual(o.(*nbdb.LogicalRouterPolicy).Nexthop, t.Nexthop) {
+				reflect.DeepEqual(o.(*nbdb.LogicalRouterPolicy).Nexthop, t.Nexthop) &&
+				reflect.DeepEqual(o.(*nbdb.LogicalRouterPolicy).Nexthops, t.Nexthops) {
 				return true
 			}
 		case *nbdb.LogicalRouterStaticRoute:
@@ -25,14 +25,14 @@ import (
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
 	"github.com/submariner-io/submariner/pkg/packetfilter"
+	"github.com/submariner-io/submariner/pkg/packetfilter/configure"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
-	"github.com/vishvananda/netlink"
 )
 
 func (ovn *Handler) cleanupGatewayDataplane() error {
-	currentRemoteSubnets, err := ovn.getExistingIPv4RuleSubnets()
+	currentRemoteSubnets, err := ovn.getExistingRuleSubnets()
 	if err != nil {
-		return errors.Wrapf(err, "error reading ip rule list for IPv4")
+		return errors.Wrapf(err, "error reading the inter-cluster ip rule list")
 	}
 
 	err = ovn.handleSubnets(currentRemoteSubnets.UnsortedList(), ovn.netLink.RuleDel, os.IsNotExist)
@@ -40,14 +40,16 @@ func (ovn *Handler) cleanupGatewayDataplane() error {
 		return errors.Wrapf(err, "error removing routing rule")
 	}
 
-	defaultRoute, err := ovn.getRouteToOVNDataPlane()
+	defaultRoutes, err := ovn.getRoutesToOVNDataPlane(constants.RouteAgentInterClusterNetworkTableID)
 	if err != nil {
-		return errors.Wrap(err, "error creating default route")
+		return errors.Wrap(err, "error creating default routes")
 	}
 
-	err = ovn.netLink.RouteDel(defaultRoute)
-	if err != nil && !os.IsNotExist(err) {
-		return errors.Wrap(err, "error deleting submariner default route")
+	for _, route := range defaultRoutes {
+		err = ovn.netLink.RouteDel(route)
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "error deleting submariner default route")
+		}
 	}
 
 	return ovn.cleanupForwardingIptables()
@@ -57,12 +59,12 @@ func (ovn *Handler) updateGatewayDataplane() error {
 	ovn.mutex.Lock()
 	defer ovn.mutex.Unlock()
 
-	currentRuleRemotes, err := ovn.getExistingIPv4RuleSubnets()
+	currentRuleRemotes, err := ovn.getExistingRuleSubnets()
 	if err != nil {
-		return errors.Wrapf(err, "error reading ip rule list for IPv4")
+		return errors.Wrapf(err, "error reading the inter-cluster ip rule list")
 	}
 
-	endpointSubnets := ovn.getRemoteSubnets()
+	endpointSubnets := ovn.getRoutableRemoteSubnets()
 
 	toAdd := endpointSubnets.Difference(currentRuleRemotes).UnsortedList()
 
@@ -78,14 +80,16 @@ func (ovn *Handler) updateGatewayDataplane() error {
 		return errors.Wrapf(err, "error removing routing rule")
 	}
 
-	defaultRoute, err := ovn.getRouteToOVNDataPlane()
+	defaultRoutes, err := ovn.getRoutesToOVNDataPlane(constants.RouteAgentInterClusterNetworkTableID)
 	if err != nil {
-		return errors.Wrap(err, "error creating default route")
+		return errors.Wrap(err, "error creating default routes")
 	}
 
-	err = ovn.netLink.RouteAdd(defaultRoute)
-	if err != nil && !os.IsExist(err) {
-		return errors.Wrap(err, "error adding submariner default")
+	for _, route := range defaultRoutes {
+		err = ovn.netLink.RouteAdd(route)
+		if err != nil && !os.IsExist(err) {
+			return errors.Wrap(err, "error adding submariner default")
+		}
 	}
 
 	return ovn.setupForwardingIptables()
@@ -220,27 +224,41 @@ func (ovn *Handler) cleanupForwardingIptables() error {
 	}), "error clearing chain %q", ForwardingSubmarinerFWDChain)
 }
 
-func (ovn *Handler) getRouteToOVNDataPlane() (*netlink.Route, error) {
-	nextHop, err := ovn.getNextHopOnK8sMgmtIntf()
-	if err != nil {
-		return nil, errors.Wrapf(err, "getNextHopOnK8sMgmtIntf returned error")
+var (
+	postRoutingChain = packetfilter.ChainIPHook{
+		Name:     constants.SmPostRoutingChain,
+		Type:     packetfilter.ChainTypeNAT,
+		Hook:     packetfilter.ChainHookPostrouting,
+		Priority: packetfilter.ChainPriorityFirst,
 	}
 
-	return &netlink.Route{
-		Gw:    *nextHop,
-		Table: constants.RouteAgentInterClusterNetworkTableID,
-	}, nil
+	forwardChains = []packetfilter.ChainIPHook{
+		{
+			Name:     ForwardingSubmarinerFWDChain,
+			Type:     packetfilter.ChainTypeFilter,
+			Hook:     packetfilter.ChainHookForward,
+			Priority: packetfilter.ChainPriorityFirst,
+		},
+		{
+			Name:     ForwardingSubmarinerMSSClampChain,
+			Type:     packetfilter.ChainTypeFilter,
+			Hook:     packetfilter.ChainHookForward,
+			Priority: packetfilter.ChainPriorityFirst,
+		},
+	}
+)
+
+// PacketFilterMigrationSpec returns the chains programmed by the OVN handler.
+func PacketFilterMigrationSpec() *configure.MigrationSpec {
+	return &configure.MigrationSpec{
+		IPHookChains: append([]packetfilter.ChainIPHook{postRoutingChain}, forwardChains...),
+	}
 }
 
 func (ovn *Handler) initIPtablesChains() error {
 	logger.V(log.DEBUG).Infof("Install/ensure %q/%s IPHook chain exists", constants.SmPostRoutingChain, "NAT")
 
-	if err := ovn.pFilter.CreateIPHookChainIfNotExists(&packetfilter.ChainIPHook{
-		Name:     constants.SmPostRoutingChain,
-		Type:     packetfilter.ChainTypeNAT,
-		Hook:     packetfilter.ChainHookPostrouting,
-		Priority: packetfilter.ChainPriorityFirst,
-	}); err != nil {
+	if err := ovn.pFilter.CreateIPHookChainIfNotExists(&postRoutingChain); err != nil {
 		return errors.Wrapf(err, "error installing %q IPHook chain", constants.SmPostRoutingChain)
 	}
 
@@ -252,14 +270,9 @@ func (ovn *Handler) initIPtablesChains() error {
 }
 
 func (ovn *Handler) ensureForwardChains() error {
-	for _, chain := range []string{ForwardingSubmarinerFWDChain, ForwardingSubmarinerMSSClampChain} {
-		if err := ovn.pFilter.CreateIPHookChainIfNotExists(&packetfilter.ChainIPHook{
-			Name:     chain,
-			Type:     packetfilter.ChainTypeFilter,
-			Hook:     packetfilter.ChainHookForward,
-			Priority: packetfilter.ChainPriorityFirst,
-		}); err != nil {
-			return errors.Wrapf(err, "error installing forwarding IPHook chain %q", chain)
+	for i := range forwardChains {
+		if err := ovn.pFilter.CreateIPHookChainIfNotExists(&forwardChains[i]); err != nil {
+			return errors.Wrapf(err, "error installing forwarding IPHook chain %q", forwardChains[i].Name)
 		}
 	}
 
@@ -19,6 +19,8 @@ limitations under the License.
 package ovn
 
 import (
+	"slices"
+
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/watcher"
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
@@ -113,7 +115,7 @@ func (g *GatewayRouteController) reconcileRemoteSubnets(subMGWRoute *submarinerv
 		return nil
 	}
 
-	if subMGWRoute.RoutePolicySpec.NextHops[0] != g.mgmtIP {
+	if !slices.Contains(subMGWRoute.RoutePolicySpec.NextHops, g.mgmtIP) {
 		// The current node is not the gateway node and hence ignore the event
 		return nil
 	}
@@ -126,7 +128,7 @@ func (g *GatewayRouteController) reconcileRemoteSubnets(subMGWRoute *submarinerv
 		}
 	}
 
-	err := g.connectionHandler.reconcileSubOvnLogicalRouterPolicies(g.remoteSubnets, g.mgmtIP)
+	err := g.connectionHandler.reconcileSubOvnLogicalRouterPolicies(g.remoteSubnets, []string{g.mgmtIP})
 	if err != nil {
 		return err
 	}
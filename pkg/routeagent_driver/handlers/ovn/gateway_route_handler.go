@@ -34,8 +34,9 @@ import (
 
 type GatewayRouteHandler struct {
 	event.HandlerBase
-	smClient  submarinerClientset.Interface
-	nextHopIP string
+	smClient     submarinerClientset.Interface
+	nextHopIP    string
+	activeActive bool
 }
 
 func NewGatewayRouteHandler(smClientSet submarinerClientset.Interface) *GatewayRouteHandler {
@@ -65,12 +66,17 @@ func (h *GatewayRouteHandler) GetNetworkPlugins() []string {
 	return []string{cni.OVNKubernetes}
 }
 
+func (h *GatewayRouteHandler) LocalEndpointCreated(endpoint *submarinerv1.Endpoint) error {
+	h.activeActive = endpoint.Spec.IsActiveActive()
+	return nil
+}
+
 func (h *GatewayRouteHandler) RemoteEndpointCreated(endpoint *submarinerv1.Endpoint) error {
 	if h.State().IsOnGateway() {
 		gwr := h.newGatewayRoute(endpoint)
 
 		result, err := util.CreateOrUpdate(context.TODO(), GatewayResourceInterface(h.smClient, endpoint.Namespace),
-			gwr, util.Replace(gwr))
+			gwr, h.mutateFor(gwr))
 		if err != nil {
 			return errors.Wrapf(err, "error processing the remote endpoint creation for %q", endpoint.Name)
 		}
@@ -83,6 +89,10 @@ func (h *GatewayRouteHandler) RemoteEndpointCreated(endpoint *submarinerv1.Endpo
 
 func (h *GatewayRouteHandler) RemoteEndpointRemoved(endpoint *submarinerv1.Endpoint) error {
 	if h.State().IsOnGateway() {
+		if h.activeActive {
+			return h.removeNextHop(endpoint)
+		}
+
 		if err := h.smClient.SubmarinerV1().GatewayRoutes(endpoint.Namespace).Delete(context.TODO(),
 			endpoint.Spec.ClusterID, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
 			return errors.Wrapf(err, "error deleting gatewayRoute %q", endpoint.Name)
@@ -108,7 +118,7 @@ func (h *GatewayRouteHandler) TransitionToGateway() error {
 		gwr := h.newGatewayRoute(&endpoints[i])
 
 		result, err := util.CreateOrUpdate(context.TODO(), GatewayResourceInterface(h.smClient, endpoints[i].Namespace),
-			gwr, util.Replace(gwr))
+			gwr, h.mutateFor(gwr))
 		if err != nil {
 			return errors.Wrapf(err, "error creating/updating GatewayRoute")
 		}
@@ -119,6 +129,41 @@ func (h *GatewayRouteHandler) TransitionToGateway() error {
 	return nil
 }
 
+func (h *GatewayRouteHandler) TransitionToNonGateway() error {
+	if !h.activeActive {
+		return nil
+	}
+
+	endpoints := h.State().GetRemoteEndpoints()
+	for i := range endpoints {
+		if err := h.removeNextHop(&endpoints[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *GatewayRouteHandler) mutateFor(gwr *submarinerv1.GatewayRoute) util.MutateFn[*submarinerv1.GatewayRoute] {
+	if h.activeActive {
+		return addNextHop(gwr.RoutePolicySpec.RemoteCIDRs, h.nextHopIP, gatewayRouteSpec)
+	}
+
+	return util.Replace(gwr)
+}
+
+func (h *GatewayRouteHandler) removeNextHop(endpoint *submarinerv1.Endpoint) error {
+	err := removeNextHop(context.TODO(), GatewayResourceInterface(h.smClient, endpoint.Namespace), endpoint.Spec.ClusterID,
+		h.nextHopIP, gatewayRouteSpec)
+	if err != nil {
+		return errors.Wrapf(err, "error removing the next hop from GatewayRoute %q", endpoint.Spec.ClusterID)
+	}
+
+	logger.Infof("Next hop %s removed from GatewayRoute %s", h.nextHopIP, endpoint.Spec.ClusterID)
+
+	return nil
+}
+
 func (h *GatewayRouteHandler) newGatewayRoute(endpoint *submarinerv1.Endpoint) *submarinerv1.GatewayRoute {
 	return &submarinerv1.GatewayRoute{
 		ObjectMeta: metav1.ObjectMeta{
@@ -19,6 +19,7 @@ limitations under the License.
 package ovn_test
 
 import (
+	"context"
 	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -28,6 +29,7 @@ import (
 	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
 	"github.com/submariner-io/submariner/pkg/event/testing"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/ovn"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("GatewayRouteHandler", func() {
@@ -74,6 +76,50 @@ var _ = Describe("GatewayRouteHandler", func() {
 		})
 	})
 
+	When("a remote Endpoint is created and deleted on an active-active gateway", func() {
+		const otherNextHop = "100.1.1.2"
+
+		var endpoint *submarinerv1.Endpoint
+
+		JustBeforeEach(func() {
+			endpoint = testing.NewEndpoint("remote-cluster1", "host", "192.0.4.0/24")
+
+			_, err := t.submClient.SubmarinerV1().GatewayRoutes(testing.Namespace).Create(context.TODO(), &submarinerv1.GatewayRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: endpoint.Spec.ClusterID,
+				},
+				RoutePolicySpec: submarinerv1.RoutePolicySpec{
+					RemoteCIDRs: endpoint.Spec.Subnets,
+					NextHops:    []string{otherNextHop},
+				},
+			}, metav1.CreateOptions{})
+			Expect(err).To(Succeed())
+
+			localEndpoint := testing.NewEndpoint(testing.LocalClusterID, t.Hostname)
+			localEndpoint.Spec.BackendConfig = map[string]string{
+				submarinerv1.GatewayHAModeConfig: submarinerv1.HAModeActiveActive,
+			}
+
+			t.CreateEndpoint(localEndpoint)
+		})
+
+		It("should add/remove its next hop to/from the GatewayRoute", func() {
+			t.CreateEndpoint(endpoint)
+
+			Eventually(func() []string {
+				return test.AwaitResource(ovn.GatewayResourceInterface(t.submClient, testing.Namespace),
+					endpoint.Spec.ClusterID).RoutePolicySpec.NextHops
+			}).Should(Equal([]string{otherNextHop, t.mgmntIntfIP}))
+
+			t.DeleteEndpoint(endpoint.Name)
+
+			Eventually(func() []string {
+				return test.AwaitResource(ovn.GatewayResourceInterface(t.submClient, testing.Namespace),
+					endpoint.Spec.ClusterID).RoutePolicySpec.NextHops
+			}).Should(Equal([]string{otherNextHop}))
+		})
+	})
+
 	Context("on transition to gateway", func() {
 		It("should create GatewayRoutes for all remote Endpoints", func() {
 			endpoint := t.CreateEndpoint(testing.NewEndpoint("remote-cluster1", "host", "192.0.4.0/24"))
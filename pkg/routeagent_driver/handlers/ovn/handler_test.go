@@ -46,6 +46,8 @@ const (
 	clusterCIDR      = "171.0.1.0/24"
 	serviceCIDR      = "181.0.1.0/24"
 	OVNK8sMgmntIntGw = "100.1.1.1"
+	clusterCIDRv6    = "fd00:171:1::/64"
+	mgmntIntGwv6     = "fd00:100:1::1"
 )
 
 var _ = Describe("Handler", func() {
@@ -54,9 +56,11 @@ var _ = Describe("Handler", func() {
 	var (
 		ovsdbClient     *fakeovn.OVSDBClient
 		transitSwitchIP ovn.TransitSwitchIP
+		clusterCIDRs    []string
 	)
 
 	BeforeEach(func() {
+		clusterCIDRs = []string{clusterCIDR}
 		ovsdbClient = fakeovn.NewOVSDBClient()
 
 		_, _ = ovsdbClient.Create(&nbdb.LogicalRouter{
@@ -86,7 +90,7 @@ var _ = Describe("Handler", func() {
 
 		t.Start(ovn.NewHandler(&ovn.HandlerConfig{
 			Namespace:   testing.Namespace,
-			ClusterCIDR: []string{clusterCIDR},
+			ClusterCIDR: clusterCIDRs,
 			ServiceCIDR: []string{serviceCIDR},
 			SubmClient:  t.submClient,
 			K8sClient:   t.k8sClient,
@@ -141,6 +145,45 @@ var _ = Describe("Handler", func() {
 			}
 		})
 
+		Context("with IPv4 and IPv6 subnets on a dual-stack cluster", func() {
+			BeforeEach(func() {
+				clusterCIDRs = []string{clusterCIDR, clusterCIDRv6}
+
+				Expect(t.netLink.RouteAdd(&netlink.Route{
+					LinkIndex: OVNK8sMgmntIntIndex,
+					Family:    syscall.AF_INET6,
+					Dst:       toIPNet(clusterCIDRv6),
+					Gw:        net.ParseIP(mgmntIntGwv6),
+				})).To(Succeed())
+			})
+
+			It("should route the host network traffic of both IP families to the OVN data plane", func() {
+				endpoint := t.CreateEndpoint(testing.NewEndpoint("remote-cluster", "host", "192.0.1.0/24", "fd00:192:1::/64"))
+
+				for _, s := range endpoint.Spec.Subnets {
+					t.netLink.AwaitRule(constants.RouteAgentHostNetworkTableID, "", s)
+				}
+
+				t.netLink.AwaitGwRoutes(0, constants.RouteAgentHostNetworkTableID, OVNK8sMgmntIntGw, mgmntIntGwv6)
+			})
+		})
+
+		Context("with IPv4 and IPv6 subnets on an IPv4 cluster", func() {
+			It("should only route the host network traffic to the IPv4 subnets", func() {
+				t.CreateEndpoint(testing.NewEndpoint("remote-cluster", "host", "192.0.1.0/24", "fd00:192:1::/64"))
+
+				t.netLink.AwaitRule(constants.RouteAgentHostNetworkTableID, "", "192.0.1.0/24")
+				t.netLink.AwaitGwRoutes(0, constants.RouteAgentHostNetworkTableID, OVNK8sMgmntIntGw)
+
+				Consistently(func() []netlink.Rule {
+					rules, err := t.netLink.RuleList(netlink.FAMILY_V6)
+					Expect(err).To(Succeed())
+
+					return rules
+				}).Should(BeEmpty())
+			})
+		})
+
 		Context("on the gateway", func() {
 			JustBeforeEach(func() {
 				t.CreateLocalHostEndpoint()
@@ -159,6 +202,9 @@ var _ = Describe("Handler", func() {
 					t.pFilter.AwaitRule(packetfilter.TableTypeNAT, constants.SmPostRoutingChain, ContainSubstring("\"DestCIDR\":%q", s))
 				}
 
+				t.pFilter.AwaitIPHookChain(packetfilter.ChainTypeFilter, ovn.ForwardingSubmarinerMSSClampChain)
+				t.pFilter.EnsureChainsIncludedIn(ovn.PacketFilterMigrationSpec().Includes)
+
 				By("Updating remote Endpoint")
 
 				oldSubnets := endpoint.Spec.Subnets
@@ -346,6 +392,29 @@ var _ = Describe("Handler", func() {
 		})
 	})
 
+	When("a NonGatewayRoute with multiple next hops is created", func() {
+		It("should create OVN router policies with ECMP next hops", func() {
+			client := t.dynClient.Resource(submarinerv1.SchemeGroupVersion.WithResource("nongatewayroutes")).Namespace(testing.Namespace)
+
+			nonGWRoute := &submarinerv1.NonGatewayRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-nongateway-route",
+				},
+				RoutePolicySpec: submarinerv1.RoutePolicySpec{
+					NextHops:    []string{"172.1.1.1", "172.1.1.2"},
+					RemoteCIDRs: []string{"111.0.1.0/24"},
+				},
+			}
+
+			test.CreateResource(client, nonGWRoute)
+
+			ovsdbClient.AwaitModel(&nbdb.LogicalRouterPolicy{
+				Match:    nonGWRoute.RoutePolicySpec.RemoteCIDRs[0],
+				Nexthops: nonGWRoute.RoutePolicySpec.NextHops,
+			})
+		})
+	})
+
 	When("the OVN management interface address changes", func() {
 		JustBeforeEach(func() {
 			t.CreateLocalHostEndpoint()
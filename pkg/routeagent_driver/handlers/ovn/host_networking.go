@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"syscall"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
@@ -39,12 +38,12 @@ func (ovn *Handler) updateHostNetworkDataplane() error {
 	ovn.mutex.Lock()
 	defer ovn.mutex.Unlock()
 
-	currentRuleRemotes, err := ovn.getExistingIPv4HostNetworkRoutes()
+	currentRuleRemotes, err := ovn.getExistingHostNetworkRoutes()
 	if err != nil {
-		return errors.Wrapf(err, "error reading ip rule list for IPv4")
+		return errors.Wrapf(err, "error reading the host network ip rule list")
 	}
 
-	endpointSubnets := ovn.getRemoteSubnets()
+	endpointSubnets := ovn.getRoutableRemoteSubnets()
 
 	toAdd := endpointSubnets.Difference(currentRuleRemotes).UnsortedList()
 
@@ -60,28 +59,25 @@ func (ovn *Handler) updateHostNetworkDataplane() error {
 		return errors.Wrapf(err, "error removing routing rule")
 	}
 
-	nextHop, err := ovn.getNextHopOnK8sMgmtIntf()
+	routes, err := ovn.getRoutesToOVNDataPlane(constants.RouteAgentHostNetworkTableID)
 	if err != nil {
-		return errors.Wrapf(err, "getNextHopOnK8sMgmtIntf returned error")
+		return errors.Wrap(err, "error creating default routes")
 	}
 
-	route := &netlink.Route{
-		Gw:    *nextHop,
-		Table: constants.RouteAgentHostNetworkTableID,
-	}
-
-	err = ovn.netLink.RouteAdd(route)
-	if err != nil && !os.IsExist(err) {
-		return errors.Wrap(err, "error adding submariner default")
+	for _, route := range routes {
+		err = ovn.netLink.RouteAdd(route)
+		if err != nil && !os.IsExist(err) {
+			return errors.Wrap(err, "error adding submariner default")
+		}
 	}
 
 	return nil
 }
 
-func (ovn *Handler) getExistingIPv4HostNetworkRoutes() (set.Set[string], error) {
+func (ovn *Handler) getExistingHostNetworkRoutes() (set.Set[string], error) {
 	currentRuleRemotes := set.New[string]()
 
-	rules, err := ovn.netLink.RuleList(netlink.FAMILY_V4)
+	rules, err := ovn.netLink.RuleList(netlink.FAMILY_ALL)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error listing rules")
 	}
@@ -113,14 +109,35 @@ func (ovn *Handler) programRulesForRemoteSubnets(subnets []string, ruleFunc func
 	return nil
 }
 
-func (ovn *Handler) getNextHopOnK8sMgmtIntf() (*net.IP, error) {
+// getRoutesToOVNDataPlane returns the default routes for the given table via the next hop on the ovn-k8s-mp0 interface,
+// one for each IP family of the local cluster CIDRs.
+func (ovn *Handler) getRoutesToOVNDataPlane(table int) ([]*netlink.Route, error) {
+	routes := []*netlink.Route{}
+
+	for _, family := range ovn.getClusterCIDRFamilies() {
+		nextHop, err := ovn.getNextHopOnK8sMgmtIntf(family)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getNextHopOnK8sMgmtIntf returned error")
+		}
+
+		routes = append(routes, &netlink.Route{
+			Family: family,
+			Gw:     *nextHop,
+			Table:  table,
+		})
+	}
+
+	return routes, nil
+}
+
+func (ovn *Handler) getNextHopOnK8sMgmtIntf(family int) (*net.IP, error) {
 	link, err := ovn.netLink.LinkByName(OVNK8sMgmntIntfName)
 
 	if err != nil && !errors.Is(err, netlink.LinkNotFoundError{}) {
 		return nil, errors.Wrapf(err, "error retrieving link by name %q", OVNK8sMgmntIntfName)
 	}
 
-	currentRouteList, err := ovn.netLink.RouteList(link, syscall.AF_INET)
+	currentRouteList, err := ovn.netLink.RouteList(link, family)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error retrieving routes on the link %s", OVNK8sMgmntIntfName)
 	}
@@ -20,6 +20,7 @@ package ovn
 
 import (
 	"context"
+	"slices"
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/resource"
@@ -38,6 +39,7 @@ type NonGatewayRouteHandler struct {
 	event.NodeHandlerBase
 	smClient        submarinerClientset.Interface
 	transitSwitchIP TransitSwitchIP
+	activeActive    bool
 }
 
 func NewNonGatewayRouteHandler(smClient submarinerClientset.Interface, transitSwitchIP TransitSwitchIP,
@@ -61,6 +63,11 @@ func (h *NonGatewayRouteHandler) GetNetworkPlugins() []string {
 	return []string{cni.OVNKubernetes}
 }
 
+func (h *NonGatewayRouteHandler) LocalEndpointCreated(endpoint *submarinerv1.Endpoint) error {
+	h.activeActive = endpoint.Spec.IsActiveActive()
+	return nil
+}
+
 func (h *NonGatewayRouteHandler) RemoteEndpointCreated(endpoint *submarinerv1.Endpoint) error {
 	if !h.State().IsOnGateway() || h.transitSwitchIP.Get() == "" {
 		return nil
@@ -69,7 +76,7 @@ func (h *NonGatewayRouteHandler) RemoteEndpointCreated(endpoint *submarinerv1.En
 	ngwr := h.newNonGatewayRoute(endpoint)
 
 	result, err := util.CreateOrUpdate(context.TODO(), NonGatewayResourceInterface(h.smClient, endpoint.Namespace),
-		ngwr, util.Replace(ngwr))
+		ngwr, h.mutateFor(ngwr))
 	if err != nil {
 		return errors.Wrapf(err, "error processing the remote endpoint create event for %q", endpoint.Name)
 	}
@@ -84,6 +91,10 @@ func (h *NonGatewayRouteHandler) RemoteEndpointRemoved(endpoint *submarinerv1.En
 		return nil
 	}
 
+	if h.activeActive {
+		return h.removeNextHop(endpoint, h.transitSwitchIP.Get())
+	}
+
 	if err := h.smClient.SubmarinerV1().NonGatewayRoutes(endpoint.Namespace).Delete(context.TODO(),
 		endpoint.Spec.ClusterID, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
 		return errors.Wrapf(err, "error deleting nonGatewayRoute %q", endpoint.Name)
@@ -112,7 +123,7 @@ func (h *NonGatewayRouteHandler) TransitionToGateway() error {
 		ngwr := h.newNonGatewayRoute(&endpoints[i])
 
 		result, err := util.CreateOrUpdate(context.TODO(), NonGatewayResourceInterface(h.smClient, endpoints[i].Namespace),
-			ngwr, util.Replace(ngwr))
+			ngwr, h.mutateFor(ngwr))
 		if err != nil {
 			return errors.Wrapf(err, "error creating/updating NonGatewayRoute")
 		}
@@ -123,7 +134,24 @@ func (h *NonGatewayRouteHandler) TransitionToGateway() error {
 	return nil
 }
 
+func (h *NonGatewayRouteHandler) TransitionToNonGateway() error {
+	if !h.activeActive || h.transitSwitchIP.Get() == "" {
+		return nil
+	}
+
+	endpoints := h.State().GetRemoteEndpoints()
+	for i := range endpoints {
+		if err := h.removeNextHop(&endpoints[i], h.transitSwitchIP.Get()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (h *NonGatewayRouteHandler) NodeUpdated(node *corev1.Node) error {
+	prevTransitSwitchIP := h.transitSwitchIP.Get()
+
 	updated, err := h.transitSwitchIP.UpdateFrom(node)
 	if err != nil {
 		logger.Errorf(err, "Error updating transit switch IP from node: %s", resource.ToJSON(node))
@@ -144,6 +172,14 @@ func (h *NonGatewayRouteHandler) NodeUpdated(node *corev1.Node) error {
 	for i := range endpoints {
 		err = util.Update(context.TODO(), NonGatewayResourceInterface(h.smClient, endpoints[i].Namespace),
 			h.newNonGatewayRoute(&endpoints[i]), func(existing *submarinerv1.NonGatewayRoute) (*submarinerv1.NonGatewayRoute, error) {
+				if h.activeActive {
+					existing.RoutePolicySpec.NextHops = slices.DeleteFunc(existing.RoutePolicySpec.NextHops, func(ip string) bool {
+						return ip == prevTransitSwitchIP
+					})
+
+					return addNextHop(existing.RoutePolicySpec.RemoteCIDRs, h.transitSwitchIP.Get(), nonGatewayRouteSpec)(existing)
+				}
+
 				existing.RoutePolicySpec.NextHops = []string{h.transitSwitchIP.Get()}
 				return existing, nil
 			})
@@ -155,6 +191,26 @@ func (h *NonGatewayRouteHandler) NodeUpdated(node *corev1.Node) error {
 	return nil
 }
 
+func (h *NonGatewayRouteHandler) mutateFor(ngwr *submarinerv1.NonGatewayRoute) util.MutateFn[*submarinerv1.NonGatewayRoute] {
+	if h.activeActive {
+		return addNextHop(ngwr.RoutePolicySpec.RemoteCIDRs, h.transitSwitchIP.Get(), nonGatewayRouteSpec)
+	}
+
+	return util.Replace(ngwr)
+}
+
+func (h *NonGatewayRouteHandler) removeNextHop(endpoint *submarinerv1.Endpoint, nextHop string) error {
+	err := removeNextHop(context.TODO(), NonGatewayResourceInterface(h.smClient, endpoint.Namespace), endpoint.Spec.ClusterID,
+		nextHop, nonGatewayRouteSpec)
+	if err != nil {
+		return errors.Wrapf(err, "error removing the next hop from NonGatewayRoute %q", endpoint.Spec.ClusterID)
+	}
+
+	logger.Infof("Next hop %s removed from NonGatewayRoute %s", nextHop, endpoint.Spec.ClusterID)
+
+	return nil
+}
+
 func (h *NonGatewayRouteHandler) newNonGatewayRoute(endpoint *submarinerv1.Endpoint) *submarinerv1.NonGatewayRoute {
 	return &submarinerv1.NonGatewayRoute{
 		ObjectMeta: metav1.ObjectMeta{
@@ -81,11 +81,11 @@ func buildLRSRsFromSubnets(subnetsToAdd []string, nextHop string) []*nbdb.Logica
 	return toAdd
 }
 
-func (c *ConnectionHandler) reconcileSubOvnLogicalRouterPolicies(remoteSubnets sets.Set[string], nextHop string) error {
+func (c *ConnectionHandler) reconcileSubOvnLogicalRouterPolicies(remoteSubnets sets.Set[string], nextHops []string) error {
 	lrpStalePredicate := func(item *nbdb.LogicalRouterPolicy) bool {
 		subnet := strings.Split(item.Match, " ")[2]
 
-		return item.Priority == ovnRoutePoliciesPrio && (!remoteSubnets.Has(subnet) || !reflect.DeepEqual(item.Nexthop, &nextHop))
+		return item.Priority == ovnRoutePoliciesPrio && (!remoteSubnets.Has(subnet) || !lrpHasNextHops(item, nextHops))
 	}
 
 	// Cleanup any existing lrps not representing the correct set of remote subnets
@@ -94,7 +94,7 @@ func (c *ConnectionHandler) reconcileSubOvnLogicalRouterPolicies(remoteSubnets s
 		return errors.Wrapf(err, "failed to delete stale submariner logical route policies")
 	}
 
-	expectedLRPs := buildLRPsFromSubnets(remoteSubnets.UnsortedList(), nextHop)
+	expectedLRPs := buildLRPsFromSubnets(remoteSubnets.UnsortedList(), nextHops)
 
 	for _, lrp := range expectedLRPs {
 		lrpSubPredicate := func(item *nbdb.LogicalRouterPolicy) bool {
@@ -116,20 +116,36 @@ func (c *ConnectionHandler) reconcileSubOvnLogicalRouterPolicies(remoteSubnets s
 // getNorthSubnetsToAddAndRemove receives the existing state for the north (other clusters) routes in the OVN
 // database, and based on the known remote endpoints it will return the elements that need
 // to be added and removed.
-func buildLRPsFromSubnets(subnetsToAdd []string, nextHop string) []*nbdb.LogicalRouterPolicy {
+func buildLRPsFromSubnets(subnetsToAdd, nextHops []string) []*nbdb.LogicalRouterPolicy {
 	toAdd := []*nbdb.LogicalRouterPolicy{}
 
 	for _, subnet := range subnetsToAdd {
-		toAdd = append(toAdd, &nbdb.LogicalRouterPolicy{
+		lrp := &nbdb.LogicalRouterPolicy{
 			Priority: ovnRoutePoliciesPrio,
 			Action:   "reroute",
 			Match:    "ip4.dst == " + subnet,
-			Nexthop:  ptr.To(nextHop),
 			ExternalIDs: map[string]string{
 				"submariner": versions.Submariner(),
 			},
-		})
+		}
+
+		// With active-active gateways, the traffic is rerouted to all the gateways using ECMP.
+		if len(nextHops) == 1 {
+			lrp.Nexthop = ptr.To(nextHops[0])
+		} else {
+			lrp.Nexthops = nextHops
+		}
+
+		toAdd = append(toAdd, lrp)
 	}
 
 	return toAdd
 }
+
+func lrpHasNextHops(lrp *nbdb.LogicalRouterPolicy, nextHops []string) bool {
+	if len(nextHops) == 1 {
+		return len(lrp.Nexthops) == 0 && reflect.DeepEqual(lrp.Nexthop, &nextHops[0])
+	}
+
+	return lrp.Nexthop == nil && sets.New(lrp.Nexthops...).Equal(sets.New(nextHops...))
+}
@@ -0,0 +1,99 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+
+Copyright Contributors to the Submariner project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ovn
+
+import (
+	"context"
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/submariner-io/admiral/pkg/resource"
+	"github.com/submariner-io/admiral/pkg/util"
+	submarinerv1 "github.com/submariner-io/submariner/pkg/apis/submariner.io/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+)
+
+// With active-active gateways, each gateway adds its own next hop to the GatewayRoute and NonGatewayRoute for a remote
+// cluster so traffic is spread across the gateways using ECMP.
+
+func gatewayRouteSpec(r *submarinerv1.GatewayRoute) *submarinerv1.RoutePolicySpec {
+	return &r.RoutePolicySpec
+}
+
+func nonGatewayRouteSpec(r *submarinerv1.NonGatewayRoute) *submarinerv1.RoutePolicySpec {
+	return &r.RoutePolicySpec
+}
+
+func addNextHop[T runtime.Object](remoteCIDRs []string, nextHop string, specOf func(T) *submarinerv1.RoutePolicySpec,
+) util.MutateFn[T] {
+	return func(existing T) (T, error) {
+		spec := specOf(existing)
+		spec.RemoteCIDRs = remoteCIDRs
+
+		if !slices.Contains(spec.NextHops, nextHop) {
+			spec.NextHops = append(spec.NextHops, nextHop)
+		}
+
+		return existing, nil
+	}
+}
+
+// removeNextHop removes the given next hop from the named route policy resource, deleting the resource if no next hops
+// remain.
+func removeNextHop[T runtime.Object](ctx context.Context, client resource.Interface[T], name, nextHop string,
+	specOf func(T) *submarinerv1.RoutePolicySpec,
+) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error { //nolint:wrapcheck // No need to wrap
+		existing, err := client.Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "error retrieving %q", name)
+		}
+
+		spec := specOf(existing)
+
+		index := slices.Index(spec.NextHops, nextHop)
+		if index < 0 {
+			return nil
+		}
+
+		if len(spec.NextHops) == 1 {
+			rv := resource.MustToMeta(existing).GetResourceVersion()
+
+			err = client.Delete(ctx, name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{ResourceVersion: &rv}})
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+
+			return err //nolint:wrapcheck // Let the retry check for a conflict
+		}
+
+		spec.NextHops = slices.Delete(spec.NextHops, index, index+1)
+
+		_, err = client.Update(ctx, existing, metav1.UpdateOptions{})
+
+		return err //nolint:wrapcheck // Let the retry check for a conflict
+	})
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/submariner-io/admiral/pkg/log"
+	"github.com/submariner-io/submariner/pkg/cidr"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/constants"
 	"github.com/vishvananda/netlink"
 	"k8s.io/utils/set"
@@ -39,6 +40,10 @@ func (ovn *Handler) handleSubnets(remoteSubnets []string, ruleFunc func(rule *ne
 
 	for _, subnetToHandle := range remoteSubnets {
 		for _, localSubnet := range localCIDRs.UnsortedList() {
+			if !cidr.SameFamily(localSubnet, subnetToHandle) {
+				continue
+			}
+
 			rule, err := ovn.getRuleSpec(localSubnet, subnetToHandle, constants.RouteAgentInterClusterNetworkTableID)
 			if err != nil {
 				return errors.Wrapf(err, "error creating rule %#v", rule)
@@ -83,10 +88,10 @@ func (ovn *Handler) getRuleSpec(dest, src string, tableID int) (*netlink.Rule, e
 	return rule, nil
 }
 
-func (ovn *Handler) getExistingIPv4RuleSubnets() (set.Set[string], error) {
+func (ovn *Handler) getExistingRuleSubnets() (set.Set[string], error) {
 	currentRuleRemotes := set.New[string]()
 
-	rules, err := ovn.netLink.RuleList(netlink.FAMILY_V4)
+	rules, err := ovn.netLink.RuleList(netlink.FAMILY_ALL)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error listing rules")
 	}
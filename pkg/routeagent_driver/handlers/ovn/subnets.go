@@ -18,7 +18,13 @@ limitations under the License.
 
 package ovn
 
-import "k8s.io/utils/set"
+import (
+	"slices"
+
+	"github.com/vishvananda/netlink"
+	k8snet "k8s.io/utils/net"
+	"k8s.io/utils/set"
+)
 
 func (ovn *Handler) getRemoteSubnets() set.Set[string] {
 	endpointSubnets := set.New[string]()
@@ -32,3 +38,37 @@ func (ovn *Handler) getRemoteSubnets() set.Set[string] {
 
 	return endpointSubnets
 }
+
+// getRoutableRemoteSubnets returns the remote subnets of the IP families of the local cluster CIDRs, the only ones
+// which can be routed to the OVN data plane.
+func (ovn *Handler) getRoutableRemoteSubnets() set.Set[string] {
+	families := ovn.getClusterCIDRFamilies()
+
+	return set.New(slices.DeleteFunc(ovn.getRemoteSubnets().UnsortedList(), func(subnet string) bool {
+		return !slices.Contains(families, cidrFamily(subnet))
+	})...)
+}
+
+// getClusterCIDRFamilies returns the IP families of the local cluster CIDRs, IPv4 first.
+func (ovn *Handler) getClusterCIDRFamilies() []int {
+	families := []int{}
+
+	for _, family := range []int{netlink.FAMILY_V4, netlink.FAMILY_V6} {
+		for _, subnet := range ovn.ClusterCIDR {
+			if cidrFamily(subnet) == family {
+				families = append(families, family)
+				break
+			}
+		}
+	}
+
+	return families
+}
+
+func cidrFamily(subnet string) int {
+	if k8snet.IsIPv6CIDRString(subnet) {
+		return netlink.FAMILY_V6
+	}
+
+	return netlink.FAMILY_V4
+}
@@ -86,7 +86,7 @@ func (ovn *Handler) Uninstall() error {
 }
 
 func (ovn *Handler) cleanupRoutes() error {
-	rules, err := ovn.netLink.RuleList(netlink.FAMILY_V4)
+	rules, err := ovn.netLink.RuleList(netlink.FAMILY_ALL)
 	if err != nil {
 		return errors.Wrapf(err, "error listing rules")
 	}
@@ -40,8 +40,7 @@ import (
 	"github.com/submariner-io/submariner/pkg/event"
 	"github.com/submariner-io/submariner/pkg/event/controller"
 	"github.com/submariner-io/submariner/pkg/node"
-	packetfilter "github.com/submariner-io/submariner/pkg/packetfilter"
-	iptables "github.com/submariner-io/submariner/pkg/packetfilter/iptables"
+	"github.com/submariner-io/submariner/pkg/packetfilter/configure"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/cabledriver"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/environment"
 	"github.com/submariner-io/submariner/pkg/routeagent_driver/handlers/calico"
@@ -118,8 +117,15 @@ func main() {
 		return
 	}
 
-	// Set packetfilter driver to iptables. Once nftables is available, we'll check which driver is supported.
-	packetfilter.SetNewDriverFn(iptables.New)
+	pfDriver, err := configure.Configure(env.PacketFilterDriver)
+	logger.FatalOnError(err, "Error configuring the packet filter driver")
+
+	if pfDriver == configure.DriverNFTables && !env.Uninstall {
+		err = configure.MigrateFromIPTables(packetFilterMigrationSpec())
+		if err != nil {
+			logger.Errorf(err, "Error migrating the packet filter rules from iptables")
+		}
+	}
 
 	np := os.Getenv("SUBMARINER_NETWORKPLUGIN")
 
@@ -144,7 +150,7 @@ func main() {
 	}
 
 	registry, err := event.NewRegistry("routeagent_driver", np,
-		kubeproxy.NewSyncHandler(env.ClusterCidr, env.ServiceCidr),
+		kubeproxy.NewSyncHandler(env.ClusterCidr, env.ServiceCidr, config),
 		ovn.NewHandler(&ovn.HandlerConfig{
 			Namespace:       env.Namespace,
 			ClusterCIDR:     env.ClusterCidr,
@@ -159,7 +165,7 @@ func main() {
 		ovn.NewNonGatewayRouteHandler(smClientset, transitSwitchIP),
 		cabledriver.NewXRFMCleanupHandler(),
 		cabledriver.NewVXLANCleanup(),
-		mtu.NewMTUHandler(env.ClusterCidr, len(env.GlobalCidr) != 0, getTCPMssValue(localNode)),
+		mtu.NewMTUHandler(env.ClusterCidr, len(env.GlobalCidr) != 0, getTCPMssValue(localNode), config, env.Namespace),
 		calico.NewCalicoIPPoolHandler(cfg, env.Namespace, k8sClientSet),
 		healthchecker.New(healthcheckerConfig,
 			smClientset.SubmarinerV1().RouteAgents(submSpec.Namespace), versions.Submariner(), localNode.Name))
@@ -213,6 +219,12 @@ func getTCPMssValue(localNode *corev1.Node) int {
 	return tcpMssValue
 }
 
+// packetFilterMigrationSpec returns the chains programmed by the route agent handlers.
+func packetFilterMigrationSpec() *configure.MigrationSpec {
+	return configure.MergeMigrationSpecs(kubeproxy.PacketFilterMigrationSpec(), ovn.PacketFilterMigrationSpec(),
+		mtu.PacketFilterMigrationSpec())
+}
+
 func uninstall(registry *event.Registry) {
 	if err := registry.StopHandlers(); err != nil {
 		logger.Warningf("Error stopping handlers: %v", err)
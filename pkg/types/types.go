@@ -49,4 +49,37 @@ type SubmarinerSpecification struct {
 	HealthCheckInterval           int
 	HealthCheckMaxPacketLossCount int
 	MetricsPort                   int `default:"32780"`
+	// ClusterCableDrivers maps remote cluster IDs to the cable driver used to connect to them, eg "cluster2:vxlan".
+	ClusterCableDrivers map[string]string `split_words:"true"`
+	// ActiveActiveGateways runs every gateway node as an active gateway, instead of electing a single one.
+	ActiveActiveGateways bool `split_words:"true"`
+	// PMTUDiscoveryEnabled enables probing the path MTU to each remote endpoint, which is published on the connections.
+	PMTUDiscoveryEnabled  bool `split_words:"true"`
+	PMTUDiscoveryPort     int  `split_words:"true"`
+	PMTUDiscoveryInterval int  `split_words:"true"` // In seconds
+	// HealthCheckProbe is the probe type ("icmp", "udp" or "tcp") used to check the health of remote endpoints.
+	HealthCheckProbe string `split_words:"true"`
+	// ClusterHealthCheckProbes maps remote cluster IDs to the probe type used for them, eg "cluster2:udp".
+	ClusterHealthCheckProbes map[string]string `split_words:"true"`
+	HealthCheckProbePort     int               `split_words:"true"`
+	// HealthCheckRemediationThreshold is the number of failed health check intervals after which a cable is reconnected.
+	// Remediation is disabled if zero.
+	HealthCheckRemediationThreshold    int  `split_words:"true"`
+	HealthCheckRemediationNATDiscovery bool `split_words:"true"`
+	HealthCheckRemediationMaxBackoff   int  `split_words:"true"` // In seconds
+	// BenchmarkEnabled runs a server receiving the traffic of GatewayBenchmarks run by remote gateways and runs the local
+	// GatewayBenchmarks.
+	BenchmarkEnabled bool `split_words:"true"`
+	BenchmarkPort    int  `split_words:"true"`
+	// PeeringAllowedClusters and PeeringDeniedClusters restrict the remote clusters this cluster connects to, by cluster ID.
+	PeeringAllowedClusters []string `split_words:"true"`
+	PeeringDeniedClusters  []string `split_words:"true"`
+	// PeeringClusterSelector is a label selector that the remote Cluster resources must match to be connected to.
+	PeeringClusterSelector string `split_words:"true"`
+	// TransitRoutingEnabled makes the gateway advertise the subnets of the clusters it's connected to and forward the
+	// traffic of remote clusters that aren't directly connected to them.
+	TransitRoutingEnabled bool `split_words:"true"`
+	// AdditionalBrokers are the names of other brokers to join, besides the default broker. The settings of each are read
+	// from the BROKER_K8S_<NAME>_ env vars, eg BROKER_K8S_<NAME>_APISERVER.
+	AdditionalBrokers []string `split_words:"true"`
 }
@@ -145,6 +145,26 @@ func GetVtepIPAddressFrom(ipAddr string, networkPrefix int) (net.IP, error) {
 	return net.ParseIP(strings.Join(ipSlice, ".")), nil
 }
 
+// GetVtepIPv6AddressFrom derives an IPv6 VTEP address by embedding the given IPv4 address in the last 32 bits of the
+// given /96 network prefix.
+func GetVtepIPv6AddressFrom(ipAddr string, networkPrefix net.IP) (net.IP, error) {
+	ipv4 := net.ParseIP(ipAddr).To4()
+	if ipv4 == nil {
+		return nil, errors.Errorf("invalid IPv4 ipAddr %q", ipAddr)
+	}
+
+	prefix := networkPrefix.To16()
+	if prefix == nil {
+		return nil, errors.Errorf("invalid IPv6 network prefix %q", networkPrefix)
+	}
+
+	vtepIP := make(net.IP, net.IPv6len)
+	copy(vtepIP, prefix[:net.IPv6len-net.IPv4len])
+	copy(vtepIP[net.IPv6len-net.IPv4len:], ipv4)
+
+	return vtepIP, nil
+}
+
 func (i *Interface) ConfigureIPAddress(ipAddress net.IP, mask net.IPMask) error {
 	ipConfig := &netlink.Addr{IPNet: &net.IPNet{
 		IP:   ipAddress,
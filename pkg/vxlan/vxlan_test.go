@@ -108,6 +108,19 @@ var _ = Describe("GetVtepIPAddressFrom", func() {
 	})
 })
 
+var _ = Describe("GetVtepIPv6AddressFrom", func() {
+	It("should embed the IPv4 address in the prefix", func() {
+		vtepIP, err := vxlan.GetVtepIPv6AddressFrom("10.17.2.3", net.ParseIP("fd00:5ab::"))
+		Expect(err).To(Succeed())
+		Expect(vtepIP).To(Equal(net.ParseIP("fd00:5ab::a11:203")))
+	})
+
+	Specify("should return an error if the input IP is not IPv4", func() {
+		_, err := vxlan.GetVtepIPv6AddressFrom("fd00::1", net.ParseIP("fd00:5ab::"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("Interface", func() {
 	t := newTestDriver()
 